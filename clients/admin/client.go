@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const requestTimeout = time.Minute * 2
+
+// Client is the client of the supervisor admin API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a new admin API client for the address.
+func NewClient(address, token string) *Client {
+	baseURL := address
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = fmt.Sprintf("http://%s", baseURL)
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// ListBots lists the bots of the node.
+func (c *Client) ListBots(ctx context.Context) ([]*BotInfo, error) {
+	var bots []*BotInfo
+	if err := c.do(ctx, http.MethodGet, "/bots", nil, &bots); err != nil {
+		return nil, err
+	}
+	return bots, nil
+}
+
+// RestartBot restarts a bot by the bot ID or the container name.
+func (c *Client) RestartBot(ctx context.Context, botRef string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/bots/%s/restart", url.PathEscape(botRef)), nil, nil)
+}
+
+// PauseBot pauses a bot by the bot ID or the container name.
+func (c *Client) PauseBot(ctx context.Context, botRef string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/bots/%s/pause", url.PathEscape(botRef)), nil, nil)
+}
+
+// ResumeBot resumes a paused bot by the bot ID or the container name.
+func (c *Client) ResumeBot(ctx context.Context, botRef string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/bots/%s/resume", url.PathEscape(botRef)), nil, nil)
+}
+
+// BotLogs returns the latest logs of the bot containers.
+func (c *Client) BotLogs(ctx context.Context, botRef string, tail int) ([]*BotLogs, error) {
+	var logs []*BotLogs
+	path := fmt.Sprintf("/bots/%s/logs?tail=%d", url.PathEscape(botRef), tail)
+	if err := c.do(ctx, http.MethodGet, path, nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// AddBotImage adds a local mode bot image.
+func (c *Client) AddBotImage(ctx context.Context, image string) error {
+	return c.do(ctx, http.MethodPost, "/bot-images", &BotImageRequest{Image: image}, nil)
+}
+
+// RemoveBotImage removes a local mode bot image.
+func (c *Client) RemoveBotImage(ctx context.Context, image string) error {
+	return c.do(ctx, http.MethodDelete, "/bot-images", &BotImageRequest{Image: image}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, target interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the admin api: %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp ErrorResponse
+		if err := json.Unmarshal(b, &errResp); err == nil && errResp.Error != "" {
+			return fmt.Errorf("%d error: %s", resp.StatusCode, errResp.Error)
+		}
+		return fmt.Errorf("%d error: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(b, target)
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"time"
+
+	"zktoro/config"
+)
+
+// BotInfo describes a bot which is assigned to the node.
+type BotInfo struct {
+	Config        config.AgentConfig `json:"config"`
+	ContainerName string             `json:"containerName"`
+	Container     *ContainerInfo     `json:"container,omitempty"`
+
+	State            string     `json:"state"`
+	StateReason      string     `json:"stateReason,omitempty"`
+	StateUpdatedAt   *time.Time `json:"stateUpdatedAt,omitempty"`
+	RecentFailures   int        `json:"recentFailures"`
+	BackoffUntil     *time.Time `json:"backoffUntil,omitempty"`
+	QuarantinedUntil *time.Time `json:"quarantinedUntil,omitempty"`
+
+	LastActivity  *time.Time    `json:"lastActivity,omitempty"`
+	RecentMetrics []*MetricInfo `json:"recentMetrics,omitempty"`
+}
+
+// ContainerInfo describes the container of a bot.
+type ContainerInfo struct {
+	ID      string    `json:"id"`
+	Image   string    `json:"image"`
+	State   string    `json:"state"`
+	Status  string    `json:"status"`
+	Created time.Time `json:"created"`
+}
+
+// MetricInfo is a metric which the node recently collected for a bot.
+type MetricInfo struct {
+	Name      string  `json:"name"`
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+	Details   string  `json:"details,omitempty"`
+}
+
+// BotLogs contains the latest logs of a bot container.
+type BotLogs struct {
+	ContainerName string `json:"containerName"`
+	Logs          string `json:"logs"`
+}
+
+// BotImageRequest adds or removes a local mode bot image.
+type BotImageRequest struct {
+	Image string `json:"image"`
+}
+
+// ErrorResponse is the body of the failed requests.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -8,7 +8,6 @@ import (
 	"zktoro/zktoro-core-go/protocol"
 	"zktoro/zktoro-core-go/security"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -26,6 +25,7 @@ type AgentRoundTrip struct {
 type AlertSender interface {
 	SignAlertAndNotify(rt *AgentRoundTrip, alert *protocol.Alert, chainID, blockNumber string, ts *domain.TrackingTimestamps) error
 	NotifyWithoutAlert(rt *AgentRoundTrip, ts *domain.TrackingTimestamps) error
+	NotifyRetractions(retractions []*protocol.BlockRetraction) error
 }
 
 // PublishClient implements the interface for a notify
@@ -40,8 +40,8 @@ type alertSender struct {
 }
 
 type AlertSenderConfig struct {
-	Key *keystore.Key
-	DS  store.DeduplicationStore
+	Signer security.Signer
+	DS     store.DeduplicationStore
 }
 
 func (a *alertSender) SignAlertAndNotify(rt *AgentRoundTrip, alert *protocol.Alert, chainID, blockNumber string, ts *domain.TrackingTimestamps) error {
@@ -60,9 +60,9 @@ func (a *alertSender) SignAlertAndNotify(rt *AgentRoundTrip, alert *protocol.Ale
 		}
 	}
 	alert.Scanner = &protocol.ScannerInfo{
-		Address: a.cfg.Key.Address.Hex(),
+		Address: a.cfg.Signer.Address().Hex(),
 	}
-	signedAlert, err := security.SignAlert(a.cfg.Key, alert)
+	signedAlert, err := security.SignAlertWithSigner(a.cfg.Signer, alert)
 	if err != nil {
 		logger.Errorf("could not sign alert (id=%s), skipping", alert.Id)
 		return err
@@ -101,6 +101,16 @@ func (a *alertSender) NotifyWithoutAlert(rt *AgentRoundTrip, ts *domain.Tracking
 	return err
 }
 
+// NotifyRetractions sends the retractions of the orphaned blocks to the publisher.
+func (a *alertSender) NotifyRetractions(retractions []*protocol.BlockRetraction) error {
+	_, err := a.pClient.Notify(
+		a.ctx, &protocol.NotifyRequest{
+			Retractions: retractions,
+		},
+	)
+	return err
+}
+
 func NewAlertSender(ctx context.Context, publisher PublishClient, cfg AlertSenderConfig) (AlertSender, error) {
 	return &alertSender{
 		ctx:     ctx,
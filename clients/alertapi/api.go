@@ -12,6 +12,26 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// APIError is returned when the alert api responds with an unsuccessful status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d error: %s", e.StatusCode, e.Body)
+}
+
+// Permanent tells if the api rejected the content of the request so that sending it again cannot
+// succeed. The auth errors are not permanent since the scanner token or registration can be fixed.
+func (e *APIError) Permanent() bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
 type client struct {
 	apiUrl string
 }
@@ -45,7 +65,7 @@ func (c *client) post(path string, body interface{}, headers map[string]string,
 			"response": string(b),
 			"status":   resp.StatusCode,
 		}).Error("alert api error")
-		return fmt.Errorf("%d error: %s", resp.StatusCode, string(b))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(b)}
 	}
 	return json.Unmarshal(b, target)
 }
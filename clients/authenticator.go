@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"sync"
 
-	"zktoro/clients/docker"
 	"zktoro/clients/messaging"
 	"zktoro/config"
 )
@@ -13,7 +12,7 @@ import (
 // IPAuthenticator makes sure ip is an assigned bot or a managed container
 type ipAuthenticator struct {
 	ctx          context.Context
-	dockerClient DockerClient
+	dockerClient ContainerRuntime
 	msgClient    MessageClient
 
 	agentConfigs  []config.AgentConfig
@@ -88,8 +87,8 @@ func (p *ipAuthenticator) handleAgentStatusRunning(payload messaging.AgentPayloa
 	return nil
 }
 
-func NewBotAuthenticator(ctx context.Context) (IPAuthenticator, error) {
-	globalClient, err := docker.NewDockerClient("")
+func NewBotAuthenticator(ctx context.Context, runtimeCfg config.ContainerRuntimeConfig) (IPAuthenticator, error) {
+	globalClient, err := NewContainerRuntime(runtimeCfg, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the global docker client: %v", err)
 	}
@@ -0,0 +1,755 @@
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zktoro/clients/cooldown"
+	"zktoro/clients/docker"
+	"zktoro/config"
+	"zktoro/zktoro-core-go/utils/workers"
+
+	"github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNerdctlPath = "nerdctl"
+	defaultRegistry    = "docker.io"
+)
+
+// Client errors
+var (
+	ErrNetworkAttachUnsupported = errors.New("nerdctl cannot attach running containers to networks")
+)
+
+// command is a nerdctl invocation.
+type command struct {
+	Args           []string
+	Stdin          []byte
+	CombinedOutput bool
+}
+
+type runFunc func(ctx context.Context, cmd *command) ([]byte, error)
+
+// nerdctlClient manages the containers in a containerd namespace by using the nerdctl CLI which
+// outputs the containers in the Docker-compatible format. The networks are referred to by name.
+type nerdctlClient struct {
+	run                   runFunc
+	workers               *workers.Group
+	username              string
+	password              string
+	labels                map[string]string
+	loggedIn              map[string]bool
+	loginMu               sync.Mutex
+	imageDownloadCooldown cooldown.Cooldown
+}
+
+// NewNerdctlClient creates a new containerd client which uses nerdctl.
+func NewNerdctlClient(name string, runtimeCfg config.ContainerRuntimeConfig, username, password string) *nerdctlClient {
+	return newNerdctlClient(newRunFunc(runtimeCfg), name, username, password)
+}
+
+func newNerdctlClient(run runFunc, name, username, password string) *nerdctlClient {
+	return &nerdctlClient{
+		run:      run,
+		workers:  workers.New(1),
+		username: username,
+		password: password,
+		labels:   initLabels(name),
+		loggedIn: make(map[string]bool),
+	}
+}
+
+func newRunFunc(runtimeCfg config.ContainerRuntimeConfig) runFunc {
+	nerdctlPath := runtimeCfg.NerdctlPath
+	if len(nerdctlPath) == 0 {
+		nerdctlPath = defaultNerdctlPath
+	}
+	globalArgs := []string{"--address", runtimeCfg.ClientSocketPath()}
+	if len(runtimeCfg.Namespace) > 0 {
+		globalArgs = append(globalArgs, "--namespace", runtimeCfg.Namespace)
+	}
+	return func(ctx context.Context, cmd *command) ([]byte, error) {
+		execCmd := exec.CommandContext(ctx, nerdctlPath, append(globalArgs, cmd.Args...)...)
+		var stdout, stderr bytes.Buffer
+		execCmd.Stdout = &stdout
+		execCmd.Stderr = &stderr
+		if cmd.CombinedOutput {
+			execCmd.Stderr = &stdout
+		}
+		if cmd.Stdin != nil {
+			execCmd.Stdin = bytes.NewReader(cmd.Stdin)
+		}
+		if err := execCmd.Run(); err != nil {
+			return nil, fmt.Errorf("nerdctl %s: %v: %s", cmd.Args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	}
+}
+
+func initLabels(name string) map[string]string {
+	labels := map[string]string{docker.Labelzktoro: "true"}
+	if len(name) > 0 {
+		labels[docker.LabelzktoroSupervisor] = name
+	}
+	return labels
+}
+
+func (c *nerdctlClient) exec(ctx context.Context, args ...string) ([]byte, error) {
+	return c.run(ctx, &command{Args: args})
+}
+
+func isNotFoundErr(err error) bool {
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "no such") || strings.Contains(errStr, "not found")
+}
+
+func isNotRunningErr(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "not running")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keyValueArgs repeats the flag for each of the key-value pairs in order.
+func keyValueArgs(flag, format string, m map[string]string) (args []string) {
+	for _, k := range sortedKeys(m) {
+		args = append(args, flag, fmt.Sprintf(format, k, m[k]))
+	}
+	return
+}
+
+// registryHost returns the registry of the image reference like the Docker CLI does.
+func registryHost(ref string) string {
+	i := strings.Index(ref, "/")
+	if i < 0 {
+		return defaultRegistry
+	}
+	host := ref[:i]
+	if strings.ContainsAny(host, ".:") || host == "localhost" {
+		return host
+	}
+	return defaultRegistry
+}
+
+func (c *nerdctlClient) ensureLogin(ctx context.Context, ref string) error {
+	if len(c.username) == 0 && len(c.password) == 0 {
+		return nil
+	}
+	host := registryHost(ref)
+
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	if c.loggedIn[host] {
+		return nil
+	}
+	_, err := c.run(ctx, &command{
+		Args:  []string{"login", "--username", c.username, "--password-stdin", host},
+		Stdin: []byte(c.password),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to login to %s: %v", host, err)
+	}
+	c.loggedIn[host] = true
+	return nil
+}
+
+// PullImage pulls an image using the given ref.
+func (c *nerdctlClient) PullImage(ctx context.Context, refStr string) error {
+	if c.imageDownloadCooldown != nil && c.imageDownloadCooldown.ShouldCoolDown(refStr) {
+		return fmt.Errorf("too many pull attempts - cooling down: %s", refStr)
+	}
+	if err := c.ensureLogin(ctx, refStr); err != nil {
+		return err
+	}
+	_, err := c.exec(ctx, "pull", "--quiet", refStr)
+	return err
+}
+
+// RemoveImage removes an image if no container uses it.
+func (c *nerdctlClient) RemoveImage(ctx context.Context, refStr string) error {
+	containers, err := c.listContainers(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get the container list: %v", err)
+	}
+	for _, container := range containers {
+		if container.Image == refStr {
+			return nil
+		}
+	}
+	_, err = c.exec(ctx, "rmi", refStr)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// HasLocalImage checks if we have an image locally.
+func (c *nerdctlClient) HasLocalImage(ctx context.Context, ref string) (bool, error) {
+	_, err := c.exec(ctx, "image", "inspect", ref)
+	if err != nil && isNotFoundErr(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnsureLocalImage ensures that we have the image locally.
+func (c *nerdctlClient) EnsureLocalImage(ctx context.Context, name, ref string) error {
+	logger := log.WithFields(log.Fields{
+		"image": ref,
+		"name":  name,
+	})
+	imageExists, err := c.HasLocalImage(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("error checking local: %v", err)
+	}
+	if imageExists {
+		logger.Info("found local image")
+		return nil
+	}
+
+	startTime := time.Now()
+	if err := c.PullImage(ctx, ref); err != nil {
+		logger.WithError(err).Error("error pulling image")
+		return fmt.Errorf("pull error (duration=%s) %s: %v", time.Since(startTime).String(), ref, err)
+	}
+	logger.Info("pulled image")
+	return nil
+}
+
+// EnsureLocalImages pulls the images asynchronously.
+func (c *nerdctlClient) EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) (errs []error) {
+	var outputs []*workers.Output
+	for _, imagePull := range imagePulls {
+		imagePull := imagePull
+		outputs = append(outputs, c.workers.Execute(func() ([]interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeoutPerPull)
+			defer cancel()
+			return nil, c.EnsureLocalImage(ctx, imagePull.Name, imagePull.Ref)
+		}))
+	}
+	for _, output := range outputs {
+		errs = append(errs, output.Error)
+	}
+	return
+}
+
+// SetImagePullCooldown sets the image pull cooldown.
+func (c *nerdctlClient) SetImagePullCooldown(threshold int, cooldownDuration time.Duration) {
+	c.imageDownloadCooldown = cooldown.New(threshold, cooldownDuration)
+}
+
+func (c *nerdctlClient) inspectNetworks(ctx context.Context, names ...string) ([]types.NetworkResource, error) {
+	b, err := c.exec(ctx, append([]string{"network", "inspect"}, names...)...)
+	if err != nil {
+		return nil, err
+	}
+	var networks []types.NetworkResource
+	if err := json.Unmarshal(b, &networks); err != nil {
+		return nil, fmt.Errorf("failed to decode the networks: %v", err)
+	}
+	return networks, nil
+}
+
+// EnsurePublicNetwork creates the network if it does not exist and returns the name as the ID.
+func (c *nerdctlClient) EnsurePublicNetwork(ctx context.Context, name string) (string, error) {
+	return c.createNetwork(ctx, name)
+}
+
+// EnsureInternalNetwork creates a regular network since nerdctl does not support the internal networks.
+func (c *nerdctlClient) EnsureInternalNetwork(ctx context.Context, name string) (string, error) {
+	log.WithField("network", name).Warn("nerdctl does not support internal networks - creating a regular one")
+	return c.createNetwork(ctx, name)
+}
+
+func (c *nerdctlClient) createNetwork(ctx context.Context, name string) (string, error) {
+	// reuse if the network exists
+	_, err := c.inspectNetworks(ctx, name)
+	if err == nil {
+		return name, nil
+	}
+	if !isNotFoundErr(err) {
+		return "", err
+	}
+	args := append([]string{"network", "create"}, keyValueArgs("--label", "%s=%s", c.labels)...)
+	if _, err := c.exec(ctx, append(args, name)...); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// RemoveNetworkByName removes the network if it exists.
+func (c *nerdctlClient) RemoveNetworkByName(ctx context.Context, networkName string) error {
+	_, err := c.exec(ctx, "network", "rm", networkName)
+	if err != nil && isNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// AttachNetwork is not supported by nerdctl. The networks are attached when the containers are created.
+func (c *nerdctlClient) AttachNetwork(ctx context.Context, containerID string, networkID string) error {
+	return ErrNetworkAttachUnsupported
+}
+
+// DetachNetwork is not supported by nerdctl.
+func (c *nerdctlClient) DetachNetwork(ctx context.Context, containerID string, networkID string) error {
+	return ErrNetworkAttachUnsupported
+}
+
+// listContainers lists all of the containers which have the labels.
+func (c *nerdctlClient) listContainers(ctx context.Context, labels map[string]string) (docker.ContainerList, error) {
+	args := append([]string{"ps", "--all", "--quiet", "--no-trunc"}, keyValueArgs("--filter", "label=%s=%s", labels)...)
+	b, err := c.exec(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids := strings.Fields(string(b))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	infos, err := c.inspectContainers(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	containers := make(docker.ContainerList, 0, len(infos))
+	for _, info := range infos {
+		containers = append(containers, toContainer(info))
+	}
+	return containers, nil
+}
+
+func (c *nerdctlClient) inspectContainers(ctx context.Context, ids ...string) ([]types.ContainerJSON, error) {
+	b, err := c.exec(ctx, append([]string{"container", "inspect", "--mode=dockercompat"}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+	var infos []types.ContainerJSON
+	if err := json.Unmarshal(b, &infos); err != nil {
+		return nil, fmt.Errorf("failed to decode the containers: %v", err)
+	}
+	return infos, nil
+}
+
+// toContainer converts the inspected container to the container list format.
+func toContainer(info types.ContainerJSON) types.Container {
+	var container types.Container
+	if info.ContainerJSONBase != nil {
+		container.ID = info.ID
+		container.Names = []string{"/" + strings.TrimPrefix(info.Name, "/")}
+		container.Image = info.Image
+		container.ImageID = info.Image
+		if created, err := time.Parse(time.RFC3339Nano, info.Created); err == nil {
+			container.Created = created.Unix()
+		}
+		if info.State != nil {
+			container.State = info.State.Status
+			container.Status = info.State.Status
+		}
+	}
+	if info.Config != nil {
+		if len(info.Config.Image) > 0 {
+			container.Image = info.Config.Image
+		}
+		container.Labels = info.Config.Labels
+	}
+	if info.NetworkSettings != nil {
+		container.NetworkSettings = &types.SummaryNetworkSettings{Networks: info.NetworkSettings.Networks}
+	}
+	return container
+}
+
+// GetContainers returns all of the containers.
+func (c *nerdctlClient) GetContainers(ctx context.Context) (docker.ContainerList, error) {
+	return c.listContainers(ctx, c.labels)
+}
+
+// GetContainersByLabel returns all of the containers that has the label.
+func (c *nerdctlClient) GetContainersByLabel(ctx context.Context, name, value string) (docker.ContainerList, error) {
+	return c.listContainers(ctx, map[string]string{name: value})
+}
+
+// GetzktoroServiceContainers returns all of the non-agent zktoro containers.
+func (c *nerdctlClient) GetzktoroServiceContainers(ctx context.Context) (zktoroContainers docker.ContainerList, err error) {
+	containers, err := c.GetContainers(ctx)
+	for _, container := range containers {
+		if !strings.Contains(docker.GetContainerName(container), "zktoro-agent") {
+			zktoroContainers = append(zktoroContainers, container)
+		}
+	}
+	return
+}
+
+// GetContainerByName gets a container by using a name lookup over all containers.
+func (c *nerdctlClient) GetContainerByName(ctx context.Context, name string) (*types.Container, error) {
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	container, ok := containers.FindByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%w with name '%s'", docker.ErrContainerNotFound, name)
+	}
+	return container, nil
+}
+
+// GetContainerByID gets a container by using an ID lookup over all containers.
+func (c *nerdctlClient) GetContainerByID(ctx context.Context, id string) (*types.Container, error) {
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	container, ok := containers.FindByID(id)
+	if !ok {
+		return nil, fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, id)
+	}
+	return container, nil
+}
+
+// InspectContainer returns container details.
+func (c *nerdctlClient) InspectContainer(ctx context.Context, id string) (*types.ContainerJSON, error) {
+	infos, err := c.inspectContainers(ctx, id)
+	if err == nil && len(infos) == 0 {
+		err = docker.ErrContainerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container details: %v", err)
+	}
+	return &infos[0], nil
+}
+
+// StartContainerWithID starts an existing container.
+func (c *nerdctlClient) StartContainerWithID(ctx context.Context, containerID string) error {
+	_, err := c.exec(ctx, "start", containerID)
+	return err
+}
+
+// StartContainer creates and starts a container or starts the existing one with the same name.
+func (c *nerdctlClient) StartContainer(ctx context.Context, containerCfg docker.ContainerConfig) (*docker.Container, error) {
+	logger := log.WithFields(log.Fields{
+		"image": containerCfg.Image,
+		"name":  containerCfg.Name,
+	})
+	logger.Info("StartContainer()")
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	containerID := ""
+	if foundContainer, ok := containers.FindByName(containerCfg.Name); ok {
+		containerID = foundContainer.ID
+	} else {
+		b, err := c.exec(ctx, c.createArgs(containerCfg)...)
+		if err != nil {
+			return nil, err
+		}
+		containerID = strings.TrimSpace(string(b))
+		for fn, content := range containerCfg.Files {
+			if err := c.copyFile(ctx, fn, content, containerID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := c.StartContainerWithID(ctx, containerID); err != nil {
+		return nil, err
+	}
+	inspection, err := c.InspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("id", containerID).Info("container is starting")
+	return &docker.Container{Name: containerCfg.Name, ID: containerID, Config: containerCfg, ImageHash: inspection.Image}, nil
+}
+
+func (c *nerdctlClient) createArgs(containerCfg docker.ContainerConfig) []string {
+	args := []string{"create", "--name", containerCfg.Name}
+
+	labels := make(map[string]string)
+	for k, v := range c.labels {
+		labels[k] = v
+	}
+	for k, v := range containerCfg.Labels {
+		labels[k] = v
+	}
+	args = append(args, keyValueArgs("--label", "%s=%s", labels)...)
+	args = append(args, keyValueArgs("--env", "%s=%s", containerCfg.Env)...)
+
+	// the linked networks cannot be attached later so they are attached on creation
+	if len(containerCfg.NetworkID) > 0 {
+		args = append(args, "--network", containerCfg.NetworkID)
+	}
+	for _, nwID := range containerCfg.LinkNetworkIDs {
+		args = append(args, "--network", nwID)
+	}
+
+	for _, hp := range sortedKeys(containerCfg.Ports) {
+		cp := containerCfg.Ports[hp]
+		hostIP := "0.0.0.0"
+		parts := strings.Split(hp, ":")
+		if len(parts) == 2 {
+			hostIP = parts[0]
+			hp = parts[1]
+		}
+		// the empty host port publishes to a random port
+		args = append(args, "--publish", fmt.Sprintf("%s:%s:%s", hostIP, hp, cp))
+	}
+	if containerCfg.PublishAllPorts {
+		args = append(args, "--publish-all")
+	}
+
+	args = append(args, keyValueArgs("--volume", "%s:%s", containerCfg.Volumes)...)
+
+	maxLogSize := containerCfg.MaxLogSize
+	if maxLogSize == "" {
+		maxLogSize = "10m"
+	}
+	maxLogFiles := containerCfg.MaxLogFiles
+	if maxLogFiles == 0 {
+		maxLogFiles = 10
+	}
+	args = append(args,
+		"--log-opt", fmt.Sprintf("max-size=%s", maxLogSize),
+		"--log-opt", fmt.Sprintf("max-file=%d", maxLogFiles),
+	)
+
+	if containerCfg.CPUQuota > 0 {
+		args = append(args, "--cpu-quota", strconv.FormatInt(containerCfg.CPUQuota, 10))
+	}
+	if containerCfg.Memory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(containerCfg.Memory, 10))
+	}
+	if containerCfg.PidsLimit > 0 {
+		args = append(args, "--pids-limit", strconv.FormatInt(containerCfg.PidsLimit, 10))
+	}
+	if containerCfg.DiskSize > 0 {
+		log.WithField("name", containerCfg.Name).Warn("nerdctl does not support disk limits - ignoring")
+	}
+	if containerCfg.DialHost {
+		args = append(args, "--add-host", "host.docker.internal:host-gateway")
+	}
+	if containerCfg.Privileged {
+		args = append(args, "--privileged")
+	}
+
+	args = append(args, containerCfg.Image)
+	return append(args, containerCfg.Cmd...)
+}
+
+// copyFile copies content bytes into container at given file path.
+func (c *nerdctlClient) copyFile(ctx context.Context, filePath string, content []byte, containerID string) error {
+	if len(filePath) == 0 {
+		return errors.New("zero length file path")
+	}
+	if filePath[0] != '/' {
+		filePath = "/" + filePath
+	}
+	f, err := os.CreateTemp("", "zktoro-file-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	_, err = c.exec(ctx, "cp", f.Name(), fmt.Sprintf("%s:%s", containerID, filePath))
+	return err
+}
+
+// StopContainer kills a container by ID
+func (c *nerdctlClient) StopContainer(ctx context.Context, id string) error {
+	return c.stopContainer(ctx, id, "SIGKILL")
+}
+
+// InterruptContainer stops a container by sending an interrupt signal.
+func (c *nerdctlClient) InterruptContainer(ctx context.Context, id string) error {
+	return c.stopContainer(ctx, id, "SIGINT")
+}
+
+// TerminateContainer stops a container by sending an termination signal.
+func (c *nerdctlClient) TerminateContainer(ctx context.Context, id string) error {
+	return c.stopContainer(ctx, id, "SIGTERM")
+}
+
+func (c *nerdctlClient) stopContainer(ctx context.Context, containerID, signal string) error {
+	log.WithFields(log.Fields{
+		"id":     containerID,
+		"signal": signal,
+	}).Info("stopping container")
+
+	_, err := c.exec(ctx, "kill", "--signal", signal, containerID)
+	if err != nil && (isNotFoundErr(err) || isNotRunningErr(err)) {
+		return nil
+	}
+	return err
+}
+
+// ShutdownContainer stops a container by sending a termination signal and waits until either container exits or context cancels.
+func (c *nerdctlClient) ShutdownContainer(ctx context.Context, id string, timeout *time.Duration) error {
+	_, err := c.exec(ctx, "stop", "--time", strconv.Itoa(int(timeout.Seconds())), id)
+	return err
+}
+
+// RemoveContainer kills and removes a container by ID.
+func (c *nerdctlClient) RemoveContainer(ctx context.Context, containerID string) error {
+	_, err := c.exec(ctx, "rm", "--force", containerID)
+	return err
+}
+
+// WaitContainerExit waits for container exit by checking periodically.
+func (c *nerdctlClient) WaitContainerExit(ctx context.Context, id string) error {
+	return docker.WaitContainerExit(ctx, c.GetContainerByID, id)
+}
+
+// WaitContainerStart waits for container start by checking periodically.
+func (c *nerdctlClient) WaitContainerStart(ctx context.Context, id string) error {
+	return docker.WaitContainerStart(ctx, c.GetContainerByID, id)
+}
+
+// WaitContainerPrune waits for container prune by checking periodically.
+func (c *nerdctlClient) WaitContainerPrune(ctx context.Context, id string) error {
+	return docker.WaitContainerPrune(ctx, c.GetContainerByID, id)
+}
+
+// Prune removes the stopped containers and the unused networks of this client.
+func (c *nerdctlClient) Prune(ctx context.Context) error {
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		if container.State == "running" {
+			continue
+		}
+		if err := c.RemoveContainer(ctx, container.ID); err != nil {
+			return err
+		}
+		log.Infof("pruned container %s", container.ID)
+	}
+
+	b, err := c.exec(ctx, "network", "ls", "--format", "{{.Name}}")
+	if err != nil {
+		return err
+	}
+	names := strings.Fields(string(b))
+	if len(names) == 0 {
+		return nil
+	}
+	networks, err := c.inspectNetworks(ctx, names...)
+	if err != nil {
+		return err
+	}
+	for _, network := range networks {
+		if !hasLabels(network.Labels, c.labels) {
+			continue
+		}
+		// the networks which are still in use cannot be removed
+		if err := c.RemoveNetworkByName(ctx, network.Name); err != nil {
+			log.WithError(err).WithField("network", network.Name).Debug("failed to prune network")
+			continue
+		}
+		log.Infof("pruned network %s", network.Name)
+	}
+	return nil
+}
+
+func hasLabels(labels, expected map[string]string) bool {
+	for k, v := range expected {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Nuke makes sure that all running zktoro containers are stopped and pruned, quickly enough.
+func (c *nerdctlClient) Nuke(ctx context.Context) error {
+	var err error
+	for i := 0; i < 4; i++ {
+		err = c.nuke(ctx)
+		if err == nil {
+			return nil
+		}
+		log.WithError(err).Error("failed to nuke - retrying")
+	}
+	return fmt.Errorf("all nuke retries failed: %v", err)
+}
+
+func (c *nerdctlClient) nuke(ctx context.Context) error {
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get zktoro containers list: %v", err)
+	}
+	// stop the supervisor first so it doesn't do funny restarts
+	sort.SliceStable(containers, func(i, j int) bool {
+		return docker.GetContainerName(containers[i]) == config.DockerSupervisorContainerName &&
+			docker.GetContainerName(containers[j]) != config.DockerSupervisorContainerName
+	})
+	for _, container := range containers {
+		if err := c.StopContainer(ctx, container.ID); err != nil {
+			return fmt.Errorf("failed to stop: %v", err)
+		}
+		if err := c.WaitContainerExit(ctx, container.ID); err != nil {
+			return err
+		}
+	}
+	if err := c.Prune(ctx); err != nil {
+		return fmt.Errorf("failed to prune: %v", err)
+	}
+	for _, container := range containers {
+		if err := c.WaitContainerPrune(ctx, container.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetContainerLogs gets the container logs.
+func (c *nerdctlClient) GetContainerLogs(ctx context.Context, containerID, tail string, truncate int) (string, error) {
+	args := []string{"logs", "--timestamps"}
+	if len(tail) > 0 {
+		args = append(args, "--tail", tail)
+	}
+	b, err := c.run(ctx, &command{Args: append(args, containerID), CombinedOutput: true})
+	if err != nil {
+		return "", err
+	}
+	if truncate >= 0 && len(b) > truncate {
+		b = b[:truncate]
+	}
+	return string(b), nil
+}
+
+// GetContainerFromRemoteAddr finds the container which has the IP address of the remote address.
+func (c *nerdctlClient) GetContainerFromRemoteAddr(ctx context.Context, hostPort string) (*types.Container, error) {
+	containers, err := c.GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return containers.FindByRemoteAddr(hostPort)
+}
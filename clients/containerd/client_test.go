@@ -0,0 +1,249 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"zktoro/clients/docker"
+	"zktoro/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInspectOutput = `[{
+	"Id": "abc123",
+	"Created": "2023-01-02T03:04:05.000000006Z",
+	"Name": "zktoro-scanner",
+	"Image": "zktoro-node@sha256:1234",
+	"State": {"Status": "running", "Running": true},
+	"Config": {"Labels": {"network.zktoro": "true", "network.zktoro.supervisor": "supervisor"}},
+	"NetworkSettings": {"Networks": {"zktoro-bots": {"IPAddress": "10.4.0.2"}}}
+}]`
+
+// testRunner replies to the nerdctl commands with the outputs of the matching command prefixes.
+type testRunner struct {
+	outputs map[string]string
+	errs    map[string]error
+	cmds    []string
+}
+
+func (tr *testRunner) run(ctx context.Context, cmd *command) ([]byte, error) {
+	cmdStr := strings.Join(cmd.Args, " ")
+	tr.cmds = append(tr.cmds, cmdStr)
+	for prefix, err := range tr.errs {
+		if strings.HasPrefix(cmdStr, prefix) {
+			return nil, err
+		}
+	}
+	for prefix, output := range tr.outputs {
+		if strings.HasPrefix(cmdStr, prefix) {
+			return []byte(output), nil
+		}
+	}
+	return nil, nil
+}
+
+func TestGetContainers(t *testing.T) {
+	r := require.New(t)
+
+	tr := &testRunner{outputs: map[string]string{
+		"ps ":                "abc123\n",
+		"container inspect ": testInspectOutput,
+	}}
+	client := newNerdctlClient(tr.run, "supervisor", "", "")
+
+	containers, err := client.GetContainers(context.Background())
+	r.NoError(err)
+	r.Len(containers, 1)
+	r.Equal([]string{
+		"ps --all --quiet --no-trunc --filter label=network.zktoro=true --filter label=network.zktoro.supervisor=supervisor",
+		"container inspect --mode=dockercompat abc123",
+	}, tr.cmds)
+
+	container := containers[0]
+	r.Equal("abc123", container.ID)
+	r.Equal("zktoro-scanner", docker.GetContainerName(container))
+	r.Equal("zktoro-node@sha256:1234", container.Image)
+	r.Equal("running", container.State)
+	r.EqualValues(1672628645, container.Created)
+	r.Equal("supervisor", container.Labels[docker.LabelzktoroSupervisor])
+
+	found, err := client.GetContainerFromRemoteAddr(context.Background(), "10.4.0.2:1234")
+	r.NoError(err)
+	r.Equal("abc123", found.ID)
+
+	_, err = client.GetContainerByName(context.Background(), "zktoro-json-rpc")
+	r.ErrorIs(err, docker.ErrContainerNotFound)
+}
+
+func TestStartContainer(t *testing.T) {
+	r := require.New(t)
+
+	tr := &testRunner{outputs: map[string]string{
+		"create ":            "abc123\n",
+		"container inspect ": testInspectOutput,
+	}}
+	client := newNerdctlClient(tr.run, "supervisor", "", "")
+
+	container, err := client.StartContainer(context.Background(), docker.ContainerConfig{
+		Name:           "zktoro-scanner",
+		Image:          "zktoro-node",
+		Env:            map[string]string{"B": "2", "A": "1"},
+		NetworkID:      "zktoro-scanner",
+		LinkNetworkIDs: []string{"zktoro-nats", "zktoro-bots"},
+		Ports:          map[string]string{"127.0.0.1:8080": "80", "": "8888"},
+		Volumes:        map[string]string{"/run/containerd/containerd.sock": "/run/containerd/containerd.sock"},
+		Files:          map[string][]byte{"passphrase": []byte("secret")},
+		CPUQuota:       50000,
+		Memory:         1024,
+		PidsLimit:      100,
+		DialHost:       true,
+		Cmd:            []string{"/zktoro-node", "scanner"},
+	})
+	r.NoError(err)
+	r.Equal("abc123", container.ID)
+	r.Equal("zktoro-node@sha256:1234", container.ImageHash)
+
+	r.Len(tr.cmds, 5)
+	r.Equal(
+		"create --name zktoro-scanner"+
+			" --label network.zktoro=true --label network.zktoro.supervisor=supervisor"+
+			" --env A=1 --env B=2"+
+			" --network zktoro-scanner --network zktoro-nats --network zktoro-bots"+
+			" --publish 0.0.0.0::8888 --publish 127.0.0.1:8080:80"+
+			" --volume /run/containerd/containerd.sock:/run/containerd/containerd.sock"+
+			" --log-opt max-size=10m --log-opt max-file=10"+
+			" --cpu-quota 50000 --memory 1024 --pids-limit 100"+
+			" --add-host host.docker.internal:host-gateway"+
+			" zktoro-node /zktoro-node scanner",
+		tr.cmds[1],
+	)
+	r.True(strings.HasPrefix(tr.cmds[2], "cp "))
+	r.True(strings.HasSuffix(tr.cmds[2], " abc123:/passphrase"))
+	r.Equal("start abc123", tr.cmds[3])
+}
+
+// testNerdctlScript records the arguments and the copied files and replies like nerdctl.
+const testNerdctlScript = `#!/bin/sh
+echo "$*" >> "$TEST_NERDCTL_DIR/args"
+shift 4 # the global args
+case "$1" in
+create) echo abc123 ;;
+container) cat "$TEST_NERDCTL_DIR/inspect.json" ;;
+cp) cp "$2" "$TEST_NERDCTL_DIR/copied" ;;
+esac
+`
+
+func TestStartContainer_Nerdctl(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	r := require.New(t)
+
+	dir := t.TempDir()
+	nerdctlPath := filepath.Join(dir, "nerdctl")
+	r.NoError(os.WriteFile(nerdctlPath, []byte(testNerdctlScript), 0700))
+	r.NoError(os.WriteFile(filepath.Join(dir, "inspect.json"), []byte(testInspectOutput), 0600))
+	t.Setenv("TEST_NERDCTL_DIR", dir)
+
+	runtimeCfg := config.ContainerRuntimeConfig{
+		Type:        config.ContainerRuntimeContainerd,
+		Namespace:   "zktoro",
+		NerdctlPath: nerdctlPath,
+	}
+	client := NewNerdctlClient("supervisor", runtimeCfg, "", "")
+
+	container, err := client.StartContainer(context.Background(), docker.ContainerConfig{
+		Name:       "zktoro-supervisor",
+		Image:      "zktoro-node",
+		Volumes:    runtimeCfg.WithRuntimeVolumes(nil),
+		Files:      map[string][]byte{"passphrase": []byte("secret")},
+		Privileged: runtimeCfg.PrivilegedClients(),
+		Cmd:        []string{"/zktoro-node", "supervisor"},
+	})
+	r.NoError(err)
+	r.Equal("abc123", container.ID)
+	r.Equal("zktoro-node@sha256:1234", container.ImageHash)
+
+	b, err := os.ReadFile(filepath.Join(dir, "args"))
+	r.NoError(err)
+	cmds := strings.Split(strings.TrimSpace(string(b)), "\n")
+	r.Len(cmds, 5)
+	for _, cmd := range cmds {
+		r.True(strings.HasPrefix(cmd, "--address /run/containerd/containerd.sock --namespace zktoro "))
+	}
+	r.Equal(
+		"--address /run/containerd/containerd.sock --namespace zktoro create --name zktoro-supervisor"+
+			" --label network.zktoro=true --label network.zktoro.supervisor=supervisor"+
+			" --volume /etc/cni/net.d:/etc/cni/net.d"+
+			" --volume /run/containerd/containerd.sock:/run/containerd/containerd.sock"+
+			" --volume /var/lib/cni:/var/lib/cni"+
+			" --volume /var/lib/containerd:/var/lib/containerd"+
+			" --volume /var/lib/nerdctl:/var/lib/nerdctl"+
+			" --log-opt max-size=10m --log-opt max-file=10"+
+			" --privileged"+
+			" zktoro-node /zktoro-node supervisor",
+		cmds[1],
+	)
+	r.True(strings.HasSuffix(cmds[2], " abc123:/passphrase"))
+	r.Equal("--address /run/containerd/containerd.sock --namespace zktoro start abc123", cmds[3])
+
+	copied, err := os.ReadFile(filepath.Join(dir, "copied"))
+	r.NoError(err)
+	r.Equal("secret", string(copied))
+}
+
+func TestStartContainer_NerdctlError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	r := require.New(t)
+
+	nerdctlPath := filepath.Join(t.TempDir(), "nerdctl")
+	r.NoError(os.WriteFile(nerdctlPath, []byte("#!/bin/sh\necho \"cannot connect\" >&2\nexit 1\n"), 0700))
+
+	client := NewNerdctlClient("supervisor", config.ContainerRuntimeConfig{NerdctlPath: nerdctlPath}, "", "")
+	_, err := client.StartContainer(context.Background(), docker.ContainerConfig{Name: "zktoro-supervisor"})
+	r.EqualError(err, "nerdctl ps: exit status 1: cannot connect")
+}
+
+func TestImagesAndNetworks(t *testing.T) {
+	r := require.New(t)
+
+	tr := &testRunner{
+		errs: map[string]error{
+			"image inspect ":   errors.New("nerdctl image: exit status 1: no such image: bot-image"),
+			"network inspect ": errors.New("nerdctl network: exit status 1: network \"bot-network\" not found"),
+		},
+	}
+	client := newNerdctlClient(tr.run, "", "user", "pass")
+
+	r.NoError(client.EnsureLocalImage(context.Background(), "bot", "registry.example.com/bot-image"))
+	r.Equal([]string{
+		"image inspect registry.example.com/bot-image",
+		"login --username user --password-stdin registry.example.com",
+		"pull --quiet registry.example.com/bot-image",
+	}, tr.cmds)
+
+	networkID, err := client.EnsurePublicNetwork(context.Background(), "bot-network")
+	r.NoError(err)
+	r.Equal("bot-network", networkID)
+	r.Equal("network create --label network.zktoro=true bot-network", tr.cmds[len(tr.cmds)-1])
+
+	r.ErrorIs(client.AttachNetwork(context.Background(), "abc123", networkID), ErrNetworkAttachUnsupported)
+}
+
+func TestRegistryHost(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("docker.io", registryHost("nats:2.3.2"))
+	r.Equal("docker.io", registryHost("library/nats"))
+	r.Equal("localhost", registryHost("localhost/bot"))
+	r.Equal("disco.zktoro.network", registryHost("disco.zktoro.network/bafybei@sha256:1234"))
+	r.Equal("127.0.0.1:5000", registryHost("127.0.0.1:5000/bot"))
+}
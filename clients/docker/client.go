@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -73,8 +74,11 @@ type ContainerConfig struct {
 	MaxLogFiles     int
 	CPUQuota        int64
 	Memory          int64
+	PidsLimit       int64
+	DiskSize        int64 // in bytes, needs a storage driver with quota support
 	Cmd             []string
 	DialHost        bool
+	Privileged      bool
 	Labels          map[string]string
 }
 
@@ -113,6 +117,23 @@ func (dcl ContainerList) ContainsAny(name string) (*types.Container, bool) {
 	return nil, false
 }
 
+// FindByRemoteAddr finds the container which has the IP address of the remote address in one of its networks.
+func (dcl ContainerList) FindByRemoteAddr(hostPort string) (*types.Container, error) {
+	ipAddr := strings.Split(hostPort, ":")[0]
+	for _, container := range dcl {
+		if container.NetworkSettings == nil {
+			continue
+		}
+		for _, network := range container.NetworkSettings.Networks {
+			if network.IPAddress == ipAddr {
+				return &container, nil
+			}
+		}
+	}
+	log.WithField("sourceIp", ipAddr).Warn("not a known bot")
+	return nil, fmt.Errorf("could not found agent container from ip address: %s", hostPort)
+}
+
 type dockerClient struct {
 	cli                   *client.Client
 	workers               *workers.Group
@@ -531,6 +552,7 @@ func (d *dockerClient) StartContainer(ctx context.Context, config ContainerConfi
 		PortBindings:    bindings,
 		PublishAllPorts: config.PublishAllPorts,
 		Binds:           volumes,
+		Privileged:      config.Privileged,
 		LogConfig: container.LogConfig{
 			Config: map[string]string{
 				"max-file": fmt.Sprintf("%d", maxLogFiles),
@@ -543,6 +565,13 @@ func (d *dockerClient) StartContainer(ctx context.Context, config ContainerConfi
 			Memory:   config.Memory,
 		},
 	}
+	if config.PidsLimit > 0 {
+		pidsLimit := config.PidsLimit
+		hostCfg.Resources.PidsLimit = &pidsLimit
+	}
+	if config.DiskSize > 0 {
+		hostCfg.StorageOpt = map[string]string{"size": strconv.FormatInt(config.DiskSize, 10)}
+	}
 
 	if config.DialHost {
 		hostCfg.ExtraHosts = append(hostCfg.ExtraHosts, "host.docker.internal:host-gateway")
@@ -642,90 +671,17 @@ func isNotRunningErr(err error) bool {
 
 // WaitContainerExit waits for container exit by checking periodically.
 func (d *dockerClient) WaitContainerExit(ctx context.Context, id string) error {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	logger := log.WithFields(log.Fields{
-		"id": id,
-	})
-
-	ctx, cancel := context.WithTimeout(ctx, time.Minute)
-	defer cancel()
-
-	for {
-		logger.Info("waiting for container exit")
-		c, err := d.GetContainerByID(ctx, id)
-		if err != nil && errors.Is(err, ErrContainerNotFound) {
-			logger.Info("no need to wait for container exit - not found")
-			return nil
-		}
-		if err != nil {
-			logger.WithError(err).Error("failed while waiting for container exit")
-			return err
-		}
-		if c.State == "exited" || c.State == "created" {
-			return nil
-		}
-		logger.WithField("containerState", c.State).Info("still waiting for exit")
-		<-ticker.C
-	}
+	return WaitContainerExit(ctx, d.GetContainerByID, id)
 }
 
 // WaitContainerStart waits for container start by checking periodically.
 func (d *dockerClient) WaitContainerStart(ctx context.Context, id string) error {
-	ticker := time.NewTicker(time.Second)
-	start := time.Now()
-	logger := log.WithFields(log.Fields{
-		"id": id,
-	})
-
-	ctx, cancel := context.WithTimeout(ctx, time.Minute)
-	defer cancel()
-
-	for t := range ticker.C {
-		logger.Info("waiting for container start")
-		c, err := d.GetContainerByID(ctx, id)
-		if err == nil && c != nil && c.State == "running" {
-			logger.Info("container started")
-			return nil
-		}
-		if err != nil {
-			return err
-		}
-		// if the conditions are not met within 30 seconds, it's a failure
-		if t.After(start.Add(time.Second * 30)) {
-			return errors.New("container did not start")
-		}
-	}
-	return nil
+	return WaitContainerStart(ctx, d.GetContainerByID, id)
 }
 
 // WaitContainerPrune waits for container prune by checking periodically.
 func (d *dockerClient) WaitContainerPrune(ctx context.Context, id string) error {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	logger := log.WithFields(log.Fields{
-		"id": id,
-	})
-
-	ctx, cancel := context.WithTimeout(ctx, time.Minute)
-	defer cancel()
-
-	for {
-		logger.Infof("waiting for container prune")
-		c, err := d.GetContainerByID(ctx, id)
-		if err != nil && errors.Is(err, ErrContainerNotFound) {
-			return nil
-		}
-		if err != nil {
-			logger.WithError(err).Error("error while waiting for prune")
-			return err
-		}
-		logger.WithField("containerState", c.State).Info("container state while waiting for prune")
-		if !(c.State == "exited" || c.State == "dead") {
-			err = fmt.Errorf("cannot prune container with status '%s' - container needs to stop first", c.State)
-			logger.WithError(err).Error("error while waiting for prune")
-			return err
-		}
-		<-ticker.C
-	}
+	return WaitContainerPrune(ctx, d.GetContainerByID, id)
 }
 
 // HasLocalImage checks if we have an image locally.
@@ -838,27 +794,7 @@ func (d *dockerClient) GetContainerFromRemoteAddr(ctx context.Context, hostPort
 	if err != nil {
 		return nil, err
 	}
-	ipAddr := strings.Split(hostPort, ":")[0]
-
-	var agentContainer *types.Container
-	for _, container := range containers {
-		for _, network := range container.NetworkSettings.Networks {
-			if network.IPAddress == ipAddr {
-				agentContainer = &container
-				break
-			}
-		}
-		if agentContainer != nil {
-			break
-		}
-	}
-
-	if agentContainer == nil {
-		log.WithField("sourceIp", ipAddr).Warn("not a known bot")
-		return nil, fmt.Errorf("could not found agent container from ip address: %s", hostPort)
-	}
-
-	return agentContainer, nil
+	return containers.FindByRemoteAddr(hostPort)
 }
 
 func initLabels(name string) []dockerLabel {
@@ -889,31 +825,37 @@ func (d *dockerClient) SetImagePullCooldown(threshold int, cooldownDuration time
 
 // NewDockerClient creates a new docker client
 func NewDockerClient(name string) (*dockerClient, error) {
+	return NewAuthDockerClient(name, "", "")
+}
+
+// NewAuthDockerClient creates a new docker client with credentials
+func NewAuthDockerClient(name string, username, password string) (*dockerClient, error) {
 	cli, err := client.NewClientWithOpts()
 	if err != nil {
 		return nil, err
 	}
-	return &dockerClient{
-		cli:     cli,
-		workers: workers.New(1),
-		labels:  initLabels(name),
-	}, nil
+	return newDockerClient(cli, name, username, password), nil
 }
 
-// NewAuthDockerClient creates a new docker client with credentials
-func NewAuthDockerClient(name string, username, password string) (*dockerClient, error) {
-	if len(username) == 0 && len(password) == 0 {
-		return NewDockerClient(name)
-	}
-	cli, err := client.NewClientWithOpts()
+// NewSocketClient creates a new client for a runtime with a Docker-compatible API on the given
+// unix socket like Podman. The API version is negotiated since Podman implements an older version.
+func NewSocketClient(name, socketPath string, username, password string) (*dockerClient, error) {
+	cli, err := client.NewClientWithOpts(
+		client.WithHost("unix://"+socketPath),
+		client.WithAPIVersionNegotiation(),
+	)
 	if err != nil {
 		return nil, err
 	}
+	return newDockerClient(cli, name, username, password), nil
+}
+
+func newDockerClient(cli *client.Client, name string, username, password string) *dockerClient {
 	return &dockerClient{
 		cli:      cli,
 		workers:  workers.New(1),
 		username: username,
 		password: password,
 		labels:   initLabels(name),
-	}, nil
+	}
 }
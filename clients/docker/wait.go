@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ContainerGetter gets a container by the ID. The runtimes wait for the container
+// state changes by polling it.
+type ContainerGetter func(ctx context.Context, id string) (*types.Container, error)
+
+// WaitContainerExit waits for container exit by checking periodically.
+func WaitContainerExit(ctx context.Context, getContainer ContainerGetter, id string) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	logger := log.WithFields(log.Fields{
+		"id": id,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	for {
+		logger.Info("waiting for container exit")
+		c, err := getContainer(ctx, id)
+		if err != nil && errors.Is(err, ErrContainerNotFound) {
+			logger.Info("no need to wait for container exit - not found")
+			return nil
+		}
+		if err != nil {
+			logger.WithError(err).Error("failed while waiting for container exit")
+			return err
+		}
+		if c.State == "exited" || c.State == "created" {
+			return nil
+		}
+		logger.WithField("containerState", c.State).Info("still waiting for exit")
+		<-ticker.C
+	}
+}
+
+// WaitContainerStart waits for container start by checking periodically.
+func WaitContainerStart(ctx context.Context, getContainer ContainerGetter, id string) error {
+	ticker := time.NewTicker(time.Second)
+	start := time.Now()
+	logger := log.WithFields(log.Fields{
+		"id": id,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	for t := range ticker.C {
+		logger.Info("waiting for container start")
+		c, err := getContainer(ctx, id)
+		if err == nil && c != nil && c.State == "running" {
+			logger.Info("container started")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// if the conditions are not met within 30 seconds, it's a failure
+		if t.After(start.Add(time.Second * 30)) {
+			return errors.New("container did not start")
+		}
+	}
+	return nil
+}
+
+// WaitContainerPrune waits for container prune by checking periodically.
+func WaitContainerPrune(ctx context.Context, getContainer ContainerGetter, id string) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	logger := log.WithFields(log.Fields{
+		"id": id,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	for {
+		logger.Infof("waiting for container prune")
+		c, err := getContainer(ctx, id)
+		if err != nil && errors.Is(err, ErrContainerNotFound) {
+			return nil
+		}
+		if err != nil {
+			logger.WithError(err).Error("error while waiting for prune")
+			return err
+		}
+		logger.WithField("containerState", c.State).Info("container state while waiting for prune")
+		if !(c.State == "exited" || c.State == "dead") {
+			err = fmt.Errorf("cannot prune container with status '%s' - container needs to stop first", c.State)
+			logger.WithError(err).Error("error while waiting for prune")
+			return err
+		}
+		<-ticker.C
+	}
+}
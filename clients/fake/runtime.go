@@ -0,0 +1,556 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"zktoro/clients"
+	"zktoro/clients/docker"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// Container states
+const (
+	StateRunning = "running"
+	StateExited  = "exited"
+)
+
+// ErrImageNotFound is returned when the image is neither local nor pullable.
+var ErrImageNotFound = errors.New("image not found")
+
+type fakeNetwork struct {
+	id     string
+	name   string
+	subnet int
+	hosts  int
+}
+
+type fakeContainer struct {
+	container types.Container
+	config    docker.ContainerConfig
+	logs      string
+}
+
+var _ clients.ContainerRuntime = &ContainerRuntime{}
+
+// ContainerRuntime is an in-memory container runtime which lets the components which manage
+// the containers run in tests without a daemon. The containers do not run anything.
+type ContainerRuntime struct {
+	images         map[string]bool
+	pullableImages map[string]bool
+	pullAll        bool
+	networks       map[string]*fakeNetwork
+	containers     map[string]*fakeContainer
+	lastID         int
+	mu             sync.Mutex
+}
+
+// NewContainerRuntime creates a new in-memory container runtime which can pull any image.
+func NewContainerRuntime() *ContainerRuntime {
+	return &ContainerRuntime{
+		images:         make(map[string]bool),
+		pullableImages: make(map[string]bool),
+		pullAll:        true,
+		networks:       make(map[string]*fakeNetwork),
+		containers:     make(map[string]*fakeContainer),
+	}
+}
+
+// SetPullableImages restricts the images which can be pulled.
+func (r *ContainerRuntime) SetPullableImages(refs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pullAll = false
+	for _, ref := range refs {
+		r.pullableImages[ref] = true
+	}
+}
+
+// AddImage adds a local image.
+func (r *ContainerRuntime) AddImage(ref string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.images[ref] = true
+}
+
+// SetContainerLogs sets the logs of a container.
+func (r *ContainerRuntime) SetContainerLogs(id, logs string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.containers[id]; ok {
+		c.logs = logs
+	}
+}
+
+// ContainerConfig returns the config which the container was created with.
+func (r *ContainerRuntime) ContainerConfig(id string) (docker.ContainerConfig, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.containers[id]
+	if !ok {
+		return docker.ContainerConfig{}, false
+	}
+	return c.config, true
+}
+
+// NetworkNames returns the names of the networks.
+func (r *ContainerRuntime) NetworkNames() (names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range r.networks {
+		names = append(names, name)
+	}
+	return
+}
+
+func (r *ContainerRuntime) nextID(prefix string) string {
+	r.lastID++
+	return fmt.Sprintf("%s-%d", prefix, r.lastID)
+}
+
+// PullImage pulls the image if it is pullable.
+func (r *ContainerRuntime) PullImage(ctx context.Context, refStr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.pullAll && !r.pullableImages[refStr] {
+		return fmt.Errorf("%w: %s", ErrImageNotFound, refStr)
+	}
+	r.images[refStr] = true
+	return nil
+}
+
+// RemoveImage removes the image if no container uses it.
+func (r *ContainerRuntime) RemoveImage(ctx context.Context, refStr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.containers {
+		if c.container.Image == refStr {
+			return nil
+		}
+	}
+	delete(r.images, refStr)
+	return nil
+}
+
+// HasLocalImage checks if the image is local.
+func (r *ContainerRuntime) HasLocalImage(ctx context.Context, ref string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.images[ref], nil
+}
+
+// EnsureLocalImage pulls the image if it is not local.
+func (r *ContainerRuntime) EnsureLocalImage(ctx context.Context, name, ref string) error {
+	if ok, _ := r.HasLocalImage(ctx, ref); ok {
+		return nil
+	}
+	return r.PullImage(ctx, ref)
+}
+
+// EnsureLocalImages pulls the images which are not local.
+func (r *ContainerRuntime) EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) (errs []error) {
+	for _, imagePull := range imagePulls {
+		errs = append(errs, r.EnsureLocalImage(ctx, imagePull.Name, imagePull.Ref))
+	}
+	return
+}
+
+// SetImagePullCooldown does nothing.
+func (r *ContainerRuntime) SetImagePullCooldown(threshold int, cooldownDuration time.Duration) {}
+
+// EnsurePublicNetwork creates the network if it does not exist.
+func (r *ContainerRuntime) EnsurePublicNetwork(ctx context.Context, name string) (string, error) {
+	return r.ensureNetwork(name), nil
+}
+
+// EnsureInternalNetwork creates the internal network if it does not exist.
+func (r *ContainerRuntime) EnsureInternalNetwork(ctx context.Context, name string) (string, error) {
+	return r.ensureNetwork(name), nil
+}
+
+func (r *ContainerRuntime) ensureNetwork(name string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if nw, ok := r.networks[name]; ok {
+		return nw.id
+	}
+	nw := &fakeNetwork{
+		id:     r.nextID("network"),
+		name:   name,
+		subnet: len(r.networks) + 1,
+	}
+	r.networks[name] = nw
+	return nw.id
+}
+
+func (r *ContainerRuntime) findNetwork(idOrName string) (*fakeNetwork, bool) {
+	if nw, ok := r.networks[idOrName]; ok {
+		return nw, true
+	}
+	for _, nw := range r.networks {
+		if nw.id == idOrName {
+			return nw, true
+		}
+	}
+	return nil, false
+}
+
+// AttachNetwork attaches the container to the network and assigns an IP address.
+func (r *ContainerRuntime) AttachNetwork(ctx context.Context, containerID string, networkID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.attachNetwork(containerID, networkID)
+}
+
+func (r *ContainerRuntime) attachNetwork(containerID string, networkID string) error {
+	c, ok := r.containers[containerID]
+	if !ok {
+		return fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, containerID)
+	}
+	nw, ok := r.findNetwork(networkID)
+	if !ok {
+		return fmt.Errorf("no such network: %s", networkID)
+	}
+	networks := c.container.NetworkSettings.Networks
+	if _, ok := networks[nw.name]; ok {
+		return nil
+	}
+	nw.hosts++
+	networks[nw.name] = &network.EndpointSettings{
+		NetworkID: nw.id,
+		IPAddress: fmt.Sprintf("10.0.%d.%d", nw.subnet, nw.hosts+1),
+	}
+	return nil
+}
+
+// DetachNetwork detaches the container from the network.
+func (r *ContainerRuntime) DetachNetwork(ctx context.Context, containerID string, networkID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.containers[containerID]
+	if !ok {
+		return fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, containerID)
+	}
+	if nw, ok := r.findNetwork(networkID); ok {
+		delete(c.container.NetworkSettings.Networks, nw.name)
+	}
+	return nil
+}
+
+// RemoveNetworkByName removes the network if no container uses it.
+func (r *ContainerRuntime) RemoveNetworkByName(ctx context.Context, networkName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.networks[networkName]; !ok {
+		return nil
+	}
+	for _, c := range r.containers {
+		if _, ok := c.container.NetworkSettings.Networks[networkName]; ok {
+			return fmt.Errorf("network %s is in use by container %s", networkName, c.container.ID)
+		}
+	}
+	delete(r.networks, networkName)
+	return nil
+}
+
+func (r *ContainerRuntime) list(match func(c *types.Container) bool) (containers docker.ContainerList) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.containers {
+		if match(&c.container) {
+			containers = append(containers, copyContainer(&c.container))
+		}
+	}
+	return
+}
+
+func copyContainer(c *types.Container) types.Container {
+	cc := *c
+	networks := make(map[string]*network.EndpointSettings)
+	for name, settings := range c.NetworkSettings.Networks {
+		settingsCopy := *settings
+		networks[name] = &settingsCopy
+	}
+	cc.NetworkSettings = &types.SummaryNetworkSettings{Networks: networks}
+	return cc
+}
+
+// GetContainers returns all of the containers.
+func (r *ContainerRuntime) GetContainers(ctx context.Context) (docker.ContainerList, error) {
+	return r.list(func(c *types.Container) bool {
+		return true
+	}), nil
+}
+
+// GetContainersByLabel returns all of the containers that has the label.
+func (r *ContainerRuntime) GetContainersByLabel(ctx context.Context, name, value string) (docker.ContainerList, error) {
+	return r.list(func(c *types.Container) bool {
+		return c.Labels[name] == value
+	}), nil
+}
+
+// GetzktoroServiceContainers returns all of the non-agent zktoro containers.
+func (r *ContainerRuntime) GetzktoroServiceContainers(ctx context.Context) (docker.ContainerList, error) {
+	return r.list(func(c *types.Container) bool {
+		return !strings.Contains(docker.GetContainerName(*c), "zktoro-agent")
+	}), nil
+}
+
+// GetContainerByName gets a container by name.
+func (r *ContainerRuntime) GetContainerByName(ctx context.Context, name string) (*types.Container, error) {
+	containers, _ := r.GetContainers(ctx)
+	c, ok := containers.FindByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%w with name '%s'", docker.ErrContainerNotFound, name)
+	}
+	return c, nil
+}
+
+// GetContainerByID gets a container by ID.
+func (r *ContainerRuntime) GetContainerByID(ctx context.Context, id string) (*types.Container, error) {
+	containers, _ := r.GetContainers(ctx)
+	c, ok := containers.FindByID(id)
+	if !ok {
+		return nil, fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, id)
+	}
+	return c, nil
+}
+
+// InspectContainer returns container details.
+func (r *ContainerRuntime) InspectContainer(ctx context.Context, id string) (*types.ContainerJSON, error) {
+	c, err := r.GetContainerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    c.ID,
+			Name:  c.Names[0],
+			Image: c.ImageID,
+			State: &types.ContainerState{
+				Status:  c.State,
+				Running: c.State == StateRunning,
+			},
+		},
+		Config: &container.Config{
+			Image:  c.Image,
+			Labels: c.Labels,
+		},
+		NetworkSettings: &types.NetworkSettings{Networks: c.NetworkSettings.Networks},
+	}, nil
+}
+
+// StartContainerWithID starts an existing container.
+func (r *ContainerRuntime) StartContainerWithID(ctx context.Context, containerID string) error {
+	return r.setState(containerID, StateRunning)
+}
+
+// StartContainer creates and starts a container or starts the existing one with the same name.
+func (r *ContainerRuntime) StartContainer(ctx context.Context, containerCfg docker.ContainerConfig) (*docker.Container, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range r.containers {
+		if docker.GetContainerName(c.container) == containerCfg.Name {
+			c.container.State = StateRunning
+			return &docker.Container{Name: containerCfg.Name, ID: c.container.ID, Config: containerCfg, ImageHash: c.container.ImageID}, nil
+		}
+	}
+
+	if !r.images[containerCfg.Image] {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, containerCfg.Image)
+	}
+	labels := map[string]string{docker.Labelzktoro: "true"}
+	for k, v := range containerCfg.Labels {
+		labels[k] = v
+	}
+	c := &fakeContainer{
+		container: types.Container{
+			ID:      r.nextID("container"),
+			Names:   []string{"/" + containerCfg.Name},
+			Image:   containerCfg.Image,
+			ImageID: "sha256:" + containerCfg.Image,
+			Labels:  labels,
+			State:   StateRunning,
+			Created: time.Now().Unix(),
+			NetworkSettings: &types.SummaryNetworkSettings{
+				Networks: make(map[string]*network.EndpointSettings),
+			},
+		},
+		config: containerCfg,
+	}
+	r.containers[c.container.ID] = c
+	for _, nwID := range append([]string{containerCfg.NetworkID}, containerCfg.LinkNetworkIDs...) {
+		if len(nwID) == 0 {
+			continue
+		}
+		if err := r.attachNetwork(c.container.ID, nwID); err != nil {
+			delete(r.containers, c.container.ID)
+			return nil, err
+		}
+	}
+	return &docker.Container{Name: containerCfg.Name, ID: c.container.ID, Config: containerCfg, ImageHash: c.container.ImageID}, nil
+}
+
+func (r *ContainerRuntime) setState(id, state string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.containers[id]
+	if !ok {
+		return fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, id)
+	}
+	c.container.State = state
+	return nil
+}
+
+func (r *ContainerRuntime) stopContainer(id string) error {
+	err := r.setState(id, StateExited)
+	if errors.Is(err, docker.ErrContainerNotFound) {
+		return nil
+	}
+	return err
+}
+
+// StopContainer stops a container.
+func (r *ContainerRuntime) StopContainer(ctx context.Context, id string) error {
+	return r.stopContainer(id)
+}
+
+// InterruptContainer stops a container.
+func (r *ContainerRuntime) InterruptContainer(ctx context.Context, id string) error {
+	return r.stopContainer(id)
+}
+
+// TerminateContainer stops a container.
+func (r *ContainerRuntime) TerminateContainer(ctx context.Context, id string) error {
+	return r.stopContainer(id)
+}
+
+// ShutdownContainer stops a container.
+func (r *ContainerRuntime) ShutdownContainer(ctx context.Context, id string, timeout *time.Duration) error {
+	return r.setState(id, StateExited)
+}
+
+// RemoveContainer removes a container.
+func (r *ContainerRuntime) RemoveContainer(ctx context.Context, containerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.containers[containerID]; !ok {
+		return fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, containerID)
+	}
+	delete(r.containers, containerID)
+	return nil
+}
+
+// WaitContainerExit returns when the container is not running.
+func (r *ContainerRuntime) WaitContainerExit(ctx context.Context, id string) error {
+	c, err := r.GetContainerByID(ctx, id)
+	if errors.Is(err, docker.ErrContainerNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if c.State == StateRunning {
+		return fmt.Errorf("container %s is still running", id)
+	}
+	return nil
+}
+
+// WaitContainerStart returns when the container is running.
+func (r *ContainerRuntime) WaitContainerStart(ctx context.Context, id string) error {
+	c, err := r.GetContainerByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.State != StateRunning {
+		return errors.New("container did not start")
+	}
+	return nil
+}
+
+// Prune removes the containers which are not running and the unused networks.
+func (r *ContainerRuntime) Prune(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used := make(map[string]bool)
+	for id, c := range r.containers {
+		if c.container.State != StateRunning {
+			delete(r.containers, id)
+			continue
+		}
+		for name := range c.container.NetworkSettings.Networks {
+			used[name] = true
+		}
+	}
+	for name := range r.networks {
+		if !used[name] {
+			delete(r.networks, name)
+		}
+	}
+	return nil
+}
+
+// WaitContainerPrune returns when the container is removed.
+func (r *ContainerRuntime) WaitContainerPrune(ctx context.Context, id string) error {
+	if _, err := r.GetContainerByID(ctx, id); err == nil {
+		return fmt.Errorf("container %s is not pruned", id)
+	}
+	return nil
+}
+
+// Nuke stops and removes all containers.
+func (r *ContainerRuntime) Nuke(ctx context.Context) error {
+	containers, _ := r.GetContainers(ctx)
+	for _, c := range containers {
+		if err := r.stopContainer(c.ID); err != nil {
+			return err
+		}
+	}
+	return r.Prune(ctx)
+}
+
+// GetContainerLogs returns the logs set for the container.
+func (r *ContainerRuntime) GetContainerLogs(ctx context.Context, containerID, tail string, truncate int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.containers[containerID]
+	if !ok {
+		return "", fmt.Errorf("%w with id '%s'", docker.ErrContainerNotFound, containerID)
+	}
+	logs := c.logs
+	if truncate >= 0 && len(logs) > truncate {
+		logs = logs[:truncate]
+	}
+	return logs, nil
+}
+
+// GetContainerFromRemoteAddr finds the container which has the IP address of the remote address.
+func (r *ContainerRuntime) GetContainerFromRemoteAddr(ctx context.Context, hostPort string) (*types.Container, error) {
+	containers, _ := r.GetContainers(ctx)
+	return containers.FindByRemoteAddr(hostPort)
+}
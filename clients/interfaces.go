@@ -13,15 +13,27 @@ import (
 	"zktoro/config"
 )
 
-// DockerClient is a client interface for interacting with docker
-type DockerClient interface {
+// ImageManager manages the container images.
+type ImageManager interface {
 	PullImage(ctx context.Context, refStr string) error
 	RemoveImage(ctx context.Context, refStr string) error
+	HasLocalImage(ctx context.Context, ref string) (bool, error)
+	EnsureLocalImage(ctx context.Context, name, ref string) error
+	EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) []error
+	SetImagePullCooldown(threshold int, cooldownDuration time.Duration)
+}
+
+// NetworkManager manages the container networks.
+type NetworkManager interface {
 	EnsurePublicNetwork(ctx context.Context, name string) (string, error)
 	EnsureInternalNetwork(ctx context.Context, name string) (string, error)
 	AttachNetwork(ctx context.Context, containerID string, networkID string) error
 	DetachNetwork(ctx context.Context, containerID string, networkID string) error
 	RemoveNetworkByName(ctx context.Context, networkName string) error
+}
+
+// ContainerManager manages the containers.
+type ContainerManager interface {
 	GetContainers(ctx context.Context) (docker.ContainerList, error)
 	GetContainersByLabel(ctx context.Context, name, value string) (docker.ContainerList, error)
 	GetzktoroServiceContainers(ctx context.Context) (zktoroContainers docker.ContainerList, err error)
@@ -40,12 +52,16 @@ type DockerClient interface {
 	Prune(ctx context.Context) error
 	WaitContainerPrune(ctx context.Context, id string) error
 	Nuke(ctx context.Context) error
-	HasLocalImage(ctx context.Context, ref string) (bool, error)
-	EnsureLocalImage(ctx context.Context, name, ref string) error
-	EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) []error
 	GetContainerLogs(ctx context.Context, containerID, tail string, truncate int) (string, error)
 	GetContainerFromRemoteAddr(ctx context.Context, hostPort string) (*types.Container, error)
-	SetImagePullCooldown(threshold int, cooldownDuration time.Duration)
+}
+
+// ContainerRuntime runs the node service and the bot containers. The runtimes describe
+// the containers with the Docker API types which Podman and nerdctl also use.
+type ContainerRuntime interface {
+	ImageManager
+	NetworkManager
+	ContainerManager
 }
 
 // MessageClient receives and publishes messages.
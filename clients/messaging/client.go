@@ -50,12 +50,28 @@ func NewClient(name, natsURL string) *Client {
 	return client
 }
 
+// DialClient tries connecting to the NATS server once and fails instead of panicking, for the
+// clients outside of the node network.
+func DialClient(name, natsURL string) (*Client, error) {
+	logger := log.WithField("name", fmt.Sprintf("%s/messaging", name)).WithField("nats", natsURL)
+	nc, err := nats.Connect(natsURL)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("successfully connected")
+	return &Client{
+		logger: logger,
+		nc:     nc,
+	}, nil
+}
+
 // AgentsHandler handles agents.* subjects.
 type AgentsHandler func(AgentPayload) error
 type SubscriptionHandler func(SubscriptionPayload) error
 type AgentMetricHandler func(*protocol.AgentMetricList) error
 type InspectionResultsHandler func(results *protocol.InspectionResults) error
 type ScannerHandler func(ScannerPayload) error
+type ConfigChangedHandler func(ConfigChangedPayload) error
 
 // Subscribe subscribes the consumer to this client.
 func (client *Client) Subscribe(subject string, handler interface{}) {
@@ -105,6 +121,14 @@ func (client *Client) Subscribe(subject string, handler interface{}) {
 			}
 			err = h(payload)
 
+		case ConfigChangedHandler:
+			var payload ConfigChangedPayload
+			err = json.Unmarshal(m.Data, &payload)
+			if err != nil {
+				break
+			}
+			err = h(payload)
+
 		default:
 			logger.Panicf("no handler found")
 		}
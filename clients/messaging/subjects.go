@@ -19,6 +19,7 @@ const (
 	SubjectScannerBlock           = "scanner.block"
 	SubjectScannerAlert           = "scanner.alert"
 	SubjectInspectionDone         = "inspection.done"
+	SubjectConfigChanged          = "config.changed"
 )
 
 // AgentPayload is the message payload.
@@ -34,3 +35,6 @@ type SubscriptionPayload []*domain.CombinerBotSubscription
 type ScannerPayload struct {
 	LatestBlockInput uint64 `json:"latestBlockInput"`
 }
+
+// ConfigChangedPayload is the message payload for the config file changes.
+type ConfigChangedPayload = config.ConfigChanges
@@ -17,31 +17,31 @@ import (
 	proto "google.golang.org/protobuf/proto"
 )
 
-// MockDockerClient is a mock of DockerClient interface.
-type MockDockerClient struct {
+// MockContainerRuntime is a mock of ContainerRuntime interface.
+type MockContainerRuntime struct {
 	ctrl     *gomock.Controller
-	recorder *MockDockerClientMockRecorder
+	recorder *MockContainerRuntimeMockRecorder
 }
 
-// MockDockerClientMockRecorder is the mock recorder for MockDockerClient.
-type MockDockerClientMockRecorder struct {
-	mock *MockDockerClient
+// MockContainerRuntimeMockRecorder is the mock recorder for MockContainerRuntime.
+type MockContainerRuntimeMockRecorder struct {
+	mock *MockContainerRuntime
 }
 
-// NewMockDockerClient creates a new mock instance.
-func NewMockDockerClient(ctrl *gomock.Controller) *MockDockerClient {
-	mock := &MockDockerClient{ctrl: ctrl}
-	mock.recorder = &MockDockerClientMockRecorder{mock}
+// NewMockContainerRuntime creates a new mock instance.
+func NewMockContainerRuntime(ctrl *gomock.Controller) *MockContainerRuntime {
+	mock := &MockContainerRuntime{ctrl: ctrl}
+	mock.recorder = &MockContainerRuntimeMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockDockerClient) EXPECT() *MockDockerClientMockRecorder {
+func (m *MockContainerRuntime) EXPECT() *MockContainerRuntimeMockRecorder {
 	return m.recorder
 }
 
 // AttachNetwork mocks base method.
-func (m *MockDockerClient) AttachNetwork(ctx context.Context, containerID, networkID string) error {
+func (m *MockContainerRuntime) AttachNetwork(ctx context.Context, containerID, networkID string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AttachNetwork", ctx, containerID, networkID)
 	ret0, _ := ret[0].(error)
@@ -49,13 +49,13 @@ func (m *MockDockerClient) AttachNetwork(ctx context.Context, containerID, netwo
 }
 
 // AttachNetwork indicates an expected call of AttachNetwork.
-func (mr *MockDockerClientMockRecorder) AttachNetwork(ctx, containerID, networkID interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) AttachNetwork(ctx, containerID, networkID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachNetwork", reflect.TypeOf((*MockDockerClient)(nil).AttachNetwork), ctx, containerID, networkID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachNetwork", reflect.TypeOf((*MockContainerRuntime)(nil).AttachNetwork), ctx, containerID, networkID)
 }
 
 // DetachNetwork mocks base method.
-func (m *MockDockerClient) DetachNetwork(ctx context.Context, containerID, networkID string) error {
+func (m *MockContainerRuntime) DetachNetwork(ctx context.Context, containerID, networkID string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DetachNetwork", ctx, containerID, networkID)
 	ret0, _ := ret[0].(error)
@@ -63,13 +63,13 @@ func (m *MockDockerClient) DetachNetwork(ctx context.Context, containerID, netwo
 }
 
 // DetachNetwork indicates an expected call of DetachNetwork.
-func (mr *MockDockerClientMockRecorder) DetachNetwork(ctx, containerID, networkID interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) DetachNetwork(ctx, containerID, networkID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachNetwork", reflect.TypeOf((*MockDockerClient)(nil).DetachNetwork), ctx, containerID, networkID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachNetwork", reflect.TypeOf((*MockContainerRuntime)(nil).DetachNetwork), ctx, containerID, networkID)
 }
 
 // EnsureInternalNetwork mocks base method.
-func (m *MockDockerClient) EnsureInternalNetwork(ctx context.Context, name string) (string, error) {
+func (m *MockContainerRuntime) EnsureInternalNetwork(ctx context.Context, name string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EnsureInternalNetwork", ctx, name)
 	ret0, _ := ret[0].(string)
@@ -78,13 +78,13 @@ func (m *MockDockerClient) EnsureInternalNetwork(ctx context.Context, name strin
 }
 
 // EnsureInternalNetwork indicates an expected call of EnsureInternalNetwork.
-func (mr *MockDockerClientMockRecorder) EnsureInternalNetwork(ctx, name interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) EnsureInternalNetwork(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureInternalNetwork", reflect.TypeOf((*MockDockerClient)(nil).EnsureInternalNetwork), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureInternalNetwork", reflect.TypeOf((*MockContainerRuntime)(nil).EnsureInternalNetwork), ctx, name)
 }
 
 // EnsureLocalImage mocks base method.
-func (m *MockDockerClient) EnsureLocalImage(ctx context.Context, name, ref string) error {
+func (m *MockContainerRuntime) EnsureLocalImage(ctx context.Context, name, ref string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EnsureLocalImage", ctx, name, ref)
 	ret0, _ := ret[0].(error)
@@ -92,13 +92,13 @@ func (m *MockDockerClient) EnsureLocalImage(ctx context.Context, name, ref strin
 }
 
 // EnsureLocalImage indicates an expected call of EnsureLocalImage.
-func (mr *MockDockerClientMockRecorder) EnsureLocalImage(ctx, name, ref interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) EnsureLocalImage(ctx, name, ref interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureLocalImage", reflect.TypeOf((*MockDockerClient)(nil).EnsureLocalImage), ctx, name, ref)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureLocalImage", reflect.TypeOf((*MockContainerRuntime)(nil).EnsureLocalImage), ctx, name, ref)
 }
 
 // EnsureLocalImages mocks base method.
-func (m *MockDockerClient) EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) []error {
+func (m *MockContainerRuntime) EnsureLocalImages(ctx context.Context, timeoutPerPull time.Duration, imagePulls []docker.ImagePull) []error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EnsureLocalImages", ctx, timeoutPerPull, imagePulls)
 	ret0, _ := ret[0].([]error)
@@ -106,13 +106,13 @@ func (m *MockDockerClient) EnsureLocalImages(ctx context.Context, timeoutPerPull
 }
 
 // EnsureLocalImages indicates an expected call of EnsureLocalImages.
-func (mr *MockDockerClientMockRecorder) EnsureLocalImages(ctx, timeoutPerPull, imagePulls interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) EnsureLocalImages(ctx, timeoutPerPull, imagePulls interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureLocalImages", reflect.TypeOf((*MockDockerClient)(nil).EnsureLocalImages), ctx, timeoutPerPull, imagePulls)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureLocalImages", reflect.TypeOf((*MockContainerRuntime)(nil).EnsureLocalImages), ctx, timeoutPerPull, imagePulls)
 }
 
 // EnsurePublicNetwork mocks base method.
-func (m *MockDockerClient) EnsurePublicNetwork(ctx context.Context, name string) (string, error) {
+func (m *MockContainerRuntime) EnsurePublicNetwork(ctx context.Context, name string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EnsurePublicNetwork", ctx, name)
 	ret0, _ := ret[0].(string)
@@ -121,13 +121,13 @@ func (m *MockDockerClient) EnsurePublicNetwork(ctx context.Context, name string)
 }
 
 // EnsurePublicNetwork indicates an expected call of EnsurePublicNetwork.
-func (mr *MockDockerClientMockRecorder) EnsurePublicNetwork(ctx, name interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) EnsurePublicNetwork(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsurePublicNetwork", reflect.TypeOf((*MockDockerClient)(nil).EnsurePublicNetwork), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsurePublicNetwork", reflect.TypeOf((*MockContainerRuntime)(nil).EnsurePublicNetwork), ctx, name)
 }
 
 // GetContainerByID mocks base method.
-func (m *MockDockerClient) GetContainerByID(ctx context.Context, id string) (*types.Container, error) {
+func (m *MockContainerRuntime) GetContainerByID(ctx context.Context, id string) (*types.Container, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainerByID", ctx, id)
 	ret0, _ := ret[0].(*types.Container)
@@ -136,13 +136,13 @@ func (m *MockDockerClient) GetContainerByID(ctx context.Context, id string) (*ty
 }
 
 // GetContainerByID indicates an expected call of GetContainerByID.
-func (mr *MockDockerClientMockRecorder) GetContainerByID(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainerByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerByID", reflect.TypeOf((*MockDockerClient)(nil).GetContainerByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerByID", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerByID), ctx, id)
 }
 
 // GetContainerByName mocks base method.
-func (m *MockDockerClient) GetContainerByName(ctx context.Context, name string) (*types.Container, error) {
+func (m *MockContainerRuntime) GetContainerByName(ctx context.Context, name string) (*types.Container, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainerByName", ctx, name)
 	ret0, _ := ret[0].(*types.Container)
@@ -151,13 +151,13 @@ func (m *MockDockerClient) GetContainerByName(ctx context.Context, name string)
 }
 
 // GetContainerByName indicates an expected call of GetContainerByName.
-func (mr *MockDockerClientMockRecorder) GetContainerByName(ctx, name interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainerByName(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerByName", reflect.TypeOf((*MockDockerClient)(nil).GetContainerByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerByName", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerByName), ctx, name)
 }
 
 // GetContainerFromRemoteAddr mocks base method.
-func (m *MockDockerClient) GetContainerFromRemoteAddr(ctx context.Context, hostPort string) (*types.Container, error) {
+func (m *MockContainerRuntime) GetContainerFromRemoteAddr(ctx context.Context, hostPort string) (*types.Container, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainerFromRemoteAddr", ctx, hostPort)
 	ret0, _ := ret[0].(*types.Container)
@@ -166,13 +166,13 @@ func (m *MockDockerClient) GetContainerFromRemoteAddr(ctx context.Context, hostP
 }
 
 // GetContainerFromRemoteAddr indicates an expected call of GetContainerFromRemoteAddr.
-func (mr *MockDockerClientMockRecorder) GetContainerFromRemoteAddr(ctx, hostPort interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainerFromRemoteAddr(ctx, hostPort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerFromRemoteAddr", reflect.TypeOf((*MockDockerClient)(nil).GetContainerFromRemoteAddr), ctx, hostPort)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerFromRemoteAddr", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerFromRemoteAddr), ctx, hostPort)
 }
 
 // GetContainerLogs mocks base method.
-func (m *MockDockerClient) GetContainerLogs(ctx context.Context, containerID, tail string, truncate int) (string, error) {
+func (m *MockContainerRuntime) GetContainerLogs(ctx context.Context, containerID, tail string, truncate int) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainerLogs", ctx, containerID, tail, truncate)
 	ret0, _ := ret[0].(string)
@@ -181,13 +181,13 @@ func (m *MockDockerClient) GetContainerLogs(ctx context.Context, containerID, ta
 }
 
 // GetContainerLogs indicates an expected call of GetContainerLogs.
-func (mr *MockDockerClientMockRecorder) GetContainerLogs(ctx, containerID, tail, truncate interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainerLogs(ctx, containerID, tail, truncate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerLogs", reflect.TypeOf((*MockDockerClient)(nil).GetContainerLogs), ctx, containerID, tail, truncate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerLogs", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerLogs), ctx, containerID, tail, truncate)
 }
 
 // GetContainers mocks base method.
-func (m *MockDockerClient) GetContainers(ctx context.Context) (docker.ContainerList, error) {
+func (m *MockContainerRuntime) GetContainers(ctx context.Context) (docker.ContainerList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainers", ctx)
 	ret0, _ := ret[0].(docker.ContainerList)
@@ -196,13 +196,13 @@ func (m *MockDockerClient) GetContainers(ctx context.Context) (docker.ContainerL
 }
 
 // GetContainers indicates an expected call of GetContainers.
-func (mr *MockDockerClientMockRecorder) GetContainers(ctx interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainers(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainers", reflect.TypeOf((*MockDockerClient)(nil).GetContainers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainers", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainers), ctx)
 }
 
 // GetContainersByLabel mocks base method.
-func (m *MockDockerClient) GetContainersByLabel(ctx context.Context, name, value string) (docker.ContainerList, error) {
+func (m *MockContainerRuntime) GetContainersByLabel(ctx context.Context, name, value string) (docker.ContainerList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetContainersByLabel", ctx, name, value)
 	ret0, _ := ret[0].(docker.ContainerList)
@@ -211,13 +211,13 @@ func (m *MockDockerClient) GetContainersByLabel(ctx context.Context, name, value
 }
 
 // GetContainersByLabel indicates an expected call of GetContainersByLabel.
-func (mr *MockDockerClientMockRecorder) GetContainersByLabel(ctx, name, value interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetContainersByLabel(ctx, name, value interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainersByLabel", reflect.TypeOf((*MockDockerClient)(nil).GetContainersByLabel), ctx, name, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainersByLabel", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainersByLabel), ctx, name, value)
 }
 
 // GetzktoroServiceContainers mocks base method.
-func (m *MockDockerClient) GetzktoroServiceContainers(ctx context.Context) (docker.ContainerList, error) {
+func (m *MockContainerRuntime) GetzktoroServiceContainers(ctx context.Context) (docker.ContainerList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetzktoroServiceContainers", ctx)
 	ret0, _ := ret[0].(docker.ContainerList)
@@ -226,13 +226,13 @@ func (m *MockDockerClient) GetzktoroServiceContainers(ctx context.Context) (dock
 }
 
 // GetzktoroServiceContainers indicates an expected call of GetzktoroServiceContainers.
-func (mr *MockDockerClientMockRecorder) GetzktoroServiceContainers(ctx interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) GetzktoroServiceContainers(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetzktoroServiceContainers", reflect.TypeOf((*MockDockerClient)(nil).GetzktoroServiceContainers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetzktoroServiceContainers", reflect.TypeOf((*MockContainerRuntime)(nil).GetzktoroServiceContainers), ctx)
 }
 
 // HasLocalImage mocks base method.
-func (m *MockDockerClient) HasLocalImage(ctx context.Context, ref string) (bool, error) {
+func (m *MockContainerRuntime) HasLocalImage(ctx context.Context, ref string) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HasLocalImage", ctx, ref)
 	ret0, _ := ret[0].(bool)
@@ -241,13 +241,13 @@ func (m *MockDockerClient) HasLocalImage(ctx context.Context, ref string) (bool,
 }
 
 // HasLocalImage indicates an expected call of HasLocalImage.
-func (mr *MockDockerClientMockRecorder) HasLocalImage(ctx, ref interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) HasLocalImage(ctx, ref interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLocalImage", reflect.TypeOf((*MockDockerClient)(nil).HasLocalImage), ctx, ref)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLocalImage", reflect.TypeOf((*MockContainerRuntime)(nil).HasLocalImage), ctx, ref)
 }
 
 // InspectContainer mocks base method.
-func (m *MockDockerClient) InspectContainer(ctx context.Context, id string) (*types.ContainerJSON, error) {
+func (m *MockContainerRuntime) InspectContainer(ctx context.Context, id string) (*types.ContainerJSON, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InspectContainer", ctx, id)
 	ret0, _ := ret[0].(*types.ContainerJSON)
@@ -256,13 +256,13 @@ func (m *MockDockerClient) InspectContainer(ctx context.Context, id string) (*ty
 }
 
 // InspectContainer indicates an expected call of InspectContainer.
-func (mr *MockDockerClientMockRecorder) InspectContainer(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) InspectContainer(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectContainer", reflect.TypeOf((*MockDockerClient)(nil).InspectContainer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectContainer", reflect.TypeOf((*MockContainerRuntime)(nil).InspectContainer), ctx, id)
 }
 
 // InterruptContainer mocks base method.
-func (m *MockDockerClient) InterruptContainer(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) InterruptContainer(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InterruptContainer", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -270,13 +270,13 @@ func (m *MockDockerClient) InterruptContainer(ctx context.Context, id string) er
 }
 
 // InterruptContainer indicates an expected call of InterruptContainer.
-func (mr *MockDockerClientMockRecorder) InterruptContainer(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) InterruptContainer(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterruptContainer", reflect.TypeOf((*MockDockerClient)(nil).InterruptContainer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InterruptContainer", reflect.TypeOf((*MockContainerRuntime)(nil).InterruptContainer), ctx, id)
 }
 
 // Nuke mocks base method.
-func (m *MockDockerClient) Nuke(ctx context.Context) error {
+func (m *MockContainerRuntime) Nuke(ctx context.Context) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Nuke", ctx)
 	ret0, _ := ret[0].(error)
@@ -284,13 +284,13 @@ func (m *MockDockerClient) Nuke(ctx context.Context) error {
 }
 
 // Nuke indicates an expected call of Nuke.
-func (mr *MockDockerClientMockRecorder) Nuke(ctx interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) Nuke(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nuke", reflect.TypeOf((*MockDockerClient)(nil).Nuke), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nuke", reflect.TypeOf((*MockContainerRuntime)(nil).Nuke), ctx)
 }
 
 // Prune mocks base method.
-func (m *MockDockerClient) Prune(ctx context.Context) error {
+func (m *MockContainerRuntime) Prune(ctx context.Context) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Prune", ctx)
 	ret0, _ := ret[0].(error)
@@ -298,13 +298,13 @@ func (m *MockDockerClient) Prune(ctx context.Context) error {
 }
 
 // Prune indicates an expected call of Prune.
-func (mr *MockDockerClientMockRecorder) Prune(ctx interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) Prune(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockDockerClient)(nil).Prune), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockContainerRuntime)(nil).Prune), ctx)
 }
 
 // PullImage mocks base method.
-func (m *MockDockerClient) PullImage(ctx context.Context, refStr string) error {
+func (m *MockContainerRuntime) PullImage(ctx context.Context, refStr string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PullImage", ctx, refStr)
 	ret0, _ := ret[0].(error)
@@ -312,13 +312,13 @@ func (m *MockDockerClient) PullImage(ctx context.Context, refStr string) error {
 }
 
 // PullImage indicates an expected call of PullImage.
-func (mr *MockDockerClientMockRecorder) PullImage(ctx, refStr interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) PullImage(ctx, refStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockDockerClient)(nil).PullImage), ctx, refStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullImage", reflect.TypeOf((*MockContainerRuntime)(nil).PullImage), ctx, refStr)
 }
 
 // RemoveContainer mocks base method.
-func (m *MockDockerClient) RemoveContainer(ctx context.Context, containerID string) error {
+func (m *MockContainerRuntime) RemoveContainer(ctx context.Context, containerID string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RemoveContainer", ctx, containerID)
 	ret0, _ := ret[0].(error)
@@ -326,13 +326,13 @@ func (m *MockDockerClient) RemoveContainer(ctx context.Context, containerID stri
 }
 
 // RemoveContainer indicates an expected call of RemoveContainer.
-func (mr *MockDockerClientMockRecorder) RemoveContainer(ctx, containerID interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) RemoveContainer(ctx, containerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockDockerClient)(nil).RemoveContainer), ctx, containerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockContainerRuntime)(nil).RemoveContainer), ctx, containerID)
 }
 
 // RemoveImage mocks base method.
-func (m *MockDockerClient) RemoveImage(ctx context.Context, refStr string) error {
+func (m *MockContainerRuntime) RemoveImage(ctx context.Context, refStr string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RemoveImage", ctx, refStr)
 	ret0, _ := ret[0].(error)
@@ -340,13 +340,13 @@ func (m *MockDockerClient) RemoveImage(ctx context.Context, refStr string) error
 }
 
 // RemoveImage indicates an expected call of RemoveImage.
-func (mr *MockDockerClientMockRecorder) RemoveImage(ctx, refStr interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) RemoveImage(ctx, refStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveImage", reflect.TypeOf((*MockDockerClient)(nil).RemoveImage), ctx, refStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveImage", reflect.TypeOf((*MockContainerRuntime)(nil).RemoveImage), ctx, refStr)
 }
 
 // RemoveNetworkByName mocks base method.
-func (m *MockDockerClient) RemoveNetworkByName(ctx context.Context, networkName string) error {
+func (m *MockContainerRuntime) RemoveNetworkByName(ctx context.Context, networkName string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RemoveNetworkByName", ctx, networkName)
 	ret0, _ := ret[0].(error)
@@ -354,25 +354,25 @@ func (m *MockDockerClient) RemoveNetworkByName(ctx context.Context, networkName
 }
 
 // RemoveNetworkByName indicates an expected call of RemoveNetworkByName.
-func (mr *MockDockerClientMockRecorder) RemoveNetworkByName(ctx, networkName interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) RemoveNetworkByName(ctx, networkName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkByName", reflect.TypeOf((*MockDockerClient)(nil).RemoveNetworkByName), ctx, networkName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworkByName", reflect.TypeOf((*MockContainerRuntime)(nil).RemoveNetworkByName), ctx, networkName)
 }
 
 // SetImagePullCooldown mocks base method.
-func (m *MockDockerClient) SetImagePullCooldown(threshold int, cooldownDuration time.Duration) {
+func (m *MockContainerRuntime) SetImagePullCooldown(threshold int, cooldownDuration time.Duration) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SetImagePullCooldown", threshold, cooldownDuration)
 }
 
 // SetImagePullCooldown indicates an expected call of SetImagePullCooldown.
-func (mr *MockDockerClientMockRecorder) SetImagePullCooldown(threshold, cooldownDuration interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) SetImagePullCooldown(threshold, cooldownDuration interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImagePullCooldown", reflect.TypeOf((*MockDockerClient)(nil).SetImagePullCooldown), threshold, cooldownDuration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImagePullCooldown", reflect.TypeOf((*MockContainerRuntime)(nil).SetImagePullCooldown), threshold, cooldownDuration)
 }
 
 // ShutdownContainer mocks base method.
-func (m *MockDockerClient) ShutdownContainer(ctx context.Context, id string, timeout *time.Duration) error {
+func (m *MockContainerRuntime) ShutdownContainer(ctx context.Context, id string, timeout *time.Duration) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ShutdownContainer", ctx, id, timeout)
 	ret0, _ := ret[0].(error)
@@ -380,13 +380,13 @@ func (m *MockDockerClient) ShutdownContainer(ctx context.Context, id string, tim
 }
 
 // ShutdownContainer indicates an expected call of ShutdownContainer.
-func (mr *MockDockerClientMockRecorder) ShutdownContainer(ctx, id, timeout interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) ShutdownContainer(ctx, id, timeout interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutdownContainer", reflect.TypeOf((*MockDockerClient)(nil).ShutdownContainer), ctx, id, timeout)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutdownContainer", reflect.TypeOf((*MockContainerRuntime)(nil).ShutdownContainer), ctx, id, timeout)
 }
 
 // StartContainer mocks base method.
-func (m *MockDockerClient) StartContainer(ctx context.Context, config docker.ContainerConfig) (*docker.Container, error) {
+func (m *MockContainerRuntime) StartContainer(ctx context.Context, config docker.ContainerConfig) (*docker.Container, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "StartContainer", ctx, config)
 	ret0, _ := ret[0].(*docker.Container)
@@ -395,13 +395,13 @@ func (m *MockDockerClient) StartContainer(ctx context.Context, config docker.Con
 }
 
 // StartContainer indicates an expected call of StartContainer.
-func (mr *MockDockerClientMockRecorder) StartContainer(ctx, config interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) StartContainer(ctx, config interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockDockerClient)(nil).StartContainer), ctx, config)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockContainerRuntime)(nil).StartContainer), ctx, config)
 }
 
 // StartContainerWithID mocks base method.
-func (m *MockDockerClient) StartContainerWithID(ctx context.Context, containerID string) error {
+func (m *MockContainerRuntime) StartContainerWithID(ctx context.Context, containerID string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "StartContainerWithID", ctx, containerID)
 	ret0, _ := ret[0].(error)
@@ -409,13 +409,13 @@ func (m *MockDockerClient) StartContainerWithID(ctx context.Context, containerID
 }
 
 // StartContainerWithID indicates an expected call of StartContainerWithID.
-func (mr *MockDockerClientMockRecorder) StartContainerWithID(ctx, containerID interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) StartContainerWithID(ctx, containerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainerWithID", reflect.TypeOf((*MockDockerClient)(nil).StartContainerWithID), ctx, containerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainerWithID", reflect.TypeOf((*MockContainerRuntime)(nil).StartContainerWithID), ctx, containerID)
 }
 
 // StopContainer mocks base method.
-func (m *MockDockerClient) StopContainer(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) StopContainer(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "StopContainer", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -423,13 +423,13 @@ func (m *MockDockerClient) StopContainer(ctx context.Context, id string) error {
 }
 
 // StopContainer indicates an expected call of StopContainer.
-func (mr *MockDockerClientMockRecorder) StopContainer(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) StopContainer(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockDockerClient)(nil).StopContainer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerRuntime)(nil).StopContainer), ctx, id)
 }
 
 // TerminateContainer mocks base method.
-func (m *MockDockerClient) TerminateContainer(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) TerminateContainer(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "TerminateContainer", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -437,13 +437,13 @@ func (m *MockDockerClient) TerminateContainer(ctx context.Context, id string) er
 }
 
 // TerminateContainer indicates an expected call of TerminateContainer.
-func (mr *MockDockerClientMockRecorder) TerminateContainer(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) TerminateContainer(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateContainer", reflect.TypeOf((*MockDockerClient)(nil).TerminateContainer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateContainer", reflect.TypeOf((*MockContainerRuntime)(nil).TerminateContainer), ctx, id)
 }
 
 // WaitContainerExit mocks base method.
-func (m *MockDockerClient) WaitContainerExit(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) WaitContainerExit(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WaitContainerExit", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -451,13 +451,13 @@ func (m *MockDockerClient) WaitContainerExit(ctx context.Context, id string) err
 }
 
 // WaitContainerExit indicates an expected call of WaitContainerExit.
-func (mr *MockDockerClientMockRecorder) WaitContainerExit(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) WaitContainerExit(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerExit", reflect.TypeOf((*MockDockerClient)(nil).WaitContainerExit), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerExit", reflect.TypeOf((*MockContainerRuntime)(nil).WaitContainerExit), ctx, id)
 }
 
 // WaitContainerPrune mocks base method.
-func (m *MockDockerClient) WaitContainerPrune(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) WaitContainerPrune(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WaitContainerPrune", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -465,13 +465,13 @@ func (m *MockDockerClient) WaitContainerPrune(ctx context.Context, id string) er
 }
 
 // WaitContainerPrune indicates an expected call of WaitContainerPrune.
-func (mr *MockDockerClientMockRecorder) WaitContainerPrune(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) WaitContainerPrune(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerPrune", reflect.TypeOf((*MockDockerClient)(nil).WaitContainerPrune), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerPrune", reflect.TypeOf((*MockContainerRuntime)(nil).WaitContainerPrune), ctx, id)
 }
 
 // WaitContainerStart mocks base method.
-func (m *MockDockerClient) WaitContainerStart(ctx context.Context, id string) error {
+func (m *MockContainerRuntime) WaitContainerStart(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WaitContainerStart", ctx, id)
 	ret0, _ := ret[0].(error)
@@ -479,9 +479,9 @@ func (m *MockDockerClient) WaitContainerStart(ctx context.Context, id string) er
 }
 
 // WaitContainerStart indicates an expected call of WaitContainerStart.
-func (mr *MockDockerClientMockRecorder) WaitContainerStart(ctx, id interface{}) *gomock.Call {
+func (mr *MockContainerRuntimeMockRecorder) WaitContainerStart(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerStart", reflect.TypeOf((*MockDockerClient)(nil).WaitContainerStart), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitContainerStart", reflect.TypeOf((*MockContainerRuntime)(nil).WaitContainerStart), ctx, id)
 }
 
 // MockMessageClient is a mock of MessageClient interface.
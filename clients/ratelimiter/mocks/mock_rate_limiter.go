@@ -46,3 +46,15 @@ func (mr *MockRateLimiterMockRecorder) ExceedsLimit(clientID interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExceedsLimit", reflect.TypeOf((*MockRateLimiter)(nil).ExceedsLimit), clientID)
 }
+
+// SetLimit mocks base method.
+func (m *MockRateLimiter) SetLimit(rateN float64, burst int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLimit", rateN, burst)
+}
+
+// SetLimit indicates an expected call of SetLimit.
+func (mr *MockRateLimiterMockRecorder) SetLimit(rateN, burst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimit", reflect.TypeOf((*MockRateLimiter)(nil).SetLimit), rateN, burst)
+}
@@ -10,6 +10,7 @@ import (
 
 type RateLimiter interface {
 	ExceedsLimit(clientID string) bool
+	SetLimit(rateN float64, burst int)
 }
 
 // rateLimiter rate limits requests.
@@ -55,6 +56,22 @@ func (rl *rateLimiter) ExceedsLimit(clientID string) bool {
 	return !limiter.Allow()
 }
 
+// SetLimit updates the rate and the burst of all clients.
+func (rl *rateLimiter) SetLimit(rateN float64, burst int) {
+	if rateN <= 0 {
+		log.Warn("ignoring non-positive rate limiter arg")
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rate = rateN
+	rl.burst = burst
+	for _, limiter := range rl.clientLimiters {
+		limiter.SetLimit(rate.Limit(rateN))
+		limiter.SetBurst(burst)
+	}
+}
+
 // deallocate inactive limiters
 func (rl *rateLimiter) autoCleanup() {
 	ticker := time.NewTicker(time.Hour)
@@ -28,3 +28,21 @@ func TestRateLimiting(t *testing.T) {
 	rateLimiter.doCleanup()
 	r.Len(rateLimiter.clientLimiters, 1)
 }
+
+func TestSetLimit(t *testing.T) {
+	r := require.New(t)
+	rateLimiter := &rateLimiter{
+		rate:           0.5,
+		burst:          1,
+		clientLimiters: make(map[string]*clientLimiter),
+	}
+	r.False(rateLimiter.ExceedsLimit(testClientID))
+	r.True(rateLimiter.ExceedsLimit(testClientID))
+
+	// the existing and the new clients get the higher limit
+	rateLimiter.SetLimit(1000, 10)
+	time.Sleep(time.Millisecond * 10)
+	r.False(rateLimiter.ExceedsLimit(testClientID))
+	r.False(rateLimiter.ExceedsLimit(testClientID + "2"))
+	r.Equal(10, rateLimiter.clientLimiters[testClientID+"2"].Burst())
+}
@@ -0,0 +1,44 @@
+package clients
+
+import (
+	"fmt"
+
+	"zktoro/clients/containerd"
+	"zktoro/clients/docker"
+	"zktoro/config"
+)
+
+// NewContainerRuntime creates the client of the container runtime selected in the config.
+// The name labels the containers of the client and the empty name refers to all zktoro containers.
+func NewContainerRuntime(runtimeCfg config.ContainerRuntimeConfig, name string) (ContainerRuntime, error) {
+	return NewAuthContainerRuntime(runtimeCfg, name, "", "")
+}
+
+// NewAuthContainerRuntime creates the client of the container runtime with the registry credentials.
+func NewAuthContainerRuntime(runtimeCfg config.ContainerRuntimeConfig, name string, username, password string) (ContainerRuntime, error) {
+	socketPath := runtimeCfg.ClientSocketPath()
+	switch runtimeCfg.Type {
+	case "", config.ContainerRuntimeDocker:
+		if socketPath == config.DefaultDockerSocket {
+			return newDockerRuntime(docker.NewAuthDockerClient(name, username, password))
+		}
+		return newDockerRuntime(docker.NewSocketClient(name, socketPath, username, password))
+
+	case config.ContainerRuntimePodman:
+		return newDockerRuntime(docker.NewSocketClient(name, socketPath, username, password))
+
+	case config.ContainerRuntimeContainerd:
+		return containerd.NewNerdctlClient(name, runtimeCfg, username, password), nil
+
+	default:
+		return nil, fmt.Errorf("unknown container runtime: %s", runtimeCfg.Type)
+	}
+}
+
+// newDockerRuntime avoids returning a typed nil client.
+func newDockerRuntime(client ContainerRuntime, err error) (ContainerRuntime, error) {
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
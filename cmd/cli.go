@@ -7,16 +7,15 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
-	"reflect"
-	"strings"
+	"time"
 	"zktoro/config"
+	"zktoro/zktoro-core-go/security"
 
 	"github.com/creasty/defaults"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/go-playground/validator/v10"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +28,9 @@ const (
 
 var (
 	cfg config.Config
+	// cfgReadErr is the error from parsing the config file. It is checked by the commands which
+	// need the config so that "zktoro config" can still report the problems in the file.
+	cfgReadErr error
 
 	parsedArgs struct {
 		Version uint64
@@ -63,6 +65,39 @@ var (
 		RunE:  handleZktoroPubKey,
 	}
 
+	cmdZktoroDID = &cobra.Command{
+		Use:   "did",
+		Short: "manage the node DID",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmdZktoroDIDCreate = &cobra.Command{
+		Use:   "create",
+		Short: "create a new node DID with an encrypted key",
+		RunE:  handleZktoroDIDCreate,
+	}
+	cmdZktoroDIDImport = &cobra.Command{
+		Use:   "import",
+		Short: "import an existing Ed25519 key as the node DID key",
+		RunE:  handleZktoroDIDImport,
+	}
+	cmdZktoroDIDRotate = &cobra.Command{
+		Use:   "rotate",
+		Short: "replace the node DID key with a new key",
+		RunE:  handleZktoroDIDRotate,
+	}
+	cmdZktoroDIDLink = &cobra.Command{
+		Use:   "link",
+		Short: "create a linkage credential between the scanner key and the node DID",
+		RunE:  withInitialized(handleZktoroDIDLink),
+	}
+	cmdZktoroDIDExport = &cobra.Command{
+		Use:   "export",
+		Short: "print the DID document of the node",
+		RunE:  handleZktoroDIDExport,
+	}
+
 	cmdzktoroAuthorize = &cobra.Command{
 		Use:   "authorize",
 		Short: "generate a signature for a specific action",
@@ -80,9 +115,151 @@ var (
 		Short: "generate a pool registration signature",
 		RunE:  withInitialized(withValidConfig(handlezktoroAuthorizePool)),
 	}
+	cmdZktoroBackfill = &cobra.Command{
+		Use:   "backfill",
+		Short: "replay a block range through the bots and write the findings to a file",
+		RunE:  withInitialized(withValidConfig(handleZktoroBackfill)),
+	}
+	cmdZktoroBots = &cobra.Command{
+		Use:   "bots",
+		Short: "manage the bots of the running node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmdZktoroBotsList = &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "list the bots of the node",
+		RunE:    withInitialized(handleZktoroBotsList),
+	}
+	cmdZktoroBotsRestart = &cobra.Command{
+		Use:   "restart <bot>",
+		Short: "restart a bot by the bot ID or the container name",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsRestart),
+	}
+	cmdZktoroBotsPause = &cobra.Command{
+		Use:   "pause <bot>",
+		Short: "stop a bot until it is resumed",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsPause),
+	}
+	cmdZktoroBotsResume = &cobra.Command{
+		Use:   "resume <bot>",
+		Short: "resume a paused bot",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsResume),
+	}
+	cmdZktoroBotsLogs = &cobra.Command{
+		Use:   "logs <bot>",
+		Short: "print the latest logs of a bot",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsLogs),
+	}
+	cmdZktoroBotsAdd = &cobra.Command{
+		Use:   "add <image>",
+		Short: "add a bot image to the local mode node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsAdd),
+	}
+	cmdZktoroBotsRemove = &cobra.Command{
+		Use:   "remove <image>",
+		Short: "remove a bot image from the local mode node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withInitialized(handleZktoroBotsRemove),
+	}
+	cmdZktoroConfig = &cobra.Command{
+		Use:   "config",
+		Short: "validate, inspect and migrate the config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmdZktoroConfigValidate = &cobra.Command{
+		Use:   "validate",
+		Short: "validate the config file and report the problems with their line numbers",
+		RunE:  handleZktoroConfigValidate,
+	}
+	cmdZktoroConfigShow = &cobra.Command{
+		Use:   "show",
+		Short: "print the config file with the secrets redacted",
+		RunE:  withConfigFile(handleZktoroConfigShow),
+	}
+	cmdZktoroConfigMigrate = &cobra.Command{
+		Use:   "migrate",
+		Short: "rewrite the config file to the current layout (keeps a backup)",
+		RunE:  handleZktoroConfigMigrate,
+	}
+	cmdZktoroConfigSchema = &cobra.Command{
+		Use:   "schema",
+		Short: "print the JSON Schema of the config file",
+		RunE:  handleZktoroConfigSchema,
+	}
+	cmdZktoroPool = &cobra.Command{
+		Use:   "pool",
+		Short: "manage the scanner pool and its scanners",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmdZktoroPoolRegister = &cobra.Command{
+		Use:   "register",
+		Short: "register a new scanner pool owned by the sender",
+		RunE:  withConfigFile(withValidConfig(handleZktoroPoolRegister)),
+	}
+	cmdZktoroPoolRegisterScanner = &cobra.Command{
+		Use:   "register-scanner",
+		Short: "register a scanner to the pool with the signature from 'zktoro authorize pool --clean'",
+		RunE:  withConfigFile(withValidConfig(handleZktoroPoolRegisterScanner)),
+	}
+	cmdZktoroPoolDisableScanner = &cobra.Command{
+		Use:   "disable-scanner <address>",
+		Short: "disable a scanner in the pool",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withConfigFile(withValidConfig(handleZktoroPoolDisableScanner)),
+	}
+	cmdZktoroPoolEnableScanner = &cobra.Command{
+		Use:   "enable-scanner <address>",
+		Short: "enable a disabled scanner in the pool",
+		Args:  cobra.ExactArgs(1),
+		RunE:  withConfigFile(withValidConfig(handleZktoroPoolEnableScanner)),
+	}
+	cmdZktoroStake = &cobra.Command{
+		Use:   "stake",
+		Short: "manage the stake on scanner pools",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmdZktoroStakeDeposit = &cobra.Command{
+		Use:   "deposit",
+		Short: "deposit stake on a pool (approves the staking contract if needed)",
+		RunE:  withConfigFile(withValidConfig(handleZktoroStakeDeposit)),
+	}
+	cmdZktoroStakeInitiateWithdrawal = &cobra.Command{
+		Use:   "initiate-withdrawal",
+		Short: "start the withdrawal delay of the active shares",
+		RunE:  withConfigFile(withValidConfig(handleZktoroStakeInitiateWithdrawal)),
+	}
+	cmdZktoroStakeWithdraw = &cobra.Command{
+		Use:   "withdraw",
+		Short: "withdraw the inactive stake after the withdrawal delay",
+		RunE:  withConfigFile(withValidConfig(handleZktoroStakeWithdraw)),
+	}
+	cmdZktoroStakeShow = &cobra.Command{
+		Use:   "show",
+		Short: "show the stake and the allocation of a pool",
+		RunE:  withConfigFile(withValidConfig(handleZktoroStakeShow)),
+	}
+	cmdZktoroDevnet = &cobra.Command{
+		Use:   "devnet",
+		Short: "run a local chain with the registry contracts and seed it from a fixture",
+		RunE:  handleZktoroDevnet,
+	}
 	cmdzktoroRunListener = &cobra.Command{
 		Use:   "listen",
-		Short: "Listen for VC to store and VP to retrieve",
+		Short: "Listen for VCs to verify and store and sign VPs on request",
 		RunE:  handleZktoroListen,
 	}
 )
@@ -97,6 +274,36 @@ func init() {
 	cmdZktoro.AddCommand(cmdZktoroSignVp)
 	cmdZktoro.AddCommand(cmdZktoroGetPubKey)
 	cmdZktoro.AddCommand(cmdzktoroRunListener)
+
+	// zktoro signvp
+	cmdZktoroSignVp.Flags().String("nonce", "", "nonce supplied by the verifier")
+	cmdZktoroSignVp.MarkFlagRequired("nonce")
+	cmdZktoroSignVp.Flags().StringSlice("audience", nil, "intended audience of the presentation")
+	cmdZktoroSignVp.Flags().StringSlice("id", nil, "IDs of the credentials to present (default: all valid credentials)")
+
+	cmdZktoro.AddCommand(cmdZktoroDID)
+	cmdZktoroDID.AddCommand(cmdZktoroDIDCreate)
+	cmdZktoroDID.AddCommand(cmdZktoroDIDImport)
+	cmdZktoroDID.AddCommand(cmdZktoroDIDRotate)
+	cmdZktoroDID.AddCommand(cmdZktoroDIDLink)
+	cmdZktoroDID.AddCommand(cmdZktoroDIDExport)
+
+	// zktoro did create
+	cmdZktoroDIDCreate.Flags().String("method", security.DIDMethodKey, "DID method (key or web)")
+	cmdZktoroDIDCreate.Flags().String("domain", "", "domain and optional path for did:web (e.g. example.com/nodes/node1)")
+	cmdZktoroDIDCreate.Flags().BoolP("force", "f", false, "overwrite the existing DID key")
+
+	// zktoro did import
+	cmdZktoroDIDImport.Flags().String("private-key", "", "hex encoded Ed25519 seed or private key")
+	cmdZktoroDIDImport.Flags().Bool("legacy", false, "encrypt the existing unencrypted DID key")
+	cmdZktoroDIDImport.Flags().String("method", security.DIDMethodKey, "DID method (key or web)")
+	cmdZktoroDIDImport.Flags().String("domain", "", "domain and optional path for did:web (e.g. example.com/nodes/node1)")
+	cmdZktoroDIDImport.Flags().BoolP("force", "f", false, "overwrite the existing DID key")
+
+	// zktoro did export
+	cmdZktoroDIDExport.Flags().StringP("output", "o", "", "write the DID document to a file (e.g. did.json for did:web)")
+	cmdZktoroDIDExport.Flags().Bool("private-key", false, "print the hex encoded private key seed instead")
+
 	cmdZktoro.PersistentFlags().String("passphrase", "", "passphrase to decrypt the private key (overrides $zktoro_PASSPHRASE)")
 	viper.BindPFlag(keyZktoroPassphrase, cmdZktoro.PersistentFlags().Lookup("passphrase"))
 
@@ -111,6 +318,85 @@ func init() {
 	cmdzktoroAuthorizePool.Flags().Bool("clean", false, "output only the encoded registration info")
 
 	cmdZktoro.AddCommand(cmdzktoroRun)
+
+	cmdZktoro.AddCommand(cmdZktoroBots)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsList)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsRestart)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsPause)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsResume)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsLogs)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsAdd)
+	cmdZktoroBots.AddCommand(cmdZktoroBotsRemove)
+
+	// zktoro bots logs
+	cmdZktoroBotsLogs.Flags().Int("tail", 100, "number of lines from the end of the logs")
+
+	cmdZktoro.AddCommand(cmdZktoroConfig)
+	cmdZktoroConfig.AddCommand(cmdZktoroConfigValidate)
+	cmdZktoroConfig.AddCommand(cmdZktoroConfigShow)
+	cmdZktoroConfig.AddCommand(cmdZktoroConfigMigrate)
+	cmdZktoroConfig.AddCommand(cmdZktoroConfigSchema)
+
+	// zktoro config
+	cmdZktoroConfigValidate.Flags().String("file", "", "config file to validate (default: <zktoro dir>/config.yml)")
+	cmdZktoroConfigShow.Flags().Bool("effective", false, "show the effective config with the defaults")
+	cmdZktoroConfigMigrate.Flags().Bool("dry-run", false, "print the migrated config without writing it")
+	cmdZktoroConfigSchema.Flags().StringP("output", "o", "", "file to write the schema to")
+
+	cmdZktoro.AddCommand(cmdZktoroPool)
+	cmdZktoroPool.AddCommand(cmdZktoroPoolRegister)
+	cmdZktoroPool.AddCommand(cmdZktoroPoolRegisterScanner)
+	cmdZktoroPool.AddCommand(cmdZktoroPoolDisableScanner)
+	cmdZktoroPool.AddCommand(cmdZktoroPoolEnableScanner)
+
+	// zktoro pool
+	addTxFlags(cmdZktoroPool)
+	cmdZktoroPoolRegister.Flags().Int64("chain-id", 0, "chain ID of the pool scanners (default: chainId in the config)")
+	cmdZktoroPoolRegisterScanner.Flags().String("id", "", "scanner pool ID (integer)")
+	cmdZktoroPoolRegisterScanner.MarkFlagRequired("id")
+	cmdZktoroPoolRegisterScanner.Flags().String("registration", "", "encoded registration info (default: sign the registration with the scanner key of this node)")
+
+	cmdZktoro.AddCommand(cmdZktoroStake)
+	cmdZktoroStake.AddCommand(cmdZktoroStakeDeposit)
+	cmdZktoroStake.AddCommand(cmdZktoroStakeInitiateWithdrawal)
+	cmdZktoroStake.AddCommand(cmdZktoroStakeWithdraw)
+	cmdZktoroStake.AddCommand(cmdZktoroStakeShow)
+
+	// zktoro stake
+	addTxFlags(cmdZktoroStake)
+	for _, cmd := range []*cobra.Command{
+		cmdZktoroStakeDeposit, cmdZktoroStakeInitiateWithdrawal, cmdZktoroStakeWithdraw, cmdZktoroStakeShow,
+	} {
+		cmd.Flags().String("id", "", "scanner pool ID (integer)")
+		cmd.MarkFlagRequired("id")
+	}
+	for _, cmd := range []*cobra.Command{
+		cmdZktoroStakeDeposit, cmdZktoroStakeInitiateWithdrawal, cmdZktoroStakeWithdraw,
+	} {
+		cmd.Flags().Bool("delegate", false, "use the delegated stake instead of the pool owner stake")
+	}
+	cmdZktoroStakeDeposit.Flags().String("amount", "", "amount of tokens to deposit (e.g. 1.5)")
+	cmdZktoroStakeDeposit.MarkFlagRequired("amount")
+	cmdZktoroStakeInitiateWithdrawal.Flags().String("shares", "", "amount of shares to withdraw (default: all active shares)")
+	cmdZktoroStakeShow.Flags().String("staker", "", "address to show the shares of")
+
+	cmdZktoro.AddCommand(cmdZktoroDevnet)
+	cmdZktoroDevnet.Flags().String("listen", "127.0.0.1:8545", "address of the json-rpc endpoint")
+	cmdZktoroDevnet.Flags().String("fixture", "", "YAML file with the pools, scanners, bots and assignments to seed")
+	cmdZktoroDevnet.Flags().StringSlice("fund", nil, "addresses to fund with ETH at genesis")
+	cmdZktoroDevnet.Flags().Bool("ens-override", false, "write the contract addresses to <zktoro dir>/ens-override.json")
+	cmdZktoroDevnet.Flags().Duration("block-time", 2*time.Second, "interval of the empty blocks which are mined when there are no transactions (0 to disable)")
+
+	cmdZktoro.AddCommand(cmdZktoroBackfill)
+	cmdZktoroBackfill.Flags().Uint64("from", 0, "first block of the range")
+	cmdZktoroBackfill.MarkFlagRequired("from")
+	cmdZktoroBackfill.Flags().Uint64("to", 0, "last block of the range")
+	cmdZktoroBackfill.MarkFlagRequired("to")
+	cmdZktoroBackfill.Flags().StringSlice("bots", nil, "bot images to run (default: localMode.botImages)")
+	cmdZktoroBackfill.Flags().StringP("output", "o", "", "JSONL file to write the findings to (default: <zktoro dir>/backfill/findings-<from>-<to>.jsonl)")
+	cmdZktoroBackfill.Flags().String("checkpoint", "", "checkpoint file to resume from (default: <output>.checkpoint)")
+	cmdZktoroBackfill.Flags().Bool("reset", false, "discard the checkpoint and the findings of the previous runs")
+	cmdZktoroBackfill.Flags().Int("concurrency", 8, "max number of bots which are sent requests concurrently")
 }
 
 func initConfig() {
@@ -134,20 +420,24 @@ func initConfig() {
 	configPath := path.Join(zktoroDir, config.DefaultConfigFileName)
 	configBytes, _ := ioutil.ReadFile(configPath)
 	if err := yaml.Unmarshal(configBytes, &cfg); err != nil {
-		yellowBold("Your config file is invalid! Please check the values and fix any formatting issues.\n")
-		logrus.WithError(err).Fatal("failed to read config")
+		yellowBold("Your config file is invalid! Please run 'zktoro config validate' and fix the reported problems.\n")
+		cfgReadErr = err
 	}
 
 	if err := defaults.Set(&cfg); err != nil {
 		panic(err)
 	}
+	if err := config.ResolveSecrets(&cfg); err != nil && cfgReadErr == nil {
+		yellowBold("Failed to resolve the secrets in your config file! Please run 'zktoro config validate' and fix the reported problems.\n")
+		cfgReadErr = err
+	}
 
 	cfg.ZktoroDir = zktoroDir
 	cfg.KeyDirPath = path.Join(cfg.ZktoroDir, config.DefaultKeysDirName)
 	cfg.Development = viper.GetBool(keyZktoroDevelopment)
 	cfg.Passphrase = viper.GetString(keyZktoroPassphrase)
 	cfg.DIDKeyPath = path.Join(zktoroDir, ".did")
-	cfg.VcPath = path.Join(zktoroDir, "vc.json")
+	cfg.CredentialsPath = path.Join(zktoroDir, "credentials.json")
 	cfg.VpPath = path.Join(zktoroDir, "vp.jwt")
 
 	viper.ReadConfig(bytes.NewBuffer(configBytes))
@@ -165,6 +455,9 @@ func withValidConfig(handler func(*cobra.Command, []string) error) func(*cobra.C
 
 func withInitialized(handler func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		if cfgReadErr != nil {
+			return fmt.Errorf("failed to read config: %v", cfgReadErr)
+		}
 		if !isInitialized() {
 			yellowBold("Please make sure you do 'zktoro init' first and check your configuration at %s/config.yml\n", cfg.ZktoroDir)
 			return errors.New("not initialized")
@@ -174,25 +467,28 @@ func withInitialized(handler func(*cobra.Command, []string) error) func(*cobra.C
 }
 
 func validateConfig() error {
-	validate := validator.New()
-
-	// Use the YAML names while validating the struct.
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("yaml"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
+	configBytes, err := os.ReadFile(cfg.ConfigFilePath())
+	if err == nil {
+		return printConfigProblems(cfg.ConfigFilePath(), configBytes)
+	}
 
-	if err := validate.Struct(&cfg); err != nil {
-		validationErrs := err.(validator.ValidationErrors)
+	err = config.Validate(&cfg)
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
 		fmt.Fprintln(os.Stderr, "The config file has invalid or missing fields:")
-		for _, validationErr := range validationErrs {
-			fmt.Fprintf(os.Stderr, "  - %s\n", validationErr.Namespace()[7:])
+		for _, field := range validationErr.Fields {
+			fmt.Fprintf(os.Stderr, "  - %s\n", field)
 		}
 		return errors.New("invalid config file")
 	}
+	return err
+}
 
-	return nil
+func withConfigFile(handler func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if cfgReadErr != nil {
+			return fmt.Errorf("failed to read config: %v", cfgReadErr)
+		}
+		return handler(cmd, args)
+	}
 }
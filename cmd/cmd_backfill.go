@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"strconv"
+	"syscall"
+
+	"zktoro/config"
+	"zktoro/services/backfill"
+
+	"zktoro/zktoro-core-go/ethereum"
+
+	"github.com/spf13/cobra"
+)
+
+func handleZktoroBackfill(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetUint64("from")
+	to, _ := cmd.Flags().GetUint64("to")
+	botImages, _ := cmd.Flags().GetStringSlice("bots")
+	outputPath, _ := cmd.Flags().GetString("output")
+	checkpointPath, _ := cmd.Flags().GetString("checkpoint")
+	reset, _ := cmd.Flags().GetBool("reset")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+	if len(botImages) == 0 {
+		botImages = cfg.LocalModeConfig.BotImages
+	}
+	if len(botImages) == 0 {
+		return errors.New("please specify the bot images with --bots")
+	}
+	if cfg.Scan.JsonRpc.Url == "" {
+		return errors.New("please specify scan.jsonRpc.url in the config")
+	}
+	backfillDir := path.Join(cfg.ZktoroDir, "backfill")
+	if outputPath == "" {
+		outputPath = path.Join(backfillDir, fmt.Sprintf("findings-%d-%d.jsonl", from, to))
+	}
+	if checkpointPath == "" {
+		checkpointPath = outputPath + ".checkpoint"
+	}
+
+	// bot IDs are assigned in the same way as the local mode does for the bot images
+	var bots []config.AgentConfig
+	for i, image := range botImages {
+		bots = append(bots, config.AgentConfig{
+			ID:      strconv.Itoa(i + 1),
+			Image:   image,
+			IsLocal: true,
+			ChainID: cfg.ChainID,
+		})
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client, err := ethereum.NewFailoverStreamEthClient(ctx, "chain", cfg.Scan.JsonRpc.EthEndpoints(), cfg.Scan.JsonRpc.FailoverOptions())
+	if err != nil {
+		return fmt.Errorf("failed to create the eth client: %v", err)
+	}
+	traceClient := client
+	if cfg.Trace.Enabled {
+		traceClient, err = ethereum.NewFailoverStreamEthClient(ctx, "trace", cfg.Trace.JsonRpc.EthEndpoints(), cfg.Trace.JsonRpc.FailoverOptions())
+		if err != nil {
+			return fmt.Errorf("failed to create the trace client: %v", err)
+		}
+	}
+	launcher, err := backfill.NewDockerBotLauncher(cfg.Scan.JsonRpc, cfg.ContainerRuntime)
+	if err != nil {
+		return err
+	}
+
+	backfiller, err := backfill.NewBackfiller(backfill.Config{
+		From:           from,
+		To:             to,
+		ChainID:        uint64(cfg.ChainID),
+		Bots:           bots,
+		Tracing:        cfg.Trace.Enabled,
+		Concurrency:    concurrency,
+		OutputPath:     outputPath,
+		CheckpointPath: checkpointPath,
+		Reset:          reset,
+		Client:         client,
+		TraceClient:    traceClient,
+		Launcher:       launcher,
+	})
+	if err != nil {
+		return err
+	}
+
+	summary, err := backfiller.Run(ctx)
+	if errors.Is(err, backfill.ErrCheckpointMismatch) {
+		yellowBold("The checkpoint at %s is from another backfill. Please use --reset to start over or --checkpoint to use another file.\n", checkpointPath)
+		return err
+	}
+	if summary != nil && summary.AlreadyDone {
+		greenBold("Blocks %d-%d were already backfilled. Use --reset to start over.\n", from, to)
+		whiteBold("Findings: %s\n", outputPath)
+		return nil
+	}
+	if summary != nil && summary.ResumedBlock != nil {
+		whiteBold("Resumed from block %d\n", *summary.ResumedBlock)
+	}
+	if err != nil {
+		if summary != nil && summary.Blocks > 0 {
+			yellowBold("Backfill stopped after block %d. Run the same command again to resume.\n", summary.LastBlock)
+		}
+		return err
+	}
+
+	greenBold("Backfilled blocks %d-%d\n", from, to)
+	fmt.Printf("Blocks: %d\nTransactions: %d\nFindings: %d\nBot errors: %d\n",
+		summary.Blocks, summary.Transactions, summary.Findings, summary.BotErrors)
+	whiteBold("Findings: %s\n", outputPath)
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"zktoro/clients/admin"
+
+	"github.com/spf13/cobra"
+)
+
+func newAdminClient() (*admin.Client, error) {
+	token := cfg.AdminAPI.AuthToken
+	if token == "" {
+		b, err := os.ReadFile(cfg.AdminTokenPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the admin api token (is the node running?): %v", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	return admin.NewClient(cfg.AdminAPI.Address, token), nil
+}
+
+func handleZktoroBotsList(cmd *cobra.Command, args []string) error {
+	client, err := newAdminClient()
+	if err != nil {
+		return err
+	}
+	bots, err := client.ListBots(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BOT ID\tIMAGE\tSTATE\tCONTAINER\tFAILURES\tLAST ACTIVITY")
+	for _, bot := range bots {
+		containerState := "-"
+		if bot.Container != nil {
+			containerState = bot.Container.Status
+		}
+		lastActivity := "-"
+		if bot.LastActivity != nil {
+			lastActivity = time.Since(*bot.LastActivity).Truncate(time.Second).String() + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			bot.Config.ID, bot.Config.Image, bot.State, containerState, bot.RecentFailures, lastActivity)
+	}
+	return w.Flush()
+}
+
+func handleZktoroBotsRestart(cmd *cobra.Command, args []string) error {
+	return doBotAction(cmd, args[0], "restarted", (*admin.Client).RestartBot)
+}
+
+func handleZktoroBotsPause(cmd *cobra.Command, args []string) error {
+	return doBotAction(cmd, args[0], "paused", (*admin.Client).PauseBot)
+}
+
+func handleZktoroBotsResume(cmd *cobra.Command, args []string) error {
+	return doBotAction(cmd, args[0], "resumed", (*admin.Client).ResumeBot)
+}
+
+func doBotAction(
+	cmd *cobra.Command, botRef, done string, action func(*admin.Client, context.Context, string) error,
+) error {
+	client, err := newAdminClient()
+	if err != nil {
+		return err
+	}
+	if err := action(client, cmd.Context(), botRef); err != nil {
+		return err
+	}
+	greenBold("Bot %s is %s\n", botRef, done)
+	return nil
+}
+
+func handleZktoroBotsLogs(cmd *cobra.Command, args []string) error {
+	tail, _ := cmd.Flags().GetInt("tail")
+	client, err := newAdminClient()
+	if err != nil {
+		return err
+	}
+	botLogs, err := client.BotLogs(cmd.Context(), args[0], tail)
+	if err != nil {
+		return err
+	}
+	for _, logs := range botLogs {
+		if len(botLogs) > 1 {
+			whiteBold("==> %s <==\n", logs.ContainerName)
+		}
+		fmt.Print(logs.Logs)
+	}
+	return nil
+}
+
+func handleZktoroBotsAdd(cmd *cobra.Command, args []string) error {
+	client, err := newAdminClient()
+	if err != nil {
+		return err
+	}
+	if err := client.AddBotImage(cmd.Context(), args[0]); err != nil {
+		return err
+	}
+	greenBold("Added bot image %s\n", args[0])
+	return nil
+}
+
+func handleZktoroBotsRemove(cmd *cobra.Command, args []string) error {
+	client, err := newAdminClient()
+	if err != nil {
+		return err
+	}
+	if err := client.RemoveBotImage(cmd.Context(), args[0]); err != nil {
+		return err
+	}
+	greenBold("Removed bot image %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"zktoro/config"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func handleZktoroConfigValidate(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("file")
+	if configPath == "" {
+		configPath = cfg.ConfigFilePath()
+	}
+	configBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read the config file: %v", err)
+	}
+	if err := printConfigProblems(configPath, configBytes); err != nil {
+		return err
+	}
+	greenBold("%s is valid\n", configPath)
+	return nil
+}
+
+// printConfigProblems prints the errors and the warnings of the config file and fails if there
+// are any errors.
+func printConfigProblems(configPath string, configBytes []byte) error {
+	_, problems, err := config.CheckConfigYAML(configBytes)
+	if err != nil {
+		return err
+	}
+	var errCount int
+	for _, problem := range problems {
+		if problem.Warning {
+			yellowBold("%s: warning: %s\n", configPath, problem.Error())
+			continue
+		}
+		redBold("%s: %s\n", configPath, problem.Error())
+		errCount++
+	}
+	if errCount > 0 {
+		return errors.New("invalid config file")
+	}
+	return nil
+}
+
+func handleZktoroConfigShow(cmd *cobra.Command, args []string) error {
+	effective, _ := cmd.Flags().GetBool("effective")
+	if effective {
+		b, err := config.EffectiveConfigYAML(cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		return nil
+	}
+
+	configBytes, err := os.ReadFile(cfg.ConfigFilePath())
+	if err != nil {
+		return fmt.Errorf("failed to read the config file: %v", err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(configBytes, &root); err != nil {
+		return fmt.Errorf("failed to parse the config file: %v", err)
+	}
+	config.RedactYAML(&root)
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func handleZktoroConfigMigrate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// the older deployments may only have the wrapped config
+	configPath := cfg.ConfigFilePath()
+	sourcePath := configPath
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		sourcePath = path.Join(cfg.ZktoroDir, config.DefaultWrappedConfigFileName)
+	}
+	configBytes, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to read the config file: %v", err)
+	}
+
+	migrated, changes, problems, err := config.MigrateYAML(configBytes)
+	if err != nil {
+		return fmt.Errorf("failed to migrate the config file: %v", err)
+	}
+	// the unknown fields are kept so that they can be fixed by hand
+	for _, problem := range problems {
+		yellowBold("%s: warning: %s\n", sourcePath, problem.Error())
+	}
+	if len(changes) == 0 {
+		greenBold("%s is up to date\n", sourcePath)
+		return nil
+	}
+	for _, change := range changes {
+		whiteBold("- %s\n", change)
+	}
+	if dryRun {
+		fmt.Print(string(migrated))
+		return nil
+	}
+
+	backupPath := sourcePath + ".bak"
+	if err := os.WriteFile(backupPath, configBytes, 0644); err != nil {
+		return fmt.Errorf("failed to back up the config file: %v", err)
+	}
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, migrated, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		return err
+	}
+	greenBold("Migrated the config to %s (backup: %s)\n", configPath, backupPath)
+
+	// the warnings are printed above already
+	_, problems, err = config.CheckConfigYAML(migrated)
+	if err != nil {
+		return err
+	}
+	var errCount int
+	for _, problem := range problems {
+		if !problem.Warning {
+			redBold("%s: %s\n", configPath, problem.Error())
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		return errors.New("invalid config file")
+	}
+	return nil
+}
+
+func handleZktoroConfigSchema(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	b, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		fmt.Println(string(b))
+		return nil
+	}
+	return os.WriteFile(outputPath, append(b, '\n'), 0644)
+}
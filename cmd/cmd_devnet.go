@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"zktoro/zktoro-core-go/ens"
+	"zktoro/zktoro-core-go/testutils/devchain"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+const ensOverrideFileName = "ens-override.json"
+
+// devnetContractNames are the contracts which are listed when the dev chain starts.
+var devnetContractNames = []string{
+	ens.AgentRegistryContract,
+	ens.ScannerRegistryContract,
+	ens.ScannerPoolRegistryContract,
+	ens.DispatchContract,
+	ens.ScannerNodeVersionContract,
+	ens.StakingContract,
+	ens.StakeAllocatorContract,
+	ens.RewardsContract,
+	ens.ZktoroContract,
+}
+
+func handleZktoroDevnet(cmd *cobra.Command, args []string) error {
+	listenAddr, _ := cmd.Flags().GetString("listen")
+	fixturePath, _ := cmd.Flags().GetString("fixture")
+	fund, _ := cmd.Flags().GetStringSlice("fund")
+	writeOverride, _ := cmd.Flags().GetBool("ens-override")
+	blockTime, _ := cmd.Flags().GetDuration("block-time")
+
+	devCfg := devchain.Config{ListenAddr: listenAddr}
+	for _, account := range fund {
+		if !common.IsHexAddress(account) {
+			return fmt.Errorf("invalid address to fund: %s", account)
+		}
+		devCfg.Accounts = append(devCfg.Accounts, common.HexToAddress(account))
+	}
+
+	var fixture *devchain.Fixture
+	if len(fixturePath) > 0 {
+		var err error
+		fixture, err = devchain.LoadFixture(fixturePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	chain, err := devchain.New(devCfg)
+	if err != nil {
+		return err
+	}
+	defer chain.Close()
+
+	if fixture != nil {
+		if err := chain.Seed(fixture); err != nil {
+			return fmt.Errorf("failed to seed the fixture: %v", err)
+		}
+	}
+
+	if writeOverride {
+		b, err := json.MarshalIndent(chain.ENSOverrides(), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(cfg.ZktoroDir, 0755); err != nil {
+			return err
+		}
+		overridePath := path.Join(cfg.ZktoroDir, ensOverrideFileName)
+		if err := os.WriteFile(overridePath, b, 0644); err != nil {
+			return fmt.Errorf("failed to write the ens overrides: %v", err)
+		}
+		greenBold("Wrote the contract addresses to %s\n", overridePath)
+	}
+
+	greenBold("Dev chain is running!\n\n")
+	whiteBold("JSON-RPC: ")
+	fmt.Println(chain.URL())
+	whiteBold("Chain ID: ")
+	fmt.Println(devchain.ChainID)
+	whiteBold("Owner: ")
+	fmt.Println(chain.Owner().Hex())
+	whiteBold("Owner key (dev only): ")
+	fmt.Printf("0x%s\n\n", hex.EncodeToString(crypto.FromECDSA(chain.OwnerKey())))
+
+	overrides := chain.ENSOverrides()
+	for _, name := range devnetContractNames {
+		fmt.Printf("  %-40s %s\n", name, overrides[name])
+	}
+
+	fmt.Println()
+	yellowBold("To use the dev chain as the registry, set these in %s:\n", cfg.ConfigFilePath())
+	fmt.Printf("  registry:\n    chainId: %d\n    jsonRpc:\n      url: %s\n  ens:\n    override: true\n\n", devchain.ChainID, chain.URL())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if blockTime > 0 {
+		go func() {
+			ticker := time.NewTicker(blockTime)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					chain.Commit()
+				}
+			}
+		}()
+	}
+	<-ctx.Done()
+	fmt.Println("Stopping the dev chain")
+	return nil
+}
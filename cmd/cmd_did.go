@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"zktoro/zktoro-core-go/security"
+
+	"github.com/spf13/cobra"
+)
+
+// errors
+var (
+	ErrDIDExists    = errors.New("node DID already exists")
+	ErrDIDNotExists = errors.New("node DID does not exist")
+)
+
+func handleZktoroDIDCreate(cmd *cobra.Command, args []string) error {
+	if err := checkDIDPassphrase(); err != nil {
+		return err
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	if err := checkDIDOverwrite(force); err != nil {
+		return err
+	}
+
+	method, _ := cmd.Flags().GetString("method")
+	domain, _ := cmd.Flags().GetString("domain")
+	key, err := security.GenerateDIDKey(method, domain)
+	if err != nil {
+		return err
+	}
+	if err := storeNodeDIDKey(key); err != nil {
+		return err
+	}
+
+	greenBold("Node DID created\n")
+	return printDIDDocument(key)
+}
+
+func handleZktoroDIDImport(cmd *cobra.Command, args []string) error {
+	if err := checkDIDPassphrase(); err != nil {
+		return err
+	}
+	legacy, _ := cmd.Flags().GetBool("legacy")
+	privateKeyHex, _ := cmd.Flags().GetString("private-key")
+	method, _ := cmd.Flags().GetString("method")
+	domain, _ := cmd.Flags().GetString("domain")
+
+	var (
+		privateKey ed25519.PrivateKey
+		err        error
+	)
+	switch {
+	case legacy && privateKeyHex != "":
+		return errors.New("please use only one of --legacy and --private-key")
+	case legacy:
+		privateKey, err = security.LoadLegacyDIDKey(cfg.DIDKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to load the legacy DID key: %v", err)
+		}
+	case privateKeyHex != "":
+		force, _ := cmd.Flags().GetBool("force")
+		if err := checkDIDOverwrite(force); err != nil {
+			return err
+		}
+		privateKey, err = decodeDIDPrivateKey(privateKeyHex)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New("please specify --legacy or --private-key")
+	}
+
+	key, err := security.NewDIDKey(method, domain, privateKey)
+	if err != nil {
+		return err
+	}
+	if err := storeNodeDIDKey(key); err != nil {
+		return err
+	}
+
+	greenBold("Node DID imported\n")
+	return printDIDDocument(key)
+}
+
+func handleZktoroDIDRotate(cmd *cobra.Command, args []string) error {
+	key, err := loadNodeDIDKey()
+	if err != nil {
+		return err
+	}
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	rotated, err := key.Rotate(privateKey)
+	if err != nil {
+		return err
+	}
+	if err := storeNodeDIDKey(rotated); err != nil {
+		return err
+	}
+
+	greenBold("Node DID key rotated\n")
+	if _, err := os.Stat(cfg.DIDLinkagePath()); err == nil {
+		if _, err := linkNodeDID(rotated); err != nil {
+			return fmt.Errorf("failed to update the DID linkage: %v", err)
+		}
+		greenBold("Updated the scanner DID linkage\n")
+	}
+	if rotated.DID != key.DID {
+		yellowBold("The DID has changed from %s - credentials issued to the old DID need to be issued again.\n", key.DID)
+	} else {
+		yellowBold("Please publish the new DID document below.\n")
+	}
+	return printDIDDocument(rotated)
+}
+
+func handleZktoroDIDLink(cmd *cobra.Command, args []string) error {
+	key, err := loadNodeDIDKey()
+	if err != nil {
+		return err
+	}
+	linkage, err := linkNodeDID(key)
+	if err != nil {
+		return err
+	}
+
+	greenBold("Scanner %s is linked to %s\n", linkage.Scanner, linkage.DID)
+	if !cfg.Identity.EmbedDIDLinkage {
+		yellowBold("Set identity.embedDidLinkage to true in config.yml to embed the linkage in tokens and batches.\n")
+	}
+	return nil
+}
+
+// linkNodeDID creates and stores the linkage between the scanner key and the node DID.
+func linkNodeDID(key *security.DIDKey) (*security.DIDLinkage, error) {
+	scannerKey, err := security.LoadKeyWithPassphrase(cfg.KeyDirPath, cfg.Passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the scanner key: %v", err)
+	}
+	linkage, err := security.CreateDIDLinkage(scannerKey, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := security.StoreDIDLinkage(cfg.DIDLinkagePath(), linkage); err != nil {
+		return nil, fmt.Errorf("failed to store the DID linkage: %v", err)
+	}
+	return linkage, nil
+}
+
+func handleZktoroDIDExport(cmd *cobra.Command, args []string) error {
+	key, err := loadNodeDIDKey()
+	if err != nil {
+		return err
+	}
+
+	exportPrivateKey, _ := cmd.Flags().GetBool("private-key")
+	if exportPrivateKey {
+		fmt.Println(hex.EncodeToString(key.PrivateKey.Seed()))
+		return nil
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	b, err := json.MarshalIndent(key.Document(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		fmt.Println(string(b))
+		return nil
+	}
+	return os.WriteFile(output, b, 0644)
+}
+
+func storeNodeDIDKey(key *security.DIDKey) error {
+	if err := os.MkdirAll(cfg.ZktoroDir, 0755); err != nil {
+		return err
+	}
+	if err := security.StoreDIDKey(cfg.DIDKeyPath, key, cfg.Passphrase); err != nil {
+		return fmt.Errorf("failed to store the DID key: %v", err)
+	}
+	return nil
+}
+
+func printDIDDocument(key *security.DIDKey) error {
+	b, err := json.MarshalIndent(key.Document(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nDID: %s\n", key.DID)
+	fmt.Printf("Verification method: %s\n\n", key.KeyID)
+	fmt.Println(string(b))
+	return nil
+}
+
+// checkDIDPassphrase makes sure that the DID key is encrypted with the scanner keystore passphrase.
+func checkDIDPassphrase() error {
+	if len(cfg.Passphrase) == 0 {
+		yellowBold("Please provide the passphrase of the scanner key.\n")
+		return errors.New("missing passphrase")
+	}
+	if !isKeyInitialized() {
+		return nil
+	}
+	if _, err := security.LoadKeyWithPassphrase(cfg.KeyDirPath, cfg.Passphrase); err != nil {
+		return fmt.Errorf("the passphrase does not match the scanner key: %v", err)
+	}
+	return nil
+}
+
+func checkDIDOverwrite(force bool) error {
+	if _, err := os.Stat(cfg.DIDKeyPath); err == nil && !force {
+		yellowBold("Please use 'zktoro did rotate' to replace the key or --force to overwrite it.\n")
+		return ErrDIDExists
+	}
+	return nil
+}
+
+func decodeDIDPrivateKey(privateKeyHex string) (ed25519.PrivateKey, error) {
+	b, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		privateKey := ed25519.PrivateKey(b)
+		if !ed25519.NewKeyFromSeed(privateKey.Seed()).Equal(privateKey) {
+			return nil, errors.New("invalid private key: public key mismatch")
+		}
+		return privateKey, nil
+	}
+	return nil, fmt.Errorf("invalid private key length: %d", len(b))
+}
+
+func loadNodeDIDKey() (*security.DIDKey, error) {
+	key, err := security.LoadDIDKeyWithPassphrase(cfg.DIDKeyPath, cfg.Passphrase)
+	switch {
+	case os.IsNotExist(err):
+		yellowBold("Please create the node DID with 'zktoro did create' first.\n")
+		return nil, ErrDIDNotExists
+	case errors.Is(err, security.ErrLegacyDIDKey):
+		yellowBold("Please encrypt the existing key with 'zktoro did import --legacy'.\n")
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("failed to load the node DID key: %v", err)
+	}
+	return key, nil
+}
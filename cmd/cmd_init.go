@@ -88,6 +88,7 @@ func printScannerAddress(address string) {
 }
 
 const defaultConfig = `# Auto generated by 'zktoro init' - safe to modify
+# Check the changes with 'zktoro config validate' - 'zktoro config schema' prints the JSON Schema for the editors
 
 # Chain ID of the network that is analyzed (1=mainnet)
 # Set this before registering the node
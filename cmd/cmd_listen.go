@@ -2,50 +2,30 @@ package cmd
 
 import (
 	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"os"
 
+	"zktoro/services"
+	"zktoro/services/listener"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 func handleZktoroListen(cmd *cobra.Command, args []string) error {
-
-	http.HandleFunc("/putVC", func(w http.ResponseWriter, r *http.Request) {
-		body, _ := io.ReadAll(r.Body)
-		defer r.Body.Close()
-		// var vc VC
-
-		_ = os.WriteFile(cfg.VcPath, body, 0644)
-		fmt.Println("\033[32m", "Credential succesfully stored", "\033[0m")
-	})
-
-	http.HandleFunc("/retrieveVP", func(w http.ResponseWriter, r *http.Request) {
-		vp, err := os.ReadFile(cfg.VpPath)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("unable to read VP file"))
-		} else {
-			w.WriteHeader(http.StatusOK)
-			w.Write(vp)
-			fmt.Println("\033[32m", "VP Sent to Client", "\033[0m")
-		}
-	})
-
-	http.HandleFunc("/signVP_temporary", func(w http.ResponseWriter, r *http.Request) {
-		err := signVP()
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("Unable to sign vp: %s", err)))
-		} else {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("VP signed"))
-
-		}
-	})
-	fmt.Println("listen on 80")
-	log.Fatal(http.ListenAndServe(":80", nil))
-	return nil
-
+	credentialHolder, err := newCredentialHolder()
+	if err != nil {
+		return err
+	}
+	credentialListener, err := listener.NewListener(cfg, credentialHolder.Router())
+	if err != nil {
+		return fmt.Errorf("failed to create the listener: %v", err)
+	}
+
+	whiteBold("Holder DID: %s\n", credentialHolder.DID())
+	if cfg.Listener.AuthMode == listener.AuthModeToken && cfg.Listener.AuthToken == "" {
+		whiteBold("Bearer token: %s\n", credentialListener.TokenPath())
+	}
+
+	ctx, cancel := services.InitMainContext()
+	defer cancel()
+	return services.StartServices(ctx, cancel, log.WithField("process", "listener"), []services.Service{credentialListener})
 }
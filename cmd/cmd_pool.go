@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"zktoro/store"
+	"zktoro/zktoro-core-go/contracts/generated/contract_scanner_pool_registry_0_1_0"
+	"zktoro/zktoro-core-go/registry"
+	"zktoro/zktoro-core-go/security/eip712"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+// newTxRegistryClient creates the registry client which is used for finding the contracts and
+// reading the state before and after the transactions.
+func newTxRegistryClient(ctx context.Context) (registry.Client, error) {
+	regClient, err := store.GetRegistryClient(ctx, cfg, registry.ClientConfig{
+		JsonRpcUrl: cfg.Registry.JsonRpc.Url,
+		ENSAddress: cfg.ENSConfig.ContractAddress,
+		Name:       "registry-client",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %v", err)
+	}
+	return regClient, nil
+}
+
+func parsePoolID(cmd *cobra.Command) (*big.Int, error) {
+	poolIDStr, _ := cmd.Flags().GetString("id")
+	poolID, ok := big.NewInt(0).SetString(poolIDStr, 10)
+	if !ok || poolID.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid pool ID: %s", poolIDStr)
+	}
+	return poolID, nil
+}
+
+func parseScannerAddress(scanner string) (common.Address, error) {
+	if !common.IsHexAddress(scanner) {
+		return common.Address{}, fmt.Errorf("invalid scanner address: %s", scanner)
+	}
+	return common.HexToAddress(scanner), nil
+}
+
+func handleZktoroPoolRegister(cmd *cobra.Command, args []string) error {
+	chainID, _ := cmd.Flags().GetInt64("chain-id")
+	if chainID == 0 {
+		chainID = int64(cfg.ChainID)
+	}
+
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+
+	receipt, err := sender.Send(cmd.Context(), "Register scanner pool", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ScannerPoolRegTx.RegisterScannerPool(opts, big.NewInt(chainID))
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	for _, log := range receipt.Logs {
+		registered, err := contracts.ScannerPoolRegFil.ParseScannerPoolRegistered(*log)
+		if err == nil {
+			greenBold("Registered scanner pool %s for chain %s\n", registered.ScannerPoolId, registered.ChainId)
+			return nil
+		}
+	}
+	return errors.New("pool registration event not found in the receipt")
+}
+
+func handleZktoroPoolRegisterScanner(cmd *cobra.Command, args []string) error {
+	poolID, err := parsePoolID(cmd)
+	if err != nil {
+		return err
+	}
+	registration, _ := cmd.Flags().GetString("registration")
+
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	var regInfo *registry.ScannerRegistrationInfo
+	if len(registration) > 0 {
+		regInfo, err = decodeScannerRegistration(registration)
+	} else {
+		regInfo, err = signLocalScannerRegistration(cmd.Context(), sender, poolID)
+	}
+	if err != nil {
+		return err
+	}
+	input := regInfo.RegistrationInput
+	if input.ScannerPoolId.Cmp(poolID) != 0 {
+		return fmt.Errorf("the registration is for pool %s and not for pool %s", input.ScannerPoolId, poolID)
+	}
+	sig, err := hexutil.Decode(regInfo.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid registration signature: %v", err)
+	}
+
+	willShutdown, err := regClient.WillNewScannerShutdownPool(poolID)
+	if err != nil {
+		return fmt.Errorf("failed to check pool shutdown condition: %v", err)
+	}
+	if willShutdown {
+		yellowBold("Warning: registering this scanner will shutdown the pool until more is staked on pool %s!\n", poolID)
+	}
+
+	contracts := regClient.Contracts()
+	receipt, err := sender.Send(cmd.Context(), "Register scanner node", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ScannerPoolRegTx.RegisterScannerNode(opts, contract_scanner_pool_registry_0_1_0.ScannerPoolRegistryCoreScannerNodeRegistration{
+			Scanner:       input.Scanner,
+			ScannerPoolId: input.ScannerPoolId,
+			ChainId:       input.ChainId,
+			Metadata:      input.Metadata,
+			Timestamp:     input.Timestamp,
+		}, sig)
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	greenBold("Registered scanner %s to pool %s\n", input.Scanner.Hex(), poolID)
+	return nil
+}
+
+// decodeScannerRegistration decodes the output of 'zktoro authorize pool --clean'.
+func decodeScannerRegistration(registration string) (*registry.ScannerRegistrationInfo, error) {
+	b, err := base64.URLEncoding.DecodeString(registration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the registration: %v", err)
+	}
+	var regInfo registry.ScannerRegistrationInfo
+	if err := json.Unmarshal(b, &regInfo); err != nil {
+		return nil, fmt.Errorf("failed to decode the registration: %v", err)
+	}
+	if regInfo.RegistrationInput == nil || regInfo.RegistrationInput.ScannerPoolId == nil ||
+		regInfo.RegistrationInput.ChainId == nil || regInfo.RegistrationInput.Timestamp == nil {
+		return nil, errors.New("incomplete registration input")
+	}
+	return &regInfo, nil
+}
+
+// signLocalScannerRegistration signs the registration of the scanner of this node.
+func signLocalScannerRegistration(ctx context.Context, sender *txSender, poolID *big.Int) (*registry.ScannerRegistrationInfo, error) {
+	signer, err := loadScannerSigner()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scanner signer: %v", err)
+	}
+	regClient, err := store.GetRegistryClient(ctx, cfg, registry.ClientConfig{
+		JsonRpcUrl: cfg.Registry.JsonRpc.Url,
+		ENSAddress: cfg.ENSConfig.ContractAddress,
+		Name:       "registry-client",
+		Signer:     signer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %v", err)
+	}
+	regClient.SetRegistryChainID(sender.chainID.Uint64())
+	regInfo, err := regClient.GenerateScannerRegistrationSignature(&eip712.ScannerNodeRegistration{
+		Scanner:       signer.Address(),
+		ScannerPoolId: poolID,
+		ChainId:       big.NewInt(int64(cfg.ChainID)),
+		Timestamp:     big.NewInt(time.Now().Unix()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate registration signature: %v", err)
+	}
+	return regInfo, nil
+}
+
+func handleZktoroPoolDisableScanner(cmd *cobra.Command, args []string) error {
+	return doScannerAction(cmd, args[0], "Disable scanner", "disabled",
+		func(contracts *registry.Contracts, opts *bind.TransactOpts, scanner common.Address) (*types.Transaction, error) {
+			return contracts.ScannerPoolRegTx.DisableScanner(opts, scanner)
+		})
+}
+
+func handleZktoroPoolEnableScanner(cmd *cobra.Command, args []string) error {
+	return doScannerAction(cmd, args[0], "Enable scanner", "enabled",
+		func(contracts *registry.Contracts, opts *bind.TransactOpts, scanner common.Address) (*types.Transaction, error) {
+			return contracts.ScannerPoolRegTx.EnableScanner(opts, scanner)
+		})
+}
+
+func doScannerAction(
+	cmd *cobra.Command, scannerStr, name, done string,
+	action func(*registry.Contracts, *bind.TransactOpts, common.Address) (*types.Transaction, error),
+) error {
+	scanner, err := parseScannerAddress(scannerStr)
+	if err != nil {
+		return err
+	}
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+	receipt, err := sender.Send(cmd.Context(), name, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return action(contracts, opts, scanner)
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	greenBold("Scanner %s is %s\n", scanner.Hex(), done)
+	return nil
+}
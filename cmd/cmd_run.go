@@ -7,8 +7,6 @@ import (
 
 	"zktoro/zktoro-core-go/registry"
 
-	"zktoro/zktoro-core-go/security"
-
 	"zktoro/cmd/runner"
 
 	"zktoro/store"
@@ -38,9 +36,9 @@ func handlezktoroRun(cmd *cobra.Command, args []string) error {
 }
 
 func checkScannerState() error {
-	scannerKey, err := security.LoadKeyWithPassphrase(cfg.KeyDirPath, cfg.Passphrase)
+	signer, err := loadScannerSigner()
 	if err != nil {
-		return fmt.Errorf("failed to load scanner key: %v", err)
+		return fmt.Errorf("failed to load scanner signer: %v", err)
 	}
 
 	// disable registration and staking check in local mode
@@ -52,7 +50,7 @@ func checkScannerState() error {
 		return nil
 	}
 
-	scannerAddressStr := scannerKey.Address.Hex()
+	scannerAddressStr := signer.Address().Hex()
 
 	registry, err := store.GetRegistryClientWithoutENS(context.Background(), cfg, registry.ClientConfig{
 		JsonRpcUrl: cfg.Registry.JsonRpc.Url,
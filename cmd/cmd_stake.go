@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"zktoro/zktoro-core-go/contracts/merged/contract_zktoro"
+	"zktoro/zktoro-core-go/registry"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+const tokenDecimals = 18
+
+var tokenUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(tokenDecimals), nil)
+
+// parseTokenAmount converts a decimal token amount (e.g. "1.5") to the smallest unit.
+func parseTokenAmount(amount string) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if len(frac) > tokenDecimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, tokenDecimals)
+	}
+	value, ok := new(big.Int).SetString(whole+frac+strings.Repeat("0", tokenDecimals-len(frac)), 10)
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid amount: %s", amount)
+	}
+	return value, nil
+}
+
+// formatTokenAmount converts the smallest unit to a decimal token amount.
+func formatTokenAmount(value *big.Int) string {
+	whole, frac := new(big.Int).QuoRem(value, tokenUnit, new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String()
+	}
+	fracStr := strings.TrimRight(fmt.Sprintf("%0*s", tokenDecimals, frac.String()), "0")
+	return fmt.Sprintf("%s.%s", whole, fracStr)
+}
+
+func stakeSubjectType(cmd *cobra.Command) uint8 {
+	if delegate, _ := cmd.Flags().GetBool("delegate"); delegate {
+		return registry.SubjectTypeDelegatorScannerPool
+	}
+	return registry.SubjectTypeScannerPool
+}
+
+func handleZktoroStakeDeposit(cmd *cobra.Command, args []string) error {
+	poolID, err := parsePoolID(cmd)
+	if err != nil {
+		return err
+	}
+	amountStr, _ := cmd.Flags().GetString("amount")
+	amount, err := parseTokenAmount(amountStr)
+	if err != nil {
+		return err
+	}
+	subjectType := stakeSubjectType(cmd)
+
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+
+	tokenAddr, err := contracts.ZktoroStaking.StakedToken(&bind.CallOpts{Context: cmd.Context()})
+	if err != nil {
+		return fmt.Errorf("failed to get the staked token: %v", err)
+	}
+	token, err := contract_zktoro.NewZktoroCaller(tokenAddr, sender.ec)
+	if err != nil {
+		return err
+	}
+	tokenTx, err := contract_zktoro.NewZktoroTransactor(tokenAddr, sender.ec)
+	if err != nil {
+		return err
+	}
+
+	callOpts := &bind.CallOpts{Context: cmd.Context()}
+	balance, err := token.BalanceOf(callOpts, sender.from)
+	if err != nil {
+		return fmt.Errorf("failed to get the token balance: %v", err)
+	}
+	if balance.Cmp(amount) < 0 && !sender.dryRun {
+		return fmt.Errorf("insufficient balance: %s < %s", formatTokenAmount(balance), amountStr)
+	}
+	allowance, err := token.Allowance(callOpts, sender.from, contracts.Addresses.ZktoroStaking)
+	if err != nil {
+		return fmt.Errorf("failed to get the token allowance: %v", err)
+	}
+	if allowance.Cmp(amount) < 0 {
+		if sender.dryRun {
+			yellowBold("The staking contract needs to be approved first so the deposit estimation below may fail.\n\n")
+		}
+		if _, err := sender.Send(cmd.Context(), "Approve staking contract", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return tokenTx.Approve(opts, contracts.Addresses.ZktoroStaking, amount)
+		}); err != nil {
+			return err
+		}
+	}
+
+	receipt, err := sender.Send(cmd.Context(), "Deposit stake", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ZktoroStakingTx.Deposit(opts, subjectType, poolID, amount)
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	greenBold("Deposited %s on pool %s\n", amountStr, poolID)
+	return nil
+}
+
+func handleZktoroStakeInitiateWithdrawal(cmd *cobra.Command, args []string) error {
+	poolID, err := parsePoolID(cmd)
+	if err != nil {
+		return err
+	}
+	subjectType := stakeSubjectType(cmd)
+
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+
+	var shares *big.Int
+	if sharesStr, _ := cmd.Flags().GetString("shares"); len(sharesStr) > 0 {
+		shares, err = parseTokenAmount(sharesStr)
+	} else {
+		shares, err = contracts.ZktoroStaking.SharesOf(&bind.CallOpts{Context: cmd.Context()}, subjectType, poolID, sender.from)
+	}
+	if err != nil {
+		return err
+	}
+	if shares.Sign() == 0 {
+		return errors.New("no active shares to withdraw")
+	}
+
+	receipt, err := sender.Send(cmd.Context(), "Initiate withdrawal", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ZktoroStakingTx.InitiateWithdrawal(opts, subjectType, poolID, shares)
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	greenBold("Initiated the withdrawal of %s shares from pool %s\n", formatTokenAmount(shares), poolID)
+	whiteBold("Please run 'zktoro stake withdraw' when the withdrawal delay is over.\n")
+	return nil
+}
+
+func handleZktoroStakeWithdraw(cmd *cobra.Command, args []string) error {
+	poolID, err := parsePoolID(cmd)
+	if err != nil {
+		return err
+	}
+	subjectType := stakeSubjectType(cmd)
+
+	sender, err := newTxSender(cmd)
+	if err != nil {
+		return err
+	}
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+
+	receipt, err := sender.Send(cmd.Context(), "Withdraw stake", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ZktoroStakingTx.Withdraw(opts, subjectType, poolID)
+	})
+	if err != nil || receipt == nil {
+		return err
+	}
+	greenBold("Withdrew the inactive stake from pool %s\n", poolID)
+	return nil
+}
+
+func handleZktoroStakeShow(cmd *cobra.Command, args []string) error {
+	poolID, err := parsePoolID(cmd)
+	if err != nil {
+		return err
+	}
+	staker, _ := cmd.Flags().GetString("staker")
+	if len(staker) > 0 && !common.IsHexAddress(staker) {
+		return fmt.Errorf("invalid staker address: %s", staker)
+	}
+
+	regClient, err := newTxRegistryClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+	contracts := regClient.Contracts()
+	opts := &bind.CallOpts{Context: cmd.Context()}
+
+	owner, err := regClient.GetScannerPoolOwner(poolID)
+	if err != nil {
+		return fmt.Errorf("failed to get the pool owner: %v", err)
+	}
+	activeStake, err := regClient.GetActivePoolStake(nil, poolID)
+	if err != nil {
+		return fmt.Errorf("failed to get the active pool stake: %v", err)
+	}
+	perManaged, err := regClient.GetAllocatedStakePerManaged(nil, poolID)
+	if err != nil {
+		return fmt.Errorf("failed to get the allocated stake per scanner: %v", err)
+	}
+	scannerCount, err := contracts.ScannerPoolReg.TotalScannersRegistered(opts, poolID)
+	if err != nil {
+		return fmt.Errorf("failed to get the scanner count: %v", err)
+	}
+	threshold, err := contracts.ScannerPoolReg.GetManagedStakeThreshold(opts, poolID)
+	if err != nil {
+		return fmt.Errorf("failed to get the stake threshold: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Pool\t%s\n", poolID)
+	fmt.Fprintf(w, "Owner\t%s\n", owner)
+	fmt.Fprintf(w, "Scanners\t%s\n", scannerCount)
+	fmt.Fprintf(w, "Active stake\t%s\n", formatTokenAmount(activeStake))
+	fmt.Fprintf(w, "Allocated stake per scanner\t%s\n", formatTokenAmount(perManaged))
+	fmt.Fprintf(w, "Stake threshold per scanner\tmin %s, max %s\n", formatTokenAmount(threshold.Min), formatTokenAmount(threshold.Max))
+
+	for _, subject := range []struct {
+		name string
+		typ  uint8
+	}{
+		{name: "Owner", typ: registry.SubjectTypeScannerPool},
+		{name: "Delegated", typ: registry.SubjectTypeDelegatorScannerPool},
+	} {
+		allocated, err := contracts.StakeAllocator.AllocatedStakeFor(opts, subject.typ, poolID)
+		if err != nil {
+			return fmt.Errorf("failed to get the allocated stake: %v", err)
+		}
+		unallocated, err := contracts.StakeAllocator.UnallocatedStakeFor(opts, subject.typ, poolID)
+		if err != nil {
+			return fmt.Errorf("failed to get the unallocated stake: %v", err)
+		}
+		fmt.Fprintf(w, "%s stake\tallocated %s, unallocated %s\n", subject.name, formatTokenAmount(allocated), formatTokenAmount(unallocated))
+
+		if len(staker) == 0 {
+			continue
+		}
+		shares, err := contracts.ZktoroStaking.SharesOf(opts, subject.typ, poolID, common.HexToAddress(staker))
+		if err != nil {
+			return fmt.Errorf("failed to get the active shares: %v", err)
+		}
+		inactiveShares, err := contracts.ZktoroStaking.InactiveSharesOf(opts, subject.typ, poolID, common.HexToAddress(staker))
+		if err != nil {
+			return fmt.Errorf("failed to get the inactive shares: %v", err)
+		}
+		fmt.Fprintf(w, "%s shares of %s\tactive %s, inactive %s\n", subject.name, staker, formatTokenAmount(shares), formatTokenAmount(inactiveShares))
+	}
+	return w.Flush()
+}
@@ -2,21 +2,31 @@ package cmd
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"strconv"
 	"time"
 
+	"zktoro/config"
 	"zktoro/store"
 
 	"zktoro/zktoro-core-go/registry"
 	"zktoro/zktoro-core-go/security"
 	"zktoro/zktoro-core-go/security/eip712"
 
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -35,38 +45,43 @@ func handlezktoroAuthorizePool(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
 	clean, _ := cmd.Flags().GetBool("clean")
 
-	scannerKey, err := security.LoadKeyWithPassphrase(cfg.KeyDirPath, cfg.Passphrase)
+	signer, err := loadScannerSigner()
 	if err != nil {
-		return fmt.Errorf("failed to load scanner key: %v", err)
+		return fmt.Errorf("failed to load scanner signer: %v", err)
 	}
-	scannerPrivateKey := scannerKey.PrivateKey
 
 	fmt.Println("ENSConfig ", cfg.ENSConfig.ContractAddress)
 	fmt.Println("JsonRpcUrl ", cfg.Registry.JsonRpc.Url)
-	fmt.Println("scannerPrivateKey ", scannerPrivateKey)
 
 	regClient, err := store.GetRegistryClientWithoutENS(context.Background(), cfg, registry.ClientConfig{
 		JsonRpcUrl: cfg.Registry.JsonRpc.Url,
 		ENSAddress: cfg.ENSConfig.ContractAddress,
 		Name:       "registry-client",
-		PrivateKey: scannerPrivateKey,
+		Signer:     signer,
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to create registry client: %v", err)
 	}
 
-	return authorizePoolWithRegistry(regClient, scannerKey, poolID, polygonscan, force, clean)
+	return authorizePoolWithRegistry(regClient, signer, poolID, polygonscan, force, clean)
+}
+
+// loadScannerSigner loads the remote signer if it is configured or the scanner key from the keystore.
+func loadScannerSigner() (security.Signer, error) {
+	return config.LoadSigner(cfg, func() (*keystore.Key, error) {
+		return security.LoadKeyWithPassphrase(cfg.KeyDirPath, cfg.Passphrase)
+	})
 }
 
 func authorizePoolWithRegistry(
 	regClient registry.Client,
-	scannerKey *keystore.Key,
+	signer security.Signer,
 	poolID int64, polygonscan, force, clean bool,
 ) error {
 	regClient.SetRegistryChainID(cfg.Registry.ChainID)
 
-	scanner, err := regClient.GetPoolScanner(scannerKey.Address.Hex())
+	scanner, err := regClient.GetPoolScanner(signer.Address().Hex())
 	if err != nil {
 		return fmt.Errorf("failed to get scanner from registry: %v", err)
 	}
@@ -87,7 +102,7 @@ func authorizePoolWithRegistry(
 
 	ts := time.Now().Unix()
 	regInfo, err := regClient.GenerateScannerRegistrationSignature(&eip712.ScannerNodeRegistration{
-		Scanner:       scannerKey.Address,
+		Scanner:       signer.Address(),
 		ScannerPoolId: big.NewInt(poolID),
 		ChainId:       big.NewInt(int64(cfg.ChainID)),
 		Metadata:      "",
@@ -95,7 +110,7 @@ func authorizePoolWithRegistry(
 	})
 
 	fmt.Println("###")
-	fmt.Println("Scanner: ", scannerKey.Address.Hex())
+	fmt.Println("Scanner: ", signer.Address().Hex())
 	fmt.Println("ScannerPoolId: ", big.NewInt(poolID))
 	fmt.Println("ChainId: ", big.NewInt(int64(cfg.ChainID)))
 	fmt.Println("Timestamp: ", big.NewInt(ts))
@@ -119,7 +134,7 @@ func authorizePoolWithRegistry(
 
 	if polygonscan {
 		whiteBold("Please use the registerScannerNode() inputs below on https://polygonscan.com as soon as possible and do not share with anyone!\n\n")
-		color.New(color.FgYellow).Println("req      :", makeArgsTuple(scannerKey.Address.Hex(), poolID, cfg.ChainID, ts))
+		color.New(color.FgYellow).Println("req      :", makeArgsTuple(signer.Address().Hex(), poolID, cfg.ChainID, ts))
 		color.New(color.FgYellow).Println("signature:", regInfo.Signature)
 	} else {
 		whiteBold("Please use the registration signature below on https://app.zktoro.network as soon as possible and do not share with anyone!\n\n")
@@ -145,3 +160,200 @@ func makeArgsTuple(scannerAddr string, poolID int64, chainID int, ts int64) stri
 	b, _ := json.Marshal(tuple)
 	return string(b)
 }
+
+const (
+	defaultTxConfirmations = 2
+	defaultTxTimeout       = 5 * time.Minute
+	// gasLimitBufferPercent is added on top of the estimated gas so that the small state changes
+	// between the estimation and the inclusion do not make the transaction run out of gas.
+	gasLimitBufferPercent    = 20
+	confirmationPollInterval = 2 * time.Second
+)
+
+// addTxFlags adds the flags of the commands which send transactions.
+func addTxFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("keyfile", "", "encrypted keystore file of the wallet which sends the transactions")
+	cmd.PersistentFlags().String("passphrase", "", "passphrase of the keystore file (default: the zktoro passphrase)")
+	cmd.PersistentFlags().String("from", "", "sender address to use in dry runs when there is no keyfile")
+	cmd.PersistentFlags().Bool("dry-run", false, "estimate the gas and print the calldata without sending the transactions")
+	cmd.PersistentFlags().Uint64("confirmations", defaultTxConfirmations, "number of confirmations to wait for")
+	cmd.PersistentFlags().Duration("timeout", defaultTxTimeout, "max duration to wait for each transaction")
+}
+
+// txSender estimates, sends and tracks the transactions of the commands.
+type txSender struct {
+	ec      *ethclient.Client
+	chainID *big.Int
+	from    common.Address
+	key     *ecdsa.PrivateKey
+
+	dryRun        bool
+	confirmations uint64
+	timeout       time.Duration
+}
+
+func newTxSender(cmd *cobra.Command) (*txSender, error) {
+	keyFile, _ := cmd.Flags().GetString("keyfile")
+	passphrase, _ := cmd.Flags().GetString("passphrase")
+	from, _ := cmd.Flags().GetString("from")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	confirmations, _ := cmd.Flags().GetUint64("confirmations")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	sender := &txSender{
+		dryRun:        dryRun,
+		confirmations: confirmations,
+		timeout:       timeout,
+	}
+
+	switch {
+	case len(keyFile) > 0:
+		if len(passphrase) == 0 {
+			passphrase = cfg.Passphrase
+		}
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the keyfile: %v", err)
+		}
+		key, err := keystore.DecryptKey(b, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the keyfile: %v", err)
+		}
+		sender.key = key.PrivateKey
+		sender.from = key.Address
+	case !dryRun:
+		return nil, errors.New("please provide the --keyfile of the wallet which sends the transactions or use --dry-run")
+	case len(from) > 0:
+		if !common.IsHexAddress(from) {
+			return nil, fmt.Errorf("invalid sender address: %s", from)
+		}
+		sender.from = common.HexToAddress(from)
+	}
+
+	ec, err := ethclient.DialContext(cmd.Context(), cfg.Registry.JsonRpc.Url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the registry json-rpc api: %v", err)
+	}
+	sender.ec = ec
+	sender.chainID, err = ec.ChainID(cmd.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the registry chain id: %v", err)
+	}
+	return sender, nil
+}
+
+// Send estimates the gas of the transaction and sends it unless it is a dry run. It returns
+// a nil receipt in dry runs.
+func (s *txSender) Send(
+	ctx context.Context, name string, makeTx func(*bind.TransactOpts) (*types.Transaction, error),
+) (*types.Receipt, error) {
+	// build the transaction without signing and sending to see the calldata
+	unsigned, err := makeTx(&bind.TransactOpts{
+		From:     s.from,
+		Context:  ctx,
+		GasLimit: 1, // skips the estimation in the binding
+		NoSend:   true,
+		Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare %s: %v", name, err)
+	}
+
+	whiteBold("%s\n", name)
+	fmt.Printf("  from: %s\n", s.from.Hex())
+	fmt.Printf("  to:   %s\n", unsigned.To().Hex())
+	gas, estimateErr := s.ec.EstimateGas(ctx, ethereum.CallMsg{
+		From:  s.from,
+		To:    unsigned.To(),
+		Value: unsigned.Value(),
+		Data:  unsigned.Data(),
+	})
+	if estimateErr == nil {
+		fmt.Printf("  estimated gas: %d\n", gas)
+	} else {
+		estimateErr = withRevertData(estimateErr)
+	}
+
+	if s.dryRun {
+		if estimateErr != nil {
+			yellowBold("  gas estimation failed (the transaction would revert): %v\n", estimateErr)
+		}
+		fmt.Printf("  calldata: %s\n\n", hexutil.Encode(unsigned.Data()))
+		return nil, nil
+	}
+	if estimateErr != nil {
+		return nil, fmt.Errorf("%s would fail: %v", name, estimateErr)
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(s.key, s.chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	opts.GasLimit = gas + gas*gasLimitBufferPercent/100
+	tx, err := makeTx(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s: %v", name, err)
+	}
+	fmt.Printf("  tx hash: %s\n", tx.Hash().Hex())
+
+	return s.waitConfirmations(ctx, tx)
+}
+
+// withRevertData adds the revert data to the error so that the custom errors of the contracts can be
+// decoded.
+func withRevertData(err error) error {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok && len(data) > 0 {
+			return fmt.Errorf("%v: %s", err, data)
+		}
+	}
+	return err
+}
+
+// waitConfirmations waits until the transaction is mined and has enough blocks on top of it.
+func (s *txSender) waitConfirmations(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	receipt, err := bind.WaitMined(ctx, s.ec, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed while waiting for the transaction to be mined: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s reverted in block %s", tx.Hash().Hex(), receipt.BlockNumber)
+	}
+
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+	var lastConfirmations uint64
+	for {
+		head, err := s.ec.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest block: %v", err)
+		}
+		var confirmations uint64
+		if head >= receipt.BlockNumber.Uint64() {
+			confirmations = head - receipt.BlockNumber.Uint64() + 1
+		}
+		if confirmations > s.confirmations {
+			confirmations = s.confirmations
+		}
+		if confirmations != lastConfirmations {
+			fmt.Printf("  confirmations: %d/%d (block %s)\n", confirmations, s.confirmations, receipt.BlockNumber)
+			lastConfirmations = confirmations
+		}
+		if confirmations >= s.confirmations {
+			greenBold("  confirmed!\n\n")
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out while waiting for the confirmations of %s", tx.Hash().Hex())
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,101 +1,65 @@
 package cmd
 
 import (
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
+	"time"
+
+	"zktoro/services/holder"
 
-	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
-	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	jsonld "github.com/piprate/json-gold/ld"
 	"github.com/spf13/cobra"
 )
 
-type VC struct {
-	Issuer_Public_Key string `json:"issuer_public_key"`
-	Holder_DID        string `json:"holder_did"`
-	VC                string `json:"vc"`
-}
-
-func signVP() error {
-	vcData, err := os.ReadFile(cfg.VcPath)
-
-	// claims := jwt.MapClaims{}
-	// token, err := jwt.ParseWithClaims(string(dat), claims, func(token *jwt.Token) (interface{}, error) {
-	// 	return []byte("<YOUR VERIFICATION KEY>"), nil
-	// })
-	// // ... error handling
-	// if token == nil {
-	// 	fmt.Println(err)
-	// }
-	var vcJson VC
-	err = json.Unmarshal(vcData, &vcJson)
+func newCredentialHolder() (*holder.Holder, error) {
+	key, err := loadNodeDIDKey()
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return nil, err
 	}
-	issuerPublicKey := vcJson.Issuer_Public_Key
-	// builder := verifiable.NewCredentialSchemaLoaderBuilder()
-
-	// factory := gojsonschema.DefaultJSONLoaderFactory{}
-
-	// builder.SetJSONLoader(factory.New("proofOfName.json"))
-
-	documentLoader := jsonld.NewDefaultDocumentLoader(http.DefaultClient)
-	fmt.Println("VC:")
-	fmt.Println(vcJson.VC)
-	fmt.Println("issuer pk: ", vcJson.Issuer_Public_Key)
-	fmt.Println("holder did: ", vcJson.Holder_DID)
-	vc, err3 := verifiable.ParseCredential([]byte(vcJson.VC),
-
-		verifiable.WithPublicKeyFetcher(verifiable.SingleKey([]byte(issuerPublicKey), kms.ED25519)),
-		verifiable.WithJSONLDDocumentLoader(documentLoader),
-		verifiable.WithDisabledProofCheck(),
-		// verifiable.WithCredentialSchemaLoader(builder.Build()),
-	)
-
-	if err3 != nil {
-		return err3
+	issuerKeys := make(map[string]string)
+	for _, issuer := range cfg.Credentials.TrustedIssuers {
+		issuerKeys[issuer.DID] = issuer.PublicKey
 	}
-	vp, err := verifiable.NewPresentation(verifiable.WithCredentials(vc))
-
+	trustedIssuers, err := holder.NewTrustedIssuers(issuerKeys)
 	if err != nil {
-		panic(fmt.Errorf("failed to build VP from VC: %w", err))
+		return nil, fmt.Errorf("invalid trusted issuers: %w", err)
 	}
 
-	vp.Holder = vcJson.Holder_DID
-	jwtClaims, err := vp.JWTClaims(nil, true)
-	if err != nil {
-		panic(fmt.Errorf("failed to create JWT claims of VP: %w", err))
-	}
+	documentLoader := holder.NewDocumentLoader()
+	statusChecker := holder.NewStatusListChecker(
+		documentLoader, cfg.Credentials.StatusListHosts,
+		time.Duration(cfg.Credentials.StatusListTimeoutSeconds)*time.Second,
+	)
+	return holder.NewHolder(
+		&holder.HolderKey{
+			DID:        key.DID,
+			KeyID:      key.KeyID,
+			PrivateKey: key.PrivateKey,
+		},
+		holder.NewFileCredentialStore(cfg.CredentialsPath),
+		holder.NewVerifier(documentLoader, statusChecker),
+		trustedIssuers,
+	), nil
+}
+
+func handleZktoroSignVp(cmd *cobra.Command, args []string) error {
+	nonce, _ := cmd.Flags().GetString("nonce")
+	audience, _ := cmd.Flags().GetStringSlice("audience")
+	credentialIDs, _ := cmd.Flags().GetStringSlice("id")
 
-	prvKeyBytes, err := os.ReadFile(cfg.DIDKeyPath)
+	credentialHolder, err := newCredentialHolder()
 	if err != nil {
-		panic(fmt.Errorf("failed to read node private key: %w", err))
+		return err
 	}
-	privateKey := prvKeyBytes
-	// fmt.Println("Private Key: ")
-	// fmt.Println(hex.EncodeToString(prvKeyBytes))
-	// fmt.Println("Public Key: ")
-	// fmt.Println(hex.EncodeToString(prvKeyBytes)[64:])
-	publicKey, _ := hex.DecodeString(hex.EncodeToString(prvKeyBytes)[64:])
-	signer := signature.GetEd25519Signer(privateKey, publicKey)
-
-	jws, err := jwtClaims.MarshalJWS(verifiable.EdDSA, signer, "")
+	vp, err := credentialHolder.Present(&holder.PresentationRequest{
+		Nonce:         nonce,
+		Audience:      audience,
+		CredentialIDs: credentialIDs,
+	})
 	if err != nil {
-		panic(fmt.Errorf("failed to sign VP inside JWT: %w", err))
+		return fmt.Errorf("failed to sign vp: %w", err)
 	}
 
-	fmt.Println(jws)
-	fmt.Println("\033[32m", "VP signed", "\033[0m")
-	_ = os.WriteFile(cfg.VpPath, []byte(jws), 0644)
-	return nil
-}
-
-func handleZktoroSignVp(cmd *cobra.Command, args []string) error {
-	return signVP()
-	// subject := claims["sub"]
+	fmt.Println(vp)
+	greenBold("VP signed\n")
+	return os.WriteFile(cfg.VpPath, []byte(vp), 0600)
 }
@@ -14,7 +14,6 @@ import (
 	"zktoro/zktoro-core-go/inspect"
 	"zktoro/zktoro-core-go/inspect/scorecalc"
 	"zktoro/zktoro-core-go/protocol/settings"
-	"zktoro/zktoro-core-go/security"
 )
 
 var nodeConfig config.Config
@@ -22,7 +21,7 @@ var nodeConfig config.Config
 func initServices(ctx context.Context, cfg config.Config) ([]services.Service, error) {
 	nodeConfig = cfg
 
-	key, err := security.LoadKey(config.DefaultContainerKeyDirPath)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +30,7 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 		Config:         cfg,
 		ProxyHost:      config.DockerJSONRPCProxyContainerName,
 		ProxyPort:      config.DefaultJSONRPCProxyPort,
-		ScannerAddress: key.Address.String(),
+		ScannerAddress: signer.Address().String(),
 	})
 	if err != nil {
 		return nil, err
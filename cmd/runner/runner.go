@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"os"
 
-	"zktoro/clients/docker"
+	"zktoro/clients"
 	"zktoro/config"
 	"zktoro/services"
 	"zktoro/services/runner"
@@ -20,11 +20,11 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the image store: %v", err)
 	}
-	dockerClient, err := docker.NewDockerClient("runner")
+	dockerClient, err := clients.NewContainerRuntime(cfg.ContainerRuntime, "runner")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the docker client: %v", err)
 	}
-	globalDockerClient, err := docker.NewDockerClient("")
+	globalDockerClient, err := clients.NewContainerRuntime(cfg.ContainerRuntime, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the docker client: %v", err)
 	}
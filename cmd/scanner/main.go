@@ -18,7 +18,6 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	gethlog "github.com/ethereum/go-ethereum/log"
 
 	"zktoro/clients"
@@ -31,6 +30,7 @@ import (
 	"zktoro/zktoro-core-go/clients/health"
 	"zktoro/zktoro-core-go/ethereum"
 	"zktoro/zktoro-core-go/feeds"
+	"zktoro/zktoro-core-go/security"
 	"zktoro/zktoro-core-go/utils"
 )
 
@@ -92,6 +92,7 @@ func initTxStream(ctx context.Context, ethClient, traceClient ethereum.Client, c
 		Offset:              getBlockOffset(cfg),
 		Start:               startBlock,
 		End:                 stopBlock,
+		ReorgWindow:         cfg.Scan.ReorgWindow,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -153,7 +154,9 @@ func getBlockOffset(cfg config.Config) int {
 	return chainSettings.DefaultOffset
 }
 
-func initCombinationStream(ctx context.Context, msgClient clients.MessageClient, cfg config.Config) (*scanner.CombinerAlertStreamService, feeds.AlertFeed, error) {
+func initCombinationStream(
+	ctx context.Context, msgClient clients.MessageClient, publisherSvc *publisher.Publisher, cfg config.Config,
+) (*scanner.CombinerAlertStreamService, feeds.AlertFeed, error) {
 	combinerFeed, err := feeds.NewCombinerFeed(
 		ctx, feeds.CombinerFeedConfig{
 			APIUrl:            cfg.CombinerConfig.AlertAPIURL,
@@ -167,8 +170,26 @@ func initCombinationStream(ctx context.Context, msgClient clients.MessageClient,
 		return nil, nil, fmt.Errorf("failed to create combiner feed: %v", err)
 	}
 
+	// the alerts of the bots on this node are pushed to the subscribers without going through the api
+	loopbackFeed := feeds.NewLoopbackFeed(ctx, combinerFeed)
+	publisherSvc.AddAlertListener(loopbackFeed.Publish)
+	msgClient.Subscribe(messaging.SubjectAgentsStatusRunning, messaging.AgentsHandler(
+		func(payload messaging.AgentPayload) error {
+			bots := make([]feeds.LocalBot, 0, len(payload))
+			for _, botConfig := range payload {
+				bot := feeds.LocalBot{ID: botConfig.ID}
+				if botConfig.ShardConfig != nil {
+					bot.Shards = botConfig.ShardConfig.Shards
+				}
+				bots = append(bots, bot)
+			}
+			loopbackFeed.SetLocalBots(bots)
+			return nil
+		},
+	))
+
 	combinerStream, err := scanner.NewCombinerAlertStreamService(
-		ctx, combinerFeed, msgClient, scanner.CombinerAlertStreamServiceConfig{
+		ctx, loopbackFeed, msgClient, scanner.CombinerAlertStreamServiceConfig{
 			Start: cfg.LocalModeConfig.RuntimeLimits.StartCombiner,
 			End:   cfg.LocalModeConfig.RuntimeLimits.StopCombiner,
 		},
@@ -207,7 +228,7 @@ func initCombinationStream(ctx context.Context, msgClient clients.MessageClient,
 		services.TriggerExit(delay)
 	}()
 
-	return combinerStream, combinerFeed, nil
+	return combinerStream, loopbackFeed, nil
 }
 
 func initTxAnalyzer(
@@ -252,14 +273,14 @@ func initCombinerAlertAnalyzer(
 	)
 }
 
-func initAlertSender(ctx context.Context, key *keystore.Key, pubClient clients.PublishClient, cfg config.Config) (clients.AlertSender, error) {
+func initAlertSender(ctx context.Context, signer security.Signer, pubClient clients.PublishClient, cfg config.Config) (clients.AlertSender, error) {
 	ds, err := store.NewDeduplicationStore(cfg)
 	if err != nil {
 		return nil, err
 	}
 	return clients.NewAlertSender(ctx, pubClient, clients.AlertSenderConfig{
-		Key: key,
-		DS:  ds,
+		Signer: signer,
+		DS:     ds,
 	})
 }
 
@@ -267,6 +288,12 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 	// can't dial localhost - need to dial host gateway from container
 	cfg.Scan.JsonRpc.Url = utils.ConvertToDockerHostURL(cfg.Scan.JsonRpc.Url)
 	cfg.Trace.JsonRpc.Url = utils.ConvertToDockerHostURL(cfg.Trace.JsonRpc.Url)
+	for i := range cfg.Scan.JsonRpc.Endpoints {
+		cfg.Scan.JsonRpc.Endpoints[i].Url = utils.ConvertToDockerHostURL(cfg.Scan.JsonRpc.Endpoints[i].Url)
+	}
+	for i := range cfg.Trace.JsonRpc.Endpoints {
+		cfg.Trace.JsonRpc.Endpoints[i].Url = utils.ConvertToDockerHostURL(cfg.Trace.JsonRpc.Endpoints[i].Url)
+	}
 	cfg.Registry.JsonRpc.Url = utils.ConvertToDockerHostURL(cfg.Registry.JsonRpc.Url)
 	cfg.Registry.IPFS.APIURL = utils.ConvertToDockerHostURL(cfg.Registry.IPFS.APIURL)
 	cfg.Registry.IPFS.GatewayURL = utils.ConvertToDockerHostURL(cfg.Registry.IPFS.GatewayURL)
@@ -278,7 +305,7 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 	cfg.PublicAPIProxy.Url = utils.ConvertToDockerHostURL(cfg.PublicAPIProxy.Url)
 	msgClient := messaging.NewClient("scanner", fmt.Sprintf("%s:%s", config.DockerNatsContainerName, config.DefaultNatsPort))
 
-	key, err := config.LoadKeyInContainer(cfg)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -287,18 +314,19 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to create publisher: %v", err)
 	}
+	services.SubscribeToConfigChanges(msgClient, "scanner", publisherSvc.ReloadConfig)
 
-	alertSender, err := initAlertSender(ctx, key, publisherSvc, cfg)
+	alertSender, err := initAlertSender(ctx, signer, publisherSvc, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize alert sender: %v", err)
 	}
 
-	ethClient, err := ethereum.NewStreamEthClient(ctx, "chain", cfg.Scan.JsonRpc.Url)
+	ethClient, err := ethereum.NewFailoverStreamEthClient(ctx, "chain", cfg.Scan.JsonRpc.EthEndpoints(), cfg.Scan.JsonRpc.FailoverOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stream eth client: %v", err)
 	}
 
-	traceClient, err := ethereum.NewStreamEthClient(ctx, "trace", cfg.Trace.JsonRpc.Url)
+	traceClient, err := ethereum.NewFailoverStreamEthClient(ctx, "trace", cfg.Trace.JsonRpc.EthEndpoints(), cfg.Trace.JsonRpc.FailoverOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create trace stream eth client: %v", err)
 	}
@@ -341,7 +369,7 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 		blockFeed.Start()
 	}
 
-	combinationStream, combinationFeed, err := initCombinationStream(ctx, msgClient, cfg)
+	combinationStream, combinationFeed, err := initCombinationStream(ctx, msgClient, publisherSvc, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize combiner stream: %v", err)
 	}
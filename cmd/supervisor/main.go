@@ -27,23 +27,23 @@ func initServices(ctx context.Context, cfg config.Config) ([]services.Service, e
 	if err != nil {
 		return nil, err
 	}
-	key, err := security.LoadKey(config.DefaultContainerKeyDirPath)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
-	botRegistry, err := registry.New(cfg, key.Address)
+	botRegistry, err := registry.New(cfg, signer.Address())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the bot registry: %v", err)
 	}
 	botLifecycleConfig := components.BotLifecycleConfig{
 		Config:         cfg,
-		ScannerAddress: key.Address,
+		ScannerAddress: signer.Address(),
 		BotRegistry:    botRegistry,
 	}
 	svc, err := supervisor.NewSupervisorService(ctx, supervisor.SupervisorServiceConfig{
 		Config:             cfg,
 		Passphrase:         passphrase,
-		Key:                key,
+		Signer:             signer,
 		BotLifecycleConfig: botLifecycleConfig,
 	})
 	if err != nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"hash/fnv"
 	"strconv"
 	"strings"
 
@@ -26,12 +27,112 @@ type AgentConfig struct {
 
 	ChainID     int
 	ShardConfig *ShardConfig
+	Queue       *QueueConfig  `yaml:"queue" json:"queue,omitempty"`
+	Resources   *BotResources `yaml:"resources" json:"resources,omitempty"`
 }
 
 type ShardConfig struct {
-	ShardID uint `yaml:"shardId" json:"shardId"`
-	Shards  uint `yaml:"shards" json:"shards"`
-	Target  uint `yaml:"target" json:"target"`
+	ShardID  uint          `yaml:"shardId" json:"shardId"`
+	Shards   uint          `yaml:"shards" json:"shards"`
+	Target   uint          `yaml:"target" json:"target"`
+	Strategy ShardStrategy `yaml:"strategy" json:"strategy,omitempty"`
+}
+
+// ShardStrategy decides which shard processes a tx. The blocks are always sharded by their numbers.
+type ShardStrategy string
+
+// Shard strategies
+const (
+	// ShardStrategyBlock sends all of the txs of a block to the shard of the block. This is the default.
+	ShardStrategyBlock ShardStrategy = "block"
+	// ShardStrategyTxHash spreads the txs of each block over the shards by their hashes.
+	ShardStrategyTxHash ShardStrategy = "txHash"
+	// ShardStrategyToAddress always sends the txs to the same address to the same shard. The contract
+	// creations are routed by their senders.
+	ShardStrategyToAddress ShardStrategy = "toAddress"
+	// ShardStrategyFromAddress always sends the txs from the same address to the same shard.
+	ShardStrategyFromAddress ShardStrategy = "fromAddress"
+)
+
+// IsValid tells if this is a known strategy. The empty strategy is the default one.
+func (strategy ShardStrategy) IsValid() bool {
+	switch strategy {
+	case "", ShardStrategyBlock, ShardStrategyTxHash, ShardStrategyToAddress, ShardStrategyFromAddress:
+		return true
+	default:
+		return false
+	}
+}
+
+// BlockShard returns the shard which should process the block.
+func (sc *ShardConfig) BlockShard(blockNumber uint64) uint {
+	return uint(blockNumber % uint64(sc.Shards))
+}
+
+// TxShard returns the shard which should process the tx.
+func (sc *ShardConfig) TxShard(blockNumber uint64, txHash, from, to string) uint {
+	switch sc.Strategy {
+	case ShardStrategyTxHash:
+		return uint(hashKey(txHash) % uint64(sc.Shards))
+	case ShardStrategyToAddress:
+		if to == "" {
+			to = from
+		}
+		return uint(jumpHash(hashKey(to), int32(sc.Shards)))
+	case ShardStrategyFromAddress:
+		return uint(jumpHash(hashKey(from), int32(sc.Shards)))
+	default:
+		return sc.BlockShard(blockNumber)
+	}
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(key)))
+	return h.Sum64()
+}
+
+// jumpHash is the jump consistent hash by Lamping and Veach. It moves only 1/n of the keys
+// when the bucket count grows to n so that most of the addresses stay on the same shard.
+func jumpHash(key uint64, buckets int32) int32 {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int32(b)
+}
+
+// QueuePolicy decides what happens to a new request when the request queue of a bot is full.
+type QueuePolicy string
+
+// Queue policies
+const (
+	// QueuePolicyDropNewest drops the new request. This is the default.
+	QueuePolicyDropNewest QueuePolicy = "dropNewest"
+	// QueuePolicyDropOldest drops the oldest request in the queue to make room for the new one.
+	QueuePolicyDropOldest QueuePolicy = "dropOldest"
+	// QueuePolicyBlock waits for room in the queue until the timeout and then drops the new request.
+	QueuePolicyBlock QueuePolicy = "block"
+	// QueuePolicySpill writes the requests to the disk while the queue is full and replays them later.
+	QueuePolicySpill QueuePolicy = "spill"
+)
+
+// QueueConfig configures the request queues of a bot. The zero values mean the defaults.
+type QueueConfig struct {
+	Policy         QueuePolicy `yaml:"policy" json:"policy,omitempty" validate:"omitempty,oneof=dropNewest dropOldest block spill"`
+	Size           int         `yaml:"size" json:"size,omitempty" validate:"min=0"`
+	BlockTimeoutMs int         `yaml:"blockTimeoutMs" json:"blockTimeoutMs,omitempty" validate:"min=0"`
+	MaxSpilled     int         `yaml:"maxSpilled" json:"maxSpilled,omitempty" validate:"min=0"`
+}
+
+// Equal tells if the queue configs are the same.
+func (qc *QueueConfig) Equal(b *QueueConfig) bool {
+	if qc == nil || b == nil {
+		return qc == b
+	}
+	return *qc == *b
 }
 
 func (ac AgentConfig) ShardID() int32 {
@@ -57,6 +158,11 @@ func (ac AgentConfig) Equal(b AgentConfig) bool {
 		return false
 	}
 
+	// queues cannot be resized so a different queue config needs a new bot client
+	if !ac.Queue.Equal(b.Queue) {
+		return false
+	}
+
 	// if both don't have sharding config, then they are the same
 	if ac.ShardConfig == nil && b.ShardConfig == nil {
 		return true
@@ -75,8 +181,9 @@ func (ac AgentConfig) Equal(b AgentConfig) bool {
 
 	sameShardID := ac.ShardConfig.ShardID == b.ShardConfig.ShardID
 	sameShardCount := ac.ShardConfig.Shards == b.ShardConfig.Shards
+	sameStrategy := ac.ShardConfig.Strategy == b.ShardConfig.Strategy
 
-	return sameShardID && sameShardCount
+	return sameShardID && sameShardCount && sameStrategy
 }
 
 // IsSharded tells if this is a sharded bot.
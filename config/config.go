@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
+	"time"
 
+	"zktoro/zktoro-core-go/ethereum"
 	"zktoro/zktoro-core-go/protocol/settings"
 
 	"github.com/creasty/defaults"
@@ -13,12 +16,45 @@ import (
 
 type PublicAPIProxyConfig struct {
 	Url             string            `yaml:"url" json:"url" validate:"omitempty,url" default:"https://api.zktoro.network"`
-	Headers         map[string]string `yaml:"headers" json:"headers"`
+	Headers         map[string]string `yaml:"headers" json:"headers" secret:"true"`
 	RateLimitConfig *RateLimitConfig  `yaml:"rateLimit" json:"rateLimit"`
 }
 type JsonRpcConfig struct {
-	Url     string            `yaml:"url" json:"url" validate:"omitempty,url"`
-	Headers map[string]string `yaml:"headers" json:"headers"`
+	Url     string            `yaml:"url" json:"url" validate:"omitempty,url" description:"URL of the JSON-RPC API"`
+	Headers map[string]string `yaml:"headers" json:"headers" secret:"true" description:"HTTP headers of the requests, e.g. the API keys"`
+	// Endpoints are the fallbacks of the url. The scanner prefers the fastest healthy endpoint.
+	Endpoints    []JsonRpcEndpointConfig `yaml:"endpoints" json:"endpoints" validate:"dive"`
+	HedgeDelayMs int                     `yaml:"hedgeDelayMs" json:"hedgeDelayMs" validate:"min=0"`
+	// Quorum is the number of endpoints which should return the same block or traces.
+	Quorum int `yaml:"quorum" json:"quorum" validate:"min=0"`
+}
+
+type JsonRpcEndpointConfig struct {
+	Url     string            `yaml:"url" json:"url" validate:"url"`
+	Weight  int               `yaml:"weight" json:"weight" default:"1" validate:"min=0"`
+	Headers map[string]string `yaml:"headers" json:"headers" secret:"true"`
+}
+
+// EthEndpoints returns the url and the endpoints in the order they are configured in.
+func (cfg JsonRpcConfig) EthEndpoints() []ethereum.Endpoint {
+	var endpoints []ethereum.Endpoint
+	// keep the url even if it is empty when there are no other endpoints so that the clients
+	// of the disabled APIs can still be created
+	if cfg.Url != "" || len(cfg.Endpoints) == 0 {
+		endpoints = append(endpoints, ethereum.Endpoint{URL: cfg.Url, Weight: 1, Headers: cfg.Headers})
+	}
+	for _, endpoint := range cfg.Endpoints {
+		endpoints = append(endpoints, ethereum.Endpoint{URL: endpoint.Url, Weight: endpoint.Weight, Headers: endpoint.Headers})
+	}
+	return endpoints
+}
+
+// FailoverOptions returns the options for the clients which use all of the endpoints.
+func (cfg JsonRpcConfig) FailoverOptions() ethereum.FailoverOptions {
+	return ethereum.FailoverOptions{
+		HedgeDelay: time.Duration(cfg.HedgeDelayMs) * time.Millisecond,
+		Quorum:     cfg.Quorum,
+	}
 }
 
 type ScannerConfig struct {
@@ -28,6 +64,8 @@ type ScannerConfig struct {
 	BlockMaxAgeSeconds   int64         `yaml:"blockMaxAgeSeconds" json:"blockMaxAgeSeconds" default:"600"`
 	RetryIntervalSeconds int64         `yaml:"retryIntervalSeconds" json:"retryIntervalSeconds" default:"8"`
 	AlertAPIURL          string        `yaml:"apiUrl" json:"apiUrl" default:"https://api.zktoro.network/graphql" validate:"url"`
+	// ReorgWindow is the number of recent blocks to detect reorgs in. Negative values disable it.
+	ReorgWindow int `yaml:"reorgWindow" json:"reorgWindow" default:"64"`
 }
 
 type TraceConfig struct {
@@ -41,12 +79,22 @@ type RateLimitConfig struct {
 }
 
 type JsonRpcProxyConfig struct {
-	JsonRpc         JsonRpcConfig    `yaml:"jsonRpc" json:"jsonRpc"`
-	RateLimitConfig *RateLimitConfig `yaml:"rateLimit" json:"rateLimit"`
+	JsonRpc         JsonRpcConfig      `yaml:"jsonRpc" json:"jsonRpc"`
+	RateLimitConfig *RateLimitConfig   `yaml:"rateLimit" json:"rateLimit"`
+	Cache           JsonRpcCacheConfig `yaml:"cache" json:"cache"`
+}
+
+// JsonRpcCacheConfig configures the cache of the responses which cannot change anymore
+// (e.g. the blocks by hash) so that the bots can share them. The responses which refer to
+// the blocks by number are cached only after the blocks have the minimum confirmations.
+type JsonRpcCacheConfig struct {
+	Disable          bool   `yaml:"disable" json:"disable"`
+	MaxSizeMB        int    `yaml:"maxSizeMb" json:"maxSizeMb" default:"128" validate:"min=1"`
+	MinConfirmations uint64 `yaml:"minConfirmations" json:"minConfirmations" default:"64"`
 }
 
 type LogConfig struct {
-	Level       string `yaml:"level" json:"level" default:"info" `
+	Level       string `yaml:"level" json:"level" default:"info" description:"Log level (trace, debug, info, warn or error)"`
 	MaxLogSize  string `yaml:"maxLogSize" json:"maxLogSize" default:"50m" `
 	MaxLogFiles int    `yaml:"maxLogFiles" json:"maxLogFiles" default:"10" `
 }
@@ -57,7 +105,7 @@ type RegistryConfig struct {
 	IPFS                   IPFSConfig    `yaml:"ipfs" json:"ipfs"`
 	ContainerRegistry      string        `yaml:"containerRegistry" json:"containerRegistry" validate:"hostname|hostname_port" default:"disco.zktoro.network" `
 	Username               string        `yaml:"username" json:"username"`
-	Password               string        `yaml:"password" json:"password"`
+	Password               string        `yaml:"password" json:"password" secret:"true"`
 	Disable                bool          `yaml:"disable" json:"disable"` // for testing situations
 	CheckIntervalSeconds   int           `yaml:"checkIntervalSeconds" json:"checkIntervalSeconds" default:"15"`
 	ReleaseDistributionUrl string        `yaml:"releaseDistributionUrl" json:"releaseDistributionUrl" default:"https://dist.zktoro.network/manifests/releases"`
@@ -67,7 +115,7 @@ type IPFSConfig struct {
 	GatewayURL string `yaml:"gatewayUrl" json:"gatewayUrl" validate:"url" default:"https://ipfs.zktoro.network" `
 	APIURL     string `yaml:"apiUrl" json:"apiUrl" validate:"url" default:"https://ipfs.zktoro.network" `
 	Username   string `yaml:"username" json:"username"`
-	Password   string `yaml:"password" json:"password"`
+	Password   string `yaml:"password" json:"password" secret:"true"`
 }
 
 type BatchConfig struct {
@@ -86,9 +134,50 @@ type PublisherConfig struct {
 }
 
 type ResourcesConfig struct {
-	DisableAgentLimits bool    `yaml:"disableAgentLimits" json:"disableAgentLimits" default:"false" `
-	AgentMaxMemoryMiB  int     `yaml:"agentMaxMemoryMib" json:"agentMaxMemoryMib" validate:"omitempty,min=100"`
-	AgentMaxCPUs       float64 `yaml:"agentMaxCpus" json:"agentMaxCpus" validate:"omitempty,gt=0"`
+	DisableAgentLimits bool `yaml:"disableAgentLimits" json:"disableAgentLimits" default:"false" `
+	// the default limits of the bots which do not declare resources
+	AgentMaxMemoryMiB int     `yaml:"agentMaxMemoryMib" json:"agentMaxMemoryMib" validate:"omitempty,min=100"`
+	AgentMaxCPUs      float64 `yaml:"agentMaxCpus" json:"agentMaxCpus" validate:"omitempty,gt=0"`
+	AgentMaxPids      int64   `yaml:"agentMaxPids" json:"agentMaxPids" validate:"omitempty,min=1"`
+	// the resources declared in the bot manifests are capped by these
+	ManifestMaxMemoryMiB int     `yaml:"manifestMaxMemoryMib" json:"manifestMaxMemoryMib" default:"4096" validate:"min=100"`
+	ManifestMaxCPUs      float64 `yaml:"manifestMaxCpus" json:"manifestMaxCpus" default:"2" validate:"gt=0"`
+	ManifestMaxPids      int64   `yaml:"manifestMaxPids" json:"manifestMaxPids" default:"4096" validate:"min=1"`
+	ManifestMaxDiskMiB   int     `yaml:"manifestMaxDiskMib" json:"manifestMaxDiskMib" default:"10240" validate:"min=1"`
+	// disk limits need a storage driver with quota support so they are applied only if enabled
+	EnableDiskLimits bool `yaml:"enableDiskLimits" json:"enableDiskLimits"`
+	// the default disk limit of the bots which do not declare disk if disk limits are enabled
+	AgentMaxDiskMiB int                  `yaml:"agentMaxDiskMib" json:"agentMaxDiskMib" validate:"omitempty,min=1"`
+	Budget          ResourceBudgetConfig `yaml:"budget" json:"budget"`
+	Restarts        BotRestartConfig     `yaml:"restarts" json:"restarts"`
+}
+
+// ResourceBudgetConfig limits the total resources of the bot containers. The supervisor queues
+// the bot launches which do not fit in the budget until some resources are freed up. Zero values
+// mean no budget. The bots without a pids or a disk limit never fit in a pids or a disk budget so
+// these budgets need agentMaxPids, and enableDiskLimits with agentMaxDiskMib. A budget cannot be
+// set if the agent limits are disabled.
+type ResourceBudgetConfig struct {
+	MemoryMiB int     `yaml:"memoryMib" json:"memoryMib" validate:"omitempty,min=100"`
+	CPUs      float64 `yaml:"cpus" json:"cpus" validate:"omitempty,gt=0"`
+	Pids      int64   `yaml:"pids" json:"pids" validate:"omitempty,min=1"`
+	DiskMiB   int     `yaml:"diskMib" json:"diskMib" validate:"omitempty,min=1"`
+}
+
+// IsSet tells if any of the resources has a budget.
+func (budget ResourceBudgetConfig) IsSet() bool {
+	return budget.MemoryMiB > 0 || budget.CPUs > 0 || budget.Pids > 0 || budget.DiskMiB > 0
+}
+
+// BotRestartConfig is the restart budget of the bots. The exits and the failed launches of a bot
+// are counted within the window and every failure delays the next start exponentially. The bots
+// which exceed the budget are quarantined. Zero max restarts means no budget.
+type BotRestartConfig struct {
+	MaxRestarts       int `yaml:"maxRestarts" json:"maxRestarts" default:"5" validate:"min=0"`
+	WindowSeconds     int `yaml:"windowSeconds" json:"windowSeconds" default:"1800" validate:"min=1"`
+	BackoffSeconds    int `yaml:"backoffSeconds" json:"backoffSeconds" default:"15" validate:"min=0"`
+	MaxBackoffSeconds int `yaml:"maxBackoffSeconds" json:"maxBackoffSeconds" default:"600" validate:"min=0"`
+	QuarantineSeconds int `yaml:"quarantineSeconds" json:"quarantineSeconds" default:"3600" validate:"min=0"`
 }
 
 type ENSConfig struct {
@@ -118,7 +207,7 @@ type AgentLogsConfig struct {
 
 type ContainerRegistryConfig struct {
 	Username string `yaml:"username" json:"username"`
-	Password string `yaml:"password" json:"password"`
+	Password string `yaml:"password" json:"password" secret:"true"`
 }
 
 type RuntimeLimits struct {
@@ -131,13 +220,13 @@ type RuntimeLimits struct {
 
 type RedisConfig struct {
 	Address  string `yaml:"address" json:"address"`
-	Password string `yaml:"password" json:"password"`
+	Password string `yaml:"password" json:"password" secret:"true"`
 	DB       int    `yaml:"db" json:"db"`
 }
 
 type RedisClusterConfig struct {
 	Addresses []string `yaml:"addresses" json:"addresses"`
-	Password  string   `yaml:"password" json:"password"`
+	Password  string   `yaml:"password" json:"password" secret:"true"`
 	DB        int      `yaml:"db" json:"db"`
 }
 
@@ -153,11 +242,11 @@ type StandaloneModeConfig struct {
 }
 
 type LocalModeConfig struct {
-	Enable                bool                     `yaml:"enable" json:"enable"`
+	Enable                bool                     `yaml:"enable" json:"enable" description:"Run the local mode bots instead of the assigned bots"`
 	IncludeMetrics        bool                     `yaml:"includeMetrics" json:"includeMetrics"`
 	BotIDs                []string                 `yaml:"botIds" json:"botIds"`
-	BotImages             []string                 `yaml:"botImages" json:"botImages"`
-	WebhookURL            string                   `yaml:"webhookUrl" json:"webhookUrl"`
+	BotImages             []string                 `yaml:"botImages" json:"botImages" description:"Bot images to run"`
+	WebhookURL            string                   `yaml:"webhookUrl" json:"webhookUrl" description:"Webhook which receives the alerts if there are no sinks"`
 	LogFileName           string                   `yaml:"logFileName" json:"logFileName"`
 	LogToStdout           bool                     `yaml:"logToStdout" json:"logToStdout"`
 	ContainerRegistry     *ContainerRegistryConfig `yaml:"containerRegistry" json:"containerRegistry"`
@@ -165,8 +254,11 @@ type LocalModeConfig struct {
 	ForceEnableInspection bool                     `yaml:"forceEnableInspection" json:"forceEnableInspection"`
 	Deduplication         *DeduplicationConfig     `yaml:"deduplication" json:"deduplication"`
 	ShardedBots           []*LocalShardedBot       `yaml:"shardedBots" json:"shardedBots"`
-	PrivateKeyHex         string                   `yaml:"privateKeyHex" json:"privateKeyHex"`
+	PrivateKeyHex         string                   `yaml:"privateKeyHex" json:"privateKeyHex" secret:"true"`
 	Standalone            StandaloneModeConfig     `yaml:"standalone" json:"standalone"`
+	Sinks                 []*AlertSinkConfig       `yaml:"sinks" json:"sinks" validate:"dive"`
+	BotQueues             []*LocalBotQueueConfig   `yaml:"botQueues" json:"botQueues" validate:"dive"`
+	BotResources          []*LocalBotResources     `yaml:"botResources" json:"botResources" validate:"dive"`
 }
 
 // IsStandalone checks if the node is in standalone mode. It should only be available
@@ -175,12 +267,103 @@ func (lmc LocalModeConfig) IsStandalone() bool {
 	return lmc.Enable && lmc.Standalone.Enable
 }
 
+// BotQueue returns the queue config of the first entry which matches the bot ID or the image.
+func (lmc LocalModeConfig) BotQueue(botID, image string) *QueueConfig {
+	for _, botQueue := range lmc.BotQueues {
+		if botQueue == nil {
+			continue
+		}
+		if (len(botQueue.BotID) > 0 && strings.EqualFold(botQueue.BotID, botID)) ||
+			(len(botQueue.BotImage) > 0 && botQueue.BotImage == image) {
+			queueCfg := botQueue.QueueConfig
+			return &queueCfg
+		}
+	}
+	return nil
+}
+
+// BotResourcesOverride returns the resources of the first entry which matches the bot ID or the image.
+func (lmc LocalModeConfig) BotResourcesOverride(botID, image string) *BotResources {
+	for _, botResources := range lmc.BotResources {
+		if botResources == nil {
+			continue
+		}
+		if (len(botResources.BotID) > 0 && strings.EqualFold(botResources.BotID, botID)) ||
+			(len(botResources.BotImage) > 0 && botResources.BotImage == image) {
+			resources := botResources.BotResources
+			return &resources
+		}
+	}
+	return nil
+}
+
+// LocalBotResources overrides the resources of a local mode bot by its ID or image.
+type LocalBotResources struct {
+	BotID        string `yaml:"botId" json:"botId"`
+	BotImage     string `yaml:"botImage" json:"botImage"`
+	BotResources `yaml:",inline"`
+}
+
+// LocalBotQueueConfig sets the queue config of a local mode bot by its ID or image.
+type LocalBotQueueConfig struct {
+	BotID       string `yaml:"botId" json:"botId"`
+	BotImage    string `yaml:"botImage" json:"botImage"`
+	QueueConfig `yaml:",inline"`
+}
+
+// Alert sink types
+const (
+	AlertSinkTypeWebhook = "webhook"
+	AlertSinkTypeFile    = "file"
+	AlertSinkTypeStdout  = "stdout"
+	AlertSinkTypeNATS    = "nats"
+	AlertSinkTypeKafka   = "kafka"
+)
+
+// AlertSinkConfig is a destination of the local mode alerts. Only the alerts which match
+// the severity and the bot ID filters are sent to the sink. Empty filters match everything.
+type AlertSinkConfig struct {
+	Name       string            `yaml:"name" json:"name"`
+	Type       string            `yaml:"type" json:"type" validate:"oneof=webhook file stdout nats kafka"`
+	Severities []string          `yaml:"severities" json:"severities" validate:"dive,oneof=UNKNOWN INFO LOW MEDIUM HIGH CRITICAL"`
+	BotIDs     []string          `yaml:"botIds" json:"botIds"`
+	Webhook    WebhookSinkConfig `yaml:"webhook" json:"webhook"`
+	File       FileSinkConfig    `yaml:"file" json:"file"`
+	NATS       NATSSinkConfig    `yaml:"nats" json:"nats"`
+	Kafka      KafkaSinkConfig   `yaml:"kafka" json:"kafka"`
+}
+
+type WebhookSinkConfig struct {
+	URL string `yaml:"url" json:"url" validate:"omitempty,url"`
+}
+
+type FileSinkConfig struct {
+	// relative paths are in the logs dir
+	Path      string `yaml:"path" json:"path"`
+	MaxSizeMB int    `yaml:"maxSizeMb" json:"maxSizeMb" default:"100" validate:"min=1"`
+	MaxFiles  int    `yaml:"maxFiles" json:"maxFiles" default:"5" validate:"min=1"`
+}
+
+type NATSSinkConfig struct {
+	URL     string `yaml:"url" json:"url" validate:"omitempty,url"`
+	Subject string `yaml:"subject" json:"subject" default:"zktoro.alerts"`
+}
+
+type KafkaSinkConfig struct {
+	Brokers        []string `yaml:"brokers" json:"brokers"`
+	Topic          string   `yaml:"topic" json:"topic" default:"zktoro-alerts"`
+	ClientID       string   `yaml:"clientId" json:"clientId" default:"zktoro-node"`
+	TimeoutSeconds int      `yaml:"timeoutSeconds" json:"timeoutSeconds" default:"10" validate:"min=1"`
+}
+
 type LocalShardedBot struct {
 	BotImage *string `yaml:"botImage" json:"botImage"`
 	// number of shards for bot
 	Shards uint `yaml:"shards" json:"shards"`
 	// target per shard for bot
 	Target uint `yaml:"target" json:"target"`
+	// decides which shard processes a tx
+	Strategy ShardStrategy `yaml:"strategy" json:"strategy" validate:"omitempty,oneof=block txHash toAddress fromAddress"`
 }
 
 type InspectionConfig struct {
@@ -200,10 +383,93 @@ type CombinerConfig struct {
 	QueryInterval     uint64 `yaml:"queryInterval" json:"queryInterval"`
 }
 
+// AlertIndexConfig is the local index of the alerts published by the node. The index is served
+// by a GraphQL endpoint which supports the alerts query of the public API, so the combiner alert
+// API URL and the public API proxy URL can be set to http://zktoro-scanner:8565 to query it.
+type AlertIndexConfig struct {
+	Enable           bool `yaml:"enable" json:"enable"`
+	RetentionMinutes int  `yaml:"retentionMinutes" json:"retentionMinutes" default:"1440" validate:"min=1"`
+	MaxSizeMB        int  `yaml:"maxSizeMb" json:"maxSizeMb" default:"256" validate:"min=1"`
+}
+
 type AdvancedConfig struct {
-	SafeOffset       bool   `yaml:"safeOffset" json:"safeOffset"`
-	IPFSExperiment   bool   `yaml:"ipfsExperiment" json:"ipfsExperiment"`
-	MulticallAddress string `yaml:"multicallAddress" json:"multicallAddress"`
+	SafeOffset         bool   `yaml:"safeOffset" json:"safeOffset"`
+	IPFSExperiment     bool   `yaml:"ipfsExperiment" json:"ipfsExperiment"`
+	MulticallAddress   string `yaml:"multicallAddress" json:"multicallAddress"`
+	MulticallChunkSize int    `yaml:"multicallChunkSize" json:"multicallChunkSize" default:"100" validate:"min=1"`
+}
+
+type ListenerTLSConfig struct {
+	Disable      bool     `yaml:"disable" json:"disable"`
+	CertFile     string   `yaml:"certFile" json:"certFile" validate:"required_with=KeyFile"`
+	KeyFile      string   `yaml:"keyFile" json:"keyFile" validate:"required_with=CertFile"`
+	ClientCAFile string   `yaml:"clientCaFile" json:"clientCaFile"`
+	Hosts        []string `yaml:"hosts" json:"hosts"`
+}
+
+type ListenerConfig struct {
+	Address                string            `yaml:"address" json:"address" validate:"hostname_port" default:":8443"`
+	TLS                    ListenerTLSConfig `yaml:"tls" json:"tls"`
+	AuthMode               string            `yaml:"authMode" json:"authMode" validate:"oneof=token mtls" default:"token"`
+	AuthToken              string            `yaml:"authToken" json:"authToken" secret:"true"`
+	MaxBodyBytes           int64             `yaml:"maxBodyBytes" json:"maxBodyBytes" validate:"min=1024" default:"1048576"`
+	ShutdownTimeoutSeconds int               `yaml:"shutdownTimeoutSeconds" json:"shutdownTimeoutSeconds" default:"10"`
+}
+
+// TrustedIssuerConfig is an issuer whose credentials are accepted by the holder. The public key
+// is the hex encoded Ed25519 key of the issuer and can be omitted for the did:key issuers.
+type TrustedIssuerConfig struct {
+	DID       string `yaml:"did" json:"did" validate:"required"`
+	PublicKey string `yaml:"publicKey" json:"publicKey" validate:"omitempty,hexadecimal"`
+}
+
+// CredentialsConfig configures the verification of the credentials stored by the holder. The
+// status lists are fetched only from the allowed hosts, or from the domain of a did:web issuer
+// if no host is allowed, and never from the private addresses.
+type CredentialsConfig struct {
+	TrustedIssuers           []TrustedIssuerConfig `yaml:"trustedIssuers" json:"trustedIssuers" validate:"dive"`
+	StatusListHosts          []string              `yaml:"statusListHosts" json:"statusListHosts"`
+	StatusListTimeoutSeconds int                   `yaml:"statusListTimeoutSeconds" json:"statusListTimeoutSeconds" default:"10" validate:"min=1"`
+}
+
+// AdminAPIConfig is the local operator API of the supervisor. The address is the host address
+// which the supervisor API port is published to.
+type AdminAPIConfig struct {
+	Disable   bool   `yaml:"disable" json:"disable"`
+	Address   string `yaml:"address" json:"address" validate:"hostname_port" default:"127.0.0.1:8555"`
+	AuthToken string `yaml:"authToken" json:"authToken" secret:"true"`
+}
+
+type IdentityConfig struct {
+	EmbedDIDLinkage bool `yaml:"embedDidLinkage" json:"embedDidLinkage"`
+}
+
+// VaultConfig is a HashiCorp Vault compatible KV secrets engine which resolves the
+// "${vault:<path>#<key>}" references. The address and the token default to the VAULT_ADDR and
+// the VAULT_TOKEN env vars.
+type VaultConfig struct {
+	Address        string `yaml:"address" json:"address" validate:"omitempty,url"`
+	Token          string `yaml:"token" json:"token" secret:"true"`
+	Namespace      string `yaml:"namespace" json:"namespace"`
+	Mount          string `yaml:"mount" json:"mount" default:"secret"`
+	KVVersion      int    `yaml:"kvVersion" json:"kvVersion" validate:"oneof=1 2" default:"2"`
+	TimeoutSeconds int    `yaml:"timeoutSeconds" json:"timeoutSeconds" default:"10"`
+}
+
+// RemoteSignerConfig is a Web3Signer compatible signing service which keeps the scanner key.
+type RemoteSignerConfig struct {
+	URL            string            `yaml:"url" json:"url" validate:"omitempty,url"`
+	Address        string            `yaml:"address" json:"address" validate:"omitempty,eth_addr"`
+	Headers        map[string]string `yaml:"headers" json:"headers" secret:"true"`
+	TimeoutSeconds int               `yaml:"timeoutSeconds" json:"timeoutSeconds" default:"10"`
+}
+
+type SignerConfig struct {
+	Remote RemoteSignerConfig `yaml:"remote" json:"remote"`
+}
+
+type SecretsConfig struct {
+	Vault VaultConfig `yaml:"vault" json:"vault"`
 }
 
 type PrometheusConfig struct {
@@ -213,42 +479,67 @@ type PrometheusConfig struct {
 type Config struct {
 	// runtime values
 
-	Development bool   `yaml:"-" json:"_development"`
-	ZktoroDir   string `yaml:"-" json:"_zktoroDir"`
-	KeyDirPath  string `yaml:"-" json:"_keyDirPath"`
-	Passphrase  string `yaml:"-" json:"_passphrase"`
-	DIDKeyPath  string
-	VcPath      string
-	VpPath      string
+	Development     bool   `yaml:"-" json:"_development"`
+	ZktoroDir       string `yaml:"-" json:"_zktoroDir"`
+	KeyDirPath      string `yaml:"-" json:"_keyDirPath"`
+	Passphrase      string `yaml:"-" json:"_passphrase"`
+	DIDKeyPath      string `yaml:"-" json:"_didKeyPath"`
+	CredentialsPath string `yaml:"-" json:"_credentialsPath"`
+	VpPath          string `yaml:"-" json:"_vpPath"`
+	// ResolvedSecrets are the resolved values of the secret references by reference.
+	ResolvedSecrets map[string]string `yaml:"-" json:"-"`
 	// yaml config values
 
-	ChainID int `yaml:"chainId" json:"chainId" default:"1" `
-
-	Scan  ScannerConfig `yaml:"scan" json:"scan"`
-	Trace TraceConfig   `yaml:"trace" json:"trace"`
-
-	Registry         RegistryConfig       `yaml:"registry" json:"registry"`
-	Publish          PublisherConfig      `yaml:"publish" json:"publish"`
-	JsonRpcProxy     JsonRpcProxyConfig   `yaml:"jsonRpcProxy" json:"jsonRpcProxy"`
-	PublicAPIProxy   PublicAPIProxyConfig `yaml:"publicApiProxy" json:"publicApiProxy"`
-	Log              LogConfig            `yaml:"log" json:"log"`
-	ResourcesConfig  ResourcesConfig      `yaml:"resources" json:"resources"`
-	ENSConfig        ENSConfig            `yaml:"ens" json:"ens"`
-	TelemetryConfig  TelemetryConfig      `yaml:"telemetry" json:"telemetry"`
-	AutoUpdate       AutoUpdateConfig     `yaml:"autoUpdate" json:"autoUpdate"`
-	AgentLogsConfig  AgentLogsConfig      `yaml:"agentLogs" json:"agentLogs"`
-	LocalModeConfig  LocalModeConfig      `yaml:"localMode" json:"localMode"`
-	InspectionConfig InspectionConfig     `yaml:"inspection" json:"inspection"`
-	StorageConfig    StorageConfig        `yaml:"storage" json:"storage"`
-	CombinerConfig   CombinerConfig       `yaml:"combiner" json:"combiner"`
-	PrometheusConfig PrometheusConfig     `yaml:"prometheus" json:"prometheus"`
-	AdvancedConfig   AdvancedConfig       `yaml:"advanced" json:"advanced"`
+	ChainID int `yaml:"chainId" json:"chainId" default:"1" description:"Chain ID of the network that is scanned"`
+
+	Scan  ScannerConfig `yaml:"scan" json:"scan" description:"JSON-RPC API of the scanned chain"`
+	Trace TraceConfig   `yaml:"trace" json:"trace" description:"JSON-RPC API which supports trace_block for the scanned chain"`
+
+	Registry         RegistryConfig         `yaml:"registry" json:"registry" description:"Registry of the bots and the node releases"`
+	Publish          PublisherConfig        `yaml:"publish" json:"publish" description:"Publishing of the alert batches"`
+	JsonRpcProxy     JsonRpcProxyConfig     `yaml:"jsonRpcProxy" json:"jsonRpcProxy" description:"JSON-RPC API and the rate limits of the bot requests"`
+	PublicAPIProxy   PublicAPIProxyConfig   `yaml:"publicApiProxy" json:"publicApiProxy" description:"Public API proxy of the bot requests"`
+	Log              LogConfig              `yaml:"log" json:"log" description:"Log level and the log rotation of the node containers"`
+	ResourcesConfig  ResourcesConfig        `yaml:"resources" json:"resources" description:"Resource limits and restart budgets of the bot containers"`
+	ENSConfig        ENSConfig              `yaml:"ens" json:"ens" description:"ENS resolution of the contract addresses"`
+	TelemetryConfig  TelemetryConfig        `yaml:"telemetry" json:"telemetry" description:"Telemetry destination"`
+	AutoUpdate       AutoUpdateConfig       `yaml:"autoUpdate" json:"autoUpdate" description:"Automatic node updates"`
+	AgentLogsConfig  AgentLogsConfig        `yaml:"agentLogs" json:"agentLogs" description:"Uploading of the bot logs"`
+	LocalModeConfig  LocalModeConfig        `yaml:"localMode" json:"localMode" description:"Running a chosen set of bots without the registry"`
+	InspectionConfig InspectionConfig       `yaml:"inspection" json:"inspection" description:"Inspection of the node environment"`
+	StorageConfig    StorageConfig          `yaml:"storage" json:"storage" description:"IPFS routing of the storage experiment"`
+	CombinerConfig   CombinerConfig         `yaml:"combiner" json:"combiner" description:"Alert queries of the combiner bots"`
+	AlertIndex       AlertIndexConfig       `yaml:"alertIndex" json:"alertIndex" description:"Local index and GraphQL endpoint of the alerts published by the node"`
+	PrometheusConfig PrometheusConfig       `yaml:"prometheus" json:"prometheus" description:"Prometheus metrics endpoint"`
+	Listener         ListenerConfig         `yaml:"listener" json:"listener" description:"Verifiable credential listener"`
+	Credentials      CredentialsConfig      `yaml:"credentials" json:"credentials" description:"Trusted issuers and status checks of the stored credentials"`
+	AdminAPI         AdminAPIConfig         `yaml:"adminApi" json:"adminApi" description:"Local operator API of the supervisor"`
+	Identity         IdentityConfig         `yaml:"identity" json:"identity" description:"Decentralized identity of the node"`
+	ContainerRuntime ContainerRuntimeConfig `yaml:"containerRuntime" json:"containerRuntime" description:"Container runtime which runs the node and the bots"`
+	Signer           SignerConfig           `yaml:"signer" json:"signer" description:"Remote signer of the scanner key instead of the local keystore"`
+	SecretsConfig    SecretsConfig          `yaml:"secrets" json:"secrets" description:"Secret stores of the secret references, e.g. ${vault:zktoro/node#password}"`
+	AdvancedConfig   AdvancedConfig         `yaml:"advanced" json:"advanced" description:"Advanced and experimental settings"`
 }
 
 func (cfg *Config) ConfigFilePath() string {
 	return path.Join(cfg.ZktoroDir, DefaultConfigFileName)
 }
 
+// BotStatesPath returns the path of the persisted bot lifecycle states.
+func (cfg *Config) BotStatesPath() string {
+	return path.Join(cfg.ZktoroDir, DefaultBotStatesFileName)
+}
+
+// AdminTokenPath returns the path of the generated admin API token.
+func (cfg *Config) AdminTokenPath() string {
+	return path.Join(cfg.ZktoroDir, DefaultAdminTokenFileName)
+}
+
+// DIDLinkagePath returns the path of the scanner-DID linkage which is kept next to the keystore.
+func (cfg *Config) DIDLinkagePath() string {
+	return path.Join(cfg.ZktoroDir, DefaultDIDLinkageFileName)
+}
+
 // GetConfigForContainer is how a container gets the zktoro configuration (file or env var)
 func GetConfigForContainer() (Config, error) {
 	cfg, err := getConfigFromFile()
@@ -300,6 +591,7 @@ func applyContextDefaults(cfg *Config) {
 		cfg.ENSConfig.ContractAddress = ""
 	}
 	cfg.ZktoroDir = DefaultContainerzktoroDirPath
+	cfg.ContainerRuntime.InContainer = true
 	cfg.KeyDirPath = path.Join(cfg.ZktoroDir, DefaultKeysDirName)
 	cfg.CombinerConfig.CombinerCachePath = path.Join(cfg.ZktoroDir, DefaultCombinerCacheFileName)
 }
@@ -357,7 +649,15 @@ func getConfigFromFile() (cfg Config, err error) {
 	}
 
 	// finally set the defaults
-	err = defaults.Set(&cfg)
+	if err = defaults.Set(&cfg); err != nil {
+		return
+	}
+
+	// the secret references are resolved on the host and copied to the containers
+	if cfg.ResolvedSecrets, err = LoadResolvedSecrets(DefaultContainerSecretsPath); err != nil {
+		return
+	}
+	err = ResolveSecrets(&cfg)
 	return
 }
 
@@ -25,10 +25,12 @@ var (
 	DockerJWTProviderContainerName    = fmt.Sprintf("%s-jwt-provider", ContainerNamePrefix)
 	DockerStorageContainerName        = fmt.Sprintf("%s-storage", ContainerNamePrefix)
 
-	DockerNetworkName = DockerScannerContainerName
+	DockerNetworkName    = DockerScannerContainerName
+	DockerBotNetworkName = fmt.Sprintf("%s-bots", ContainerNamePrefix)
 
 	DefaultContainerzktoroDirPath     = "/.zktoro"
 	DefaultContainerConfigPath        = path.Join(DefaultContainerzktoroDirPath, DefaultConfigFileName)
 	DefaultContainerWrappedConfigPath = path.Join(DefaultContainerzktoroDirPath, DefaultWrappedConfigFileName)
 	DefaultContainerKeyDirPath        = path.Join(DefaultContainerzktoroDirPath, DefaultKeysDirName)
+	DefaultContainerSecretsPath       = path.Join("/", DefaultSecretsFileName)
 )
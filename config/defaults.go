@@ -4,8 +4,12 @@ const (
 	DefaultKeysDirName           = ".keys"
 	DefaultCombinerCacheFileName = ".combiner_cache.json"
 	DefaultConfigFileName        = "config.yml"
+	DefaultDIDLinkageFileName    = "did-linkage.json"
+	DefaultBotStatesFileName     = "bot-states.json"
+	DefaultAdminTokenFileName    = "admin.token"
 	DefaultWrappedConfigFileName = "wrapped-config.yml"
 	DefaultConfigWrapperKey      = "x-zktoro-config"
+	DefaultSecretsFileName       = "secrets.json" // the resolved secrets which are copied to the containers
 	DefaultNatsPort              = "4222"
 	DefaultContainerPort         = "8089"
 	DefaultHealthPort            = "8090"
@@ -13,5 +17,7 @@ const (
 	DefaultStoragePort           = "8525"
 	DefaultPublicAPIProxyPort    = "8535"
 	DefaultJSONRPCProxyPort      = "8545"
+	DefaultAdminAPIPort          = "8555"
+	DefaultAlertIndexPort        = "8565"
 	DefaultzktoroNodeBinaryPath  = "/zktoro" // the path for the common binary in the container image
 )
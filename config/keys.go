@@ -2,10 +2,13 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"zktoro/zktoro-core-go/security"
+	"zktoro/zktoro-core-go/utils"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -24,3 +27,51 @@ func LoadKeyInContainer(cfg Config) (*keystore.Key, error) {
 	}
 	return security.LoadKey(DefaultContainerKeyDirPath)
 }
+
+// LoadSigner creates the remote signer if it is configured, otherwise it loads the key with the
+// given loader.
+func LoadSigner(cfg Config, loadKey func() (*keystore.Key, error)) (security.Signer, error) {
+	remoteCfg := cfg.Signer.Remote
+	if len(remoteCfg.URL) > 0 {
+		signer, err := security.NewRemoteSigner(security.RemoteSignerConfig{
+			URL:     remoteCfg.URL,
+			Address: remoteCfg.Address,
+			Headers: remoteCfg.Headers,
+			Timeout: time.Duration(remoteCfg.TimeoutSeconds) * time.Second,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the remote signer: %v", err)
+		}
+		return signer, nil
+	}
+	key, err := loadKey()
+	if err != nil {
+		return nil, err
+	}
+	return security.NewKeySigner(key), nil
+}
+
+// LoadSignerInContainer loads the signer in the service container depending on the config.
+func LoadSignerInContainer(cfg Config) (security.Signer, error) {
+	// can't dial localhost - need to dial host gateway from container
+	cfg.Signer.Remote.URL = utils.ConvertToDockerHostURL(cfg.Signer.Remote.URL)
+	return LoadSigner(cfg, func() (*keystore.Key, error) {
+		return LoadKeyInContainer(cfg)
+	})
+}
+
+// LoadDIDLinkageInContainer loads, verifies and encodes the scanner-DID linkage if it should be embedded.
+// It returns an empty string if embedding is disabled.
+func LoadDIDLinkageInContainer(cfg Config, scannerAddress common.Address) (string, error) {
+	if !cfg.Identity.EmbedDIDLinkage {
+		return "", nil
+	}
+	linkage, err := security.LoadDIDLinkage(cfg.DIDLinkagePath())
+	if err != nil {
+		return "", fmt.Errorf("failed to load the DID linkage: %v", err)
+	}
+	if err := security.VerifyDIDLinkageForScanner(linkage, scannerAddress.Hex()); err != nil {
+		return "", fmt.Errorf("invalid DID linkage: %v", err)
+	}
+	return security.EncodeDIDLinkage(linkage)
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/creasty/defaults"
+	"github.com/go-playground/validator/v10"
+)
+
+// ReloadableFields are the config fields which the node services can apply at runtime. Changing
+// any other field requires a node restart.
+var ReloadableFields = []string{
+	"log.level",
+	"jsonRpcProxy.rateLimit",
+	"publicApiProxy.rateLimit",
+	"localMode.botImages",
+	"localMode.webhookUrl",
+	"localMode.logFileName",
+	"localMode.logToStdout",
+	"localMode.sinks",
+}
+
+// ConfigChanges are the changed config fields by their YAML paths.
+type ConfigChanges struct {
+	Reloadable      []string `json:"reloadable"`
+	RestartRequired []string `json:"restartRequired"`
+}
+
+// IsEmpty tells if nothing has changed.
+func (changes ConfigChanges) IsEmpty() bool {
+	return len(changes.Reloadable) == 0 && len(changes.RestartRequired) == 0
+}
+
+// Reloaded tells if any of the given reloadable fields has changed.
+func (changes ConfigChanges) Reloaded(fields ...string) bool {
+	for _, changed := range changes.Reloadable {
+		for _, field := range fields {
+			if changed == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DiffConfig compares the YAML fields of the configs. The reloadable fields are reported
+// separately from the fields which require a restart.
+func DiffConfig(oldCfg, newCfg Config) ConfigChanges {
+	var changes ConfigChanges
+	diffFields("", reflect.ValueOf(oldCfg), reflect.ValueOf(newCfg), &changes)
+	return changes
+}
+
+func diffFields(prefix string, oldVal, newVal reflect.Value, changes *ConfigChanges) {
+	t := oldVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		fieldPath := name
+		if prefix != "" {
+			fieldPath = prefix + "." + name
+		}
+
+		oldField, newField := oldVal.Field(i), newVal.Field(i)
+		if reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			continue
+		}
+		switch {
+		case isReloadable(fieldPath):
+			changes.Reloadable = append(changes.Reloadable, fieldPath)
+
+		case field.Type.Kind() == reflect.Struct && hasReloadableChild(fieldPath):
+			diffFields(fieldPath, oldField, newField, changes)
+
+		default:
+			changes.RestartRequired = append(changes.RestartRequired, fieldPath)
+		}
+	}
+}
+
+func isReloadable(fieldPath string) bool {
+	for _, reloadable := range ReloadableFields {
+		if reloadable == fieldPath {
+			return true
+		}
+	}
+	return false
+}
+
+func hasReloadableChild(fieldPath string) bool {
+	for _, reloadable := range ReloadableFields {
+		if strings.HasPrefix(reloadable, fieldPath+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func yamlName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// ValidationError lists the invalid or missing config fields.
+type ValidationError struct {
+	Fields []string
+	// Rules are the failed validation rules by field.
+	Rules map[string]string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("invalid or missing config fields: %s", strings.Join(err.Fields, ", "))
+}
+
+// Validate validates the config by using the YAML names of the fields.
+func Validate(cfg *Config) error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(yamlName)
+	validate.RegisterStructValidation(validateResourcesConfig, ResourcesConfig{})
+
+	err := validate.Struct(cfg)
+	if err == nil {
+		return nil
+	}
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+	validationErr := &ValidationError{Rules: make(map[string]string)}
+	for _, fieldErr := range validationErrs {
+		// drop the "Config." prefix
+		field := strings.TrimPrefix(fieldErr.Namespace(), "Config.")
+		validationErr.Fields = append(validationErr.Fields, field)
+		validationErr.Rules[field] = fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			validationErr.Rules[field] += "=" + fieldErr.Param()
+		}
+	}
+	return validationErr
+}
+
+// LoadConfigFile reads the config file, sets the defaults and resolves the secret references. The
+// other runtime values are left empty.
+func LoadConfigFile(configPath string) (cfg Config, err error) {
+	if err = readYamlFile(configPath, &cfg); err != nil {
+		return
+	}
+	if err = defaults.Set(&cfg); err != nil {
+		return
+	}
+	err = ResolveSecrets(&cfg)
+	return
+}
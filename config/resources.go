@@ -1,30 +1,168 @@
 package config
 
+import "github.com/go-playground/validator/v10"
+
 // BotResourceLimits contain the agent resource limits data.
 type BotResourceLimits struct {
 	CPUQuota int64 // in microseconds
 	Memory   int64 // in bytes
+	Pids     int64
+	Disk     int64 // in bytes
+}
+
+// Fits tells if the limits fit in the budget after the committed limits. The budget fields
+// with zero values are unlimited. The limits with zero values are unlimited so they never fit
+// in a budget.
+func (limits *BotResourceLimits) Fits(committed *BotResourceLimits, budget ResourceBudgetConfig) bool {
+	if budget.CPUs > 0 && !fitsInBudget(committed.CPUQuota, limits.CPUQuota, CPUsToMicroseconds(budget.CPUs)) {
+		return false
+	}
+	if budget.MemoryMiB > 0 && !fitsInBudget(committed.Memory, limits.Memory, MiBToBytes(budget.MemoryMiB)) {
+		return false
+	}
+	if budget.Pids > 0 && !fitsInBudget(committed.Pids, limits.Pids, budget.Pids) {
+		return false
+	}
+	if budget.DiskMiB > 0 && !fitsInBudget(committed.Disk, limits.Disk, MiBToBytes(budget.DiskMiB)) {
+		return false
+	}
+	return true
+}
+
+func fitsInBudget(committed, limit, budget int64) bool {
+	return limit > 0 && committed+limit <= budget
+}
+
+// validateResourcesConfig rejects a budget when the agent limits are disabled since the bots
+// without limits never fit in a budget. The pids and the disk budgets require the node limits
+// of the same resource for the same reason.
+func validateResourcesConfig(sl validator.StructLevel) {
+	resourcesCfg := sl.Current().Interface().(ResourcesConfig)
+	if resourcesCfg.DisableAgentLimits {
+		if resourcesCfg.Budget.IsSet() {
+			sl.ReportError(resourcesCfg.Budget, "budget", "Budget", "excluded_with", "disableAgentLimits")
+		}
+		return
+	}
+	if resourcesCfg.Budget.Pids > 0 && resourcesCfg.AgentMaxPids == 0 {
+		sl.ReportError(resourcesCfg.AgentMaxPids, "agentMaxPids", "AgentMaxPids", "required_with", "budget.pids")
+	}
+	if resourcesCfg.Budget.DiskMiB > 0 {
+		if !resourcesCfg.EnableDiskLimits {
+			sl.ReportError(resourcesCfg.EnableDiskLimits, "enableDiskLimits", "EnableDiskLimits", "required_with", "budget.diskMib")
+		}
+		if resourcesCfg.AgentMaxDiskMiB == 0 {
+			sl.ReportError(resourcesCfg.AgentMaxDiskMiB, "agentMaxDiskMib", "AgentMaxDiskMiB", "required_with", "budget.diskMib")
+		}
+	}
+}
+
+// Add adds the other limits to these.
+func (limits *BotResourceLimits) Add(other *BotResourceLimits) {
+	limits.CPUQuota += other.CPUQuota
+	limits.Memory += other.Memory
+	limits.Pids += other.Pids
+	limits.Disk += other.Disk
+}
+
+// BotResources are the resources declared for a bot. Zero values mean the node defaults.
+type BotResources struct {
+	MemoryMiB int     `yaml:"memoryMib" json:"memoryMib,omitempty" validate:"omitempty,min=100"`
+	CPUs      float64 `yaml:"cpus" json:"cpus,omitempty" validate:"omitempty,gt=0"`
+	Pids      int64   `yaml:"pids" json:"pids,omitempty" validate:"omitempty,min=1"`
+	DiskMiB   int     `yaml:"diskMib" json:"diskMib,omitempty" validate:"omitempty,min=1"`
+}
+
+// Override returns a copy of the resources with the non-zero values of the override.
+func (br *BotResources) Override(override *BotResources) *BotResources {
+	if br == nil && override == nil {
+		return nil
+	}
+	var result BotResources
+	if br != nil {
+		result = *br
+	}
+	if override == nil {
+		return &result
+	}
+	if override.MemoryMiB > 0 {
+		result.MemoryMiB = override.MemoryMiB
+	}
+	if override.CPUs > 0 {
+		result.CPUs = override.CPUs
+	}
+	if override.Pids > 0 {
+		result.Pids = override.Pids
+	}
+	if override.DiskMiB > 0 {
+		result.DiskMiB = override.DiskMiB
+	}
+	return &result
+}
+
+// CapManifestResources caps the resources declared in a bot manifest by the node config.
+func CapManifestResources(resourcesCfg ResourcesConfig, declared *BotResources) *BotResources {
+	if declared == nil {
+		return nil
+	}
+	capped := *declared
+	if resourcesCfg.ManifestMaxMemoryMiB > 0 && capped.MemoryMiB > resourcesCfg.ManifestMaxMemoryMiB {
+		capped.MemoryMiB = resourcesCfg.ManifestMaxMemoryMiB
+	}
+	if resourcesCfg.ManifestMaxCPUs > 0 && capped.CPUs > resourcesCfg.ManifestMaxCPUs {
+		capped.CPUs = resourcesCfg.ManifestMaxCPUs
+	}
+	if resourcesCfg.ManifestMaxPids > 0 && capped.Pids > resourcesCfg.ManifestMaxPids {
+		capped.Pids = resourcesCfg.ManifestMaxPids
+	}
+	if resourcesCfg.ManifestMaxDiskMiB > 0 && capped.DiskMiB > resourcesCfg.ManifestMaxDiskMiB {
+		capped.DiskMiB = resourcesCfg.ManifestMaxDiskMiB
+	}
+	return &capped
 }
 
 // GetAgentResourceLimits calculates and returns the resource limits by
-// taking the configuration into account. Zero values mean no limits.
-func GetAgentResourceLimits(resourcesCfg ResourcesConfig) *BotResourceLimits {
+// taking the configuration and the bot resources into account. Zero values mean no limits.
+func GetAgentResourceLimits(resourcesCfg ResourcesConfig, botResources *BotResources) *BotResourceLimits {
 	var limits BotResourceLimits
 
 	if resourcesCfg.DisableAgentLimits {
 		return &limits
 	}
+	if botResources == nil {
+		botResources = &BotResources{}
+	}
 
 	limits.CPUQuota = getDefaultCPUQuotaPerAgent()
-	if resourcesCfg.AgentMaxCPUs > 0 {
+	switch {
+	case botResources.CPUs > 0:
+		limits.CPUQuota = CPUsToMicroseconds(botResources.CPUs)
+	case resourcesCfg.AgentMaxCPUs > 0:
 		limits.CPUQuota = CPUsToMicroseconds(resourcesCfg.AgentMaxCPUs)
 	}
 
 	limits.Memory = getDefaultMemoryPerAgent()
-	if resourcesCfg.AgentMaxMemoryMiB > 0 {
+	switch {
+	case botResources.MemoryMiB > 0:
+		limits.Memory = MiBToBytes(botResources.MemoryMiB)
+	case resourcesCfg.AgentMaxMemoryMiB > 0:
 		limits.Memory = MiBToBytes(resourcesCfg.AgentMaxMemoryMiB)
 	}
 
+	limits.Pids = resourcesCfg.AgentMaxPids
+	if botResources.Pids > 0 {
+		limits.Pids = botResources.Pids
+	}
+
+	if resourcesCfg.EnableDiskLimits {
+		switch {
+		case botResources.DiskMiB > 0:
+			limits.Disk = MiBToBytes(botResources.DiskMiB)
+		case resourcesCfg.AgentMaxDiskMiB > 0:
+			limits.Disk = MiBToBytes(resourcesCfg.AgentMaxDiskMiB)
+		}
+	}
+
 	return &limits
 }
 
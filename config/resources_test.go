@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapManifestResources(t *testing.T) {
+	r := require.New(t)
+
+	resourcesCfg := ResourcesConfig{
+		ManifestMaxMemoryMiB: 1000,
+		ManifestMaxCPUs:      1,
+		ManifestMaxPids:      100,
+		ManifestMaxDiskMiB:   500,
+	}
+	r.Nil(CapManifestResources(resourcesCfg, nil))
+	r.Equal(&BotResources{MemoryMiB: 1000, CPUs: 1, Pids: 100, DiskMiB: 500}, CapManifestResources(resourcesCfg, &BotResources{
+		MemoryMiB: 2000, CPUs: 2, Pids: 200, DiskMiB: 1000,
+	}))
+	r.Equal(&BotResources{MemoryMiB: 200, CPUs: 0.5, Pids: 50, DiskMiB: 100}, CapManifestResources(resourcesCfg, &BotResources{
+		MemoryMiB: 200, CPUs: 0.5, Pids: 50, DiskMiB: 100,
+	}))
+}
+
+func TestBotResourceLimits_Fits(t *testing.T) {
+	r := require.New(t)
+
+	committed := &BotResourceLimits{Pids: 100, Disk: MiBToBytes(100)}
+
+	// pids
+	budget := ResourceBudgetConfig{Pids: 200}
+	r.True((&BotResourceLimits{Pids: 100}).Fits(committed, budget))
+	r.False((&BotResourceLimits{Pids: 101}).Fits(committed, budget))
+	r.False((&BotResourceLimits{}).Fits(committed, budget))
+
+	// disk
+	budget = ResourceBudgetConfig{DiskMiB: 200}
+	r.True((&BotResourceLimits{Disk: MiBToBytes(100)}).Fits(committed, budget))
+	r.False((&BotResourceLimits{Disk: MiBToBytes(101)}).Fits(committed, budget))
+	r.False((&BotResourceLimits{}).Fits(committed, budget))
+
+	// no budget
+	r.True((&BotResourceLimits{}).Fits(committed, ResourceBudgetConfig{}))
+}
+
+func TestGetAgentResourceLimits_Disk(t *testing.T) {
+	r := require.New(t)
+
+	resourcesCfg := ResourcesConfig{AgentMaxDiskMiB: 100}
+	r.Zero(GetAgentResourceLimits(resourcesCfg, &BotResources{DiskMiB: 200}).Disk)
+
+	resourcesCfg.EnableDiskLimits = true
+	r.Equal(MiBToBytes(200), GetAgentResourceLimits(resourcesCfg, &BotResources{DiskMiB: 200}).Disk)
+	r.Equal(MiBToBytes(100), GetAgentResourceLimits(resourcesCfg, nil).Disk)
+}
+
+func TestValidate_BudgetWithoutAgentLimits(t *testing.T) {
+	r := require.New(t)
+
+	cfg, problems, err := CheckConfigYAML([]byte(`
+resources:
+  disableAgentLimits: true
+  budget:
+    memoryMib: 1000
+`))
+	r.NoError(err)
+	r.True(cfg.ResourcesConfig.DisableAgentLimits)
+	var found bool
+	for _, problem := range problems {
+		if problem.Field == "resources.budget" {
+			found = true
+			r.Equal("cannot be set with disableAgentLimits", problem.Message)
+		}
+	}
+	r.True(found)
+
+	cfg.ResourcesConfig.Budget = ResourceBudgetConfig{}
+	var validationErr *ValidationError
+	if err := Validate(&cfg); errors.As(err, &validationErr) {
+		r.NotContains(validationErr.Fields, "resources.budget")
+	}
+}
+
+func TestValidate_BudgetWithoutNodeLimits(t *testing.T) {
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		config   string
+		problems map[string]string
+	}{
+		{
+			config: `
+resources:
+  budget:
+    pids: 1000
+`,
+			problems: map[string]string{"resources.agentMaxPids": "is required with budget.pids"},
+		},
+		{
+			config: `
+resources:
+  budget:
+    diskMib: 1000
+`,
+			problems: map[string]string{
+				"resources.enableDiskLimits": "is required with budget.diskMib",
+				"resources.agentMaxDiskMib":  "is required with budget.diskMib",
+			},
+		},
+		{
+			config: `
+resources:
+  enableDiskLimits: true
+  budget:
+    diskMib: 1000
+`,
+			problems: map[string]string{"resources.agentMaxDiskMib": "is required with budget.diskMib"},
+		},
+		{
+			config: `
+resources:
+  agentMaxPids: 100
+  enableDiskLimits: true
+  agentMaxDiskMib: 100
+  budget:
+    pids: 1000
+    diskMib: 1000
+`,
+		},
+	} {
+		_, problems, err := CheckConfigYAML([]byte(tc.config))
+		r.NoError(err)
+		found := make(map[string]string)
+		for _, problem := range problems {
+			if strings.HasPrefix(problem.Field, "resources.") {
+				found[problem.Field] = problem.Message
+			}
+		}
+		if tc.problems == nil {
+			r.Empty(found, tc.config)
+			continue
+		}
+		r.Equal(tc.problems, found, tc.config)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+// ContainerRuntimeType is the type of the container runtime which runs the node services and the bots.
+type ContainerRuntimeType string
+
+// Container runtime types
+const (
+	ContainerRuntimeDocker     ContainerRuntimeType = "docker"
+	ContainerRuntimePodman     ContainerRuntimeType = "podman"
+	ContainerRuntimeContainerd ContainerRuntimeType = "containerd"
+)
+
+// Default container runtime sockets on the host
+const (
+	DefaultDockerSocket     = "/var/run/docker.sock"
+	DefaultPodmanSocket     = "/run/podman/podman.sock"
+	DefaultContainerdSocket = "/run/containerd/containerd.sock"
+)
+
+// Host paths which nerdctl uses to manage the containerd containers. The containerd target of the
+// node image ships nerdctl and the CNI plugins but the containers are networked by the nerdctl OCI
+// hook which runc runs on the host from the same nerdctl path, so the host needs the same nerdctl at
+// /usr/local/bin/nerdctl and the CNI plugins in /opt/cni/bin. The state directories are mounted to
+// the same paths in the node containers which manage the runtime.
+var containerdHostPaths = []string{
+	"/var/lib/containerd", // the container snapshots which nerdctl mounts to copy the files
+	"/var/lib/nerdctl",    // nerdctl container and network state
+	"/var/lib/cni",        // CNI IPAM state
+	"/etc/cni/net.d",      // the network configs created by nerdctl
+}
+
+// ContainerRuntimeConfig selects the container runtime. Podman is used through its Docker-compatible API.
+//
+// The containerd runtime is refused by the validation until the bots can be isolated from each other on it:
+// nerdctl cannot attach the running service containers to a network per bot, so the bots would have to
+// share a network. The nerdctl client and the host setup above are kept for the future support.
+type ContainerRuntimeConfig struct {
+	Type ContainerRuntimeType `yaml:"type" json:"type" default:"docker" validate:"omitempty,oneof=docker podman"`
+	// Socket is the path of the runtime socket on the host. Rootless Podman usually
+	// listens on /run/user/<uid>/podman/podman.sock.
+	Socket      string `yaml:"socket" json:"socket,omitempty"`
+	Namespace   string `yaml:"namespace" json:"namespace" default:"zktoro"`
+	NerdctlPath string `yaml:"nerdctlPath" json:"nerdctlPath" default:"nerdctl"`
+
+	// InContainer is set when the config is loaded inside one of the node containers
+	// which reach the runtime through the mounted socket.
+	InContainer bool `yaml:"-" json:"_inContainer"`
+}
+
+// SocketPath returns the path of the runtime socket on the host.
+func (cfg ContainerRuntimeConfig) SocketPath() string {
+	if len(cfg.Socket) > 0 {
+		return cfg.Socket
+	}
+	switch cfg.Type {
+	case ContainerRuntimePodman:
+		return DefaultPodmanSocket
+	case ContainerRuntimeContainerd:
+		return DefaultContainerdSocket
+	default:
+		return DefaultDockerSocket
+	}
+}
+
+// MountedSocketPath returns the path which the runtime socket is mounted to in the node containers.
+// The Docker-compatible sockets are mounted to the Docker path so the clients can use the defaults.
+func (cfg ContainerRuntimeConfig) MountedSocketPath() string {
+	if cfg.Type == ContainerRuntimeContainerd {
+		return DefaultContainerdSocket
+	}
+	return DefaultDockerSocket
+}
+
+// WithRuntimeVolumes adds the volumes which give a node container access to the runtime.
+func (cfg ContainerRuntimeConfig) WithRuntimeVolumes(volumes map[string]string) map[string]string {
+	if volumes == nil {
+		volumes = make(map[string]string)
+	}
+	volumes[cfg.SocketPath()] = cfg.MountedSocketPath()
+	if cfg.Type == ContainerRuntimeContainerd {
+		for _, hostPath := range containerdHostPaths {
+			volumes[hostPath] = hostPath
+		}
+	}
+	return volumes
+}
+
+// PrivilegedClients tells if the node containers which manage the runtime need to be privileged.
+// nerdctl mounts the container snapshots and joins the network namespaces of the containers.
+func (cfg ContainerRuntimeConfig) PrivilegedClients() bool {
+	return cfg.Type == ContainerRuntimeContainerd
+}
+
+// AttachesNetworks tells if the runtime can attach the running containers to new networks. The bots run
+// in a shared network on the runtimes which cannot, since the service containers can join it only on creation.
+func (cfg ContainerRuntimeConfig) AttachesNetworks() bool {
+	return cfg.Type != ContainerRuntimeContainerd
+}
+
+// ClientSocketPath returns the socket path which the runtime clients in this process should use.
+func (cfg ContainerRuntimeConfig) ClientSocketPath() string {
+	if cfg.InContainer {
+		return cfg.MountedSocketPath()
+	}
+	return cfg.SocketPath()
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_ContainerRuntime(t *testing.T) {
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		runtimeType string
+		valid       bool
+	}{
+		{"docker", true},
+		{"podman", true},
+		// the bots cannot be isolated from each other on containerd yet
+		{"containerd", false},
+	} {
+		_, problems, err := CheckConfigYAML([]byte("containerRuntime:\n  type: " + tc.runtimeType + "\n"))
+		r.NoError(err)
+		var found bool
+		for _, problem := range problems {
+			if problem.Field == "containerRuntime.type" {
+				found = true
+				r.Equal("must be one of: docker, podman", problem.Message)
+			}
+		}
+		r.Equal(!tc.valid, found, tc.runtimeType)
+	}
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SchemaURL is the JSON Schema dialect of the config schema.
+const SchemaURL = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema describes the config file. It is generated from the yaml, default, validate,
+// description and secret tags of the config structs.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Const                interface{}            `json:"const,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	WriteOnly            bool                   `json:"writeOnly,omitempty"`
+}
+
+// yamlField is a field of a config struct by its YAML key. The fields of the inlined structs
+// are flattened.
+type yamlField struct {
+	Key   string
+	Field reflect.StructField
+	Index []int
+}
+
+func yamlFields(t reflect.Type) []yamlField {
+	var fields []yamlField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("yaml")
+		if strings.Contains(tag, ",inline") && field.Type.Kind() == reflect.Struct {
+			for _, inlined := range yamlFields(field.Type) {
+				inlined.Index = append([]int{i}, inlined.Index...)
+				fields = append(fields, inlined)
+			}
+			continue
+		}
+		name := yamlName(field)
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		fields = append(fields, yamlField{Key: name, Field: field, Index: []int{i}})
+	}
+	return fields
+}
+
+// findYAMLField finds the field of the struct type by the YAML key.
+func findYAMLField(t reflect.Type, key string) (yamlField, bool) {
+	for _, field := range yamlFields(t) {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return yamlField{}, false
+}
+
+// findYAMLFieldFold finds the field of the struct type by the YAML key while ignoring the case.
+func findYAMLFieldFold(t reflect.Type, key string) (yamlField, bool) {
+	for _, field := range yamlFields(t) {
+		if strings.EqualFold(field.Key, key) {
+			return field, true
+		}
+	}
+	return yamlField{}, false
+}
+
+// GenerateSchema generates the JSON Schema of the config file.
+func GenerateSchema() *JSONSchema {
+	schema := typeSchema(reflect.TypeOf(Config{}))
+	schema.Schema = SchemaURL
+	schema.Title = "zktoro node config"
+	schema.Description = "The config.yml of the zktoro scan node"
+	return schema
+}
+
+func typeSchema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &JSONSchema{
+			Type:                 "object",
+			Properties:           make(map[string]*JSONSchema),
+			AdditionalProperties: false,
+		}
+		for _, field := range yamlFields(t) {
+			fieldSchema := typeSchema(field.Field.Type)
+			applyTags(fieldSchema, field.Field)
+			if isRequired(field.Field) {
+				schema.Required = append(schema.Required, field.Key)
+			}
+			schema.Properties[field.Key] = fieldSchema
+		}
+		return schema
+
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: typeSchema(t.Elem())}
+
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: typeSchema(t.Elem())}
+
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+
+	default:
+		return &JSONSchema{Type: "string"}
+	}
+}
+
+func applyTags(schema *JSONSchema, field reflect.StructField) {
+	schema.Description = field.Tag.Get("description")
+	schema.WriteOnly = isSecret(field)
+	if defaultValue, ok := field.Tag.Lookup("default"); ok && defaultValue != "" {
+		schema.Default = parseDefault(schema, defaultValue)
+	}
+
+	// the rules after dive are for the items
+	scopes := []*ruleScope{{schema: schema}}
+	scope := scopes[0]
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if scope.schema.Items != nil {
+				scope = &ruleScope{schema: scope.schema.Items}
+				scopes = append(scopes, scope)
+			}
+		case "omitempty":
+			scope.omitEmpty = true
+		case "url":
+			scope.schema.Format = "uri"
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				scope.schema.Enum = append(scope.schema.Enum, value)
+			}
+		case "min":
+			if minimum, err := strconv.ParseFloat(param, 64); err == nil && scope.schema.Type != "string" {
+				scope.bounds.Minimum = &minimum
+			}
+		case "gt":
+			if minimum, err := strconv.ParseFloat(param, 64); err == nil {
+				scope.bounds.ExclusiveMinimum = &minimum
+			}
+		}
+	}
+	for _, scope := range scopes {
+		scope.applyBounds()
+	}
+}
+
+// ruleScope collects the validation rules of a field or of the items of a field.
+type ruleScope struct {
+	schema    *JSONSchema
+	omitEmpty bool
+	bounds    JSONSchema
+}
+
+// applyBounds sets the bounds of the numbers. The validator skips the other rules of the zero
+// values with omitempty, so the zero is allowed in addition to the bounded values.
+func (scope *ruleScope) applyBounds() {
+	if scope.bounds.Minimum == nil && scope.bounds.ExclusiveMinimum == nil {
+		return
+	}
+	if !scope.omitEmpty {
+		scope.schema.Minimum = scope.bounds.Minimum
+		scope.schema.ExclusiveMinimum = scope.bounds.ExclusiveMinimum
+		return
+	}
+	bounds := scope.bounds
+	scope.schema.AnyOf = []*JSONSchema{{Const: 0}, &bounds}
+}
+
+func parseDefault(schema *JSONSchema, defaultValue string) interface{} {
+	switch schema.Type {
+	case "boolean":
+		if b, err := strconv.ParseBool(defaultValue); err == nil {
+			return b
+		}
+	case "integer":
+		if i, err := strconv.ParseInt(defaultValue, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(defaultValue, 64); err == nil {
+			return f
+		}
+	case "object", "array":
+		// the JSON defaults of the structs are not repeated in the schema
+		return nil
+	}
+	return defaultValue
+}
+
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecret(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true"
+}
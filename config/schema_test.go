@@ -0,0 +1,44 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	r := require.New(t)
+
+	schema := GenerateSchema()
+	r.Equal(SchemaURL, schema.Schema)
+	r.Equal(false, schema.AdditionalProperties)
+
+	// defaults
+	r.Equal(int64(1), schema.Properties["chainId"].Default)
+	r.Equal("info", schema.Properties["log"].Properties["level"].Default)
+	cache := schema.Properties["jsonRpcProxy"].Properties["cache"]
+	r.Equal(int64(128), cache.Properties["maxSizeMb"].Default)
+	r.Equal(1.0, *cache.Properties["maxSizeMb"].Minimum)
+
+	// enums
+	r.Equal([]interface{}{"docker", "podman"}, schema.Properties["containerRuntime"].Properties["type"].Enum)
+
+	// secrets
+	r.True(schema.Properties["scan"].Properties["jsonRpc"].Properties["headers"].WriteOnly)
+	r.True(schema.Properties["localMode"].Properties["privateKeyHex"].WriteOnly)
+	r.False(schema.Properties["scan"].Properties["jsonRpc"].Properties["url"].WriteOnly)
+
+	// the zero is allowed with omitempty
+	pids := schema.Properties["resources"].Properties["agentMaxPids"]
+	r.Nil(pids.Minimum)
+	r.Len(pids.AnyOf, 2)
+	r.Equal(0, pids.AnyOf[0].Const)
+	r.Equal(1.0, *pids.AnyOf[1].Minimum)
+	cpus := schema.Properties["resources"].Properties["agentMaxCpus"]
+	r.Nil(cpus.ExclusiveMinimum)
+	r.Equal(0.0, *cpus.AnyOf[1].ExclusiveMinimum)
+
+	_, err := json.Marshal(schema)
+	r.NoError(err)
+}
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// minRedactedSecretLength avoids redacting the trivial values from the logs.
+const minRedactedSecretLength = 4
+
+// secretRefRegexp matches the secret references like "${env:NAME}", "${file:/path}" and
+// "${vault:zktoro/node#password}".
+var secretRefRegexp = regexp.MustCompile(`\$\{([a-zA-Z0-9_-]+):([^}]+)\}`)
+
+// SecretProvider resolves the references of a secret store. The name is the prefix of the
+// references, e.g. "vault" for "${vault:zktoro/node#password}".
+type SecretProvider interface {
+	Name() string
+	GetSecret(ref string) (string, error)
+}
+
+var (
+	secretProviders   = make(map[string]SecretProvider)
+	secretProvidersMu sync.RWMutex
+
+	// knownSecrets are sorted by the length so the longer secrets are redacted first in case
+	// they contain the shorter ones
+	knownSecrets   []string
+	knownSecretsMu sync.RWMutex
+)
+
+func init() {
+	RegisterSecretProvider(&envSecretProvider{})
+	RegisterSecretProvider(&fileSecretProvider{})
+}
+
+// RegisterSecretProvider makes a secret provider available to the config references.
+func RegisterSecretProvider(provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[provider.Name()] = provider
+}
+
+type envSecretProvider struct{}
+
+func (p *envSecretProvider) Name() string {
+	return "env"
+}
+
+func (p *envSecretProvider) GetSecret(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("env var %s is not set", ref)
+	}
+	return value, nil
+}
+
+type fileSecretProvider struct{}
+
+func (p *fileSecretProvider) Name() string {
+	return "file"
+}
+
+func (p *fileSecretProvider) GetSecret(ref string) (string, error) {
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// SecretError is a secret reference of a config field which could not be resolved.
+type SecretError struct {
+	Field string
+	Ref   string
+	Err   error
+}
+
+func (err *SecretError) Error() string {
+	return fmt.Sprintf("%s: failed to resolve %s: %v", err.Field, err.Ref, err.Err)
+}
+
+func (err *SecretError) Unwrap() error {
+	return err.Err
+}
+
+// ResolveSecrets replaces the secret references in the config values. The references which were
+// already resolved in cfg.ResolvedSecrets are not requested again. The resolved values and the
+// plaintext values of the secret fields are redacted from the logs.
+func ResolveSecrets(cfg *Config) error {
+	resolver := &secretResolver{
+		providers: make(map[string]SecretProvider),
+		resolved:  cfg.ResolvedSecrets,
+	}
+	if resolver.resolved == nil {
+		resolver.resolved = make(map[string]string)
+	}
+	secretProvidersMu.RLock()
+	for name, provider := range secretProviders {
+		resolver.providers[name] = provider
+	}
+	secretProvidersMu.RUnlock()
+
+	// the secret stores can be configured with the references to the env vars and the files
+	if err := resolver.resolve(reflect.ValueOf(&cfg.SecretsConfig).Elem(), "secrets", false); err != nil {
+		return err
+	}
+	if vault := newVaultSecretProvider(cfg.SecretsConfig.Vault); vault != nil {
+		resolver.providers[vault.Name()] = vault
+	}
+	if err := resolver.resolve(reflect.ValueOf(cfg).Elem(), "", false); err != nil {
+		return err
+	}
+	cfg.ResolvedSecrets = resolver.resolved
+	return nil
+}
+
+type secretResolver struct {
+	providers map[string]SecretProvider
+	resolved  map[string]string
+}
+
+func (resolver *secretResolver) resolve(v reflect.Value, fieldPath string, secret bool) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return resolver.resolve(v.Elem(), fieldPath, secret)
+
+	case reflect.Struct:
+		for _, field := range yamlFields(v.Type()) {
+			fieldVal := v.FieldByIndex(field.Index)
+			if err := resolver.resolve(fieldVal, joinFieldPath(fieldPath, field.Key), isSecret(field.Field)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolver.resolve(v.Index(i), fmt.Sprintf("%s[%d]", fieldPath, i), secret); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// the map values are not addressable so they are resolved in a copy
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			if err := resolver.resolve(value, joinFieldPath(fieldPath, fmt.Sprint(iter.Key().Interface())), secret); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), value)
+		}
+
+	case reflect.String:
+		value, err := resolver.resolveString(v.String(), fieldPath)
+		if err != nil {
+			return err
+		}
+		if v.CanSet() {
+			v.SetString(value)
+		}
+		if secret {
+			addKnownSecret(value)
+		}
+	}
+	return nil
+}
+
+func (resolver *secretResolver) resolveString(s, fieldPath string) (string, error) {
+	matches := secretRefRegexp.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	var sb strings.Builder
+	var last int
+	for _, match := range matches {
+		ref := s[match[0]:match[1]]
+		value, ok := resolver.resolved[ref]
+		if !ok {
+			providerName, providerRef := s[match[2]:match[3]], s[match[4]:match[5]]
+			provider, found := resolver.providers[providerName]
+			if !found {
+				return "", &SecretError{Field: fieldPath, Ref: ref, Err: fmt.Errorf("unknown secret provider '%s'", providerName)}
+			}
+			var err error
+			value, err = provider.GetSecret(providerRef)
+			if err != nil {
+				return "", &SecretError{Field: fieldPath, Ref: ref, Err: err}
+			}
+			resolver.resolved[ref] = value
+		}
+		addKnownSecret(value)
+		sb.WriteString(s[last:match[0]])
+		sb.WriteString(value)
+		last = match[1]
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), nil
+}
+
+// LoadResolvedSecrets reads the secrets which were resolved on the host and copied to the container.
+func LoadResolvedSecrets(secretsPath string) (map[string]string, error) {
+	b, err := os.ReadFile(secretsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resolved map[string]string
+	if err := json.Unmarshal(b, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to decode the resolved secrets: %v", err)
+	}
+	for _, value := range resolved {
+		addKnownSecret(value)
+	}
+	return resolved, nil
+}
+
+// ResolvedSecretsFile encodes the resolved secrets of the config so they can be copied to the
+// containers which cannot access the secret stores of the host.
+func ResolvedSecretsFile(cfg Config) []byte {
+	b, _ := json.Marshal(cfg.ResolvedSecrets)
+	return b
+}
+
+func addKnownSecret(value string) {
+	if len(value) < minRedactedSecretLength {
+		return
+	}
+	values := []string{value}
+	// the secrets may be escaped in the JSON logs
+	if b, err := json.Marshal(value); err == nil {
+		if escaped := string(b[1 : len(b)-1]); escaped != value {
+			values = append(values, escaped)
+		}
+	}
+
+	knownSecretsMu.Lock()
+	defer knownSecretsMu.Unlock()
+	for _, value := range values {
+		i := sort.Search(len(knownSecrets), func(i int) bool {
+			return len(knownSecrets[i]) <= len(value)
+		})
+		if containsString(knownSecrets, value) {
+			continue
+		}
+		knownSecrets = append(knownSecrets, "")
+		copy(knownSecrets[i+1:], knownSecrets[i:])
+		knownSecrets[i] = value
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactSecrets replaces the known secret values in the string.
+func RedactSecrets(s string) string {
+	knownSecretsMu.RLock()
+	defer knownSecretsMu.RUnlock()
+	for _, secret := range knownSecrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// RedactingFormatter redacts the known secret values from the formatted log entries.
+type RedactingFormatter struct {
+	log.Formatter
+}
+
+// Format implements log.Formatter.
+func (f *RedactingFormatter) Format(entry *log.Entry) ([]byte, error) {
+	b, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(RedactSecrets(string(b))), nil
+}
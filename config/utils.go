@@ -26,8 +26,10 @@ func InitLogLevel(cfg Config) error {
 	} else {
 		log.SetLevel(log.InfoLevel)
 	}
-	log.SetFormatter(&log.TextFormatter{
-		FullTimestamp: true,
+	log.SetFormatter(&RedactingFormatter{
+		Formatter: &log.TextFormatter{
+			FullTimestamp: true,
+		},
 	})
 	return nil
 }
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultVaultSecretKey = "value"
+
+// vaultSecretProvider reads the secrets from a HashiCorp Vault compatible KV secrets engine. The
+// references are like "<path>#<key>" and the key defaults to "value".
+type vaultSecretProvider struct {
+	cfg        VaultConfig
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	secrets map[string]map[string]interface{} // by path
+}
+
+// newVaultSecretProvider creates the Vault provider if there is an address in the config or in
+// the env.
+func newVaultSecretProvider(cfg VaultConfig) *vaultSecretProvider {
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Address == "" {
+		return nil
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &vaultSecretProvider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: timeout},
+		secrets:    make(map[string]map[string]interface{}),
+	}
+}
+
+func (p *vaultSecretProvider) Name() string {
+	return "vault"
+}
+
+func (p *vaultSecretProvider) GetSecret(ref string) (string, error) {
+	secretPath, key, found := strings.Cut(ref, "#")
+	if !found {
+		key = defaultVaultSecretKey
+	}
+	secretPath = strings.Trim(secretPath, "/")
+	if secretPath == "" {
+		return "", fmt.Errorf("empty secret path")
+	}
+
+	data, err := p.readSecret(secretPath)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s' does not have the key '%s'", secretPath, key)
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	default:
+		b, err := json.Marshal(value)
+		return string(b), err
+	}
+}
+
+func (p *vaultSecretProvider) readSecret(secretPath string) (map[string]interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if data, ok := p.secrets[secretPath]; ok {
+		return data, nil
+	}
+
+	mount := strings.Trim(p.cfg.Mount, "/")
+	url := fmt.Sprintf("%s/v1/%s/%s", strings.TrimRight(p.cfg.Address, "/"), mount, secretPath)
+	if p.cfg.KVVersion != 1 {
+		url = fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(p.cfg.Address, "/"), mount, secretPath)
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.cfg.Token != "" {
+		req.Header.Set("X-Vault-Token", p.cfg.Token)
+	}
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault responded with '%d' for secret '%s'", resp.StatusCode, secretPath)
+	}
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode the vault response: %v", err)
+	}
+	var data map[string]interface{}
+	if p.cfg.KVVersion == 1 {
+		err = json.Unmarshal(body.Data, &data)
+	} else {
+		// the kv v2 secrets are versioned and the values are nested
+		var versioned struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		err = json.Unmarshal(body.Data, &versioned)
+		data = versioned.Data
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the vault secret: %v", err)
+	}
+	p.secrets[secretPath] = data
+	return data, nil
+}
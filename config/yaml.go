@@ -0,0 +1,573 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/creasty/defaults"
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "<redacted>"
+
+var (
+	yamlErrLineRegexp  = regexp.MustCompile(`line (\d+): (.*)`)
+	yamlErrValueRegexp = regexp.MustCompile("`([^`]*)`")
+	fieldIndexRegexp   = regexp.MustCompile(`\[([^\]]+)\]`)
+)
+
+// ConfigError is a problem at a position of the config file. The line and the column are
+// zero if the position is unknown.
+type ConfigError struct {
+	Field   string
+	Line    int
+	Column  int
+	Message string
+	Warning bool
+}
+
+func (err *ConfigError) Error() string {
+	var sb strings.Builder
+	switch {
+	case err.Line > 0 && err.Column > 0:
+		fmt.Fprintf(&sb, "line %d, column %d: ", err.Line, err.Column)
+	case err.Line > 0:
+		fmt.Fprintf(&sb, "line %d: ", err.Line)
+	}
+	if err.Field != "" {
+		fmt.Fprintf(&sb, "%s: ", err.Field)
+	}
+	sb.WriteString(err.Message)
+	return sb.String()
+}
+
+// CheckConfigYAML parses and validates the config file content. The returned problems include the
+// syntax errors, the type errors, the failed validations and the unknown fields as warnings, sorted
+// by their positions. The config is returned with the defaults if it could be decoded.
+func CheckConfigYAML(b []byte) (cfg Config, problems []*ConfigError, err error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return cfg, []*ConfigError{parseYAMLError(err.Error(), nil)}, nil
+	}
+	doc := documentContent(&root)
+
+	if doc != nil {
+		if err := doc.Decode(&cfg); err != nil {
+			var typeErr *yaml.TypeError
+			if !errors.As(err, &typeErr) {
+				return cfg, nil, err
+			}
+			for _, msg := range typeErr.Errors {
+				problems = append(problems, parseYAMLError(msg, doc))
+			}
+		}
+		problems = append(problems, findUnknownFields(doc, reflect.TypeOf(cfg), "")...)
+	}
+	if err := defaults.Set(&cfg); err != nil {
+		return cfg, nil, err
+	}
+
+	err = ResolveSecrets(&cfg)
+	var secretErr *SecretError
+	switch {
+	case errors.As(err, &secretErr):
+		line, column := findFieldPosition(doc, secretErr.Field)
+		problems = append(problems, &ConfigError{
+			Field:   secretErr.Field,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf("failed to resolve %s: %v", secretErr.Ref, secretErr.Err),
+		})
+	case err != nil:
+		return cfg, nil, err
+	}
+
+	err = Validate(&cfg)
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		for _, field := range validationErr.Fields {
+			// the unresolved reference is already reported
+			if secretErr != nil && secretErr.Field == field {
+				continue
+			}
+			line, column := findFieldPosition(doc, field)
+			problems = append(problems, &ConfigError{
+				Field:   field,
+				Line:    line,
+				Column:  column,
+				Message: validationMessage(validationErr.Rules[field]),
+			})
+		}
+	case err != nil:
+		return cfg, nil, err
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return cfg, problems, nil
+}
+
+func documentContent(root *yaml.Node) *yaml.Node {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0]
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	return root
+}
+
+// parseYAMLError converts the YAML error messages like "line 3: cannot unmarshal !!str `abc` into int".
+// The column is found by looking for the value in the document.
+func parseYAMLError(msg string, doc *yaml.Node) *ConfigError {
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	matches := yamlErrLineRegexp.FindStringSubmatch(msg)
+	if matches == nil {
+		return &ConfigError{Message: msg}
+	}
+	line, _ := strconv.Atoi(matches[1])
+	configErr := &ConfigError{Line: line, Message: matches[2]}
+	if valueMatches := yamlErrValueRegexp.FindStringSubmatch(matches[2]); valueMatches != nil && doc != nil {
+		if node := findNodeOnLine(doc, line, valueMatches[1]); node != nil {
+			configErr.Column = node.Column
+		}
+	}
+	return configErr
+}
+
+func findNodeOnLine(node *yaml.Node, line int, value string) *yaml.Node {
+	if node.Line == line && node.Kind == yaml.ScalarNode && strings.HasPrefix(node.Value, value) {
+		return node
+	}
+	for _, child := range node.Content {
+		if found := findNodeOnLine(child, line, value); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findUnknownFields reports the keys which do not match any field of the config type.
+func findUnknownFields(node *yaml.Node, t reflect.Type, prefix string) (problems []*ConfigError) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			fieldPath := joinFieldPath(prefix, keyNode.Value)
+			field, ok := findYAMLField(t, keyNode.Value)
+			if !ok {
+				problems = append(problems, &ConfigError{
+					Field:   fieldPath,
+					Line:    keyNode.Line,
+					Column:  keyNode.Column,
+					Message: "unknown field",
+					Warning: true,
+				})
+				continue
+			}
+			problems = append(problems, findUnknownFields(valueNode, field.Field.Type, fieldPath)...)
+		}
+
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fieldPath := joinFieldPath(prefix, node.Content[i].Value)
+			problems = append(problems, findUnknownFields(node.Content[i+1], t.Elem(), fieldPath)...)
+		}
+
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for i, item := range node.Content {
+			problems = append(problems, findUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	return
+}
+
+func joinFieldPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// findFieldPosition finds the position of a field like "scan.jsonRpc.endpoints[0].url". The position
+// of the closest parent is returned for the missing fields.
+func findFieldPosition(doc *yaml.Node, fieldPath string) (line, column int) {
+	node := doc
+	for _, segment := range strings.Split(fieldPath, ".") {
+		if node == nil {
+			break
+		}
+		key, indexes := splitFieldSegment(segment)
+
+		keyNode, valueNode := findMappingValue(node, key)
+		if valueNode == nil {
+			// e.g. the Go names of the inlined structs
+			continue
+		}
+		line, column = keyNode.Line, keyNode.Column
+		node = valueNode
+		for _, index := range indexes {
+			next := findIndexedValue(node, index)
+			if next == nil {
+				return
+			}
+			node = next
+			line, column = node.Line, node.Column
+		}
+	}
+	return
+}
+
+// splitFieldSegment splits a segment like "endpoints[0]" to the key and the indexes.
+func splitFieldSegment(segment string) (key string, indexes []string) {
+	i := strings.Index(segment, "[")
+	if i < 0 {
+		return segment, nil
+	}
+	for _, matches := range fieldIndexRegexp.FindAllStringSubmatch(segment[i:], -1) {
+		indexes = append(indexes, matches[1])
+	}
+	return segment[:i], indexes
+}
+
+func findMappingValue(node *yaml.Node, key string) (keyNode, valueNode *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func findIndexedValue(node *yaml.Node, index string) *yaml.Node {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i >= len(node.Content) {
+			return nil
+		}
+		return node.Content[i]
+	case yaml.MappingNode:
+		_, valueNode := findMappingValue(node, index)
+		return valueNode
+	}
+	return nil
+}
+
+func validationMessage(rule string) string {
+	name, param, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		return "is required"
+	case "required_with":
+		return fmt.Sprintf("is required with %s", param)
+	case "required_unless":
+		return fmt.Sprintf("is required unless %s", param)
+	case "excluded_with":
+		return fmt.Sprintf("cannot be set with %s", param)
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(param), ", "))
+	case "min":
+		return fmt.Sprintf("must be at least %s", param)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", param)
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", param)
+	case "hostname_port":
+		return "must be a host:port address"
+	case "hostname|hostname_port":
+		return "must be a hostname or a host:port address"
+	case "eth_addr":
+		return "must be an Ethereum address"
+	default:
+		return fmt.Sprintf("failed the '%s' rule", rule)
+	}
+}
+
+// EffectiveConfigYAML encodes the config with the defaults and redacts the secrets.
+func EffectiveConfigYAML(cfg Config) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+	RedactYAML(&node)
+	b, err := encodeYAML(&node)
+	if err != nil {
+		return nil, err
+	}
+	// the resolved references of the other fields
+	return []byte(RedactSecrets(string(b))), nil
+}
+
+// RedactYAML replaces the values of the secret config fields in the document.
+func RedactYAML(root *yaml.Node) {
+	if doc := documentContent(root); doc != nil {
+		redactNode(doc, reflect.TypeOf(Config{}))
+	}
+}
+
+func redactNode(node *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			field, ok := findYAMLField(t, node.Content[i].Value)
+			if !ok {
+				continue
+			}
+			valueNode := node.Content[i+1]
+			if !isSecret(field.Field) {
+				redactNode(valueNode, field.Field.Type)
+				continue
+			}
+			if valueNode.Kind == yaml.MappingNode {
+				// e.g. the headers
+				for j := 1; j < len(valueNode.Content); j += 2 {
+					redactScalar(valueNode.Content[j])
+				}
+				continue
+			}
+			redactScalar(valueNode)
+		}
+
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 1; i < len(node.Content); i += 2 {
+			redactNode(node.Content[i], t.Elem())
+		}
+
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for _, item := range node.Content {
+			redactNode(item, t.Elem())
+		}
+	}
+}
+
+func redactScalar(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Value == "" || node.Tag == "!!null" {
+		return
+	}
+	node.Value = redactedValue
+	node.Tag = "!!str"
+	node.Style = 0
+}
+
+// renamedField maps the older path of a config field to the current path. The paths use the
+// YAML keys like "resources.agentMaxMemoryMib".
+type renamedField struct {
+	From string
+	To   string
+}
+
+// renamedFields are the other spellings of the fields which are moved to the current fields by the
+// migration. The keys which differ only by the case are corrected without an entry.
+var renamedFields = []renamedField{
+	{From: "resources.agentMaxMemoryMb", To: "resources.agentMaxMemoryMib"},
+	{From: "resources.agentMaxDiskMb", To: "resources.agentMaxDiskMib"},
+	{From: "resources.budget.memoryMb", To: "resources.budget.memoryMib"},
+	{From: "resources.budget.diskMb", To: "resources.budget.diskMib"},
+	{From: "jsonRpcProxy.rateLimiting", To: "jsonRpcProxy.rateLimit"},
+}
+
+// MigrateYAML upgrades an older config file to the current layout and returns the applied changes.
+// The renamed fields are moved to their current paths and the keys which differ from a field only
+// by the case are corrected. The other unknown fields are kept and returned as the problems. The
+// comments are kept.
+func MigrateYAML(b []byte) (migrated []byte, changes []string, problems []*ConfigError, err error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return nil, nil, nil, err
+	}
+	doc := documentContent(&root)
+	if doc == nil {
+		return b, nil, nil, nil
+	}
+
+	// the configs of the older deployments were wrapped under a key
+	if _, wrapped := findMappingValue(doc, DefaultConfigWrapperKey); wrapped != nil && len(doc.Content) == 2 {
+		doc = wrapped
+		root.Content = []*yaml.Node{doc}
+		changes = append(changes, fmt.Sprintf("unwrapped the config from '%s'", DefaultConfigWrapperKey))
+	}
+
+	for _, renamed := range renamedFields {
+		keyNode, valueNode := findFieldKeyValue(doc, renamed.From)
+		if keyNode == nil {
+			continue
+		}
+		if _, existing := findFieldKeyValue(doc, renamed.To); existing != nil {
+			problems = append(problems, &ConfigError{
+				Field:   renamed.From,
+				Line:    keyNode.Line,
+				Column:  keyNode.Column,
+				Message: fmt.Sprintf("renamed to '%s' which is also set", renamed.To),
+				Warning: true,
+			})
+			continue
+		}
+		removeFieldNode(doc, renamed.From)
+		setFieldNode(doc, renamed.To, keyNode, valueNode)
+		changes = append(changes, fmt.Sprintf("moved '%s' to '%s' (line %d)", renamed.From, renamed.To, keyNode.Line))
+	}
+
+	changes = append(changes, fixFieldCase(doc, reflect.TypeOf(Config{}), "")...)
+
+	// the unknown fields may be mistyped so they are not removed
+	problems = append(problems, findUnknownFields(doc, reflect.TypeOf(Config{}), "")...)
+
+	if len(changes) == 0 {
+		return b, nil, problems, nil
+	}
+	migrated, err = encodeYAML(&root)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return migrated, changes, problems, nil
+}
+
+// fixFieldCase renames the keys which match a field only when the case is ignored.
+func fixFieldCase(node *yaml.Node, t reflect.Type, prefix string) (changes []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			field, ok := findYAMLField(t, keyNode.Value)
+			if !ok {
+				field, ok = findYAMLFieldFold(t, keyNode.Value)
+				if !ok {
+					continue
+				}
+				if existing, _ := findMappingValue(node, field.Key); existing != nil {
+					continue
+				}
+				changes = append(changes, fmt.Sprintf(
+					"renamed '%s' to '%s' (line %d)", joinFieldPath(prefix, keyNode.Value), joinFieldPath(prefix, field.Key), keyNode.Line,
+				))
+				keyNode.Value = field.Key
+			}
+			changes = append(changes, fixFieldCase(valueNode, field.Field.Type, joinFieldPath(prefix, field.Key))...)
+		}
+
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fieldPath := joinFieldPath(prefix, node.Content[i].Value)
+			changes = append(changes, fixFieldCase(node.Content[i+1], t.Elem(), fieldPath)...)
+		}
+
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for i, item := range node.Content {
+			changes = append(changes, fixFieldCase(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	return
+}
+
+// removeFieldNode removes the key of a field path like "localMode.sinks[0].url".
+func removeFieldNode(doc *yaml.Node, fieldPath string) bool {
+	parentPath, key := splitFieldPath(fieldPath)
+	parent := doc
+	if parentPath != "" {
+		parent = findFieldNode(doc, parentPath)
+	}
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// setFieldNode adds the key and the value at a field path like "resources.budget.memoryMib"
+// and creates the missing parents.
+func setFieldNode(doc *yaml.Node, fieldPath string, keyNode, valueNode *yaml.Node) {
+	parentPath, key := splitFieldPath(fieldPath)
+	parent := doc
+	if parentPath != "" {
+		for _, segment := range strings.Split(parentPath, ".") {
+			_, next := findMappingValue(parent, segment)
+			if next == nil {
+				next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, next)
+			}
+			parent = next
+		}
+	}
+	keyNode.Value = key
+	parent.Content = append(parent.Content, keyNode, valueNode)
+}
+
+func splitFieldPath(fieldPath string) (parentPath, key string) {
+	if i := strings.LastIndex(fieldPath, "."); i >= 0 {
+		return fieldPath[:i], fieldPath[i+1:]
+	}
+	return "", fieldPath
+}
+
+func findFieldKeyValue(doc *yaml.Node, fieldPath string) (keyNode, valueNode *yaml.Node) {
+	parentPath, key := splitFieldPath(fieldPath)
+	parent := doc
+	if parentPath != "" {
+		parent = findFieldNode(doc, parentPath)
+	}
+	if parent == nil {
+		return nil, nil
+	}
+	return findMappingValue(parent, key)
+}
+
+func findFieldNode(doc *yaml.Node, fieldPath string) *yaml.Node {
+	node := doc
+	for _, segment := range strings.Split(fieldPath, ".") {
+		key, indexes := splitFieldSegment(segment)
+		_, node = findMappingValue(node, key)
+		if node == nil {
+			return nil
+		}
+		for _, index := range indexes {
+			if node = findIndexedValue(node, index); node == nil {
+				return nil
+			}
+		}
+	}
+	return node
+}
+
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func findConfigProblem(problems []*ConfigError, field string) *ConfigError {
+	for _, problem := range problems {
+		if problem.Field == field {
+			return problem
+		}
+	}
+	return nil
+}
+
+func TestCheckConfigYAML_Positions(t *testing.T) {
+	r := require.New(t)
+
+	_, problems, err := CheckConfigYAML([]byte(`
+chainId: 1
+log:
+  level: debug
+  maxLogFiles: many
+scan:
+  jsonRpc:
+    url: not a url
+    timeout: 5s
+`))
+	r.NoError(err)
+
+	// type error
+	r.Len(problems, 3)
+	r.Equal(5, problems[0].Line)
+	r.Equal(16, problems[0].Column)
+	r.False(problems[0].Warning)
+
+	// validation error
+	problem := findConfigProblem(problems, "scan.jsonRpc.url")
+	r.NotNil(problem)
+	r.Equal(8, problem.Line)
+	r.Equal(5, problem.Column)
+	r.False(problem.Warning)
+
+	// unknown field
+	problem = findConfigProblem(problems, "scan.jsonRpc.timeout")
+	r.NotNil(problem)
+	r.Equal(9, problem.Line)
+	r.Equal(5, problem.Column)
+	r.True(problem.Warning)
+
+	// syntax error
+	_, problems, err = CheckConfigYAML([]byte("chainId: 1\nlog:\n  level: [debug\n"))
+	r.NoError(err)
+	r.Len(problems, 1)
+	r.Greater(problems[0].Line, 0)
+}
+
+func TestEffectiveConfigYAML_Redacted(t *testing.T) {
+	r := require.New(t)
+
+	t.Setenv("ZKTORO_TEST_RPC_URL", "https://rpc.example.com/resolved-api-key")
+	var cfg Config
+	cfg.Scan.JsonRpc.Url = "${env:ZKTORO_TEST_RPC_URL}"
+	cfg.Scan.JsonRpc.Headers = map[string]string{"Authorization": "Bearer header-token"}
+	cfg.LocalModeConfig.PrivateKeyHex = "plaintext-private-key"
+	r.NoError(ResolveSecrets(&cfg))
+
+	b, err := EffectiveConfigYAML(cfg)
+	r.NoError(err)
+	s := string(b)
+	r.NotContains(s, "resolved-api-key")
+	r.NotContains(s, "header-token")
+	r.NotContains(s, "plaintext-private-key")
+
+	var effective Config
+	r.NoError(yaml.Unmarshal(b, &effective))
+	r.Equal(redactedValue, effective.Scan.JsonRpc.Url)
+	r.Equal(redactedValue, effective.Scan.JsonRpc.Headers["Authorization"])
+	r.Equal(redactedValue, effective.LocalModeConfig.PrivateKeyHex)
+}
+
+func TestMigrateYAML(t *testing.T) {
+	r := require.New(t)
+
+	migrated, changes, problems, err := MigrateYAML([]byte(`x-zktoro-config:
+  chainID: 137
+  resources:
+    # the memory of each bot
+    agentMaxMemoryMb: 1000
+    budget:
+      diskMb: 5000
+  jsonRpcProxy:
+    rateLimiting:
+      rate: 10
+  unknownField: true
+`))
+	r.NoError(err)
+	r.Len(changes, 5)
+
+	cfg, checkProblems, err := CheckConfigYAML(migrated)
+	r.NoError(err)
+	r.Equal(137, cfg.ChainID)
+	r.Equal(1000, cfg.ResourcesConfig.AgentMaxMemoryMiB)
+	r.Equal(5000, cfg.ResourcesConfig.Budget.DiskMiB)
+	r.NotNil(cfg.JsonRpcProxy.RateLimitConfig)
+	r.Contains(string(migrated), "# the memory of each bot")
+
+	// the unknown fields are kept
+	r.Contains(string(migrated), "unknownField: true")
+	r.Len(problems, 1)
+	r.Equal("unknownField", problems[0].Field)
+	r.True(problems[0].Warning)
+	r.NotNil(findConfigProblem(checkProblems, "unknownField"))
+
+	// up to date
+	again, changes, _, err := MigrateYAML(migrated)
+	r.NoError(err)
+	r.Empty(changes)
+	r.Equal(migrated, again)
+}
+
+func TestMigrateYAML_RenamedConflict(t *testing.T) {
+	r := require.New(t)
+
+	original := []byte(`resources:
+  agentMaxMemoryMb: 1000
+  agentMaxMemoryMib: 2000
+`)
+	migrated, changes, problems, err := MigrateYAML(original)
+	r.NoError(err)
+	r.Empty(changes)
+	r.Equal(original, migrated)
+
+	problem := findConfigProblem(problems, "resources.agentMaxMemoryMb")
+	r.NotNil(problem)
+	r.Equal(2, problem.Line)
+	r.Equal("renamed to 'resources.agentMaxMemoryMib' which is also set", problem.Message)
+}
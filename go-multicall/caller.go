@@ -63,12 +63,16 @@ func (caller *Caller) Call(opts *bind.CallOpts, calls ...*Call) ([]*Call, error)
 
 	results, err := caller.contract.Aggregate3(opts, multiCalls)
 	if err != nil {
-		return calls, fmt.Errorf("multicall failed: %v", err)
+		return calls, fmt.Errorf("multicall failed: %w", err)
 	}
 
 	for i, result := range results {
 		call := calls[i] // index always matches
 		call.Failed = !result.Success
+		// the return data of a failed call is the revert data
+		if call.Failed {
+			continue
+		}
 		if err := call.Unpack(result.ReturnData); err != nil {
 			return calls, fmt.Errorf("failed to unpack call outputs at index [%d]: %v", i, err)
 		}
@@ -90,13 +90,14 @@ const testABI = `[
 
 type multicallStub struct {
 	returnData func(calls []contract_multicall.Multicall3Call3) [][]byte
+	failures   map[int]bool
 }
 
 func (ms *multicallStub) Aggregate3(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call3) (results []contract_multicall.Multicall3Result, err error) {
 	allReturnData := ms.returnData(calls)
-	for _, returnData := range allReturnData {
+	for i, returnData := range allReturnData {
 		results = append(results, contract_multicall.Multicall3Result{
-			Success:    true,
+			Success:    !ms.failures[i],
 			ReturnData: returnData,
 		})
 	}
@@ -173,6 +174,38 @@ func TestCaller_TwoCalls(t *testing.T) {
 	r.Equal(values2.Val6, call2Out.Val6)
 }
 
+func TestCaller_FailedCall(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(oneValueType), "testFunc", true)
+	call2 := testContract.NewCall(new(oneValueType), "testFunc", true).AllowFailure()
+
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) [][]byte {
+				return [][]byte{
+					calls[0].CallData[4:],
+					// revert data which can't be unpacked as outputs
+					{0x08, 0xc3, 0x79, 0xa0},
+				}
+			},
+			failures: map[int]bool{1: true},
+		},
+	}
+
+	calls, err := caller.Call(nil, call1, call2)
+	r.NoError(err)
+	r.Len(calls, 2)
+	r.False(calls[0].Failed)
+	r.True(calls[0].Outputs.(*oneValueType).Val1)
+	r.True(calls[1].CanFail)
+	r.True(calls[1].Failed)
+	r.False(calls[1].Outputs.(*oneValueType).Val1)
+}
+
 const emptyABI = `[
 	{
 		"constant":true,
@@ -234,6 +267,10 @@ const oneValueABI = `[
 	}
 ]`
 
+type oneValueType struct {
+	Val1 bool
+}
+
 func TestCaller_BadInput(t *testing.T) {
 	r := require.New(t)
 
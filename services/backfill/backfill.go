@@ -0,0 +1,396 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"zktoro/clients/agentgrpc"
+	"zktoro/config"
+
+	"zktoro/zktoro-core-go/domain"
+	"zktoro/zktoro-core-go/ethereum"
+	"zktoro/zktoro-core-go/feeds"
+	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/protocol/alerthash"
+	"zktoro/zktoro-core-go/utils"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultConcurrency    = 8
+	defaultRequestTimeout = 30 * time.Second
+	botDialTimeout        = 2 * time.Minute
+	maxRequestAttempts    = 3
+	maxBotResponseSize    = 10000000 // 10M
+)
+
+// Config contains the backfill parameters.
+type Config struct {
+	From           uint64
+	To             uint64
+	ChainID        uint64
+	Bots           []config.AgentConfig
+	Tracing        bool
+	Concurrency    int
+	RequestTimeout time.Duration
+	OutputPath     string
+	CheckpointPath string
+	Reset          bool
+
+	Client      ethereum.Client
+	TraceClient ethereum.Client
+	Launcher    BotLauncher
+}
+
+// Summary is the result of a backfill run.
+type Summary struct {
+	Blocks       uint64
+	Transactions uint64
+	Findings     uint64
+	BotErrors    uint64
+	LastBlock    uint64
+	AlreadyDone  bool
+	ResumedBlock *uint64
+}
+
+type backfillBot struct {
+	config.AgentConfig
+	client agentgrpc.Client
+}
+
+// Backfiller replays a block range through the bots and writes the findings in the
+// order of the blocks, the transactions and the bots so that the output of the same
+// range is the same in every run.
+type Backfiller struct {
+	cfg        Config
+	bots       []*backfillBot
+	checkpoint *Checkpoint
+	writer     *FindingWriter
+	summary    Summary
+}
+
+// NewBackfiller creates a new backfiller.
+func NewBackfiller(cfg Config) (*Backfiller, error) {
+	if cfg.From > cfg.To {
+		return nil, fmt.Errorf("invalid block range: %d > %d", cfg.From, cfg.To)
+	}
+	if len(cfg.Bots) == 0 {
+		return nil, errors.New("no bots to backfill")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+	return &Backfiller{cfg: cfg}, nil
+}
+
+func (b *Backfiller) botImages() []string {
+	var images []string
+	for _, bot := range b.cfg.Bots {
+		images = append(images, bot.Image)
+	}
+	return images
+}
+
+// Run processes the blocks which were not processed in the previous runs.
+func (b *Backfiller) Run(ctx context.Context) (*Summary, error) {
+	if b.cfg.Reset {
+		for _, filePath := range []string{b.cfg.CheckpointPath, b.cfg.OutputPath} {
+			if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+	}
+
+	var err error
+	b.checkpoint, err = LoadCheckpoint(b.cfg.CheckpointPath, b.cfg.From, b.cfg.To, b.botImages())
+	if err != nil {
+		return nil, err
+	}
+	if b.checkpoint.IsDone() {
+		b.summary.AlreadyDone = true
+		b.summary.LastBlock = b.cfg.To
+		return &b.summary, nil
+	}
+	if b.checkpoint.LastBlock != nil {
+		resumed := b.checkpoint.NextBlock()
+		b.summary.ResumedBlock = &resumed
+	}
+
+	b.writer, err = OpenFindingWriter(b.cfg.OutputPath, b.checkpoint.OutputOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer b.writer.Close()
+
+	defer b.cfg.Launcher.Close()
+	if err := b.startBots(ctx); err != nil {
+		return nil, err
+	}
+	defer b.closeBots()
+
+	blockFeed, err := feeds.NewBlockFeed(ctx, b.cfg.Client, b.cfg.TraceClient, feeds.BlockFeedConfig{
+		Start:   new(big.Int).SetUint64(b.checkpoint.NextBlock()),
+		End:     new(big.Int).SetUint64(b.cfg.To),
+		ChainID: new(big.Int).SetUint64(b.cfg.ChainID),
+		Tracing: b.cfg.Tracing,
+		// the output is append-only so the range is expected to be final
+		ReorgWindow: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	errCh := blockFeed.Subscribe(func(evt *domain.BlockEvent) error {
+		return b.handleBlock(ctx, evt)
+	})
+	blockFeed.Start()
+
+	err = <-errCh
+	if err != feeds.ErrEndBlockReached {
+		return &b.summary, err
+	}
+	return &b.summary, nil
+}
+
+func (b *Backfiller) startBots(ctx context.Context) error {
+	for _, botCfg := range b.cfg.Bots {
+		addr, err := b.cfg.Launcher.Launch(ctx, botCfg)
+		if err != nil {
+			return err
+		}
+
+		dialCtx, cancel := context.WithTimeout(ctx, botDialTimeout)
+		conn, err := grpc.DialContext(
+			dialCtx, addr,
+			grpc.WithInsecure(),
+			grpc.WithBlock(),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxBotResponseSize)),
+		)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to connect to bot %s: %v", botCfg.ID, err)
+		}
+		client := agentgrpc.NewClient()
+		client.WithConn(conn)
+		bot := &backfillBot{AgentConfig: botCfg, client: client}
+		b.bots = append(b.bots, bot)
+
+		resp, err := client.Initialize(ctx, &protocol.InitializeRequest{AgentId: botCfg.ID})
+		if err != nil && status.Code(err) != codes.Unimplemented {
+			return fmt.Errorf("failed to initialize bot %s: %v", botCfg.ID, err)
+		}
+		if resp != nil && resp.Status == protocol.ResponseStatus_ERROR {
+			return fmt.Errorf("bot %s failed to initialize", botCfg.ID)
+		}
+		log.WithFields(log.Fields{
+			"bot":   botCfg.ID,
+			"image": botCfg.Image,
+		}).Info("started bot")
+	}
+	return nil
+}
+
+func (b *Backfiller) closeBots() {
+	for _, bot := range b.bots {
+		bot.client.Close()
+	}
+}
+
+// blockResults contains the responses of the bots to a block and its transactions.
+type blockResults struct {
+	block    *protocol.EvaluateBlockRequest
+	txs      []*protocol.EvaluateTxRequest
+	blockRes []*protocol.EvaluateBlockResponse
+	txRes    [][]*protocol.EvaluateTxResponse
+}
+
+func (b *Backfiller) handleBlock(ctx context.Context, evt *domain.BlockEvent) error {
+	blockNumber, err := utils.HexToBigInt(evt.Block.Number)
+	if err != nil {
+		return err
+	}
+	logger := log.WithField("block", blockNumber.Uint64())
+
+	blockMsg, err := evt.ToMessage()
+	if err != nil {
+		return fmt.Errorf("failed to convert block %d: %v", blockNumber.Uint64(), err)
+	}
+	results := &blockResults{
+		block:    &protocol.EvaluateBlockRequest{RequestId: blockMsg.BlockHash, Event: blockMsg},
+		blockRes: make([]*protocol.EvaluateBlockResponse, len(b.bots)),
+	}
+	for i := range evt.Block.Transactions {
+		txEvt := &domain.TransactionEvent{
+			BlockEvt:    evt,
+			Transaction: &evt.Block.Transactions[i],
+			Timestamps:  evt.Timestamps,
+		}
+		txMsg, err := txEvt.ToMessage()
+		if err != nil {
+			return fmt.Errorf("failed to convert tx %s: %v", txEvt.Transaction.Hash, err)
+		}
+		results.txs = append(results.txs, &protocol.EvaluateTxRequest{RequestId: txMsg.Transaction.Hash, Event: txMsg})
+		results.txRes = append(results.txRes, make([]*protocol.EvaluateTxResponse, len(b.bots)))
+	}
+
+	// the requests of a bot are sent one by one in the order of the live node so that the
+	// stateful bots see the same sequence in every run; only the bots run in parallel
+	grp, grpCtx := errgroup.WithContext(ctx)
+	grp.SetLimit(b.cfg.Concurrency)
+	for botIndex, bot := range b.bots {
+		botIndex, bot := botIndex, bot
+		grp.Go(func() error {
+			blockResp := new(protocol.EvaluateBlockResponse)
+			if err := b.invoke(grpCtx, bot, agentgrpc.MethodEvaluateBlock, results.block, blockResp); err != nil {
+				return err
+			}
+			results.blockRes[botIndex] = blockResp
+			for txIndex, txReq := range results.txs {
+				txResp := new(protocol.EvaluateTxResponse)
+				if err := b.invoke(grpCtx, bot, agentgrpc.MethodEvaluateTx, txReq, txResp); err != nil {
+					return err
+				}
+				results.txRes[txIndex][botIndex] = txResp
+			}
+			return nil
+		})
+	}
+	if err := grp.Wait(); err != nil {
+		return fmt.Errorf("failed to process block %d: %v", blockNumber.Uint64(), err)
+	}
+
+	if err := b.writeResults(results); err != nil {
+		return err
+	}
+	offset, err := b.writer.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to write the findings: %v", err)
+	}
+	lastBlock := blockNumber.Uint64()
+	b.checkpoint.LastBlock = &lastBlock
+	b.checkpoint.OutputOffset = offset
+	if err := b.checkpoint.Store(b.cfg.CheckpointPath); err != nil {
+		return fmt.Errorf("failed to store the checkpoint: %v", err)
+	}
+
+	b.summary.Blocks++
+	b.summary.Transactions += uint64(len(results.txs))
+	b.summary.LastBlock = lastBlock
+	logger.WithField("transactions", len(results.txs)).Debug("processed block")
+	return nil
+}
+
+// invoke sends the request and retries on transport errors. The bot responses with
+// the error status are only counted since retrying them would not change the result.
+func (b *Backfiller) invoke(ctx context.Context, bot *backfillBot, method agentgrpc.Method, req, resp interface{}) error {
+	var err error
+	for attempt := 1; attempt <= maxRequestAttempts; attempt++ {
+		reqCtx, cancel := context.WithTimeout(ctx, b.cfg.RequestTimeout)
+		err = bot.client.Invoke(reqCtx, method, req, resp)
+		cancel()
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.WithError(err).WithFields(log.Fields{
+			"bot":     bot.ID,
+			"method":  method,
+			"attempt": attempt,
+		}).Warn("bot request failed")
+	}
+	if err != nil {
+		return fmt.Errorf("bot %s: %v", bot.ID, err)
+	}
+
+	var respStatus protocol.ResponseStatus
+	switch r := resp.(type) {
+	case *protocol.EvaluateBlockResponse:
+		respStatus = r.Status
+	case *protocol.EvaluateTxResponse:
+		respStatus = r.Status
+	}
+	if respStatus == protocol.ResponseStatus_ERROR {
+		atomic.AddUint64(&b.summary.BotErrors, 1)
+	}
+	return nil
+}
+
+func (b *Backfiller) writeResults(results *blockResults) error {
+	blockEvt := results.block.Event
+	blockTs, err := utils.HexToBigInt(blockEvt.Block.Timestamp)
+	if err != nil {
+		return err
+	}
+	blockNumber, err := utils.HexToBigInt(blockEvt.BlockNumber)
+	if err != nil {
+		return err
+	}
+	newRecord := func(bot *backfillBot, alertID, txHash string, f *protocol.Finding, private bool) *FindingRecord {
+		return &FindingRecord{
+			AlertID:        alertID,
+			BotID:          bot.ID,
+			BotImage:       bot.Image,
+			ChainID:        b.cfg.ChainID,
+			BlockNumber:    blockNumber.Uint64(),
+			BlockHash:      blockEvt.BlockHash,
+			BlockTimestamp: blockTs.Uint64(),
+			TxHash:         txHash,
+			FindingAlertID: f.AlertId,
+			Name:           f.Name,
+			Description:    f.Description,
+			Severity:       f.Severity.String(),
+			Type:           f.Type.String(),
+			Protocol:       f.Protocol,
+			Metadata:       f.Metadata,
+			Addresses:      f.Addresses,
+			Private:        f.Private || private,
+			Finding:        f,
+		}
+	}
+
+	for botIndex, bot := range b.bots {
+		resp := results.blockRes[botIndex]
+		for _, f := range resp.Findings {
+			alertID := alerthash.ForBlockAlert(&alerthash.Inputs{
+				BlockEvent: blockEvt,
+				Finding:    f,
+				BotInfo:    alerthash.BotInfo{BotImage: bot.Image, BotID: bot.ID},
+			})
+			if err := b.writer.Write(newRecord(bot, alertID, "", f, resp.Private)); err != nil {
+				return err
+			}
+			b.summary.Findings++
+		}
+	}
+	for txIndex, txReq := range results.txs {
+		for botIndex, bot := range b.bots {
+			resp := results.txRes[txIndex][botIndex]
+			for _, f := range resp.Findings {
+				alertID := alerthash.ForTransactionAlert(&alerthash.Inputs{
+					TransactionEvent: txReq.Event,
+					Finding:          f,
+					BotInfo:          alerthash.BotInfo{BotImage: bot.Image, BotID: bot.ID},
+				})
+				if err := b.writer.Write(newRecord(bot, alertID, txReq.Event.Transaction.Hash, f, resp.Private)); err != nil {
+					return err
+				}
+				b.summary.Findings++
+			}
+		}
+	}
+	return nil
+}
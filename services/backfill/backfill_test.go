@@ -0,0 +1,282 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"zktoro/zktoro-core-go/domain"
+	mock_ethereum "zktoro/zktoro-core-go/ethereum/mocks"
+	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/goccy/go-json"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testChainID = 1
+	testFrom    = 10
+	testTo      = 14
+)
+
+// testBot returns a finding for every block and every tx except the second tx of a block
+// which it responds to with an error.
+type testBot struct {
+	protocol.UnimplementedAgentServer
+	name      string
+	failBlock atomic.Int64
+
+	requests   []string
+	inFlight   int
+	overlapped bool
+	mu         sync.Mutex
+}
+
+// record keeps the order of the requests and detects the requests which are sent before
+// the previous one is responded to.
+func (bot *testBot) record(req string) func() {
+	bot.mu.Lock()
+	bot.requests = append(bot.requests, req)
+	bot.inFlight++
+	if bot.inFlight > 1 {
+		bot.overlapped = true
+	}
+	bot.mu.Unlock()
+	// leave time for any concurrent request to arrive
+	time.Sleep(time.Millisecond)
+	return func() {
+		bot.mu.Lock()
+		bot.inFlight--
+		bot.mu.Unlock()
+	}
+}
+
+func (bot *testBot) EvaluateBlock(ctx context.Context, req *protocol.EvaluateBlockRequest) (*protocol.EvaluateBlockResponse, error) {
+	defer bot.record(req.Event.BlockHash)()
+	if utils.HexToInt64(req.Event.BlockNumber) == bot.failBlock.Load() {
+		return nil, status.Error(codes.Unavailable, "bot is down")
+	}
+	return &protocol.EvaluateBlockResponse{
+		Status: protocol.ResponseStatus_SUCCESS,
+		Findings: []*protocol.Finding{{
+			Name:     fmt.Sprintf("%s block", bot.name),
+			AlertId:  "BLOCK",
+			Severity: protocol.Finding_INFO,
+			Type:     protocol.Finding_INFORMATION,
+		}},
+	}, nil
+}
+
+func (bot *testBot) EvaluateTx(ctx context.Context, req *protocol.EvaluateTxRequest) (*protocol.EvaluateTxResponse, error) {
+	defer bot.record(req.Event.Transaction.Hash)()
+	if strings.HasSuffix(req.Event.Transaction.Hash, "1") {
+		return &protocol.EvaluateTxResponse{Status: protocol.ResponseStatus_ERROR}, nil
+	}
+	return &protocol.EvaluateTxResponse{
+		Status: protocol.ResponseStatus_SUCCESS,
+		Findings: []*protocol.Finding{{
+			Name:      fmt.Sprintf("%s tx", bot.name),
+			AlertId:   "TX",
+			Severity:  protocol.Finding_HIGH,
+			Type:      protocol.Finding_EXPLOIT,
+			Addresses: []string{"0xb", "0xa"},
+			Metadata:  map[string]string{"b": "2", "a": "1"},
+		}},
+	}, nil
+}
+
+// testLauncher serves the test bots in the process.
+type testLauncher struct {
+	bots    map[string]*testBot
+	servers []*grpc.Server
+}
+
+func (launcher *testLauncher) Launch(ctx context.Context, botCfg config.AgentConfig) (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	server := grpc.NewServer()
+	protocol.RegisterAgentServer(server, launcher.bots[botCfg.Image])
+	go server.Serve(ln)
+	launcher.servers = append(launcher.servers, server)
+	return ln.Addr().String(), nil
+}
+
+func (launcher *testLauncher) Close() error {
+	for _, server := range launcher.servers {
+		server.Stop()
+	}
+	launcher.servers = nil
+	return nil
+}
+
+func testBlock(number *big.Int) *domain.Block {
+	hash := fmt.Sprintf("0xblock%d", number.Int64())
+	block := &domain.Block{
+		Hash:       hash,
+		ParentHash: fmt.Sprintf("0xblock%d", number.Int64()-1),
+		Number:     utils.BigIntToHex(number),
+		Timestamp:  utils.BigIntToHex(big.NewInt(1600000000 + number.Int64())),
+	}
+	for i := 0; i < 3; i++ {
+		block.Transactions = append(block.Transactions, domain.Transaction{
+			BlockHash:   hash,
+			BlockNumber: block.Number,
+			From:        "0xfrom",
+			Hash:        fmt.Sprintf("0xtx%d%d", number.Int64(), i),
+		})
+	}
+	return block
+}
+
+func newTestBackfiller(t *testing.T, dir string, launcher BotLauncher) *Backfiller {
+	ctrl := gomock.NewController(t)
+	client := mock_ethereum.NewMockClient(ctrl)
+	client.EXPECT().IsWebsocket().Return(false).AnyTimes()
+	client.EXPECT().BlockByNumber(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, number *big.Int) (*domain.Block, error) {
+			return testBlock(number), nil
+		},
+	).AnyTimes()
+	client.EXPECT().GetLogs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	backfiller, err := NewBackfiller(Config{
+		From:    testFrom,
+		To:      testTo,
+		ChainID: testChainID,
+		Bots: []config.AgentConfig{
+			{ID: "1", Image: "bot-a", ChainID: testChainID},
+			{ID: "2", Image: "bot-b", ChainID: testChainID},
+		},
+		OutputPath:     path.Join(dir, "findings.jsonl"),
+		CheckpointPath: path.Join(dir, "checkpoint.json"),
+		Client:         client,
+		Launcher:       launcher,
+	})
+	require.NoError(t, err)
+	return backfiller
+}
+
+func newTestLauncher() *testLauncher {
+	return &testLauncher{bots: map[string]*testBot{
+		"bot-a": {name: "a"},
+		"bot-b": {name: "b"},
+	}}
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	summary, err := newTestBackfiller(t, dir, newTestLauncher()).Run(context.Background())
+	r.NoError(err)
+	r.EqualValues(5, summary.Blocks)
+	r.EqualValues(15, summary.Transactions)
+	// 2 block findings and 2 findings for 2 of the 3 txs in each block
+	r.EqualValues(30, summary.Findings)
+	r.EqualValues(10, summary.BotErrors)
+	r.EqualValues(testTo, summary.LastBlock)
+
+	b, err := os.ReadFile(path.Join(dir, "findings.jsonl"))
+	r.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	r.Len(lines, 30)
+
+	var first, third FindingRecord
+	r.NoError(json.Unmarshal([]byte(lines[0]), &first))
+	r.Equal("1", first.BotID)
+	r.Equal("bot-a", first.BotImage)
+	r.EqualValues(testFrom, first.BlockNumber)
+	r.Empty(first.TxHash)
+	r.Equal("INFO", first.Severity)
+	r.NotEmpty(first.AlertID)
+
+	r.NoError(json.Unmarshal([]byte(lines[2]), &third))
+	r.Equal("0xtx100", third.TxHash)
+	r.Equal("HIGH", third.Severity)
+	r.Equal("EXPLOIT", third.Type)
+	r.Equal([]string{"0xa", "0xb"}, third.Addresses)
+
+	// the whole range is done
+	summary, err = newTestBackfiller(t, dir, newTestLauncher()).Run(context.Background())
+	r.NoError(err)
+	r.True(summary.AlreadyDone)
+}
+
+func TestBackfiller_Resume(t *testing.T) {
+	r := require.New(t)
+
+	expectedDir := t.TempDir()
+	_, err := newTestBackfiller(t, expectedDir, newTestLauncher()).Run(context.Background())
+	r.NoError(err)
+	expected, err := os.ReadFile(path.Join(expectedDir, "findings.jsonl"))
+	r.NoError(err)
+
+	dir := t.TempDir()
+	launcher := newTestLauncher()
+	launcher.bots["bot-b"].failBlock.Store(12)
+	_, err = newTestBackfiller(t, dir, launcher).Run(context.Background())
+	r.Error(err)
+
+	cp, err := LoadCheckpoint(path.Join(dir, "checkpoint.json"), testFrom, testTo, []string{"bot-a", "bot-b"})
+	r.NoError(err)
+	r.EqualValues(12, cp.NextBlock())
+
+	// a partially written block should be dropped when resuming
+	f, err := os.OpenFile(path.Join(dir, "findings.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	r.NoError(err)
+	_, err = f.WriteString("{\"partial\":")
+	r.NoError(err)
+	r.NoError(f.Close())
+
+	launcher.bots["bot-b"].failBlock.Store(0)
+	summary, err := newTestBackfiller(t, dir, launcher).Run(context.Background())
+	r.NoError(err)
+	r.EqualValues(12, *summary.ResumedBlock)
+	r.EqualValues(3, summary.Blocks)
+
+	actual, err := os.ReadFile(path.Join(dir, "findings.jsonl"))
+	r.NoError(err)
+	r.Equal(string(expected), string(actual))
+
+	// another range cannot reuse the checkpoint
+	_, err = LoadCheckpoint(path.Join(dir, "checkpoint.json"), testFrom, testTo+1, []string{"bot-a", "bot-b"})
+	r.ErrorIs(err, ErrCheckpointMismatch)
+}
+
+func TestBackfiller_RequestOrderPerBot(t *testing.T) {
+	r := require.New(t)
+
+	launcher := newTestLauncher()
+	_, err := newTestBackfiller(t, t.TempDir(), launcher).Run(context.Background())
+	r.NoError(err)
+
+	var expected []string
+	for number := int64(testFrom); number <= testTo; number++ {
+		block := testBlock(big.NewInt(number))
+		expected = append(expected, block.Hash)
+		for _, tx := range block.Transactions {
+			expected = append(expected, tx.Hash)
+		}
+	}
+	for _, bot := range launcher.bots {
+		r.Equal(expected, bot.requests, bot.name)
+		r.False(bot.overlapped, bot.name)
+	}
+}
@@ -0,0 +1,162 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"zktoro/clients"
+	"zktoro/clients/docker"
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BotLauncher starts the bots and returns the gRPC addresses which they can be reached at.
+type BotLauncher interface {
+	Launch(ctx context.Context, botCfg config.AgentConfig) (string, error)
+	Close() error
+}
+
+// dockerBotLauncher runs the bots as local containers. The bots reach the JSON-RPC API
+// through a proxy on the host so that the headers and the URL scheme of the configured API
+// do not need to be supported by the bots.
+type dockerBotLauncher struct {
+	client     clients.ContainerRuntime
+	rpcURL     *url.URL
+	rpcHeaders map[string]string
+
+	proxy        *http.Server
+	proxyPort    string
+	containerIDs []string
+	mu           sync.Mutex
+}
+
+// NewDockerBotLauncher creates a new bot launcher which uses the local container runtime.
+func NewDockerBotLauncher(rpcCfg config.JsonRpcConfig, runtimeCfg config.ContainerRuntimeConfig) (*dockerBotLauncher, error) {
+	rpcURL, err := url.Parse(rpcCfg.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json-rpc url: %v", err)
+	}
+	client, err := clients.NewContainerRuntime(runtimeCfg, "backfill")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the container runtime client: %v", err)
+	}
+	return &dockerBotLauncher{client: client, rpcURL: rpcURL, rpcHeaders: rpcCfg.Headers}, nil
+}
+
+// startProxy starts the JSON-RPC proxy if it is not running yet.
+func (launcher *dockerBotLauncher) startProxy() error {
+	if launcher.proxy != nil {
+		return nil
+	}
+	// the containers connect through the host gateway so listen on all interfaces
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return fmt.Errorf("failed to start the json-rpc proxy: %v", err)
+	}
+	rp := httputil.NewSingleHostReverseProxy(launcher.rpcURL)
+	director := rp.Director
+	rp.Director = func(req *http.Request) {
+		director(req)
+		req.Host = launcher.rpcURL.Host
+		for k, v := range launcher.rpcHeaders {
+			req.Header.Set(k, v)
+		}
+	}
+	launcher.proxy = &http.Server{Handler: rp}
+	launcher.proxyPort = strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	go func() {
+		if err := launcher.proxy.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("json-rpc proxy failed")
+		}
+	}()
+	return nil
+}
+
+// Launch starts the bot container and publishes its gRPC port on the loopback interface.
+func (launcher *dockerBotLauncher) Launch(ctx context.Context, botCfg config.AgentConfig) (string, error) {
+	launcher.mu.Lock()
+	defer launcher.mu.Unlock()
+
+	if err := launcher.startProxy(); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("zktoro-backfill-bot-%s", botCfg.ID)
+	if err := launcher.client.EnsureLocalImage(ctx, name, botCfg.Image); err != nil {
+		return "", fmt.Errorf("failed to ensure the image of bot %s: %v", botCfg.ID, err)
+	}
+	// do not reuse the containers from the previous runs since the ports change
+	if existing, err := launcher.client.GetContainerByName(ctx, name); err == nil {
+		if err := launcher.client.RemoveContainer(ctx, existing.ID); err != nil {
+			return "", fmt.Errorf("failed to remove the old container of bot %s: %v", botCfg.ID, err)
+		}
+	}
+
+	hostPort, err := freePort()
+	if err != nil {
+		return "", err
+	}
+	container, err := launcher.client.StartContainer(ctx, docker.ContainerConfig{
+		Name:  name,
+		Image: botCfg.Image,
+		Env: map[string]string{
+			config.EnvJsonRpcHost:   "host.docker.internal",
+			config.EnvJsonRpcPort:   launcher.proxyPort,
+			config.EnvAgentGrpcPort: botCfg.GrpcPort(),
+			config.EnvzktoroBotID:   botCfg.ID,
+			config.EnvzktoroChainID: strconv.Itoa(botCfg.ChainID),
+		},
+		Ports: map[string]string{
+			fmt.Sprintf("127.0.0.1:%s", hostPort): botCfg.GrpcPort(),
+		},
+		DialHost: true,
+		Labels: map[string]string{
+			docker.LabelzktoroIsBot: "true",
+			docker.LabelzktoroBotID: botCfg.ID,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start bot %s: %v", botCfg.ID, err)
+	}
+	launcher.containerIDs = append(launcher.containerIDs, container.ID)
+	return net.JoinHostPort("127.0.0.1", hostPort), nil
+}
+
+// Close removes the bot containers and stops the proxy.
+func (launcher *dockerBotLauncher) Close() error {
+	launcher.mu.Lock()
+	defer launcher.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, containerID := range launcher.containerIDs {
+		if err := launcher.client.TerminateContainer(ctx, containerID); err != nil {
+			log.WithError(err).WithField("container", containerID).Warn("failed to stop the bot container")
+		}
+		if err := launcher.client.RemoveContainer(ctx, containerID); err != nil {
+			log.WithError(err).WithField("container", containerID).Warn("failed to remove the bot container")
+		}
+	}
+	launcher.containerIDs = nil
+	if launcher.proxy != nil {
+		return launcher.proxy.Close()
+	}
+	return nil
+}
+
+func freePort() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	return strconv.Itoa(ln.Addr().(*net.TCPAddr).Port), nil
+}
@@ -0,0 +1,80 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrCheckpointMismatch is returned when the checkpoint belongs to another backfill.
+var ErrCheckpointMismatch = errors.New("checkpoint belongs to a different block range or bot list")
+
+// Checkpoint is the progress of a backfill. The output offset is the size of the output
+// after the last completed block so that any findings of a partially processed block can
+// be truncated before resuming.
+type Checkpoint struct {
+	From         uint64    `json:"from"`
+	To           uint64    `json:"to"`
+	Bots         []string  `json:"bots"`
+	LastBlock    *uint64   `json:"lastBlock,omitempty"`
+	OutputOffset int64     `json:"outputOffset"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// NextBlock returns the first block which was not processed yet.
+func (cp *Checkpoint) NextBlock() uint64 {
+	if cp.LastBlock == nil {
+		return cp.From
+	}
+	return *cp.LastBlock + 1
+}
+
+// IsDone tells if the whole range was processed.
+func (cp *Checkpoint) IsDone() bool {
+	return cp.NextBlock() > cp.To
+}
+
+// Matches checks if the checkpoint is for the same range and the bots.
+func (cp *Checkpoint) Matches(from, to uint64, bots []string) bool {
+	return cp.From == from && cp.To == to && strings.Join(cp.Bots, ",") == strings.Join(bots, ",")
+}
+
+// LoadCheckpoint loads the checkpoint or creates a new one if the file does not exist.
+func LoadCheckpoint(checkpointPath string, from, to uint64, bots []string) (*Checkpoint, error) {
+	b, err := os.ReadFile(checkpointPath)
+	if os.IsNotExist(err) {
+		return &Checkpoint{From: from, To: to, Bots: bots}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("failed to decode the checkpoint: %v", err)
+	}
+	if !cp.Matches(from, to, bots) {
+		return nil, ErrCheckpointMismatch
+	}
+	return &cp, nil
+}
+
+// Store writes the checkpoint file atomically.
+func (cp *Checkpoint) Store(checkpointPath string) error {
+	cp.UpdatedAt = time.Now().UTC()
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(checkpointPath), 0755); err != nil {
+		return err
+	}
+	tmpPath := checkpointPath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, checkpointPath)
+}
@@ -0,0 +1,90 @@
+package backfill
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"zktoro/zktoro-core-go/protocol"
+)
+
+// FindingRecord is a line of the output. The fields are flat and the same for all of the
+// lines so that the output can be loaded as a table (e.g. converted to Parquet).
+type FindingRecord struct {
+	AlertID        string            `json:"alertId"`
+	BotID          string            `json:"botId"`
+	BotImage       string            `json:"botImage"`
+	ChainID        uint64            `json:"chainId"`
+	BlockNumber    uint64            `json:"blockNumber"`
+	BlockHash      string            `json:"blockHash"`
+	BlockTimestamp uint64            `json:"blockTimestamp"`
+	TxHash         string            `json:"txHash"`
+	FindingAlertID string            `json:"findingAlertId"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Severity       string            `json:"severity"`
+	Type           string            `json:"type"`
+	Protocol       string            `json:"protocol"`
+	Metadata       map[string]string `json:"metadata"`
+	Addresses      []string          `json:"addresses"`
+	Private        bool              `json:"private"`
+	Finding        *protocol.Finding `json:"finding"`
+}
+
+// FindingWriter writes the findings as JSONL.
+type FindingWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+	offset int64
+}
+
+// OpenFindingWriter opens the output and drops anything after the offset.
+func OpenFindingWriter(outputPath string, offset int64) (*FindingWriter, error) {
+	if err := os.MkdirAll(path.Dir(outputPath), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the output: %v", err)
+	}
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate the output: %v", err)
+	}
+	if _, err := file.Seek(offset, 0); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FindingWriter{file: file, writer: bufio.NewWriter(file), offset: offset}, nil
+}
+
+// Write writes a finding record.
+func (fw *FindingWriter) Write(record *FindingRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode the finding: %v", err)
+	}
+	b = append(b, '\n')
+	n, err := fw.writer.Write(b)
+	fw.offset += int64(n)
+	return err
+}
+
+// Sync flushes the written findings to the disk and returns the output offset.
+func (fw *FindingWriter) Sync() (int64, error) {
+	if err := fw.writer.Flush(); err != nil {
+		return 0, err
+	}
+	return fw.offset, fw.file.Sync()
+}
+
+// Close implements io.Closer.
+func (fw *FindingWriter) Close() error {
+	if err := fw.writer.Flush(); err != nil {
+		fw.file.Close()
+		return err
+	}
+	return fw.file.Close()
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path"
 	"regexp"
 	"sync"
 	"time"
@@ -25,6 +27,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // BotClient represents a detection bot that is being communicated to and managed.
@@ -38,21 +41,27 @@ type BotClient interface {
 	IsClosed() bool
 
 	TxBufferIsFull() bool
+	TxQueueStats() QueueStats
+	BlockQueueStats() QueueStats
 
 	Initialize()
 	StartProcessing()
 
 	ShouldProcessBlock(blockNumberHex string) bool
+	ShouldProcessTx(event *protocol.TransactionEvent) bool
 	ShouldProcessAlert(event *protocol.AlertEvent) bool
 
-	TxRequestCh() chan<- *botreq.TxRequest
-	BlockRequestCh() chan<- *botreq.BlockRequest
+	SendTxRequest(req *botreq.TxRequest) Delivery
+	SendBlockRequest(req *botreq.BlockRequest) Delivery
 	CombinationRequestCh() chan<- *botreq.CombinationRequest
 
 	LogStatus()
 
 	CombinerBotSubscriptions() []domain.CombinerBotSubscription
 
+	// Discard closes the bot and deletes the requests which are spilled to the disk.
+	Discard() error
+
 	io.Closer
 }
 
@@ -72,9 +81,10 @@ type botClient struct {
 	configUnsafe      config.AgentConfig
 	alertConfigUnsafe protocol.AlertConfig
 
-	txRequests          chan *botreq.TxRequest          // never closed - deallocated when bot is discarded
-	blockRequests       chan *botreq.BlockRequest       // never closed - deallocated when bot is discarded
-	combinationRequests chan *botreq.CombinationRequest // never closed - deallocated when bot is discarded
+	txQueue             *requestQueue[botreq.TxRequest]    // never closed - deallocated when bot is discarded
+	blockQueue          *requestQueue[botreq.BlockRequest] // never closed - deallocated when bot is discarded
+	combinationRequests chan *botreq.CombinationRequest    // never closed - deallocated when bot is discarded
+	spillDir            string
 
 	resultChannels botreq.SendOnlyChannels
 
@@ -123,20 +133,46 @@ func MakeCombinerBotSubscriptions(
 	return
 }
 
-// NewBotClient creates a new bot client.
+// NewBotClient creates a new bot client. The requests are spilled to a dir of the bot under
+// the spill dir if the queue policy says so.
 func NewBotClient(
 	ctx context.Context, botCfg config.AgentConfig,
 	msgClient clients.MessageClient, lifecycleMetrics metrics.Lifecycle, botDialer agentgrpc.BotDialer,
-	resultChannels botreq.SendOnlyChannels,
+	resultChannels botreq.SendOnlyChannels, spillDir string,
 ) *botClient {
 	botCtx, botCtxCancel := context.WithCancel(ctx)
+	var botSpillDir, txSpillDir, blockSpillDir string
+	if len(spillDir) > 0 {
+		botSpillDir = path.Join(spillDir, botCfg.ContainerName())
+		txSpillDir = path.Join(botSpillDir, "tx")
+		blockSpillDir = path.Join(botSpillDir, "block")
+	}
 	return &botClient{
-		ctx:                 botCtx,
-		ctxCancel:           botCtxCancel,
-		configUnsafe:        botCfg,
-		txRequests:          make(chan *botreq.TxRequest, DefaultBufferSize),
-		blockRequests:       make(chan *botreq.BlockRequest, DefaultBufferSize),
+		ctx:          botCtx,
+		ctxCancel:    botCtxCancel,
+		configUnsafe: botCfg,
+		txQueue: newRequestQueue(
+			"tx", botCfg.Queue, botCtx.Done(), txSpillDir,
+			func(req *botreq.TxRequest) ([]byte, error) {
+				return proto.Marshal(req.Original)
+			},
+			func(b []byte) (*botreq.TxRequest, error) {
+				var original protocol.EvaluateTxRequest
+				return &botreq.TxRequest{Original: &original}, proto.Unmarshal(b, &original)
+			},
+		),
+		blockQueue: newRequestQueue(
+			"block", botCfg.Queue, botCtx.Done(), blockSpillDir,
+			func(req *botreq.BlockRequest) ([]byte, error) {
+				return proto.Marshal(req.Original)
+			},
+			func(b []byte) (*botreq.BlockRequest, error) {
+				var original protocol.EvaluateBlockRequest
+				return &botreq.BlockRequest{Original: &original}, proto.Unmarshal(b, &original)
+			},
+		),
 		combinationRequests: make(chan *botreq.CombinationRequest, DefaultBufferSize),
+		spillDir:            botSpillDir,
 		resultChannels:      resultChannels,
 		errCounter:          nodeutils.NewErrorCounter(3, isCriticalErr),
 		msgClient:           msgClient,
@@ -157,8 +193,9 @@ func isCriticalErr(err error) bool {
 func (bot *botClient) LogStatus() {
 	log.WithFields(log.Fields{
 		"bot":         bot.Config().ID,
-		"blockBuffer": len(bot.blockRequests),
-		"txBuffer":    len(bot.txRequests),
+		"blockBuffer": len(bot.blockQueue.ch),
+		"txBuffer":    len(bot.txQueue.ch),
+		"txSpilled":   bot.txQueue.Stats().Spilled,
 		"initialized": bot.IsInitialized(),
 		"closed":      bot.IsClosed(),
 	}).Debug("bot status")
@@ -166,7 +203,17 @@ func (bot *botClient) LogStatus() {
 
 // TxBufferIsFull tells if an bot input buffer is full.
 func (bot *botClient) TxBufferIsFull() bool {
-	return len(bot.txRequests) == DefaultBufferSize
+	return bot.txQueue.Stats().IsFull()
+}
+
+// TxQueueStats returns the occupancy of the tx request queue.
+func (bot *botClient) TxQueueStats() QueueStats {
+	return bot.txQueue.Stats()
+}
+
+// BlockQueueStats returns the occupancy of the block request queue.
+func (bot *botClient) BlockQueueStats() QueueStats {
+	return bot.blockQueue.Stats()
 }
 
 // SetConfig sets the bot config.
@@ -221,14 +268,14 @@ func (bot *botClient) setGrpcClient(client agentgrpc.Client) {
 	bot.clientUnsafe = client
 }
 
-// TxRequestCh returns the transaction request channel safely.
-func (bot *botClient) TxRequestCh() chan<- *botreq.TxRequest {
-	return bot.txRequests
+// SendTxRequest sends the request to the tx request queue by following the queue policy.
+func (bot *botClient) SendTxRequest(req *botreq.TxRequest) Delivery {
+	return bot.txQueue.Send(req)
 }
 
-// BlockRequestCh returns the block request channel safely.
-func (bot *botClient) BlockRequestCh() chan<- *botreq.BlockRequest {
-	return bot.blockRequests
+// SendBlockRequest sends the request to the block request queue by following the queue policy.
+func (bot *botClient) SendBlockRequest(req *botreq.BlockRequest) Delivery {
+	return bot.blockQueue.Send(req)
 }
 
 // CombinationRequestCh returns the alert request channel safely.
@@ -255,6 +302,24 @@ func (bot *botClient) Close() error {
 	return nil
 }
 
+// Discard closes the bot and deletes the spill dir of the bot so that the requests are not
+// replayed to the bot if it is started again later.
+func (bot *botClient) Discard() error {
+	_ = bot.Close()
+	if err := bot.txQueue.removeSpilled(); err != nil {
+		return err
+	}
+	if err := bot.blockQueue.removeSpilled(); err != nil {
+		return err
+	}
+	if len(bot.spillDir) > 0 {
+		if err := os.RemoveAll(bot.spillDir); err != nil {
+			return fmt.Errorf("failed to remove spill dir: %v", err)
+		}
+	}
+	return nil
+}
+
 // Closed returns the closed channel.
 func (bot *botClient) Closed() <-chan struct{} {
 	return bot.ctx.Done()
@@ -389,6 +454,8 @@ func validateInitializeResponse(response *protocol.InitializeResponse) error {
 // StartProcessing launches the goroutines to concurrently process incoming requests
 // from request channels.
 func (bot *botClient) StartProcessing() {
+	go bot.txQueue.replay(bot.ctx)
+	go bot.blockQueue.replay(bot.ctx)
 	go bot.processTransactions()
 	go bot.processBlocks()
 	go bot.processCombinationAlerts()
@@ -427,7 +494,7 @@ func (bot *botClient) processTransactions() {
 
 	<-bot.Initialized()
 
-	processRequests(bot.ctx, bot.txRequests, bot.Closed(), lg, bot.processTransaction)
+	processRequests(bot.ctx, bot.txQueue.ch, bot.Closed(), lg, bot.processTransaction)
 }
 
 func (bot *botClient) processBlocks() {
@@ -441,7 +508,7 @@ func (bot *botClient) processBlocks() {
 
 	<-bot.Initialized()
 
-	processRequests(bot.ctx, bot.blockRequests, bot.Closed(), lg, bot.processBlock)
+	processRequests(bot.ctx, bot.blockQueue.ch, bot.Closed(), lg, bot.processBlock)
 }
 
 func (bot *botClient) processHealthChecks() {
@@ -772,6 +839,35 @@ func (bot *botClient) ShouldProcessBlock(blockNumberHex string) bool {
 	botConfig := bot.Config()
 
 	blockNumber, _ := hexutil.DecodeUint64(blockNumberHex)
+	if !isInBlockRange(botConfig, blockNumber) {
+		return false
+	}
+
+	// if sharded, block % shards must be equal to shard id
+	if botConfig.IsSharded() {
+		return botConfig.ShardConfig.BlockShard(blockNumber) == botConfig.ShardConfig.ShardID
+	}
+	return true
+}
+
+// ShouldProcessTx tells if the bot should process the tx. The sharded bots receive the txs
+// according to their shard strategies.
+func (bot *botClient) ShouldProcessTx(event *protocol.TransactionEvent) bool {
+	botConfig := bot.Config()
+
+	blockNumber, _ := hexutil.DecodeUint64(event.Block.BlockNumber)
+	if !isInBlockRange(botConfig, blockNumber) {
+		return false
+	}
+
+	if botConfig.IsSharded() {
+		tx := event.Transaction
+		return botConfig.ShardConfig.TxShard(blockNumber, tx.Hash, tx.From, tx.To) == botConfig.ShardConfig.ShardID
+	}
+	return true
+}
+
+func isInBlockRange(botConfig config.AgentConfig, blockNumber uint64) bool {
 	var isAtLeastStartBlock bool
 	if botConfig.StartBlock != nil {
 		isAtLeastStartBlock = blockNumber >= *botConfig.StartBlock
@@ -786,15 +882,7 @@ func (bot *botClient) ShouldProcessBlock(blockNumberHex string) bool {
 		isAtMostStopBlock = true
 	}
 
-	var isOnThisShard bool
-	// if sharded, block % shards must be equal to shard id
-	if botConfig.IsSharded() {
-		isOnThisShard = uint(blockNumber)%botConfig.ShardConfig.Shards == botConfig.ShardConfig.ShardID
-	} else {
-		isOnThisShard = true
-	}
-
-	return isAtLeastStartBlock && isAtMostStopBlock && isOnThisShard
+	return isAtLeastStartBlock && isAtMostStopBlock
 }
 
 func (bot *botClient) ShouldProcessAlert(event *protocol.AlertEvent) bool {
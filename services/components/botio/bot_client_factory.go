@@ -20,21 +20,23 @@ type botClientFactory struct {
 	msgClient        clients.MessageClient
 	lifecycleMetrics metrics.Lifecycle
 	dialer           agentgrpc.BotDialer
+	spillDir         string
 }
 
 // NewBotClientFactory creates a new bot client factory by reusing provided dependencies.
 func NewBotClientFactory(
 	resultChannels botreq.SendOnlyChannels, msgClient clients.MessageClient,
-	lifecycleMetrics metrics.Lifecycle, dialer agentgrpc.BotDialer,
+	lifecycleMetrics metrics.Lifecycle, dialer agentgrpc.BotDialer, spillDir string,
 ) BotClientFactory {
 	return &botClientFactory{
 		resultChannels:   resultChannels,
 		msgClient:        msgClient,
 		lifecycleMetrics: lifecycleMetrics,
 		dialer:           dialer,
+		spillDir:         spillDir,
 	}
 }
 
 func (bcf *botClientFactory) NewBotClient(ctx context.Context, botConfig config.AgentConfig) BotClient {
-	return NewBotClient(ctx, botConfig, bcf.msgClient, bcf.lifecycleMetrics, bcf.dialer, bcf.resultChannels)
+	return NewBotClient(ctx, botConfig, bcf.msgClient, bcf.lifecycleMetrics, bcf.dialer, bcf.resultChannels, bcf.spillDir)
 }
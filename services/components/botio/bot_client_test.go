@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path"
 	"testing"
 	"time"
 
@@ -78,7 +80,7 @@ func (s *BotClientSuite) SetupTest() {
 	s.botClient = NewBotClient(
 		context.Background(), config.AgentConfig{
 			ID: testBotID,
-		}, s.msgClient, s.lifecycleMetrics, s.botDialer, s.resultChannels.SendOnly(),
+		}, s.msgClient, s.lifecycleMetrics, s.botDialer, s.resultChannels.SendOnly(), "",
 	)
 }
 
@@ -138,9 +140,9 @@ func (s *BotClientSuite) TestStartProcessStop() {
 		gomock.Any(), agentgrpc.MethodEvaluateTx,
 		gomock.AssignableToTypeOf(&protocol.EvaluateTxRequest{}), gomock.AssignableToTypeOf(&protocol.EvaluateTxResponse{}),
 	).Return(nil)
-	s.botClient.TxRequestCh() <- &botreq.TxRequest{
+	s.r.Equal(DeliveryQueued, s.botClient.SendTxRequest(&botreq.TxRequest{
 		Original: txReq,
-	}
+	}))
 	txResult := <-s.resultChannels.Tx
 	txResp.Timestamp = txResult.Response.Timestamp // bypass - hard to match
 	txResp.LatencyMs = txResult.Response.LatencyMs // bypass - hard to match
@@ -150,9 +152,9 @@ func (s *BotClientSuite) TestStartProcessStop() {
 		gomock.Any(), agentgrpc.MethodEvaluateBlock,
 		gomock.AssignableToTypeOf(&protocol.EvaluateBlockRequest{}), gomock.AssignableToTypeOf(&protocol.EvaluateBlockResponse{}),
 	).Return(nil)
-	s.botClient.BlockRequestCh() <- &botreq.BlockRequest{
+	s.r.Equal(DeliveryQueued, s.botClient.SendBlockRequest(&botreq.BlockRequest{
 		Original: blockReq,
-	}
+	}))
 	blockResult := <-s.resultChannels.Block
 	blockResp.Timestamp = blockResult.Response.Timestamp // bypass - hard to match
 	blockResp.LatencyMs = blockResult.Response.LatencyMs // bypass - hard to match
@@ -321,3 +323,84 @@ func (s *BotClientSuite) TestHealthCheck_WithError() {
 
 	s.r.False(result, "Expected healthCheck to return false")
 }
+
+func (s *BotClientSuite) TestShouldProcessTx() {
+	txEvent := func(blockNumber, hash, from, to string) *protocol.TransactionEvent {
+		return &protocol.TransactionEvent{
+			Block:       &protocol.TransactionEvent_EthBlock{BlockNumber: blockNumber},
+			Transaction: &protocol.TransactionEvent_EthTransaction{Hash: hash, From: from, To: to},
+		}
+	}
+	// returns the shards which process the tx
+	processingShards := func(strategy config.ShardStrategy, event *protocol.TransactionEvent) (shards []uint) {
+		for shardID := uint(0); shardID < 4; shardID++ {
+			s.botClient.SetConfig(config.AgentConfig{
+				ID:          testBotID,
+				ShardConfig: &config.ShardConfig{ShardID: shardID, Shards: 4, Target: 1, Strategy: strategy},
+			})
+			if s.botClient.ShouldProcessTx(event) {
+				shards = append(shards, shardID)
+			}
+		}
+		return
+	}
+
+	// the default strategy routes by the block number
+	s.r.Equal([]uint{1}, processingShards("", txEvent("0x5", "0x1", "0xa", "0xb")))
+	s.r.Equal([]uint{1}, processingShards(config.ShardStrategyBlock, txEvent("0x5", "0x1", "0xa", "0xb")))
+
+	for _, strategy := range []config.ShardStrategy{config.ShardStrategyTxHash, config.ShardStrategyToAddress, config.ShardStrategyFromAddress} {
+		s.r.Len(processingShards(strategy, txEvent("0x5", "0x1", "0xa", "0xb")), 1, strategy)
+	}
+
+	// the same address is always on the same shard
+	s.r.Equal(
+		processingShards(config.ShardStrategyToAddress, txEvent("0x5", "0x1", "0xa", "0xB")),
+		processingShards(config.ShardStrategyToAddress, txEvent("0x6", "0x2", "0xc", "0xb")),
+	)
+	s.r.Equal(
+		processingShards(config.ShardStrategyFromAddress, txEvent("0x5", "0x1", "0xa", "0xb")),
+		processingShards(config.ShardStrategyFromAddress, txEvent("0x6", "0x2", "0xa", "0xc")),
+	)
+	// contract creations are routed by the sender
+	s.r.Equal(
+		processingShards(config.ShardStrategyToAddress, txEvent("0x5", "0x1", "0xa", "")),
+		processingShards(config.ShardStrategyToAddress, txEvent("0x6", "0x2", "0xb", "0xa")),
+	)
+
+	// the block range is respected
+	stopBlock := uint64(4)
+	s.botClient.SetConfig(config.AgentConfig{ID: testBotID, StopBlock: &stopBlock})
+	s.r.False(s.botClient.ShouldProcessTx(txEvent("0x5", "0x1", "0xa", "0xb")))
+	s.r.True(s.botClient.ShouldProcessTx(txEvent("0x4", "0x1", "0xa", "0xb")))
+}
+
+// TestDiscardRemovesSpillDir tests that the spilled requests of a removed bot are deleted.
+func (s *BotClientSuite) TestDiscardRemovesSpillDir() {
+	spillDir := s.T().TempDir()
+	botConfig := config.AgentConfig{
+		ID:    testBotID,
+		Queue: &config.QueueConfig{Policy: config.QueuePolicySpill, Size: 1},
+	}
+	bot := NewBotClient(
+		context.Background(), botConfig, s.msgClient, s.lifecycleMetrics, s.botDialer, s.resultChannels.SendOnly(), spillDir,
+	)
+
+	txReq := &botreq.TxRequest{Original: &protocol.EvaluateTxRequest{RequestId: testRequestID}}
+	s.r.Equal(DeliveryQueued, bot.SendTxRequest(txReq))
+	s.r.Equal(DeliverySpilled, bot.SendTxRequest(txReq))
+	botSpillDir := path.Join(spillDir, botConfig.ContainerName())
+	s.r.DirExists(path.Join(botSpillDir, "tx"))
+	s.r.DirExists(path.Join(botSpillDir, "block"))
+
+	s.lifecycleMetrics.EXPECT().ClientClose(botConfig)
+	s.r.NoError(bot.Discard())
+	s.r.True(bot.IsClosed())
+	_, err := os.Stat(botSpillDir)
+	s.r.True(os.IsNotExist(err))
+
+	// the removed spill queue does not write to the disk anymore
+	s.r.ErrorIs(bot.txQueue.spill.Push([]byte{1}), errSpillRemoved)
+	_, err = os.Stat(botSpillDir)
+	s.r.True(os.IsNotExist(err))
+}
@@ -10,6 +10,7 @@ import (
 	domain "zktoro/zktoro-core-go/domain"
 	protocol "zktoro/zktoro-core-go/protocol"
 	config "zktoro/config"
+	botio "zktoro/services/components/botio"
 	botreq "zktoro/services/components/botio/botreq"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -37,18 +38,18 @@ func (m *MockBotClient) EXPECT() *MockBotClientMockRecorder {
 	return m.recorder
 }
 
-// BlockRequestCh mocks base method.
-func (m *MockBotClient) BlockRequestCh() chan<- *botreq.BlockRequest {
+// BlockQueueStats mocks base method.
+func (m *MockBotClient) BlockQueueStats() botio.QueueStats {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BlockRequestCh")
-	ret0, _ := ret[0].(chan<- *botreq.BlockRequest)
+	ret := m.ctrl.Call(m, "BlockQueueStats")
+	ret0, _ := ret[0].(botio.QueueStats)
 	return ret0
 }
 
-// BlockRequestCh indicates an expected call of BlockRequestCh.
-func (mr *MockBotClientMockRecorder) BlockRequestCh() *gomock.Call {
+// BlockQueueStats indicates an expected call of BlockQueueStats.
+func (mr *MockBotClientMockRecorder) BlockQueueStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockRequestCh", reflect.TypeOf((*MockBotClient)(nil).BlockRequestCh))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockQueueStats", reflect.TypeOf((*MockBotClient)(nil).BlockQueueStats))
 }
 
 // Close mocks base method.
@@ -121,6 +122,20 @@ func (mr *MockBotClientMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockBotClient)(nil).Config))
 }
 
+// Discard mocks base method.
+func (m *MockBotClient) Discard() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discard")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Discard indicates an expected call of Discard.
+func (mr *MockBotClientMockRecorder) Discard() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discard", reflect.TypeOf((*MockBotClient)(nil).Discard))
+}
+
 // Initialize mocks base method.
 func (m *MockBotClient) Initialize() {
 	m.ctrl.T.Helper()
@@ -187,6 +202,34 @@ func (mr *MockBotClientMockRecorder) LogStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogStatus", reflect.TypeOf((*MockBotClient)(nil).LogStatus))
 }
 
+// SendBlockRequest mocks base method.
+func (m *MockBotClient) SendBlockRequest(req *botreq.BlockRequest) botio.Delivery {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBlockRequest", req)
+	ret0, _ := ret[0].(botio.Delivery)
+	return ret0
+}
+
+// SendBlockRequest indicates an expected call of SendBlockRequest.
+func (mr *MockBotClientMockRecorder) SendBlockRequest(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBlockRequest", reflect.TypeOf((*MockBotClient)(nil).SendBlockRequest), req)
+}
+
+// SendTxRequest mocks base method.
+func (m *MockBotClient) SendTxRequest(req *botreq.TxRequest) botio.Delivery {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTxRequest", req)
+	ret0, _ := ret[0].(botio.Delivery)
+	return ret0
+}
+
+// SendTxRequest indicates an expected call of SendTxRequest.
+func (mr *MockBotClientMockRecorder) SendTxRequest(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTxRequest", reflect.TypeOf((*MockBotClient)(nil).SendTxRequest), req)
+}
+
 // SetConfig mocks base method.
 func (m *MockBotClient) SetConfig(arg0 config.AgentConfig) {
 	m.ctrl.T.Helper()
@@ -227,6 +270,20 @@ func (mr *MockBotClientMockRecorder) ShouldProcessBlock(blockNumberHex interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldProcessBlock", reflect.TypeOf((*MockBotClient)(nil).ShouldProcessBlock), blockNumberHex)
 }
 
+// ShouldProcessTx mocks base method.
+func (m *MockBotClient) ShouldProcessTx(event *protocol.TransactionEvent) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldProcessTx", event)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShouldProcessTx indicates an expected call of ShouldProcessTx.
+func (mr *MockBotClientMockRecorder) ShouldProcessTx(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldProcessTx", reflect.TypeOf((*MockBotClient)(nil).ShouldProcessTx), event)
+}
+
 // StartProcessing mocks base method.
 func (m *MockBotClient) StartProcessing() {
 	m.ctrl.T.Helper()
@@ -253,16 +310,16 @@ func (mr *MockBotClientMockRecorder) TxBufferIsFull() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxBufferIsFull", reflect.TypeOf((*MockBotClient)(nil).TxBufferIsFull))
 }
 
-// TxRequestCh mocks base method.
-func (m *MockBotClient) TxRequestCh() chan<- *botreq.TxRequest {
+// TxQueueStats mocks base method.
+func (m *MockBotClient) TxQueueStats() botio.QueueStats {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TxRequestCh")
-	ret0, _ := ret[0].(chan<- *botreq.TxRequest)
+	ret := m.ctrl.Call(m, "TxQueueStats")
+	ret0, _ := ret[0].(botio.QueueStats)
 	return ret0
 }
 
-// TxRequestCh indicates an expected call of TxRequestCh.
-func (mr *MockBotClientMockRecorder) TxRequestCh() *gomock.Call {
+// TxQueueStats indicates an expected call of TxQueueStats.
+func (mr *MockBotClientMockRecorder) TxQueueStats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxRequestCh", reflect.TypeOf((*MockBotClient)(nil).TxRequestCh))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxQueueStats", reflect.TypeOf((*MockBotClient)(nil).TxQueueStats))
 }
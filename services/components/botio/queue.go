@@ -0,0 +1,221 @@
+package botio
+
+import (
+	"context"
+	"time"
+
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Queue defaults
+const (
+	DefaultQueueBlockTimeout = time.Second
+	DefaultMaxSpilled        = 100000
+	SpillDirName             = ".spill"
+)
+
+// Delivery is the outcome of sending a request to a bot queue.
+type Delivery int
+
+// Deliveries
+const (
+	// DeliveryQueued means that the request is in the queue.
+	DeliveryQueued Delivery = iota
+	// DeliveryDroppedOldest means that the request is in the queue but the oldest one is dropped.
+	DeliveryDroppedOldest
+	// DeliveryDropped means that the request is dropped.
+	DeliveryDropped
+	// DeliverySpilled means that the request is written to the disk to be replayed later.
+	DeliverySpilled
+	// DeliveryClosed means that the bot is closed.
+	DeliveryClosed
+)
+
+// QueueStats shows the occupancy of a bot request queue.
+type QueueStats struct {
+	Queued   int
+	Capacity int
+	Spilled  int
+}
+
+// IsFull tells if the queue is full.
+func (qs QueueStats) IsFull() bool {
+	return qs.Queued >= qs.Capacity
+}
+
+// requestQueue delivers the requests to a bot by following the queue policy when the buffer is full.
+type requestQueue[R any] struct {
+	name         string
+	ch           chan *R
+	closed       <-chan struct{}
+	policy       config.QueuePolicy
+	blockTimeout time.Duration
+
+	spill   *spillQueue
+	spillCh chan struct{}
+	encode  func(*R) ([]byte, error)
+	decode  func([]byte) (*R, error)
+}
+
+func newRequestQueue[R any](
+	name string, queueCfg *config.QueueConfig, closed <-chan struct{}, spillDir string,
+	encode func(*R) ([]byte, error), decode func([]byte) (*R, error),
+) *requestQueue[R] {
+	if queueCfg == nil {
+		queueCfg = &config.QueueConfig{}
+	}
+	size := queueCfg.Size
+	if size == 0 {
+		size = DefaultBufferSize
+	}
+	q := &requestQueue[R]{
+		name:         name,
+		ch:           make(chan *R, size),
+		closed:       closed,
+		policy:       queueCfg.Policy,
+		blockTimeout: DefaultQueueBlockTimeout,
+		spillCh:      make(chan struct{}, 1),
+		encode:       encode,
+		decode:       decode,
+	}
+	if queueCfg.BlockTimeoutMs > 0 {
+		q.blockTimeout = time.Duration(queueCfg.BlockTimeoutMs) * time.Millisecond
+	}
+
+	if q.policy != config.QueuePolicySpill {
+		return q
+	}
+	maxSpilled := queueCfg.MaxSpilled
+	if maxSpilled == 0 {
+		maxSpilled = DefaultMaxSpilled
+	}
+	var err error
+	if len(spillDir) == 0 {
+		log.WithField("queue", name).Warn("no spill dir - dropping the new requests when the queue is full")
+	} else if q.spill, err = newSpillQueue(spillDir, maxSpilled); err != nil {
+		log.WithError(err).WithField("queue", name).Error("failed to create the spill queue - dropping the new requests when the queue is full")
+	}
+	return q
+}
+
+// Send delivers the request to the queue.
+func (q *requestQueue[R]) Send(req *R) Delivery {
+	// keep the order of the requests while there are spilled ones
+	if q.spill != nil && q.spill.Len() > 0 {
+		return q.spillRequest(req)
+	}
+
+	select {
+	case <-q.closed:
+		return DeliveryClosed
+	case q.ch <- req:
+		return DeliveryQueued
+	default:
+	}
+
+	switch q.policy {
+	case config.QueuePolicyDropOldest:
+		delivery := DeliveryQueued
+		select {
+		case <-q.ch:
+			delivery = DeliveryDroppedOldest
+		default:
+		}
+		select {
+		case q.ch <- req:
+			return delivery
+		default:
+			return DeliveryDropped
+		}
+
+	case config.QueuePolicyBlock:
+		timer := time.NewTimer(q.blockTimeout)
+		defer timer.Stop()
+		select {
+		case <-q.closed:
+			return DeliveryClosed
+		case q.ch <- req:
+			return DeliveryQueued
+		case <-timer.C:
+			return DeliveryDropped
+		}
+
+	case config.QueuePolicySpill:
+		if q.spill != nil {
+			return q.spillRequest(req)
+		}
+	}
+
+	return DeliveryDropped
+}
+
+func (q *requestQueue[R]) spillRequest(req *R) Delivery {
+	b, err := q.encode(req)
+	if err == nil {
+		err = q.spill.Push(b)
+	}
+	if err != nil {
+		log.WithError(err).WithField("queue", q.name).Warn("failed to spill the request - dropping")
+		return DeliveryDropped
+	}
+	select {
+	case q.spillCh <- struct{}{}:
+	default:
+	}
+	return DeliverySpilled
+}
+
+// replay moves the spilled requests back to the queue as soon as there is room.
+func (q *requestQueue[R]) replay(ctx context.Context) {
+	if q.spill == nil {
+		return
+	}
+	for {
+		for {
+			seq, b, ok := q.spill.Peek()
+			if !ok {
+				break
+			}
+			req, err := q.decode(b)
+			if err != nil {
+				log.WithError(err).WithField("queue", q.name).Warn("failed to decode the spilled request - dropping")
+				q.spill.Remove(seq)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case q.ch <- req:
+			}
+			q.spill.Remove(seq)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.spillCh:
+		}
+	}
+}
+
+// Stats returns the queue occupancy.
+func (q *requestQueue[R]) Stats() QueueStats {
+	stats := QueueStats{
+		Queued:   len(q.ch),
+		Capacity: cap(q.ch),
+	}
+	if q.spill != nil {
+		stats.Spilled = q.spill.Len()
+	}
+	return stats
+}
+
+// removeSpilled deletes the spilled requests from the disk.
+func (q *requestQueue[R]) removeSpilled() error {
+	if q.spill == nil {
+		return nil
+	}
+	return q.spill.RemoveAll()
+}
@@ -0,0 +1,125 @@
+package botio
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	n int
+}
+
+func newTestQueue(queueCfg *config.QueueConfig, closed <-chan struct{}, spillDir string) *requestQueue[testRequest] {
+	return newRequestQueue(
+		"test", queueCfg, closed, spillDir,
+		func(req *testRequest) ([]byte, error) {
+			return []byte(strconv.Itoa(req.n)), nil
+		},
+		func(b []byte) (*testRequest, error) {
+			n, err := strconv.Atoi(string(b))
+			return &testRequest{n: n}, err
+		},
+	)
+}
+
+func receiveAll(q *requestQueue[testRequest]) (ns []int) {
+	for {
+		select {
+		case req := <-q.ch:
+			ns = append(ns, req.n)
+		default:
+			return
+		}
+	}
+}
+
+func TestRequestQueue_DropNewest(t *testing.T) {
+	r := require.New(t)
+
+	q := newTestQueue(&config.QueueConfig{Size: 2}, make(chan struct{}), "")
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 1}))
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 2}))
+	r.True(q.Stats().IsFull())
+	r.Equal(DeliveryDropped, q.Send(&testRequest{n: 3}))
+	r.Equal([]int{1, 2}, receiveAll(q))
+}
+
+func TestRequestQueue_DropOldest(t *testing.T) {
+	r := require.New(t)
+
+	q := newTestQueue(&config.QueueConfig{Policy: config.QueuePolicyDropOldest, Size: 2}, make(chan struct{}), "")
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 1}))
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 2}))
+	r.Equal(DeliveryDroppedOldest, q.Send(&testRequest{n: 3}))
+	r.Equal([]int{2, 3}, receiveAll(q))
+}
+
+func TestRequestQueue_Block(t *testing.T) {
+	r := require.New(t)
+
+	closed := make(chan struct{})
+	q := newTestQueue(&config.QueueConfig{Policy: config.QueuePolicyBlock, Size: 1, BlockTimeoutMs: 50}, closed, "")
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 1}))
+
+	// times out if the bot does not make room
+	start := time.Now()
+	r.Equal(DeliveryDropped, q.Send(&testRequest{n: 2}))
+	r.GreaterOrEqual(time.Since(start), 50*time.Millisecond)
+
+	// waits for the bot
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-q.ch
+	}()
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 3}))
+	r.Equal([]int{3}, receiveAll(q))
+
+	// does not wait for a closed bot
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 4}))
+	close(closed)
+	r.Equal(DeliveryClosed, q.Send(&testRequest{n: 5}))
+}
+
+func TestRequestQueue_Spill(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	queueCfg := &config.QueueConfig{Policy: config.QueuePolicySpill, Size: 2, MaxSpilled: 3}
+	q := newTestQueue(queueCfg, make(chan struct{}), dir)
+	r.NotNil(q.spill)
+	for n := 1; n <= 2; n++ {
+		r.Equal(DeliveryQueued, q.Send(&testRequest{n: n}))
+	}
+	for n := 3; n <= 5; n++ {
+		r.Equal(DeliverySpilled, q.Send(&testRequest{n: n}))
+	}
+	r.Equal(DeliveryDropped, q.Send(&testRequest{n: 6}))
+	r.Equal(QueueStats{Queued: 2, Capacity: 2, Spilled: 3}, q.Stats())
+
+	// the spilled requests are replayed after a restart
+	q = newTestQueue(queueCfg, make(chan struct{}), dir)
+	r.Equal(3, q.Stats().Spilled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.replay(ctx)
+
+	var ns []int
+	for len(ns) < 3 {
+		ns = append(ns, (<-q.ch).n)
+	}
+	r.Equal([]int{3, 4, 5}, ns)
+	r.Eventually(func() bool {
+		return q.Stats().Spilled == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// the new requests are not spilled anymore
+	r.Equal(DeliveryQueued, q.Send(&testRequest{n: 7}))
+	r.Equal([]int{7}, receiveAll(q))
+}
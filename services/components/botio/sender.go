@@ -7,6 +7,7 @@ import (
 
 	"zktoro/clients"
 	"zktoro/clients/messaging"
+	"zktoro/config"
 	"zktoro/services/components/botio/botreq"
 	"zktoro/services/components/metrics"
 
@@ -52,17 +53,21 @@ func (rs *requestSender) Health() health.Reports {
 	bots := rs.botPool.GetCurrentBotClients()
 
 	botCount := len(bots)
-	var fullCount int
+	var (
+		fullCount    int
+		queueReports health.Reports
+	)
 	for _, bot := range bots {
 		if bot.TxBufferIsFull() {
 			fullCount++
 		}
+		queueReports = append(queueReports, makeQueueReports(bot)...)
 	}
 	status := health.StatusOK
 	if botCount == 0 {
 		status = health.StatusFailing
 	}
-	return health.Reports{
+	return append(health.Reports{
 		&health.Report{
 			Name:    "agents.total",
 			Status:  status,
@@ -73,6 +78,55 @@ func (rs *requestSender) Health() health.Reports {
 			Status:  health.StatusInfo,
 			Details: strconv.Itoa(fullCount),
 		},
+	}, queueReports...)
+}
+
+// makeQueueReports reports the queue occupancy of a bot. The spilled requests are reported
+// only if the bot spills.
+func makeQueueReports(bot BotClient) (reports health.Reports) {
+	botConfig := bot.Config()
+	name := botConfig.ContainerName()
+	txStats := bot.TxQueueStats()
+	blockStats := bot.BlockQueueStats()
+	reports = health.Reports{
+		{
+			Name:    "queue.tx.size." + name,
+			Status:  health.StatusInfo,
+			Details: strconv.Itoa(txStats.Queued),
+		},
+		{
+			Name:    "queue.block.size." + name,
+			Status:  health.StatusInfo,
+			Details: strconv.Itoa(blockStats.Queued),
+		},
+	}
+	if botConfig.Queue == nil || botConfig.Queue.Policy != config.QueuePolicySpill {
+		return
+	}
+	return append(reports,
+		&health.Report{
+			Name:    "queue.tx.spilled." + name,
+			Status:  health.StatusInfo,
+			Details: strconv.Itoa(txStats.Spilled),
+		},
+		&health.Report{
+			Name:    "queue.block.spilled." + name,
+			Status:  health.StatusInfo,
+			Details: strconv.Itoa(blockStats.Spilled),
+		},
+	)
+}
+
+// makeQueueMetrics makes the queue occupancy metrics of a bot.
+func makeQueueMetrics(bot BotClient) []*protocol.AgentMetric {
+	botConfig := bot.Config()
+	txStats := bot.TxQueueStats()
+	blockStats := bot.BlockQueueStats()
+	return []*protocol.AgentMetric{
+		metrics.CreateAgentMetric(botConfig, metrics.MetricTxQueueSize, float64(txStats.Queued)),
+		metrics.CreateAgentMetric(botConfig, metrics.MetricTxQueueSpilled, float64(txStats.Spilled)),
+		metrics.CreateAgentMetric(botConfig, metrics.MetricBlockQueueSize, float64(blockStats.Queued)),
+		metrics.CreateAgentMetric(botConfig, metrics.MetricBlockQueueSpilled, float64(blockStats.Spilled)),
 	}
 }
 
@@ -97,7 +151,7 @@ func (rs *requestSender) SendEvaluateTxRequest(req *protocol.EvaluateTxRequest)
 
 	var metricsList []*protocol.AgentMetric
 	for _, bot := range bots {
-		if !bot.ShouldProcessBlock(req.Event.Block.BlockNumber) {
+		if !bot.ShouldProcessTx(req.Event) {
 			continue
 		}
 		botConfig := bot.Config()
@@ -107,17 +161,18 @@ func (rs *requestSender) SendEvaluateTxRequest(req *protocol.EvaluateTxRequest)
 			"duration": time.Since(startTime),
 		}).Debug("sending tx request to evalTxCh")
 
-		// unblock req send and discard agent if agent is closed
-
-		select {
-		case <-bot.Closed():
+		// the queue policy of the bot decides what to do if the buffer is full
+		switch bot.SendTxRequest(&botreq.TxRequest{Original: req}) {
+		case DeliveryClosed:
 			lg.WithField("bot", botConfig.ID).Debug("bot is closed - skipping")
-		case bot.TxRequestCh() <- &botreq.TxRequest{
-			Original: req,
-		}:
-		default: // do not try to send if the buffer is full
+		case DeliveryDropped:
 			lg.WithField("bot", botConfig.ID).Debug("agent tx request buffer is full - skipping")
 			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricTxDrop, 1))
+		case DeliveryDroppedOldest:
+			lg.WithField("bot", botConfig.ID).Debug("agent tx request buffer is full - dropped the oldest request")
+			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricTxDrop, 1))
+		case DeliverySpilled:
+			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricTxSpill, 1))
 		}
 		lg.WithFields(log.Fields{
 			"bot":      botConfig.ID,
@@ -157,16 +212,18 @@ func (rs *requestSender) SendEvaluateBlockRequest(req *protocol.EvaluateBlockReq
 			"duration": time.Since(startTime),
 		}).Debug("sending block request to evalBlockCh")
 
-		// unblock req send if agent is closed
-		select {
-		case <-bot.Closed():
+		// the queue policy of the bot decides what to do if the buffer is full
+		switch bot.SendBlockRequest(&botreq.BlockRequest{Original: req}) {
+		case DeliveryClosed:
 			lg.WithField("bot", botConfig.ID).Debug("bot is closed - skipping")
-		case bot.BlockRequestCh() <- &botreq.BlockRequest{
-			Original: req,
-		}:
-		default: // do not try to send if the buffer is full
+		case DeliveryDropped:
 			lg.WithField("bot", botConfig.ID).Warn("agent block request buffer is full - skipping")
 			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricBlockDrop, 1))
+		case DeliveryDroppedOldest:
+			lg.WithField("bot", botConfig.ID).Warn("agent block request buffer is full - dropped the oldest request")
+			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricBlockDrop, 1))
+		case DeliverySpilled:
+			metricsList = append(metricsList, metrics.CreateAgentMetric(botConfig, metrics.MetricBlockSpill, 1))
 		}
 		lg.WithFields(
 			log.Fields{
@@ -176,6 +233,11 @@ func (rs *requestSender) SendEvaluateBlockRequest(req *protocol.EvaluateBlockReq
 		).Debug("sent tx request to evalBlockCh")
 	}
 
+	// report the queue occupancy once per block
+	for _, bot := range bots {
+		metricsList = append(metricsList, makeQueueMetrics(bot)...)
+	}
+
 	blockNumber, _ := hexutil.DecodeUint64(req.Event.BlockNumber)
 	rs.msgClient.Publish(messaging.SubjectScannerBlock, &messaging.ScannerPayload{
 		LatestBlockInput: blockNumber,
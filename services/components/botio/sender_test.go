@@ -10,6 +10,7 @@ import (
 	"zktoro/services/components/botio"
 	"zktoro/services/components/botio/botreq"
 	mock_botio "zktoro/services/components/botio/mocks"
+	"zktoro/services/components/metrics"
 
 	"zktoro/zktoro-core-go/protocol"
 
@@ -49,17 +50,59 @@ func (s *SenderTestSuite) SetupTest() {
 
 func (s *SenderTestSuite) TestHealth() {
 	s.botClient.EXPECT().TxBufferIsFull().Return(false)
+	s.botClient.EXPECT().Config().Return(config.AgentConfig{
+		ID:      "bot",
+		IsLocal: true,
+		Queue:   &config.QueueConfig{Policy: config.QueuePolicySpill},
+	})
+	s.botClient.EXPECT().TxQueueStats().Return(botio.QueueStats{Queued: 2, Capacity: 10, Spilled: 3})
+	s.botClient.EXPECT().BlockQueueStats().Return(botio.QueueStats{Queued: 1, Capacity: 10})
 	reports := s.sender.Health()
+	s.r.Len(reports, 6)
 	s.r.Equal("agents.total", reports[0].Name)
 	s.r.Equal("agents.lagging", reports[1].Name)
+
+	containerName := "zktoro-agent-bot"
+	s.r.Equal("queue.tx.size."+containerName, reports[2].Name)
+	s.r.Equal("2", reports[2].Details)
+	s.r.Equal("queue.block.size."+containerName, reports[3].Name)
+	s.r.Equal("1", reports[3].Details)
+	s.r.Equal("queue.tx.spilled."+containerName, reports[4].Name)
+	s.r.Equal("3", reports[4].Details)
+	s.r.Equal("queue.block.spilled."+containerName, reports[5].Name)
+	s.r.Equal("0", reports[5].Details)
 }
 
 func (s *SenderTestSuite) TestSendEvaluateTxRequest() {
 	s.botPool.EXPECT().WaitForAll().Times(1)
-	s.botClient.EXPECT().ShouldProcessBlock(gomock.Any()).Return(true)
+	s.botClient.EXPECT().ShouldProcessTx(gomock.Any()).Return(true)
 	s.botClient.EXPECT().Config().Return(config.AgentConfig{})
-	s.botClient.EXPECT().Closed().Return(make(chan struct{}))
-	s.botClient.EXPECT().TxRequestCh().Return(make(chan *botreq.TxRequest, 1))
+	s.botClient.EXPECT().SendTxRequest(gomock.Any()).Return(botio.DeliveryQueued)
+
+	s.sender.SendEvaluateTxRequest(&protocol.EvaluateTxRequest{
+		Event: &protocol.TransactionEvent{
+			Transaction: &protocol.TransactionEvent_EthTransaction{
+				Hash: "0x1",
+			},
+			Block: &protocol.TransactionEvent_EthBlock{
+				BlockNumber: "0x1",
+			},
+		},
+	})
+}
+
+func (s *SenderTestSuite) TestSendEvaluateTxRequest_Dropped() {
+	s.botPool.EXPECT().WaitForAll().Times(1)
+	s.botClient.EXPECT().ShouldProcessTx(gomock.Any()).Return(true)
+	s.botClient.EXPECT().Config().Return(config.AgentConfig{ID: "bot"})
+	s.botClient.EXPECT().SendTxRequest(gomock.Any()).Return(botio.DeliveryDroppedOldest)
+	s.msgClient.EXPECT().PublishProto(messaging.SubjectMetricAgent, gomock.Any()).Do(
+		func(subject string, payload interface{}) {
+			metricList := payload.(*protocol.AgentMetricList)
+			s.r.Len(metricList.Metrics, 1)
+			s.r.Equal(metrics.MetricTxDrop, metricList.Metrics[0].Name)
+		},
+	)
 
 	s.sender.SendEvaluateTxRequest(&protocol.EvaluateTxRequest{
 		Event: &protocol.TransactionEvent{
@@ -76,10 +119,12 @@ func (s *SenderTestSuite) TestSendEvaluateTxRequest() {
 func (s *SenderTestSuite) TestSendEvaluateBlockRequest() {
 	s.botPool.EXPECT().WaitForAll().Times(1)
 	s.botClient.EXPECT().ShouldProcessBlock(gomock.Any()).Return(true)
-	s.botClient.EXPECT().Config().Return(config.AgentConfig{})
-	s.botClient.EXPECT().Closed().Return(make(chan struct{}))
-	s.botClient.EXPECT().BlockRequestCh().Return(make(chan *botreq.BlockRequest, 1))
+	s.botClient.EXPECT().Config().Return(config.AgentConfig{}).Times(2)
+	s.botClient.EXPECT().SendBlockRequest(gomock.Any()).Return(botio.DeliveryQueued)
+	s.botClient.EXPECT().TxQueueStats().Return(botio.QueueStats{})
+	s.botClient.EXPECT().BlockQueueStats().Return(botio.QueueStats{})
 	s.msgClient.EXPECT().Publish(messaging.SubjectScannerBlock, gomock.Any())
+	s.msgClient.EXPECT().PublishProto(messaging.SubjectMetricAgent, gomock.Any())
 
 	s.sender.SendEvaluateBlockRequest(&protocol.EvaluateBlockRequest{
 		Event: &protocol.BlockEvent{
@@ -0,0 +1,161 @@
+package botio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	spillEntryExt       = ".req"
+	spillEntryNameWidth = 20
+)
+
+var (
+	errSpillFull    = errors.New("spill queue is full")
+	errSpillRemoved = errors.New("spill queue is removed")
+)
+
+// spillQueue is a FIFO queue of encoded requests on the disk. Each entry is a single file
+// in the spill dir so the requests left from a previous run are replayed after a restart.
+type spillQueue struct {
+	dir        string
+	maxEntries int
+	lastSeq    uint64
+	pending    []uint64
+	removed    bool
+	mu         sync.Mutex
+}
+
+func newSpillQueue(dir string, maxEntries int) (*spillQueue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create spill dir: %v", err)
+	}
+	sq := &spillQueue{
+		dir:        dir,
+		maxEntries: maxEntries,
+	}
+	if err := sq.load(); err != nil {
+		return nil, err
+	}
+	return sq, nil
+}
+
+func (sq *spillQueue) load() error {
+	dirEntries, err := os.ReadDir(sq.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spill dir: %v", err)
+	}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, spillEntryExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spillEntryExt), 10, 64)
+		if err != nil {
+			log.WithField("file", name).Warn("ignoring unknown file in spill dir")
+			continue
+		}
+		sq.pending = append(sq.pending, seq)
+		if seq > sq.lastSeq {
+			sq.lastSeq = seq
+		}
+	}
+	sort.Slice(sq.pending, func(i, j int) bool {
+		return sq.pending[i] < sq.pending[j]
+	})
+	return nil
+}
+
+func (sq *spillQueue) entryPath(seq uint64) string {
+	return path.Join(sq.dir, fmt.Sprintf("%0*d%s", spillEntryNameWidth, seq, spillEntryExt))
+}
+
+// Push writes the request as the newest entry.
+func (sq *spillQueue) Push(b []byte) error {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if sq.removed {
+		return errSpillRemoved
+	}
+	if len(sq.pending) >= sq.maxEntries {
+		return errSpillFull
+	}
+
+	// write to a temp file first so that a crash never leaves a partial entry behind
+	seq := sq.lastSeq + 1
+	entryPath := sq.entryPath(seq)
+	tmpPath := entryPath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return fmt.Errorf("failed to write spill entry: %v", err)
+	}
+	if err := os.Rename(tmpPath, entryPath); err != nil {
+		return fmt.Errorf("failed to move spill entry: %v", err)
+	}
+
+	sq.lastSeq = seq
+	sq.pending = append(sq.pending, seq)
+	return nil
+}
+
+// Peek returns the oldest entry without removing it. The entries which cannot be read are removed.
+func (sq *spillQueue) Peek() (uint64, []byte, bool) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	for len(sq.pending) > 0 {
+		seq := sq.pending[0]
+		b, err := os.ReadFile(sq.entryPath(seq))
+		if err == nil {
+			return seq, b, true
+		}
+		log.WithError(err).WithField("seq", seq).Warn("failed to read spill entry - dropping")
+		sq.pending = sq.pending[1:]
+	}
+	return 0, nil, false
+}
+
+// Remove deletes the entry after it is moved back to the queue.
+func (sq *spillQueue) Remove(seq uint64) {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if err := os.Remove(sq.entryPath(seq)); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("seq", seq).Warn("failed to remove spill entry")
+	}
+	for i, pendingSeq := range sq.pending {
+		if pendingSeq == seq {
+			sq.pending = append(sq.pending[:i], sq.pending[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len returns the number of the entries.
+func (sq *spillQueue) Len() int {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	return len(sq.pending)
+}
+
+// RemoveAll deletes the spill dir with all of the entries. The queue cannot be used afterwards.
+func (sq *spillQueue) RemoveAll() error {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	sq.removed = true
+	sq.pending = nil
+	if err := os.RemoveAll(sq.dir); err != nil {
+		return fmt.Errorf("failed to remove spill dir: %v", err)
+	}
+	return nil
+}
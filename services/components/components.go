@@ -3,10 +3,10 @@ package components
 import (
 	"context"
 	"fmt"
+	"path"
 
 	"zktoro/clients"
 	"zktoro/clients/agentgrpc"
-	"zktoro/clients/docker"
 	"zktoro/config"
 	"zktoro/services/components/botio"
 	"zktoro/services/components/botio/botreq"
@@ -40,6 +40,7 @@ func GetBotProcessingComponents(ctx context.Context, botProcCfg BotProcessingCon
 	botClientFactory := botio.NewBotClientFactory(
 		resultChannels.SendOnly(), botProcCfg.MessageClient,
 		lifecycleMetrics, agentgrpc.NewBotDialer(),
+		path.Join(botProcCfg.Config.ZktoroDir, botio.SpillDirName),
 	)
 	botPool := lifecycle.NewBotPool(
 		ctx, lifecycleMetrics, botClientFactory, botProcCfg.Config.BotsToWait(),
@@ -78,8 +79,10 @@ type BotLifecycleConfig struct {
 
 // BotLifecycle contains the bot lifecycle components.
 type BotLifecycle struct {
-	BotManager lifecycle.BotLifecycleManager
-	BotClient  containers.BotClient
+	BotManager  lifecycle.BotLifecycleManager
+	BotOperator lifecycle.BotOperator
+	BotActivity lifecycle.BotActivityReader
+	BotClient   containers.BotClient
 }
 
 // GetBotLifecycleComponents returns the bot lifecycle management components.
@@ -87,42 +90,48 @@ func GetBotLifecycleComponents(ctx context.Context, botLifeConfig BotLifecycleCo
 	cfg := botLifeConfig.Config
 	// bot image client is helpful for loading local mode agents from a restricted container registry
 	var (
-		botImageClient clients.DockerClient
+		botImageClient clients.ContainerRuntime
 		err            error
 	)
 	if cfg.LocalModeConfig.Enable && cfg.LocalModeConfig.ContainerRegistry != nil {
-		botImageClient, err = docker.NewAuthDockerClient(
-			"",
+		botImageClient, err = clients.NewAuthContainerRuntime(
+			cfg.ContainerRuntime, "",
 			cfg.LocalModeConfig.ContainerRegistry.Username,
 			cfg.LocalModeConfig.ContainerRegistry.Password,
 		)
 	} else {
-		botImageClient, err = docker.NewDockerClient("")
+		botImageClient, err = clients.NewContainerRuntime(cfg.ContainerRuntime, "")
 	}
 	if err != nil {
 		return BotLifecycle{}, fmt.Errorf("failed to create the bot image docker client: %v", err)
 	}
 
-	dockerClient, err := docker.NewDockerClient(containers.LabelzktoroSupervisor)
+	dockerClient, err := clients.NewContainerRuntime(cfg.ContainerRuntime, containers.LabelzktoroSupervisor)
 	if err != nil {
 		return BotLifecycle{}, fmt.Errorf("failed to create the bot docker client: %v", err)
 	}
 
 	botClient := containers.NewBotClient(
-		botLifeConfig.Config.Log, botLifeConfig.Config.ResourcesConfig,
+		botLifeConfig.Config.Log, botLifeConfig.Config.ResourcesConfig, botLifeConfig.Config.ContainerRuntime,
 		dockerClient, botImageClient,
 	)
 	lifecycleMetrics := metrics.NewLifecycleClient(botLifeConfig.MessageClient)
 	lifecycleMediator := mediator.New(botLifeConfig.MessageClient, lifecycleMetrics)
 	botMonitor := lifecycle.NewBotMonitor(lifecycleMetrics)
 	lifecycleMediator.ConnectBotMonitor(botMonitor)
+	botStates, err := lifecycle.NewBotStates(cfg.BotStatesPath(), cfg.ResourcesConfig.Restarts, lifecycleMetrics)
+	if err != nil {
+		return BotLifecycle{}, fmt.Errorf("failed to load the bot states: %v", err)
+	}
 	botManager := lifecycle.NewManager(
 		botLifeConfig.BotRegistry, botClient, lifecycleMediator,
-		lifecycleMetrics, botMonitor,
+		lifecycleMetrics, botMonitor, cfg.ResourcesConfig, botStates,
 	)
 
 	return BotLifecycle{
-		BotManager: botManager,
-		BotClient:  botClient,
+		BotManager:  botManager,
+		BotOperator: botManager,
+		BotActivity: botMonitor,
+		BotClient:   botClient,
 	}, nil
 }
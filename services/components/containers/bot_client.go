@@ -37,19 +37,21 @@ type BotClient interface {
 type botClient struct {
 	logConfig       config.LogConfig
 	resourcesConfig config.ResourcesConfig
-	client          clients.DockerClient
-	botImageClient  clients.DockerClient
+	runtimeConfig   config.ContainerRuntimeConfig
+	client          clients.ContainerRuntime
+	botImageClient  clients.ContainerRuntime
 }
 
 // NewBotClient creates a new bot client to manage bot containers.
 func NewBotClient(
-	logConfig config.LogConfig, resourcesConfig config.ResourcesConfig,
-	client clients.DockerClient, botImageClient clients.DockerClient,
+	logConfig config.LogConfig, resourcesConfig config.ResourcesConfig, runtimeConfig config.ContainerRuntimeConfig,
+	client clients.ContainerRuntime, botImageClient clients.ContainerRuntime,
 ) *botClient {
 	botImageClient.SetImagePullCooldown(ImagePullCooldownThreshold, ImagePullCooldownDuration)
 	return &botClient{
 		logConfig:       logConfig,
 		resourcesConfig: resourcesConfig,
+		runtimeConfig:   runtimeConfig,
 		client:          client,
 		botImageClient:  botImageClient,
 	}
@@ -77,7 +79,7 @@ func (bc *botClient) LaunchBot(ctx context.Context, botConfig config.AgentConfig
 	defer cancel()
 
 	// first make sure that the bot's bridge network exists
-	botNetworkID, err := bc.client.EnsurePublicNetwork(ctx, botConfig.ContainerName())
+	botNetworkID, err := bc.client.EnsurePublicNetwork(ctx, bc.botNetworkName(botConfig.ContainerName()))
 	if err != nil {
 		return fmt.Errorf("error creating public network: %v", err)
 	}
@@ -99,12 +101,26 @@ func (bc *botClient) LaunchBot(ctx context.Context, botConfig config.AgentConfig
 		return fmt.Errorf("unexpected error while getting the bot container '%s': %v", botConfig.ContainerName(), err)
 	}
 
+	// the service containers are already in the shared bot network
+	if !bc.runtimeConfig.AttachesNetworks() {
+		return nil
+	}
+
 	// at this point we have created a new bot container and a new bridge network for the bot
 	// or found the existing container and the network: it's time to ensure that all service containers
 	// are reattached to the bot's network
 	return bc.attachServiceContainers(ctx, botNetworkID)
 }
 
+// botNetworkName returns the shared bot network if the runtime cannot attach
+// the service containers to a network per bot.
+func (bc *botClient) botNetworkName(containerName string) string {
+	if bc.runtimeConfig.AttachesNetworks() {
+		return containerName
+	}
+	return config.DockerBotNetworkName
+}
+
 func (bc *botClient) attachServiceContainers(ctx context.Context, botNetworkID string) error {
 	serviceContainerIDs, err := bc.getServiceContainerIDs(ctx)
 	if err != nil {
@@ -146,9 +162,13 @@ func (bc *botClient) TearDownBot(ctx context.Context, containerName string, remo
 	if err != nil {
 		return fmt.Errorf("failed to get the bot container to tear down: %v", err)
 	}
-	serviceContainerIDs, err := bc.getServiceContainerIDs(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get service container ids during bot cleanup: %v", err)
+	sharedNetwork := !bc.runtimeConfig.AttachesNetworks()
+	var serviceContainerIDs []string
+	if !sharedNetwork {
+		serviceContainerIDs, err = bc.getServiceContainerIDs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get service container ids during bot cleanup: %v", err)
+		}
 	}
 	defer log.WithField("botContainer", containerName).Info("done tearing down the bot and the associated docker resources")
 	// not returning any errors in `if`s below so we keep on by removing whatever is left
@@ -185,12 +205,15 @@ func (bc *botClient) TearDownBot(ctx context.Context, containerName string, remo
 			},
 		).WithError(err).Warn("failed to remove the bot container")
 	}
-	if err := bc.client.RemoveNetworkByName(ctx, containerName); err != nil {
-		log.WithFields(
-			log.Fields{
-				"network": containerName,
-			},
-		).WithError(err).Warn("failed to destroy the bot network")
+	// keep the shared bot network for the other bots
+	if !sharedNetwork {
+		if err := bc.client.RemoveNetworkByName(ctx, containerName); err != nil {
+			log.WithFields(
+				log.Fields{
+					"network": containerName,
+				},
+			).WithError(err).Warn("failed to destroy the bot network")
+		}
 	}
 	if !removeImage {
 		return nil
@@ -29,8 +29,8 @@ const (
 type BotClientTestSuite struct {
 	r *require.Assertions
 
-	client         *mock_clients.MockDockerClient
-	botImageClient *mock_clients.MockDockerClient
+	client         *mock_clients.MockContainerRuntime
+	botImageClient *mock_clients.MockContainerRuntime
 
 	botClient *botClient
 
@@ -45,12 +45,12 @@ func (s *BotClientTestSuite) SetupTest() {
 	s.r = s.Require()
 
 	ctrl := gomock.NewController(s.T())
-	s.client = mock_clients.NewMockDockerClient(ctrl)
-	s.botImageClient = mock_clients.NewMockDockerClient(ctrl)
+	s.client = mock_clients.NewMockContainerRuntime(ctrl)
+	s.botImageClient = mock_clients.NewMockContainerRuntime(ctrl)
 
 	s.botImageClient.EXPECT().SetImagePullCooldown(ImagePullCooldownThreshold, ImagePullCooldownDuration)
 
-	s.botClient = NewBotClient(config.LogConfig{}, config.ResourcesConfig{}, s.client, s.botImageClient)
+	s.botClient = NewBotClient(config.LogConfig{}, config.ResourcesConfig{}, config.ContainerRuntimeConfig{}, s.client, s.botImageClient)
 }
 
 func (s *BotClientTestSuite) TestEnsureBotImages() {
@@ -29,7 +29,7 @@ func NewBotContainerConfig(
 	networkID string, botConfig config.AgentConfig,
 	logConfig config.LogConfig, resourcesConfig config.ResourcesConfig,
 ) docker.ContainerConfig {
-	limits := config.GetAgentResourceLimits(resourcesConfig)
+	limits := config.GetAgentResourceLimits(resourcesConfig, botConfig.Resources)
 
 	return docker.ContainerConfig{
 		Name:           botConfig.ContainerName(),
@@ -52,6 +52,8 @@ func NewBotContainerConfig(
 		MaxLogSize:  logConfig.MaxLogSize,
 		CPUQuota:    limits.CPUQuota,
 		Memory:      limits.Memory,
+		PidsLimit:   limits.Pids,
+		DiskSize:    limits.Disk,
 		Labels: map[string]string{
 			docker.LabelzktoroIsBot:                     LabelValuezktoroIsBot,
 			docker.LabelzktoroSupervisorStrategyVersion: LabelValueStrategyVersion,
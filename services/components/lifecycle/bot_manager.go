@@ -2,8 +2,10 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"zktoro/clients/docker"
@@ -13,6 +15,7 @@ import (
 	"zktoro/services/components/registry"
 	"zktoro/store"
 
+	"github.com/docker/docker/api/types"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -24,6 +27,9 @@ var (
 	heartbeatBotLoadInterval = time.Hour
 )
 
+// ErrBotNotFound is returned when an operator action refers to an unknown bot.
+var ErrBotNotFound = errors.New("bot not found")
+
 // BotLifecycleManager manages lifecycles of running bots.
 type BotLifecycleManager interface {
 	ManageBots(ctx context.Context) error
@@ -33,24 +39,47 @@ type BotLifecycleManager interface {
 	TearDownRunningBots(ctx context.Context)
 }
 
+// BotOperator lets the node operators inspect and control the bots.
+type BotOperator interface {
+	ListBots() []ManagedBot
+	RestartBot(ctx context.Context, botRef string) error
+	PauseBot(ctx context.Context, botRef string) error
+	ResumeBot(ctx context.Context, botRef string) error
+}
+
+// ManagedBot is an assigned bot with its lifecycle state.
+type ManagedBot struct {
+	Config config.AgentConfig
+	State  BotStateRecord
+}
+
 type botLifecycleManager struct {
 	botRegistry       registry.BotRegistry
 	botClient         containers.BotClient
 	botPool           BotPoolUpdater
 	lifecycleMetrics  metrics.Lifecycle
 	botMonitor        BotMonitor
+	resourcesCfg      config.ResourcesConfig
+	botStates         *BotStates
 	lastHeartbeatLoad time.Time
 
 	runningBots []config.AgentConfig
+	// the assigned bots which are held back by the backoff, the quarantine or the operator
+	heldBots []config.AgentConfig
+	botsMu   sync.RWMutex
+
+	// serializes the management rounds and the operator actions
+	mu sync.Mutex
 }
 
 var _ BotLifecycleManager = &botLifecycleManager{}
+var _ BotOperator = &botLifecycleManager{}
 
 // NewManager creates new.
 func NewManager(
 	botRegistry registry.BotRegistry, botClient containers.BotClient,
 	botPool BotPoolUpdater, lifecycleMetrics metrics.Lifecycle,
-	botMonitor BotMonitor,
+	botMonitor BotMonitor, resourcesCfg config.ResourcesConfig, botStates *BotStates,
 ) *botLifecycleManager {
 	return &botLifecycleManager{
 		botRegistry:      botRegistry,
@@ -58,6 +87,8 @@ func NewManager(
 		botPool:          botPool,
 		lifecycleMetrics: lifecycleMetrics,
 		botMonitor:       botMonitor,
+		resourcesCfg:     resourcesCfg,
+		botStates:        botStates,
 	}
 }
 
@@ -86,6 +117,9 @@ func (blm *botLifecycleManager) addHeartbeatBotIfDue(cfgs []config.AgentConfig)
 // ManageBots starts containers for assigned bots and stops the containers for unassigned
 // bots and lets other services know.
 func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
 	botsToRun, err := blm.botRegistry.LoadAssignedBots()
 	if err != nil {
 		blm.lifecycleMetrics.SystemError("load.assigned.bots", err)
@@ -96,6 +130,11 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 	// append the heartbeat bot if due to execute
 	botsToRun = blm.addHeartbeatBotIfDue(botsToRun)
 
+	// forget the held bots which are not assigned anymore
+	for _, unassignedBotConfig := range FindMissingBots(blm.heldBots, botsToRun) {
+		blm.transition(unassignedBotConfig, BotStateStopped, "unassigned")
+	}
+
 	// find the removed bots and remove them from the pool
 	removedBotConfigs := FindMissingBots(blm.runningBots, botsToRun)
 	if len(removedBotConfigs) > 0 {
@@ -112,6 +151,7 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 
 	// then stop the containers
 	for _, removedBotConfig := range removedBotConfigs {
+		blm.transition(removedBotConfig, BotStateStopped, "unassigned")
 		if err := blm.botClient.TearDownBot(ctx, removedBotConfig.ContainerName(), true); err != nil {
 			log.WithError(err).WithField("container", removedBotConfig.ContainerName()).
 				Warn("failed to tear down unassigned bot container")
@@ -119,12 +159,29 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 		}
 	}
 
+	// the bots which survived a whole round after the launch are running now
+	for _, keptBotConfig := range FindMissingBots(blm.runningBots, removedBotConfigs) {
+		if blm.botStates.State(keptBotConfig) == BotStateInitializing {
+			blm.transition(keptBotConfig, BotStateRunning, "initialized")
+		}
+	}
+
 	// find the bot containers to start
 	addedBotConfigs := FindExtraBots(blm.runningBots, botsToRun)
 
+	// skip the bots which are backing off, quarantined or paused until the next time
+	var heldBotConfigs []config.AgentConfig
+	addedBotConfigs, heldBotConfigs, botsToRun = blm.applyRestartBudget(addedBotConfigs, botsToRun)
+
+	// queue the bots which do not fit in the resource budget until the next time
+	addedBotConfigs, botsToRun = blm.applyResourceBudget(addedBotConfigs, botsToRun)
+
 	// then download all images concurrently
 	var downloadErrs []error
 	if len(addedBotConfigs) > 0 {
+		for _, addedBotConfig := range addedBotConfigs {
+			blm.transition(addedBotConfig, BotStatePulling, "")
+		}
 		downloadErrs = blm.botClient.EnsureBotImages(ctx, addedBotConfigs)
 	}
 
@@ -141,10 +198,12 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 			// drop the bot from the list so it can be picked again next time
 			botsToRun = Drop(addedBotConfig, botsToRun)
 			blm.lifecycleMetrics.FailurePull(downloadErrs[i], addedBotConfig)
+			blm.botStates.Fail(addedBotConfig, "pull failed")
 			continue
 		}
 
 		// skip if the bot could not start
+		blm.transition(addedBotConfig, BotStateLaunching, "")
 		err := blm.botClient.LaunchBot(ctx, addedBotConfig)
 		if err != nil {
 			log.WithError(err).WithField("container", addedBotConfig.ContainerName()).
@@ -152,8 +211,13 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 			// drop the bot from the list so it can be picked again next time
 			botsToRun = Drop(addedBotConfig, botsToRun)
 			blm.lifecycleMetrics.FailureLaunch(err, addedBotConfig)
+			blm.botStates.Fail(addedBotConfig, "launch failed")
 			continue
 		}
+		blm.transition(addedBotConfig, BotStateInitializing, "launched")
+		blm.lifecycleMetrics.ResourceLimits(
+			config.GetAgentResourceLimits(blm.resourcesCfg, addedBotConfig.Resources), addedBotConfig,
+		)
 	}
 
 	// then update the pool with latest bots
@@ -163,12 +227,80 @@ func (blm *botLifecycleManager) ManageBots(ctx context.Context) error {
 	blm.lifecycleMetrics.StatusRunning(botsToRun...)
 	blm.botMonitor.MonitorBots(GetBotIDs(botsToRun))
 
-	blm.runningBots = botsToRun
+	blm.setBots(botsToRun, heldBotConfigs)
 	return nil
 }
 
+// applyRestartBudget holds back the added bots which are backing off, quarantined or paused and
+// moves the rest to the pending state. The held bots are picked again next time.
+func (blm *botLifecycleManager) applyRestartBudget(
+	addedBotConfigs, botsToRun []config.AgentConfig,
+) (launchBotConfigs, heldBotConfigs, runBotConfigs []config.AgentConfig) {
+	for _, addedBotConfig := range addedBotConfigs {
+		if !blm.botStates.CanStart(addedBotConfig) {
+			log.WithFields(log.Fields{
+				"bot":   addedBotConfig.ID,
+				"state": blm.botStates.State(addedBotConfig),
+			}).Info("holding back the bot launch")
+			heldBotConfigs = append(heldBotConfigs, addedBotConfig)
+			botsToRun = Drop(addedBotConfig, botsToRun)
+			continue
+		}
+		blm.transition(addedBotConfig, BotStatePending, "assigned")
+		launchBotConfigs = append(launchBotConfigs, addedBotConfig)
+	}
+	return launchBotConfigs, heldBotConfigs, botsToRun
+}
+
+// applyResourceBudget drops the added bots which do not fit in the node resource budget
+// after the bots which keep running. The dropped bots are picked again next time.
+func (blm *botLifecycleManager) applyResourceBudget(
+	addedBotConfigs, botsToRun []config.AgentConfig,
+) (launchBotConfigs, runBotConfigs []config.AgentConfig) {
+	budget := blm.resourcesCfg.Budget
+	if !budget.IsSet() {
+		return addedBotConfigs, botsToRun
+	}
+
+	var committed config.BotResourceLimits
+	for _, botConfig := range FindMissingBots(botsToRun, addedBotConfigs) {
+		committed.Add(config.GetAgentResourceLimits(blm.resourcesCfg, botConfig.Resources))
+	}
+
+	var queuedBotConfigs []config.AgentConfig
+	for _, addedBotConfig := range addedBotConfigs {
+		limits := config.GetAgentResourceLimits(blm.resourcesCfg, addedBotConfig.Resources)
+		if !limits.Fits(&committed, budget) {
+			queuedBotConfigs = append(queuedBotConfigs, addedBotConfig)
+			botsToRun = Drop(addedBotConfig, botsToRun)
+			continue
+		}
+		committed.Add(limits)
+		launchBotConfigs = append(launchBotConfigs, addedBotConfig)
+	}
+
+	if len(queuedBotConfigs) > 0 {
+		log.WithFields(log.Fields{
+			"queued":        len(queuedBotConfigs),
+			"cpuQuota":      committed.CPUQuota,
+			"memory":        committed.Memory,
+			"pids":          committed.Pids,
+			"disk":          committed.Disk,
+			"budgetCpu":     budget.CPUs,
+			"budgetMib":     budget.MemoryMiB,
+			"budgetPids":    budget.Pids,
+			"budgetDiskMib": budget.DiskMiB,
+		}).Warn("node resource budget is exceeded - queued bot launches")
+		blm.lifecycleMetrics.StatusQueued(queuedBotConfigs...)
+	}
+	return launchBotConfigs, botsToRun
+}
+
 // CleanupUnusedBots cleans up unused bots.
 func (blm *botLifecycleManager) CleanupUnusedBots(ctx context.Context) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
 	if len(blm.runningBots) == 0 {
 		return nil
 	}
@@ -200,6 +332,9 @@ func (blm *botLifecycleManager) CleanupUnusedBots(ctx context.Context) error {
 
 // ExitInactiveBots exits inactive bots so the restart can pick them up later.
 func (blm *botLifecycleManager) ExitInactiveBots(ctx context.Context) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
 	inactiveBotIDs := blm.botMonitor.GetInactiveBots()
 	if len(inactiveBotIDs) == 0 {
 		return nil
@@ -215,6 +350,7 @@ func (blm *botLifecycleManager) ExitInactiveBots(ctx context.Context) error {
 			continue
 		}
 		inactiveCfgs = append(inactiveCfgs, botConfig)
+		blm.transition(botConfig, BotStateDegraded, "inactive")
 		logger.Info("killing inactive bot for reinitialization")
 		if err := blm.botClient.StopBot(ctx, botConfig); err != nil {
 			logger.WithError(err).Error("failed to stop the inactive bot")
@@ -228,6 +364,9 @@ func (blm *botLifecycleManager) ExitInactiveBots(ctx context.Context) error {
 
 // RestartExitedBots restarts bot containers when they are down and lets other services know.
 func (blm *botLifecycleManager) RestartExitedBots(ctx context.Context) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
 	botContainers, err := blm.botClient.LoadBotContainers(ctx)
 	if err != nil {
 		blm.lifecycleMetrics.SystemError("load.bot.containers", fmt.Errorf("failed to load bot containers: %v", err.Error()))
@@ -249,13 +388,29 @@ func (blm *botLifecycleManager) RestartExitedBots(ctx context.Context) error {
 			continue
 		}
 		logger = log.WithField("botId", restartedBotConfig.ID)
+
+		// count the exit against the restart budget once and then wait for the backoff
+		switch blm.botStates.State(restartedBotConfig) {
+		case BotStateBackoff, BotStateQuarantined:
+		default:
+			if blm.botStates.Fail(restartedBotConfig, "exited") == BotStateQuarantined {
+				logger.Warn("bot exceeded the restart budget - quarantined")
+			}
+		}
+		if !blm.botStates.CanStart(restartedBotConfig) {
+			continue
+		}
+
 		logger.Warn("restarting bot container")
+		blm.transition(restartedBotConfig, BotStateLaunching, "restart")
 		blm.lifecycleMetrics.ActionRestart(restartedBotConfig)
 		if err := blm.botClient.StartWaitBotContainer(ctx, botContainer.ID); err != nil {
 			logger.WithError(err).Error("failed to start exited bot container")
 			blm.lifecycleMetrics.BotError("start.exited.bot.container", fmt.Errorf("failed to start exited bot container: %v", err.Error()), restartedBotConfig)
+			blm.botStates.Fail(restartedBotConfig, "restart failed")
 			continue
 		}
+		blm.transition(restartedBotConfig, BotStateInitializing, "restarted")
 		restartedBotConfigs = append(restartedBotConfigs, restartedBotConfig)
 	}
 
@@ -270,6 +425,9 @@ func (blm *botLifecycleManager) RestartExitedBots(ctx context.Context) error {
 
 // TearDownRunningBots tears down all running bots.
 func (blm *botLifecycleManager) TearDownRunningBots(ctx context.Context) {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
 	if len(blm.runningBots) == 0 {
 		return
 	}
@@ -287,6 +445,10 @@ func (blm *botLifecycleManager) TearDownRunningBots(ctx context.Context) {
 
 	// then stop the containers
 	for _, runningBotConfig := range blm.runningBots {
+		// keep the quarantines for the next start
+		if blm.botStates.State(runningBotConfig) != BotStateQuarantined {
+			blm.transition(runningBotConfig, BotStateStopped, "teardown")
+		}
 		err := blm.botClient.TearDownBot(ctx, runningBotConfig.ContainerName(), false)
 		if err != nil {
 			blm.lifecycleMetrics.BotError("teardown.bot", err, runningBotConfig)
@@ -296,6 +458,173 @@ func (blm *botLifecycleManager) TearDownRunningBots(ctx context.Context) {
 	}
 }
 
+// ListBots returns the running and the held bots with their states.
+func (blm *botLifecycleManager) ListBots() []ManagedBot {
+	blm.botsMu.RLock()
+	defer blm.botsMu.RUnlock()
+
+	managedBots := make([]ManagedBot, 0, len(blm.runningBots)+len(blm.heldBots))
+	for _, botConfig := range append(append([]config.AgentConfig{}, blm.runningBots...), blm.heldBots...) {
+		record, ok := blm.botStates.Get(botConfig.ContainerName())
+		if !ok {
+			record = BotStateRecord{BotID: botConfig.ID, State: BotStatePending}
+		}
+		managedBots = append(managedBots, ManagedBot{Config: botConfig, State: record})
+	}
+	return managedBots
+}
+
+// RestartBot restarts the containers of a bot by the bot ID or the container name. The restart
+// budget of the bot is reset and the held bots are launched in the next management round.
+func (blm *botLifecycleManager) RestartBot(ctx context.Context, botRef string) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
+	runningBotConfigs := findBotsByRef(blm.runningBots, botRef)
+	heldBotConfigs := findBotsByRef(blm.heldBots, botRef)
+	if len(runningBotConfigs) == 0 && len(heldBotConfigs) == 0 {
+		return ErrBotNotFound
+	}
+	for _, heldBotConfig := range heldBotConfigs {
+		blm.reset(heldBotConfig, "restarted by operator")
+	}
+	if len(runningBotConfigs) == 0 {
+		return nil
+	}
+
+	botContainers, err := blm.botClient.LoadBotContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load bot containers: %v", err)
+	}
+
+	var (
+		restartedBotConfigs []config.AgentConfig
+		restartErr          error
+	)
+	for _, botConfig := range runningBotConfigs {
+		logger := log.WithField("container", botConfig.ContainerName())
+		botContainer, found := findContainer(botContainers, botConfig.ContainerName())
+		if !found {
+			logger.Warn("could not find the bot container to restart")
+			restartErr = fmt.Errorf("could not find the bot container: %s", botConfig.ContainerName())
+			continue
+		}
+
+		switch blm.botStates.State(botConfig) {
+		case BotStateBackoff, BotStateQuarantined:
+			blm.reset(botConfig, "restarted by operator")
+		}
+		logger.Info("restarting bot container by operator request")
+		blm.transition(botConfig, BotStateLaunching, "restarted by operator")
+		blm.lifecycleMetrics.ActionRestart(botConfig)
+		if botContainer.State != "exited" {
+			if err := blm.botClient.StopBot(ctx, botConfig); err != nil {
+				logger.WithError(err).Warn("failed to stop the bot container before restart")
+			}
+		}
+		if err := blm.botClient.StartWaitBotContainer(ctx, botContainer.ID); err != nil {
+			logger.WithError(err).Error("failed to restart the bot container")
+			blm.lifecycleMetrics.BotError("restart.bot.container", err, botConfig)
+			blm.botStates.Fail(botConfig, "restart failed")
+			restartErr = fmt.Errorf("failed to restart the bot container: %v", err)
+			continue
+		}
+		blm.transition(botConfig, BotStateInitializing, "restarted")
+		restartedBotConfigs = append(restartedBotConfigs, botConfig)
+	}
+
+	if len(restartedBotConfigs) > 0 {
+		if err := blm.botPool.ReconnectToBotsWithConfigs(restartedBotConfigs); err != nil {
+			blm.lifecycleMetrics.SystemError("reinit.bots.with.configs", fmt.Errorf("failed to reinit bots with configs: %v", err.Error()))
+		}
+	}
+	return restartErr
+}
+
+// PauseBot tears down the containers of a bot by the bot ID or the container name and holds the
+// bot back until it is resumed.
+func (blm *botLifecycleManager) PauseBot(ctx context.Context, botRef string) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
+	runningBotConfigs := findBotsByRef(blm.runningBots, botRef)
+	heldBotConfigs := findBotsByRef(blm.heldBots, botRef)
+	if len(runningBotConfigs) == 0 && len(heldBotConfigs) == 0 {
+		return ErrBotNotFound
+	}
+	for _, heldBotConfig := range heldBotConfigs {
+		blm.transition(heldBotConfig, BotStatePaused, "paused by operator")
+	}
+	if len(runningBotConfigs) == 0 {
+		return nil
+	}
+
+	if err := blm.botPool.RemoveBotsWithConfigs(runningBotConfigs); err != nil {
+		log.WithError(err).Error("error removing paused bots")
+		blm.lifecycleMetrics.SystemError("remove.bots.with.configs", err)
+	}
+	blm.lifecycleMetrics.StatusStopping(runningBotConfigs...)
+
+	// then wait a little to let the bot pool process this
+	time.Sleep(botRemoveTimeout)
+
+	runningBots, heldBots := blm.runningBots, blm.heldBots
+	for _, botConfig := range runningBotConfigs {
+		blm.transition(botConfig, BotStatePaused, "paused by operator")
+		if err := blm.botClient.TearDownBot(ctx, botConfig.ContainerName(), false); err != nil {
+			log.WithError(err).WithField("container", botConfig.ContainerName()).
+				Warn("failed to tear down paused bot container")
+			blm.lifecycleMetrics.BotError("paused.teardown", err, botConfig)
+		}
+		runningBots = Drop(botConfig, runningBots)
+		heldBots = append(heldBots, botConfig)
+	}
+	blm.setBots(runningBots, heldBots)
+	blm.botMonitor.MonitorBots(GetBotIDs(runningBots))
+	return nil
+}
+
+// ResumeBot lets the paused bot by the bot ID or the container name launch in the next
+// management round.
+func (blm *botLifecycleManager) ResumeBot(ctx context.Context, botRef string) error {
+	blm.mu.Lock()
+	defer blm.mu.Unlock()
+
+	var resumed bool
+	for _, botConfig := range findBotsByRef(blm.heldBots, botRef) {
+		if blm.botStates.State(botConfig) != BotStatePaused {
+			continue
+		}
+		blm.transition(botConfig, BotStatePending, "resumed by operator")
+		resumed = true
+	}
+	if !resumed {
+		return ErrBotNotFound
+	}
+	return nil
+}
+
+func (blm *botLifecycleManager) setBots(runningBots, heldBots []config.AgentConfig) {
+	blm.botsMu.Lock()
+	defer blm.botsMu.Unlock()
+
+	blm.runningBots = runningBots
+	blm.heldBots = heldBots
+}
+
+func (blm *botLifecycleManager) reset(botConfig config.AgentConfig, reason string) {
+	if err := blm.botStates.Reset(botConfig, reason); err != nil {
+		log.WithError(err).WithField("bot", botConfig.ID).Warn("failed to reset the bot state")
+	}
+}
+
+// transition moves the bot to the next state and logs the invalid transitions.
+func (blm *botLifecycleManager) transition(botConfig config.AgentConfig, to BotState, reason string) {
+	if err := blm.botStates.Transition(botConfig, to, reason); err != nil {
+		log.WithError(err).WithField("bot", botConfig.ID).Warn("failed to transition the bot state")
+	}
+}
+
 func (blm *botLifecycleManager) findBotConfig(containerName string) (config.AgentConfig, bool) {
 	for _, bot := range blm.runningBots {
 		if bot.ContainerName() == containerName {
@@ -313,3 +642,22 @@ func (blm *botLifecycleManager) findBotConfigByID(botID string) (config.AgentCon
 	}
 	return config.AgentConfig{}, false
 }
+
+// findBotsByRef finds the bots by the bot ID or the container name.
+func findBotsByRef(botList []config.AgentConfig, botRef string) (found []config.AgentConfig) {
+	for _, bot := range botList {
+		if bot.ID == botRef || bot.ContainerName() == botRef {
+			found = append(found, bot)
+		}
+	}
+	return
+}
+
+func findContainer(botContainers []types.Container, containerName string) (types.Container, bool) {
+	for _, botContainer := range botContainers {
+		if docker.GetContainerName(botContainer) == containerName {
+			return botContainer, true
+		}
+	}
+	return types.Container{}, false
+}
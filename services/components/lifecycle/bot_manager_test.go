@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	mock_agentgrpc "zktoro/clients/agentgrpc/mocks"
 	mock_clients "zktoro/clients/mocks"
@@ -56,7 +57,12 @@ func (s *BotLifecycleManagerTestSuite) SetupTest() {
 	s.botPool = mock_lifecycle.NewMockBotPoolUpdater(ctrl)
 	s.botMonitor = mock_lifecycle.NewMockBotMonitor(ctrl)
 
-	s.botManager = NewManager(s.botRegistry, s.botContainers, s.botPool, s.lifecycleMetrics, s.botMonitor)
+	// the state transitions are tested separately
+	s.lifecycleMetrics.EXPECT().StateTransition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	botStates, err := NewBotStates("", config.BotRestartConfig{}, s.lifecycleMetrics)
+	s.r.NoError(err)
+	s.botManager = NewManager(s.botRegistry, s.botContainers, s.botPool, s.lifecycleMetrics, s.botMonitor, config.ResourcesConfig{}, botStates)
 }
 
 func (s *BotLifecycleManagerTestSuite) TestAddUpdateRemove() {
@@ -110,6 +116,9 @@ func (s *BotLifecycleManagerTestSuite) TestAddUpdateRemove() {
 	s.botContainers.EXPECT().LaunchBot(gomock.Any(), addedBots[0]).Return(nil).Times(1)
 	s.botContainers.EXPECT().LaunchBot(gomock.Any(), addedBots[1]).Return(nil).Times(1)
 	s.botContainers.EXPECT().LaunchBot(gomock.Any(), addedBots[2]).Return(nil).Times(1)
+	for _, addedBot := range addedBots {
+		s.lifecycleMetrics.EXPECT().ResourceLimits(config.GetAgentResourceLimits(config.ResourcesConfig{}, nil), addedBot)
+	}
 
 	s.lifecycleMetrics.EXPECT().StatusRunning(addedBots).Times(1)
 	s.botPool.EXPECT().UpdateBotsWithLatestConfigs(addedBots)
@@ -118,6 +127,92 @@ func (s *BotLifecycleManagerTestSuite) TestAddUpdateRemove() {
 	s.r.NoError(s.botManager.ManageBots(context.Background()))
 }
 
+func (s *BotLifecycleManagerTestSuite) TestResourceBudget() {
+	resourcesCfg := config.ResourcesConfig{Budget: config.ResourceBudgetConfig{CPUs: 0.5}}
+	s.botManager.resourcesCfg = resourcesCfg
+
+	alreadyRunning := []config.AgentConfig{
+		{
+			ID:        testBotID1,
+			Image:     testImageRef1,
+			Resources: &config.BotResources{CPUs: 0.3},
+		},
+	}
+	latestAssigned := []config.AgentConfig{
+		alreadyRunning[0],
+		{
+			ID:    testBotID2,
+			Image: testImageRef2,
+		},
+		{
+			ID:        testBotID3,
+			Image:     testImageRef3,
+			Resources: &config.BotResources{CPUs: 0.1},
+		},
+	}
+	s.botManager.runningBots = alreadyRunning
+
+	s.botRegistry.EXPECT().LoadAssignedBots().Return(latestAssigned, nil).Times(1)
+	s.botRegistry.EXPECT().LoadHeartbeatBot().Return(nil, nil).Times(1)
+	s.lifecycleMetrics.EXPECT().SystemStatus("load.assigned.bots", "3")
+
+	// the default 0.2 cpus fit in the budget but the third bot does not
+	launchedBots := latestAssigned[:2]
+	s.botContainers.EXPECT().EnsureBotImages(gomock.Any(), latestAssigned[1:2]).Return([]error{nil}).Times(1)
+	s.botContainers.EXPECT().LaunchBot(gomock.Any(), latestAssigned[1]).Return(nil).Times(1)
+	s.lifecycleMetrics.EXPECT().ResourceLimits(config.GetAgentResourceLimits(resourcesCfg, nil), latestAssigned[1])
+	s.lifecycleMetrics.EXPECT().StatusQueued(latestAssigned[2])
+
+	s.lifecycleMetrics.EXPECT().StatusRunning(launchedBots).Times(1)
+	s.botPool.EXPECT().UpdateBotsWithLatestConfigs(launchedBots)
+	s.botMonitor.EXPECT().MonitorBots(GetBotIDs(launchedBots))
+
+	s.r.NoError(s.botManager.ManageBots(context.Background()))
+	s.r.Equal(launchedBots, s.botManager.runningBots)
+}
+
+func (s *BotLifecycleManagerTestSuite) TestResourceBudget_Pids() {
+	resourcesCfg := config.ResourcesConfig{AgentMaxPids: 100, Budget: config.ResourceBudgetConfig{Pids: 250}}
+	s.botManager.resourcesCfg = resourcesCfg
+
+	latestAssigned := []config.AgentConfig{
+		{
+			ID:    testBotID1,
+			Image: testImageRef1,
+		},
+		{
+			ID:        testBotID2,
+			Image:     testImageRef2,
+			Resources: &config.BotResources{Pids: 200},
+		},
+		{
+			ID:        testBotID3,
+			Image:     testImageRef3,
+			Resources: &config.BotResources{Pids: 150},
+		},
+	}
+
+	s.botRegistry.EXPECT().LoadAssignedBots().Return(latestAssigned, nil).Times(1)
+	s.botRegistry.EXPECT().LoadHeartbeatBot().Return(nil, nil).Times(1)
+	s.lifecycleMetrics.EXPECT().SystemStatus("load.assigned.bots", "3")
+
+	// the default 100 pids and the 150 pids fit in the budget but the 200 pids do not
+	launchedBots := []config.AgentConfig{latestAssigned[0], latestAssigned[2]}
+	s.botContainers.EXPECT().EnsureBotImages(gomock.Any(), launchedBots).Return([]error{nil, nil}).Times(1)
+	for _, botConfig := range launchedBots {
+		s.botContainers.EXPECT().LaunchBot(gomock.Any(), botConfig).Return(nil).Times(1)
+		s.lifecycleMetrics.EXPECT().ResourceLimits(config.GetAgentResourceLimits(resourcesCfg, botConfig.Resources), botConfig)
+	}
+	s.lifecycleMetrics.EXPECT().StatusQueued(latestAssigned[1])
+
+	s.lifecycleMetrics.EXPECT().StatusRunning(launchedBots).Times(1)
+	s.botPool.EXPECT().UpdateBotsWithLatestConfigs(launchedBots)
+	s.botMonitor.EXPECT().MonitorBots(GetBotIDs(launchedBots))
+
+	s.r.NoError(s.botManager.ManageBots(context.Background()))
+	s.r.Equal(launchedBots, s.botManager.runningBots)
+}
+
 func (s *BotLifecycleManagerTestSuite) TestLoadBotsError() {
 	err := errors.New("test err asigned bots")
 	s.botRegistry.EXPECT().LoadAssignedBots().Return(nil, err).Times(1)
@@ -240,3 +335,96 @@ func (s *BotLifecycleManagerTestSuite) TestTearDown() {
 
 	s.botManager.TearDownRunningBots(context.Background())
 }
+
+func (s *BotLifecycleManagerTestSuite) TestRestartBudget() {
+	botConfig := config.AgentConfig{
+		ID:    testBotID1,
+		Image: testImageRef,
+	}
+	s.botManager.runningBots = []config.AgentConfig{botConfig}
+
+	now := time.Now().UTC()
+	s.botManager.botStates.restartCfg = config.BotRestartConfig{
+		MaxRestarts:       1,
+		WindowSeconds:     3600,
+		BackoffSeconds:    60,
+		MaxBackoffSeconds: 600,
+		QuarantineSeconds: 3600,
+	}
+	s.botManager.botStates.now = func() time.Time { return now }
+
+	s.botContainers.EXPECT().LoadBotContainers(gomock.Any()).Return([]types.Container{
+		{
+			ID:    testContainerID1,
+			Names: []string{fmt.Sprintf("/%s", botConfig.ContainerName())},
+			State: "exited",
+		},
+	}, nil).Times(4)
+
+	// the first exit backs off
+	s.r.NoError(s.botManager.RestartExitedBots(context.Background()))
+	s.r.Equal(BotStateBackoff, s.botManager.botStates.State(botConfig))
+
+	// and the bot is restarted after the backoff
+	now = now.Add(time.Minute)
+	s.lifecycleMetrics.EXPECT().ActionRestart(botConfig)
+	s.botContainers.EXPECT().StartWaitBotContainer(gomock.Any(), testContainerID1).Return(nil)
+	s.botPool.EXPECT().ReconnectToBotsWithConfigs([]config.AgentConfig{botConfig})
+	s.r.NoError(s.botManager.RestartExitedBots(context.Background()))
+	s.r.Equal(BotStateInitializing, s.botManager.botStates.State(botConfig))
+
+	// the second exit exceeds the budget
+	s.r.NoError(s.botManager.RestartExitedBots(context.Background()))
+	s.r.Equal(BotStateQuarantined, s.botManager.botStates.State(botConfig))
+
+	// and the bot is restarted after the quarantine
+	now = now.Add(time.Hour)
+	s.lifecycleMetrics.EXPECT().ActionRestart(botConfig)
+	s.botContainers.EXPECT().StartWaitBotContainer(gomock.Any(), testContainerID1).Return(nil)
+	s.botPool.EXPECT().ReconnectToBotsWithConfigs([]config.AgentConfig{botConfig})
+	s.r.NoError(s.botManager.RestartExitedBots(context.Background()))
+	s.r.Equal(BotStateInitializing, s.botManager.botStates.State(botConfig))
+}
+
+func (s *BotLifecycleManagerTestSuite) TestPauseResumeRestartBot() {
+	botConfig := config.AgentConfig{ID: testBotID1, Image: testImageRef1}
+	otherBotConfig := config.AgentConfig{ID: testBotID2, Image: testImageRef2}
+	s.botManager.runningBots = []config.AgentConfig{botConfig, otherBotConfig}
+
+	s.r.ErrorIs(s.botManager.PauseBot(context.Background(), "unknown"), ErrBotNotFound)
+	s.r.ErrorIs(s.botManager.ResumeBot(context.Background(), botConfig.ID), ErrBotNotFound)
+
+	// pausing tears down the container and holds the bot back
+	s.botPool.EXPECT().RemoveBotsWithConfigs([]config.AgentConfig{botConfig})
+	s.lifecycleMetrics.EXPECT().StatusStopping(botConfig)
+	s.botContainers.EXPECT().TearDownBot(gomock.Any(), botConfig.ContainerName(), false)
+	s.botMonitor.EXPECT().MonitorBots([]string{otherBotConfig.ID})
+	s.r.NoError(s.botManager.PauseBot(context.Background(), botConfig.ContainerName()))
+	s.r.Equal(BotStatePaused, s.botManager.botStates.State(botConfig))
+	s.r.False(s.botManager.botStates.CanStart(botConfig))
+	s.r.Len(s.botManager.ListBots(), 2)
+
+	// resuming lets the bot start in the next round
+	s.r.NoError(s.botManager.ResumeBot(context.Background(), botConfig.ID))
+	s.r.Equal(BotStatePending, s.botManager.botStates.State(botConfig))
+	s.r.True(s.botManager.botStates.CanStart(botConfig))
+
+	// restarting a running bot restarts the container and resets the budget
+	s.botManager.botStates.Fail(otherBotConfig, "exited")
+	s.botContainers.EXPECT().LoadBotContainers(gomock.Any()).Return([]types.Container{
+		{
+			ID:    testContainerID2,
+			Names: []string{fmt.Sprintf("/%s", otherBotConfig.ContainerName())},
+			State: "running",
+		},
+	}, nil)
+	s.lifecycleMetrics.EXPECT().ActionRestart(otherBotConfig)
+	s.botContainers.EXPECT().StopBot(gomock.Any(), otherBotConfig)
+	s.botContainers.EXPECT().StartWaitBotContainer(gomock.Any(), testContainerID2).Return(nil)
+	s.botPool.EXPECT().ReconnectToBotsWithConfigs([]config.AgentConfig{otherBotConfig})
+	s.r.NoError(s.botManager.RestartBot(context.Background(), otherBotConfig.ID))
+	s.r.Equal(BotStateInitializing, s.botManager.botStates.State(otherBotConfig))
+	record, ok := s.botManager.botStates.Get(otherBotConfig.ContainerName())
+	s.r.True(ok)
+	s.r.Empty(record.Failures)
+}
@@ -2,6 +2,7 @@ package lifecycle
 
 import (
 	"sync"
+	"time"
 
 	"zktoro/services/components/metrics"
 
@@ -19,6 +20,17 @@ type BotMonitorState interface {
 	GetInactiveBots() []string
 }
 
+// BotActivity is the latest activity of a bot.
+type BotActivity struct {
+	LastActivity  time.Time
+	RecentMetrics []*protocol.AgentMetric
+}
+
+// BotActivityReader reads the latest activities of the monitored bots.
+type BotActivityReader interface {
+	GetBotActivity(botID string) (BotActivity, bool)
+}
+
 // BotMonitor monitors the statuses of the bots using the incoming metrics.
 type BotMonitor interface {
 	BotMonitorUpdater
@@ -32,6 +44,7 @@ type botMonitor struct {
 }
 
 var _ BotMonitor = &botMonitor{}
+var _ BotActivityReader = &botMonitor{}
 
 // NewBotMonitor creates a new bot monitor.
 func NewBotMonitor(lifecycleMetrics metrics.Lifecycle) *botMonitor {
@@ -53,6 +66,9 @@ func (bm *botMonitor) UpdateWithMetrics(botMetrics *protocol.AgentMetricList) er
 		if botMetric.Name == metrics.MetricStatusActive {
 			bm.saveBotActivity(botMetric.AgentId)
 		}
+		bm.findTrackerAndDo(botMetric.AgentId, func(tracker *BotTracker) {
+			tracker.SaveMetric(botMetric)
+		})
 	}
 
 	return nil
@@ -125,3 +141,18 @@ func (bm *botMonitor) GetInactiveBots() (inactive []string) {
 
 	return
 }
+
+// GetBotActivity returns the latest activity of a monitored bot.
+func (bm *botMonitor) GetBotActivity(botID string) (activity BotActivity, found bool) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	bm.findTrackerAndDo(botID, func(tracker *BotTracker) {
+		activity = BotActivity{
+			LastActivity:  tracker.LastActivity(),
+			RecentMetrics: tracker.RecentMetrics(),
+		}
+		found = true
+	})
+	return
+}
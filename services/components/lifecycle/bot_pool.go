@@ -146,7 +146,8 @@ func (bp *botPool) startBotClient(botConfig config.AgentConfig) botio.BotClient
 	return botClient
 }
 
-// RemoveBotsWithConfigs closes and discards the bots to be removed.
+// RemoveBotsWithConfigs closes and discards the bots to be removed. The requests spilled to the
+// disk for the removed bots are deleted.
 func (bp *botPool) RemoveBotsWithConfigs(removedBotConfigs messaging.AgentPayload) error {
 	bp.mu.Lock()
 	defer bp.mu.Unlock()
@@ -159,7 +160,9 @@ func (bp *botPool) RemoveBotsWithConfigs(removedBotConfigs messaging.AgentPayloa
 			logger.Info("could not find the removed bot! skipping")
 			continue
 		}
-		_ = botClient.Close()
+		if err := botClient.Discard(); err != nil {
+			logger.WithError(err).Warn("failed to discard the removed bot")
+		}
 	}
 
 	// find the bots we are not supposed to remove and keep them
@@ -108,7 +108,7 @@ func (s *BotPoolTestSuite) TestRemove() {
 	s.botPool.botClients = []botio.BotClient{s.botClient1, s.botClient2}
 	s.botClient1.EXPECT().Config().Return(assigned[0]).AnyTimes()
 	s.botClient2.EXPECT().Config().Return(assigned[1]).AnyTimes()
-	s.botClient1.EXPECT().Close().AnyTimes()
+	s.botClient1.EXPECT().Discard().AnyTimes()
 
 	s.botPool.RemoveBotsWithConfigs(removed)
 
@@ -0,0 +1,317 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"zktoro/config"
+	"zktoro/services/components/metrics"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BotState is a state in the lifecycle of a bot container.
+type BotState string
+
+// Bot states
+const (
+	BotStatePending      BotState = "pending"
+	BotStatePulling      BotState = "pulling"
+	BotStateLaunching    BotState = "launching"
+	BotStateInitializing BotState = "initializing"
+	BotStateRunning      BotState = "running"
+	BotStateDegraded     BotState = "degraded"
+	BotStateBackoff      BotState = "backoff"
+	BotStateQuarantined  BotState = "quarantined"
+	BotStatePaused       BotState = "paused"
+	BotStateStopped      BotState = "stopped"
+)
+
+// botStateTransitions are the allowed transitions. Any state except the quarantine can go back
+// to pending when the bot is reassigned, e.g. after a supervisor restart. The operators can pause
+// the bots in any state and can restart the started bots.
+var botStateTransitions = map[BotState][]BotState{
+	BotStatePending:      {BotStatePulling, BotStateLaunching, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStatePulling:      {BotStatePending, BotStateLaunching, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateLaunching:    {BotStatePending, BotStateInitializing, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateInitializing: {BotStatePending, BotStateLaunching, BotStateRunning, BotStateDegraded, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateRunning:      {BotStatePending, BotStateLaunching, BotStateDegraded, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateDegraded:     {BotStatePending, BotStateLaunching, BotStateBackoff, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateBackoff:      {BotStatePending, BotStatePulling, BotStateLaunching, BotStateQuarantined, BotStatePaused, BotStateStopped},
+	BotStateQuarantined:  {BotStatePending, BotStatePaused, BotStateStopped},
+	BotStatePaused:       {BotStatePending, BotStateStopped},
+	BotStateStopped:      {BotStatePending},
+}
+
+// CanTransition tells if the state can transition to the given state.
+func (state BotState) CanTransition(to BotState) bool {
+	for _, allowed := range botStateTransitions[state] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// BotStateRecord is the persisted lifecycle state of a bot container.
+type BotStateRecord struct {
+	BotID     string    `json:"botId"`
+	State     BotState  `json:"state"`
+	Reason    string    `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// the exits and the failed starts within the restart window
+	Failures         []time.Time `json:"failures,omitempty"`
+	BackoffUntil     time.Time   `json:"backoffUntil"`
+	QuarantinedUntil time.Time   `json:"quarantinedUntil"`
+}
+
+// BotStates is the lifecycle state machine of the bot containers. The states are kept by container
+// name and are persisted after every transition so that the restart budgets survive the supervisor
+// restarts.
+type BotStates struct {
+	statesPath       string
+	restartCfg       config.BotRestartConfig
+	lifecycleMetrics metrics.Lifecycle
+
+	records map[string]*BotStateRecord
+	mu      sync.Mutex
+
+	now func() time.Time
+}
+
+// NewBotStates loads the bot states from the file. The empty path keeps the states only in memory.
+func NewBotStates(statesPath string, restartCfg config.BotRestartConfig, lifecycleMetrics metrics.Lifecycle) (*BotStates, error) {
+	bs := &BotStates{
+		statesPath:       statesPath,
+		restartCfg:       restartCfg,
+		lifecycleMetrics: lifecycleMetrics,
+		records:          make(map[string]*BotStateRecord),
+		now:              func() time.Time { return time.Now().UTC() },
+	}
+	if statesPath == "" {
+		return bs, nil
+	}
+	b, err := os.ReadFile(statesPath)
+	if os.IsNotExist(err) {
+		return bs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &bs.records); err != nil {
+		return nil, fmt.Errorf("failed to decode the bot states: %v", err)
+	}
+	return bs, nil
+}
+
+// Get returns a copy of the state record of a bot container.
+func (bs *BotStates) Get(containerName string) (BotStateRecord, bool) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	record, ok := bs.records[containerName]
+	if !ok {
+		return BotStateRecord{}, false
+	}
+	recordCopy := *record
+	recordCopy.Failures = append([]time.Time(nil), record.Failures...)
+	return recordCopy, true
+}
+
+// All returns copies of all state records by container name.
+func (bs *BotStates) All() map[string]BotStateRecord {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	records := make(map[string]BotStateRecord, len(bs.records))
+	for containerName, record := range bs.records {
+		records[containerName] = *record
+	}
+	return records
+}
+
+// State returns the current state of a bot container. Unknown bots are pending.
+func (bs *BotStates) State(botConfig config.AgentConfig) BotState {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	record, ok := bs.records[botConfig.ContainerName()]
+	if !ok {
+		return BotStatePending
+	}
+	return record.State
+}
+
+// Transition moves the bot to the given state if the transition is allowed.
+func (bs *BotStates) Transition(botConfig config.AgentConfig, to BotState, reason string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	return bs.transition(botConfig, to, reason)
+}
+
+// Reset clears the restart budget, the backoff and the quarantine of the bot and moves it to
+// the pending state.
+func (bs *BotStates) Reset(botConfig config.AgentConfig, reason string) error {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	record := bs.getRecord(botConfig)
+	record.Failures = nil
+	record.BackoffUntil = time.Time{}
+	record.QuarantinedUntil = time.Time{}
+	if record.State == BotStatePending {
+		return bs.store()
+	}
+	return bs.transition(botConfig, BotStatePending, reason)
+}
+
+// CanStart tells if the bot is out of the backoff and the quarantine and is not paused. The expired
+// quarantines are lifted and the restart budget of the bot is reset.
+func (bs *BotStates) CanStart(botConfig config.AgentConfig) bool {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	record := bs.getRecord(botConfig)
+	now := bs.now()
+	switch record.State {
+	case BotStateQuarantined:
+		if now.Before(record.QuarantinedUntil) {
+			return false
+		}
+		record.Failures = nil
+		if err := bs.transition(botConfig, BotStatePending, "quarantine expired"); err != nil {
+			return false
+		}
+		return true
+
+	case BotStateBackoff:
+		return !now.Before(record.BackoffUntil)
+
+	case BotStatePaused:
+		return false
+
+	default:
+		return true
+	}
+}
+
+// Fail counts a failure against the restart budget of the bot and moves the bot to the backoff
+// or to the quarantine if the budget is exceeded.
+func (bs *BotStates) Fail(botConfig config.AgentConfig, reason string) BotState {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	record := bs.getRecord(botConfig)
+	now := bs.now()
+	window := time.Duration(bs.restartCfg.WindowSeconds) * time.Second
+	var failures []time.Time
+	for _, failure := range record.Failures {
+		if window == 0 || now.Sub(failure) < window {
+			failures = append(failures, failure)
+		}
+	}
+	record.Failures = append(failures, now)
+
+	to := BotStateBackoff
+	if bs.restartCfg.MaxRestarts > 0 && len(record.Failures) > bs.restartCfg.MaxRestarts {
+		to = BotStateQuarantined
+		record.QuarantinedUntil = now.Add(time.Duration(bs.restartCfg.QuarantineSeconds) * time.Second)
+	} else {
+		record.BackoffUntil = now.Add(bs.backoff(len(record.Failures)))
+	}
+
+	if record.State == to {
+		// no transition but the budget is updated
+		if err := bs.store(); err != nil {
+			log.WithError(err).Warn("failed to store the bot states")
+		}
+		return to
+	}
+	if err := bs.transition(botConfig, to, reason); err != nil {
+		log.WithError(err).WithField("bot", botConfig.ID).Warn("failed to apply the bot failure")
+	}
+	return record.State
+}
+
+// backoff doubles the backoff duration with every failure in the window up to the max.
+func (bs *BotStates) backoff(failures int) time.Duration {
+	backoff := time.Duration(bs.restartCfg.BackoffSeconds) * time.Second
+	maxBackoff := time.Duration(bs.restartCfg.MaxBackoffSeconds) * time.Second
+	for i := 1; i < failures && i < 32 && (maxBackoff == 0 || backoff < maxBackoff); i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func (bs *BotStates) getRecord(botConfig config.AgentConfig) *BotStateRecord {
+	record, ok := bs.records[botConfig.ContainerName()]
+	if !ok {
+		record = &BotStateRecord{BotID: botConfig.ID, State: BotStatePending}
+		bs.records[botConfig.ContainerName()] = record
+	}
+	return record
+}
+
+func (bs *BotStates) transition(botConfig config.AgentConfig, to BotState, reason string) error {
+	record := bs.getRecord(botConfig)
+	from := record.State
+	if from == to {
+		return nil
+	}
+	if !from.CanTransition(to) {
+		return fmt.Errorf("invalid bot state transition: %s -> %s", from, to)
+	}
+	record.State = to
+	record.Reason = reason
+	record.UpdatedAt = bs.now()
+	bs.lifecycleMetrics.StateTransition(string(from), string(to), reason, botConfig)
+	log.WithFields(log.Fields{
+		"bot":    botConfig.ID,
+		"from":   from,
+		"to":     to,
+		"reason": reason,
+	}).Debug("bot state transition")
+
+	if err := bs.store(); err != nil {
+		log.WithError(err).Warn("failed to store the bot states")
+	}
+	return nil
+}
+
+// store writes the states file atomically. The stopped bots without recent failures are
+// forgotten as there is nothing left to remember about them.
+func (bs *BotStates) store() error {
+	window := time.Duration(bs.restartCfg.WindowSeconds) * time.Second
+	for containerName, record := range bs.records {
+		if record.State != BotStateStopped {
+			continue
+		}
+		if len(record.Failures) == 0 || (window > 0 && bs.now().Sub(record.Failures[len(record.Failures)-1]) >= window) {
+			delete(bs.records, containerName)
+		}
+	}
+
+	if bs.statesPath == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(bs.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(bs.statesPath), 0755); err != nil {
+		return err
+	}
+	tmpPath := bs.statesPath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, bs.statesPath)
+}
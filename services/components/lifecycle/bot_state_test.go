@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"zktoro/config"
+	mock_metrics "zktoro/services/components/metrics/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testRestartCfg = config.BotRestartConfig{
+	MaxRestarts:       3,
+	WindowSeconds:     3600,
+	BackoffSeconds:    10,
+	MaxBackoffSeconds: 25,
+	QuarantineSeconds: 600,
+}
+
+func TestBotStateTransitions(t *testing.T) {
+	r := require.New(t)
+
+	lifecycleMetrics := mock_metrics.NewMockLifecycle(gomock.NewController(t))
+	botStates, err := NewBotStates("", testRestartCfg, lifecycleMetrics)
+	r.NoError(err)
+
+	botConfig := config.AgentConfig{ID: testBotID1, Image: testImageRef1}
+	r.Equal(BotStatePending, botStates.State(botConfig))
+
+	gomock.InOrder(
+		lifecycleMetrics.EXPECT().StateTransition("pending", "pulling", "", botConfig),
+		lifecycleMetrics.EXPECT().StateTransition("pulling", "launching", "", botConfig),
+		lifecycleMetrics.EXPECT().StateTransition("launching", "initializing", "launched", botConfig),
+		lifecycleMetrics.EXPECT().StateTransition("initializing", "running", "initialized", botConfig),
+	)
+	r.NoError(botStates.Transition(botConfig, BotStatePulling, ""))
+	r.NoError(botStates.Transition(botConfig, BotStateLaunching, ""))
+	r.NoError(botStates.Transition(botConfig, BotStateInitializing, "launched"))
+	r.NoError(botStates.Transition(botConfig, BotStateRunning, "initialized"))
+
+	// same state is not a transition
+	r.NoError(botStates.Transition(botConfig, BotStateRunning, "initialized"))
+	// and a running bot must be restarted
+	r.Error(botStates.Transition(botConfig, BotStateInitializing, ""))
+	r.Equal(BotStateRunning, botStates.State(botConfig))
+}
+
+func TestBotStatesBackoffAndQuarantine(t *testing.T) {
+	r := require.New(t)
+
+	lifecycleMetrics := mock_metrics.NewMockLifecycle(gomock.NewController(t))
+	lifecycleMetrics.EXPECT().StateTransition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	botStates, err := NewBotStates("", testRestartCfg, lifecycleMetrics)
+	r.NoError(err)
+	now := time.Now().UTC()
+	botStates.now = func() time.Time { return now }
+
+	botConfig := config.AgentConfig{ID: testBotID1, Image: testImageRef1}
+
+	// the backoff doubles up to the max
+	for _, backoff := range []time.Duration{10 * time.Second, 20 * time.Second, 25 * time.Second} {
+		r.Equal(BotStateBackoff, botStates.Fail(botConfig, "exited"))
+		r.False(botStates.CanStart(botConfig))
+		now = now.Add(backoff)
+		r.True(botStates.CanStart(botConfig))
+		r.NoError(botStates.Transition(botConfig, BotStateLaunching, "restart"))
+	}
+
+	// and the bot is quarantined when it exceeds the budget
+	r.Equal(BotStateQuarantined, botStates.Fail(botConfig, "exited"))
+	now = now.Add(time.Minute)
+	r.False(botStates.CanStart(botConfig))
+
+	// until the quarantine expires and the budget is reset
+	now = now.Add(10 * time.Minute)
+	r.True(botStates.CanStart(botConfig))
+	r.Equal(BotStatePending, botStates.State(botConfig))
+	record, ok := botStates.Get(botConfig.ContainerName())
+	r.True(ok)
+	r.Empty(record.Failures)
+
+	// the failures out of the window are not counted
+	for i := 0; i < testRestartCfg.MaxRestarts+2; i++ {
+		r.Equal(BotStateBackoff, botStates.Fail(botConfig, "exited"))
+		now = now.Add(time.Hour)
+	}
+}
+
+func TestBotStatesPersisted(t *testing.T) {
+	r := require.New(t)
+
+	lifecycleMetrics := mock_metrics.NewMockLifecycle(gomock.NewController(t))
+	lifecycleMetrics.EXPECT().StateTransition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	statesPath := path.Join(t.TempDir(), config.DefaultBotStatesFileName)
+
+	botStates, err := NewBotStates(statesPath, config.BotRestartConfig{MaxRestarts: 1, WindowSeconds: 3600, QuarantineSeconds: 600}, lifecycleMetrics)
+	r.NoError(err)
+
+	crashingBot := config.AgentConfig{ID: testBotID1, Image: testImageRef1}
+	stoppedBot := config.AgentConfig{ID: testBotID2, Image: testImageRef2}
+	r.Equal(BotStateBackoff, botStates.Fail(crashingBot, "exited"))
+	r.Equal(BotStateQuarantined, botStates.Fail(crashingBot, "exited"))
+	r.NoError(botStates.Transition(stoppedBot, BotStateStopped, "unassigned"))
+
+	// the quarantine should survive the restart and the stopped bot should be forgotten
+	botStates, err = NewBotStates(statesPath, config.BotRestartConfig{}, lifecycleMetrics)
+	r.NoError(err)
+	r.Equal(BotStateQuarantined, botStates.State(crashingBot))
+	r.False(botStates.CanStart(crashingBot))
+	_, ok := botStates.Get(stoppedBot.ContainerName())
+	r.False(ok)
+	r.Len(botStates.All(), 1)
+}
@@ -2,6 +2,8 @@ package lifecycle
 
 import (
 	"time"
+
+	"zktoro/zktoro-core-go/protocol"
 )
 
 // Timeouts
@@ -10,11 +12,15 @@ const (
 	inactivityThreshold = time.Minute * 15
 )
 
+// maxRecentMetrics is the number of the latest metrics kept per bot.
+const maxRecentMetrics = 20
+
 // BotTracker tracks activity time of a bot.
 type BotTracker struct {
-	botID        string
-	lastActivity time.Time
-	lastRead     time.Time
+	botID         string
+	lastActivity  time.Time
+	lastRead      time.Time
+	recentMetrics []*protocol.AgentMetric
 }
 
 // NewBotTracker creates new.
@@ -49,3 +55,21 @@ func (bt *BotTracker) SaveActivity() {
 func (bt *BotTracker) BotID() string {
 	return bt.botID
 }
+
+// LastActivity returns the time of the last activity.
+func (bt *BotTracker) LastActivity() time.Time {
+	return bt.lastActivity
+}
+
+// SaveMetric keeps the metric among the recent metrics of the bot.
+func (bt *BotTracker) SaveMetric(metric *protocol.AgentMetric) {
+	bt.recentMetrics = append(bt.recentMetrics, metric)
+	if len(bt.recentMetrics) > maxRecentMetrics {
+		bt.recentMetrics = bt.recentMetrics[len(bt.recentMetrics)-maxRecentMetrics:]
+	}
+}
+
+// RecentMetrics returns the latest metrics of the bot from the oldest to the newest.
+func (bt *BotTracker) RecentMetrics() []*protocol.AgentMetric {
+	return append([]*protocol.AgentMetric(nil), bt.recentMetrics...)
+}
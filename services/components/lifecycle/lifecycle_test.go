@@ -97,11 +97,15 @@ func (s *LifecycleTestSuite) SetupTest() {
 	s.lifecycleMetrics.EXPECT().HealthCheckAttempt(gomock.Any()).AnyTimes()
 	s.botGrpc.EXPECT().DoHealthCheck(gomock.Any()).AnyTimes()
 	s.lifecycleMetrics.EXPECT().HealthCheckSuccess(gomock.Any()).AnyTimes()
+	s.lifecycleMetrics.EXPECT().ResourceLimits(gomock.Any(), gomock.Any()).AnyTimes()
+	s.lifecycleMetrics.EXPECT().StateTransition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-	botClientFactory := botio.NewBotClientFactory(s.resultChannels.SendOnly(), s.msgClient, s.lifecycleMetrics, s.dialer)
+	botClientFactory := botio.NewBotClientFactory(s.resultChannels.SendOnly(), s.msgClient, s.lifecycleMetrics, s.dialer, "")
 	s.botPool = NewBotPool(context.Background(), s.lifecycleMetrics, botClientFactory, 0)
 	s.botPool.waitInit = true // hack to make testing synchronous
-	s.botManager = NewManager(s.botRegistry, s.botContainers, s.botPool, s.lifecycleMetrics, s.botMonitor)
+	botStates, err := NewBotStates("", config.BotRestartConfig{}, s.lifecycleMetrics)
+	s.r.NoError(err)
+	s.botManager = NewManager(s.botRegistry, s.botContainers, s.botPool, s.lifecycleMetrics, s.botMonitor, config.ResourcesConfig{}, botStates)
 }
 
 func (s *LifecycleTestSuite) TestDownloadTimeout() {
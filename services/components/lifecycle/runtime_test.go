@@ -0,0 +1,122 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zktoro/clients/docker"
+	"zktoro/clients/fake"
+	"zktoro/config"
+	"zktoro/services/components/containers"
+	mock_lifecycle "zktoro/services/components/lifecycle/mocks"
+	mock_metrics "zktoro/services/components/metrics/mocks"
+	mock_registry "zktoro/services/components/registry/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testServiceContainerNames = []string{
+	config.DockerScannerContainerName, config.DockerJSONRPCProxyContainerName,
+	config.DockerJWTProviderContainerName, config.DockerPublicAPIProxyContainerName,
+}
+
+// TestManageBotsWithRuntime runs the bot manager with the concrete bot client on top of
+// the in-memory container runtime and checks the containers and the networks.
+func TestManageBotsWithRuntime(t *testing.T) {
+	for _, runtimeType := range []config.ContainerRuntimeType{
+		config.ContainerRuntimeDocker, config.ContainerRuntimeContainerd,
+	} {
+		t.Run(string(runtimeType), func(t *testing.T) {
+			testManageBotsWithRuntime(t, config.ContainerRuntimeConfig{Type: runtimeType})
+		})
+	}
+}
+
+func testManageBotsWithRuntime(t *testing.T, runtimeCfg config.ContainerRuntimeConfig) {
+	r := require.New(t)
+	ctx := context.Background()
+	botRemoveTimeout = 0
+
+	// given that the service containers are running
+	runtime := fake.NewContainerRuntime()
+	runtime.AddImage("zktoro-node")
+	var linkNetworkIDs []string
+	if !runtimeCfg.AttachesNetworks() {
+		botNetworkID, err := runtime.EnsurePublicNetwork(ctx, config.DockerBotNetworkName)
+		r.NoError(err)
+		linkNetworkIDs = append(linkNetworkIDs, botNetworkID)
+	}
+	for _, name := range testServiceContainerNames {
+		_, err := runtime.StartContainer(ctx, docker.ContainerConfig{
+			Name:           name,
+			Image:          "zktoro-node",
+			LinkNetworkIDs: linkNetworkIDs,
+		})
+		r.NoError(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	botRegistry := mock_registry.NewMockBotRegistry(ctrl)
+	botPool := mock_lifecycle.NewMockBotPoolUpdater(ctrl)
+	botMonitor := mock_lifecycle.NewMockBotMonitor(ctrl)
+	lifecycleMetrics := mock_metrics.NewMockLifecycle(ctrl)
+	lifecycleMetrics.EXPECT().SystemStatus(gomock.Any(), gomock.Any()).AnyTimes()
+	lifecycleMetrics.EXPECT().ResourceLimits(gomock.Any(), gomock.Any()).AnyTimes()
+	lifecycleMetrics.EXPECT().StatusRunning(gomock.Any()).AnyTimes()
+	lifecycleMetrics.EXPECT().StatusStopping(gomock.Any()).AnyTimes()
+	lifecycleMetrics.EXPECT().StateTransition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	botPool.EXPECT().UpdateBotsWithLatestConfigs(gomock.Any()).AnyTimes()
+	botPool.EXPECT().RemoveBotsWithConfigs(gomock.Any()).AnyTimes()
+	botMonitor.EXPECT().MonitorBots(gomock.Any()).AnyTimes()
+
+	botClient := containers.NewBotClient(config.LogConfig{}, config.ResourcesConfig{}, runtimeCfg, runtime, runtime)
+	botStates, err := NewBotStates("", config.BotRestartConfig{}, lifecycleMetrics)
+	r.NoError(err)
+	botManager := NewManager(botRegistry, botClient, botPool, lifecycleMetrics, botMonitor, config.ResourcesConfig{}, botStates)
+	botManager.lastHeartbeatLoad = time.Now().UTC().Add(-10 * time.Minute)
+
+	assigned := []config.AgentConfig{{ID: testBotID1, Image: testImageRef1}}
+	botNetworkName := assigned[0].ContainerName()
+	if !runtimeCfg.AttachesNetworks() {
+		botNetworkName = config.DockerBotNetworkName
+	}
+
+	// when a bot is assigned
+	botRegistry.EXPECT().LoadAssignedBots().Return(assigned, nil)
+	r.NoError(botManager.ManageBots(ctx))
+
+	// then the bot container should run in the bot network with the service containers
+	botContainer, err := runtime.GetContainerByName(ctx, assigned[0].ContainerName())
+	r.NoError(err)
+	r.Equal(fake.StateRunning, botContainer.State)
+	r.Equal(testImageRef1, botContainer.Image)
+	r.Contains(botContainer.NetworkSettings.Networks, botNetworkName)
+	for _, name := range testServiceContainerNames {
+		serviceContainer, err := runtime.GetContainerByName(ctx, name)
+		r.NoError(err)
+		r.Contains(serviceContainer.NetworkSettings.Networks, botNetworkName)
+	}
+	bots, err := botClient.LoadBotContainers(ctx)
+	r.NoError(err)
+	r.Len(bots, 1)
+
+	// and when the bot is unassigned
+	botRegistry.EXPECT().LoadAssignedBots().Return(nil, nil)
+	r.NoError(botManager.ManageBots(ctx))
+
+	// then the bot container should be removed
+	_, err = runtime.GetContainerByName(ctx, assigned[0].ContainerName())
+	r.ErrorIs(err, docker.ErrContainerNotFound)
+	hasImage, err := runtime.HasLocalImage(ctx, testImageRef1)
+	r.NoError(err)
+	r.False(hasImage)
+
+	// and only the shared bot network should be kept
+	if runtimeCfg.AttachesNetworks() {
+		r.NotContains(runtime.NetworkNames(), botNetworkName)
+	} else {
+		r.Contains(runtime.NetworkNames(), botNetworkName)
+	}
+}
@@ -23,6 +23,7 @@ const (
 	MetricStatusStopping    = "agent.status.stopping"
 	MetricStatusActive      = "agent.status.active"
 	MetricStatusInactive    = "agent.status.inactive"
+	MetricStatusQueued      = "agent.status.queued"
 
 	MetricActionUpdate      = "agent.action.update"
 	MetricActionRestart     = "agent.action.restart"
@@ -41,6 +42,11 @@ const (
 	MetricHealthCheckAttempt = "agent.health.attempt"
 	MetricHealthCheckSuccess = "agent.health.success"
 	MetricHealthCheckError   = "agent.health.error"
+
+	MetricResourcesCPUs   = "agent.resources.cpus"
+	MetricResourcesMemory = "agent.resources.memory" // in MiB
+	MetricResourcesPids   = "agent.resources.pids"
+	MetricResourcesDisk   = "agent.resources.disk" // in MiB
 )
 
 // Lifecycle creates lifecycle metrics. It is useful in
@@ -55,6 +61,7 @@ type Lifecycle interface {
 	StatusStopping(...config.AgentConfig)
 	StatusActive(...config.AgentConfig)
 	StatusInactive(...config.AgentConfig)
+	StatusQueued(...config.AgentConfig)
 
 	ActionUpdate(...config.AgentConfig)
 	ActionRestart(...config.AgentConfig)
@@ -78,6 +85,10 @@ type Lifecycle interface {
 	HealthCheckAttempt(botConfigs ...config.AgentConfig)
 	HealthCheckSuccess(botConfigs ...config.AgentConfig)
 	HealthCheckError(err error, botConfigs ...config.AgentConfig)
+
+	ResourceLimits(limits *config.BotResourceLimits, botConfig config.AgentConfig)
+
+	StateTransition(from, to, reason string, botConfig config.AgentConfig)
 }
 
 type lifecycle struct {
@@ -123,6 +134,10 @@ func (lc *lifecycle) StatusInactive(botConfigs ...config.AgentConfig) {
 	SendAgentMetrics(lc.msgClient, fromBotConfigs(MetricStatusInactive, "", botConfigs))
 }
 
+func (lc *lifecycle) StatusQueued(botConfigs ...config.AgentConfig) {
+	SendAgentMetrics(lc.msgClient, fromBotConfigs(MetricStatusQueued, "", botConfigs))
+}
+
 func (lc *lifecycle) ActionUpdate(botConfigs ...config.AgentConfig) {
 	SendAgentMetrics(lc.msgClient, fromBotConfigs(MetricActionUpdate, "", botConfigs))
 }
@@ -195,6 +210,25 @@ func (lc *lifecycle) HealthCheckError(err error, botConfigs ...config.AgentConfi
 	SendAgentMetrics(lc.msgClient, fromBotConfigs(MetricHealthCheckError, err.Error(), botConfigs))
 }
 
+// ResourceLimits reports the effective resource limits of a bot container. Zero values mean no limits.
+func (lc *lifecycle) ResourceLimits(limits *config.BotResourceLimits, botConfig config.AgentConfig) {
+	SendAgentMetrics(lc.msgClient, []*protocol.AgentMetric{
+		CreateAgentMetric(botConfig, MetricResourcesCPUs, float64(limits.CPUQuota)/float64(config.CPUsToMicroseconds(1))),
+		CreateAgentMetric(botConfig, MetricResourcesMemory, float64(limits.Memory)/float64(config.MiBToBytes(1))),
+		CreateAgentMetric(botConfig, MetricResourcesPids, float64(limits.Pids)),
+		CreateAgentMetric(botConfig, MetricResourcesDisk, float64(limits.Disk)/float64(config.MiBToBytes(1))),
+	})
+}
+
+// StateTransition reports a bot lifecycle state transition as "agent.state.<to>".
+func (lc *lifecycle) StateTransition(from, to, reason string, botConfig config.AgentConfig) {
+	details := fmt.Sprintf("from=%s", from)
+	if reason != "" {
+		details = fmt.Sprintf("%s reason=%s", details, reason)
+	}
+	SendAgentMetrics(lc.msgClient, fromBotConfigs(fmt.Sprintf("agent.state.%s", to), details, []config.AgentConfig{botConfig}))
+}
+
 func fromBotSubscriptions(action string, subscriptions []domain.CombinerBotSubscription) (metrics []*protocol.AgentMetric) {
 	for _, botSub := range subscriptions {
 		metrics = append(metrics, CreateAgentMetric(config.AgentConfig{ID: botSub.Subscriber.BotID}, action, 1))
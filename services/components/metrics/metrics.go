@@ -29,10 +29,19 @@ const (
 	MetricBlockSuccess  = "block.success"
 	MetricBlockDrop     = "block.drop"
 
+	MetricTxSpill           = "tx.spill"
+	MetricTxQueueSize       = "tx.queue.size"
+	MetricTxQueueSpilled    = "tx.queue.spilled"
+	MetricBlockSpill        = "block.spill"
+	MetricBlockQueueSize    = "block.queue.size"
+	MetricBlockQueueSpilled = "block.queue.spilled"
+
 	MetricJSONRPCLatency          = "jsonrpc.latency"
 	MetricJSONRPCRequest          = "jsonrpc.request"
 	MetricJSONRPCSuccess          = "jsonrpc.success"
 	MetricJSONRPCThrottled        = "jsonrpc.throttled"
+	MetricJSONRPCCacheHit         = "jsonrpc.cache.hit"
+	MetricJSONRPCCacheMiss        = "jsonrpc.cache.miss"
 	MetricPublicAPIProxyLatency   = "publicapi.latency"
 	MetricPublicAPIProxyRequest   = "publicapi.request"
 	MetricPublicAPIProxySuccess   = "publicapi.success"
@@ -134,7 +143,7 @@ func GetCombinerMetrics(agt config.AgentConfig, resp *protocol.EvaluateAlertResp
 	return createMetrics(agt, resp.Timestamp, metrics)
 }
 
-func GetJSONRPCMetrics(agt config.AgentConfig, at time.Time, success, throttled int, latencyMs time.Duration) []*protocol.AgentMetric {
+func GetJSONRPCMetrics(agt config.AgentConfig, at time.Time, success, throttled, cacheHits, cacheMisses int, latencyMs time.Duration) []*protocol.AgentMetric {
 	values := make(map[string]float64)
 	if latencyMs > 0 {
 		values[MetricJSONRPCLatency] = float64(latencyMs.Milliseconds())
@@ -147,6 +156,12 @@ func GetJSONRPCMetrics(agt config.AgentConfig, at time.Time, success, throttled
 		values[MetricJSONRPCThrottled] = float64(throttled)
 		values[MetricJSONRPCRequest] += float64(throttled)
 	}
+	if cacheHits > 0 {
+		values[MetricJSONRPCCacheHit] = float64(cacheHits)
+	}
+	if cacheMisses > 0 {
+		values[MetricJSONRPCCacheMiss] = float64(cacheMisses)
+	}
 	return createMetrics(agt, at.Format(time.RFC3339), values)
 }
 
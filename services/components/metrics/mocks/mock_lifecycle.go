@@ -325,6 +325,30 @@ func (mr *MockLifecycleMockRecorder) HealthCheckSuccess(botConfigs ...interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheckSuccess", reflect.TypeOf((*MockLifecycle)(nil).HealthCheckSuccess), botConfigs...)
 }
 
+// ResourceLimits mocks base method.
+func (m *MockLifecycle) ResourceLimits(limits *config.BotResourceLimits, botConfig config.AgentConfig) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResourceLimits", limits, botConfig)
+}
+
+// ResourceLimits indicates an expected call of ResourceLimits.
+func (mr *MockLifecycleMockRecorder) ResourceLimits(limits, botConfig interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceLimits", reflect.TypeOf((*MockLifecycle)(nil).ResourceLimits), limits, botConfig)
+}
+
+// StateTransition mocks base method.
+func (m *MockLifecycle) StateTransition(from, to, reason string, botConfig config.AgentConfig) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StateTransition", from, to, reason, botConfig)
+}
+
+// StateTransition indicates an expected call of StateTransition.
+func (mr *MockLifecycleMockRecorder) StateTransition(from, to, reason, botConfig interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateTransition", reflect.TypeOf((*MockLifecycle)(nil).StateTransition), from, to, reason, botConfig)
+}
+
 // StatusActive mocks base method.
 func (m *MockLifecycle) StatusActive(arg0 ...config.AgentConfig) {
 	m.ctrl.T.Helper()
@@ -389,6 +413,22 @@ func (mr *MockLifecycleMockRecorder) StatusInitialized(arg0 ...interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusInitialized", reflect.TypeOf((*MockLifecycle)(nil).StatusInitialized), arg0...)
 }
 
+// StatusQueued mocks base method.
+func (m *MockLifecycle) StatusQueued(arg0 ...config.AgentConfig) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "StatusQueued", varargs...)
+}
+
+// StatusQueued indicates an expected call of StatusQueued.
+func (mr *MockLifecycleMockRecorder) StatusQueued(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusQueued", reflect.TypeOf((*MockLifecycle)(nil).StatusQueued), arg0...)
+}
+
 // StatusRunning mocks base method.
 func (m *MockLifecycle) StatusRunning(arg0 ...config.AgentConfig) {
 	m.ctrl.T.Helper()
@@ -21,6 +21,14 @@ func newGauge(desc *prometheus.Desc, value float64, labels ...string) prometheus
 func transformHealthMetricsToProm(metricKinds []*MetricKind, metrics HealthMetrics) (promMetrics []prometheus.Metric) {
 	for _, metricKind := range metricKinds {
 		for _, mapping := range metricKind.Mappings {
+			if len(mapping.FromHealthPrefix) > 0 {
+				for _, metric := range metrics.WithPrefix(mapping.FromHealthPrefix) {
+					// the label is the rest of the original name
+					label := metric.ReportName[len(mapping.FromHealthPrefix):]
+					promMetrics = append(promMetrics, newGauge(metricKind.Desc, metric.Value(), label))
+				}
+				continue
+			}
 			if metric, ok := metrics.Get(mapping.FromHealth); ok {
 				promMetrics = append(promMetrics, newGauge(metricKind.Desc, metric.Value(), mapping.ToProm))
 			}
@@ -37,6 +45,8 @@ type MetricKind struct {
 type Mapping struct {
 	FromHealth string
 	ToProm     string
+	// FromHealthPrefix maps all of the metrics with the prefix and labels them by the rest of the name.
+	FromHealthPrefix string
 }
 
 var knownMetricKinds = []*MetricKind{
@@ -263,6 +273,10 @@ var knownMetricKinds = []*MetricKind{
 				FromHealth: "publisher_event_batch_publish_error",
 				ToProm:     "batch_publish",
 			},
+			{
+				FromHealth: "publisher_event_outbox_send_error",
+				ToProm:     "outbox_send",
+			},
 		},
 	},
 
@@ -291,6 +305,32 @@ var knownMetricKinds = []*MetricKind{
 		},
 	},
 
+	{
+		Desc: prometheus.NewDesc(
+			fqName("publisher_outbox_depth"), "signed batches waiting in the publisher outbox",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealth: "publisher_outbox_depth",
+				ToProm:     "pending",
+			},
+		},
+	},
+
+	{
+		Desc: prometheus.NewDesc(
+			fqName("publisher_outbox_age_seconds"), "time elapsed since the oldest pending outbox batch was created",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealth: "publisher_outbox_oldest_pending_time",
+				ToProm:     "oldest_pending",
+			},
+		},
+	},
+
 	////////// active bots
 
 	{
@@ -310,6 +350,56 @@ var knownMetricKinds = []*MetricKind{
 		},
 	},
 
+	////////// bot queues
+
+	{
+		Desc: prometheus.NewDesc(
+			fqName("bot_tx_queue_size"), "requests in the bot tx queues",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealthPrefix: "sender_queue_tx_size_",
+			},
+		},
+	},
+
+	{
+		Desc: prometheus.NewDesc(
+			fqName("bot_block_queue_size"), "requests in the bot block queues",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealthPrefix: "sender_queue_block_size_",
+			},
+		},
+	},
+
+	{
+		Desc: prometheus.NewDesc(
+			fqName("bot_tx_queue_spilled"), "tx requests spilled to the disk",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealthPrefix: "sender_queue_tx_spilled_",
+			},
+		},
+	},
+
+	{
+		Desc: prometheus.NewDesc(
+			fqName("bot_block_queue_spilled"), "block requests spilled to the disk",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealthPrefix: "sender_queue_block_spilled_",
+			},
+		},
+	},
+
 	////////// inspector
 
 	{
@@ -79,6 +79,7 @@ func (pc *promCollector) Describe(ch chan<- *prometheus.Desc) {
 
 type HealthMetric struct {
 	MetricName string
+	ReportName string
 	Report     *health.Report
 }
 
@@ -97,6 +98,15 @@ func (metrics HealthMetrics) Get(name string) (*HealthMetric, bool) {
 	return nil, false
 }
 
+func (metrics HealthMetrics) WithPrefix(prefix string) (found HealthMetrics) {
+	for _, metric := range metrics {
+		if strings.HasPrefix(metric.MetricName, prefix) {
+			found = append(found, metric)
+		}
+	}
+	return
+}
+
 func (pc *promCollector) Collect(ch chan<- prometheus.Metric) {
 	var healthMetrics HealthMetrics
 	for _, report := range pc.serviceHealth.CheckServiceHealth() {
@@ -111,6 +121,7 @@ func (pc *promCollector) Collect(ch chan<- prometheus.Metric) {
 
 		healthMetrics = append(healthMetrics, &HealthMetric{
 			MetricName: toPrometheusName(parts[1]),
+			ReportName: parts[1],
 			Report:     report,
 		})
 	}
@@ -19,6 +19,11 @@ func (*testServiceHealth) CheckServiceHealth() (allReports health.Reports) {
 			Status:  health.StatusOK,
 			Details: "1420687622144",
 		},
+		{
+			Name:    "foo.service.bar.queue.zktoro-agent-1",
+			Status:  health.StatusInfo,
+			Details: "5",
+		},
 	}
 }
 
@@ -35,6 +40,17 @@ var testMetricKinds = []*MetricKind{
 			},
 		},
 	},
+	{
+		Desc: prometheus.NewDesc(
+			fqName("service_bar_queue"), "",
+			[]string{"name"}, nil,
+		),
+		Mappings: []*Mapping{
+			{
+				FromHealthPrefix: "bar_queue_",
+			},
+		},
+	},
 }
 
 func TestPrometheusCollector(t *testing.T) {
@@ -50,7 +66,7 @@ func TestPrometheusCollector(t *testing.T) {
 	close(descCh)
 	r.Len(descCh, 0)
 
-	metricCh := make(chan prometheus.Metric, 1)
+	metricCh := make(chan prometheus.Metric, 2)
 	collector.Collect(metricCh)
 	close(metricCh)
 
@@ -59,7 +75,7 @@ func TestPrometheusCollector(t *testing.T) {
 		allMetrics = append(allMetrics, metric)
 	}
 
-	r.Len(allMetrics, 1)
+	r.Len(allMetrics, 2)
 
 	var encodedMetric io_prometheus_client.Metric
 
@@ -69,6 +85,11 @@ func TestPrometheusCollector(t *testing.T) {
 	label := encodedMetric.GetLabel()[0]
 	r.Equal("name", label.GetName())
 	r.Equal("m1", label.GetValue())
+
+	queue := allMetrics[1]
+	r.NoError(queue.Write(&encodedMetric))
+	r.Equal(float64(5), *encodedMetric.Gauge.Value)
+	r.Equal("zktoro-agent-1", encodedMetric.GetLabel()[0].GetValue())
 }
 
 func TestStartPrometheusCollector(t *testing.T) {
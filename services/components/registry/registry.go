@@ -22,6 +22,15 @@ type BotRegistry interface {
 	health.Reporter
 }
 
+// ErrNotLocalMode is returned when a local mode feature is used with the public registry.
+var ErrNotLocalMode = errors.New("feature available only in local mode")
+
+// LocalBotImages changes the local mode bot images at runtime.
+type LocalBotImages interface {
+	AddBotImage(image string) error
+	RemoveBotImage(image string) error
+}
+
 // botRegistry retrieves the bot list changes so the node can stay in sync.
 type botRegistry struct {
 	cfg            config.Config
@@ -36,6 +45,8 @@ type botRegistry struct {
 	lastErr            health.ErrorTracker
 }
 
+var _ LocalBotImages = &botRegistry{}
+
 // New creates a new service.
 func New(cfg config.Config, scannerAddress common.Address) (BotRegistry, error) {
 	service := &botRegistry{
@@ -89,6 +100,24 @@ func (br *botRegistry) LoadAssignedBots() ([]config.AgentConfig, error) {
 	return br.botConfigs, nil
 }
 
+// AddBotImage adds a local mode bot image which is loaded next time.
+func (br *botRegistry) AddBotImage(image string) error {
+	localStore, ok := br.registryStore.(store.LocalBotImageStore)
+	if !ok {
+		return ErrNotLocalMode
+	}
+	return localStore.AddBotImage(image)
+}
+
+// RemoveBotImage removes a local mode bot image so that the bot is unassigned next time.
+func (br *botRegistry) RemoveBotImage(image string) error {
+	localStore, ok := br.registryStore.(store.LocalBotImageStore)
+	if !ok {
+		return ErrNotLocalMode
+	}
+	return localStore.RemoveBotImage(image)
+}
+
 // Name implements health.Reporter interface.
 func (br *botRegistry) Name() string {
 	return "bot-registry"
@@ -1,15 +1,15 @@
 package security
 
 import (
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"zktoro/zktoro-core-go/security"
 )
 
-func CreateBotJWT(key *keystore.Key, agentID string, claims map[string]interface{}, creator func(key *keystore.Key, claims map[string]interface{}) (string, error)) (string, error) {
+func CreateBotJWT(signer security.Signer, agentID string, claims map[string]interface{}, creator func(signer security.Signer, claims map[string]interface{}) (string, error)) (string, error) {
 	if claims == nil {
 		claims = make(map[string]interface{})
 	}
 
 	claims["bot-id"] = agentID
 
-	return creator(key, claims)
+	return creator(signer, claims)
 }
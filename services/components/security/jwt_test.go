@@ -4,7 +4,8 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"zktoro/zktoro-core-go/security"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,7 +14,7 @@ func TestCreateBotJWT(t *testing.T) {
 	testCases := []struct {
 		name        string
 		claims      map[string]interface{}
-		jwtFunc     func(key *keystore.Key, c map[string]interface{}) (string, error)
+		jwtFunc     func(signer security.Signer, c map[string]interface{}) (string, error)
 		expectedJWT string
 		expectedErr error
 	}{
@@ -22,7 +23,7 @@ func TestCreateBotJWT(t *testing.T) {
 			claims: map[string]interface{}{
 				"test": "value",
 			},
-			jwtFunc: func(key *keystore.Key, c map[string]interface{}) (string, error) {
+			jwtFunc: func(signer security.Signer, c map[string]interface{}) (string, error) {
 				assert.Equal(t, "value", c["test"])
 				assert.Equal(t, "botID", c["bot-id"])
 				return "jwt", nil
@@ -33,7 +34,7 @@ func TestCreateBotJWT(t *testing.T) {
 		{
 			name:   "default claims",
 			claims: nil,
-			jwtFunc: func(key *keystore.Key, c map[string]interface{}) (string, error) {
+			jwtFunc: func(signer security.Signer, c map[string]interface{}) (string, error) {
 				assert.Equal(t, "botID", c["bot-id"])
 				return "jwt", nil
 			},
@@ -43,7 +44,7 @@ func TestCreateBotJWT(t *testing.T) {
 		{
 			name:   "jwt creation error",
 			claims: nil,
-			jwtFunc: func(key *keystore.Key, c map[string]interface{}) (string, error) {
+			jwtFunc: func(signer security.Signer, c map[string]interface{}) (string, error) {
 				assert.Equal(t, "botID", c["bot-id"])
 				return "", testErr
 			},
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"zktoro/clients"
+	"zktoro/clients/messaging"
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ConfigReloader applies the reloadable config changes to a service.
+type ConfigReloader func(cfg config.Config, changes config.ConfigChanges) error
+
+// SubscribeToConfigChanges reloads the container config when the runner broadcasts a config change.
+// The log level is applied here and the rest of the changes are applied by the reloaders.
+func SubscribeToConfigChanges(msgClient clients.MessageClient, name string, reloaders ...ConfigReloader) {
+	logger := log.WithField("service", name)
+	msgClient.Subscribe(messaging.SubjectConfigChanged, messaging.ConfigChangedHandler(func(changes messaging.ConfigChangedPayload) error {
+		if len(changes.RestartRequired) > 0 {
+			logger.WithField("fields", changes.RestartRequired).Warn("config changes require a restart")
+		}
+		if len(changes.Reloadable) == 0 {
+			return nil
+		}
+
+		cfg, err := config.GetConfigForContainer()
+		if err != nil {
+			return err
+		}
+		if err := config.Validate(&cfg); err != nil {
+			return err
+		}
+		if changes.Reloaded("log.level") {
+			lvl, err := log.ParseLevel(cfg.Log.Level)
+			if err != nil {
+				return err
+			}
+			log.SetLevel(lvl)
+		}
+		for _, reload := range reloaders {
+			if err := reload(cfg, changes); err != nil {
+				return err
+			}
+		}
+		logger.WithField("fields", changes.Reloadable).Info("reloaded config")
+		return nil
+	}))
+}
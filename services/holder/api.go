@@ -0,0 +1,136 @@
+package holder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	errBadCredentialMessage   = "bad credential message body"
+	errBadPresentationMessage = "bad presentation request body"
+)
+
+// CredentialSummary describes a stored credential without its content.
+type CredentialSummary struct {
+	ID        string     `json:"id"`
+	Issuer    string     `json:"issuer"`
+	Types     []string   `json:"types"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	StoredAt  time.Time  `json:"storedAt"`
+}
+
+// PresentationResponse contains the signed presentation.
+type PresentationResponse struct {
+	VP string `json:"vp"`
+}
+
+// DIDResponse contains the holder DID.
+type DIDResponse struct {
+	DID string `json:"did"`
+}
+
+func summarize(cred *StoredCredential) *CredentialSummary {
+	return &CredentialSummary{
+		ID:        cred.ID,
+		Issuer:    cred.Issuer,
+		Types:     cred.Types,
+		ExpiresAt: cred.ExpiresAt,
+		StoredAt:  cred.StoredAt,
+	}
+}
+
+// Router creates the routes of the credential holder API.
+func (h *Holder) Router() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/did", h.handleGetDID).Methods(http.MethodGet)
+	r.HandleFunc("/putVC", h.handlePutVC).Methods(http.MethodPost, http.MethodPut)
+	r.HandleFunc("/credentials", h.handleListCredentials).Methods(http.MethodGet)
+	r.HandleFunc("/credentials", h.handleDeleteCredential).Methods(http.MethodDelete).Queries("id", "{id}")
+	r.HandleFunc("/signVP", h.handleSignVP).Methods(http.MethodPost)
+	return r
+}
+
+func (h *Holder) handleGetDID(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, &DIDResponse{DID: h.DID()})
+}
+
+func (h *Holder) handlePutVC(w http.ResponseWriter, req *http.Request) {
+	var msg CredentialRequest
+	if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, errBadCredentialMessage)
+		return
+	}
+	cred, err := h.StoreCredential(&msg)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, summarize(cred))
+}
+
+func (h *Holder) handleListCredentials(w http.ResponseWriter, req *http.Request) {
+	creds, err := h.Credentials()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summaries := make([]*CredentialSummary, 0, len(creds))
+	for _, cred := range creds {
+		summaries = append(summaries, summarize(cred))
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (h *Holder) handleDeleteCredential(w http.ResponseWriter, req *http.Request) {
+	if err := h.DeleteCredential(mux.Vars(req)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Holder) handleSignVP(w http.ResponseWriter, req *http.Request) {
+	var msg PresentationRequest
+	if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, errBadPresentationMessage)
+		return
+	}
+	vp, err := h.Present(&msg)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &PresentationResponse{VP: vp})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var reqErr *RequestError
+	switch {
+	case errors.Is(err, ErrCredentialNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.As(err, &reqErr):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		log.WithError(err).Error("credential holder error")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprint(w, "internal error")
+		return
+	}
+	_, _ = fmt.Fprint(w, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("failed to write credential holder response")
+	}
+}
@@ -0,0 +1,184 @@
+package holder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	log "github.com/sirupsen/logrus"
+)
+
+// CredentialRequest is the payload that an issuer sends to store a credential. The issuer key
+// is never taken from the request but from the trusted issuers.
+type CredentialRequest struct {
+	HolderDID string `json:"holder_did"`
+	VC        string `json:"vc"`
+}
+
+// RequestError is returned when the request content is not acceptable.
+type RequestError struct {
+	Err error
+}
+
+func (re *RequestError) Error() string {
+	return re.Err.Error()
+}
+
+func (re *RequestError) Unwrap() error {
+	return re.Err
+}
+
+func badRequest(err error) error {
+	return &RequestError{Err: err}
+}
+
+// Holder verifies, stores and presents the node's credentials.
+type Holder struct {
+	key      *HolderKey
+	store    CredentialStore
+	verifier *Verifier
+	issuers  *TrustedIssuers
+}
+
+// NewHolder creates a new holder which accepts the credentials of the trusted issuers.
+func NewHolder(key *HolderKey, store CredentialStore, verifier *Verifier, issuers *TrustedIssuers) *Holder {
+	return &Holder{
+		key:      key,
+		store:    store,
+		verifier: verifier,
+		issuers:  issuers,
+	}
+}
+
+// DID returns the holder DID.
+func (h *Holder) DID() string {
+	return h.key.DID
+}
+
+// StoreCredential verifies the credential and stores it if it is valid, issued by a trusted
+// issuer and issued to this holder.
+func (h *Holder) StoreCredential(req *CredentialRequest) (*StoredCredential, error) {
+	vc, err := h.parseTrustedCredential(req.VC)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	if vc.ID == "" {
+		return nil, badRequest(errors.New("credential has no id"))
+	}
+	subject, err := verifiable.SubjectID(vc.Subject)
+	if err != nil {
+		return nil, badRequest(fmt.Errorf("invalid credential subject: %v", err))
+	}
+	if subject != h.key.DID || (req.HolderDID != "" && req.HolderDID != h.key.DID) {
+		return nil, badRequest(ErrCredentialNotHolders)
+	}
+	if err := h.verifier.CheckValidity(vc, h.issuers.KeyFetcher()); err != nil {
+		return nil, badRequest(err)
+	}
+
+	cred := &StoredCredential{
+		ID:       vc.ID,
+		Issuer:   vc.Issuer.ID,
+		Subject:  subject,
+		Types:    vc.Types,
+		StoredAt: time.Now().UTC(),
+		Raw:      req.VC,
+	}
+	if vc.Expired != nil {
+		expiresAt := vc.Expired.Time.UTC()
+		cred.ExpiresAt = &expiresAt
+	}
+	if err := h.store.Put(cred); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{
+		"id":     cred.ID,
+		"issuer": cred.Issuer,
+	}).Info("stored credential")
+	return cred, nil
+}
+
+// Credentials lists the stored credentials.
+func (h *Holder) Credentials() ([]*StoredCredential, error) {
+	return h.store.List()
+}
+
+// DeleteCredential removes a stored credential.
+func (h *Holder) DeleteCredential(id string) error {
+	return h.store.Delete(id)
+}
+
+// Present signs a presentation of the requested credentials, or of all valid credentials
+// if no credential is specified.
+func (h *Holder) Present(req *PresentationRequest) (string, error) {
+	var (
+		creds []*StoredCredential
+		err   error
+	)
+	if len(req.CredentialIDs) == 0 {
+		creds, err = h.store.List()
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, id := range req.CredentialIDs {
+		cred, err := h.store.Get(id)
+		if err != nil {
+			return "", err
+		}
+		creds = append(creds, cred)
+	}
+
+	var vcs []*verifiable.Credential
+	for _, cred := range creds {
+		vc, err := h.loadValidCredential(cred)
+		if err != nil && len(req.CredentialIDs) > 0 {
+			return "", badRequest(fmt.Errorf("credential %s: %w", cred.ID, err))
+		}
+		if err != nil {
+			log.WithError(err).WithField("id", cred.ID).Warn("skipping invalid credential in presentation")
+			continue
+		}
+		vcs = append(vcs, vc)
+	}
+
+	vp, err := SignPresentation(h.key, req, vcs, h.verifier.now())
+	if errors.Is(err, ErrMissingNonce) || errors.Is(err, ErrNoCredentials) {
+		return "", badRequest(err)
+	}
+	return vp, err
+}
+
+// loadValidCredential parses the stored credential again so that the trust, the expiry and the
+// revocation are checked at presentation time.
+func (h *Holder) loadValidCredential(cred *StoredCredential) (*verifiable.Credential, error) {
+	vc, err := h.parseTrustedCredential(cred.Raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.verifier.CheckValidity(vc, h.issuers.KeyFetcher()); err != nil {
+		return nil, err
+	}
+	return vc, nil
+}
+
+// parseTrustedCredential parses the credential after making sure that the issuer is trusted, and
+// checks the proof with the key of the trusted issuer.
+func (h *Holder) parseTrustedCredential(raw string) (*verifiable.Credential, error) {
+	issuer, err := credentialIssuer([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.issuers.PublicKey(issuer); err != nil {
+		return nil, err
+	}
+	vc, err := h.verifier.ParseCredential([]byte(raw), h.issuers.KeyFetcher())
+	if err != nil {
+		return nil, err
+	}
+	if vc.Issuer.ID != issuer {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedIssuer, vc.Issuer.ID)
+	}
+	return vc, nil
+}
@@ -0,0 +1,322 @@
+package holder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	"github.com/stretchr/testify/require"
+)
+
+const testNonce = "0123456789abcdef"
+
+type testIssuer struct {
+	did        string
+	keyID      string
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+}
+
+func newTestIssuer(r *require.Assertions) *testIssuer {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	did, keyID := fingerprint.CreateDIDKey(pub)
+	return &testIssuer{did: did, keyID: keyID, publicKey: pub, privateKey: priv}
+}
+
+func (ti *testIssuer) publicKeyHex() string {
+	return hex.EncodeToString(ti.publicKey)
+}
+
+func (ti *testIssuer) sign(r *require.Assertions, vc *verifiable.Credential) string {
+	claims, err := vc.JWTClaims(false)
+	r.NoError(err)
+	jws, err := claims.MarshalJWS(verifiable.EdDSA, signature.GetEd25519Signer(ti.privateKey, ti.publicKey), ti.keyID)
+	r.NoError(err)
+	return jws
+}
+
+func (ti *testIssuer) issue(r *require.Assertions, id, subject string, expires time.Time, status *verifiable.TypedID) string {
+	issued := time.Now().Add(-time.Hour)
+	return ti.sign(r, &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      id,
+		Types:   []string{verifiable.VCType},
+		Issuer:  verifiable.Issuer{ID: ti.did},
+		Issued:  &util.TimeWrapper{Time: issued},
+		Expired: &util.TimeWrapper{Time: expires},
+		Subject: subject,
+		Status:  status,
+	})
+}
+
+func (ti *testIssuer) statusList(r *require.Assertions, revokedIndex int) string {
+	bitstring := make([]byte, 16)
+	bitstring[revokedIndex/8] |= 1 << (7 - revokedIndex%8)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(bitstring)
+	r.NoError(err)
+	r.NoError(zw.Close())
+
+	return ti.sign(r, &verifiable.Credential{
+		Context: []string{verifiable.ContextURI},
+		ID:      "urn:uuid:status-list",
+		Types:   []string{verifiable.VCType},
+		Issuer:  verifiable.Issuer{ID: ti.did},
+		Issued:  &util.TimeWrapper{Time: time.Now().Add(-time.Hour)},
+		Subject: []verifiable.Subject{{
+			ID: "urn:uuid:status-list#list",
+			CustomFields: verifiable.CustomFields{
+				"type":          "StatusList2021",
+				"statusPurpose": "revocation",
+				"encodedList":   base64.RawURLEncoding.EncodeToString(buf.Bytes()),
+			},
+		}},
+	})
+}
+
+func newTestHolder(r *require.Assertions, dir string, trusted ...*testIssuer) *Holder {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	issuerKeys := make(map[string]string)
+	for _, issuer := range trusted {
+		issuerKeys[issuer.did] = issuer.publicKeyHex()
+	}
+	issuers, err := NewTrustedIssuers(issuerKeys)
+	r.NoError(err)
+
+	loader := NewDocumentLoader()
+	statusChecker := NewStatusListChecker(loader, []string{"127.0.0.1"}, time.Second*5)
+	// the test status lists are served from the loopback address
+	statusChecker.allowPrivate = true
+	return NewHolder(
+		NewHolderKey(priv),
+		NewFileCredentialStore(path.Join(dir, "credentials.json")),
+		NewVerifier(loader, statusChecker),
+		issuers,
+	)
+}
+
+func TestHolder_StoreAndPresent(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	h := newTestHolder(r, t.TempDir(), issuer)
+
+	vc := issuer.issue(r, "urn:uuid:cred-1", h.DID(), time.Now().Add(time.Hour), nil)
+	cred, err := h.StoreCredential(&CredentialRequest{
+		HolderDID: h.DID(),
+		VC:        vc,
+	})
+	r.NoError(err)
+	r.Equal("urn:uuid:cred-1", cred.ID)
+	r.Equal(issuer.did, cred.Issuer)
+
+	creds, err := h.Credentials()
+	r.NoError(err)
+	r.Len(creds, 1)
+
+	vp, err := h.Present(&PresentationRequest{
+		Nonce:    testNonce,
+		Audience: []string{"did:example:verifier"},
+	})
+	r.NoError(err)
+
+	verifier, err := jwt.NewEd25519Verifier(h.key.PrivateKey.Public().(ed25519.PublicKey))
+	r.NoError(err)
+	token, _, err := jwt.Parse(vp, jwt.WithSignatureVerifier(verifier))
+	r.NoError(err)
+	kid, _ := token.Headers.KeyID()
+	r.Equal(h.key.KeyID, kid)
+
+	var claims presentationClaims
+	r.NoError(token.DecodeClaims(&claims))
+	r.Equal(testNonce, claims.Nonce)
+	r.Equal(h.DID(), claims.Issuer)
+	r.Equal([]string{"did:example:verifier"}, []string(claims.Audience))
+	r.NotNil(claims.Expiry)
+	r.Len(claims.Presentation.Credential, 1)
+}
+
+func TestHolder_StoreCredential_Rejected(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	other := newTestIssuer(r)
+	h := newTestHolder(r, t.TempDir(), issuer)
+
+	tests := []struct {
+		name string
+		req  *CredentialRequest
+		err  error
+	}{
+		{
+			name: "untrusted issuer",
+			req: &CredentialRequest{
+				VC: other.issue(r, "urn:uuid:cred-1", h.DID(), time.Now().Add(time.Hour), nil),
+			},
+			err: ErrUntrustedIssuer,
+		},
+		{
+			name: "expired",
+			req: &CredentialRequest{
+				VC: issuer.issue(r, "urn:uuid:cred-2", h.DID(), time.Now().Add(-time.Minute), nil),
+			},
+			err: ErrCredentialExpired,
+		},
+		{
+			name: "other holder",
+			req: &CredentialRequest{
+				VC: issuer.issue(r, "urn:uuid:cred-3", other.did, time.Now().Add(time.Hour), nil),
+			},
+			err: ErrCredentialNotHolders,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := h.StoreCredential(test.req)
+			require.ErrorIs(t, err, test.err)
+			var reqErr *RequestError
+			require.ErrorAs(t, err, &reqErr)
+		})
+	}
+
+	creds, err := h.Credentials()
+	r.NoError(err)
+	r.Empty(creds)
+}
+
+func TestHolder_StoreCredential_Revoked(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	h := newTestHolder(r, t.TempDir(), issuer)
+
+	const revokedIndex = 42
+	statusList := issuer.statusList(r, revokedIndex)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(w, statusList)
+	}))
+	defer server.Close()
+
+	status := func(index int) *verifiable.TypedID {
+		return &verifiable.TypedID{
+			ID:   fmt.Sprintf("%s#%d", server.URL, index),
+			Type: StatusTypeStatusList2021,
+			CustomFields: verifiable.CustomFields{
+				"statusPurpose":        "revocation",
+				"statusListIndex":      fmt.Sprint(index),
+				"statusListCredential": server.URL,
+			},
+		}
+	}
+
+	_, err := h.StoreCredential(&CredentialRequest{
+		VC: issuer.issue(r, "urn:uuid:revoked", h.DID(), time.Now().Add(time.Hour), status(revokedIndex)),
+	})
+	r.ErrorIs(err, ErrCredentialRevoked)
+
+	_, err = h.StoreCredential(&CredentialRequest{
+		VC: issuer.issue(r, "urn:uuid:valid", h.DID(), time.Now().Add(time.Hour), status(revokedIndex+1)),
+	})
+	r.NoError(err)
+}
+
+func TestHolder_Present_Errors(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	h := newTestHolder(r, t.TempDir(), issuer)
+
+	_, err := h.Present(&PresentationRequest{Nonce: testNonce})
+	r.ErrorIs(err, ErrNoCredentials)
+
+	_, err = h.StoreCredential(&CredentialRequest{
+		VC: issuer.issue(r, "urn:uuid:cred-1", h.DID(), time.Now().Add(time.Hour), nil),
+	})
+	r.NoError(err)
+
+	_, err = h.Present(&PresentationRequest{})
+	r.ErrorIs(err, ErrMissingNonce)
+
+	_, err = h.Present(&PresentationRequest{Nonce: testNonce, CredentialIDs: []string{"urn:uuid:unknown"}})
+	r.ErrorIs(err, ErrCredentialNotFound)
+
+	// expires after it was stored
+	h.verifier.now = func() time.Time { return time.Now().Add(time.Hour * 2) }
+	_, err = h.Present(&PresentationRequest{Nonce: testNonce, CredentialIDs: []string{"urn:uuid:cred-1"}})
+	r.ErrorIs(err, ErrCredentialExpired)
+	_, err = h.Present(&PresentationRequest{Nonce: testNonce})
+	r.ErrorIs(err, ErrNoCredentials)
+}
+
+func TestNewTrustedIssuers(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	other := newTestIssuer(r)
+
+	issuers, err := NewTrustedIssuers(map[string]string{issuer.did: ""})
+	r.NoError(err)
+	key, err := issuers.PublicKey(issuer.did)
+	r.NoError(err)
+	r.Equal(issuer.publicKey, key)
+	_, err = issuers.PublicKey(other.did)
+	r.ErrorIs(err, ErrUntrustedIssuer)
+
+	_, err = NewTrustedIssuers(map[string]string{issuer.did: other.publicKeyHex()})
+	r.ErrorIs(err, ErrIssuerKeyMismatch)
+	_, err = NewTrustedIssuers(map[string]string{"did:web:issuer.example.com": ""})
+	r.ErrorIs(err, ErrUnknownIssuerKey)
+}
+
+func TestStatusListChecker_Restrictions(t *testing.T) {
+	r := require.New(t)
+
+	issuer := newTestIssuer(r)
+	statusList := issuer.statusList(r, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(w, statusList)
+	}))
+	defer server.Close()
+
+	issuers, err := NewTrustedIssuers(map[string]string{issuer.did: ""})
+	r.NoError(err)
+	vc := &verifiable.Credential{
+		Issuer: verifiable.Issuer{ID: issuer.did},
+		Status: &verifiable.TypedID{
+			Type: StatusTypeStatusList2021,
+			CustomFields: verifiable.CustomFields{
+				"statusListIndex":      "1",
+				"statusListCredential": server.URL,
+			},
+		},
+	}
+	loader := NewDocumentLoader()
+
+	// no allowed host and not a did:web issuer
+	_, err = NewStatusListChecker(loader, nil, time.Second).IsRevoked(vc, issuers.KeyFetcher())
+	r.ErrorIs(err, ErrStatusListHostNotAllowed)
+
+	_, err = NewStatusListChecker(loader, []string{"issuer.example.com"}, time.Second).IsRevoked(vc, issuers.KeyFetcher())
+	r.ErrorIs(err, ErrStatusListHostNotAllowed)
+
+	_, err = NewStatusListChecker(loader, []string{"127.0.0.1"}, time.Second).IsRevoked(vc, issuers.KeyFetcher())
+	r.ErrorIs(err, ErrStatusListPrivateAddress)
+}
@@ -0,0 +1,83 @@
+package holder
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// ErrUntrustedIssuer is returned for the credentials of the issuers which are not trusted.
+var ErrUntrustedIssuer = errors.New("issuer is not trusted")
+
+// TrustedIssuers resolves the public keys of the issuers whose credentials are accepted.
+type TrustedIssuers struct {
+	keys map[string]ed25519.PublicKey
+}
+
+// NewTrustedIssuers creates the trusted issuers from the issuer DIDs and their hex encoded
+// Ed25519 public keys. The key of a did:key issuer is taken from the DID if it is empty.
+func NewTrustedIssuers(issuerKeys map[string]string) (*TrustedIssuers, error) {
+	ti := &TrustedIssuers{keys: make(map[string]ed25519.PublicKey)}
+	for did, keyHex := range issuerKeys {
+		key, err := decodeIssuerPublicKey(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("issuer %s: %v", did, err)
+		}
+
+		if strings.HasPrefix(did, didKeyPrefix) {
+			didKey, err := PublicKeyFromDIDKey(did)
+			if err != nil {
+				return nil, fmt.Errorf("issuer %s: %v", did, err)
+			}
+			if key != nil && !key.Equal(didKey) {
+				return nil, fmt.Errorf("issuer %s: %w", did, ErrIssuerKeyMismatch)
+			}
+			key = didKey
+		}
+		if key == nil {
+			return nil, fmt.Errorf("issuer %s: %w", did, ErrUnknownIssuerKey)
+		}
+		ti.keys[did] = key
+	}
+	return ti, nil
+}
+
+// PublicKey returns the public key of a trusted issuer.
+func (ti *TrustedIssuers) PublicKey(did string) (ed25519.PublicKey, error) {
+	key, ok := ti.keys[did]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedIssuer, did)
+	}
+	return key, nil
+}
+
+// KeyFetcher resolves the keys of the credential proofs from the trusted issuers.
+func (ti *TrustedIssuers) KeyFetcher() verifiable.PublicKeyFetcher {
+	return func(issuerID, keyID string) (*verifier.PublicKey, error) {
+		key, err := ti.PublicKey(issuerID)
+		if err != nil {
+			return nil, err
+		}
+		return &verifier.PublicKey{Type: kms.ED25519, Value: key}, nil
+	}
+}
+
+func decodeIssuerPublicKey(keyHex string) (ed25519.PublicKey, error) {
+	if keyHex == "" {
+		return nil, nil
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(keyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer public key: %v", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid issuer public key length: %d", len(b))
+	}
+	return b, nil
+}
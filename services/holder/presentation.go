@@ -0,0 +1,105 @@
+package holder
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	josejwt "github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jwt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util/signature"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+const (
+	defaultPresentationTTL = time.Minute * 5
+	minNonceLength         = 8
+)
+
+// Errors
+var (
+	ErrMissingNonce         = fmt.Errorf("nonce must be at least %d characters", minNonceLength)
+	ErrNoCredentials        = errors.New("no credentials to present")
+	ErrCredentialNotHolders = errors.New("credential is not issued to this holder")
+)
+
+// PresentationRequest is sent by a verifier to get a fresh presentation.
+type PresentationRequest struct {
+	Nonce         string   `json:"nonce"`
+	Audience      []string `json:"audience"`
+	CredentialIDs []string `json:"credentialIds"`
+}
+
+// HolderKey is the holder's signing key and its DID.
+type HolderKey struct {
+	DID        string
+	KeyID      string
+	PrivateKey ed25519.PrivateKey
+}
+
+// NewHolderKey creates the holder key identified with a did:key.
+func NewHolderKey(privateKey ed25519.PrivateKey) *HolderKey {
+	did, keyID := fingerprint.CreateDIDKey(privateKey.Public().(ed25519.PublicKey))
+	return &HolderKey{
+		DID:        did,
+		KeyID:      keyID,
+		PrivateKey: privateKey,
+	}
+}
+
+// presentationClaims extends the VP JWT claims with the verifier's nonce.
+type presentationClaims struct {
+	*verifiable.JWTPresClaims
+
+	Nonce string `json:"nonce"`
+}
+
+// SignPresentation creates a JWT VP which is bound to the nonce and the audience
+// of the request and expires shortly.
+func SignPresentation(key *HolderKey, req *PresentationRequest, creds []*verifiable.Credential, now time.Time) (string, error) {
+	if len(req.Nonce) < minNonceLength {
+		return "", ErrMissingNonce
+	}
+	if len(creds) == 0 {
+		return "", ErrNoCredentials
+	}
+	vp, err := verifiable.NewPresentation(verifiable.WithCredentials(creds...))
+	if err != nil {
+		return "", fmt.Errorf("failed to build presentation: %v", err)
+	}
+	vp.ID = fmt.Sprintf("urn:uuid:%s", uuid.NewString())
+	vp.Holder = key.DID
+
+	jwtClaims, err := vp.JWTClaims(req.Audience, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to create presentation claims: %v", err)
+	}
+	jwtClaims.IssuedAt = josejwt.NewNumericDate(now)
+	jwtClaims.NotBefore = josejwt.NewNumericDate(now)
+	jwtClaims.Expiry = josejwt.NewNumericDate(now.Add(defaultPresentationTTL))
+
+	signer := signature.GetEd25519Signer(key.PrivateKey, key.PrivateKey.Public().(ed25519.PublicKey))
+	claims := &presentationClaims{
+		JWTPresClaims: jwtClaims,
+		Nonce:         req.Nonce,
+	}
+	return signPresentationClaims(claims, signer, key.KeyID)
+}
+
+func signPresentationClaims(claims *presentationClaims, signer verifiable.Signer, keyID string) (string, error) {
+	algName, err := verifiable.EdDSA.Name()
+	if err != nil {
+		return "", err
+	}
+	token, err := jwt.NewSigned(claims, map[string]interface{}{
+		jose.HeaderKeyID: keyID,
+	}, verifiable.GetJWTSigner(signer, algName))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign presentation: %v", err)
+	}
+	return token.Serialize(false)
+}
@@ -0,0 +1,267 @@
+package holder
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/patrickmn/go-cache"
+	jsonld "github.com/piprate/json-gold/ld"
+)
+
+// Supported credential status types
+const (
+	StatusTypeStatusList2021     = "StatusList2021Entry"
+	StatusTypeRevocationList2020 = "RevocationList2020Status"
+)
+
+const (
+	statusListExpiry       = time.Minute * 5
+	maxStatusListBodySize  = 1 << 20
+	maxStatusListRedirects = 3
+	didWebPrefix           = "did:web:"
+)
+
+// Errors
+var (
+	ErrStatusListHostNotAllowed = errors.New("status list host is not allowed")
+	ErrStatusListPrivateAddress = errors.New("status list address is private")
+)
+
+// StatusChecker checks the revocation status of credentials.
+type StatusChecker interface {
+	IsRevoked(vc *verifiable.Credential, keyFetcher verifiable.PublicKeyFetcher) (bool, error)
+}
+
+type statusListChecker struct {
+	httpClient     *http.Client
+	documentLoader jsonld.DocumentLoader
+	listCache      *cache.Cache
+	allowedHosts   map[string]bool
+	allowPrivate   bool
+}
+
+// NewStatusListChecker creates a checker which supports the StatusList2021 and
+// RevocationList2020 status methods. The status lists are fetched only from the allowed hosts,
+// or from the domain of a did:web issuer if no host is allowed.
+func NewStatusListChecker(documentLoader jsonld.DocumentLoader, allowedHosts []string, timeout time.Duration) *statusListChecker {
+	slc := &statusListChecker{
+		documentLoader: documentLoader,
+		listCache:      cache.New(statusListExpiry, statusListExpiry),
+		allowedHosts:   make(map[string]bool),
+	}
+	for _, host := range allowedHosts {
+		slc.allowedHosts[strings.ToLower(host)] = true
+	}
+	dialer := &net.Dialer{Timeout: timeout, Control: slc.checkDialAddress}
+	slc.httpClient = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxStatusListRedirects {
+				return errors.New("too many status list redirects")
+			}
+			return slc.checkListURL(req.URL, via[0].URL.Hostname())
+		},
+	}
+	return slc
+}
+
+// checkDialAddress rejects the private addresses after the host is resolved so that a public
+// host name cannot point the checker at the node's network.
+func (slc *statusListChecker) checkDialAddress(network, address string, _ syscall.RawConn) error {
+	if slc.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrStatusListPrivateAddress, host)
+	}
+	return nil
+}
+
+// checkListURL makes sure that the status list is fetched over HTTP(S) from an allowed host.
+// The issuer host is the domain of a did:web issuer or the host of the first request.
+func (slc *statusListChecker) checkListURL(listURL *url.URL, issuerHost string) error {
+	if listURL.Scheme != "https" && listURL.Scheme != "http" {
+		return fmt.Errorf("unsupported status list scheme: %s", listURL.Scheme)
+	}
+	host := strings.ToLower(listURL.Hostname())
+	if len(slc.allowedHosts) > 0 {
+		if !slc.allowedHosts[host] {
+			return fmt.Errorf("%w: %s", ErrStatusListHostNotAllowed, host)
+		}
+		return nil
+	}
+	if issuerHost == "" || host != strings.ToLower(issuerHost) {
+		return fmt.Errorf("%w: %s", ErrStatusListHostNotAllowed, host)
+	}
+	return nil
+}
+
+// didWebHost returns the domain of a did:web DID.
+func didWebHost(did string) string {
+	if !strings.HasPrefix(did, didWebPrefix) {
+		return ""
+	}
+	domain := strings.SplitN(strings.TrimPrefix(did, didWebPrefix), ":", 2)[0]
+	domain, err := url.PathUnescape(domain)
+	if err != nil {
+		return ""
+	}
+	return strings.SplitN(domain, ":", 2)[0]
+}
+
+type statusEntry struct {
+	index   int
+	listURL string
+}
+
+func parseStatusEntry(status *verifiable.TypedID) (*statusEntry, error) {
+	var indexField, listField string
+	switch status.Type {
+	case StatusTypeStatusList2021:
+		indexField, listField = "statusListIndex", "statusListCredential"
+	case StatusTypeRevocationList2020:
+		indexField, listField = "revocationListIndex", "revocationListCredential"
+	default:
+		return nil, fmt.Errorf("unsupported credential status type: %s", status.Type)
+	}
+
+	var indexStr string
+	switch indexVal := status.CustomFields[indexField].(type) {
+	case string:
+		indexStr = indexVal
+	case float64:
+		indexStr = strconv.FormatFloat(indexVal, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("missing %s in credential status", indexField)
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil || index < 0 {
+		return nil, fmt.Errorf("invalid %s in credential status: %s", indexField, indexStr)
+	}
+	listURL, ok := status.CustomFields[listField].(string)
+	if !ok || listURL == "" {
+		return nil, fmt.Errorf("missing %s in credential status", listField)
+	}
+	return &statusEntry{index: index, listURL: listURL}, nil
+}
+
+func (slc *statusListChecker) IsRevoked(vc *verifiable.Credential, keyFetcher verifiable.PublicKeyFetcher) (bool, error) {
+	entry, err := parseStatusEntry(vc.Status)
+	if err != nil {
+		return false, err
+	}
+	bitstring, err := slc.getStatusList(entry.listURL, vc.Issuer.ID, keyFetcher)
+	if err != nil {
+		return false, err
+	}
+	if entry.index/8 >= len(bitstring) {
+		return false, fmt.Errorf("status list index out of range: %d", entry.index)
+	}
+	// the first index is the leftmost bit
+	return bitstring[entry.index/8]&(1<<(7-entry.index%8)) != 0, nil
+}
+
+func (slc *statusListChecker) getStatusList(listURL, issuer string, keyFetcher verifiable.PublicKeyFetcher) ([]byte, error) {
+	cached, ok := slc.listCache.Get(listURL)
+	if ok {
+		return cached.([]byte), nil
+	}
+
+	u, err := url.Parse(listURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status list url: %v", err)
+	}
+	if err := slc.checkListURL(u, didWebHost(issuer)); err != nil {
+		return nil, err
+	}
+	resp, err := slc.httpClient.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status list responded with '%d'", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxStatusListBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status list: %v", err)
+	}
+
+	listVC, err := verifiable.ParseCredential(
+		b,
+		verifiable.WithPublicKeyFetcher(keyFetcher),
+		verifiable.WithJSONLDDocumentLoader(slc.documentLoader),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status list credential: %w", err)
+	}
+	if listVC.Issuer.ID != issuer {
+		return nil, fmt.Errorf("status list issuer '%s' does not match credential issuer '%s'", listVC.Issuer.ID, issuer)
+	}
+
+	subject, ok := listVC.Subject.([]verifiable.Subject)
+	if !ok || len(subject) == 0 {
+		return nil, fmt.Errorf("invalid status list subject")
+	}
+	encodedList, ok := subject[0].CustomFields["encodedList"].(string)
+	if !ok {
+		return nil, fmt.Errorf("status list has no encoded list")
+	}
+	bitstring, err := decodeStatusList(encodedList)
+	if err != nil {
+		return nil, err
+	}
+
+	slc.listCache.Set(listURL, bitstring, 0)
+	return bitstring, nil
+}
+
+func decodeStatusList(encodedList string) ([]byte, error) {
+	var (
+		compressed []byte
+		err        error
+	)
+	for _, encoding := range []*base64.Encoding{
+		base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding,
+	} {
+		compressed, err = encoding.DecodeString(encodedList)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode status list: %v", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %v", err)
+	}
+	defer reader.Close()
+	bitstring, err := io.ReadAll(io.LimitReader(reader, maxStatusListBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %v", err)
+	}
+	return bitstring, nil
+}
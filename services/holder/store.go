@@ -0,0 +1,132 @@
+package holder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Errors
+var (
+	ErrCredentialNotFound = errors.New("credential not found")
+)
+
+// StoredCredential is a verified credential kept by the holder.
+type StoredCredential struct {
+	ID        string     `json:"id"`
+	Issuer    string     `json:"issuer"`
+	Subject   string     `json:"subject"`
+	Types     []string   `json:"types"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	StoredAt  time.Time  `json:"storedAt"`
+	Raw       string     `json:"raw"`
+}
+
+// CredentialStore stores credentials keyed by ID.
+type CredentialStore interface {
+	Put(cred *StoredCredential) error
+	Get(id string) (*StoredCredential, error)
+	List() ([]*StoredCredential, error)
+	Delete(id string) error
+}
+
+type fileCredentialStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCredentialStore creates a credential store which keeps all credentials in one JSON file.
+func NewFileCredentialStore(path string) *fileCredentialStore {
+	return &fileCredentialStore{path: path}
+}
+
+func (fcs *fileCredentialStore) load() (map[string]*StoredCredential, error) {
+	creds := make(map[string]*StoredCredential)
+	b, err := os.ReadFile(fcs.path)
+	if os.IsNotExist(err) {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %v", err)
+	}
+	if err := json.Unmarshal(b, &creds); err != nil {
+		return nil, fmt.Errorf("failed to decode credentials file: %v", err)
+	}
+	return creds, nil
+}
+
+func (fcs *fileCredentialStore) save(creds map[string]*StoredCredential) error {
+	b, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %v", err)
+	}
+	tmpPath := fcs.path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %v", err)
+	}
+	return os.Rename(tmpPath, fcs.path)
+}
+
+func (fcs *fileCredentialStore) Put(cred *StoredCredential) error {
+	fcs.mu.Lock()
+	defer fcs.mu.Unlock()
+
+	creds, err := fcs.load()
+	if err != nil {
+		return err
+	}
+	creds[cred.ID] = cred
+	return fcs.save(creds)
+}
+
+func (fcs *fileCredentialStore) Get(id string) (*StoredCredential, error) {
+	fcs.mu.Lock()
+	defer fcs.mu.Unlock()
+
+	creds, err := fcs.load()
+	if err != nil {
+		return nil, err
+	}
+	cred, ok := creds[id]
+	if !ok {
+		return nil, ErrCredentialNotFound
+	}
+	return cred, nil
+}
+
+func (fcs *fileCredentialStore) List() ([]*StoredCredential, error) {
+	fcs.mu.Lock()
+	defer fcs.mu.Unlock()
+
+	creds, err := fcs.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*StoredCredential, 0, len(creds))
+	for _, cred := range creds {
+		list = append(list, cred)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StoredAt.Before(list[j].StoredAt)
+	})
+	return list, nil
+}
+
+func (fcs *fileCredentialStore) Delete(id string) error {
+	fcs.mu.Lock()
+	defer fcs.mu.Unlock()
+
+	creds, err := fcs.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := creds[id]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(creds, id)
+	return fcs.save(creds)
+}
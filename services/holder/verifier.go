@@ -0,0 +1,162 @@
+package holder
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"zktoro/zktoro-core-go/utils/httpclient"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/ldcontext/embed"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	jsonld "github.com/piprate/json-gold/ld"
+	log "github.com/sirupsen/logrus"
+)
+
+const didKeyPrefix = "did:key:"
+
+// Errors
+var (
+	ErrCredentialExpired     = errors.New("credential is expired")
+	ErrCredentialNotYetValid = errors.New("credential is not valid yet")
+	ErrCredentialRevoked     = errors.New("credential is revoked")
+	ErrIssuerKeyMismatch     = errors.New("issuer public key does not match the issuer DID")
+	ErrUnknownIssuerKey      = errors.New("cannot resolve the issuer public key")
+)
+
+// Verifier verifies the credentials before they are stored and presented.
+type Verifier struct {
+	documentLoader jsonld.DocumentLoader
+	statusChecker  StatusChecker
+	now            func() time.Time
+}
+
+// NewVerifier creates a new verifier.
+func NewVerifier(documentLoader jsonld.DocumentLoader, statusChecker StatusChecker) *Verifier {
+	return &Verifier{
+		documentLoader: documentLoader,
+		statusChecker:  statusChecker,
+		now:            time.Now,
+	}
+}
+
+// NewDocumentLoader creates a JSON-LD document loader which has the well-known contexts
+// preloaded and fetches the rest.
+func NewDocumentLoader() jsonld.DocumentLoader {
+	loader := jsonld.NewCachingDocumentLoader(jsonld.NewDefaultDocumentLoader(httpclient.Default))
+	for _, ldContext := range embed.Contexts {
+		doc, err := jsonld.DocumentFromReader(bytes.NewReader(ldContext.Content))
+		if err != nil {
+			log.WithError(err).WithField("context", ldContext.URL).Warn("failed to preload json-ld context")
+			continue
+		}
+		loader.AddDocument(ldContext.URL, doc)
+	}
+	return loader
+}
+
+// ParseCredential parses the credential and checks the issuer's Ed25519 proof with the key
+// resolved by the key fetcher.
+func (v *Verifier) ParseCredential(raw []byte, keyFetcher verifiable.PublicKeyFetcher) (*verifiable.Credential, error) {
+	vc, err := verifiable.ParseCredential(
+		raw,
+		verifiable.WithPublicKeyFetcher(keyFetcher),
+		verifiable.WithJSONLDDocumentLoader(v.documentLoader),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+	if vc.JWT == "" && len(vc.Proofs) == 0 {
+		return nil, errors.New("invalid credential: no proof found")
+	}
+	return vc, nil
+}
+
+// CheckValidity checks the validity period and the revocation status of a parsed credential.
+func (v *Verifier) CheckValidity(vc *verifiable.Credential, keyFetcher verifiable.PublicKeyFetcher) error {
+	now := v.now()
+	if vc.Expired != nil && !vc.Expired.Time.After(now) {
+		return ErrCredentialExpired
+	}
+	if vc.Issued != nil && vc.Issued.Time.After(now) {
+		return ErrCredentialNotYetValid
+	}
+	if vc.Status == nil {
+		return nil
+	}
+	revoked, err := v.statusChecker.IsRevoked(vc, keyFetcher)
+	if err != nil {
+		return fmt.Errorf("failed to check credential status: %w", err)
+	}
+	if revoked {
+		return ErrCredentialRevoked
+	}
+	return nil
+}
+
+// PublicKeyFromDIDKey extracts the Ed25519 public key from a did:key DID.
+func PublicKeyFromDIDKey(did string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(did, didKeyPrefix) {
+		return nil, fmt.Errorf("not a did:key: %s", did)
+	}
+	methodID := strings.SplitN(strings.TrimPrefix(did, didKeyPrefix), "#", 2)[0]
+	pubKey, code, err := fingerprint.PubKeyFromFingerprint(methodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode did:key: %v", err)
+	}
+	if code != fingerprint.ED25519PubKeyMultiCodec {
+		return nil, fmt.Errorf("did:key is not an ed25519 key: 0x%x", code)
+	}
+	return pubKey, nil
+}
+
+// credentialIssuer reads the issuer of a JSON-LD or a JWT credential without verifying it, so
+// that the untrusted issuers are rejected before the credential is processed.
+func credentialIssuer(raw []byte) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if !bytes.HasPrefix(raw, []byte("{")) {
+		parts := bytes.Split(raw, []byte("."))
+		if len(parts) != 3 {
+			return "", errors.New("invalid credential: not a JSON or a JWT credential")
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(string(parts[1]))
+		if err != nil {
+			return "", fmt.Errorf("invalid credential: %v", err)
+		}
+		var claims struct {
+			Issuer string          `json:"iss"`
+			VC     json.RawMessage `json:"vc"`
+		}
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			return "", fmt.Errorf("invalid credential: %v", err)
+		}
+		if claims.Issuer != "" {
+			return claims.Issuer, nil
+		}
+		raw = claims.VC
+	}
+
+	var vc struct {
+		Issuer json.RawMessage `json:"issuer"`
+	}
+	if err := json.Unmarshal(raw, &vc); err != nil {
+		return "", fmt.Errorf("invalid credential: %v", err)
+	}
+	var issuer string
+	if err := json.Unmarshal(vc.Issuer, &issuer); err == nil && issuer != "" {
+		return issuer, nil
+	}
+	var issuerObj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(vc.Issuer, &issuerObj); err == nil && issuerObj.ID != "" {
+		return issuerObj.ID, nil
+	}
+	return "", errors.New("invalid credential: missing issuer")
+}
@@ -0,0 +1,537 @@
+package json_rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	maxRequestBodySize = 10 << 20
+	headCheckInterval  = 10 * time.Second
+)
+
+var errBadUpstreamResponse = errors.New("bad upstream response")
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// cacheStats counts the cache hits and misses of a bot request.
+type cacheStats struct {
+	hits   int
+	misses int
+}
+
+type cacheStatsKey struct{}
+
+func withCacheStats(ctx context.Context, stats *cacheStats) context.Context {
+	return context.WithValue(ctx, cacheStatsKey{}, stats)
+}
+
+func getCacheStats(ctx context.Context) *cacheStats {
+	stats, ok := ctx.Value(cacheStatsKey{}).(*cacheStats)
+	if !ok {
+		return &cacheStats{}
+	}
+	return stats
+}
+
+// responseCache serves the requests which cannot change anymore from memory so that the bots
+// do not fetch the same data from the JSON-RPC API over and over again. The blocks can be replaced
+// by a reorg until they are deep enough, so the results which refer to the blocks by number are
+// cached only after they have the minimum number of confirmations.
+type responseCache struct {
+	url              string
+	headers          map[string]string
+	client           *http.Client
+	minConfirmations uint64
+
+	// safeHeight is the highest block number which has the minimum confirmations
+	safeHeight atomic.Uint64
+
+	cache *lru.SizeConstrainedCache[string, json.RawMessage]
+	group singleflight.Group
+}
+
+func newResponseCache(
+	url string, headers map[string]string, client *http.Client, maxSize uint64, minConfirmations uint64,
+) *responseCache {
+	return &responseCache{
+		url:              url,
+		headers:          headers,
+		client:           client,
+		minConfirmations: minConfirmations,
+		cache:            lru.NewSizeConstrainedCache[string, json.RawMessage](maxSize),
+	}
+}
+
+// TrackHead updates the safe height with the latest block number periodically. The results which
+// refer to the blocks by number are not cached until the head is known.
+func (rc *responseCache) TrackHead(ctx context.Context) {
+	ticker := time.NewTicker(headCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := rc.updateHead(ctx); err != nil {
+			log.WithError(err).Warn("failed to get the latest block number for the cache")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rc *responseCache) updateHead(ctx context.Context) error {
+	respBody, err := rc.fetch(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`))
+	if err != nil {
+		return err
+	}
+	var (
+		resp rpcResponse
+		head hexutil.Uint64
+	)
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("%w: %v", errBadUpstreamResponse, err)
+	}
+	if len(resp.Error) > 0 {
+		return fmt.Errorf("%w: %s", errBadUpstreamResponse, string(resp.Error))
+	}
+	if err := json.Unmarshal(resp.Result, &head); err != nil {
+		return fmt.Errorf("%w: %v", errBadUpstreamResponse, err)
+	}
+	rc.setHead(uint64(head))
+	return nil
+}
+
+func (rc *responseCache) setHead(head uint64) {
+	if head < rc.minConfirmations {
+		rc.safeHeight.Store(0)
+		return
+	}
+	rc.safeHeight.Store(head - rc.minConfirmations)
+}
+
+// isConfirmed tells if the block is deep enough so that it cannot be replaced by a reorg.
+func (rc *responseCache) isConfirmed(blockNumber uint64) bool {
+	safeHeight := rc.safeHeight.Load()
+	return safeHeight > 0 && blockNumber <= safeHeight
+}
+
+// Handler serves the requests from the cache if they are cacheable and passes the rest to the next handler.
+func (rc *responseCache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(w, req)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestBodySize))
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		reqs, isBatch, ok := parseRequests(body)
+		if !ok || !hasCacheable(reqs) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		var resps []*rpcResponse
+		if isBatch {
+			resps, err = rc.serveBatch(req.Context(), reqs)
+		} else {
+			var resp *rpcResponse
+			resp, err = rc.serveSingle(req.Context(), reqs[0])
+			resps = []*rpcResponse{resp}
+		}
+		if err != nil {
+			log.WithError(err).Warn("failed to serve the request through the cache")
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		var out interface{} = resps[0]
+		if isBatch {
+			out = resps
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			log.WithError(err).Error("failed to write jsonrpc response body")
+		}
+	})
+}
+
+// serveSingle serves a cacheable request from the cache and coalesces the identical requests on misses.
+func (rc *responseCache) serveSingle(ctx context.Context, req *rpcRequest) (*rpcResponse, error) {
+	stats := getCacheStats(ctx)
+	key, _ := cacheKey(req)
+	if result, ok := rc.cache.Get(key); ok {
+		stats.hits++
+		return newResultResponse(req.ID, result), nil
+	}
+	stats.misses++
+
+	v, err, _ := rc.group.Do(key, func() (interface{}, error) {
+		upstreamReq := *req
+		upstreamReq.ID = json.RawMessage("1")
+		b, err := json.Marshal(&upstreamReq)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := rc.fetch(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		var resp rpcResponse
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return nil, fmt.Errorf("%w: %v", errBadUpstreamResponse, err)
+		}
+		rc.store(key, req, &resp)
+		return &resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := *v.(*rpcResponse)
+	resp.ID = req.ID
+	return &resp, nil
+}
+
+// serveBatch serves the cached requests of a batch from the cache and sends the rest to the API as a batch.
+func (rc *responseCache) serveBatch(ctx context.Context, reqs []*rpcRequest) ([]*rpcResponse, error) {
+	stats := getCacheStats(ctx)
+	resps := make([]*rpcResponse, len(reqs))
+	var (
+		upstreamReqs []*rpcRequest
+		missIndexes  []int
+	)
+	for i, req := range reqs {
+		key, cacheable := cacheKey(req)
+		if cacheable {
+			if result, ok := rc.cache.Get(key); ok {
+				stats.hits++
+				resps[i] = newResultResponse(req.ID, result)
+				continue
+			}
+			stats.misses++
+		}
+		// use the indexes as the ids so that the responses can be matched even if the ids repeat
+		upstreamReq := *req
+		upstreamReq.ID = json.RawMessage(fmt.Sprintf("%d", len(upstreamReqs)))
+		upstreamReqs = append(upstreamReqs, &upstreamReq)
+		missIndexes = append(missIndexes, i)
+	}
+	if len(upstreamReqs) == 0 {
+		return resps, nil
+	}
+
+	b, err := json.Marshal(upstreamReqs)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := rc.fetch(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	var upstreamResps []*rpcResponse
+	if err := json.Unmarshal(respBody, &upstreamResps); err != nil {
+		return nil, fmt.Errorf("%w: %v", errBadUpstreamResponse, err)
+	}
+	for _, resp := range upstreamResps {
+		var j int
+		if err := json.Unmarshal(resp.ID, &j); err != nil || j < 0 || j >= len(upstreamReqs) {
+			return nil, fmt.Errorf("%w: unknown id %s", errBadUpstreamResponse, string(resp.ID))
+		}
+		i := missIndexes[j]
+		if key, cacheable := cacheKey(reqs[i]); cacheable {
+			rc.store(key, reqs[i], resp)
+		}
+		resp.ID = reqs[i].ID
+		resps[i] = resp
+	}
+	for _, resp := range resps {
+		if resp == nil {
+			return nil, fmt.Errorf("%w: missing responses", errBadUpstreamResponse)
+		}
+	}
+	return resps, nil
+}
+
+// store caches the successful responses which have a final result.
+func (rc *responseCache) store(key string, req *rpcRequest, resp *rpcResponse) {
+	if len(resp.Error) > 0 || isNullResult(resp.Result) {
+		return
+	}
+	blockNumber, byNumber := requestBlockNumber(req)
+	if txBlockMethods[req.Method] {
+		blockNumber, byNumber = resultBlockNumber(req.Method, resp.Result)
+		if !byNumber {
+			return
+		}
+	}
+	if byNumber && !rc.isConfirmed(blockNumber) {
+		return
+	}
+	rc.cache.Add(key, resp.Result)
+}
+
+func (rc *responseCache) fetch(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rc.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for h, v := range rc.headers {
+		req.Header.Set(h, v)
+	}
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", errBadUpstreamResponse, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func newResultResponse(id, result json.RawMessage) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func isNullResult(result json.RawMessage) bool {
+	return len(result) == 0 || string(result) == "null"
+}
+
+// resultBlockNumber returns the number of the block which includes the transaction of the result. The
+// pending transactions do not have a block number.
+func resultBlockNumber(method string, result json.RawMessage) (uint64, bool) {
+	if method == "trace_transaction" {
+		var traces []struct {
+			BlockNumber *uint64 `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(result, &traces); err != nil || len(traces) == 0 || traces[0].BlockNumber == nil {
+			return 0, false
+		}
+		return *traces[0].BlockNumber, true
+	}
+	var tx struct {
+		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(result, &tx); err != nil || tx.BlockNumber == nil {
+		return 0, false
+	}
+	return uint64(*tx.BlockNumber), true
+}
+
+// parseRequests parses a single request or a batch of requests.
+func parseRequests(body []byte) (reqs []*rpcRequest, isBatch bool, ok bool) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, false, false
+	}
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &reqs); err != nil || len(reqs) == 0 {
+			return nil, false, false
+		}
+		for _, req := range reqs {
+			if req == nil {
+				return nil, false, false
+			}
+		}
+		return reqs, true, true
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false, false
+	}
+	return []*rpcRequest{&req}, false, true
+}
+
+func hasCacheable(reqs []*rpcRequest) bool {
+	for _, req := range reqs {
+		if _, ok := cacheKey(req); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// blockParamIndexes are the indexes of the block parameters of the methods which return
+// the same result for the same concrete block.
+var blockParamIndexes = map[string]int{
+	"eth_getBlockByNumber":                    0,
+	"eth_getBlockTransactionCountByNumber":    0,
+	"eth_getTransactionByBlockNumberAndIndex": 0,
+	"eth_getUncleCountByBlockNumber":          0,
+	"eth_call":                                1,
+	"eth_getBalance":                          1,
+	"eth_getCode":                             1,
+	"eth_getTransactionCount":                 1,
+	"eth_getStorageAt":                        2,
+	"trace_block":                             0,
+}
+
+// hashKeyedMethods return the same result once they return one.
+var hashKeyedMethods = map[string]bool{
+	"eth_chainId":                           true,
+	"eth_getBlockByHash":                    true,
+	"eth_getBlockTransactionCountByHash":    true,
+	"eth_getTransactionByHash":              true,
+	"eth_getTransactionByBlockHashAndIndex": true,
+	"eth_getTransactionReceipt":             true,
+	"trace_transaction":                     true,
+}
+
+// txBlockMethods return null or the pending view of a transaction before it is mined and can move the
+// transaction to another block after a reorg, so their results are cached only after the block of the
+// transaction is confirmed.
+var txBlockMethods = map[string]bool{
+	"eth_getTransactionByHash":  true,
+	"eth_getTransactionReceipt": true,
+	"trace_transaction":         true,
+}
+
+// cacheKey returns the key of the request if its result cannot change. The requests which
+// refer to a tag like "latest" are never cached.
+func cacheKey(req *rpcRequest) (string, bool) {
+	var params []json.RawMessage
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return "", false
+		}
+	}
+
+	switch {
+	case hashKeyedMethods[req.Method]:
+
+	case req.Method == "eth_getLogs":
+		if len(params) != 1 || !isConcreteLogFilter(params[0]) {
+			return "", false
+		}
+
+	default:
+		i, ok := blockParamIndexes[req.Method]
+		if !ok || i >= len(params) || !isConcreteBlock(params[i]) {
+			return "", false
+		}
+	}
+
+	var key bytes.Buffer
+	key.WriteString(req.Method)
+	for _, param := range params {
+		key.WriteByte('|')
+		if err := json.Compact(&key, param); err != nil {
+			return "", false
+		}
+	}
+	return strings.ToLower(key.String()), true
+}
+
+// requestBlockNumber returns the highest block number which the request refers to if it refers to
+// a block by number. The logs of a range are final only when the last block of the range is.
+func requestBlockNumber(req *rpcRequest) (uint64, bool) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return 0, false
+	}
+	if req.Method == "eth_getLogs" {
+		if len(params) != 1 {
+			return 0, false
+		}
+		var filter struct {
+			BlockHash *string         `json:"blockHash"`
+			ToBlock   json.RawMessage `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params[0], &filter); err != nil || filter.BlockHash != nil {
+			return 0, false
+		}
+		return blockNumberParam(filter.ToBlock)
+	}
+	i, ok := blockParamIndexes[req.Method]
+	if !ok || i >= len(params) {
+		return 0, false
+	}
+	return blockNumberParam(params[i])
+}
+
+// blockNumberParam returns the number of the block parameter if it is a number or an EIP-1898 object
+// with a number. The hashes refer to the same block after a reorg.
+func blockNumberParam(param json.RawMessage) (uint64, bool) {
+	var blockNumOrHash string
+	if err := json.Unmarshal(param, &blockNumOrHash); err != nil {
+		var blockObj struct {
+			BlockHash   string `json:"blockHash"`
+			BlockNumber string `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(param, &blockObj); err != nil || len(blockObj.BlockHash) > 0 {
+			return 0, false
+		}
+		blockNumOrHash = blockObj.BlockNumber
+	}
+	// the hashes are too long to be numbers
+	n, err := hexutil.DecodeUint64(blockNumOrHash)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// isConcreteBlock tells if the block parameter is a number, a hash or an EIP-1898 object instead of a tag.
+func isConcreteBlock(param json.RawMessage) bool {
+	var blockNumOrHash string
+	if err := json.Unmarshal(param, &blockNumOrHash); err == nil {
+		return strings.HasPrefix(blockNumOrHash, "0x")
+	}
+	var blockObj struct {
+		BlockHash   string `json:"blockHash"`
+		BlockNumber string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(param, &blockObj); err != nil {
+		return false
+	}
+	return strings.HasPrefix(blockObj.BlockHash, "0x") || strings.HasPrefix(blockObj.BlockNumber, "0x")
+}
+
+func isConcreteLogFilter(param json.RawMessage) bool {
+	var filter struct {
+		BlockHash *string         `json:"blockHash"`
+		FromBlock json.RawMessage `json:"fromBlock"`
+		ToBlock   json.RawMessage `json:"toBlock"`
+	}
+	if err := json.Unmarshal(param, &filter); err != nil {
+		return false
+	}
+	if filter.BlockHash != nil {
+		return true
+	}
+	return len(filter.FromBlock) > 0 && len(filter.ToBlock) > 0 &&
+		isConcreteBlock(filter.FromBlock) && isConcreteBlock(filter.ToBlock)
+}
@@ -0,0 +1,276 @@
+package json_rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUpstream echoes the method of each request as the result unless there is a result for the method.
+type testUpstream struct {
+	requests atomic.Int64
+	release  chan struct{}
+	results  map[string]string
+}
+
+func (up *testUpstream) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	up.requests.Add(1)
+	if up.release != nil {
+		<-up.release
+	}
+	body, _ := io.ReadAll(req.Body)
+	reqs, isBatch, _ := parseRequests(body)
+	var resps []*rpcResponse
+	for _, rpcReq := range reqs {
+		result, _ := json.Marshal(rpcReq.Method)
+		if methodResult, ok := up.results[rpcReq.Method]; ok {
+			result = json.RawMessage(methodResult)
+		}
+		resps = append(resps, &rpcResponse{JSONRPC: "2.0", ID: rpcReq.ID, Result: result})
+	}
+	if isBatch {
+		// the responses of a batch can be in any order
+		for i, j := 0, len(resps)-1; i < j; i, j = i+1, j-1 {
+			resps[i], resps[j] = resps[j], resps[i]
+		}
+		json.NewEncoder(w).Encode(resps)
+		return
+	}
+	json.NewEncoder(w).Encode(resps[0])
+}
+
+var passThrough = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	w.Write([]byte("passed"))
+})
+
+const testMinConfirmations = 10
+
+func initTestCache(t *testing.T) (*testUpstream, http.Handler) {
+	up, rc := initTestResponseCache(t)
+	// the test blocks are confirmed unless a test moves the head
+	rc.setHead(0x1000)
+	return up, rc.Handler(passThrough)
+}
+
+func initTestResponseCache(t *testing.T) (*testUpstream, *responseCache) {
+	up := &testUpstream{}
+	server := httptest.NewServer(up)
+	t.Cleanup(server.Close)
+	return up, newResponseCache(server.URL, nil, server.Client(), 1<<20, testMinConfirmations)
+}
+
+func sendTestRequest(h http.Handler, body string) (string, *cacheStats) {
+	var stats cacheStats
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	req = req.WithContext(withCacheStats(context.Background(), &stats))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	return recorder.Body.String(), &stats
+}
+
+func TestResponseCache_Single(t *testing.T) {
+	r := require.New(t)
+
+	up, h := initTestCache(t)
+	req := `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x1",true]}`
+
+	resp, stats := sendTestRequest(h, req)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":"eth_getBlockByNumber"}`, resp)
+	r.Equal(cacheStats{misses: 1}, *stats)
+
+	resp, stats = sendTestRequest(h, `{"jsonrpc":"2.0","id":"abc","method":"eth_getBlockByNumber","params":["0x1", true]}`)
+	r.JSONEq(`{"jsonrpc":"2.0","id":"abc","result":"eth_getBlockByNumber"}`, resp)
+	r.Equal(cacheStats{hits: 1}, *stats)
+	r.EqualValues(1, up.requests.Load())
+
+	// the tags are not cached
+	resp, _ = sendTestRequest(h, `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["latest",true]}`)
+	r.Equal("passed", resp)
+	resp, _ = sendTestRequest(h, `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	r.Equal("passed", resp)
+	r.EqualValues(1, up.requests.Load())
+}
+
+func TestResponseCache_Batch(t *testing.T) {
+	r := require.New(t)
+
+	up, h := initTestCache(t)
+	up.results = map[string]string{"eth_getTransactionReceipt": `{"blockHash":"0x1","blockNumber":"0x1"}`}
+	_, _ = sendTestRequest(h, `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0x1"},"0x10"]}`)
+
+	resp, stats := sendTestRequest(h, `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0x1"},"0x10"]},
+		{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]},
+		{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionReceipt","params":["0xabc"]}
+	]`)
+	r.JSONEq(`[
+		{"jsonrpc":"2.0","id":1,"result":"eth_call"},
+		{"jsonrpc":"2.0","id":1,"result":"eth_blockNumber"},
+		{"jsonrpc":"2.0","id":2,"result":{"blockHash":"0x1","blockNumber":"0x1"}}
+	]`, resp)
+	r.Equal(cacheStats{hits: 1, misses: 1}, *stats)
+	r.EqualValues(2, up.requests.Load())
+
+	_, stats = sendTestRequest(h, `[{"jsonrpc":"2.0","id":3,"method":"eth_getTransactionReceipt","params":["0xABC"]}]`)
+	r.Equal(cacheStats{hits: 1}, *stats)
+	r.EqualValues(2, up.requests.Load())
+}
+
+func TestResponseCache_PendingTx(t *testing.T) {
+	r := require.New(t)
+
+	up, h := initTestCache(t)
+	up.results = map[string]string{
+		"eth_getTransactionByHash":  `{"hash":"0xabc","blockHash":null,"blockNumber":null}`,
+		"eth_getTransactionReceipt": "null",
+	}
+	txReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0xabc"]}`
+	receiptReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0xabc"]}`
+
+	// the pending tx is not cached
+	resp, _ := sendTestRequest(h, txReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0xabc","blockHash":null,"blockNumber":null}}`, resp)
+	_, _ = sendTestRequest(h, receiptReq)
+	r.EqualValues(2, up.requests.Load())
+
+	// the mined tx is cached
+	up.results["eth_getTransactionByHash"] = `{"hash":"0xabc","blockHash":"0x1","blockNumber":"0x1"}`
+	up.results["eth_getTransactionReceipt"] = `{"transactionHash":"0xabc","blockHash":"0x1","blockNumber":"0x1"}`
+	resp, stats := sendTestRequest(h, txReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0xabc","blockHash":"0x1","blockNumber":"0x1"}}`, resp)
+	r.Equal(cacheStats{misses: 1}, *stats)
+	_, stats = sendTestRequest(h, txReq)
+	r.Equal(cacheStats{hits: 1}, *stats)
+	_, _ = sendTestRequest(h, receiptReq)
+	_, stats = sendTestRequest(h, receiptReq)
+	r.Equal(cacheStats{hits: 1}, *stats)
+	r.EqualValues(4, up.requests.Load())
+}
+
+func TestResponseCache_Reorg(t *testing.T) {
+	r := require.New(t)
+
+	up, rc := initTestResponseCache(t)
+	h := rc.Handler(passThrough)
+	blockReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x100",false]}`
+	logsReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0xff","toBlock":"0x100"}]}`
+	receiptReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0xabc"]}`
+
+	// nothing is cached by number before the head is known
+	up.results = map[string]string{"eth_getBlockByNumber": `{"hash":"0xa"}`}
+	resp, _ := sendTestRequest(h, blockReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0xa"}}`, resp)
+
+	// the block and the logs and the receipts in it are not cached while the block can be reorged
+	rc.setHead(0x100 + testMinConfirmations - 1)
+	up.results = map[string]string{
+		"eth_getBlockByNumber":      `{"hash":"0xb"}`,
+		"eth_getLogs":               `[{"blockHash":"0xb"}]`,
+		"eth_getTransactionReceipt": `{"blockHash":"0xb","blockNumber":"0x100"}`,
+	}
+	for _, req := range []string{blockReq, logsReq, receiptReq} {
+		_, stats := sendTestRequest(h, req)
+		r.Equal(cacheStats{misses: 1}, *stats)
+	}
+
+	// the reorged block is served from the upstream
+	up.results = map[string]string{
+		"eth_getBlockByNumber":      `{"hash":"0xc"}`,
+		"eth_getLogs":               `[{"blockHash":"0xc"}]`,
+		"eth_getTransactionReceipt": `{"blockHash":"0xc","blockNumber":"0x100"}`,
+	}
+	resp, stats := sendTestRequest(h, blockReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0xc"}}`, resp)
+	r.Equal(cacheStats{misses: 1}, *stats)
+	resp, _ = sendTestRequest(h, logsReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":[{"blockHash":"0xc"}]}`, resp)
+	resp, _ = sendTestRequest(h, receiptReq)
+	r.JSONEq(`{"jsonrpc":"2.0","id":1,"result":{"blockHash":"0xc","blockNumber":"0x100"}}`, resp)
+
+	// and cached once it is confirmed
+	rc.setHead(0x100 + testMinConfirmations)
+	for _, req := range []string{blockReq, logsReq, receiptReq} {
+		_, _ = sendTestRequest(h, req)
+		_, stats := sendTestRequest(h, req)
+		r.Equal(cacheStats{hits: 1}, *stats)
+	}
+	r.EqualValues(10, up.requests.Load())
+
+	// the logs of a range past the head are never cached
+	futureLogsReq := `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x100","toBlock":"0x1000"}]}`
+	_, _ = sendTestRequest(h, futureLogsReq)
+	_, stats = sendTestRequest(h, futureLogsReq)
+	r.Equal(cacheStats{misses: 1}, *stats)
+}
+
+func TestResponseCache_UpdateHead(t *testing.T) {
+	r := require.New(t)
+
+	up, rc := initTestResponseCache(t)
+	up.results = map[string]string{"eth_blockNumber": `"0x100"`}
+	r.NoError(rc.updateHead(context.Background()))
+	r.EqualValues(0x100-testMinConfirmations, rc.safeHeight.Load())
+
+	up.results = map[string]string{"eth_blockNumber": `"0x1"`}
+	r.NoError(rc.updateHead(context.Background()))
+	r.EqualValues(0, rc.safeHeight.Load())
+	r.False(rc.isConfirmed(0))
+}
+
+func TestResponseCache_Coalesce(t *testing.T) {
+	r := require.New(t)
+
+	up, h := initTestCache(t)
+	up.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _ := sendTestRequest(h, `{"jsonrpc":"2.0","id":1,"method":"trace_block","params":["0x1"]}`)
+			assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":"trace_block"}`, resp)
+		}()
+	}
+	// let the requests reach the cache before releasing the first one
+	time.Sleep(100 * time.Millisecond)
+	close(up.release)
+	wg.Wait()
+	r.EqualValues(1, up.requests.Load())
+}
+
+func TestCacheKey(t *testing.T) {
+	r := require.New(t)
+
+	for _, tc := range []struct {
+		req       string
+		cacheable bool
+	}{
+		{`{"method":"eth_getBlockByNumber","params":["0x1",false]}`, true},
+		{`{"method":"eth_getBlockByNumber","params":["finalized",false]}`, false},
+		{`{"method":"eth_getBlockByHash","params":["0xabc",false]}`, true},
+		{`{"method":"eth_call","params":[{"to":"0x1"},"latest"]}`, false},
+		{`{"method":"eth_call","params":[{"to":"0x1"}]}`, false},
+		{`{"method":"eth_call","params":[{"to":"0x1"},{"blockHash":"0xabc"}]}`, true},
+		{`{"method":"eth_getStorageAt","params":["0x1","0x0","0x10"]}`, true},
+		{`{"method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2"}]}`, true},
+		{`{"method":"eth_getLogs","params":[{"fromBlock":"0x1"}]}`, false},
+		{`{"method":"eth_getLogs","params":[{"blockHash":"0xabc"}]}`, true},
+		{`{"method":"eth_sendRawTransaction","params":["0x1"]}`, false},
+	} {
+		var req rpcRequest
+		r.NoError(json.Unmarshal([]byte(tc.req), &req))
+		_, cacheable := cacheKey(&req)
+		r.Equal(tc.cacheable, cacheable, tc.req)
+	}
+}
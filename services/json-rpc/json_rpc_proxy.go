@@ -15,6 +15,7 @@ import (
 
 	"zktoro/clients/messaging"
 	"zktoro/config"
+	"zktoro/services"
 	"zktoro/services/components/metrics"
 
 	"zktoro/zktoro-core-go/clients/health"
@@ -22,12 +23,14 @@ import (
 	"zktoro/zktoro-core-go/protocol"
 	"zktoro/zktoro-core-go/protocol/settings"
 	"zktoro/zktoro-core-go/utils"
+	"zktoro/zktoro-core-go/utils/httpclient"
 )
 
 // JsonRpcProxy proxies requests from agents to json-rpc endpoint
 type JsonRpcProxy struct {
 	ctx         context.Context
 	cfg         config.JsonRpcConfig
+	cacheCfg    config.JsonRpcCacheConfig
 	server      *http.Server
 	msgClient   clients.MessageClient
 	rateLimiter ratelimiter.RateLimiter
@@ -58,9 +61,18 @@ func (p *JsonRpcProxy) Start() error {
 		AllowCredentials: true,
 	})
 
+	var h http.Handler = rp
+	if !p.cacheCfg.Disable {
+		cache := newResponseCache(
+			p.cfg.Url, p.cfg.Headers, httpclient.Default, uint64(p.cacheCfg.MaxSizeMB)<<20, p.cacheCfg.MinConfirmations,
+		)
+		go cache.TrackHead(p.ctx)
+		h = cache.Handler(rp)
+	}
+
 	p.server = &http.Server{
 		Addr:    ":8545",
-		Handler: p.metricHandler(c.Handler(rp)),
+		Handler: p.metricHandler(c.Handler(h)),
 	}
 	utils.GoListenAndServe(p.server)
 
@@ -77,19 +89,20 @@ func (p *JsonRpcProxy) metricHandler(h http.Handler) http.Handler {
 			writeTooManyReqsErr(w, req)
 			p.msgClient.PublishProto(
 				messaging.SubjectMetricAgent, &protocol.AgentMetricList{
-					Metrics: metrics.GetJSONRPCMetrics(*agentConfig, t, 0, 1, 0),
+					Metrics: metrics.GetJSONRPCMetrics(*agentConfig, t, 0, 1, 0, 0, 0),
 				},
 			)
 			return
 		}
 
-		h.ServeHTTP(w, req)
+		var stats cacheStats
+		h.ServeHTTP(w, req.WithContext(withCacheStats(req.Context(), &stats)))
 
 		if err == nil {
 			duration := time.Since(t)
 			p.msgClient.PublishProto(
 				messaging.SubjectMetricAgent, &protocol.AgentMetricList{
-					Metrics: metrics.GetJSONRPCMetrics(*agentConfig, t, 1, 0, duration),
+					Metrics: metrics.GetJSONRPCMetrics(*agentConfig, t, 1, 0, stats.hits, stats.misses, duration),
 				},
 			)
 		}
@@ -133,26 +146,42 @@ func NewJsonRpcProxy(ctx context.Context, cfg config.Config) (*JsonRpcProxy, err
 		jCfg = cfg.JsonRpcProxy.JsonRpc
 	}
 
-	rateLimiting := cfg.JsonRpcProxy.RateLimitConfig
-	if rateLimiting == nil {
-		rateLimiting = (*config.RateLimitConfig)(settings.GetChainSettings(cfg.ChainID).JsonRpcRateLimiting)
-	}
+	rateLimiting := getRateLimiting(cfg)
 
 	msgClient := messaging.NewClient("json-rpc", fmt.Sprintf("%s:%s", config.DockerNatsContainerName, config.DefaultNatsPort))
 
-	botAuthenticator, err := clients.NewBotAuthenticator(ctx)
+	botAuthenticator, err := clients.NewBotAuthenticator(ctx, cfg.ContainerRuntime)
 	if err != nil {
 		return nil, err
 	}
 
-	return &JsonRpcProxy{
+	proxy := &JsonRpcProxy{
 		ctx:              ctx,
 		cfg:              jCfg,
+		cacheCfg:         cfg.JsonRpcProxy.Cache,
 		botAuthenticator: botAuthenticator,
 		msgClient:        msgClient,
 		rateLimiter: ratelimiter.NewRateLimiter(
 			rateLimiting.Rate,
 			rateLimiting.Burst,
 		),
-	}, nil
+	}
+	services.SubscribeToConfigChanges(msgClient, proxy.Name(), proxy.reloadConfig)
+	return proxy, nil
+}
+
+// reloadConfig applies the rate limit changes.
+func (p *JsonRpcProxy) reloadConfig(cfg config.Config, changes config.ConfigChanges) error {
+	if changes.Reloaded("jsonRpcProxy.rateLimit") {
+		rateLimiting := getRateLimiting(cfg)
+		p.rateLimiter.SetLimit(rateLimiting.Rate, rateLimiting.Burst)
+	}
+	return nil
+}
+
+func getRateLimiting(cfg config.Config) *config.RateLimitConfig {
+	if cfg.JsonRpcProxy.RateLimitConfig != nil {
+		return cfg.JsonRpcProxy.RateLimitConfig
+	}
+	return (*config.RateLimitConfig)(settings.GetChainSettings(cfg.ChainID).JsonRpcRateLimiting)
 }
@@ -9,11 +9,9 @@ import (
 	"zktoro/zktoro-core-go/security"
 
 	"github.com/docker/docker/api/types"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	log "github.com/sirupsen/logrus"
 
 	"zktoro/clients"
-	"zktoro/clients/docker"
 	"zktoro/config"
 	sec "zktoro/services/components/security"
 )
@@ -26,25 +24,29 @@ type JWTProvider interface {
 
 type jwtProvider struct {
 	cfg            config.Config
-	key            *keystore.Key
-	dockerClient   clients.DockerClient
-	jwtCreatorFunc func(key *keystore.Key, claims map[string]interface{}) (string, error)
+	signer         security.Signer
+	dockerClient   clients.ContainerRuntime
+	jwtCreatorFunc func(signer security.Signer, claims map[string]interface{}) (string, error)
 }
 
 func NewJWTProvider(cfg config.Config) (JWTProvider, error) {
-	dc, err := docker.NewDockerClient("")
+	dc, err := clients.NewContainerRuntime(cfg.ContainerRuntime, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create the global docker client: %v", err)
 	}
-	key, err := security.LoadKey(config.DefaultContainerKeyDirPath)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
+	didLinkage, err := config.LoadDIDLinkageInContainer(cfg, signer.Address())
+	if err != nil {
+		log.WithError(err).Warn("not embedding the DID linkage")
+	}
 	return &jwtProvider{
 		cfg:            cfg,
-		key:            key,
+		signer:         signer,
 		dockerClient:   dc,
-		jwtCreatorFunc: security.CreateScannerJWT,
+		jwtCreatorFunc: security.NewScannerJWTCreator(didLinkage),
 	}, nil
 }
 
@@ -61,7 +63,7 @@ func (p *jwtProvider) CreateJWTFromIP(ctx context.Context, ipAddress string, cla
 		"agentId": bot,
 	})
 
-	res, err := sec.CreateBotJWT(p.key, bot, claims, p.jwtCreatorFunc)
+	res, err := sec.CreateBotJWT(p.signer, bot, claims, p.jwtCreatorFunc)
 	if err != nil {
 		logger.WithError(err).Error("error creating jwt")
 		return "", err
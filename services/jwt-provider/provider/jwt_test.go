@@ -9,16 +9,16 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	nw "github.com/docker/docker/api/types/network"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"zktoro/clients/docker"
 	mock_clients "zktoro/clients/mocks"
 	"zktoro/config"
+	"zktoro/zktoro-core-go/security"
 )
 
-func expectGetContainer(dc *mock_clients.MockDockerClient, containerID, ipAddress string) {
+func expectGetContainer(dc *mock_clients.MockContainerRuntime, containerID, ipAddress string) {
 	dc.EXPECT().GetContainers(gomock.Any()).Return(docker.ContainerList{{
 		ID: containerID,
 		NetworkSettings: &types.SummaryNetworkSettings{
@@ -32,7 +32,7 @@ func expectGetContainer(dc *mock_clients.MockDockerClient, containerID, ipAddres
 	}}, nil).Times(1)
 }
 
-func expectInspect(dc *mock_clients.MockDockerClient, containerID, botID, ipAddress string) {
+func expectInspect(dc *mock_clients.MockContainerRuntime, containerID, botID, ipAddress string) {
 	dc.EXPECT().InspectContainer(gomock.Any(), containerID).Return(&types.ContainerJSON{
 		Config: &container.Config{
 			Env: []string{
@@ -62,12 +62,12 @@ func TestCreateJWT(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		mockFunc      func(dc *mock_clients.MockDockerClient)
+		mockFunc      func(dc *mock_clients.MockContainerRuntime)
 		expectedError error
 	}{
 		{
 			name: "successful case",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				expectGetContainer(dc, containerID, ipAddress)
 				expectInspect(dc, containerID, botID, ipAddress)
 			},
@@ -75,14 +75,14 @@ func TestCreateJWT(t *testing.T) {
 		},
 		{
 			name: "get containers error",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				dc.EXPECT().GetContainers(ctx).Return(nil, errors.New("test err"))
 			},
 			expectedError: ErrCannotFindBotForIP,
 		},
 		{
 			name: "inspect container error",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				expectGetContainer(dc, containerID, ipAddress)
 				dc.EXPECT().InspectContainer(ctx, containerID).Return(nil, errors.New("test err"))
 			},
@@ -90,7 +90,7 @@ func TestCreateJWT(t *testing.T) {
 		},
 		{
 			name: "non-matching inspect case",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				expectGetContainer(dc, containerID, ipAddress)
 				expectInspect(dc, containerID, "other", ipAddress)
 			},
@@ -98,14 +98,14 @@ func TestCreateJWT(t *testing.T) {
 		},
 		{
 			name: "non-matching ip case",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				expectGetContainer(dc, containerID, "other")
 			},
 			expectedError: ErrCannotFindBotForIP,
 		},
 		{
 			name: "error with jwt case",
-			mockFunc: func(dc *mock_clients.MockDockerClient) {
+			mockFunc: func(dc *mock_clients.MockContainerRuntime) {
 				expectGetContainer(dc, containerID, ipAddress)
 				expectInspect(dc, containerID, botID, ipAddress)
 			},
@@ -115,13 +115,13 @@ func TestCreateJWT(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockDockerClient := mock_clients.NewMockDockerClient(ctrl)
+			mockDockerClient := mock_clients.NewMockContainerRuntime(ctrl)
 
 			// Define your JWTProvider
 			jp := &jwtProvider{
 				cfg:          config.Config{},
 				dockerClient: mockDockerClient,
-				jwtCreatorFunc: func(key *keystore.Key, claims map[string]interface{}) (string, error) {
+				jwtCreatorFunc: func(signer security.Signer, claims map[string]interface{}) (string, error) {
 					// Mock the JWT creation function here
 					if tc.expectedError != nil {
 						return "", tc.expectedError
@@ -0,0 +1,59 @@
+package listener
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Supported auth modes
+const (
+	AuthModeToken = "token"
+	AuthModeMTLS  = "mtls"
+)
+
+const (
+	// TokenFileName is the name of the file which keeps the generated bearer token.
+	TokenFileName = "listener.token"
+
+	bearerPrefix = "Bearer "
+)
+
+// LoadOrCreateToken returns the token in the token file or creates a new one.
+func LoadOrCreateToken(tokenPath string) (string, error) {
+	b, err := os.ReadFile(tokenPath)
+	if err == nil && len(strings.TrimSpace(string(b))) > 0 {
+		return strings.TrimSpace(string(b)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read the token file: %v", err)
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	if err := os.WriteFile(tokenPath, []byte(token), 0600); err != nil {
+		return "", fmt.Errorf("failed to write the token file: %v", err)
+	}
+	return token, nil
+}
+
+// WithBearerAuth rejects the requests which do not have the expected bearer token.
+func WithBearerAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authHeader := req.Header.Get("Authorization")
+		if !strings.HasPrefix(authHeader, bearerPrefix) ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authHeader, bearerPrefix)), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
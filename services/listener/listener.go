@@ -0,0 +1,200 @@
+package listener
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"time"
+
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	readHeaderTimeout = time.Second * 10
+	readTimeout       = time.Second * 30
+	writeTimeout      = time.Second * 30
+	idleTimeout       = time.Minute * 2
+)
+
+// Listener serves the credential holder API with TLS, client authentication and request limits.
+type Listener struct {
+	cfg       config.ListenerConfig
+	zktoroDir string
+	handler   http.Handler
+
+	tlsConfig *tls.Config
+	srv       *http.Server
+	addr      net.Addr
+}
+
+// NewListener creates a new listener for the handler.
+func NewListener(cfg config.Config, handler http.Handler) (*Listener, error) {
+	lCfg := cfg.Listener
+	switch lCfg.AuthMode {
+	case AuthModeToken:
+	case AuthModeMTLS:
+		if lCfg.TLS.Disable {
+			return nil, errors.New("mtls auth mode requires tls")
+		}
+		if lCfg.TLS.ClientCAFile == "" {
+			return nil, errors.New("mtls auth mode requires a client CA file")
+		}
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %s", lCfg.AuthMode)
+	}
+
+	l := &Listener{
+		cfg:       lCfg,
+		zktoroDir: cfg.ZktoroDir,
+	}
+
+	if lCfg.AuthMode == AuthModeToken {
+		token := lCfg.AuthToken
+		if token == "" {
+			var err error
+			token, err = LoadOrCreateToken(l.TokenPath())
+			if err != nil {
+				return nil, err
+			}
+		}
+		handler = WithBearerAuth(token, handler)
+	}
+	l.handler = withAccessLog(withBodyLimit(lCfg.MaxBodyBytes, handler))
+
+	if !lCfg.TLS.Disable {
+		tlsConfig, err := newTLSConfig(lCfg, cfg.ZktoroDir)
+		if err != nil {
+			return nil, err
+		}
+		l.tlsConfig = tlsConfig
+	}
+	return l, nil
+}
+
+// TokenPath returns the path of the generated bearer token.
+func (l *Listener) TokenPath() string {
+	return path.Join(l.zktoroDir, TokenFileName)
+}
+
+// Addr returns the listening address after the listener is started.
+func (l *Listener) Addr() net.Addr {
+	return l.addr
+}
+
+// Start starts listening and serves in the background.
+func (l *Listener) Start() error {
+	if l.cfg.TLS.Disable {
+		log.Warn("listener tls is disabled - credentials and tokens are sent in plaintext")
+	}
+
+	lis, err := net.Listen("tcp", l.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", l.cfg.Address, err)
+	}
+	l.addr = lis.Addr()
+	if l.tlsConfig != nil {
+		lis = tls.NewListener(lis, l.tlsConfig)
+	}
+
+	l.srv = &http.Server{
+		Handler:           l.handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	go func() {
+		log.WithFields(log.Fields{
+			"address": l.addr.String(),
+			"tls":     l.tlsConfig != nil,
+			"auth":    l.cfg.AuthMode,
+		}).Info("starting listener")
+		err := l.srv.Serve(lis)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Panic("listener server error")
+		}
+	}()
+	return nil
+}
+
+// Stop gracefully shuts down the server.
+func (l *Listener) Stop() error {
+	if l.srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(l.cfg.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := l.srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("error stopping listener server")
+		return l.srv.Close()
+	}
+	return nil
+}
+
+// Name returns the name of the service.
+func (l *Listener) Name() string {
+	return "listener"
+}
+
+// withBodyLimit rejects large requests early and limits the bytes read from the rest.
+func withBodyLimit(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > maxBytes {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		req.Body = http.MaxBytesReader(w, req.Body, maxBytes)
+		next.ServeHTTP(w, req)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += n
+	return n, err
+}
+
+func withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, req)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		fields := log.Fields{
+			"method":     req.Method,
+			"path":       req.URL.Path,
+			"status":     rec.status,
+			"bytes":      rec.bytes,
+			"durationMs": time.Since(start).Milliseconds(),
+			"remote":     req.RemoteAddr,
+		}
+		if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+			fields["client"] = req.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		log.WithFields(fields).Info("listener request")
+	})
+}
@@ -0,0 +1,179 @@
+package listener
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "test-token"
+
+func testConfig(dir string) config.Config {
+	return config.Config{
+		ZktoroDir: dir,
+		Listener: config.ListenerConfig{
+			Address:                "127.0.0.1:0",
+			AuthMode:               AuthModeToken,
+			AuthToken:              testToken,
+			MaxBodyBytes:           16,
+			ShutdownTimeoutSeconds: 1,
+		},
+	}
+}
+
+func echoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write(b)
+	})
+}
+
+func startListener(r *require.Assertions, cfg config.Config) *Listener {
+	l, err := NewListener(cfg, echoHandler())
+	r.NoError(err)
+	r.NoError(l.Start())
+	return l
+}
+
+func tlsClient(r *require.Assertions, caFile string, clientCert *tls.Certificate) *http.Client {
+	caPEM, err := os.ReadFile(caFile)
+	r.NoError(err)
+	roots := x509.NewCertPool()
+	r.True(roots.AppendCertsFromPEM(caPEM))
+	tlsConfig := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+func createClientCert(r *require.Assertions, certFile string) tls.Certificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	r.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	r.NoError(err)
+	r.NoError(writePEM(certFile, "CERTIFICATE", certDER, 0644))
+	return tls.Certificate{Certificate: [][]byte{certDER}, PrivateKey: privateKey}
+}
+
+func post(r *require.Assertions, client *http.Client, url, token, body string) int {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	r.NoError(err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	r.NoError(err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestListener_TokenAuthAndBodyLimit(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	l := startListener(r, testConfig(dir))
+	defer l.Stop()
+
+	client := tlsClient(r, path.Join(dir, selfSignedCertFileName), nil)
+	url := fmt.Sprintf("https://%s/putVC", l.Addr())
+
+	r.Equal(http.StatusUnauthorized, post(r, client, url, "", "{}"))
+	r.Equal(http.StatusUnauthorized, post(r, client, url, "wrong-token", "{}"))
+	r.Equal(http.StatusOK, post(r, client, url, testToken, "{}"))
+	r.Equal(http.StatusRequestEntityTooLarge, post(r, client, url, testToken, strings.Repeat("a", 17)))
+
+	r.NoError(l.Stop())
+	_, err := client.Get(url)
+	r.Error(err)
+}
+
+func TestListener_GeneratedToken(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	cfg := testConfig(dir)
+	cfg.Listener.AuthToken = ""
+	cfg.Listener.TLS.Disable = true
+	l := startListener(r, cfg)
+	defer l.Stop()
+
+	token, err := os.ReadFile(l.TokenPath())
+	r.NoError(err)
+	r.NotEmpty(token)
+
+	url := fmt.Sprintf("http://%s/putVC", l.Addr())
+	r.Equal(http.StatusOK, post(r, http.DefaultClient, url, string(token), "{}"))
+
+	// the same token is used after restart
+	l2, err := NewListener(cfg, echoHandler())
+	r.NoError(err)
+	token2, err := os.ReadFile(l2.TokenPath())
+	r.NoError(err)
+	r.Equal(token, token2)
+}
+
+func TestListener_MTLS(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+
+	// use a self-signed client certificate as the client CA
+	clientCertFile := path.Join(dir, "client-cert.pem")
+	clientCert := createClientCert(r, clientCertFile)
+
+	cfg := testConfig(dir)
+	cfg.Listener.AuthMode = AuthModeMTLS
+	cfg.Listener.TLS.ClientCAFile = clientCertFile
+	l := startListener(r, cfg)
+	defer l.Stop()
+
+	url := fmt.Sprintf("https://%s/putVC", l.Addr())
+	r.Equal(http.StatusOK, post(r, tlsClient(r, path.Join(dir, selfSignedCertFileName), &clientCert), url, "", "{}"))
+
+	_, err := tlsClient(r, path.Join(dir, selfSignedCertFileName), nil).Get(url)
+	r.Error(err)
+}
+
+func TestListener_InvalidConfig(t *testing.T) {
+	r := require.New(t)
+
+	cfg := testConfig(t.TempDir())
+	cfg.Listener.AuthMode = AuthModeMTLS
+	_, err := NewListener(cfg, echoHandler())
+	r.Error(err)
+
+	cfg.Listener.TLS.Disable = true
+	cfg.Listener.TLS.ClientCAFile = "ca.pem"
+	_, err = NewListener(cfg, echoHandler())
+	r.Error(err)
+}
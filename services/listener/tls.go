@@ -0,0 +1,138 @@
+package listener
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	selfSignedCertFileName = "listener-cert.pem"
+	selfSignedKeyFileName  = "listener-key.pem"
+	selfSignedCertValidity = time.Hour * 24 * 365
+	// renew the self-signed certificate a while before it expires
+	selfSignedCertRenewBefore = time.Hour * 24 * 7
+)
+
+// newTLSConfig creates the server TLS config from the configured certificate or from a self-signed
+// certificate which is created in the zktoro dir at first use.
+func newTLSConfig(cfg config.ListenerConfig, zktoroDir string) (*tls.Config, error) {
+	certFile, keyFile := cfg.TLS.CertFile, cfg.TLS.KeyFile
+	if certFile == "" {
+		certFile = path.Join(zktoroDir, selfSignedCertFileName)
+		keyFile = path.Join(zktoroDir, selfSignedKeyFileName)
+		if err := ensureSelfSignedCert(certFile, keyFile, cfg.TLS.Hosts); err != nil {
+			return nil, fmt.Errorf("failed to bootstrap self-signed certificate: %v", err)
+		}
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the listener certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if cfg.AuthMode != AuthModeMTLS {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.TLS.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the client CA file: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no certificates found in the client CA file")
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
+
+// ensureSelfSignedCert reuses the existing self-signed certificate unless it is about to expire.
+func ensureSelfSignedCert(certFile, keyFile string, hosts []string) error {
+	certPEM, err := os.ReadFile(certFile)
+	if err == nil {
+		block, _ := pem.Decode(certPEM)
+		if block != nil {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err == nil && time.Now().Add(selfSignedCertRenewBefore).Before(cert.NotAfter) {
+				return nil
+			}
+		}
+	}
+
+	log.WithField("certFile", certFile).Info("creating self-signed listener certificate")
+	return createSelfSignedCert(certFile, keyFile, hosts)
+}
+
+func createSelfSignedCert(certFile, keyFile string, hosts []string) error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"zktoro node"}, CommonName: "zktoro-listener"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	hosts = append([]string{"localhost", "127.0.0.1", "::1"}, hosts...)
+	if hostname, err := os.Hostname(); err == nil {
+		hosts = append(hosts, hostname)
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(keyFile, "EC PRIVATE KEY", keyDER, 0600); err != nil {
+		return err
+	}
+	return writePEM(certFile, "CERTIFICATE", certDER, 0644)
+}
+
+func writePEM(filePath, blockType string, b []byte, perm os.FileMode) error {
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: b}), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
@@ -14,7 +14,6 @@ import (
 	"zktoro/zktoro-core-go/security"
 	"zktoro/zktoro-core-go/utils"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/rs/cors"
 	"github.com/sirupsen/logrus"
 
@@ -22,6 +21,7 @@ import (
 	"zktoro/clients/messaging"
 	"zktoro/clients/ratelimiter"
 	"zktoro/config"
+	"zktoro/services"
 	"zktoro/services/components/metrics"
 	sec "zktoro/services/components/security"
 )
@@ -40,7 +40,7 @@ const claimKeyBotOwner = "bot-owner"
 type PublicAPIProxy struct {
 	ctx       context.Context
 	cfg       config.PublicAPIProxyConfig
-	Key       *keystore.Key
+	Signer    security.Signer
 	msgClient clients.MessageClient
 
 	server *http.Server
@@ -49,6 +49,7 @@ type PublicAPIProxy struct {
 
 	lastErr       health.ErrorTracker
 	authenticator clients.IPAuthenticator
+	didLinkage    string
 }
 
 func (p *PublicAPIProxy) newReverseProxy() http.Handler {
@@ -174,7 +175,7 @@ func (p *PublicAPIProxy) setAuthBearer(r *http.Request) {
 
 	claims := map[string]interface{}{claimKeyBotOwner: botOwner}
 
-	jwtToken, err := sec.CreateBotJWT(p.Key, botID, claims, security.CreateScannerJWT)
+	jwtToken, err := sec.CreateBotJWT(p.Signer, botID, claims, security.NewScannerJWTCreator(p.didLinkage))
 	if err != nil {
 		log.WithError(err).Warn("can't create bot jwt")
 		return
@@ -262,28 +263,50 @@ func (p *PublicAPIProxy) testAPI() {
 }
 
 func NewPublicAPIProxy(ctx context.Context, cfg config.Config) (*PublicAPIProxy, error) {
-	key, err := security.LoadKey(config.DefaultContainerKeyDirPath)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	botAuthenticator, err := clients.NewBotAuthenticator(ctx)
+	botAuthenticator, err := clients.NewBotAuthenticator(ctx, cfg.ContainerRuntime)
 	if err != nil {
 		return nil, err
 	}
 
 	msgClient := messaging.NewClient("public-api", fmt.Sprintf("%s:%s", config.DockerNatsContainerName, config.DefaultNatsPort))
 
-	rateLimiting := cfg.PublicAPIProxy.RateLimitConfig
-	if rateLimiting == nil {
-		rateLimiting = &config.RateLimitConfig{Rate: 1000, Burst: 1}
+	rateLimiting := getRateLimiting(cfg)
+
+	proxy, err := newPublicAPIProxy(ctx, cfg.PublicAPIProxy, botAuthenticator, ratelimiter.NewRateLimiter(rateLimiting.Rate, rateLimiting.Burst), signer, msgClient)
+	if err != nil {
+		return nil, err
 	}
+	proxy.didLinkage, err = config.LoadDIDLinkageInContainer(cfg, signer.Address())
+	if err != nil {
+		logrus.WithError(err).Warn("not embedding the DID linkage")
+	}
+	services.SubscribeToConfigChanges(msgClient, proxy.Name(), proxy.reloadConfig)
+	return proxy, nil
+}
 
-	return newPublicAPIProxy(ctx, cfg.PublicAPIProxy, botAuthenticator, ratelimiter.NewRateLimiter(rateLimiting.Rate, rateLimiting.Burst), key, msgClient)
+// reloadConfig applies the rate limit changes.
+func (p *PublicAPIProxy) reloadConfig(cfg config.Config, changes config.ConfigChanges) error {
+	if changes.Reloaded("publicApiProxy.rateLimit") {
+		rateLimiting := getRateLimiting(cfg)
+		p.rateLimiter.SetLimit(rateLimiting.Rate, rateLimiting.Burst)
+	}
+	return nil
+}
+
+func getRateLimiting(cfg config.Config) *config.RateLimitConfig {
+	if cfg.PublicAPIProxy.RateLimitConfig != nil {
+		return cfg.PublicAPIProxy.RateLimitConfig
+	}
+	return &config.RateLimitConfig{Rate: 1000, Burst: 1}
 }
 
 func newPublicAPIProxy(
-	ctx context.Context, cfg config.PublicAPIProxyConfig, botAuthenticator clients.IPAuthenticator, rateLimiter ratelimiter.RateLimiter, key *keystore.Key, msgClient clients.MessageClient,
+	ctx context.Context, cfg config.PublicAPIProxyConfig, botAuthenticator clients.IPAuthenticator, rateLimiter ratelimiter.RateLimiter, signer security.Signer, msgClient clients.MessageClient,
 ) (
 	*PublicAPIProxy, error,
 ) {
@@ -292,7 +315,7 @@ func newPublicAPIProxy(
 		cfg:           cfg,
 		authenticator: botAuthenticator,
 		msgClient:     msgClient,
-		Key:           key,
+		Signer:        signer,
 		rateLimiter:   rateLimiter,
 	}, nil
 }
@@ -51,7 +51,7 @@ func TestPublicAPIProxy_setAuthBearer(t *testing.T) {
 	ctx = context.WithValue(ctx, isScannerKey, true)
 	req = req.WithContext(ctx)
 
-	proxy := PublicAPIProxy{Key: key}
+	proxy := PublicAPIProxy{Signer: security.NewKeySigner(key)}
 	proxy.setAuthBearer(req)
 	// parse and authenticate token
 	h := req.Header.Get("Authorization")
@@ -131,7 +131,7 @@ func TestPublicAPIProxy(t *testing.T) {
 		context.Background(), config.PublicAPIProxyConfig{
 			Url:     "https://api.zktoro.network",
 			Headers: map[string]string{"test-header": "test-header-value"},
-		}, authenticator, ratelimiter, _keyConstructor(t), messageClient,
+		}, authenticator, ratelimiter, security.NewKeySigner(_keyConstructor(t)), messageClient,
 	)
 
 	server := httptest.NewServer(p.createPublicAPIProxyHandler())
@@ -0,0 +1,110 @@
+package alertindex
+
+import (
+	"strconv"
+	"time"
+
+	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ToAlertEvent converts the alert of a notification to the alert type of the public API. It
+// returns nil if the notification does not have an alert.
+func ToAlertEvent(notif *protocol.NotifyRequest, chainID uint64) *protocol.AlertEvent_Alert {
+	if notif.SignedAlert == nil || notif.SignedAlert.Alert == nil || notif.SignedAlert.Alert.Finding == nil {
+		return nil
+	}
+	alert := notif.SignedAlert.Alert
+	finding := alert.Finding
+
+	alertEvent := &protocol.AlertEvent_Alert{
+		AlertId:            finding.AlertId,
+		Addresses:          finding.Addresses,
+		CreatedAt:          alert.Timestamp,
+		Description:        finding.Description,
+		Hash:               alert.Id,
+		Metadata:           finding.Metadata,
+		Name:               finding.Name,
+		ScanNodeCount:      1,
+		Severity:           finding.Severity.String(),
+		FindingType:        finding.Type.String(),
+		RelatedAlerts:      finding.RelatedAlerts,
+		ChainId:            chainID,
+		Truncated:          alert.Truncated,
+		AddressBloomFilter: alert.AddressBloomFilter,
+		Source:             &protocol.AlertEvent_Alert_Source{},
+	}
+	if alert.Agent != nil {
+		alertEvent.Source.Bot = &protocol.AlertEvent_Alert_Bot{
+			Id:        alert.Agent.Id,
+			Image:     alert.Agent.Image,
+			Reference: alert.Agent.Manifest,
+		}
+	}
+	for _, label := range finding.Labels {
+		alertEvent.Labels = append(alertEvent.Labels, &protocol.AlertEvent_Alert_Label{
+			Label:      label.Label,
+			Confidence: label.Confidence,
+			Entity:     label.Entity,
+			EntityType: label.EntityType.String(),
+			Remove:     label.Remove,
+			Metadata:   label.Metadata,
+		})
+	}
+
+	switch {
+	case notif.EvalBlockRequest != nil && notif.EvalBlockRequest.Event != nil:
+		event := notif.EvalBlockRequest.Event
+		var blockTimestamp string
+		if event.Block != nil {
+			blockTimestamp = event.Block.Timestamp
+		}
+		alertEvent.Source.Block = toAlertBlock(chainID, event.BlockHash, event.BlockNumber, blockTimestamp)
+
+	case notif.EvalTxRequest != nil && notif.EvalTxRequest.Event != nil:
+		event := notif.EvalTxRequest.Event
+		if event.Block != nil {
+			alertEvent.Source.Block = toAlertBlock(chainID, event.Block.BlockHash, event.Block.BlockNumber, event.Block.BlockTimestamp)
+		}
+		if event.Transaction != nil {
+			alertEvent.Source.TransactionHash = event.Transaction.Hash
+		}
+		if len(alertEvent.Addresses) == 0 {
+			alertEvent.Addresses = utils.MapKeys(event.Addresses)
+		}
+
+	case notif.EvalAlertRequest != nil && notif.EvalAlertRequest.Event != nil:
+		sourceAlert := notif.EvalAlertRequest.Event.Alert
+		if sourceAlert != nil && sourceAlert.Source != nil {
+			alertEvent.Source.Block = sourceAlert.Source.Block
+			sourceEvent := &protocol.AlertEvent_Alert_SourceAlertEvent{
+				AlertHash: sourceAlert.Hash,
+				Timestamp: sourceAlert.CreatedAt,
+				ChainId:   strconv.FormatUint(sourceAlert.ChainId, 10),
+			}
+			if sourceAlert.Source.Bot != nil {
+				sourceEvent.BotId = sourceAlert.Source.Bot.Id
+			}
+			alertEvent.Source.SourceEvent = sourceEvent
+		}
+	}
+
+	return alertEvent
+}
+
+// toAlertBlock converts the hex block number and timestamp of the evaluated events.
+func toAlertBlock(chainID uint64, blockHash, blockNumber, blockTimestamp string) *protocol.AlertEvent_Alert_Block {
+	block := &protocol.AlertEvent_Alert_Block{
+		Hash:    blockHash,
+		ChainId: chainID,
+	}
+	if number, err := hexutil.DecodeUint64(blockNumber); err == nil {
+		block.Number = number
+	}
+	if timestamp, err := hexutil.DecodeUint64(blockTimestamp); err == nil {
+		block.Timestamp = time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339)
+	}
+	return block
+}
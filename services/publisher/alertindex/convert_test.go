@@ -0,0 +1,55 @@
+package alertindex
+
+import (
+	"testing"
+
+	"zktoro/zktoro-core-go/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToAlertEvent(t *testing.T) {
+	r := require.New(t)
+
+	r.Nil(ToAlertEvent(&protocol.NotifyRequest{}, 1))
+
+	alert := ToAlertEvent(&protocol.NotifyRequest{
+		SignedAlert: &protocol.SignedAlert{
+			Alert: &protocol.Alert{
+				Id:        "0xhash",
+				Timestamp: "2023-01-01T00:00:00Z",
+				Agent:     &protocol.AgentInfo{Id: "0xbot", Image: "image", Manifest: "manifest"},
+				Finding: &protocol.Finding{
+					AlertId:  "ALERT-1",
+					Name:     "name",
+					Severity: protocol.Finding_HIGH,
+					Type:     protocol.Finding_EXPLOIT,
+					Labels:   []*protocol.Label{{Label: "attacker", Entity: "0xabc", EntityType: protocol.Label_ADDRESS}},
+				},
+			},
+		},
+		EvalTxRequest: &protocol.EvaluateTxRequest{
+			Event: &protocol.TransactionEvent{
+				Block: &protocol.TransactionEvent_EthBlock{
+					BlockHash:      "0xblock",
+					BlockNumber:    "0x10",
+					BlockTimestamp: "0x63b0cd00",
+				},
+				Transaction: &protocol.TransactionEvent_EthTransaction{Hash: "0xtx"},
+				Addresses:   map[string]bool{"0xabc": true},
+			},
+		},
+	}, 1)
+	r.NotNil(alert)
+	r.Equal("0xhash", alert.Hash)
+	r.Equal("ALERT-1", alert.AlertId)
+	r.Equal("HIGH", alert.Severity)
+	r.Equal("EXPLOIT", alert.FindingType)
+	r.Equal(uint64(1), alert.ChainId)
+	r.Equal([]string{"0xabc"}, alert.Addresses)
+	r.Equal("0xbot", alert.Source.Bot.Id)
+	r.Equal("0xtx", alert.Source.TransactionHash)
+	r.Equal(uint64(16), alert.Source.Block.Number)
+	r.Equal("2023-01-01T00:00:00Z", alert.Source.Block.Timestamp)
+	r.Equal("ADDRESS", alert.Labels[0].EntityType)
+}
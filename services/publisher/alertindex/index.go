@@ -0,0 +1,337 @@
+package alertindex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/bits-and-blooms/bloom"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	segmentExt       = ".jsonl"
+	segmentNameWidth = 20
+
+	// a segment is closed after this duration so that the retention can drop the old alerts
+	maxSegmentAge = 10 * time.Minute
+	// the size limit drops the oldest segment so a segment holds a fraction of the max size
+	segmentsPerMaxSize = 8
+)
+
+// entry is an indexed alert.
+type entry struct {
+	alert     *protocol.AlertEvent_Alert
+	createdAt time.Time
+	addresses map[string]bool
+	bloom     *bloom.BloomFilter
+	segment   uint64
+}
+
+func newEntry(alert *protocol.AlertEvent_Alert, segment uint64) *entry {
+	e := &entry{
+		alert:     alert,
+		addresses: make(map[string]bool),
+		segment:   segment,
+	}
+	createdAt, err := time.Parse(time.RFC3339, alert.CreatedAt)
+	if err != nil {
+		createdAt = time.Now().UTC()
+	}
+	e.createdAt = createdAt
+	for _, address := range alert.Addresses {
+		e.addresses[strings.ToLower(address)] = true
+	}
+	if alert.AddressBloomFilter != nil && len(alert.AddressBloomFilter.Bitset) > 0 {
+		bf, err := utils.CreateBloomFilterFromProto(alert.AddressBloomFilter)
+		if err != nil {
+			log.WithError(err).WithField("alertHash", alert.Hash).Warn("failed to read the address bloom filter")
+		} else {
+			e.bloom = bf
+		}
+	}
+	return e
+}
+
+func (e *entry) botID() string {
+	if e.alert.Source == nil || e.alert.Source.Bot == nil {
+		return ""
+	}
+	return strings.ToLower(e.alert.Source.Bot.Id)
+}
+
+func (e *entry) blockNumber() uint64 {
+	if e.alert.Source == nil || e.alert.Source.Block == nil {
+		return 0
+	}
+	return e.alert.Source.Block.Number
+}
+
+// hasAddress checks the addresses of the alert first and the bloom filter next. The bloom
+// filter can have false positives like the address queries of the public API.
+func (e *entry) hasAddress(address string) bool {
+	lower := strings.ToLower(address)
+	if e.addresses[lower] {
+		return true
+	}
+	if e.bloom == nil {
+		return false
+	}
+	return e.bloom.TestString(address) || e.bloom.TestString(lower)
+}
+
+// segment is a file which contains the alerts written in a period of time.
+type segment struct {
+	seq     uint64
+	size    int64
+	created time.Time
+	newest  time.Time
+}
+
+// Index is an embedded index of the alerts. The alerts are appended to segment files which
+// are dropped after the retention period or when the index exceeds the max size. The alerts
+// are looked up from the memory by bot ID, alert ID, chain, block and addresses.
+type Index struct {
+	dir            string
+	retention      time.Duration
+	maxSize        int64
+	maxSegmentSize int64
+
+	file     *os.File
+	segments []*segment // the last segment is written to
+	size     int64
+
+	entries   []*entry
+	byHash    map[string]*entry
+	byBot     map[string][]*entry
+	byAlertID map[string][]*entry
+	mu        sync.RWMutex
+}
+
+// NewIndex creates the index dir if needed and loads the alerts left from a previous run.
+func NewIndex(dir string, retention time.Duration, maxSize int64) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create alert index dir: %v", err)
+	}
+	idx := &Index{
+		dir:            dir,
+		retention:      retention,
+		maxSize:        maxSize,
+		maxSegmentSize: maxSize / segmentsPerMaxSize,
+	}
+	idx.resetMaps()
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+	// never append to a segment from a previous run since it can end with a partial line
+	if err := idx.openSegment(time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	idx.prune(time.Now().UTC())
+	return idx, nil
+}
+
+func (idx *Index) resetMaps() {
+	idx.byHash = make(map[string]*entry)
+	idx.byBot = make(map[string][]*entry)
+	idx.byAlertID = make(map[string][]*entry)
+}
+
+func (idx *Index) segmentPath(seq uint64) string {
+	return path.Join(idx.dir, fmt.Sprintf("%0*d%s", segmentNameWidth, seq, segmentExt))
+}
+
+func (idx *Index) load() error {
+	dirEntries, err := os.ReadDir(idx.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read alert index dir: %v", err)
+	}
+	var seqs []uint64
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			log.WithField("file", name).Warn("ignoring unknown file in alert index dir")
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool {
+		return seqs[i] < seqs[j]
+	})
+	for _, seq := range seqs {
+		if err := idx.loadSegment(seq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Index) loadSegment(seq uint64) error {
+	file, err := os.Open(idx.segmentPath(seq))
+	if err != nil {
+		return fmt.Errorf("failed to open alert index segment: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat alert index segment: %v", err)
+	}
+
+	seg := &segment{
+		seq:     seq,
+		size:    info.Size(),
+		created: info.ModTime(),
+		newest:  info.ModTime(),
+	}
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var alert protocol.AlertEvent_Alert
+			if err := json.Unmarshal(line, &alert); err != nil {
+				log.WithError(err).WithField("segment", seq).Warn("skipping corrupt line in alert index segment")
+			} else {
+				idx.add(newEntry(&alert, seq))
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read alert index segment: %v", err)
+		}
+	}
+	idx.segments = append(idx.segments, seg)
+	idx.size += seg.size
+	return nil
+}
+
+func (idx *Index) openSegment(now time.Time) error {
+	var seq uint64 = 1
+	if len(idx.segments) > 0 {
+		seq = idx.segments[len(idx.segments)-1].seq + 1
+	}
+	file, err := os.OpenFile(idx.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert index segment: %v", err)
+	}
+	idx.file = file
+	idx.segments = append(idx.segments, &segment{
+		seq:     seq,
+		created: now,
+		newest:  now,
+	})
+	return nil
+}
+
+func (idx *Index) currentSegment() *segment {
+	return idx.segments[len(idx.segments)-1]
+}
+
+func (idx *Index) add(e *entry) {
+	idx.entries = append(idx.entries, e)
+	idx.byHash[e.alert.Hash] = e
+	botID := e.botID()
+	idx.byBot[botID] = append(idx.byBot[botID], e)
+	idx.byAlertID[e.alert.AlertId] = append(idx.byAlertID[e.alert.AlertId], e)
+}
+
+// Put adds the alert to the index. The alerts which are already in the index are ignored.
+func (idx *Index) Put(alert *protocol.AlertEvent_Alert) error {
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %v", err)
+	}
+	b = append(b, '\n')
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.byHash[alert.Hash]; ok {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	seg := idx.currentSegment()
+	if seg.size > 0 && (now.Sub(seg.created) > maxSegmentAge || seg.size+int64(len(b)) > idx.maxSegmentSize) {
+		if err := idx.file.Close(); err != nil {
+			return fmt.Errorf("failed to close alert index segment: %v", err)
+		}
+		if err := idx.openSegment(now); err != nil {
+			return err
+		}
+		seg = idx.currentSegment()
+	}
+	n, err := idx.file.Write(b)
+	seg.size += int64(n)
+	idx.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write alert to index: %v", err)
+	}
+	seg.newest = now
+
+	idx.add(newEntry(alert, seg.seq))
+	idx.prune(now)
+	return nil
+}
+
+// prune drops the oldest segments which are past the retention or exceed the max size.
+func (idx *Index) prune(now time.Time) {
+	var dropped bool
+	for len(idx.segments) > 1 {
+		oldest := idx.segments[0]
+		if now.Sub(oldest.newest) <= idx.retention && idx.size <= idx.maxSize {
+			break
+		}
+		if err := os.Remove(idx.segmentPath(oldest.seq)); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).WithField("segment", oldest.seq).Warn("failed to remove alert index segment")
+		}
+		idx.size -= oldest.size
+		idx.segments = idx.segments[1:]
+		dropped = true
+	}
+	if !dropped {
+		return
+	}
+
+	firstSeq := idx.segments[0].seq
+	entries := idx.entries
+	idx.entries = nil
+	idx.resetMaps()
+	for _, e := range entries {
+		if e.segment >= firstSeq {
+			idx.add(e)
+		}
+	}
+}
+
+// Len returns the number of the indexed alerts.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Close closes the segment which is written to.
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.file.Close()
+}
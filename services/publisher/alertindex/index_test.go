@@ -0,0 +1,216 @@
+package alertindex
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxSize = 1 << 20
+
+func testAlert(hash, botID, alertID string, blockNumber uint64) *protocol.AlertEvent_Alert {
+	return &protocol.AlertEvent_Alert{
+		AlertId:   alertID,
+		Hash:      hash,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Name:      "test alert",
+		Severity:  "HIGH",
+		ChainId:   1,
+		Source: &protocol.AlertEvent_Alert_Source{
+			TransactionHash: fmt.Sprintf("0xtx%d", blockNumber),
+			Bot:             &protocol.AlertEvent_Alert_Bot{Id: botID},
+			Block: &protocol.AlertEvent_Alert_Block{
+				Number:    blockNumber,
+				Timestamp: time.Unix(int64(blockNumber), 0).UTC().Format(time.RFC3339),
+				ChainId:   1,
+			},
+		},
+	}
+}
+
+func hashes(alerts []*protocol.AlertEvent_Alert) []string {
+	var result []string
+	for _, alert := range alerts {
+		result = append(result, alert.Hash)
+	}
+	return result
+}
+
+func TestIndex_Query(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	alert1 := testAlert("0x1", "0xbot1", "ALERT-1", 10)
+	alert1.Addresses = []string{"0xAbC"}
+	alert2 := testAlert("0x2", "0xbot1", "ALERT-2", 11)
+	alert2.Severity = "LOW"
+	alert3 := testAlert("0x3", "0xBOT2", "ALERT-1", 12)
+	alert3.ChainId = 137
+	bf, err := utils.CreateBloomFilter([]string{"0xdef"}, 0.01)
+	r.NoError(err)
+	alert3.AddressBloomFilter = bf
+
+	for _, alert := range []*protocol.AlertEvent_Alert{alert1, alert2, alert3} {
+		r.NoError(idx.Put(alert))
+	}
+	// duplicates are ignored
+	r.NoError(idx.Put(alert1))
+	r.Equal(3, idx.Len())
+
+	alerts, pageInfo := idx.Query(&graphql.AlertsInput{})
+	r.Equal([]string{"0x3", "0x2", "0x1"}, hashes(alerts))
+	r.False(pageInfo.HasNextPage)
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{BlockSortDirection: graphql.SortAsc})
+	r.Equal([]string{"0x1", "0x2", "0x3"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{Bots: []string{"0xbot2"}})
+	r.Equal([]string{"0x3"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{AlertId: "ALERT-1"})
+	r.Equal([]string{"0x3", "0x1"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{Bots: []string{"0xbot1"}, AlertIds: []string{"ALERT-2"}})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{AlertHash: "0x2"})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{ChainId: 137})
+	r.Equal([]string{"0x3"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{Severities: []string{"low"}})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{TransactionHash: "0xtx11"})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{BlockNumberRange: &graphql.BlockRange{StartBlockNumber: 11, EndBlockNumber: 11}})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{BlockTimestampRange: &graphql.TimestampRange{StartTimestamp: 11000}})
+	r.Equal([]string{"0x3", "0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{Addresses: []string{"0xabc", "0xDEF"}})
+	r.Equal([]string{"0x3", "0x1"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{Addresses: []string{"0x123"}})
+	r.Empty(alerts)
+}
+
+func TestIndex_Pagination(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	for i := 1; i <= 5; i++ {
+		r.NoError(idx.Put(testAlert(fmt.Sprintf("0x%d", i), "0xbot", "ALERT", uint64(i))))
+	}
+
+	input := &graphql.AlertsInput{First: 2}
+	var pages [][]string
+	for {
+		alerts, pageInfo := idx.Query(input)
+		pages = append(pages, hashes(alerts))
+		if !pageInfo.HasNextPage {
+			break
+		}
+		input.After = &graphql.AlertEndCursorInput{
+			AlertId:     pageInfo.EndCursor.AlertId,
+			BlockNumber: pageInfo.EndCursor.BlockNumber,
+		}
+	}
+	r.Equal([][]string{{"0x5", "0x4"}, {"0x3", "0x2"}, {"0x1"}}, pages)
+}
+
+func TestIndex_CreatedSince(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	oldAlert := testAlert("0x1", "0xbot", "ALERT", 1)
+	oldAlert.CreatedAt = time.Now().Add(-time.Minute * 30).UTC().Format(time.RFC3339)
+	r.NoError(idx.Put(oldAlert))
+	r.NoError(idx.Put(testAlert("0x2", "0xbot", "ALERT", 2)))
+
+	alerts, _ := idx.Query(&graphql.AlertsInput{CreatedSince: uint((time.Minute * 10).Milliseconds())})
+	r.Equal([]string{"0x2"}, hashes(alerts))
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{CreatedBefore: uint((time.Minute * 10).Milliseconds())})
+	r.Equal([]string{"0x1"}, hashes(alerts))
+
+	// the alerts older than the retention are not returned
+	expiredAlert := testAlert("0x3", "0xbot", "ALERT", 3)
+	expiredAlert.CreatedAt = time.Now().Add(-time.Hour * 2).UTC().Format(time.RFC3339)
+	r.NoError(idx.Put(expiredAlert))
+	alerts, _ = idx.Query(&graphql.AlertsInput{})
+	r.Equal([]string{"0x2", "0x1"}, hashes(alerts))
+}
+
+func TestIndex_Reload(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	idx, err := NewIndex(dir, time.Hour, testMaxSize)
+	r.NoError(err)
+	r.NoError(idx.Put(testAlert("0x1", "0xbot", "ALERT", 1)))
+	r.NoError(idx.Put(testAlert("0x2", "0xbot", "ALERT", 2)))
+	r.NoError(idx.Close())
+
+	// a partial line from a crash should be skipped
+	f, err := os.OpenFile(idx.segmentPath(1), os.O_WRONLY|os.O_APPEND, 0644)
+	r.NoError(err)
+	_, err = f.WriteString(`{"hash":"0x3","alertId`)
+	r.NoError(err)
+	r.NoError(f.Close())
+
+	idx, err = NewIndex(dir, time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+	r.Equal(2, idx.Len())
+
+	alerts, _ := idx.Query(&graphql.AlertsInput{Bots: []string{"0xbot"}})
+	r.Equal([]string{"0x2", "0x1"}, hashes(alerts))
+
+	// the new alerts go to a new segment
+	r.NoError(idx.Put(testAlert("0x3", "0xbot", "ALERT", 3)))
+	r.Equal(3, idx.Len())
+	r.FileExists(idx.segmentPath(2))
+}
+
+func TestIndex_MaxSize(t *testing.T) {
+	r := require.New(t)
+
+	// every segment fits a few alerts so the oldest segments are dropped
+	idx, err := NewIndex(t.TempDir(), time.Hour, 4096)
+	r.NoError(err)
+	defer idx.Close()
+
+	for i := 1; i <= 100; i++ {
+		r.NoError(idx.Put(testAlert(fmt.Sprintf("0x%d", i), "0xbot", "ALERT", uint64(i))))
+	}
+
+	r.Less(idx.Len(), 100)
+	r.LessOrEqual(idx.size, idx.maxSize)
+	alerts, _ := idx.Query(&graphql.AlertsInput{BlockSortDirection: graphql.SortAsc})
+	r.Equal(idx.Len(), len(alerts))
+	r.Equal("0x100", alerts[len(alerts)-1].Hash)
+	r.NotEqual("0x1", alerts[0].Hash)
+	_, ok := idx.byHash["0x1"]
+	r.False(ok)
+	r.NoFileExists(idx.segmentPath(1))
+}
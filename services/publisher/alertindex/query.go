@@ -0,0 +1,269 @@
+package alertindex
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/protocol"
+)
+
+const dateLayout = "2006-01-02"
+
+// Query returns the alerts which match the input, in the same order and with the same
+// pagination as the alerts query of the public API.
+func (idx *Index) Query(input *graphql.AlertsInput) ([]*protocol.AlertEvent_Alert, *graphql.PageInfo) {
+	if input == nil {
+		input = &graphql.AlertsInput{}
+	}
+	now := time.Now().UTC()
+	f := newFilter(input, idx.retention, now)
+
+	idx.mu.RLock()
+	var matches []*entry
+	for _, e := range idx.candidates(input) {
+		if f.match(e) {
+			matches = append(matches, e)
+		}
+	}
+	idx.mu.RUnlock()
+
+	desc := input.BlockSortDirection != graphql.SortAsc
+	sort.SliceStable(matches, func(i, j int) bool {
+		if desc {
+			return less(matches[j], matches[i])
+		}
+		return less(matches[i], matches[j])
+	})
+
+	if input.After != nil {
+		cursor := &entry{alert: &protocol.AlertEvent_Alert{
+			Hash:   input.After.AlertId,
+			Source: &protocol.AlertEvent_Alert_Source{Block: &protocol.AlertEvent_Alert_Block{Number: uint64(input.After.BlockNumber)}},
+		}}
+		start := sort.Search(len(matches), func(i int) bool {
+			if desc {
+				return less(matches[i], cursor)
+			}
+			return less(cursor, matches[i])
+		})
+		matches = matches[start:]
+	}
+
+	first := int(input.First)
+	if first == 0 {
+		first = graphql.DefaultPageSize
+	}
+	pageInfo := &graphql.PageInfo{}
+	if len(matches) > first {
+		matches = matches[:first]
+		pageInfo.HasNextPage = true
+	}
+	if len(matches) > 0 {
+		last := matches[len(matches)-1]
+		pageInfo.EndCursor = &graphql.EndCursor{
+			AlertId:     last.alert.Hash,
+			BlockNumber: uint(last.blockNumber()),
+		}
+	}
+
+	alerts := make([]*protocol.AlertEvent_Alert, len(matches))
+	for i, e := range matches {
+		alerts[i] = e.alert
+	}
+	return alerts, pageInfo
+}
+
+// less orders the alerts by block number and then by hash so that the cursor is stable.
+func less(a, b *entry) bool {
+	if a.blockNumber() != b.blockNumber() {
+		return a.blockNumber() < b.blockNumber()
+	}
+	return a.alert.Hash < b.alert.Hash
+}
+
+// candidates narrows down the alerts by using the lookup maps.
+func (idx *Index) candidates(input *graphql.AlertsInput) []*entry {
+	switch {
+	case len(input.AlertHash) > 0:
+		if e, ok := idx.byHash[input.AlertHash]; ok {
+			return []*entry{e}
+		}
+		return nil
+
+	case len(input.Bots) > 0:
+		var entries []*entry
+		seen := make(map[string]bool)
+		for _, botID := range input.Bots {
+			botID = strings.ToLower(botID)
+			if seen[botID] {
+				continue
+			}
+			seen[botID] = true
+			entries = append(entries, idx.byBot[botID]...)
+		}
+		return entries
+
+	case len(input.AlertId) > 0 && len(input.AlertIds) == 0:
+		return idx.byAlertID[input.AlertId]
+
+	default:
+		return idx.entries
+	}
+}
+
+// filter checks the alerts against the input.
+type filter struct {
+	input        *graphql.AlertsInput
+	alertIDs     map[string]bool
+	severities   map[string]bool
+	oldest       time.Time
+	newest       time.Time
+	blockFrom    time.Time
+	blockTo      time.Time
+	hasBlockTime bool
+}
+
+func newFilter(input *graphql.AlertsInput, retention time.Duration, now time.Time) *filter {
+	f := &filter{
+		input:  input,
+		oldest: now.Add(-retention),
+	}
+	if len(input.AlertId) > 0 || len(input.AlertIds) > 0 {
+		f.alertIDs = make(map[string]bool)
+		if len(input.AlertId) > 0 {
+			f.alertIDs[input.AlertId] = true
+		}
+		for _, alertID := range input.AlertIds {
+			f.alertIDs[alertID] = true
+		}
+	}
+	if len(input.Severities) > 0 {
+		f.severities = make(map[string]bool)
+		for _, severity := range input.Severities {
+			f.severities[strings.ToUpper(severity)] = true
+		}
+	}
+	if input.CreatedSince > 0 {
+		since := now.Add(-time.Duration(input.CreatedSince) * time.Millisecond)
+		if since.After(f.oldest) {
+			f.oldest = since
+		}
+	}
+	if input.CreatedBefore > 0 {
+		f.newest = now.Add(-time.Duration(input.CreatedBefore) * time.Millisecond)
+	}
+	if r := input.BlockTimestampRange; r != nil {
+		f.hasBlockTime = true
+		f.blockFrom = time.UnixMilli(int64(r.StartTimestamp))
+		if r.EndTimestamp > 0 {
+			f.blockTo = time.UnixMilli(int64(r.EndTimestamp))
+		}
+	}
+	if r := input.BlockDateRange; r != nil {
+		f.hasBlockTime = true
+		// the dates default to the query date
+		startDate, err := time.Parse(dateLayout, r.StartDate)
+		if err != nil {
+			startDate = now.Truncate(24 * time.Hour)
+		}
+		endDate, err := time.Parse(dateLayout, r.EndDate)
+		if err != nil {
+			endDate = now.Truncate(24 * time.Hour)
+		}
+		f.blockFrom = startDate
+		f.blockTo = endDate.Add(24*time.Hour - time.Nanosecond)
+	}
+	return f
+}
+
+func (f *filter) match(e *entry) bool {
+	input := f.input
+	alert := e.alert
+
+	if e.createdAt.Before(f.oldest) {
+		return false
+	}
+	if !f.newest.IsZero() && e.createdAt.After(f.newest) {
+		return false
+	}
+	if len(input.AlertHash) > 0 && alert.Hash != input.AlertHash {
+		return false
+	}
+	if f.alertIDs != nil && !f.alertIDs[alert.AlertId] {
+		return false
+	}
+	if len(input.AlertName) > 0 && alert.Name != input.AlertName {
+		return false
+	}
+	if input.ChainId > 0 && alert.ChainId != uint64(input.ChainId) {
+		return false
+	}
+	if f.severities != nil && !f.severities[strings.ToUpper(alert.Severity)] {
+		return false
+	}
+	if len(input.TransactionHash) > 0 &&
+		(alert.Source == nil || !strings.EqualFold(alert.Source.TransactionHash, input.TransactionHash)) {
+		return false
+	}
+	if r := input.BlockNumberRange; r != nil {
+		blockNumber := e.blockNumber()
+		if blockNumber < uint64(r.StartBlockNumber) || (r.EndBlockNumber > 0 && blockNumber > uint64(r.EndBlockNumber)) {
+			return false
+		}
+	}
+	if f.hasBlockTime && !f.matchBlockTime(e) {
+		return false
+	}
+	if r := input.ScanNodeConfirmations; r != nil {
+		count := uint(alert.ScanNodeCount)
+		if count < r.Gte || (r.Lte > 0 && count > r.Lte) {
+			return false
+		}
+	}
+	if len(input.ProjectId) > 0 && !hasProject(alert, input.ProjectId) {
+		return false
+	}
+	if len(input.Addresses) > 0 && !hasAnyAddress(e, input.Addresses) {
+		return false
+	}
+	return true
+}
+
+func (f *filter) matchBlockTime(e *entry) bool {
+	if e.alert.Source == nil || e.alert.Source.Block == nil {
+		return false
+	}
+	blockTime, err := time.Parse(time.RFC3339, e.alert.Source.Block.Timestamp)
+	if err != nil {
+		return false
+	}
+	if blockTime.Before(f.blockFrom) {
+		return false
+	}
+	return f.blockTo.IsZero() || !blockTime.After(f.blockTo)
+}
+
+func hasProject(alert *protocol.AlertEvent_Alert, projectID string) bool {
+	for _, project := range alert.Projects {
+		if project.Id == projectID {
+			return true
+		}
+	}
+	for _, contract := range alert.Contracts {
+		if contract.ProjectId == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyAddress(e *entry, addresses []string) bool {
+	for _, address := range addresses {
+		if e.hasAddress(address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,391 @@
+package alertindex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/protocol"
+)
+
+// The alerts are converted to the objects of the public GraphQL schema before the fields which
+// are not in the selection set of the query are dropped. The objects are kept as
+// map[string]interface{} and the lists of objects as []interface{} so that they can be told apart
+// from the scalar values.
+
+func toSchemaPageInfo(pageInfo *graphql.PageInfo) map[string]interface{} {
+	var endCursor map[string]interface{}
+	if pageInfo.EndCursor != nil {
+		endCursor = map[string]interface{}{
+			"alertId":     pageInfo.EndCursor.AlertId,
+			"blockNumber": pageInfo.EndCursor.BlockNumber,
+		}
+	}
+	return map[string]interface{}{
+		"hasNextPage": pageInfo.HasNextPage,
+		"endCursor":   endCursor,
+	}
+}
+
+func toSchemaAlerts(alerts []*protocol.AlertEvent_Alert) []interface{} {
+	result := make([]interface{}, 0, len(alerts))
+	for _, alert := range alerts {
+		result = append(result, toSchemaAlert(alert))
+	}
+	return result
+}
+
+func toSchemaAlert(alert *protocol.AlertEvent_Alert) map[string]interface{} {
+	var contracts []interface{}
+	for _, contract := range alert.Contracts {
+		contracts = append(contracts, map[string]interface{}{
+			"name":      contract.Name,
+			"projectId": contract.ProjectId,
+		})
+	}
+	var projects []interface{}
+	for _, project := range alert.Projects {
+		projects = append(projects, map[string]interface{}{
+			"id": project.Id,
+		})
+	}
+	var labels []interface{}
+	for _, label := range alert.Labels {
+		labels = append(labels, map[string]interface{}{
+			"label":      label.Label,
+			"confidence": label.Confidence,
+			"entity":     label.Entity,
+			"entityType": label.EntityType,
+			"remove":     label.Remove,
+			"metadata":   label.Metadata,
+		})
+	}
+	var addressBloomFilter map[string]interface{}
+	if bf := alert.AddressBloomFilter; bf != nil {
+		addressBloomFilter = map[string]interface{}{
+			"bitset":    bf.Bitset,
+			"itemCount": bf.ItemCount,
+			"k":         bf.K,
+			"m":         bf.M,
+		}
+	}
+
+	return map[string]interface{}{
+		"alertId":            alert.AlertId,
+		"addresses":          alert.Addresses,
+		"contracts":          contracts,
+		"createdAt":          alert.CreatedAt,
+		"description":        alert.Description,
+		"hash":               alert.Hash,
+		"metadata":           alert.Metadata,
+		"name":               alert.Name,
+		"projects":           projects,
+		"protocol":           nil,
+		"scanNodeCount":      alert.ScanNodeCount,
+		"severity":           alert.Severity,
+		"source":             toSchemaSource(alert.Source),
+		"alertDocumentType":  nil,
+		"findingType":        alert.FindingType,
+		"relatedAlerts":      alert.RelatedAlerts,
+		"chainId":            alert.ChainId,
+		"labels":             labels,
+		"truncated":          alert.Truncated,
+		"addressBloomFilter": addressBloomFilter,
+	}
+}
+
+func toSchemaSource(source *protocol.AlertEvent_Alert_Source) map[string]interface{} {
+	if source == nil {
+		return nil
+	}
+	var bot map[string]interface{}
+	if b := source.Bot; b != nil {
+		bot = map[string]interface{}{
+			"chainIds":     b.ChainIds,
+			"createdAt":    b.CreatedAt,
+			"description":  b.Description,
+			"developer":    b.Developer,
+			"docReference": b.DocReference,
+			"enabled":      b.Enabled,
+			"id":           b.Id,
+			"image":        b.Image,
+			"name":         b.Name,
+			"reference":    b.Reference,
+			"repository":   b.Repository,
+			"projects":     b.Projects,
+			"scanNodes":    b.ScanNodes,
+			"version":      b.Version,
+		}
+	}
+	var block map[string]interface{}
+	if b := source.Block; b != nil {
+		block = map[string]interface{}{
+			"number":    b.Number,
+			"hash":      b.Hash,
+			"timestamp": b.Timestamp,
+			"chainId":   b.ChainId,
+		}
+	}
+	var sourceAlert map[string]interface{}
+	if e := source.SourceEvent; e != nil {
+		var chainID interface{}
+		if id, err := strconv.ParseUint(e.ChainId, 10, 64); err == nil {
+			chainID = id
+		}
+		sourceAlert = map[string]interface{}{
+			"hash":      e.AlertHash,
+			"botId":     e.BotId,
+			"timestamp": e.Timestamp,
+			"chainId":   chainID,
+		}
+	}
+	return map[string]interface{}{
+		"transactionHash": source.TransactionHash,
+		"bot":             bot,
+		"block":           block,
+		"sourceAlert":     sourceAlert,
+	}
+}
+
+// field is a field in the selection set of a query.
+type field struct {
+	name       string
+	alias      string
+	selections []*field
+}
+
+func (f *field) responseKey() string {
+	if len(f.alias) > 0 {
+		return f.alias
+	}
+	return f.name
+}
+
+// parseQuery parses the selection set of the operation in the query. The arguments are skipped
+// since the input is read from the variables. The fragments and the directives are not supported.
+func parseQuery(query string) ([]*field, error) {
+	p := &queryParser{tokens: tokenize(query)}
+	// skip the operation type, the name and the variable definitions
+	for p.peek() != "{" {
+		if p.peek() == "" {
+			return nil, fmt.Errorf("query has no selection set")
+		}
+		if p.next() == "(" {
+			if err := p.skipArguments(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	fields, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "" {
+		return nil, fmt.Errorf("only a single operation is supported")
+	}
+	return fields, nil
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+// skipArguments skips the tokens until the closing parenthesis of an opened one.
+func (p *queryParser) skipArguments() error {
+	depth := 1
+	for depth > 0 {
+		switch p.next() {
+		case "":
+			return fmt.Errorf("unexpected end of query")
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *queryParser) parseSelectionSet() ([]*field, error) {
+	if token := p.next(); token != "{" {
+		return nil, fmt.Errorf("expected '{' but found '%s'", token)
+	}
+	var fields []*field
+	for p.peek() != "}" {
+		token := p.next()
+		switch {
+		case token == "":
+			return nil, fmt.Errorf("unexpected end of query")
+		case token == "...":
+			return nil, fmt.Errorf("fragments are not supported")
+		case !isName(token):
+			return nil, fmt.Errorf("unexpected '%s' in selection set", token)
+		}
+
+		f := &field{name: token}
+		if p.peek() == ":" {
+			p.next()
+			f.alias = f.name
+			f.name = p.next()
+			if !isName(f.name) {
+				return nil, fmt.Errorf("unexpected '%s' after alias '%s'", f.name, f.alias)
+			}
+		}
+		if p.peek() == "(" {
+			p.next()
+			if err := p.skipArguments(); err != nil {
+				return nil, err
+			}
+		}
+		if strings.HasPrefix(p.peek(), "@") {
+			return nil, fmt.Errorf("directives are not supported")
+		}
+		if p.peek() == "{" {
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			f.selections = selections
+		}
+		fields = append(fields, f)
+	}
+	p.next()
+	return fields, nil
+}
+
+func isName(token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+	for i, r := range token {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits the query into the names, the punctuators and the string values. The commas
+// and the comments are ignored like the white space.
+func tokenize(query string) []string {
+	var tokens []string
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			i++
+
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			if i > len(runes) {
+				i = len(runes)
+			}
+			tokens = append(tokens, string(runes[start:i]))
+
+		case r == '.' && i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.':
+			tokens = append(tokens, "...")
+			i += 3
+
+		case strings.ContainsRune("{}():=![]", r):
+			tokens = append(tokens, string(r))
+			i++
+
+		default:
+			start := i
+			i++
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '-' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+	return tokens
+}
+
+// selectFields keeps the fields of the value which are in the selection set.
+func selectFields(value interface{}, fields []*field, typeName string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		result := make(map[string]interface{})
+		for _, f := range fields {
+			fieldValue, ok := v[f.name]
+			if !ok {
+				return nil, fmt.Errorf("cannot query field '%s' on type '%s'", f.name, typeName)
+			}
+			isObject := isObjectValue(fieldValue)
+			switch {
+			case isObject && f.selections == nil:
+				return nil, fmt.Errorf("field '%s' of type '%s' must have a selection of subfields", f.name, typeName)
+			case !isObject && f.selections != nil:
+				return nil, fmt.Errorf("field '%s' of type '%s' must not have a selection", f.name, typeName)
+			case isObject:
+				selected, err := selectFields(fieldValue, f.selections, f.name)
+				if err != nil {
+					return nil, err
+				}
+				fieldValue = selected
+			}
+			result[f.responseKey()] = fieldValue
+		}
+		return result, nil
+
+	case []interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			selected, err := selectFields(item, fields, typeName)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, selected)
+		}
+		return result, nil
+
+	default:
+		return value, nil
+	}
+}
+
+func isObjectValue(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
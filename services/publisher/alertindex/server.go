@@ -0,0 +1,127 @@
+package alertindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxRequestSize = 1 << 20
+
+type graphqlRequest struct {
+	OperationName string `json:"operationName"`
+	Query         string `json:"query"`
+	Variables     struct {
+		Input *graphql.AlertsInput `json:"input"`
+	} `json:"variables"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type graphqlResponse struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []graphqlError         `json:"errors,omitempty"`
+}
+
+// Server serves the alerts from the index with the alerts query of the public GraphQL API. The
+// alerts are returned with the field names of the public schema and only with the selected fields.
+type Server struct {
+	index  *Index
+	port   string
+	server *http.Server
+}
+
+// NewServer creates a new server.
+func NewServer(index *Index, port string) *Server {
+	return &Server{
+		index: index,
+		port:  port,
+	}
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	router := mux.NewRouter()
+	router.HandleFunc("/graphql", s.handleGraphQL).Methods(http.MethodPost)
+	return router
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	var req graphqlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	fields, err := parseQuery(req.Query)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "invalid query: "+err.Error())
+		return
+	}
+
+	data := make(map[string]interface{})
+	for _, f := range fields {
+		if f.name != "alerts" {
+			writeErrors(w, http.StatusBadRequest, fmt.Sprintf("only the alerts query is supported: cannot query field '%s'", f.name))
+			return
+		}
+		if f.selections == nil {
+			writeErrors(w, http.StatusBadRequest, "field 'alerts' must have a selection of subfields")
+			return
+		}
+		alerts, pageInfo := s.index.Query(req.Variables.Input)
+		alertsResult, err := selectFields(map[string]interface{}{
+			"pageInfo": toSchemaPageInfo(pageInfo),
+			"alerts":   toSchemaAlerts(alerts),
+		}, f.selections, "AlertsResponse")
+		if err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		data[f.responseKey()] = alertsResult
+	}
+	writeResponse(w, http.StatusOK, &graphqlResponse{Data: data})
+}
+
+func writeErrors(w http.ResponseWriter, code int, message string) {
+	writeResponse(w, code, &graphqlResponse{Errors: []graphqlError{{Message: message}}})
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp *graphqlResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("failed to write alert index response")
+	}
+}
+
+// Start starts the server.
+func (s *Server) Start() error {
+	s.server = &http.Server{
+		Addr:    ":" + s.port,
+		Handler: s.Handler(),
+	}
+	utils.GoListenAndServe(s.server)
+	return nil
+}
+
+// Stop stops the server.
+func (s *Server) Stop() error {
+	if s.server != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+// Name returns the name of the server.
+func (s *Server) Name() string {
+	return "alert-index"
+}
@@ -0,0 +1,141 @@
+package alertindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/protocol"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GraphQLClient(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	for i := 1; i <= 5; i++ {
+		r.NoError(idx.Put(testAlert(fmt.Sprintf("0x%d", i), "0xbot1", "ALERT", uint64(i))))
+	}
+	r.NoError(idx.Put(testAlert("0x6", "0xbot2", "ALERT", 6)))
+
+	server := httptest.NewServer(NewServer(idx, "").Handler())
+	defer server.Close()
+
+	// the client should follow the pages
+	client := graphql.NewClient(server.URL + "/graphql")
+	alertEvents, err := client.GetAlerts(context.Background(), &graphql.AlertsInput{
+		Bots:  []string{"0xbot1"},
+		First: 2,
+	}, nil)
+	r.NoError(err)
+	r.Len(alertEvents, 5)
+	r.Equal("0x5", alertEvents[0].Alert.Hash)
+	r.Equal("0x1", alertEvents[4].Alert.Hash)
+	r.Equal(uint64(1), alertEvents[4].Alert.Source.Block.Number)
+	r.Equal("0xbot1", alertEvents[4].Alert.Source.Bot.Id)
+	r.NotEmpty(alertEvents[4].Timestamps.SourceAlert)
+}
+
+func TestServer_UnsupportedQuery(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	server := httptest.NewServer(NewServer(idx, "").Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/graphql", "application/json", bytes.NewBufferString(`{"query":"{ bots { id } }"}`))
+	r.NoError(err)
+	defer resp.Body.Close()
+	r.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_SchemaFields(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	r.NoError(err)
+	defer idx.Close()
+
+	alert := testAlert("0x1", "0xbot1", "ALERT", 1)
+	alert.Source.SourceEvent = &protocol.AlertEvent_Alert_SourceAlertEvent{AlertHash: "0xsource", BotId: "0xbot2", ChainId: "1"}
+	alert.AddressBloomFilter = &protocol.BloomFilter{K: "1", M: "2"}
+	r.NoError(idx.Put(alert))
+
+	server := httptest.NewServer(NewServer(idx, "").Handler())
+	defer server.Close()
+
+	query := `query getAlerts($input: AlertsInput) {
+		result: alerts(input: $input) {
+			pageInfo { hasNextPage }
+			alerts {
+				hash
+				source {
+					block { number }
+					sourceAlert { hash botId chainId }
+				}
+			}
+		}
+	}`
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": map[string]interface{}{"input": map[string]interface{}{}}})
+	r.NoError(err)
+	resp, err := http.Post(server.URL+"/graphql", "application/json", bytes.NewReader(body))
+	r.NoError(err)
+	defer resp.Body.Close()
+	r.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	r.Equal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"result": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": false},
+				"alerts": []interface{}{
+					map[string]interface{}{
+						"hash": "0x1",
+						"source": map[string]interface{}{
+							"block":       map[string]interface{}{"number": float64(1)},
+							"sourceAlert": map[string]interface{}{"hash": "0xsource", "botId": "0xbot2", "chainId": float64(1)},
+						},
+					},
+				},
+			},
+		},
+	}, result)
+}
+
+func TestServer_InvalidSelection(t *testing.T) {
+	idx, err := NewIndex(t.TempDir(), time.Hour, testMaxSize)
+	require.NoError(t, err)
+	defer idx.Close()
+	require.NoError(t, idx.Put(testAlert("0x1", "0xbot1", "ALERT", 1)))
+
+	server := httptest.NewServer(NewServer(idx, "").Handler())
+	defer server.Close()
+
+	for _, query := range []string{
+		`{ alerts { alerts { sourceEvent } } }`,
+		`{ alerts { alerts { source } } }`,
+		`{ alerts { alerts { hash { id } } } }`,
+		`{ alerts { ...fields } }`,
+	} {
+		body, err := json.Marshal(map[string]interface{}{"query": query})
+		require.NoError(t, err)
+		resp, err := http.Post(server.URL+"/graphql", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
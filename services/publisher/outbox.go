@@ -0,0 +1,253 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zktoro/zktoro-core-go/domain"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	outboxDirName        = ".outbox"
+	outboxEntryExt       = ".json"
+	outboxCorruptExt     = ".corrupt"
+	outboxDeadLetterExt  = ".dead"
+	outboxEntryNameWidth = 20
+
+	// the dead-lettered and corrupt entries are kept for inspection within these limits
+	defaultSetAsideRetention = time.Hour * 24 * 7
+	defaultMaxSetAside       = 1000
+)
+
+// OutboxEntry is a signed batch request waiting for a receipt from the alert API.
+type OutboxEntry struct {
+	Seq       uint64                    `json:"seq"`
+	CreatedAt time.Time                 `json:"createdAt"`
+	Request   *domain.AlertBatchRequest `json:"request"`
+}
+
+// OutboxStats summarizes the pending entries.
+type OutboxStats struct {
+	Depth         int
+	OldestPending *time.Time
+}
+
+// Outbox is a durable FIFO queue of signed batches. Each entry is stored as a single
+// file in the outbox dir and is removed only after the batch is acknowledged.
+type Outbox struct {
+	dir     string
+	lastSeq uint64
+
+	setAsideRetention time.Duration
+	maxSetAside       int
+
+	pending []uint64
+	oldest  map[uint64]time.Time
+	mu      sync.Mutex
+}
+
+// NewOutbox creates the outbox dir if needed and loads the entries left from a previous run.
+func NewOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox dir: %v", err)
+	}
+	ob := &Outbox{
+		dir:               dir,
+		setAsideRetention: defaultSetAsideRetention,
+		maxSetAside:       defaultMaxSetAside,
+		oldest:            make(map[uint64]time.Time),
+	}
+	if err := ob.load(); err != nil {
+		return nil, err
+	}
+	ob.pruneSetAside()
+	return ob, nil
+}
+
+func (ob *Outbox) load() error {
+	dirEntries, err := os.ReadDir(ob.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read outbox dir: %v", err)
+	}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !strings.HasSuffix(name, outboxEntryExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, outboxEntryExt), 10, 64)
+		if err != nil {
+			log.WithField("file", name).Warn("ignoring unknown file in outbox dir")
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat outbox entry: %v", err)
+		}
+		ob.pending = append(ob.pending, seq)
+		ob.oldest[seq] = info.ModTime()
+		if seq > ob.lastSeq {
+			ob.lastSeq = seq
+		}
+	}
+	sort.Slice(ob.pending, func(i, j int) bool {
+		return ob.pending[i] < ob.pending[j]
+	})
+	return nil
+}
+
+func (ob *Outbox) entryPath(seq uint64) string {
+	return path.Join(ob.dir, fmt.Sprintf("%0*d%s", outboxEntryNameWidth, seq, outboxEntryExt))
+}
+
+// Put persists the request as the newest entry.
+func (ob *Outbox) Put(req *domain.AlertBatchRequest) (*OutboxEntry, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	entry := &OutboxEntry{
+		Seq:       ob.lastSeq + 1,
+		CreatedAt: time.Now().UTC(),
+		Request:   req,
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox entry: %v", err)
+	}
+
+	// write to a temp file first so that a crash never leaves a partial entry behind
+	entryPath := ob.entryPath(entry.Seq)
+	tmpPath := entryPath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write outbox entry: %v", err)
+	}
+	if err := os.Rename(tmpPath, entryPath); err != nil {
+		return nil, fmt.Errorf("failed to move outbox entry: %v", err)
+	}
+
+	ob.lastSeq = entry.Seq
+	ob.pending = append(ob.pending, entry.Seq)
+	ob.oldest[entry.Seq] = entry.CreatedAt
+	return entry, nil
+}
+
+// Peek returns the oldest entry without removing it. It returns nil if the outbox is empty.
+// Entries that cannot be decoded are set aside so they do not block the rest of the queue.
+func (ob *Outbox) Peek() (*OutboxEntry, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	for len(ob.pending) > 0 {
+		seq := ob.pending[0]
+		entryPath := ob.entryPath(seq)
+		b, err := os.ReadFile(entryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outbox entry: %v", err)
+		}
+		var entry OutboxEntry
+		if err := json.Unmarshal(b, &entry); err == nil && entry.Request != nil {
+			entry.Seq = seq
+			return &entry, nil
+		}
+		log.WithField("file", entryPath).Error("found corrupt outbox entry - setting aside")
+		if err := os.Rename(entryPath, entryPath+outboxCorruptExt); err != nil {
+			return nil, fmt.Errorf("failed to set aside corrupt outbox entry: %v", err)
+		}
+		ob.drop(seq)
+		ob.pruneSetAside()
+	}
+	return nil, nil
+}
+
+// Remove deletes the entry after it is acknowledged.
+func (ob *Outbox) Remove(entry *OutboxEntry) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if err := os.Remove(ob.entryPath(entry.Seq)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove outbox entry: %v", err)
+	}
+	ob.drop(entry.Seq)
+	return nil
+}
+
+// DeadLetter sets the entry aside after the alert API rejected it so that it does not block
+// the rest of the queue. The entry file is kept for inspection until it is pruned.
+func (ob *Outbox) DeadLetter(entry *OutboxEntry) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	entryPath := ob.entryPath(entry.Seq)
+	if err := os.Rename(entryPath, entryPath+outboxDeadLetterExt); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to dead-letter outbox entry: %v", err)
+	}
+	ob.drop(entry.Seq)
+	ob.pruneSetAside()
+	return nil
+}
+
+// pruneSetAside removes the dead-lettered and corrupt entries which are older than the retention
+// and the oldest ones above the max count so that they do not fill the disk.
+func (ob *Outbox) pruneSetAside() {
+	dirEntries, err := os.ReadDir(ob.dir)
+	if err != nil {
+		log.WithError(err).Warn("failed to read outbox dir for pruning")
+		return
+	}
+	var kept []string
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || !(strings.HasSuffix(name, outboxDeadLetterExt) || strings.HasSuffix(name, outboxCorruptExt)) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err == nil && time.Since(info.ModTime()) <= ob.setAsideRetention {
+			kept = append(kept, name)
+			continue
+		}
+		ob.removeSetAside(name)
+	}
+	// the names start with the sequence numbers so they sort from the oldest
+	sort.Strings(kept)
+	for len(kept) > ob.maxSetAside {
+		ob.removeSetAside(kept[0])
+		kept = kept[1:]
+	}
+}
+
+func (ob *Outbox) removeSetAside(name string) {
+	if err := os.Remove(path.Join(ob.dir, name)); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("file", name).Warn("failed to prune outbox entry")
+	}
+}
+
+func (ob *Outbox) drop(seq uint64) {
+	for i, pendingSeq := range ob.pending {
+		if pendingSeq == seq {
+			ob.pending = append(ob.pending[:i], ob.pending[i+1:]...)
+			break
+		}
+	}
+	delete(ob.oldest, seq)
+}
+
+// Stats returns the queue depth and the creation time of the oldest pending entry.
+func (ob *Outbox) Stats() OutboxStats {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	stats := OutboxStats{Depth: len(ob.pending)}
+	if len(ob.pending) > 0 {
+		oldest := ob.oldest[ob.pending[0]]
+		stats.OldestPending = &oldest
+	}
+	return stats
+}
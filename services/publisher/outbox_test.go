@@ -0,0 +1,136 @@
+package publisher
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"zktoro/zktoro-core-go/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_Order(t *testing.T) {
+	r := require.New(t)
+
+	ob, err := NewOutbox(t.TempDir())
+	r.NoError(err)
+
+	entry, err := ob.Peek()
+	r.NoError(err)
+	r.Nil(entry)
+	r.Equal(0, ob.Stats().Depth)
+	r.Nil(ob.Stats().OldestPending)
+
+	_, err = ob.Put(&domain.AlertBatchRequest{Ref: "ref1"})
+	r.NoError(err)
+	_, err = ob.Put(&domain.AlertBatchRequest{Ref: "ref2"})
+	r.NoError(err)
+
+	stats := ob.Stats()
+	r.Equal(2, stats.Depth)
+	r.NotNil(stats.OldestPending)
+
+	entry, err = ob.Peek()
+	r.NoError(err)
+	r.Equal("ref1", entry.Request.Ref)
+
+	// peeking again should return the same entry until it is removed
+	entry, err = ob.Peek()
+	r.NoError(err)
+	r.Equal("ref1", entry.Request.Ref)
+
+	r.NoError(ob.Remove(entry))
+	entry, err = ob.Peek()
+	r.NoError(err)
+	r.Equal("ref2", entry.Request.Ref)
+	r.Equal(1, ob.Stats().Depth)
+}
+
+func TestOutbox_Reload(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	ob, err := NewOutbox(dir)
+	r.NoError(err)
+	first, err := ob.Put(&domain.AlertBatchRequest{Ref: "ref1"})
+	r.NoError(err)
+	_, err = ob.Put(&domain.AlertBatchRequest{Ref: "ref2"})
+	r.NoError(err)
+	r.NoError(ob.Remove(first))
+
+	// simulate a restart
+	ob, err = NewOutbox(dir)
+	r.NoError(err)
+	r.Equal(1, ob.Stats().Depth)
+
+	entry, err := ob.Peek()
+	r.NoError(err)
+	r.Equal("ref2", entry.Request.Ref)
+
+	// new entries should continue after the highest existing sequence
+	third, err := ob.Put(&domain.AlertBatchRequest{Ref: "ref3"})
+	r.NoError(err)
+	r.Greater(third.Seq, entry.Seq)
+}
+
+func TestOutbox_CorruptEntry(t *testing.T) {
+	r := require.New(t)
+
+	ob, err := NewOutbox(t.TempDir())
+	r.NoError(err)
+	corrupt, err := ob.Put(&domain.AlertBatchRequest{Ref: "ref1"})
+	r.NoError(err)
+	_, err = ob.Put(&domain.AlertBatchRequest{Ref: "ref2"})
+	r.NoError(err)
+
+	r.NoError(os.WriteFile(ob.entryPath(corrupt.Seq), []byte("{"), 0644))
+
+	entry, err := ob.Peek()
+	r.NoError(err)
+	r.Equal("ref2", entry.Request.Ref)
+	r.Equal(1, ob.Stats().Depth)
+
+	_, err = os.Stat(ob.entryPath(corrupt.Seq) + outboxCorruptExt)
+	r.NoError(err)
+}
+
+func TestOutbox_PruneSetAside(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	ob, err := NewOutbox(dir)
+	r.NoError(err)
+	ob.maxSetAside = 2
+
+	var entries []*OutboxEntry
+	for i := 0; i < 4; i++ {
+		entry, err := ob.Put(&domain.AlertBatchRequest{Ref: fmt.Sprintf("ref%d", i)})
+		r.NoError(err)
+		entries = append(entries, entry)
+	}
+	for _, entry := range entries[:3] {
+		r.NoError(ob.DeadLetter(entry))
+	}
+
+	// only the newest dead letters are kept
+	_, err = os.Stat(ob.entryPath(entries[0].Seq) + outboxDeadLetterExt)
+	r.True(os.IsNotExist(err))
+	for _, entry := range entries[1:3] {
+		_, err = os.Stat(ob.entryPath(entry.Seq) + outboxDeadLetterExt)
+		r.NoError(err)
+	}
+
+	// the expired dead letters are removed on restart and the pending entries are kept
+	expired := time.Now().Add(-defaultSetAsideRetention - time.Hour)
+	r.NoError(os.Chtimes(ob.entryPath(entries[1].Seq)+outboxDeadLetterExt, expired, expired))
+	r.NoError(os.Chtimes(ob.entryPath(entries[3].Seq), expired, expired))
+	ob, err = NewOutbox(dir)
+	r.NoError(err)
+	_, err = os.Stat(ob.entryPath(entries[1].Seq) + outboxDeadLetterExt)
+	r.True(os.IsNotExist(err))
+	_, err = os.Stat(ob.entryPath(entries[2].Seq) + outboxDeadLetterExt)
+	r.NoError(err)
+	r.Equal(1, ob.Stats().Depth)
+}
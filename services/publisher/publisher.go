@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,14 +21,17 @@ import (
 	"zktoro/clients/storagegrpc"
 	"zktoro/config"
 	"zktoro/services/components/metrics"
+	"zktoro/services/publisher/alertindex"
+	"zktoro/services/publisher/sinks"
 	"zktoro/services/publisher/webhooklog"
 	"zktoro/services/storage"
 	"zktoro/store"
 
 	"zktoro/zktoro-core-go/clients/health"
 	"zktoro/zktoro-core-go/clients/webhook"
-	"zktoro/zktoro-core-go/clients/webhook/client/operations"
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
 	"zktoro/zktoro-core-go/domain"
+	"zktoro/zktoro-core-go/encoding"
 	"zktoro/zktoro-core-go/ipfs"
 	"zktoro/zktoro-core-go/protocol"
 	"zktoro/zktoro-core-go/protocol/transform"
@@ -34,7 +39,7 @@ import (
 	"zktoro/zktoro-core-go/security"
 	"zktoro/zktoro-core-go/utils"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	backoff "github.com/cenkalti/backoff/v4"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	ipfsapi "github.com/ipfs/go-ipfs-api"
@@ -43,11 +48,15 @@ import (
 )
 
 const (
-	defaultInterval            = time.Second * 15
-	defaultBatchLimit          = 500
-	defaultBatchBufferSize     = 100
-	defaultBatchSendRetryTimes = 3
-	defaultBatchSendRetryDelay = time.Second * 3
+	defaultInterval         = time.Second * 15
+	defaultBatchLimit       = 500
+	defaultBatchBufferSize  = 100
+	defaultOutboxMinBackoff = time.Second * 3
+	defaultOutboxMaxBackoff = time.Minute * 5
+	defaultSinkMinBackoff   = time.Second
+	defaultSinkRetryTimeout = time.Minute
+
+	alertIndexDirName = ".alert-index"
 
 	fastReportInterval = time.Minute
 	slowReportInterval = time.Minute * 15
@@ -64,12 +73,22 @@ type Publisher struct {
 	metricsAggregator *AgentMetricsAggregator
 	messageClient     clients.MessageClient
 	alertClient       clients.AlertAPIClient
-	localAlertClient  LocalAlertClient
+	alertSinks        []sinks.AlertSink
+	alertSinksGen     uint64
+	alertSinksMu      sync.RWMutex
 
 	lifecycleMetrics metrics.Lifecycle
 
 	batchRefStore    store.StringStore
 	lastReceiptStore store.StringStore
+	outbox           *Outbox
+	outboxCh         chan struct{}
+	didLinkage       string
+
+	alertIndex       *alertindex.Index
+	alertIndexServer *alertindex.Server
+	alertListeners   []func(alert *protocol.AlertEvent_Alert)
+	alertListenersMu sync.RWMutex
 
 	server *grpc.Server
 
@@ -88,6 +107,7 @@ type Publisher struct {
 	lastBatchSkipReason     health.MessageTracker
 	lastBatchPublishErr     health.ErrorTracker
 	lastMetricsFlush        health.TimeTracker
+	lastOutboxSendErr       health.ErrorTracker
 
 	// these help following single ticker and keep send intervals on track
 	batchTicker          *time.Ticker
@@ -105,9 +125,6 @@ type Publisher struct {
 	latestInspectionResultsMu sync.RWMutex
 }
 
-// LocalAlertClient sends the local alerts.
-type LocalAlertClient webhook.AlertWebhookClient
-
 // StorageClient stores content.
 type StorageClient protocol.StorageClient
 
@@ -128,7 +145,7 @@ type IPFS interface {
 
 type PublisherConfig struct {
 	ChainID         int
-	Key             *keystore.Key
+	Signer          security.Signer
 	PublisherConfig config.PublisherConfig
 	ReleaseSummary  *release.ReleaseSummary
 	Config          config.Config
@@ -168,6 +185,12 @@ func (pub *Publisher) publishNextBatch(batch *protocol.AlertBatch) (published bo
 			AutoUpdates: !pub.cfg.Config.AutoUpdate.Disable,
 		}
 	}
+	if len(pub.didLinkage) > 0 {
+		if batch.ScannerVersion == nil {
+			batch.ScannerVersion = &protocol.ScannerVersion{}
+		}
+		batch.ScannerVersion.DidLinkage = pub.didLinkage
+	}
 	lastBatchRef, err := pub.batchRefStore.Get()
 	if err == nil {
 		batch.Parent = lastBatchRef
@@ -181,7 +204,7 @@ func (pub *Publisher) publishNextBatch(batch *protocol.AlertBatch) (published bo
 		batch.LatestBlockInput = batch.BlockEnd
 	}
 
-	signedBatch, err := security.SignBatch(pub.cfg.Key, batch)
+	signedBatch, err := security.SignBatchWithSigner(pub.cfg.Signer, batch)
 	if err != nil {
 		return false, fmt.Errorf("failed to build envelope: %v", err)
 	}
@@ -219,24 +242,20 @@ func (pub *Publisher) publishNextBatch(batch *protocol.AlertBatch) (published bo
 	pub.lastBatchReadyMu.RUnlock()
 
 	if pub.cfg.Config.LocalModeConfig.Enable {
-		scannerJwt, err := security.CreateScannerJWT(
-			pub.cfg.Key, map[string]interface{}{
+		scannerJwt, err := security.CreateScannerJWTWithSigner(
+			pub.cfg.Signer, security.WithDIDLinkage(map[string]interface{}{
 				"localMode": "true",
-			},
+			}, pub.didLinkage),
 		)
+		if err != nil {
+			return false, fmt.Errorf("failed to create local mode scanner jwt: %v", err)
+		}
 		alertBatch := transform.ToWebhookAlertBatch(batch)
 		if !pub.cfg.Config.LocalModeConfig.IncludeMetrics {
 			log.Debug("excluding metrics due to local mode config")
 			alertBatch.Metrics = nil
 		}
-		_, err = pub.localAlertClient.SendAlerts(
-			&operations.SendAlertsParams{
-				Context:       context.Background(),
-				Payload:       alertBatch,
-				Authorization: utils.StringPtr(fmt.Sprintf("Bearer %s", scannerJwt)),
-			},
-		)
-		if err != nil {
+		if err = pub.sendToSinks(alertBatch, scannerJwt); err != nil {
 			log.WithError(err).Error("failed to send local mode alerts")
 			return false, err
 		}
@@ -276,8 +295,8 @@ func (pub *Publisher) publishNextBatch(batch *protocol.AlertBatch) (published bo
 		lastReceipt = lr
 	}
 
-	signedBatchSummary, err := security.SignBatchSummary(
-		pub.cfg.Key, &protocol.BatchSummary{
+	signedBatchSummary, err := security.SignBatchSummaryWithSigner(
+		pub.cfg.Signer, &protocol.BatchSummary{
 			Batch:            cid,
 			ChainId:          batch.ChainId,
 			BlockStart:       batch.BlockStart,
@@ -294,90 +313,257 @@ func (pub *Publisher) publishNextBatch(batch *protocol.AlertBatch) (published bo
 		return false, err
 	}
 
+	scannerAddr := pub.cfg.Signer.Address().Hex()
+
+	// persist the signed batch before sending so that it survives api outages and restarts
+	entry, err := pub.outbox.Put(&domain.AlertBatchRequest{
+		Scanner:            scannerAddr,
+		ChainID:            int64(batch.ChainId),
+		BlockStart:         int64(batch.BlockStart),
+		BlockEnd:           int64(batch.BlockEnd),
+		AlertCount:         int64(batch.AlertCount),
+		MaxSeverity:        int64(batch.MaxSeverity),
+		Ref:                cid,
+		SignedBatch:        signedBatch,
+		SignedBatchSummary: signedBatchSummary,
+	})
 	if err != nil {
-		logger.WithError(err).Error("failed to sign cid")
-		return false, err
+		logger.WithError(err).Error("failed to add batch to outbox")
+		return false, fmt.Errorf("failed to add batch to outbox: %v", err)
 	}
+	logger.WithField("outboxSeq", entry.Seq).Info("added alert batch to outbox")
+	pub.notifyOutbox()
 
-	scannerAddr := pub.cfg.Key.Address.Hex()
+	// the batch counts as published only after the api returns a receipt for it
+	return false, nil
+}
 
-	var resp *domain.AlertBatchResponse
-	for i := 0; i < defaultBatchSendRetryTimes; i++ {
-		scannerJwt, err := security.CreateScannerJWT(
-			pub.cfg.Key, map[string]interface{}{
-				"batch": cid,
-			},
-		)
-		resp, err = pub.alertClient.PostBatch(&domain.AlertBatchRequest{
-			Scanner:            scannerAddr,
-			ChainID:            int64(batch.ChainId),
-			BlockStart:         int64(batch.BlockStart),
-			BlockEnd:           int64(batch.BlockEnd),
-			AlertCount:         int64(batch.AlertCount),
-			MaxSeverity:        int64(batch.MaxSeverity),
-			Ref:                cid,
-			SignedBatch:        signedBatch,
-			SignedBatchSummary: signedBatchSummary,
-		}, scannerJwt)
-		// we only retry timeouts to avoid side effects to other cases
-		if err != nil && strings.Contains(strings.ToLower(err.Error()), "timeout") {
-			logger.WithError(err).Warn("got timeout while sending batch - retrying")
-			time.Sleep(defaultBatchSendRetryDelay)
+func (pub *Publisher) notifyOutbox() {
+	select {
+	case pub.outboxCh <- struct{}{}:
+	default:
+	}
+}
+
+// sendOutboxBatches sends the outbox entries in order and retries the oldest entry
+// with exponential backoff until the alert api acknowledges it. The entries which the
+// alert api rejects are dead-lettered so that they do not block the rest of the queue.
+func (pub *Publisher) sendOutboxBatches() {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = defaultOutboxMinBackoff
+	bo.MaxInterval = defaultOutboxMaxBackoff
+	bo.MaxElapsedTime = 0 // never give up
+
+	for {
+		entry, err := pub.outbox.Peek()
+		if err != nil {
+			log.WithError(err).Error("failed to read next outbox entry")
+		}
+		if err == nil && entry == nil {
+			select {
+			case <-pub.outboxCh:
+				continue
+			case <-pub.ctx.Done():
+				return
+			}
+		}
+
+		if err == nil {
+			err = pub.sendOutboxEntry(entry)
+		}
+		pub.lastOutboxSendErr.Set(err)
+		if err != nil && isPermanentSendError(err) {
+			pub.deadLetter(entry, err)
+			bo.Reset()
 			continue
 		}
-		// do not retry if we got any other error or success
-		break
+		if err != nil {
+			select {
+			case <-time.After(bo.NextBackOff()):
+				continue
+			case <-pub.ctx.Done():
+				return
+			}
+		}
+		bo.Reset()
+		pub.lastBatchPublish.Set()
+		if err := pub.outbox.Remove(entry); err != nil {
+			log.WithError(err).WithField("outboxSeq", entry.Seq).Error("failed to remove sent batch from outbox")
+		}
+	}
+}
+
+// isPermanentSendError tells if the alert api rejected the batch so that retrying cannot succeed.
+func isPermanentSendError(err error) bool {
+	var apiErr *alertapi.APIError
+	return errors.As(err, &apiErr) && apiErr.Permanent()
+}
+
+func (pub *Publisher) deadLetter(entry *OutboxEntry, sendErr error) {
+	logger := log.WithFields(log.Fields{
+		"ref":       entry.Request.Ref,
+		"outboxSeq": entry.Seq,
+	})
+	logger.WithError(sendErr).Error("alert api rejected the batch - dead-lettering")
+	pub.lifecycleMetrics.SystemError("publisher.outbox.dead-letter", sendErr)
+	if err := pub.outbox.DeadLetter(entry); err != nil {
+		logger.WithError(err).Error("failed to dead-letter batch")
 	}
+}
 
+// chainToLastReceipt makes sure that the batch summary points to the receipt of the last
+// batch which was actually sent. The batches queued during an outage are signed before the
+// receipts of the batches ahead of them are known, so the summary is signed again if needed.
+func (pub *Publisher) chainToLastReceipt(req *domain.AlertBatchRequest) error {
+	lastReceipt, err := pub.lastReceiptStore.Get()
+	if err != nil {
+		lastReceipt = ""
+	}
+	var summary protocol.BatchSummary
+	if err := encoding.DecodeGzippedProto(req.SignedBatchSummary.Encoded, &summary); err != nil {
+		return fmt.Errorf("failed to decode batch summary: %v", err)
+	}
+	if summary.PreviousReceipt == lastReceipt {
+		return nil
+	}
+	summary.PreviousReceipt = lastReceipt
+	signedBatchSummary, err := security.SignBatchSummaryWithSigner(pub.cfg.Signer, &summary)
+	if err != nil {
+		return fmt.Errorf("failed to sign batch summary: %v", err)
+	}
+	req.SignedBatchSummary = signedBatchSummary
+	return nil
+}
+
+func (pub *Publisher) sendOutboxEntry(entry *OutboxEntry) error {
+	req := entry.Request
+	logger := log.WithFields(
+		log.Fields{
+			"blockStart":  req.BlockStart,
+			"blockEnd":    req.BlockEnd,
+			"alertCount":  req.AlertCount,
+			"maxSeverity": req.MaxSeverity,
+			"ref":         req.Ref,
+			"outboxSeq":   entry.Seq,
+		},
+	)
+
+	if err := pub.chainToLastReceipt(req); err != nil {
+		logger.WithError(err).Error("failed to chain batch to last receipt")
+		return err
+	}
+
+	scannerJwt, err := security.CreateScannerJWTWithSigner(
+		pub.cfg.Signer, security.WithDIDLinkage(map[string]interface{}{
+			"batch": req.Ref,
+		}, pub.didLinkage),
+	)
+	if err != nil {
+		logger.WithError(err).Error("failed to create scanner jwt")
+		return fmt.Errorf("failed to create scanner jwt: %v", err)
+	}
+
+	resp, err := pub.alertClient.PostBatch(req, scannerJwt)
 	if err != nil {
 		logger.WithError(err).Error("alert while sending batch")
-		return false, fmt.Errorf("failed to send the alert tx: %v", err)
+		return fmt.Errorf("failed to send the alert tx: %w", err)
+	}
+	if resp.SignedReceipt == nil {
+		logger.Warn("alert api did not return a receipt")
+		return nil
 	}
 
-	if resp.SignedReceipt != nil {
-		// store off receipt id
-		if err := pub.lastReceiptStore.Put(resp.ReceiptID); err != nil {
-			logger.WithError(err).Error("failed to marshal receipt")
-			return true, err
-		}
-		logger = logger.WithFields(
-			log.Fields{
-				"receiptId": resp.ReceiptID,
-			},
-		)
+	// store off receipt id
+	if err := pub.lastReceiptStore.Put(resp.ReceiptID); err != nil {
+		logger.WithError(err).Error("failed to marshal receipt")
+	}
+	logger = logger.WithFields(
+		log.Fields{
+			"receiptId": resp.ReceiptID,
+		},
+	)
 
-		// if for some reason receipt can't marshal, log and move on
-		b, err := json.Marshal(resp.SignedReceipt)
-		if err != nil {
-			logger.WithError(err).Error("failed to marshal receipt (not saving receipt)")
-			return true, nil
-		}
-		logger = logger.WithFields(log.Fields{
-			"receipt": string(b),
-		})
+	// if for some reason receipt can't marshal, log and move on
+	b, err := json.Marshal(resp.SignedReceipt)
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal receipt (not saving receipt)")
+		return nil
+	}
+	logger = logger.WithFields(log.Fields{
+		"receipt": string(b),
+	})
 
-		if pub.cfg.Config.AdvancedConfig.IPFSExperiment {
-			ctx, cancel := context.WithTimeout(pub.ctx, time.Second*10)
-			defer cancel()
-			putResp, err := pub.storage.Put(ctx, &protocol.PutRequest{
-				User:  scannerAddr,
-				Kind:  storage.KindBatchReceipt,
-				Bytes: b,
+	if pub.cfg.Config.AdvancedConfig.IPFSExperiment {
+		ctx, cancel := context.WithTimeout(pub.ctx, time.Second*10)
+		defer cancel()
+		putResp, err := pub.storage.Put(ctx, &protocol.PutRequest{
+			User:  req.Scanner,
+			Kind:  storage.KindBatchReceipt,
+			Bytes: b,
+		})
+		if err != nil {
+			logger.WithError(err).Warn("failed to store batch receipt")
+		} else {
+			logger = logger.WithFields(log.Fields{
+				"storedReceiptRef":  putResp.ContentId,
+				"storedReceiptPath": putResp.ContentPath,
 			})
-			if err != nil {
-				logger.WithError(err).Warn("failed to store batch receipt")
-			} else {
-				logger = logger.WithFields(log.Fields{
-					"storedReceiptRef":  putResp.ContentId,
-					"storedReceiptPath": putResp.ContentPath,
-				})
-			}
 		}
 	}
 
 	logger.Info("alert batch")
 
-	return true, nil
+	return nil
+}
+
+// sendToSinks sends the batch to all of the sinks so that a failing sink does not block the others.
+// The failing sinks are retried with backoff for a while before the batch fails. The sinks are not
+// locked during the retries so that a reload can replace them. The batch is sent to all of the new
+// sinks then since the replaced sinks are closed.
+func (pub *Publisher) sendToSinks(batch *models.AlertBatch, scannerJwt string) error {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = defaultSinkMinBackoff
+	bo.MaxElapsedTime = defaultSinkRetryTimeout
+
+	pending, gen := pub.getAlertSinks()
+	return backoff.Retry(func() error {
+		if currSinks, currGen := pub.getAlertSinks(); currGen != gen {
+			pending, gen = currSinks, currGen
+		}
+		var failed []sinks.AlertSink
+		for _, sink := range pending {
+			if err := sink.Send(pub.ctx, batch, scannerJwt); err != nil {
+				log.WithError(err).WithField("sink", sink.Name()).Error("failed to send alerts to sink")
+				failed = append(failed, sink)
+			}
+		}
+		pending = failed
+		if len(failed) == 0 {
+			return nil
+		}
+		var names []string
+		for _, sink := range failed {
+			names = append(names, sink.Name())
+		}
+		return fmt.Errorf("failed to send alerts to sinks: %s", strings.Join(names, ", "))
+	}, backoff.WithContext(bo, pub.ctx))
+}
+
+// getAlertSinks returns a copy of the sinks and their generation which changes when they are replaced.
+func (pub *Publisher) getAlertSinks() ([]sinks.AlertSink, uint64) {
+	pub.alertSinksMu.RLock()
+	defer pub.alertSinksMu.RUnlock()
+	return append([]sinks.AlertSink(nil), pub.alertSinks...), pub.alertSinksGen
+}
+
+// setAlertSinks replaces the sinks and returns the old sinks.
+func (pub *Publisher) setAlertSinks(alertSinks []sinks.AlertSink) []sinks.AlertSink {
+	pub.alertSinksMu.Lock()
+	defer pub.alertSinksMu.Unlock()
+	oldSinks := pub.alertSinks
+	pub.alertSinks = alertSinks
+	pub.alertSinksGen++
+	return oldSinks
 }
 
 func (pub *Publisher) hasBots() bool {
@@ -396,7 +582,7 @@ func (pub *Publisher) shouldSkipPublishing(batch *protocol.AlertBatch) (string,
 		return "", false
 	}
 
-	if batch.AlertCount > 0 {
+	if batch.AlertCount > 0 || len(batch.Retractions) > 0 {
 		return "", false
 	}
 	// after this line, alert count is considered as zero
@@ -532,30 +718,35 @@ func (bd *BatchData) GetPrivateAlerts(notif *protocol.NotifyRequest) *protocol.A
 	return res
 }
 
+// isPrivateAlert checks if the alert of the notification is private.
+func isPrivateAlert(notif *protocol.NotifyRequest) bool {
+	if notif.SignedAlert == nil || notif.SignedAlert.Alert == nil || notif.SignedAlert.Alert.Finding == nil {
+		return false
+	}
+
+	// default at per-finding level
+	if notif.SignedAlert.Alert.Finding.Private {
+		return true
+	}
+
+	// if public, let a private override at response-level win
+	switch {
+	case notif.EvalBlockResponse != nil:
+		return notif.EvalBlockResponse.Private
+	case notif.EvalTxResponse != nil:
+		return notif.EvalTxResponse.Private
+	case notif.EvalAlertResponse != nil:
+		return notif.EvalAlertResponse.Private
+	}
+	return false
+}
+
 // AppendAlert adds the alert to the relevant list.
 func (bd *BatchData) AppendAlert(notif *protocol.NotifyRequest) {
 	isBlockAlert := notif.EvalBlockRequest != nil
 	isTxAlert := notif.EvalTxRequest != nil
 	isCombinationAlert := notif.EvalAlertRequest != nil
-
-	var isPrivate bool
-
-	if notif.SignedAlert != nil && notif.SignedAlert.Alert != nil && notif.SignedAlert.Alert.Finding != nil {
-		// default at per-finding level
-		isPrivate = notif.SignedAlert.Alert.Finding.Private
-
-		// if public, let a private override at response-level win
-		if !isPrivate {
-			if notif.EvalBlockResponse != nil {
-				isPrivate = notif.EvalBlockResponse.Private
-			} else if notif.EvalTxResponse != nil {
-				isPrivate = notif.EvalTxResponse.Private
-			} else if notif.EvalAlertResponse.Private {
-				isPrivate = notif.EvalAlertResponse.Private
-			}
-		}
-	}
-
+	isPrivate := isPrivateAlert(notif)
 	hasAlert := notif.SignedAlert != nil
 
 	var agentAlerts *protocol.AgentAlerts
@@ -748,6 +939,12 @@ func (pub *Publisher) prepareLatestBatch() {
 	for i < pub.batchLimit {
 		select {
 		case notif := <-pub.notifCh:
+			if len(notif.Retractions) > 0 {
+				log.WithField("retractions", len(notif.Retractions)).Info("publisher received block retractions")
+				batch.Retractions = append(batch.Retractions, notif.Retractions...)
+				continue
+			}
+
 			alert := notif.SignedAlert
 			hasAlert := alert != nil
 			if hasAlert {
@@ -786,6 +983,7 @@ func (pub *Publisher) prepareLatestBatch() {
 			}
 
 			batch.AppendAlert(notif)
+			pub.handleLocalAlert(notif)
 
 		case batchTime, timedOut = <-pub.batchTicker.C:
 		}
@@ -806,9 +1004,51 @@ func (pub *Publisher) prepareLatestBatch() {
 	pub.batchCh <- (*protocol.AlertBatch)(batch)
 }
 
+// AddAlertListener adds a listener which receives the public alerts of the bots on this node as
+// soon as the publisher receives them. The listeners should not block.
+func (pub *Publisher) AddAlertListener(listener func(alert *protocol.AlertEvent_Alert)) {
+	pub.alertListenersMu.Lock()
+	defer pub.alertListenersMu.Unlock()
+	pub.alertListeners = append(pub.alertListeners, listener)
+}
+
+// handleLocalAlert adds the public alerts to the local alert index so that the bots on this node
+// can query them and sends them to the listeners.
+func (pub *Publisher) handleLocalAlert(notif *protocol.NotifyRequest) {
+	pub.alertListenersMu.RLock()
+	listeners := pub.alertListeners
+	pub.alertListenersMu.RUnlock()
+
+	if pub.alertIndex == nil && len(listeners) == 0 {
+		return
+	}
+	// the private alerts are not served to the other bots
+	if isPrivateAlert(notif) {
+		return
+	}
+	alert := alertindex.ToAlertEvent(notif, uint64(pub.cfg.ChainID))
+	if alert == nil {
+		return
+	}
+	if pub.alertIndex != nil {
+		if err := pub.alertIndex.Put(alert); err != nil {
+			log.WithError(err).WithField("alertHash", alert.Hash).Warn("failed to add alert to the local index")
+		}
+	}
+	for _, listener := range listeners {
+		listener(alert)
+	}
+}
+
 func (pub *Publisher) Start() error {
+	if pub.alertIndexServer != nil {
+		if err := pub.alertIndexServer.Start(); err != nil {
+			return err
+		}
+	}
 	go pub.prepareBatches()
 	go pub.publishBatches()
+	go pub.sendOutboxBatches()
 	pub.registerMessageHandlers()
 	return nil
 }
@@ -817,15 +1057,57 @@ func (pub *Publisher) Stop() error {
 	if pub.server != nil {
 		pub.server.Stop()
 	}
+	if pub.alertIndexServer != nil {
+		if err := pub.alertIndexServer.Stop(); err != nil {
+			log.WithError(err).Warn("failed to stop the alert index server")
+		}
+	}
+	if pub.alertIndex != nil {
+		if err := pub.alertIndex.Close(); err != nil {
+			log.WithError(err).Warn("failed to close the alert index")
+		}
+	}
+	pub.alertSinksMu.RLock()
+	defer pub.alertSinksMu.RUnlock()
+	closeAlertSinks(pub.alertSinks)
+	return nil
+}
+
+// ReloadConfig recreates the local alert sinks when the local mode destinations change.
+func (pub *Publisher) ReloadConfig(cfg config.Config, changes config.ConfigChanges) error {
+	if !changes.Reloaded("localMode.webhookUrl", "localMode.logFileName", "localMode.logToStdout", "localMode.sinks") {
+		return nil
+	}
+	cfg.ZktoroDir = pub.cfg.Config.ZktoroDir
+	cfg.LocalModeConfig.WebhookURL = utils.ConvertToDockerHostURL(cfg.LocalModeConfig.WebhookURL)
+	alertSinks, err := newLocalAlertSinks(cfg)
+	if err != nil {
+		return err
+	}
+
+	closeAlertSinks(pub.setAlertSinks(alertSinks))
 	return nil
 }
 
+func closeAlertSinks(alertSinks []sinks.AlertSink) {
+	for _, sink := range alertSinks {
+		if err := sink.Close(); err != nil {
+			log.WithError(err).WithField("sink", sink.Name()).Warn("failed to close alert sink")
+		}
+	}
+}
+
 func (pub *Publisher) Name() string {
 	return "publisher"
 }
 
 // Health implements the health.Reporter interface.
 func (pub *Publisher) Health() health.Reports {
+	outboxStats := pub.outbox.Stats()
+	var oldestPending string
+	if outboxStats.OldestPending != nil {
+		oldestPending = outboxStats.OldestPending.Format(time.RFC3339)
+	}
 	return health.Reports{
 		pub.lastBatchPublish.GetReport("event.batch-publish.time"),
 		pub.lastBatchPublishAttempt.GetReport("event.batch-publish-attempt.time"),
@@ -837,6 +1119,17 @@ func (pub *Publisher) Health() health.Reports {
 		},
 		pub.lastBatchSkipReason.GetReport("event.batch-skip.reason"),
 		pub.lastMetricsFlush.GetReport("event.metrics-flush.time"),
+		pub.lastOutboxSendErr.GetReport("event.outbox-send.error"),
+		&health.Report{
+			Name:    "outbox.depth",
+			Status:  health.StatusInfo,
+			Details: strconv.Itoa(outboxStats.Depth),
+		},
+		&health.Report{
+			Name:    "outbox.oldest-pending.time",
+			Status:  health.StatusInfo,
+			Details: oldestPending,
+		},
 	}
 }
 
@@ -844,7 +1137,7 @@ func NewPublisher(ctx context.Context, cfg config.Config) (*Publisher, error) {
 	msgClient := messaging.NewClient("metrics", fmt.Sprintf("%s:%s", config.DockerNatsContainerName, config.DefaultNatsPort))
 	lifecycleMetrics := metrics.NewLifecycleClient(msgClient)
 
-	key, err := config.LoadKeyInContainer(cfg)
+	signer, err := config.LoadSignerInContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -868,13 +1161,59 @@ func NewPublisher(ctx context.Context, cfg config.Config) (*Publisher, error) {
 
 	return initPublisher(ctx, msgClient, lifecycleMetrics, apiClient, storageClient, PublisherConfig{
 		ChainID:         cfg.ChainID,
-		Key:             key,
+		Signer:          signer,
 		PublisherConfig: cfg.Publish,
 		ReleaseSummary:  releaseSummary,
 		Config:          cfg,
 	})
 }
 
+// newLocalAlertSinks creates the configured sinks of the local mode alerts. Without any sinks
+// in the config, the alerts go to the webhook, the log file or stdout as before.
+func newLocalAlertSinks(cfg config.Config) ([]sinks.AlertSink, error) {
+	localModeCfg := cfg.LocalModeConfig
+	if !localModeCfg.Enable {
+		return nil, nil
+	}
+
+	if len(localModeCfg.Sinks) > 0 {
+		logsDir := path.Join(cfg.ZktoroDir, "logs")
+		var alertSinks []sinks.AlertSink
+		for _, sinkCfg := range localModeCfg.Sinks {
+			sink, err := sinks.NewAlertSink(sinkCfg, logsDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create local alert sink: %v", err)
+			}
+			alertSinks = append(alertSinks, sink)
+		}
+		return alertSinks, nil
+	}
+
+	localAlertDest := localModeCfg.WebhookURL
+	switch {
+	case len(localAlertDest) > 0:
+		client, err := webhook.NewAlertWebhookClient(localAlertDest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local alert webhook client: %s", localAlertDest)
+		}
+		return []sinks.AlertSink{sinks.NewClientSink(config.AlertSinkTypeWebhook, client)}, nil
+
+	case localModeCfg.LogToStdout:
+		logger, err := webhooklog.NewStdoutLogger()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local alert stdout logger: %v", err)
+		}
+		return []sinks.AlertSink{sinks.NewClientSink(config.AlertSinkTypeStdout, logger)}, nil
+
+	default:
+		logger, err := webhooklog.NewLogger(localModeCfg.LogFileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local alert logger: %v", err)
+		}
+		return []sinks.AlertSink{sinks.NewClientSink(config.AlertSinkTypeFile, logger)}, nil
+	}
+}
+
 func initPublisher(
 	ctx context.Context, mc clients.MessageClient,
 	lifecycleMetrics metrics.Lifecycle, alertClient clients.AlertAPIClient,
@@ -895,25 +1234,35 @@ func initPublisher(
 		batchLimit = *cfg.PublisherConfig.Batch.MaxAlerts
 	}
 
-	var localAlertClient LocalAlertClient
-	localAlertDest := cfg.Config.LocalModeConfig.WebhookURL
-	if cfg.Config.LocalModeConfig.Enable && len(localAlertDest) > 0 {
-		localAlertClient, err = webhook.NewAlertWebhookClient(localAlertDest)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create local alert webhook client: %s", localAlertDest)
-		}
+	alertSinks, err := newLocalAlertSinks(cfg.Config)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Config.LocalModeConfig.Enable && len(localAlertDest) == 0 && !cfg.Config.LocalModeConfig.LogToStdout {
-		localAlertClient, err = webhooklog.NewLogger(cfg.Config.LocalModeConfig.LogFileName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create local alert logger: %s", localAlertDest)
-		}
+
+	outbox, err := NewOutbox(path.Join(cfg.Config.ZktoroDir, outboxDirName))
+	if err != nil {
+		return nil, err
 	}
-	if cfg.Config.LocalModeConfig.Enable && len(localAlertDest) == 0 && cfg.Config.LocalModeConfig.LogToStdout {
-		localAlertClient, err = webhooklog.NewStdoutLogger()
+
+	var (
+		alertIndex       *alertindex.Index
+		alertIndexServer *alertindex.Server
+	)
+	if alertIndexCfg := cfg.Config.AlertIndex; alertIndexCfg.Enable {
+		alertIndex, err = alertindex.NewIndex(
+			path.Join(cfg.Config.ZktoroDir, alertIndexDirName),
+			time.Duration(alertIndexCfg.RetentionMinutes)*time.Minute,
+			int64(alertIndexCfg.MaxSizeMB)<<20,
+		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create local alert stdout logger: %s", localAlertDest)
+			return nil, err
 		}
+		alertIndexServer = alertindex.NewServer(alertIndex, config.DefaultAlertIndexPort)
+	}
+
+	didLinkage, err := config.LoadDIDLinkageInContainer(cfg.Config, cfg.Signer.Address())
+	if err != nil {
+		log.WithError(err).Warn("not embedding the DID linkage")
 	}
 
 	return &Publisher{
@@ -924,10 +1273,15 @@ func initPublisher(
 		metricsAggregator: NewMetricsAggregator(time.Duration(*cfg.PublisherConfig.Batch.MetricsBucketIntervalSeconds) * time.Second),
 		messageClient:     mc,
 		alertClient:       alertClient,
-		localAlertClient:  localAlertClient,
+		alertSinks:        alertSinks,
 		lifecycleMetrics:  lifecycleMetrics,
 		batchRefStore:     store.NewFileStringStore(path.Join(cfg.Config.ZktoroDir, ".last-batch")),
 		lastReceiptStore:  store.NewFileStringStore(path.Join(cfg.Config.ZktoroDir, ".last-receipt")),
+		outbox:            outbox,
+		outboxCh:          make(chan struct{}, 1),
+		didLinkage:        didLinkage,
+		alertIndex:        alertIndex,
+		alertIndexServer:  alertIndexServer,
 
 		skipEmpty:     cfg.PublisherConfig.Batch.SkipEmpty,
 		skipPublish:   cfg.PublisherConfig.SkipPublish,
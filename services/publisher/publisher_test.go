@@ -1,13 +1,32 @@
 package publisher
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
 	"testing"
 	"time"
 
+	"zktoro/clients/alertapi"
+	mock_clients "zktoro/clients/mocks"
 	"zktoro/config"
+	mock_metrics "zktoro/services/components/metrics/mocks"
+	"zktoro/services/publisher/alertindex"
+	"zktoro/services/publisher/sinks"
+	"zktoro/store"
 
+	"zktoro/zktoro-core-go/clients/graphql"
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+	"zktoro/zktoro-core-go/domain"
+	"zktoro/zktoro-core-go/encoding"
 	"zktoro/zktoro-core-go/protocol"
+	"zktoro/zktoro-core-go/security"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -128,6 +147,19 @@ func TestShouldSkipPublishing(t *testing.T) {
 			},
 			expectedSkipValue: false,
 		},
+		{
+			name: "has retractions in local mode",
+			publisher: &Publisher{
+				lastBatchSendAttempt: veryRecently,
+				cfg: PublisherConfig{
+					Config: config.Config{LocalModeConfig: config.LocalModeConfig{Enable: true}},
+				},
+			},
+			batch: &protocol.AlertBatch{
+				Retractions: []*protocol.BlockRetraction{{BlockNumber: 1, BlockHash: "0x1"}},
+			},
+			expectedSkipValue: false,
+		},
 		{
 			name: "has metrics and running bots",
 			publisher: &Publisher{
@@ -187,3 +219,208 @@ func TestShouldSkipPublishing(t *testing.T) {
 		})
 	}
 }
+
+func newLocalAlertNotif(id string, private bool) *protocol.NotifyRequest {
+	return &protocol.NotifyRequest{
+		SignedAlert: &protocol.SignedAlert{
+			Alert: &protocol.Alert{
+				Id:        id,
+				Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+				Agent:     &protocol.AgentInfo{Id: "0xbot"},
+				Finding:   &protocol.Finding{AlertId: "ALERT"},
+			},
+		},
+		EvalBlockRequest: &protocol.EvaluateBlockRequest{
+			Event: &protocol.BlockEvent{BlockNumber: "0x1", Block: &protocol.BlockEvent_EthBlock{Timestamp: "0x1"}},
+		},
+		EvalBlockResponse: &protocol.EvaluateBlockResponse{Private: private},
+	}
+}
+
+func TestPublisher_AlertListener(t *testing.T) {
+	r := require.New(t)
+
+	pub := &Publisher{cfg: PublisherConfig{ChainID: 1}}
+	var received []*protocol.AlertEvent_Alert
+	pub.AddAlertListener(func(alert *protocol.AlertEvent_Alert) {
+		received = append(received, alert)
+	})
+	newNotif := newLocalAlertNotif
+
+	// the private alerts are not sent to the listeners
+	pub.handleLocalAlert(newNotif("0x1", false))
+	pub.handleLocalAlert(newNotif("0x2", true))
+	pub.handleLocalAlert(&protocol.NotifyRequest{})
+
+	r.Len(received, 1)
+	r.Equal("0x1", received[0].Hash)
+	r.Equal("0xbot", received[0].Source.Bot.Id)
+	r.Equal(uint64(1), received[0].Source.Block.Number)
+}
+
+func TestPublisher_AlertIndexSkipsPrivateAlerts(t *testing.T) {
+	r := require.New(t)
+
+	idx, err := alertindex.NewIndex(t.TempDir(), time.Hour, 1<<20)
+	r.NoError(err)
+	defer idx.Close()
+
+	pub := &Publisher{cfg: PublisherConfig{ChainID: 1}, alertIndex: idx}
+	pub.handleLocalAlert(newLocalAlertNotif("0x1", false))
+	pub.handleLocalAlert(newLocalAlertNotif("0x2", true))
+
+	alerts, _ := idx.Query(&graphql.AlertsInput{})
+	r.Len(alerts, 1)
+	r.Equal("0x1", alerts[0].Hash)
+
+	alerts, _ = idx.Query(&graphql.AlertsInput{AlertHash: "0x2"})
+	r.Empty(alerts)
+}
+
+func signTestBatchSummary(r *require.Assertions, signer security.Signer, ref string) *domain.AlertBatchRequest {
+	summary, err := security.SignBatchSummaryWithSigner(signer, &protocol.BatchSummary{Batch: ref})
+	r.NoError(err)
+	return &domain.AlertBatchRequest{Ref: ref, SignedBatchSummary: summary}
+}
+
+func TestPublisher_SendOutboxBatches(t *testing.T) {
+	r := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	privateKey, err := crypto.GenerateKey()
+	r.NoError(err)
+	signer := security.NewKeySigner(&keystore.Key{PrivateKey: privateKey, Address: crypto.PubkeyToAddress(privateKey.PublicKey)})
+
+	outbox, err := NewOutbox(t.TempDir())
+	r.NoError(err)
+	alertClient := mock_clients.NewMockAlertAPIClient(ctrl)
+	lifecycleMetrics := mock_metrics.NewMockLifecycle(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pub := &Publisher{
+		ctx:              ctx,
+		cfg:              PublisherConfig{Signer: signer},
+		alertClient:      alertClient,
+		lifecycleMetrics: lifecycleMetrics,
+		lastReceiptStore: store.NewFileStringStore(path.Join(t.TempDir(), "last-receipt")),
+		outbox:           outbox,
+		outboxCh:         make(chan struct{}, 1),
+	}
+
+	// all of the batches are queued before any receipt is known
+	for _, ref := range []string{"rejected", "sent", "next"} {
+		_, err := outbox.Put(signTestBatchSummary(r, signer, ref))
+		r.NoError(err)
+	}
+
+	previousReceipt := func(req *domain.AlertBatchRequest) string {
+		r.NoError(security.VerifySignedPayload(req.SignedBatchSummary))
+		var summary protocol.BatchSummary
+		r.NoError(encoding.DecodeGzippedProto(req.SignedBatchSummary.Encoded, &summary))
+		return summary.PreviousReceipt
+	}
+
+	gomock.InOrder(
+		alertClient.EXPECT().PostBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(req *domain.AlertBatchRequest, token string) (*domain.AlertBatchResponse, error) {
+				r.Equal("rejected", req.Ref)
+				return nil, &alertapi.APIError{StatusCode: http.StatusBadRequest, Body: "bad batch"}
+			}),
+		alertClient.EXPECT().PostBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(req *domain.AlertBatchRequest, token string) (*domain.AlertBatchResponse, error) {
+				r.Equal("sent", req.Ref)
+				r.Empty(previousReceipt(req))
+				return &domain.AlertBatchResponse{ReceiptID: "receipt1", SignedReceipt: &protocol.SignedPayload{}}, nil
+			}),
+		alertClient.EXPECT().PostBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(req *domain.AlertBatchRequest, token string) (*domain.AlertBatchResponse, error) {
+				r.Equal("next", req.Ref)
+				r.Equal("receipt1", previousReceipt(req))
+				// no receipt is not a failure
+				return &domain.AlertBatchResponse{}, nil
+			}),
+	)
+	lifecycleMetrics.EXPECT().SystemError("publisher.outbox.dead-letter", gomock.Any())
+
+	go pub.sendOutboxBatches()
+	r.Eventually(func() bool {
+		return outbox.Stats().Depth == 0
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestIsPermanentSendError(t *testing.T) {
+	r := require.New(t)
+
+	apiErr := func(status int) error {
+		return fmt.Errorf("failed to send the alert tx: %w", &alertapi.APIError{StatusCode: status})
+	}
+	r.True(isPermanentSendError(apiErr(http.StatusBadRequest)))
+	r.True(isPermanentSendError(apiErr(http.StatusRequestEntityTooLarge)))
+	r.True(isPermanentSendError(apiErr(http.StatusUnprocessableEntity)))
+	r.False(isPermanentSendError(apiErr(http.StatusUnauthorized)))
+	r.False(isPermanentSendError(apiErr(http.StatusForbidden)))
+	r.False(isPermanentSendError(apiErr(http.StatusNotFound)))
+	r.False(isPermanentSendError(apiErr(http.StatusTooManyRequests)))
+	r.False(isPermanentSendError(apiErr(http.StatusRequestTimeout)))
+	r.False(isPermanentSendError(apiErr(http.StatusBadGateway)))
+	r.False(isPermanentSendError(errors.New("connection refused")))
+}
+
+type testAlertSink struct {
+	name   string
+	fails  int
+	sent   int
+	onFail func()
+}
+
+func (sink *testAlertSink) Name() string {
+	return sink.name
+}
+
+func (sink *testAlertSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	if sink.fails > 0 {
+		sink.fails--
+		if sink.onFail != nil {
+			sink.onFail()
+		}
+		return errors.New("not stored")
+	}
+	sink.sent++
+	return nil
+}
+
+func (sink *testAlertSink) Close() error {
+	return nil
+}
+
+func TestPublisher_SendToSinks_RetriesFailedSinks(t *testing.T) {
+	r := require.New(t)
+
+	healthy := &testAlertSink{name: "healthy"}
+	flaky := &testAlertSink{name: "flaky", fails: 1}
+	pub := &Publisher{ctx: context.Background(), alertSinks: []sinks.AlertSink{healthy, flaky}}
+
+	r.NoError(pub.sendToSinks(&models.AlertBatch{}, ""))
+	// only the failed sink is sent to again
+	r.Equal(1, healthy.sent)
+	r.Equal(1, flaky.sent)
+}
+
+func TestPublisher_SendToSinks_Reload(t *testing.T) {
+	r := require.New(t)
+
+	healthy := &testAlertSink{name: "healthy"}
+	flaky := &testAlertSink{name: "flaky", fails: 1}
+	reloaded := &testAlertSink{name: "reloaded"}
+	pub := &Publisher{ctx: context.Background(), alertSinks: []sinks.AlertSink{healthy, flaky}}
+	// the sinks are reloaded while the batch is being sent
+	flaky.onFail = func() {
+		pub.setAlertSinks([]sinks.AlertSink{reloaded})
+	}
+
+	r.NoError(pub.sendToSinks(&models.AlertBatch{}, ""))
+	r.Equal(1, healthy.sent)
+	r.Equal(0, flaky.sent)
+	r.Equal(1, reloaded.sent)
+}
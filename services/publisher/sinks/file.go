@@ -0,0 +1,130 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+)
+
+// FileSink writes the alerts to a JSONL file, one alert or retraction per line. The file is rotated
+// when it reaches the max size and the oldest of the rotated files are removed.
+type FileSink struct {
+	name     string
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a new file sink.
+func NewFileSink(name, filePath string, maxSize int64, maxFiles int) (*FileSink, error) {
+	if err := os.MkdirAll(path.Dir(filePath), 0777); err != nil {
+		return nil, fmt.Errorf("failed to create the sink dir: %v", err)
+	}
+	sink := &FileSink{
+		name:     name,
+		path:     filePath,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	log.WithField("path", filePath).Info("logging local alerts to file")
+	return sink, nil
+}
+
+func (sink *FileSink) open() error {
+	file, err := os.OpenFile(sink.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open the alert file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat the alert file: %v", err)
+	}
+	sink.file = file
+	sink.size = info.Size()
+	return nil
+}
+
+// rotatedPath returns the path of the n-th rotated file.
+func (sink *FileSink) rotatedPath(n int) string {
+	return fmt.Sprintf("%s.%d", sink.path, n)
+}
+
+// rotate shifts the rotated files by one and starts a new file.
+func (sink *FileSink) rotate() error {
+	if err := sink.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(sink.rotatedPath(sink.maxFiles))
+	for n := sink.maxFiles - 1; n >= 1; n-- {
+		os.Rename(sink.rotatedPath(n), sink.rotatedPath(n+1))
+	}
+	if sink.maxFiles > 0 {
+		if err := os.Rename(sink.path, sink.rotatedPath(1)); err != nil {
+			return fmt.Errorf("failed to rotate the alert file: %v", err)
+		}
+	} else {
+		os.Remove(sink.path)
+	}
+	return sink.open()
+}
+
+// Name returns the name of the sink.
+func (sink *FileSink) Name() string {
+	return sink.name
+}
+
+// Send appends the alerts and the retractions to the file.
+func (sink *FileSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	for _, alert := range batch.Alerts {
+		if err := sink.writeLine(alert); err != nil {
+			return fmt.Errorf("failed to write the alert: %v", err)
+		}
+	}
+	for _, retraction := range batch.Retractions {
+		if err := sink.writeLine(&RetractionRecord{Retraction: retraction}); err != nil {
+			return fmt.Errorf("failed to write the retraction: %v", err)
+		}
+	}
+	return nil
+}
+
+func (sink *FileSink) writeLine(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if sink.size > 0 && sink.size+int64(len(b)) > sink.maxSize {
+		if err := sink.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := sink.file.Write(b)
+	sink.size += int64(n)
+	return err
+}
+
+// Close implements io.Closer.
+func (sink *FileSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return sink.file.Close()
+}
@@ -0,0 +1,296 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"zktoro/config"
+
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+
+	"github.com/goccy/go-json"
+	log "github.com/sirupsen/logrus"
+)
+
+// KafkaSink produces the alerts to a Kafka topic, one record per alert. The records are keyed
+// by the bot ID so the alerts of a bot stay in order in the same partition. The retractions
+// are keyed by the block hash.
+type KafkaSink struct {
+	name     string
+	brokers  []string
+	topic    string
+	clientID string
+	timeout  time.Duration
+
+	correlationID int32
+	conns         map[string]net.Conn
+	partitions    []int32
+	leaders       map[int32]string
+	mu            sync.Mutex
+}
+
+// kafkaProduceError is a partition error from the broker.
+type kafkaProduceError struct {
+	partition int32
+	code      int16
+}
+
+func (err *kafkaProduceError) Error() string {
+	return fmt.Sprintf("kafka: produce to partition %d failed with error code %d", err.partition, err.code)
+}
+
+// NewKafkaSink creates a new Kafka sink. It connects to the brokers lazily.
+func NewKafkaSink(name string, kafkaCfg config.KafkaSinkConfig) *KafkaSink {
+	return &KafkaSink{
+		name:     name,
+		brokers:  kafkaCfg.Brokers,
+		topic:    kafkaCfg.Topic,
+		clientID: kafkaCfg.ClientID,
+		timeout:  time.Duration(kafkaCfg.TimeoutSeconds) * time.Second,
+		conns:    make(map[string]net.Conn),
+	}
+}
+
+// Name returns the name of the sink.
+func (sink *KafkaSink) Name() string {
+	return sink.name
+}
+
+// Send produces the alerts and waits for the acknowledgement of all in-sync replicas.
+func (sink *KafkaSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	if len(batch.Alerts) == 0 && len(batch.Retractions) == 0 {
+		return nil
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if len(sink.leaders) == 0 {
+		if err := sink.refreshMetadata(); err != nil {
+			return err
+		}
+	}
+
+	byPartition := make(map[int32][]*kafkaRecord)
+	for _, alert := range batch.Alerts {
+		value, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("failed to encode the alert: %v", err)
+		}
+		key := []byte(alertBotID(alert))
+		partition := sink.partitionFor(key)
+		byPartition[partition] = append(byPartition[partition], &kafkaRecord{Key: key, Value: value})
+	}
+	for _, retraction := range batch.Retractions {
+		value, err := json.Marshal(&RetractionRecord{Retraction: retraction})
+		if err != nil {
+			return fmt.Errorf("failed to encode the retraction: %v", err)
+		}
+		key := []byte(retraction.BlockHash)
+		partition := sink.partitionFor(key)
+		byPartition[partition] = append(byPartition[partition], &kafkaRecord{Key: key, Value: value})
+	}
+
+	for partition, records := range byPartition {
+		err := sink.produce(partition, records)
+		if err == nil {
+			continue
+		}
+		// the leadership might have moved: retry once with fresh metadata
+		log.WithError(err).WithField("sink", sink.name).Warn("retrying kafka produce")
+		if err := sink.refreshMetadata(); err != nil {
+			return err
+		}
+		if err := sink.produce(partition, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sink *KafkaSink) partitionFor(key []byte) int32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return sink.partitions[h.Sum32()%uint32(len(sink.partitions))]
+}
+
+func (sink *KafkaSink) produce(partition int32, records []*kafkaRecord) error {
+	leader, ok := sink.leaders[partition]
+	if !ok {
+		return fmt.Errorf("kafka: no leader for partition %d", partition)
+	}
+
+	var req kafkaEncoder
+	req.nullableString(nil) // transactional id
+	req.int16(-1)           // acks from all in-sync replicas
+	req.int32(int32(sink.timeout.Milliseconds()))
+	req.int32(1)
+	req.string(sink.topic)
+	req.int32(1)
+	req.int32(partition)
+	req.bytes(encodeKafkaRecordBatch(records, time.Now()))
+
+	resp, err := sink.roundTrip(leader, kafkaAPIProduce, kafkaProduceVersion, req.Bytes())
+	if err != nil {
+		return err
+	}
+	for i := resp.arrayLen(); i > 0; i-- {
+		resp.string()
+		for j := resp.arrayLen(); j > 0; j-- {
+			respPartition := resp.int32()
+			code := resp.int16()
+			resp.int64() // base offset
+			resp.int64() // log append time
+			if resp.err == nil && code != kafkaErrNone {
+				return &kafkaProduceError{partition: respPartition, code: code}
+			}
+		}
+	}
+	return resp.err
+}
+
+// refreshMetadata finds the partitions of the topic and their leaders from any of the brokers.
+func (sink *KafkaSink) refreshMetadata() error {
+	var req kafkaEncoder
+	req.int32(1)
+	req.string(sink.topic)
+
+	var lastErr error
+	for _, broker := range sink.brokers {
+		resp, err := sink.roundTrip(broker, kafkaAPIMetadata, kafkaMetadataVersion, req.Bytes())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		brokerAddrs := make(map[int32]string)
+		for i := resp.arrayLen(); i > 0; i-- {
+			nodeID := resp.int32()
+			host := resp.string()
+			port := resp.int32()
+			if n := resp.int16(); n > 0 { // rack
+				resp.next(int(n))
+			}
+			brokerAddrs[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+		}
+		resp.int32() // controller id
+
+		var (
+			partitions []int32
+			leaders    = make(map[int32]string)
+		)
+		for i := resp.arrayLen(); i > 0; i-- {
+			topicErr := resp.int16()
+			resp.string()
+			resp.int8() // is internal
+			if resp.err == nil && topicErr != kafkaErrNone {
+				lastErr = &kafkaProduceError{partition: -1, code: topicErr}
+			}
+			for j := resp.arrayLen(); j > 0; j-- {
+				resp.int16() // partition error
+				partition := resp.int32()
+				leader := resp.int32()
+				for k := resp.arrayLen(); k > 0; k-- { // replicas
+					resp.int32()
+				}
+				for k := resp.arrayLen(); k > 0; k-- { // isr
+					resp.int32()
+				}
+				partitions = append(partitions, partition)
+				if addr, ok := brokerAddrs[leader]; ok {
+					leaders[partition] = addr
+				}
+			}
+		}
+		if resp.err != nil {
+			lastErr = resp.err
+			continue
+		}
+		if len(partitions) == 0 {
+			continue
+		}
+		sink.partitions = partitions
+		sink.leaders = leaders
+		return nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no partitions")
+	}
+	return fmt.Errorf("kafka: failed to get the metadata of topic %s: %v", sink.topic, lastErr)
+}
+
+// roundTrip sends the request to the broker and returns the response body.
+func (sink *KafkaSink) roundTrip(addr string, apiKey, apiVersion int16, body []byte) (*kafkaDecoder, error) {
+	conn, ok := sink.conns[addr]
+	if !ok {
+		var err error
+		conn, err = net.DialTimeout("tcp", addr, sink.timeout)
+		if err != nil {
+			return nil, err
+		}
+		sink.conns[addr] = conn
+	}
+
+	sink.correlationID++
+	var req kafkaEncoder
+	req.int16(apiKey)
+	req.int16(apiVersion)
+	req.int32(sink.correlationID)
+	req.string(sink.clientID)
+	req.Write(body)
+
+	var frame kafkaEncoder
+	frame.bytes(req.Bytes())
+
+	resp, err := sink.exchange(conn, frame.Bytes())
+	if err != nil {
+		conn.Close()
+		delete(sink.conns, addr)
+		return nil, err
+	}
+	if correlationID := resp.int32(); correlationID != sink.correlationID {
+		conn.Close()
+		delete(sink.conns, addr)
+		return nil, fmt.Errorf("kafka: unexpected correlation id %d", correlationID)
+	}
+	return resp, nil
+}
+
+func (sink *KafkaSink) exchange(conn net.Conn, frame []byte) (*kafkaDecoder, error) {
+	if err := conn.SetDeadline(time.Now().Add(sink.timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(frame); err != nil {
+		return nil, err
+	}
+	var sizeBuf [4]byte
+	if _, err := io.ReadFull(conn, sizeBuf[:]); err != nil {
+		return nil, err
+	}
+	size := (&kafkaDecoder{b: sizeBuf[:]}).int32()
+	if size < 4 {
+		return nil, errKafkaShortBuffer
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return nil, err
+	}
+	return &kafkaDecoder{b: b}, nil
+}
+
+// Close implements io.Closer.
+func (sink *KafkaSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	for addr, conn := range sink.conns {
+		conn.Close()
+		delete(sink.conns, addr)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// Kafka API keys and versions which the producer uses
+const (
+	kafkaAPIProduce  int16 = 0
+	kafkaAPIMetadata int16 = 3
+
+	kafkaProduceVersion  int16 = 3
+	kafkaMetadataVersion int16 = 1
+
+	kafkaRecordBatchMagic int8 = 2
+)
+
+const kafkaErrNone int16 = 0
+
+var (
+	errKafkaShortBuffer = errors.New("kafka: short buffer")
+	crc32cTable         = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// kafkaEncoder writes the big-endian Kafka protocol primitives.
+type kafkaEncoder struct {
+	bytes.Buffer
+}
+
+func (e *kafkaEncoder) int8(v int8) {
+	e.WriteByte(byte(v))
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	e.Write(binary.BigEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	e.Write(binary.BigEndian.AppendUint32(nil, uint32(v)))
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	e.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+}
+
+func (e *kafkaEncoder) varint(v int64) {
+	e.Write(binary.AppendVarint(nil, v))
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.WriteString(v)
+}
+
+func (e *kafkaEncoder) nullableString(v *string) {
+	if v == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*v)
+}
+
+func (e *kafkaEncoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.Write(v)
+}
+
+// varintBytes writes the bytes with a varint length as in the records.
+func (e *kafkaEncoder) varintBytes(v []byte) {
+	if v == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(v)))
+	e.Write(v)
+}
+
+// kafkaDecoder reads the Kafka protocol primitives. The first error sticks.
+type kafkaDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.b) < n {
+		d.err = errKafkaShortBuffer
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errKafkaShortBuffer
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+func (d *kafkaDecoder) varintBytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// arrayLen reads an array length and treats null arrays as empty.
+func (d *kafkaDecoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 || int(n) > len(d.b) {
+		if n > 0 {
+			d.err = errKafkaShortBuffer
+		}
+		return 0
+	}
+	return int(n)
+}
+
+// kafkaRecord is a record in a record batch.
+type kafkaRecord struct {
+	Key   []byte
+	Value []byte
+}
+
+// encodeKafkaRecordBatch encodes the records as a v2 record batch.
+func encodeKafkaRecordBatch(records []*kafkaRecord, ts time.Time) []byte {
+	var body kafkaEncoder
+	body.int16(0) // attributes: no compression
+	body.int32(int32(len(records) - 1))
+	body.int64(ts.UnixMilli())
+	body.int64(ts.UnixMilli())
+	body.int64(-1) // producer id
+	body.int16(-1) // producer epoch
+	body.int32(-1) // base sequence
+	body.int32(int32(len(records)))
+	for i, record := range records {
+		var rec kafkaEncoder
+		rec.int8(0)          // attributes
+		rec.varint(0)        // timestamp delta
+		rec.varint(int64(i)) // offset delta
+		rec.varintBytes(record.Key)
+		rec.varintBytes(record.Value)
+		rec.varint(0) // headers
+		body.varint(int64(rec.Len()))
+		body.Write(rec.Bytes())
+	}
+
+	var batch kafkaEncoder
+	batch.int64(0) // base offset
+	batch.int32(int32(4 + 1 + 4 + body.Len()))
+	batch.int32(-1) // partition leader epoch
+	batch.int8(kafkaRecordBatchMagic)
+	batch.int32(int32(crc32.Checksum(body.Bytes(), crc32cTable)))
+	batch.Write(body.Bytes())
+	return batch.Bytes()
+}
@@ -0,0 +1,261 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeKafkaRecordBatches decodes the record batches and verifies the checksums.
+func decodeKafkaRecordBatches(b []byte) ([]*kafkaRecord, error) {
+	var records []*kafkaRecord
+	d := &kafkaDecoder{b: b}
+	for len(d.b) > 0 && d.err == nil {
+		d.int64() // base offset
+		batchLen := d.int32()
+		batch := &kafkaDecoder{b: d.next(int(batchLen))}
+		batch.int32() // partition leader epoch
+		if magic := batch.int8(); batch.err == nil && magic != kafkaRecordBatchMagic {
+			return nil, errors.New("kafka: unsupported record batch version")
+		}
+		crc := uint32(batch.int32())
+		if batch.err == nil && crc32.Checksum(batch.b, crc32cTable) != crc {
+			return nil, errors.New("kafka: record batch checksum mismatch")
+		}
+		batch.int16()                 // attributes
+		batch.int32()                 // last offset delta
+		batch.next(8 + 8 + 8 + 2 + 4) // timestamps and producer info
+		count := batch.arrayLen()
+		for i := 0; i < count && batch.err == nil; i++ {
+			rec := &kafkaDecoder{b: batch.next(int(batch.varint()))}
+			rec.int8()   // attributes
+			rec.varint() // timestamp delta
+			rec.varint() // offset delta
+			record := &kafkaRecord{Key: rec.varintBytes(), Value: rec.varintBytes()}
+			if rec.err != nil {
+				return nil, rec.err
+			}
+			records = append(records, record)
+		}
+		if batch.err != nil {
+			return nil, batch.err
+		}
+	}
+	return records, d.err
+}
+
+// testKafkaBroker is a stand-in broker which serves the metadata of a single topic
+// and collects the produced records.
+type testKafkaBroker struct {
+	ln         net.Listener
+	topic      string
+	partitions int32
+	errorCode  int16
+
+	records map[int32][]*kafkaRecord
+	mu      sync.Mutex
+}
+
+func startTestKafkaBroker(t *testing.T, topic string, partitions int32) *testKafkaBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	broker := &testKafkaBroker{
+		ln:         ln,
+		topic:      topic,
+		partitions: partitions,
+		records:    make(map[int32][]*kafkaRecord),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+func (broker *testKafkaBroker) addr() string {
+	return broker.ln.Addr().String()
+}
+
+func (broker *testKafkaBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var sizeBuf [4]byte
+		if _, err := io.ReadFull(conn, sizeBuf[:]); err != nil {
+			return
+		}
+		b := make([]byte, (&kafkaDecoder{b: sizeBuf[:]}).int32())
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return
+		}
+		req := &kafkaDecoder{b: b}
+		apiKey := req.int16()
+		req.int16() // version
+		correlationID := req.int32()
+		req.string() // client id
+
+		var resp kafkaEncoder
+		resp.int32(correlationID)
+		switch apiKey {
+		case kafkaAPIMetadata:
+			broker.writeMetadata(&resp)
+		case kafkaAPIProduce:
+			if err := broker.handleProduce(req, &resp); err != nil {
+				return
+			}
+		default:
+			return
+		}
+		var frame kafkaEncoder
+		frame.bytes(resp.Bytes())
+		if _, err := conn.Write(frame.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (broker *testKafkaBroker) writeMetadata(resp *kafkaEncoder) {
+	host, portStr, _ := net.SplitHostPort(broker.addr())
+	port, _ := strconv.Atoi(portStr)
+	resp.int32(1)
+	resp.int32(1) // node id
+	resp.string(host)
+	resp.int32(int32(port))
+	resp.nullableString(nil) // rack
+	resp.int32(1)            // controller id
+	resp.int32(1)
+	resp.int16(kafkaErrNone)
+	resp.string(broker.topic)
+	resp.int8(0)
+	resp.int32(broker.partitions)
+	for i := int32(0); i < broker.partitions; i++ {
+		resp.int16(kafkaErrNone)
+		resp.int32(i)
+		resp.int32(1) // leader
+		resp.int32(1)
+		resp.int32(1) // replicas
+		resp.int32(1)
+		resp.int32(1) // isr
+	}
+}
+
+func (broker *testKafkaBroker) handleProduce(req *kafkaDecoder, resp *kafkaEncoder) error {
+	req.string() // transactional id
+	if acks := req.int16(); acks != -1 {
+		return errors.New("unexpected acks")
+	}
+	req.int32() // timeout
+	req.arrayLen()
+	topic := req.string()
+	req.arrayLen()
+	partition := req.int32()
+	records, err := decodeKafkaRecordBatches(req.bytes())
+	if err != nil {
+		return err
+	}
+	if req.err != nil || topic != broker.topic {
+		return errors.New("invalid produce request")
+	}
+
+	broker.mu.Lock()
+	code := broker.errorCode
+	if code == kafkaErrNone {
+		broker.records[partition] = append(broker.records[partition], records...)
+	}
+	broker.mu.Unlock()
+
+	resp.int32(1)
+	resp.string(topic)
+	resp.int32(1)
+	resp.int32(partition)
+	resp.int16(code)
+	resp.int64(0)  // base offset
+	resp.int64(-1) // log append time
+	resp.int32(0)  // throttle time
+	return nil
+}
+
+func (broker *testKafkaBroker) allRecords() map[int32][]*kafkaRecord {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	return broker.records
+}
+
+func TestKafkaRecordBatch(t *testing.T) {
+	r := require.New(t)
+
+	records := []*kafkaRecord{
+		{Key: []byte("key1"), Value: []byte("value1")},
+		{Key: nil, Value: []byte("value2")},
+	}
+	b := encodeKafkaRecordBatch(records, time.Now())
+	decoded, err := decodeKafkaRecordBatches(b)
+	r.NoError(err)
+	r.Equal(records, decoded)
+
+	b[len(b)-1]++
+	_, err = decodeKafkaRecordBatches(b)
+	r.Error(err)
+}
+
+func TestKafkaSink(t *testing.T) {
+	r := require.New(t)
+
+	broker := startTestKafkaBroker(t, "alerts", 3)
+	sink, err := NewAlertSink(&config.AlertSinkConfig{
+		Name:       "kafka",
+		Type:       config.AlertSinkTypeKafka,
+		Severities: []string{models.AlertSeverityHIGH, models.AlertSeverityLOW},
+		Kafka: config.KafkaSinkConfig{
+			// the first broker is down
+			Brokers:        []string{"127.0.0.1:1", broker.addr()},
+			Topic:          "alerts",
+			ClientID:       "test",
+			TimeoutSeconds: 5,
+		},
+	}, "")
+	r.NoError(err)
+	defer sink.Close()
+
+	r.NoError(sink.Send(context.Background(), testBatch(), ""))
+	r.NoError(sink.Send(context.Background(), testBatch(), ""))
+
+	partitionRecords := broker.allRecords()
+	r.Len(partitionRecords, 1, "the alerts of a bot should go to the same partition")
+	for _, records := range partitionRecords {
+		r.Len(records, 4)
+		for _, record := range records {
+			r.Equal(testBotID1, string(record.Key))
+			var alert models.Alert
+			r.NoError(json.Unmarshal(record.Value, &alert))
+			r.Equal(testBotID1, alert.Source.Bot.ID)
+		}
+	}
+
+	broker.mu.Lock()
+	broker.errorCode = 6 // not leader
+	broker.mu.Unlock()
+	err = sink.Send(context.Background(), testBatch(), "")
+	var produceErr *kafkaProduceError
+	r.ErrorAs(err, &produceErr)
+	r.Equal(int16(6), produceErr.code)
+}
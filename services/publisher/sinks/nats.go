@@ -0,0 +1,95 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+
+	"github.com/goccy/go-json"
+	"github.com/nats-io/nats.go"
+)
+
+const natsAckTimeout = time.Second * 10
+
+// NATSSink publishes the alerts to a JetStream subject, one message per alert. A batch is
+// sent only after the stream acknowledges every message so that the alerts which are not
+// stored are sent again.
+type NATSSink struct {
+	name    string
+	subject string
+	nc      *nats.Conn
+	js      nats.JetStreamContext
+}
+
+// NewNATSSink creates a new NATS sink.
+func NewNATSSink(name, natsURL, subject string) (*NATSSink, error) {
+	nc, err := nats.Connect(natsURL, nats.Name(fmt.Sprintf("zktoro-sink-%s", name)), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %v", err)
+	}
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %v", err)
+	}
+	return &NATSSink{name: name, subject: subject, nc: nc, js: js}, nil
+}
+
+// Name returns the name of the sink.
+func (sink *NATSSink) Name() string {
+	return sink.name
+}
+
+// Send publishes the alerts and the retractions and waits until the stream acknowledges them.
+// The message IDs let the stream drop the duplicates when a batch is sent again.
+func (sink *NATSSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	var futures []nats.PubAckFuture
+	for _, alert := range batch.Alerts {
+		b, err := json.Marshal(alert)
+		if err != nil {
+			return fmt.Errorf("failed to encode the alert: %v", err)
+		}
+		future, err := sink.js.PublishAsync(sink.subject, b, nats.MsgId(alert.Hash))
+		if err != nil {
+			return fmt.Errorf("failed to publish the alert: %v", err)
+		}
+		futures = append(futures, future)
+	}
+	for _, retraction := range batch.Retractions {
+		b, err := json.Marshal(&RetractionRecord{Retraction: retraction})
+		if err != nil {
+			return fmt.Errorf("failed to encode the retraction: %v", err)
+		}
+		future, err := sink.js.PublishAsync(sink.subject, b, nats.MsgId(fmt.Sprintf("retraction-%s", retraction.BlockHash)))
+		if err != nil {
+			return fmt.Errorf("failed to publish the retraction: %v", err)
+		}
+		futures = append(futures, future)
+	}
+	return waitForAcks(ctx, futures)
+}
+
+func waitForAcks(ctx context.Context, futures []nats.PubAckFuture) error {
+	timeout := time.NewTimer(natsAckTimeout)
+	defer timeout.Stop()
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return fmt.Errorf("stream did not store the message: %v", err)
+		case <-timeout.C:
+			return fmt.Errorf("timed out waiting for the stream ack")
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for the stream ack: %v", ctx.Err())
+		}
+	}
+	return nil
+}
+
+// Close implements io.Closer.
+func (sink *NATSSink) Close() error {
+	sink.nc.Close()
+	return nil
+}
@@ -0,0 +1,202 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"zktoro/config"
+	"zktoro/services/publisher/webhooklog"
+
+	"zktoro/zktoro-core-go/clients/webhook"
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+	"zktoro/zktoro-core-go/clients/webhook/client/operations"
+	"zktoro/zktoro-core-go/utils"
+)
+
+// AlertSink receives the local mode alert batches.
+type AlertSink interface {
+	Name() string
+	// Send sends the batch. The token is the scanner JWT which authorizes the batch.
+	Send(ctx context.Context, batch *models.AlertBatch, token string) error
+	Close() error
+}
+
+// RetractionRecord is written next to the alerts by the sinks which send the alerts one by one.
+// The consumers should drop the alerts of the retracted block hash.
+type RetractionRecord struct {
+	Retraction *models.BlockRetraction `json:"retraction"`
+}
+
+// Filter selects the alerts and the metrics which a sink receives.
+type Filter struct {
+	severities map[string]bool
+	botIDs     map[string]bool
+}
+
+// NewFilter creates a new filter. Empty lists match everything.
+func NewFilter(severities, botIDs []string) *Filter {
+	filter := &Filter{}
+	if len(severities) > 0 {
+		filter.severities = make(map[string]bool)
+		for _, severity := range severities {
+			filter.severities[strings.ToUpper(severity)] = true
+		}
+	}
+	if len(botIDs) > 0 {
+		filter.botIDs = make(map[string]bool)
+		for _, botID := range botIDs {
+			filter.botIDs[strings.ToLower(botID)] = true
+		}
+	}
+	return filter
+}
+
+func (filter *Filter) matchesBot(botID string) bool {
+	return filter.botIDs == nil || filter.botIDs[strings.ToLower(botID)]
+}
+
+// Matches checks if the alert passes the filter.
+func (filter *Filter) Matches(alert *models.Alert) bool {
+	if filter.severities != nil && !filter.severities[alert.Severity] {
+		return false
+	}
+	return filter.matchesBot(alertBotID(alert))
+}
+
+// Apply returns a copy of the batch with only the matching alerts and metrics. The retractions
+// are kept since they apply to all of the alerts of a block.
+func (filter *Filter) Apply(batch *models.AlertBatch) *models.AlertBatch {
+	if filter.severities == nil && filter.botIDs == nil {
+		return batch
+	}
+	filtered := &models.AlertBatch{Retractions: batch.Retractions}
+	for _, alert := range batch.Alerts {
+		if filter.Matches(alert) {
+			filtered.Alerts = append(filtered.Alerts, alert)
+		}
+	}
+	for _, metric := range batch.Metrics {
+		if filter.matchesBot(metric.BotID) {
+			filtered.Metrics = append(filtered.Metrics, metric)
+		}
+	}
+	return filtered
+}
+
+func alertBotID(alert *models.Alert) string {
+	if alert.Source == nil || alert.Source.Bot == nil {
+		return ""
+	}
+	return alert.Source.Bot.ID
+}
+
+type filteredSink struct {
+	AlertSink
+	filter *Filter
+}
+
+// WithFilter wraps the sink so that it only receives the batches filtered by the filter.
+// Empty batches are not sent.
+func WithFilter(sink AlertSink, filter *Filter) AlertSink {
+	return &filteredSink{AlertSink: sink, filter: filter}
+}
+
+func (sink *filteredSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	batch = sink.filter.Apply(batch)
+	if len(batch.Alerts) == 0 && len(batch.Metrics) == 0 && len(batch.Retractions) == 0 {
+		return nil
+	}
+	return sink.AlertSink.Send(ctx, batch, token)
+}
+
+// ClientSink sends the batches with a webhook client.
+type ClientSink struct {
+	name   string
+	client webhook.AlertWebhookClient
+}
+
+// NewClientSink creates a new sink from the webhook client.
+func NewClientSink(name string, client webhook.AlertWebhookClient) *ClientSink {
+	return &ClientSink{name: name, client: client}
+}
+
+// Name returns the name of the sink.
+func (sink *ClientSink) Name() string {
+	return sink.name
+}
+
+// Send sends the batch with the client.
+func (sink *ClientSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	_, err := sink.client.SendAlerts(&operations.SendAlertsParams{
+		Context:       ctx,
+		Payload:       batch,
+		Authorization: utils.StringPtr(fmt.Sprintf("Bearer %s", token)),
+	})
+	return err
+}
+
+// Close implements io.Closer.
+func (sink *ClientSink) Close() error {
+	if closer, ok := sink.client.(interface{ Close() error }); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewAlertSink creates a sink from the config. Relative file paths are resolved in the logs dir.
+func NewAlertSink(sinkCfg *config.AlertSinkConfig, logsDir string) (AlertSink, error) {
+	name := sinkCfg.Name
+	if len(name) == 0 {
+		name = sinkCfg.Type
+	}
+
+	var (
+		sink AlertSink
+		err  error
+	)
+	switch sinkCfg.Type {
+	case config.AlertSinkTypeWebhook:
+		if len(sinkCfg.Webhook.URL) == 0 {
+			return nil, fmt.Errorf("sink %s: webhook url is required", name)
+		}
+		var client webhook.AlertWebhookClient
+		client, err = webhook.NewAlertWebhookClient(sinkCfg.Webhook.URL)
+		if err == nil {
+			sink = NewClientSink(name, client)
+		}
+
+	case config.AlertSinkTypeFile:
+		filePath := sinkCfg.File.Path
+		if len(filePath) == 0 {
+			filePath = fmt.Sprintf("%s.jsonl", name)
+		}
+		if !path.IsAbs(filePath) {
+			filePath = path.Join(logsDir, filePath)
+		}
+		sink, err = NewFileSink(name, filePath, int64(sinkCfg.File.MaxSizeMB)*1024*1024, sinkCfg.File.MaxFiles)
+
+	case config.AlertSinkTypeStdout:
+		sink = NewClientSink(name, &webhooklog.StdoutLogger{})
+
+	case config.AlertSinkTypeNATS:
+		if len(sinkCfg.NATS.URL) == 0 {
+			return nil, fmt.Errorf("sink %s: nats url is required", name)
+		}
+		sink, err = NewNATSSink(name, sinkCfg.NATS.URL, sinkCfg.NATS.Subject)
+
+	case config.AlertSinkTypeKafka:
+		if len(sinkCfg.Kafka.Brokers) == 0 {
+			return nil, fmt.Errorf("sink %s: kafka brokers are required", name)
+		}
+		sink = NewKafkaSink(name, sinkCfg.Kafka)
+
+	default:
+		return nil, fmt.Errorf("sink %s: unknown sink type: %s", name, sinkCfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sink %s: %v", name, err)
+	}
+	return WithFilter(sink, NewFilter(sinkCfg.Severities, sinkCfg.BotIDs)), nil
+}
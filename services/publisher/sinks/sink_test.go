@@ -0,0 +1,304 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"zktoro/zktoro-core-go/clients/webhook/client/models"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBotID1 = "0x0100000000000000000000000000000000000000000000000000000000000000"
+	testBotID2 = "0x0200000000000000000000000000000000000000000000000000000000000000"
+)
+
+func testAlert(botID, severity string) *models.Alert {
+	return &models.Alert{
+		AlertID:  fmt.Sprintf("%s-%s", botID, severity),
+		Severity: severity,
+		Source:   &models.AlertSource{Bot: &models.AlertBot{ID: botID}},
+	}
+}
+
+func testBatch() *models.AlertBatch {
+	return &models.AlertBatch{
+		Alerts: []*models.Alert{
+			testAlert(testBotID1, models.AlertSeverityHIGH),
+			testAlert(testBotID1, models.AlertSeverityLOW),
+			testAlert(testBotID2, models.AlertSeverityCRITICAL),
+		},
+		Metrics: models.BotMetricsList{
+			{BotID: testBotID1},
+			{BotID: testBotID2},
+		},
+	}
+}
+
+type recordingSink struct {
+	batches []*models.AlertBatch
+}
+
+func (sink *recordingSink) Name() string {
+	return "recording"
+}
+
+func (sink *recordingSink) Send(ctx context.Context, batch *models.AlertBatch, token string) error {
+	sink.batches = append(sink.batches, batch)
+	return nil
+}
+
+func (sink *recordingSink) Close() error {
+	return nil
+}
+
+func TestFilter(t *testing.T) {
+	r := require.New(t)
+
+	batch := testBatch()
+	r.Equal(batch, NewFilter(nil, nil).Apply(batch))
+
+	filtered := NewFilter([]string{"high", "critical"}, nil).Apply(batch)
+	r.Len(filtered.Alerts, 2)
+	r.Len(filtered.Metrics, 2)
+
+	filtered = NewFilter(nil, []string{strings.ToUpper(testBotID2)}).Apply(batch)
+	r.Len(filtered.Alerts, 1)
+	r.Equal(testBotID2, filtered.Alerts[0].Source.Bot.ID)
+	r.Len(filtered.Metrics, 1)
+	r.Equal(testBotID2, filtered.Metrics[0].BotID)
+
+	filtered = NewFilter([]string{models.AlertSeverityLOW}, []string{testBotID2}).Apply(batch)
+	r.Empty(filtered.Alerts)
+
+	// the retractions are not filtered
+	batch.Retractions = models.BlockRetractionList{{BlockNumber: 1, BlockHash: "0x1"}}
+	filtered = NewFilter([]string{models.AlertSeverityLOW}, []string{testBotID2}).Apply(batch)
+	r.Len(filtered.Retractions, 1)
+}
+
+func TestWithFilter(t *testing.T) {
+	r := require.New(t)
+
+	sink := &recordingSink{}
+	filtered := WithFilter(sink, NewFilter([]string{models.AlertSeverityINFO}, []string{testBotID1}))
+	r.NoError(filtered.Send(context.Background(), &models.AlertBatch{
+		Alerts: []*models.Alert{testAlert(testBotID1, models.AlertSeverityHIGH)},
+	}, ""))
+	r.Empty(sink.batches)
+
+	r.NoError(filtered.Send(context.Background(), testBatch(), ""))
+	r.Len(sink.batches, 1)
+	r.Empty(sink.batches[0].Alerts)
+	r.Len(sink.batches[0].Metrics, 1)
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	alertLine, err := json.Marshal(testAlert(testBotID1, models.AlertSeverityHIGH))
+	r.NoError(err)
+	lineSize := int64(len(alertLine) + 1)
+
+	sink, err := NewAlertSink(&config.AlertSinkConfig{
+		Name:   "alerts",
+		Type:   config.AlertSinkTypeFile,
+		BotIDs: []string{testBotID1},
+		File:   config.FileSinkConfig{MaxSizeMB: 1, MaxFiles: 2},
+	}, dir)
+	r.NoError(err)
+	// make the files hold two alerts each
+	sink.(*filteredSink).AlertSink.(*FileSink).maxSize = lineSize * 2
+
+	for i := 0; i < 4; i++ {
+		r.NoError(sink.Send(context.Background(), testBatch(), ""))
+	}
+	r.NoError(sink.Close())
+
+	alertsPath := path.Join(dir, "alerts.jsonl")
+	for _, filePath := range []string{alertsPath, alertsPath + ".1", alertsPath + ".2"} {
+		b, err := os.ReadFile(filePath)
+		r.NoError(err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		r.Len(lines, 2)
+		for _, line := range lines {
+			var alert models.Alert
+			r.NoError(json.Unmarshal([]byte(line), &alert))
+			r.Equal(testBotID1, alert.Source.Bot.ID)
+		}
+	}
+	_, err = os.Stat(alertsPath + ".3")
+	r.True(os.IsNotExist(err))
+}
+
+func TestFileSink_Retractions(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	sink, err := NewAlertSink(&config.AlertSinkConfig{
+		Name:       "alerts",
+		Type:       config.AlertSinkTypeFile,
+		Severities: []string{models.AlertSeverityCRITICAL},
+		File:       config.FileSinkConfig{MaxSizeMB: 1, MaxFiles: 2},
+	}, dir)
+	r.NoError(err)
+
+	batch := testBatch()
+	batch.Retractions = models.BlockRetractionList{
+		{ChainID: 1, BlockNumber: 10, BlockHash: "0xorphaned", CanonicalBlockHash: "0xcanonical"},
+	}
+	r.NoError(sink.Send(context.Background(), batch, ""))
+	r.NoError(sink.Close())
+
+	b, err := os.ReadFile(path.Join(dir, "alerts.jsonl"))
+	r.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	r.Len(lines, 2)
+
+	var alert models.Alert
+	r.NoError(json.Unmarshal([]byte(lines[0]), &alert))
+	r.Equal(models.AlertSeverityCRITICAL, alert.Severity)
+
+	var record RetractionRecord
+	r.NoError(json.Unmarshal([]byte(lines[1]), &record))
+	r.Equal(batch.Retractions[0], record.Retraction)
+}
+
+// testNATSAck is how the stand-in NATS server acknowledges the JetStream messages.
+type testNATSAck int
+
+const (
+	testNATSAckStored testNATSAck = iota
+	testNATSAckError
+	testNATSAckNone
+)
+
+// startTestNATSServer starts a stand-in NATS server which sends the published messages to the
+// channel and acknowledges them like a JetStream stream.
+func startTestNATSServer(t *testing.T, ack testNATSAck) (string, chan []byte) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	msgs := make(chan []byte, 100)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestNATSConn(conn, msgs, ack)
+		}
+	}()
+	return fmt.Sprintf("nats://%s", ln.Addr().String()), msgs
+}
+
+func serveTestNATSConn(conn net.Conn, msgs chan []byte, ack testNATSAck) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"version\":\"2.3.2\",\"proto\":1,\"headers\":true,\"max_payload\":1048576}\r\n")
+	reader := bufio.NewReader(conn)
+	var (
+		replySid string
+		seq      int
+	)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "SUB":
+			replySid = fields[len(fields)-1]
+		case "PUB", "HPUB":
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return
+			}
+			var headerSize int
+			if strings.ToUpper(fields[0]) == "HPUB" {
+				headerSize, _ = strconv.Atoi(fields[len(fields)-2])
+			}
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			msgs <- payload[headerSize:size]
+
+			reply := fields[2]
+			seq++
+			var resp string
+			switch ack {
+			case testNATSAckStored:
+				resp = fmt.Sprintf("{\"stream\":\"ALERTS\",\"seq\":%d}", seq)
+			case testNATSAckError:
+				resp = "{\"error\":{\"code\":503,\"description\":\"stream is full\"}}"
+			default:
+				continue
+			}
+			fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, replySid, len(resp), resp)
+		}
+	}
+}
+
+func newTestNATSSink(r *require.Assertions, natsURL string) AlertSink {
+	sink, err := NewAlertSink(&config.AlertSinkConfig{
+		Type:       config.AlertSinkTypeNATS,
+		Severities: []string{models.AlertSeverityHIGH, models.AlertSeverityCRITICAL},
+		NATS:       config.NATSSinkConfig{URL: natsURL, Subject: "alerts"},
+	}, "")
+	r.NoError(err)
+	return sink
+}
+
+func TestNATSSink(t *testing.T) {
+	r := require.New(t)
+
+	natsURL, msgs := startTestNATSServer(t, testNATSAckStored)
+	sink := newTestNATSSink(r, natsURL)
+	defer sink.Close()
+	r.Equal(config.AlertSinkTypeNATS, sink.Name())
+
+	r.NoError(sink.Send(context.Background(), testBatch(), ""))
+	r.Len(msgs, 2)
+	for _, severity := range []string{models.AlertSeverityHIGH, models.AlertSeverityCRITICAL} {
+		var alert models.Alert
+		r.NoError(json.Unmarshal(<-msgs, &alert))
+		r.Equal(severity, alert.Severity)
+	}
+}
+
+func TestNATSSink_NotStored(t *testing.T) {
+	r := require.New(t)
+
+	natsURL, _ := startTestNATSServer(t, testNATSAckError)
+	sink := newTestNATSSink(r, natsURL)
+	defer sink.Close()
+	r.ErrorContains(sink.Send(context.Background(), testBatch(), ""), "stream is full")
+
+	natsURL, _ = startTestNATSServer(t, testNATSAckNone)
+	sink = newTestNATSSink(r, natsURL)
+	defer sink.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancel()
+	r.Error(sink.Send(ctx, testBatch(), ""))
+}
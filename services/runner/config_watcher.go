@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"zktoro/clients/messaging"
+	"zktoro/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const configWatchInterval = time.Second * 5
+
+// configWatcher detects the valid changes in the config file.
+type configWatcher struct {
+	configPath  string
+	current     config.Config
+	lastModTime time.Time
+}
+
+func newConfigWatcher(cfg config.Config) *configWatcher {
+	watcher := &configWatcher{
+		configPath: cfg.ConfigFilePath(),
+		current:    cfg,
+	}
+	if info, err := os.Stat(watcher.configPath); err == nil {
+		watcher.lastModTime = info.ModTime()
+	}
+	return watcher
+}
+
+// check loads the config file if it was modified and returns the changes. The invalid configs are
+// not accepted so that the next modification is compared with the last valid config.
+func (watcher *configWatcher) check() (config.ConfigChanges, error) {
+	info, err := os.Stat(watcher.configPath)
+	if err != nil {
+		return config.ConfigChanges{}, fmt.Errorf("failed to check the config file: %v", err)
+	}
+	if info.ModTime().Equal(watcher.lastModTime) {
+		return config.ConfigChanges{}, nil
+	}
+	watcher.lastModTime = info.ModTime()
+
+	newCfg, err := config.LoadConfigFile(watcher.configPath)
+	if err != nil {
+		return config.ConfigChanges{}, fmt.Errorf("failed to load the config file: %w", err)
+	}
+	if err := config.Validate(&newCfg); err != nil {
+		return config.ConfigChanges{}, err
+	}
+
+	// keep the runtime values
+	newCfg.Development = watcher.current.Development
+	newCfg.ZktoroDir = watcher.current.ZktoroDir
+	newCfg.KeyDirPath = watcher.current.KeyDirPath
+	newCfg.Passphrase = watcher.current.Passphrase
+	newCfg.DIDKeyPath = watcher.current.DIDKeyPath
+	newCfg.CredentialsPath = watcher.current.CredentialsPath
+	newCfg.VpPath = watcher.current.VpPath
+
+	changes := config.DiffConfig(watcher.current, newCfg)
+	watcher.current = newCfg
+	return changes, nil
+}
+
+// watchConfig broadcasts the config file changes to the node services.
+func (runner *Runner) watchConfig() {
+	watcher := newConfigWatcher(runner.cfg)
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changes, err := watcher.check()
+			if err != nil {
+				log.WithError(err).Error("ignoring the config file change")
+				continue
+			}
+			if changes.IsEmpty() {
+				continue
+			}
+			runner.applyConfigChanges(watcher.current, changes)
+
+		case <-runner.ctx.Done():
+			return
+		}
+	}
+}
+
+func (runner *Runner) applyConfigChanges(cfg config.Config, changes config.ConfigChanges) {
+	if len(changes.RestartRequired) > 0 {
+		log.WithField("fields", changes.RestartRequired).Warn("config changes require a node restart")
+	}
+	if changes.Reloaded("log.level") {
+		if err := config.InitLogLevel(cfg); err != nil {
+			log.WithError(err).Error("failed to apply the log level")
+		}
+	}
+
+	if runner.msgClient == nil {
+		// the nats port is published on the host by the supervisor
+		msgClient, err := messaging.DialClient("runner", fmt.Sprintf("127.0.0.1:%s", config.DefaultNatsPort))
+		if err != nil {
+			log.WithError(err).Error("failed to broadcast the config changes")
+			return
+		}
+		runner.msgClient = msgClient
+	}
+	runner.msgClient.Publish(messaging.SubjectConfigChanged, changes)
+	log.WithFields(log.Fields{
+		"reloadable":      changes.Reloadable,
+		"restartRequired": changes.RestartRequired,
+	}).Info("broadcasted the config changes")
+}
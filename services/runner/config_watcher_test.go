@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"zktoro/config"
+
+	"github.com/creasty/defaults"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfig(t *testing.T, configPath, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(configPath, modTime, modTime))
+}
+
+func TestConfigWatcher(t *testing.T) {
+	r := require.New(t)
+
+	zktoroDir := t.TempDir()
+	configPath := path.Join(zktoroDir, config.DefaultConfigFileName)
+	modTime := time.Now().Add(-time.Hour)
+	writeTestConfig(t, configPath, "chainId: 1\nlog:\n  level: info\n", modTime)
+
+	cfg, err := config.LoadConfigFile(configPath)
+	r.NoError(err)
+	cfg.ZktoroDir = zktoroDir
+	watcher := newConfigWatcher(cfg)
+
+	// no modification
+	changes, err := watcher.check()
+	r.NoError(err)
+	r.True(changes.IsEmpty())
+
+	// the reloadable and the other fields are reported separately
+	modTime = modTime.Add(time.Minute)
+	writeTestConfig(t, configPath, `chainId: 137
+log:
+  level: debug
+localMode:
+  enable: true
+  botImages:
+    - bot-image
+  webhookUrl: http://localhost:8080
+`, modTime)
+	changes, err = watcher.check()
+	r.NoError(err)
+	r.ElementsMatch([]string{"log.level", "localMode.botImages", "localMode.webhookUrl"}, changes.Reloadable)
+	r.ElementsMatch([]string{"chainId", "localMode.enable"}, changes.RestartRequired)
+	r.True(changes.Reloaded("localMode.webhookUrl"))
+	r.Equal(zktoroDir, watcher.current.ZktoroDir)
+
+	// the invalid configs are ignored
+	modTime = modTime.Add(time.Minute)
+	writeTestConfig(t, configPath, "chainId: 137\nscan:\n  jsonRpc:\n    url: not-a-url\n", modTime)
+	_, err = watcher.check()
+	var validationErr *config.ValidationError
+	r.ErrorAs(err, &validationErr)
+	r.Contains(validationErr.Fields, "scan.jsonRpc.url")
+	r.Equal("debug", watcher.current.Log.Level)
+}
+
+func TestDiffConfigNoChanges(t *testing.T) {
+	r := require.New(t)
+
+	var cfg config.Config
+	r.NoError(defaults.Set(&cfg))
+	r.True(config.DiffConfig(cfg, cfg).IsEmpty())
+}
+
+func TestConfigWatcherSecrets(t *testing.T) {
+	r := require.New(t)
+
+	// a stand-in for the vault kv v2 secrets engine
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != "test-vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if req.URL.Path != "/v1/secret/data/zktoro/node" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"password":"vault-password"},"metadata":{"version":1}}}`)
+	}))
+	defer vault.Close()
+
+	zktoroDir := t.TempDir()
+	tokenPath := path.Join(zktoroDir, "vault.token")
+	r.NoError(os.WriteFile(tokenPath, []byte("test-vault-token\n"), 0600))
+	t.Setenv("TEST_IPFS_PASSWORD", "env-password")
+
+	configPath := path.Join(zktoroDir, config.DefaultConfigFileName)
+	writeTestConfig(t, configPath, fmt.Sprintf(`secrets:
+  vault:
+    address: %s
+    token: ${file:%s}
+registry:
+  password: ${vault:zktoro/node#password}
+  ipfs:
+    password: ${env:TEST_IPFS_PASSWORD}
+`, vault.URL, tokenPath), time.Now())
+
+	cfg, err := config.LoadConfigFile(configPath)
+	r.NoError(err)
+	r.Equal("test-vault-token", cfg.SecretsConfig.Vault.Token)
+	r.Equal("vault-password", cfg.Registry.Password)
+	r.Equal("env-password", cfg.Registry.IPFS.Password)
+	r.Equal("vault-password", cfg.ResolvedSecrets["${vault:zktoro/node#password}"])
+	r.Equal("registry password is <redacted>", config.RedactSecrets("registry password is vault-password"))
+
+	// the missing secrets fail the reload
+	cfg.ZktoroDir = zktoroDir
+	watcher := newConfigWatcher(cfg)
+	writeTestConfig(t, configPath, fmt.Sprintf(`secrets:
+  vault:
+    address: %s
+    token: ${file:%s}
+registry:
+  password: ${vault:zktoro/missing#password}
+`, vault.URL, tokenPath), time.Now().Add(time.Minute))
+	_, err = watcher.check()
+	var secretErr *config.SecretError
+	r.ErrorAs(err, &secretErr)
+	r.Equal("registry.password", secretErr.Field)
+	r.Equal("vault-password", watcher.current.Registry.Password)
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"sync"
@@ -33,8 +34,8 @@ type Runner struct {
 	ctx          context.Context
 	cfg          config.Config
 	imgStore     store.ZktoroImageStore
-	dockerClient clients.DockerClient
-	globalClient clients.DockerClient
+	dockerClient clients.ContainerRuntime
+	globalClient clients.ContainerRuntime
 
 	updaterContainer     *docker.Container
 	supervisorContainer  *docker.Container
@@ -43,6 +44,8 @@ type Runner struct {
 	containerMu          sync.RWMutex // protects above refs and containers
 
 	healthClient health.HealthClient
+	// lazily connected to broadcast the config changes
+	msgClient clients.MessageClient
 }
 
 // EthereumClient is useful for checking the JSON-RPC API.
@@ -52,8 +55,8 @@ type EthereumClient interface {
 
 // NewRunner creates a new runner.
 func NewRunner(ctx context.Context, cfg config.Config,
-	imgStore store.ZktoroImageStore, runnerDockerClient clients.DockerClient,
-	globalDockerClient clients.DockerClient,
+	imgStore store.ZktoroImageStore, runnerDockerClient clients.ContainerRuntime,
+	globalDockerClient clients.ContainerRuntime,
 ) *Runner {
 	return &Runner{
 		ctx:          ctx,
@@ -86,6 +89,7 @@ func (runner *Runner) Start() error {
 	}
 
 	go runner.keepContainersAlive()
+	go runner.watchConfig()
 
 	prometheus.StartCollector(runner, nil, runner.cfg.PrometheusConfig.Port)
 
@@ -327,12 +331,46 @@ func (runner *Runner) startUpdater(logger *log.Entry, latestRefs store.ImageRefs
 	return nil
 }
 
+// ensureSupervisorNetworks creates the node networks for the supervisor if the runtime cannot attach
+// the supervisor to them later. The supervisor attaches itself otherwise.
+func (runner *Runner) ensureSupervisorNetworks() (nodeNetworkID string, linkNetworkIDs []string, err error) {
+	if runner.cfg.ContainerRuntime.AttachesNetworks() {
+		return "", nil, nil
+	}
+	nodeNetworkID, err = runner.dockerClient.EnsurePublicNetwork(runner.ctx, config.DockerNetworkName)
+	if err != nil {
+		return "", nil, err
+	}
+	natsNetworkID, err := runner.dockerClient.EnsureInternalNetwork(runner.ctx, config.DockerNatsContainerName)
+	if err != nil {
+		return "", nil, err
+	}
+	return nodeNetworkID, []string{natsNetworkID}, nil
+}
+
 func (runner *Runner) startSupervisor(logger *log.Entry, latestRefs store.ImageRefs) (err error) {
 	supervisorRef := latestRefs.Supervisor
 	supervisorRef, err = runner.ensureImage(logger, "supervisor", supervisorRef)
 	if err != nil {
 		return err
 	}
+	nodeNetworkID, linkNetworkIDs, err := runner.ensureSupervisorNetworks()
+	if err != nil {
+		logger.WithError(err).Errorf("failed to create the supervisor networks")
+		return err
+	}
+	supervisorPorts := map[string]string{
+		"": config.DefaultHealthPort, // random host port
+	}
+	if !runner.cfg.AdminAPI.Disable {
+		// bound to the configured host address, i.e. the localhost by default, and to the
+		// same port which the supervisor listens on in the container
+		_, adminPort, err := net.SplitHostPort(runner.cfg.AdminAPI.Address)
+		if err != nil {
+			return fmt.Errorf("invalid admin api address: %v", err)
+		}
+		supervisorPorts[runner.cfg.AdminAPI.Address] = adminPort
+	}
 	sc, err := runner.dockerClient.StartContainer(runner.ctx, docker.ContainerConfig{
 		Name:  config.DockerSupervisorContainerName,
 		Image: supervisorRef,
@@ -342,20 +380,21 @@ func (runner *Runner) startSupervisor(logger *log.Entry, latestRefs store.ImageR
 			config.EnvHostzktoroDir: runner.cfg.ZktoroDir,
 			config.EnvReleaseInfo:   latestRefs.ReleaseInfo.String(),
 		},
-		Volumes: map[string]string{
-			// give access to host docker
-			"/var/run/docker.sock": "/var/run/docker.sock",
-			runner.cfg.ZktoroDir:   config.DefaultContainerzktoroDirPath,
-		},
-		Ports: map[string]string{
-			"": config.DefaultHealthPort, // random host port
-		},
+		// give access to the container runtime
+		Volumes: runner.cfg.ContainerRuntime.WithRuntimeVolumes(map[string]string{
+			runner.cfg.ZktoroDir: config.DefaultContainerzktoroDirPath,
+		}),
+		Privileged: runner.cfg.ContainerRuntime.PrivilegedClients(),
+		Ports:      supervisorPorts,
 		Files: map[string][]byte{
-			"passphrase": []byte(runner.cfg.Passphrase),
+			"passphrase":                  []byte(runner.cfg.Passphrase),
+			config.DefaultSecretsFileName: config.ResolvedSecretsFile(runner.cfg),
 		},
-		DialHost:    true,
-		MaxLogSize:  runner.cfg.Log.MaxLogSize,
-		MaxLogFiles: runner.cfg.Log.MaxLogFiles,
+		DialHost:       true,
+		NetworkID:      nodeNetworkID,
+		LinkNetworkIDs: linkNetworkIDs,
+		MaxLogSize:     runner.cfg.Log.MaxLogSize,
+		MaxLogFiles:    runner.cfg.Log.MaxLogFiles,
 	})
 	if err != nil {
 		logger.WithError(err).Errorf("failed to start the supervisor")
@@ -153,6 +153,14 @@ func (t *BlockAnalyzerService) Start() error {
 	go func() {
 		// for each block
 		for block := range t.cfg.BlockChannel {
+			// the bots receive the canonical blocks next so only retract the orphaned ones
+			if block.IsReorg() {
+				if err := t.cfg.AlertSender.NotifyRetractions(block.Reorg.ToRetractions()); err != nil {
+					log.WithError(err).Error("failed to notify retractions")
+				}
+				continue
+			}
+
 			// convert to message
 			blockEvt, err := block.ToMessage()
 			if err != nil {
@@ -72,7 +72,7 @@ func ContainerMain(name string, getServices func(ctx context.Context, cfg config
 		return
 	}
 	log.SetLevel(lvl)
-	log.SetFormatter(&log.JSONFormatter{})
+	log.SetFormatter(&config.RedactingFormatter{Formatter: &log.JSONFormatter{}})
 	logger.Info("starting")
 	defer logger.Info("exiting")
 
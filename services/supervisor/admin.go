@@ -0,0 +1,325 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"zktoro/clients"
+	"zktoro/clients/admin"
+	"zktoro/clients/docker"
+	"zktoro/config"
+	"zktoro/services/components/containers"
+	"zktoro/services/components/lifecycle"
+	"zktoro/services/components/registry"
+	"zktoro/services/listener"
+	"zktoro/store"
+
+	"github.com/docker/docker/api/types"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultBotLogsTail    = 100
+	maxBotLogsTail        = 10000
+	adminShutdownTimeout  = time.Second * 5
+	adminReadTimeout      = time.Second * 30
+	adminOperationTimeout = time.Minute * 2
+)
+
+// adminAPI is the local operator API of the supervisor.
+type adminAPI struct {
+	botOperator lifecycle.BotOperator
+	botActivity lifecycle.BotActivityReader
+	botClient   containers.BotClient
+	client      clients.ContainerRuntime
+	// nil if not in local mode
+	botImages registry.LocalBotImages
+	// lets the bot changes take effect without waiting for the next round
+	refresh func()
+
+	srv *http.Server
+}
+
+// startAdminAPI starts the admin API for the bots of the supervisor.
+func (sup *SupervisorService) startAdminAPI() error {
+	api := &adminAPI{
+		botOperator: sup.botLifecycle.BotOperator,
+		botActivity: sup.botLifecycle.BotActivity,
+		botClient:   sup.botLifecycle.BotClient,
+		client:      sup.client,
+		refresh:     sup.refreshBots,
+	}
+	if sup.config.Config.LocalModeConfig.Enable {
+		api.botImages, _ = sup.botLifecycleConfig.BotRegistry.(registry.LocalBotImages)
+	}
+	if err := api.start(sup.config.Config.AdminAPI, sup.config.Config.AdminTokenPath()); err != nil {
+		return err
+	}
+	sup.adminAPI = api
+	return nil
+}
+
+// Router creates the routes of the admin API.
+func (api *adminAPI) Router() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/bots", api.handleListBots).Methods(http.MethodGet)
+	r.HandleFunc("/bots/{bot}/restart", api.handleRestartBot).Methods(http.MethodPost)
+	r.HandleFunc("/bots/{bot}/pause", api.handlePauseBot).Methods(http.MethodPost)
+	r.HandleFunc("/bots/{bot}/resume", api.handleResumeBot).Methods(http.MethodPost)
+	r.HandleFunc("/bots/{bot}/logs", api.handleBotLogs).Methods(http.MethodGet)
+	r.HandleFunc("/bot-images", api.handleAddBotImage).Methods(http.MethodPost)
+	r.HandleFunc("/bot-images", api.handleRemoveBotImage).Methods(http.MethodDelete)
+	return r
+}
+
+// start serves the admin API with the token auth in the background.
+func (api *adminAPI) start(adminCfg config.AdminAPIConfig, tokenPath string) error {
+	token := adminCfg.AuthToken
+	if token == "" {
+		var err error
+		token, err = listener.LoadOrCreateToken(tokenPath)
+		if err != nil {
+			return fmt.Errorf("failed to load the admin api token: %v", err)
+		}
+	}
+
+	lis, listenAddress, err := listenAdminAPI(adminCfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the admin api: %v", err)
+	}
+	api.srv = &http.Server{
+		Handler:           listener.WithBearerAuth(token, api.Router()),
+		ReadHeaderTimeout: adminReadTimeout,
+		ReadTimeout:       adminReadTimeout,
+		WriteTimeout:      adminOperationTimeout,
+	}
+	go func() {
+		log.WithFields(log.Fields{
+			"address":       adminCfg.Address,
+			"listenAddress": listenAddress,
+		}).Info("starting admin api")
+		if err := api.srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("admin api server error")
+		}
+	}()
+	return nil
+}
+
+// listenAdminAPI listens on the configured admin API address. The supervisor runs in a container
+// so a loopback host or a host os address is not reachable or not available in the container:
+// the API listens on all container interfaces with the configured port in that case and the
+// runner publishes the port only on the configured host address.
+func listenAdminAPI(address string) (net.Listener, string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid admin api address '%s': %v", address, err)
+	}
+	ip := net.ParseIP(host)
+	if host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		lis, err := net.Listen("tcp", address)
+		if err == nil {
+			return lis, address, nil
+		}
+		if !errors.Is(err, syscall.EADDRNOTAVAIL) {
+			return nil, "", err
+		}
+	}
+	listenAddress := net.JoinHostPort("", port)
+	lis, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, "", err
+	}
+	return lis, listenAddress, nil
+}
+
+func (api *adminAPI) stop() {
+	if api.srv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+	defer cancel()
+	if err := api.srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Warn("failed to stop the admin api")
+	}
+}
+
+func (api *adminAPI) handleListBots(w http.ResponseWriter, req *http.Request) {
+	botContainers, err := api.botClient.LoadBotContainers(req.Context())
+	if err != nil {
+		writeAdminError(w, fmt.Errorf("failed to load bot containers: %v", err))
+		return
+	}
+
+	managedBots := api.botOperator.ListBots()
+	bots := make([]*admin.BotInfo, 0, len(managedBots))
+	for _, managedBot := range managedBots {
+		bots = append(bots, api.toBotInfo(managedBot, botContainers))
+	}
+	writeAdminJSON(w, http.StatusOK, bots)
+}
+
+func (api *adminAPI) toBotInfo(managedBot lifecycle.ManagedBot, botContainers []types.Container) *admin.BotInfo {
+	containerName := managedBot.Config.ContainerName()
+	info := &admin.BotInfo{
+		Config:           managedBot.Config,
+		ContainerName:    containerName,
+		State:            string(managedBot.State.State),
+		StateReason:      managedBot.State.Reason,
+		StateUpdatedAt:   timeOrNil(managedBot.State.UpdatedAt),
+		RecentFailures:   len(managedBot.State.Failures),
+		BackoffUntil:     timeOrNil(managedBot.State.BackoffUntil),
+		QuarantinedUntil: timeOrNil(managedBot.State.QuarantinedUntil),
+	}
+	for _, botContainer := range botContainers {
+		if docker.GetContainerName(botContainer) != containerName {
+			continue
+		}
+		info.Container = &admin.ContainerInfo{
+			ID:      botContainer.ID,
+			Image:   botContainer.Image,
+			State:   botContainer.State,
+			Status:  botContainer.Status,
+			Created: time.Unix(botContainer.Created, 0).UTC(),
+		}
+		break
+	}
+	if activity, ok := api.botActivity.GetBotActivity(managedBot.Config.ID); ok {
+		info.LastActivity = timeOrNil(activity.LastActivity)
+		for _, metric := range activity.RecentMetrics {
+			info.RecentMetrics = append(info.RecentMetrics, &admin.MetricInfo{
+				Name:      metric.Name,
+				Timestamp: metric.Timestamp,
+				Value:     metric.Value,
+				Details:   metric.Details,
+			})
+		}
+	}
+	return info
+}
+
+func (api *adminAPI) handleRestartBot(w http.ResponseWriter, req *http.Request) {
+	api.doBotAction(w, req, api.botOperator.RestartBot)
+}
+
+func (api *adminAPI) handlePauseBot(w http.ResponseWriter, req *http.Request) {
+	api.doBotAction(w, req, api.botOperator.PauseBot)
+}
+
+func (api *adminAPI) handleResumeBot(w http.ResponseWriter, req *http.Request) {
+	api.doBotAction(w, req, api.botOperator.ResumeBot)
+}
+
+func (api *adminAPI) doBotAction(w http.ResponseWriter, req *http.Request, action func(context.Context, string) error) {
+	ctx, cancel := context.WithTimeout(req.Context(), adminOperationTimeout)
+	defer cancel()
+	if err := action(ctx, mux.Vars(req)["bot"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	api.refresh()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *adminAPI) handleBotLogs(w http.ResponseWriter, req *http.Request) {
+	botRef := mux.Vars(req)["bot"]
+	tail := defaultBotLogsTail
+	if tailStr := req.URL.Query().Get("tail"); tailStr != "" {
+		var err error
+		tail, err = strconv.Atoi(tailStr)
+		if err != nil || tail <= 0 || tail > maxBotLogsTail {
+			writeAdminJSON(w, http.StatusBadRequest, &admin.ErrorResponse{Error: "invalid tail"})
+			return
+		}
+	}
+
+	var botLogs []*admin.BotLogs
+	for _, managedBot := range api.botOperator.ListBots() {
+		containerName := managedBot.Config.ContainerName()
+		if managedBot.Config.ID != botRef && containerName != botRef {
+			continue
+		}
+		container, err := api.client.GetContainerByName(req.Context(), containerName)
+		if err != nil {
+			// held bots may not have containers
+			continue
+		}
+		logs, err := api.client.GetContainerLogs(req.Context(), container.ID, strconv.Itoa(tail), -1)
+		if err != nil {
+			writeAdminError(w, fmt.Errorf("failed to get the logs of %s: %v", containerName, err))
+			return
+		}
+		botLogs = append(botLogs, &admin.BotLogs{ContainerName: containerName, Logs: logs})
+	}
+	if len(botLogs) == 0 {
+		writeAdminError(w, lifecycle.ErrBotNotFound)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, botLogs)
+}
+
+func (api *adminAPI) handleAddBotImage(w http.ResponseWriter, req *http.Request) {
+	api.doBotImageAction(w, req, func(botImages registry.LocalBotImages, image string) error {
+		return botImages.AddBotImage(image)
+	})
+}
+
+func (api *adminAPI) handleRemoveBotImage(w http.ResponseWriter, req *http.Request) {
+	api.doBotImageAction(w, req, func(botImages registry.LocalBotImages, image string) error {
+		return botImages.RemoveBotImage(image)
+	})
+}
+
+func (api *adminAPI) doBotImageAction(
+	w http.ResponseWriter, req *http.Request, action func(registry.LocalBotImages, string) error,
+) {
+	if api.botImages == nil {
+		writeAdminError(w, registry.ErrNotLocalMode)
+		return
+	}
+	var imageReq admin.BotImageRequest
+	if err := json.NewDecoder(req.Body).Decode(&imageReq); err != nil || imageReq.Image == "" {
+		writeAdminJSON(w, http.StatusBadRequest, &admin.ErrorResponse{Error: "bad bot image request body"})
+		return
+	}
+	if err := action(api.botImages, imageReq.Image); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	api.refresh()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, lifecycle.ErrBotNotFound), errors.Is(err, store.ErrBotImageNotFound):
+		writeAdminJSON(w, http.StatusNotFound, &admin.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, registry.ErrNotLocalMode):
+		writeAdminJSON(w, http.StatusConflict, &admin.ErrorResponse{Error: err.Error()})
+	default:
+		log.WithError(err).Error("admin api error")
+		writeAdminJSON(w, http.StatusInternalServerError, &admin.ErrorResponse{Error: err.Error()})
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("failed to write admin api response")
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,162 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"zktoro/clients/admin"
+	"zktoro/config"
+	mock_containers "zktoro/services/components/containers/mocks"
+	"zktoro/services/components/lifecycle"
+	"zktoro/services/components/registry"
+	"zktoro/services/listener"
+	"zktoro/zktoro-core-go/protocol"
+
+	"github.com/docker/docker/api/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "test-token"
+
+type testBotOperator struct {
+	bots   []lifecycle.ManagedBot
+	paused []string
+}
+
+func (op *testBotOperator) ListBots() []lifecycle.ManagedBot {
+	return op.bots
+}
+
+func (op *testBotOperator) RestartBot(ctx context.Context, botRef string) error {
+	return nil
+}
+
+func (op *testBotOperator) PauseBot(ctx context.Context, botRef string) error {
+	for _, bot := range op.bots {
+		if bot.Config.ID == botRef {
+			op.paused = append(op.paused, botRef)
+			return nil
+		}
+	}
+	return lifecycle.ErrBotNotFound
+}
+
+func (op *testBotOperator) ResumeBot(ctx context.Context, botRef string) error {
+	return nil
+}
+
+type testBotActivity map[string]lifecycle.BotActivity
+
+func (activity testBotActivity) GetBotActivity(botID string) (lifecycle.BotActivity, bool) {
+	botActivity, ok := activity[botID]
+	return botActivity, ok
+}
+
+func TestAdminAPI(t *testing.T) {
+	r := require.New(t)
+
+	botConfig := config.AgentConfig{ID: "0x1", Image: "bot-image"}
+	lastActivity := time.Now().UTC().Add(-time.Minute)
+	operator := &testBotOperator{
+		bots: []lifecycle.ManagedBot{
+			{Config: botConfig, State: lifecycle.BotStateRecord{BotID: botConfig.ID, State: lifecycle.BotStateRunning}},
+		},
+	}
+	botClient := mock_containers.NewMockBotClient(gomock.NewController(t))
+	var refreshed int
+	api := &adminAPI{
+		botOperator: operator,
+		botActivity: testBotActivity{
+			botConfig.ID: {
+				LastActivity:  lastActivity,
+				RecentMetrics: []*protocol.AgentMetric{{Name: "tx.request", Value: 1}},
+			},
+		},
+		botClient: botClient,
+		refresh:   func() { refreshed++ },
+	}
+	srv := httptest.NewServer(listener.WithBearerAuth(testAdminToken, api.Router()))
+	defer srv.Close()
+
+	// the token is required
+	_, err := admin.NewClient(srv.URL, "wrong-token").ListBots(context.Background())
+	r.Error(err)
+
+	client := admin.NewClient(srv.URL, testAdminToken)
+
+	botClient.EXPECT().LoadBotContainers(gomock.Any()).Return([]types.Container{
+		{
+			ID:     "container-id",
+			Names:  []string{"/" + botConfig.ContainerName()},
+			State:  "running",
+			Status: "Up 5 minutes",
+		},
+	}, nil)
+	bots, err := client.ListBots(context.Background())
+	r.NoError(err)
+	r.Len(bots, 1)
+	r.Equal(botConfig.ID, bots[0].Config.ID)
+	r.Equal("running", bots[0].State)
+	r.NotNil(bots[0].Container)
+	r.Equal("container-id", bots[0].Container.ID)
+	r.NotNil(bots[0].LastActivity)
+	r.True(lastActivity.Equal(*bots[0].LastActivity))
+	r.Len(bots[0].RecentMetrics, 1)
+
+	r.NoError(client.PauseBot(context.Background(), botConfig.ID))
+	r.Equal([]string{botConfig.ID}, operator.paused)
+	r.Equal(1, refreshed)
+
+	err = client.PauseBot(context.Background(), "0x2")
+	r.Error(err)
+	r.True(strings.HasPrefix(err.Error(), "404"))
+
+	// the bot images can be changed only in local mode
+	err = client.AddBotImage(context.Background(), "new-bot-image")
+	r.Error(err)
+	r.Contains(err.Error(), registry.ErrNotLocalMode.Error())
+}
+
+func TestAdminAPIBadTail(t *testing.T) {
+	r := require.New(t)
+
+	api := &adminAPI{botOperator: &testBotOperator{}}
+	req := httptest.NewRequest(http.MethodGet, "/bots/0x1/logs?tail=abc", nil)
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, req)
+	r.Equal(http.StatusBadRequest, w.Code)
+
+	var errResp admin.ErrorResponse
+	r.NoError(json.NewDecoder(w.Body).Decode(&errResp))
+	r.NotEmpty(errResp.Error)
+}
+
+func TestListenAdminAPI(t *testing.T) {
+	r := require.New(t)
+
+	// the loopback addresses are not reachable from the host os through the published port
+	lis, listenAddress, err := listenAdminAPI("127.0.0.1:0")
+	r.NoError(err)
+	r.Equal(":0", listenAddress)
+	r.NoError(lis.Close())
+
+	// the addresses of the host os are not available in the container
+	lis, listenAddress, err = listenAdminAPI("192.0.2.1:0")
+	r.NoError(err)
+	r.Equal(":0", listenAddress)
+	r.NoError(lis.Close())
+
+	lis, listenAddress, err = listenAdminAPI("0.0.0.0:0")
+	r.NoError(err)
+	r.Equal("0.0.0.0:0", listenAddress)
+	r.NoError(lis.Close())
+
+	_, _, err = listenAdminAPI("8555")
+	r.Error(err)
+}
@@ -77,9 +77,9 @@ func (sup *SupervisorService) doSyncAgentLogs() error {
 	}
 
 	if len(sendLogs) > 0 {
-		scannerJwt, err := security.CreateScannerJWT(sup.config.Key, map[string]interface{}{
+		scannerJwt, err := security.CreateScannerJWTWithSigner(sup.config.Signer, security.WithDIDLinkage(map[string]interface{}{
 			"access": "agent_logs",
-		})
+		}, sup.didLinkage))
 		if err != nil {
 			return fmt.Errorf("failed to create scanner token: %v", err)
 		}
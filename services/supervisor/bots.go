@@ -18,10 +18,21 @@ func (sup *SupervisorService) refreshBotContainers() {
 
 		case <-time.After(time.Minute):
 			sup.doRefreshBotContainers()
+
+		case <-sup.refreshCh:
+			sup.doRefreshBotContainers()
 		}
 	}
 }
 
+// refreshBots requests a refresh of the bot containers without waiting for the next round.
+func (sup *SupervisorService) refreshBots() {
+	select {
+	case sup.refreshCh <- struct{}{}:
+	default:
+	}
+}
+
 func (sup *SupervisorService) doRefreshBotContainers() {
 	if err := sup.botLifecycle.BotManager.ManageBots(sup.ctx); err != nil {
 		log.WithError(err).Error("error while managing bots")
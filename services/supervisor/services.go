@@ -2,6 +2,7 @@ package supervisor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -17,6 +18,8 @@ import (
 	"zktoro/services"
 	"zktoro/services/components"
 	"zktoro/services/components/containers"
+	"zktoro/services/components/registry"
+	"zktoro/store"
 
 	"zktoro/zktoro-core-go/clients/agentlogs"
 	"zktoro/zktoro-core-go/clients/health"
@@ -25,7 +28,6 @@ import (
 	"zktoro/zktoro-core-go/release"
 	"zktoro/zktoro-core-go/security"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ipfs/go-cid"
 	log "github.com/sirupsen/logrus"
 )
@@ -45,8 +47,8 @@ var knownServiceContainerNames = []string{
 type SupervisorService struct {
 	ctx context.Context
 
-	client       clients.DockerClient
-	globalClient clients.DockerClient
+	client       clients.ContainerRuntime
+	globalClient clients.ContainerRuntime
 
 	botLifecycleConfig components.BotLifecycleConfig
 	botLifecycle       components.BotLifecycle
@@ -83,12 +85,17 @@ type SupervisorService struct {
 	sendAgentLogs func(agents agentlogs.Agents, authToken string) error
 	prevAgentLogs agentlogs.Agents
 	inspectionCh  chan *protocol.InspectionResults
+
+	adminAPI  *adminAPI
+	refreshCh chan struct{}
+
+	didLinkage string
 }
 
 type SupervisorServiceConfig struct {
 	Config             config.Config
 	Passphrase         string
-	Key                *keystore.Key
+	Signer             security.Signer
 	BotLifecycleConfig components.BotLifecycleConfig
 }
 
@@ -104,6 +111,12 @@ func (sup *SupervisorService) Start() error {
 		return err
 	}
 
+	if !sup.config.Config.AdminAPI.Disable {
+		if err := sup.startAdminAPI(); err != nil {
+			return err
+		}
+	}
+
 	go sup.healthCheck()
 	go sup.refreshBotContainers()
 
@@ -157,20 +170,38 @@ func (sup *SupervisorService) start() error {
 	}
 	commonNodeImage := supervisorContainer.Image
 
+	// the runner starts the supervisor in these networks if the runtime cannot attach it later
+	attachesNetworks := sup.config.Config.ContainerRuntime.AttachesNetworks()
+
 	nodeNetworkID, err := sup.client.EnsurePublicNetwork(sup.ctx, config.DockerNetworkName)
 	if err != nil {
 		return err
 	}
-	if err := sup.client.AttachNetwork(sup.ctx, supervisorContainer.ID, nodeNetworkID); err != nil {
-		return fmt.Errorf("failed to attach supervisor container to node network: %v", err)
+	if attachesNetworks {
+		if err := sup.client.AttachNetwork(sup.ctx, supervisorContainer.ID, nodeNetworkID); err != nil {
+			return fmt.Errorf("failed to attach supervisor container to node network: %v", err)
+		}
 	}
 
 	natsNetworkID, err := sup.client.EnsureInternalNetwork(sup.ctx, config.DockerNatsContainerName)
 	if err != nil {
 		return err
 	}
-	if err := sup.client.AttachNetwork(sup.ctx, supervisorContainer.ID, natsNetworkID); err != nil {
-		return fmt.Errorf("failed to attach supervisor container to nats network: %v", err)
+	if attachesNetworks {
+		if err := sup.client.AttachNetwork(sup.ctx, supervisorContainer.ID, natsNetworkID); err != nil {
+			return fmt.Errorf("failed to attach supervisor container to nats network: %v", err)
+		}
+	}
+
+	// the containers which the bots talk to join the shared bot network on creation
+	// if the runtime cannot attach them to the bot networks later
+	botServiceNetworkIDs := []string{natsNetworkID}
+	if !attachesNetworks {
+		botNetworkID, err := sup.client.EnsurePublicNetwork(sup.ctx, config.DockerBotNetworkName)
+		if err != nil {
+			return err
+		}
+		botServiceNetworkIDs = append(botServiceNetworkIDs, botNetworkID)
 	}
 
 	manageIpfsDir(sup.config.Config)
@@ -261,17 +292,15 @@ func (sup *SupervisorService) start() error {
 				Env: map[string]string{
 					config.EnvReleaseInfo: releaseInfo.String(),
 				},
-				Volumes: map[string]string{
-					// give access to host docker
-					"/var/run/docker.sock": "/var/run/docker.sock",
-					hostzktoroDir:          config.DefaultContainerzktoroDirPath,
-				},
+				// give access to the container runtime
+				Volumes: sup.config.Config.ContainerRuntime.WithRuntimeVolumes(map[string]string{
+					hostzktoroDir: config.DefaultContainerzktoroDirPath,
+				}),
+				Privileged: sup.config.Config.ContainerRuntime.PrivilegedClients(),
 				Ports: map[string]string{
 					"": config.DefaultHealthPort, // random host port
 				},
-				Files: map[string][]byte{
-					"passphrase": []byte(sup.config.Passphrase),
-				},
+				Files:       sup.serviceFiles(),
 				DialHost:    true,
 				NetworkID:   nodeNetworkID,
 				MaxLogFiles: sup.maxLogFiles,
@@ -293,17 +322,20 @@ func (sup *SupervisorService) start() error {
 			Name:  config.DockerJSONRPCProxyContainerName,
 			Image: commonNodeImage,
 			Cmd:   []string{config.DefaultzktoroNodeBinaryPath, "json-rpc"},
-			Volumes: map[string]string{
-				// give access to host docker
-				"/var/run/docker.sock": "/var/run/docker.sock",
-				hostzktoroDir:          config.DefaultContainerzktoroDirPath,
-			},
+			// give access to the container runtime
+			Volumes: sup.config.Config.ContainerRuntime.WithRuntimeVolumes(map[string]string{
+				hostzktoroDir: config.DefaultContainerzktoroDirPath,
+			}),
+			Privileged: sup.config.Config.ContainerRuntime.PrivilegedClients(),
 			Ports: map[string]string{
 				"": config.DefaultHealthPort, // random host port
 			},
+			Files: map[string][]byte{
+				config.DefaultSecretsFileName: config.ResolvedSecretsFile(sup.config.Config),
+			},
 			DialHost:       true,
 			NetworkID:      nodeNetworkID,
-			LinkNetworkIDs: []string{natsNetworkID},
+			LinkNetworkIDs: botServiceNetworkIDs,
 			MaxLogFiles:    sup.maxLogFiles,
 			MaxLogSize:     sup.maxLogSize,
 		},
@@ -318,20 +350,18 @@ func (sup *SupervisorService) start() error {
 			Name:  config.DockerPublicAPIProxyContainerName,
 			Image: commonNodeImage,
 			Cmd:   []string{config.DefaultzktoroNodeBinaryPath, "public-api"},
-			Volumes: map[string]string{
-				// give access to host docker
-				"/var/run/docker.sock": "/var/run/docker.sock",
-				hostzktoroDir:          config.DefaultContainerzktoroDirPath,
-			},
+			// give access to the container runtime
+			Volumes: sup.config.Config.ContainerRuntime.WithRuntimeVolumes(map[string]string{
+				hostzktoroDir: config.DefaultContainerzktoroDirPath,
+			}),
+			Privileged: sup.config.Config.ContainerRuntime.PrivilegedClients(),
 			Ports: map[string]string{
 				"": config.DefaultHealthPort, // random host port
 			},
-			Files: map[string][]byte{
-				"passphrase": []byte(sup.config.Passphrase),
-			},
+			Files:          sup.serviceFiles(),
 			DialHost:       true,
 			NetworkID:      nodeNetworkID,
-			LinkNetworkIDs: []string{natsNetworkID},
+			LinkNetworkIDs: botServiceNetworkIDs,
 			MaxLogFiles:    sup.maxLogFiles,
 			MaxLogSize:     sup.maxLogSize,
 		},
@@ -363,9 +393,7 @@ func (sup *SupervisorService) start() error {
 			Ports: map[string]string{
 				"": config.DefaultHealthPort, // random host port
 			},
-			Files: map[string][]byte{
-				"passphrase": []byte(sup.config.Passphrase),
-			},
+			Files:          sup.serviceFiles(),
 			DialHost:       true,
 			NetworkID:      nodeNetworkID,
 			LinkNetworkIDs: []string{natsNetworkID},
@@ -404,12 +432,10 @@ func (sup *SupervisorService) start() error {
 			Ports: map[string]string{
 				"": config.DefaultHealthPort, // random host port
 			},
-			Files: map[string][]byte{
-				"passphrase": []byte(sup.config.Passphrase),
-			},
+			Files:          sup.serviceFiles(),
 			DialHost:       true,
 			NetworkID:      nodeNetworkID,
-			LinkNetworkIDs: []string{natsNetworkID},
+			LinkNetworkIDs: botServiceNetworkIDs,
 			MaxLogFiles:    sup.maxLogFiles,
 			MaxLogSize:     sup.maxLogSize,
 		},
@@ -427,20 +453,18 @@ func (sup *SupervisorService) start() error {
 			Env: map[string]string{
 				config.EnvReleaseInfo: releaseInfo.String(),
 			},
-			Volumes: map[string]string{
-				// give access to host docker
-				"/var/run/docker.sock": "/var/run/docker.sock",
-				hostzktoroDir:          config.DefaultContainerzktoroDirPath,
-			},
+			// give access to the container runtime
+			Volumes: sup.config.Config.ContainerRuntime.WithRuntimeVolumes(map[string]string{
+				hostzktoroDir: config.DefaultContainerzktoroDirPath,
+			}),
+			Privileged: sup.config.Config.ContainerRuntime.PrivilegedClients(),
 			Ports: map[string]string{
 				"": config.DefaultHealthPort, // random host port
 			},
-			Files: map[string][]byte{
-				"passphrase": []byte(sup.config.Passphrase),
-			},
+			Files:          sup.serviceFiles(),
 			DialHost:       true,
 			NetworkID:      nodeNetworkID,
-			LinkNetworkIDs: []string{natsNetworkID},
+			LinkNetworkIDs: botServiceNetworkIDs,
 			MaxLogFiles:    sup.maxLogFiles,
 			MaxLogSize:     sup.maxLogSize,
 		},
@@ -471,6 +495,39 @@ func (sup *SupervisorService) registerMessageHandlers() {
 	if *sup.config.Config.InspectionConfig.InspectAtStartup {
 		sup.msgClient.Subscribe(messaging.SubjectInspectionDone, messaging.InspectionResultsHandler(sup.handleInspectionResults))
 	}
+	services.SubscribeToConfigChanges(sup.msgClient, sup.Name(), sup.reloadConfig)
+}
+
+// reloadConfig applies the local mode bot image changes.
+func (sup *SupervisorService) reloadConfig(cfg config.Config, changes config.ConfigChanges) error {
+	if !changes.Reloaded("localMode.botImages") || !sup.config.Config.LocalModeConfig.Enable {
+		return nil
+	}
+	botImages, ok := sup.botLifecycleConfig.BotRegistry.(registry.LocalBotImages)
+	if !ok {
+		return nil
+	}
+
+	newImages := make(map[string]bool)
+	for _, image := range cfg.LocalModeConfig.BotImages {
+		newImages[image] = true
+	}
+	for _, image := range sup.config.Config.LocalModeConfig.BotImages {
+		if newImages[image] {
+			continue
+		}
+		if err := botImages.RemoveBotImage(image); err != nil && !errors.Is(err, store.ErrBotImageNotFound) {
+			return err
+		}
+	}
+	for _, image := range cfg.LocalModeConfig.BotImages {
+		if err := botImages.AddBotImage(image); err != nil {
+			return err
+		}
+	}
+	sup.config.Config.LocalModeConfig.BotImages = cfg.LocalModeConfig.BotImages
+	sup.refreshBots()
+	return nil
 }
 
 func manageIpfsDir(cfg config.Config) error {
@@ -637,10 +694,18 @@ func (sup *SupervisorService) doSyncTelemetryDataToCustomHandler() error {
 	return sup.doSyncTelemetryData(customURL)
 }
 
+// serviceFiles are the passphrase and the resolved secrets which are copied to the service containers.
+func (sup *SupervisorService) serviceFiles() map[string][]byte {
+	return map[string][]byte{
+		"passphrase":                  []byte(sup.config.Passphrase),
+		config.DefaultSecretsFileName: config.ResolvedSecretsFile(sup.config.Config),
+	}
+}
+
 func (sup *SupervisorService) doSyncTelemetryData(destUrl string) error {
-	scannerJwt, err := security.CreateScannerJWT(sup.config.Key, map[string]interface{}{
+	scannerJwt, err := security.CreateScannerJWTWithSigner(sup.config.Signer, security.WithDIDLinkage(map[string]interface{}{
 		"access": "telemetry",
-	})
+	}, sup.didLinkage))
 	if err != nil {
 		return err
 	}
@@ -682,6 +747,10 @@ func (sup *SupervisorService) Stop() error {
 	// we don't want tear downs to be aborted by the closed service context
 	ctx := context.Background()
 
+	if sup.adminAPI != nil {
+		sup.adminAPI.stop()
+	}
+
 	if !services.IsGracefulShutdown() {
 		sup.botLifecycle.BotManager.TearDownRunningBots(ctx)
 	}
@@ -757,13 +826,13 @@ func (sup *SupervisorService) handleInspectionResults(payload *protocol.Inspecti
 }
 
 func NewSupervisorService(ctx context.Context, cfg SupervisorServiceConfig) (*SupervisorService, error) {
-	dockerClient, err := docker.NewDockerClient(containers.LabelzktoroSupervisor)
+	dockerClient, err := clients.NewContainerRuntime(cfg.Config.ContainerRuntime, containers.LabelzktoroSupervisor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create the docker client: %v", err)
+		return nil, fmt.Errorf("failed to create the container runtime client: %v", err)
 	}
-	globalClient, err := docker.NewDockerClient("")
+	globalClient, err := clients.NewContainerRuntime(cfg.Config.ContainerRuntime, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create the global docker client: %v", err)
+		return nil, fmt.Errorf("failed to create the global container runtime client: %v", err)
 	}
 
 	releaseClient, err := release.NewClient(cfg.Config.Registry.IPFS.GatewayURL, []string{cfg.Config.Registry.ReleaseDistributionUrl})
@@ -778,11 +847,17 @@ func NewSupervisorService(ctx context.Context, cfg SupervisorServiceConfig) (*Su
 		releaseClient:      releaseClient,
 		botLifecycleConfig: cfg.BotLifecycleConfig,
 		config:             cfg,
-		healthClient:       health.NewClient(),
+		healthClient:       health.NewClient(health.WithRedactor(config.RedactSecrets)),
 		sendAgentLogs:      agentlogs.NewClient(cfg.Config.AgentLogsConfig.URL).SendLogs,
 		inspectionCh:       make(chan *protocol.InspectionResults),
+		refreshCh:          make(chan struct{}, 1),
 	}
 	sup.autoUpdatesDisabled.Set(strconv.FormatBool(cfg.Config.AutoUpdate.Disable))
 
+	sup.didLinkage, err = config.LoadDIDLinkageInContainer(cfg.Config, cfg.Signer.Address())
+	if err != nil {
+		log.WithError(err).Warn("not embedding the DID linkage")
+	}
+
 	return sup, nil
 }
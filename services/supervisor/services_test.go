@@ -53,8 +53,8 @@ func TestSuite(t *testing.T) {
 type Suite struct {
 	r *require.Assertions
 
-	dockerClient  *mock_clients.MockDockerClient
-	globalClient  *mock_clients.MockDockerClient
+	dockerClient  *mock_clients.MockContainerRuntime
+	globalClient  *mock_clients.MockContainerRuntime
 	releaseClient *mrelease.MockClient
 
 	msgClient *mock_clients.MockMessageClient
@@ -104,8 +104,8 @@ func (s *Suite) SetupTest() {
 	s.r = require.New(s.T())
 	os.Setenv(config.EnvHostzktoroDir, "/tmp/zktoro")
 	ctrl := gomock.NewController(s.T())
-	s.dockerClient = mock_clients.NewMockDockerClient(ctrl)
-	s.globalClient = mock_clients.NewMockDockerClient(ctrl)
+	s.dockerClient = mock_clients.NewMockContainerRuntime(ctrl)
+	s.globalClient = mock_clients.NewMockContainerRuntime(ctrl)
 	s.releaseClient = mrelease.NewMockClient(ctrl)
 	s.botClient = mock_containers.NewMockBotClient(ctrl)
 
@@ -127,7 +127,7 @@ func (s *Suite) SetupTest() {
 		msgClient:     s.msgClient,
 		releaseClient: s.releaseClient,
 	}
-	supervisor.config.Key = key
+	supervisor.config.Signer = security.NewKeySigner(key)
 	supervisor.config.Config.TelemetryConfig.Disable = true
 	supervisor.config.Config.Log.Level = "debug"
 	supervisor.config.Config.ChainID = 1
@@ -175,6 +175,7 @@ func (s *Suite) initialContainerCheck() {
 
 func (s *Suite) TestStartServices() {
 	s.msgClient.EXPECT().Subscribe(messaging.SubjectMetricAgent, gomock.Any())
+	s.msgClient.EXPECT().Subscribe(messaging.SubjectConfigChanged, gomock.Any())
 
 	s.releaseClient.EXPECT().GetReleaseManifest(gomock.Any()).Return(&release.ReleaseManifest{}, nil).AnyTimes()
 
@@ -21,8 +21,9 @@ import (
 )
 
 var (
-	errInvalidBot = errors.New("invalid bot")
-	ErrLocalMode  = errors.New("feature not available (private/local registry)")
+	errInvalidBot       = errors.New("invalid bot")
+	ErrLocalMode        = errors.New("feature not available (private/local registry)")
+	ErrBotImageNotFound = errors.New("bot image not found")
 )
 
 const (
@@ -43,6 +44,12 @@ type RegistryStore interface {
 	GetAgentsIfChanged(scanner string) ([]config.AgentConfig, bool, error)
 }
 
+// LocalBotImageStore changes the local mode bot images at runtime.
+type LocalBotImageStore interface {
+	AddBotImage(image string) error
+	RemoveBotImage(image string) error
+}
+
 type registryStore struct {
 	ctx context.Context
 	bms BotManifestStore
@@ -202,14 +209,27 @@ func loadBot(ctx context.Context, cfg config.Config, bms BotManifestStore, agent
 	}
 
 	return &config.AgentConfig{
-		ID:       agentID,
-		Image:    image,
-		Manifest: ref,
-		ChainID:  cfg.ChainID,
-		Owner:    owner,
+		ID:        agentID,
+		Image:     image,
+		Manifest:  ref,
+		ChainID:   cfg.ChainID,
+		Owner:     owner,
+		Resources: config.CapManifestResources(cfg.ResourcesConfig, toBotResources(signedManifest.Manifest.Resources)),
 	}, signedManifest, nil
 }
 
+func toBotResources(resources *manifest.AgentResources) *config.BotResources {
+	if resources == nil {
+		return nil
+	}
+	return &config.BotResources{
+		MemoryMiB: resources.MemoryMiB,
+		CPUs:      resources.CPUs,
+		Pids:      resources.Pids,
+		DiskMiB:   resources.DiskMiB,
+	}
+}
+
 func (rs *registryStore) loadAssignment(assignment *registry.Assignment) (*config.AgentConfig, error) {
 	botCfg, agentData, err := loadBot(rs.ctx, rs.cfg, rs.bms, assignment.AgentID, assignment.AgentManifest, assignment.AgentOwner)
 	if err != nil {
@@ -231,10 +251,11 @@ func NewRegistryStore(ctx context.Context, cfg config.Config) (*registryStore, e
 
 	rc, err := GetRegistryClient(
 		ctx, cfg, registry.ClientConfig{
-			JsonRpcUrl:       cfg.Registry.JsonRpc.Url,
-			ENSAddress:       cfg.ENSConfig.ContractAddress,
-			Name:             "registry-store",
-			MulticallAddress: cfg.AdvancedConfig.MulticallAddress,
+			JsonRpcUrl:         cfg.Registry.JsonRpc.Url,
+			ENSAddress:         cfg.ENSConfig.ContractAddress,
+			Name:               "registry-store",
+			MulticallAddress:   cfg.AdvancedConfig.MulticallAddress,
+			MulticallChunkSize: cfg.AdvancedConfig.MulticallChunkSize,
 		},
 	)
 	if err != nil {
@@ -269,6 +290,7 @@ func NewRegistryStore(ctx context.Context, cfg config.Config) (*registryStore, e
 func populateShardConfig(assignment *registry.Assignment, agentManifest *manifest.SignedAgentManifest, chainID int) *config.ShardConfig {
 	var (
 		target, shards uint
+		strategy       string
 	)
 
 	// check if there is a default chain setting
@@ -277,6 +299,7 @@ func populateShardConfig(assignment *registry.Assignment, agentManifest *manifes
 	if ok {
 		target = chainSetting.Target
 		shards = chainSetting.Shards
+		strategy = chainSetting.ShardStrategy
 	}
 
 	// check if there is a chain setting for the scanner's chain
@@ -286,6 +309,7 @@ func populateShardConfig(assignment *registry.Assignment, agentManifest *manifes
 	if ok {
 		target = chainSetting.Target
 		shards = chainSetting.Shards
+		strategy = chainSetting.ShardStrategy
 	}
 
 	// if no sharding specified, shard count is 1 and target is total assigns
@@ -303,7 +327,9 @@ func populateShardConfig(assignment *registry.Assignment, agentManifest *manifes
 
 	shardID := calculateShardID(target, uint(assignment.ScannerIndex))
 
-	return createShardConfig(shardID, shards, target)
+	shardConfig := createShardConfig(shardID, shards, target)
+	shardConfig.Strategy = toShardStrategy(strategy)
+	return shardConfig
 }
 
 func createShardConfig(shardID, shards, target uint) *config.ShardConfig {
@@ -314,6 +340,17 @@ func createShardConfig(shardID, shards, target uint) *config.ShardConfig {
 	}
 }
 
+// toShardStrategy falls back to the default strategy if the strategy is unknown so that
+// all of the shards of a bot agree on where the txs go.
+func toShardStrategy(strategy string) config.ShardStrategy {
+	shardStrategy := config.ShardStrategy(strategy)
+	if !shardStrategy.IsValid() {
+		log.WithField("strategy", strategy).Warn("unknown shard strategy - using the default")
+		return ""
+	}
+	return shardStrategy
+}
+
 type privateRegistryStore struct {
 	ctx context.Context
 	cfg config.Config
@@ -355,6 +392,8 @@ func (rs *privateRegistryStore) GetAgentsIfChanged(scanner string) ([]config.Age
 		}
 
 		agtCfg.Owner = agt.Owner
+		agtCfg.Queue = rs.cfg.LocalModeConfig.BotQueue(agtCfg.ID, agtCfg.Image)
+		agtCfg.Resources = agtCfg.Resources.Override(rs.cfg.LocalModeConfig.BotResourcesOverride(agtCfg.ID, agtCfg.Image))
 		agentConfigs = append(agentConfigs, *agtCfg)
 	}
 
@@ -365,9 +404,10 @@ func (rs *privateRegistryStore) GetAgentsIfChanged(scanner string) ([]config.Age
 			instances := shardedBot.Shards * shardedBot.Target
 			for botIdx := uint(0); botIdx < instances; botIdx++ {
 				shardConfig := &config.ShardConfig{
-					Shards:  shardedBot.Shards,
-					Target:  shardedBot.Target,
-					ShardID: calculateShardID(shardedBot.Target, botIdx),
+					Shards:   shardedBot.Shards,
+					Target:   shardedBot.Target,
+					ShardID:  calculateShardID(shardedBot.Target, botIdx),
+					Strategy: shardedBot.Strategy,
 				}
 
 				agentID := strconv.Itoa(len(agentConfigs) + i + 1)
@@ -392,6 +432,40 @@ func (rs *privateRegistryStore) GetAgentsIfChanged(scanner string) ([]config.Age
 	return agentConfigs, true, nil
 }
 
+// AddBotImage adds a local mode bot image at runtime. The new bot gets the next ID so that the
+// containers of the other bots are kept.
+func (rs *privateRegistryStore) AddBotImage(image string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for _, botImage := range rs.cfg.LocalModeConfig.BotImages {
+		if botImage == image {
+			return nil
+		}
+	}
+	rs.cfg.LocalModeConfig.BotImages = append(rs.cfg.LocalModeConfig.BotImages, image)
+	return nil
+}
+
+// RemoveBotImage removes a local mode bot image at runtime. The removed image leaves an empty
+// slot behind so that the IDs of the other bots do not change.
+func (rs *privateRegistryStore) RemoveBotImage(image string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	var found bool
+	for i, botImage := range rs.cfg.LocalModeConfig.BotImages {
+		if botImage == image {
+			rs.cfg.LocalModeConfig.BotImages[i] = ""
+			found = true
+		}
+	}
+	if !found {
+		return ErrBotImageNotFound
+	}
+	return nil
+}
+
 func (rs *privateRegistryStore) FindAgentGlobally(agentID string) (*config.AgentConfig, error) {
 	return nil, ErrLocalMode
 }
@@ -406,6 +480,8 @@ func (rs *privateRegistryStore) makePrivateModeAgentConfig(
 		IsLocal:     true,
 		ShardConfig: shardConfig,
 		ChainID:     rs.cfg.ChainID,
+		Queue:       rs.cfg.LocalModeConfig.BotQueue(id, image),
+		Resources:   rs.cfg.LocalModeConfig.BotResourcesOverride(id, image),
 	}
 }
 
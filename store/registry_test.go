@@ -155,6 +155,46 @@ func TestPopulateShardConfig(t *testing.T) {
 				Shards:  1,
 			},
 		},
+		{
+			name: "should use the shard strategy from the chain setting",
+			args: args{
+				assignedScanners: 6,
+				scannerIndex:     4,
+				chainSettings: map[string]manifest.AgentChainSettings{
+					"1": {
+						Target:        2,
+						Shards:        3,
+						ShardStrategy: "toAddress",
+					},
+				},
+				chainID: 1,
+			},
+			expectedShardConfig: &config.ShardConfig{
+				ShardID:  2,
+				Target:   2,
+				Shards:   3,
+				Strategy: config.ShardStrategyToAddress,
+			},
+		}, {
+			name: "should use the default strategy if the shard strategy is unknown",
+			args: args{
+				assignedScanners: 6,
+				scannerIndex:     4,
+				chainSettings: map[string]manifest.AgentChainSettings{
+					"default": {
+						Target:        2,
+						Shards:        3,
+						ShardStrategy: "random",
+					},
+				},
+				chainID: 1,
+			},
+			expectedShardConfig: &config.ShardConfig{
+				ShardID: 2,
+				Target:  2,
+				Shards:  3,
+			},
+		},
 		// Add more test cases as needed
 	}
 
@@ -306,3 +346,27 @@ func TestGetAgentsIfChanged_NoUpdateNeeded(t *testing.T) {
 	r.False(update)
 	r.Nil(agents)
 }
+
+func TestPrivateRegistryStoreBotImages(t *testing.T) {
+	r := require.New(t)
+
+	rs := &privateRegistryStore{cfg: config.Config{
+		ChainID:         1,
+		LocalModeConfig: config.LocalModeConfig{Enable: true, BotImages: []string{"bot-image-1", "bot-image-2"}},
+	}}
+
+	r.NoError(rs.AddBotImage("bot-image-3"))
+	r.NoError(rs.AddBotImage("bot-image-3"))
+	r.NoError(rs.RemoveBotImage("bot-image-1"))
+	r.ErrorIs(rs.RemoveBotImage("bot-image-4"), ErrBotImageNotFound)
+
+	// the other bots should keep their IDs
+	agentConfigs, changed, err := rs.GetAgentsIfChanged("")
+	r.NoError(err)
+	r.True(changed)
+	r.Len(agentConfigs, 2)
+	r.Equal("2", agentConfigs[0].ID)
+	r.Equal("bot-image-2", agentConfigs[0].Image)
+	r.Equal("3", agentConfigs[1].ID)
+	r.Equal("bot-image-3", agentConfigs[1].Image)
+}
@@ -1,6 +1,7 @@
 package health
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -15,11 +16,27 @@ type HealthClient interface {
 	SendReports(src, dest, authToken string) error
 }
 
-type healthClient struct{}
+type healthClient struct {
+	redact func(string) string
+}
+
+// ClientOption configures the health client.
+type ClientOption func(hc *healthClient)
+
+// WithRedactor redacts the report details before they are sent.
+func WithRedactor(redact func(string) string) ClientOption {
+	return func(hc *healthClient) {
+		hc.redact = redact
+	}
+}
 
 // NewClient creates a new client.
-func NewClient() *healthClient {
-	return &healthClient{}
+func NewClient(opts ...ClientOption) *healthClient {
+	hc := &healthClient{}
+	for _, opt := range opts {
+		opt(hc)
+	}
+	return hc
 }
 
 func containerURL(port string) string {
@@ -75,7 +92,19 @@ func (hc *healthClient) SendReports(src, dest, authToken string) error {
 	}
 	defer resp.Body.Close()
 
-	req, err := http.NewRequest("POST", dest, resp.Body)
+	var reports Reports
+	if err := json.NewDecoder(resp.Body).Decode(&reports); err != nil {
+		return fmt.Errorf("failed to decode reports: %v", err)
+	}
+	if hc.redact != nil {
+		reports.RedactDetails(hc.redact)
+	}
+	b, err := json.Marshal(reports)
+	if err != nil {
+		return fmt.Errorf("failed to encode reports: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", dest, bytes.NewReader(b))
 	if err != nil {
 		return fmt.Errorf("failed to create post request: %v", err)
 	}
@@ -67,6 +67,13 @@ func (reports Reports) ObfuscateDetails() {
 	}
 }
 
+// RedactDetails redacts details in each report.
+func (reports Reports) RedactDetails(redact func(string) string) {
+	for _, report := range reports {
+		report.Details = redact(report.Details)
+	}
+}
+
 // SummaryReport implements some methods to help construct summary `Reports` easily.
 type SummaryReport struct {
 	report   Report
@@ -23,6 +23,9 @@ type AlertBatch struct {
 
 	// metrics
 	Metrics BotMetricsList `json:"metrics,omitempty"`
+
+	// retractions
+	Retractions BlockRetractionList `json:"retractions,omitempty"`
 }
 
 // Validate validates this alert batch
@@ -37,6 +40,10 @@ func (m *AlertBatch) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateRetractions(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -77,6 +84,23 @@ func (m *AlertBatch) validateMetrics(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *AlertBatch) validateRetractions(formats strfmt.Registry) error {
+	if swag.IsZero(m.Retractions) { // not required
+		return nil
+	}
+
+	if err := m.Retractions.Validate(formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("retractions")
+		} else if ce, ok := err.(*errors.CompositeError); ok {
+			return ce.ValidateName("retractions")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this alert batch based on the context it is used
 func (m *AlertBatch) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
@@ -89,6 +113,10 @@ func (m *AlertBatch) ContextValidate(ctx context.Context, formats strfmt.Registr
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateRetractions(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -123,6 +151,20 @@ func (m *AlertBatch) contextValidateMetrics(ctx context.Context, formats strfmt.
 	return nil
 }
 
+func (m *AlertBatch) contextValidateRetractions(ctx context.Context, formats strfmt.Registry) error {
+
+	if err := m.Retractions.ContextValidate(ctx, formats); err != nil {
+		if ve, ok := err.(*errors.Validation); ok {
+			return ve.ValidateName("retractions")
+		} else if ce, ok := err.(*errors.CompositeError); ok {
+			return ce.ValidateName("retractions")
+		}
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *AlertBatch) MarshalBinary() ([]byte, error) {
 	if m == nil {
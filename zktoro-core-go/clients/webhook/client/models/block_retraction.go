@@ -0,0 +1,63 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// BlockRetraction Invalidates the alerts of a block which was orphaned by a reorg
+//
+// swagger:model BlockRetraction
+type BlockRetraction struct {
+
+	// Hash of the orphaned block
+	// Example: 0xf9e777b739cf90a197c74c461933422dcf26fadf50e0ef9aa72af76727da87ca
+	BlockHash string `json:"blockHash,omitempty"`
+
+	// block number
+	// Example: 1235678901234
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+
+	// Hash of the block which replaced the orphaned block
+	// Example: 0x6c6f9e3b2d0e3c8d1f6e1a7b0e5f2a4c9d8b7a6f5e4d3c2b1a0f9e8d7c6b5a49
+	CanonicalBlockHash string `json:"canonicalBlockHash,omitempty"`
+
+	// chain Id
+	// Example: 1337
+	ChainID uint64 `json:"chainId,omitempty"`
+}
+
+// Validate validates this block retraction
+func (m *BlockRetraction) Validate(formats strfmt.Registry) error {
+	return nil
+}
+
+// ContextValidate validates this block retraction based on context it is used
+func (m *BlockRetraction) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *BlockRetraction) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *BlockRetraction) UnmarshalBinary(b []byte) error {
+	var res BlockRetraction
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
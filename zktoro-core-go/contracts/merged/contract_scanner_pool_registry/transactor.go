@@ -0,0 +1,630 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package contract_scanner_pool_registry
+
+import (
+	import_fmt "fmt"
+	import_sync "sync"
+
+	scannerpoolregistry010 "zktoro/zktoro-core-go/contracts/generated/contract_scanner_pool_registry_0_1_0"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ScannerPoolRegistryTransactor is a new type which can multiplex calls to different implementation types.
+type ScannerPoolRegistryTransactor struct {
+	typ0 *scannerpoolregistry010.ScannerPoolRegistryTransactor
+
+	currTag string
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+}
+
+// NewScannerPoolRegistryTransactor creates a new merged type.
+func NewScannerPoolRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*ScannerPoolRegistryTransactor, error) {
+	var (
+		mergedType ScannerPoolRegistryTransactor
+		err        error
+	)
+	mergedType.currTag = "0.1.0"
+
+	mergedType.typ0, err = scannerpoolregistry010.NewScannerPoolRegistryTransactor(address, transactor)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize scannerpoolregistry010.ScannerPoolRegistryTransactor: %v", err)
+	}
+
+	return &mergedType, nil
+}
+
+// IsKnownTagForScannerPoolRegistryTransactor tells if given tag is a known tag.
+func IsKnownTagForScannerPoolRegistryTransactor(tag string) bool {
+
+	if tag == "0.1.0" {
+		return true
+	}
+
+	return false
+}
+
+// Use sets the used implementation to given tag.
+func (merged *ScannerPoolRegistryTransactor) Use(tag string) (changed bool) {
+	if !merged.unsafe {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+	}
+	// use the default tag if the provided tag is unknown
+	if !IsKnownTagForScannerPoolRegistryTransactor(tag) {
+		tag = "0.1.0"
+	}
+	changed = merged.currTag != tag
+	merged.currTag = tag
+	return
+}
+
+// Unsafe disables the mutex.
+func (merged *ScannerPoolRegistryTransactor) Unsafe() {
+	merged.unsafe = true
+}
+
+// Safe enables the mutex.
+func (merged *ScannerPoolRegistryTransactor) Safe() {
+	merged.unsafe = false
+}
+
+// Approve multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) Approve(opts *bind.TransactOpts, to common.Address, tokenId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.Approve(opts, to, tokenId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.Approve not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// DisableRouter multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) DisableRouter(opts *bind.TransactOpts) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.DisableRouter(opts)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.DisableRouter not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// DisableScanner multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) DisableScanner(opts *bind.TransactOpts, scanner common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.DisableScanner(opts, scanner)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.DisableScanner not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// EnableScanner multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) EnableScanner(opts *bind.TransactOpts, scanner common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.EnableScanner(opts, scanner)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.EnableScanner not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Initialize multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) Initialize(opts *bind.TransactOpts, __manager common.Address, __name string, __symbol string, __stakeSubjectGateway common.Address, __registrationDelay *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.Initialize(opts, __manager, __name, __symbol, __stakeSubjectGateway, __registrationDelay)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.Initialize not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Multicall multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) Multicall(opts *bind.TransactOpts, data [][]byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.Multicall(opts, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.Multicall not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RegisterMigratedScannerNode multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) RegisterMigratedScannerNode(opts *bind.TransactOpts, req scannerpoolregistry010.ScannerPoolRegistryCoreScannerNodeRegistration, disabled bool) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.RegisterMigratedScannerNode(opts, req, disabled)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.RegisterMigratedScannerNode not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RegisterMigratedScannerPool multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) RegisterMigratedScannerPool(opts *bind.TransactOpts, scannerPoolAddress common.Address, chainId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.RegisterMigratedScannerPool(opts, scannerPoolAddress, chainId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.RegisterMigratedScannerPool not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RegisterScannerNode multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) RegisterScannerNode(opts *bind.TransactOpts, req scannerpoolregistry010.ScannerPoolRegistryCoreScannerNodeRegistration, signature []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.RegisterScannerNode(opts, req, signature)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.RegisterScannerNode not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RegisterScannerPool multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) RegisterScannerPool(opts *bind.TransactOpts, chainId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.RegisterScannerPool(opts, chainId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.RegisterScannerPool not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SafeTransferFrom multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SafeTransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, tokenId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SafeTransferFrom(opts, from, to, tokenId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SafeTransferFrom not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SafeTransferFrom0 multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SafeTransferFrom0(opts *bind.TransactOpts, from common.Address, to common.Address, tokenId *big.Int, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SafeTransferFrom0(opts, from, to, tokenId, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SafeTransferFrom0 not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetAccessManager multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetAccessManager(opts *bind.TransactOpts, newManager common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetAccessManager(opts, newManager)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetAccessManager not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetApprovalForAll multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetApprovalForAll(opts *bind.TransactOpts, operator common.Address, approved bool) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetApprovalForAll(opts, operator, approved)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetApprovalForAll not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetManagedStakeThreshold multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetManagedStakeThreshold(opts *bind.TransactOpts, newStakeThreshold scannerpoolregistry010.IStakeSubjectStakeThreshold, chainId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetManagedStakeThreshold(opts, newStakeThreshold, chainId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetManagedStakeThreshold not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetManager multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetManager(opts *bind.TransactOpts, scannerPoolId *big.Int, manager common.Address, enable bool) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetManager(opts, scannerPoolId, manager, enable)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetManager not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetName multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetName(opts *bind.TransactOpts, ensRegistry common.Address, ensName string) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetName(opts, ensRegistry, ensName)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetName not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetRegistrationDelay multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetRegistrationDelay(opts *bind.TransactOpts, delay *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetRegistrationDelay(opts, delay)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetRegistrationDelay not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetSubjectHandler multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) SetSubjectHandler(opts *bind.TransactOpts, subjectGateway common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.SetSubjectHandler(opts, subjectGateway)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.SetSubjectHandler not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// TransferFrom multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, tokenId *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.TransferFrom(opts, from, to, tokenId)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.TransferFrom not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpdateScannerMetadata multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) UpdateScannerMetadata(opts *bind.TransactOpts, scanner common.Address, metadata string) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.UpdateScannerMetadata(opts, scanner, metadata)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.UpdateScannerMetadata not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeTo multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) UpgradeTo(opts *bind.TransactOpts, newImplementation common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.UpgradeTo(opts, newImplementation)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.UpgradeTo not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeToAndCall multiplexes to different implementations of the method.
+func (merged *ScannerPoolRegistryTransactor) UpgradeToAndCall(opts *bind.TransactOpts, newImplementation common.Address, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.0" {
+		val, methodErr := merged.typ0.UpgradeToAndCall(opts, newImplementation, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ScannerPoolRegistryTransactor.UpgradeToAndCall not implemented (tag=%s)", merged.currTag)
+	return
+}
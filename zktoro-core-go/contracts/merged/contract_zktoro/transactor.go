@@ -0,0 +1,462 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package contract_zktoro
+
+import (
+	import_fmt "fmt"
+	import_sync "sync"
+
+	zktoro020 "zktoro/zktoro-core-go/contracts/generated/contract_zktoro_0_2_0"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ZktoroTransactor is a new type which can multiplex calls to different implementation types.
+type ZktoroTransactor struct {
+	typ0 *zktoro020.ZktoroTransactor
+
+	currTag string
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+}
+
+// NewZktoroTransactor creates a new merged type.
+func NewZktoroTransactor(address common.Address, transactor bind.ContractTransactor) (*ZktoroTransactor, error) {
+	var (
+		mergedType ZktoroTransactor
+		err        error
+	)
+	mergedType.currTag = "0.2.0"
+
+	mergedType.typ0, err = zktoro020.NewZktoroTransactor(address, transactor)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize zktoro020.ZktoroTransactor: %v", err)
+	}
+
+	return &mergedType, nil
+}
+
+// IsKnownTagForZktoroTransactor tells if given tag is a known tag.
+func IsKnownTagForZktoroTransactor(tag string) bool {
+
+	if tag == "0.2.0" {
+		return true
+	}
+
+	return false
+}
+
+// Use sets the used implementation to given tag.
+func (merged *ZktoroTransactor) Use(tag string) (changed bool) {
+	if !merged.unsafe {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+	}
+	// use the default tag if the provided tag is unknown
+	if !IsKnownTagForZktoroTransactor(tag) {
+		tag = "0.2.0"
+	}
+	changed = merged.currTag != tag
+	merged.currTag = tag
+	return
+}
+
+// Unsafe disables the mutex.
+func (merged *ZktoroTransactor) Unsafe() {
+	merged.unsafe = true
+}
+
+// Safe enables the mutex.
+func (merged *ZktoroTransactor) Safe() {
+	merged.unsafe = false
+}
+
+// Approve multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Approve(opts *bind.TransactOpts, spender common.Address, amount *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Approve(opts, spender, amount)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Approve not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// DecreaseAllowance multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) DecreaseAllowance(opts *bind.TransactOpts, spender common.Address, subtractedValue *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.DecreaseAllowance(opts, spender, subtractedValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.DecreaseAllowance not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Delegate multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Delegate(opts *bind.TransactOpts, delegatee common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Delegate(opts, delegatee)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Delegate not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// DelegateBySig multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) DelegateBySig(opts *bind.TransactOpts, delegatee common.Address, nonce *big.Int, expiry *big.Int, v uint8, r [32]byte, s [32]byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.DelegateBySig(opts, delegatee, nonce, expiry, v, r, s)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.DelegateBySig not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// GrantRole multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) GrantRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.GrantRole(opts, role, account)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.GrantRole not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// IncreaseAllowance multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) IncreaseAllowance(opts *bind.TransactOpts, spender common.Address, addedValue *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.IncreaseAllowance(opts, spender, addedValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.IncreaseAllowance not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Initialize multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Initialize(opts *bind.TransactOpts, admin common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Initialize(opts, admin)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Initialize not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Mint multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Mint(opts *bind.TransactOpts, to common.Address, amount *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Mint(opts, to, amount)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Mint not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Permit multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Permit(opts *bind.TransactOpts, owner common.Address, spender common.Address, value *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Permit(opts, owner, spender, value, deadline, v, r, s)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Permit not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RenounceRole multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) RenounceRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.RenounceRole(opts, role, account)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.RenounceRole not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RevokeRole multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) RevokeRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.RevokeRole(opts, role, account)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.RevokeRole not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetName multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) SetName(opts *bind.TransactOpts, ensRegistry common.Address, ensName string) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.SetName(opts, ensRegistry, ensName)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.SetName not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Transfer multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.Transfer(opts, to, amount)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.Transfer not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// TransferFrom multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) TransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, amount *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.TransferFrom(opts, from, to, amount)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.TransferFrom not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeTo multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) UpgradeTo(opts *bind.TransactOpts, newImplementation common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.UpgradeTo(opts, newImplementation)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.UpgradeTo not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeToAndCall multiplexes to different implementations of the method.
+func (merged *ZktoroTransactor) UpgradeToAndCall(opts *bind.TransactOpts, newImplementation common.Address, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.2.0" {
+		val, methodErr := merged.typ0.UpgradeToAndCall(opts, newImplementation, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroTransactor.UpgradeToAndCall not implemented (tag=%s)", merged.currTag)
+	return
+}
@@ -0,0 +1,1010 @@
+// Code generated by go-merge-types. DO NOT EDIT.
+
+package contract_zktoro_staking
+
+import (
+	import_fmt "fmt"
+	import_sync "sync"
+
+	zktorostaking011 "zktoro/zktoro-core-go/contracts/generated/contract_zktoro_staking_0_1_1"
+
+	zktorostaking012 "zktoro/zktoro-core-go/contracts/generated/contract_zktoro_staking_0_1_2"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ZktoroStakingTransactor is a new type which can multiplex calls to different implementation types.
+type ZktoroStakingTransactor struct {
+	typ0 *zktorostaking011.ZktoroStakingTransactor
+
+	typ1 *zktorostaking012.ZktoroStakingTransactor
+
+	currTag string
+	mu      import_sync.RWMutex
+	unsafe  bool // default: false
+}
+
+// NewZktoroStakingTransactor creates a new merged type.
+func NewZktoroStakingTransactor(address common.Address, transactor bind.ContractTransactor) (*ZktoroStakingTransactor, error) {
+	var (
+		mergedType ZktoroStakingTransactor
+		err        error
+	)
+	mergedType.currTag = "0.1.2"
+
+	mergedType.typ0, err = zktorostaking011.NewZktoroStakingTransactor(address, transactor)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize zktorostaking011.ZktoroStakingTransactor: %v", err)
+	}
+
+	mergedType.typ1, err = zktorostaking012.NewZktoroStakingTransactor(address, transactor)
+	if err != nil {
+		return nil, import_fmt.Errorf("failed to initialize zktorostaking012.ZktoroStakingTransactor: %v", err)
+	}
+
+	return &mergedType, nil
+}
+
+// IsKnownTagForZktoroStakingTransactor tells if given tag is a known tag.
+func IsKnownTagForZktoroStakingTransactor(tag string) bool {
+
+	if tag == "0.1.1" {
+		return true
+	}
+
+	if tag == "0.1.2" {
+		return true
+	}
+
+	return false
+}
+
+// Use sets the used implementation to given tag.
+func (merged *ZktoroStakingTransactor) Use(tag string) (changed bool) {
+	if !merged.unsafe {
+		merged.mu.Lock()
+		defer merged.mu.Unlock()
+	}
+	// use the default tag if the provided tag is unknown
+	if !IsKnownTagForZktoroStakingTransactor(tag) {
+		tag = "0.1.2"
+	}
+	changed = merged.currTag != tag
+	merged.currTag = tag
+	return
+}
+
+// Unsafe disables the mutex.
+func (merged *ZktoroStakingTransactor) Unsafe() {
+	merged.unsafe = true
+}
+
+// Safe enables the mutex.
+func (merged *ZktoroStakingTransactor) Safe() {
+	merged.unsafe = false
+}
+
+// Deposit multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Deposit(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, stakeValue *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Deposit(opts, subjectType, subject, stakeValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Deposit(opts, subjectType, subject, stakeValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Deposit not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Freeze multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Freeze(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, frozen bool) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Freeze(opts, subjectType, subject, frozen)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Freeze(opts, subjectType, subject, frozen)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Freeze not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Initialize multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Initialize(opts *bind.TransactOpts, __manager common.Address, __router common.Address, __stakedToken common.Address, __withdrawalDelay uint64, __treasury common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Initialize(opts, __manager, __router, __stakedToken, __withdrawalDelay, __treasury)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Initialize(opts, __manager, __stakedToken, __withdrawalDelay, __treasury)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Initialize not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// InitiateWithdrawal multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) InitiateWithdrawal(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, sharesValue *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.InitiateWithdrawal(opts, subjectType, subject, sharesValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.InitiateWithdrawal(opts, subjectType, subject, sharesValue)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.InitiateWithdrawal not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Multicall multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Multicall(opts *bind.TransactOpts, data [][]byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Multicall(opts, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Multicall(opts, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Multicall not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// RelayPermit multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) RelayPermit(opts *bind.TransactOpts, value *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.RelayPermit(opts, value, deadline, v, r, s)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.RelayPermit(opts, value, deadline, v, r, s)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.RelayPermit not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// ReleaseReward multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) ReleaseReward(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, account common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.ReleaseReward(opts, subjectType, subject, account)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.ReleaseReward not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Reward multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Reward(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, value *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Reward(opts, subjectType, subject, value)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Reward not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SafeBatchTransferFrom multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SafeBatchTransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, ids []*big.Int, amounts []*big.Int, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SafeBatchTransferFrom(opts, from, to, ids, amounts, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SafeBatchTransferFrom(opts, from, to, ids, amounts, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SafeBatchTransferFrom not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SafeTransferFrom multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SafeTransferFrom(opts *bind.TransactOpts, from common.Address, to common.Address, id *big.Int, amount *big.Int, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SafeTransferFrom(opts, from, to, id, amount, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SafeTransferFrom(opts, from, to, id, amount, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SafeTransferFrom not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetAccessManager multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetAccessManager(opts *bind.TransactOpts, newManager common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetAccessManager(opts, newManager)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetAccessManager(opts, newManager)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetAccessManager not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetApprovalForAll multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetApprovalForAll(opts *bind.TransactOpts, operator common.Address, approved bool) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetApprovalForAll(opts, operator, approved)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetApprovalForAll(opts, operator, approved)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetApprovalForAll not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetDelay multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetDelay(opts *bind.TransactOpts, newDelay uint64) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetDelay(opts, newDelay)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetDelay(opts, newDelay)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetDelay not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetName multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetName(opts *bind.TransactOpts, ensRegistry common.Address, ensName string) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetName(opts, ensRegistry, ensName)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetName(opts, ensRegistry, ensName)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetName not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetRouter multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetRouter(opts *bind.TransactOpts, newRouter common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetRouter(opts, newRouter)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetRouter not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetStakingParametersManager multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetStakingParametersManager(opts *bind.TransactOpts, newStakingParameters common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetStakingParametersManager(opts, newStakingParameters)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetStakingParametersManager not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetTreasury multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetTreasury(opts *bind.TransactOpts, newTreasury common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetTreasury(opts, newTreasury)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetTreasury(opts, newTreasury)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetTreasury not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetURI multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetURI(opts *bind.TransactOpts, newUri string) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.SetURI(opts, newUri)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetURI(opts, newUri)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetURI not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Slash multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Slash(opts *bind.TransactOpts, subjectType uint8, subject *big.Int, stakeValue *big.Int, proposer common.Address, proposerPercent *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Slash(opts, subjectType, subject, stakeValue, proposer, proposerPercent)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Slash(opts, subjectType, subject, stakeValue, proposer, proposerPercent)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Slash not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Sweep multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Sweep(opts *bind.TransactOpts, token common.Address, recipient common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Sweep(opts, token, recipient)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Sweep(opts, token, recipient)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Sweep not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeTo multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) UpgradeTo(opts *bind.TransactOpts, newImplementation common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.UpgradeTo(opts, newImplementation)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.UpgradeTo(opts, newImplementation)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.UpgradeTo not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// UpgradeToAndCall multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) UpgradeToAndCall(opts *bind.TransactOpts, newImplementation common.Address, data []byte) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.UpgradeToAndCall(opts, newImplementation, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.UpgradeToAndCall(opts, newImplementation, data)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.UpgradeToAndCall not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Withdraw multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Withdraw(opts *bind.TransactOpts, subjectType uint8, subject *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.1" {
+		val, methodErr := merged.typ0.Withdraw(opts, subjectType, subject)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Withdraw(opts, subjectType, subject)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Withdraw not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// ConfigureStakeHelpers multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) ConfigureStakeHelpers(opts *bind.TransactOpts, _subjectGateway common.Address, _allocator common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.ConfigureStakeHelpers(opts, _subjectGateway, _allocator)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.ConfigureStakeHelpers not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// DisableRouter multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) DisableRouter(opts *bind.TransactOpts) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.DisableRouter(opts)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.DisableRouter not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// Migrate multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) Migrate(opts *bind.TransactOpts, oldSubjectType uint8, oldSubject *big.Int, newSubjectType uint8, newSubject *big.Int, staker common.Address) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.Migrate(opts, oldSubjectType, oldSubject, newSubjectType, newSubject, staker)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.Migrate not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetReentrancyGuard multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetReentrancyGuard(opts *bind.TransactOpts) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetReentrancyGuard(opts)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetReentrancyGuard not implemented (tag=%s)", merged.currTag)
+	return
+}
+
+// SetSlashDelegatorsPercent multiplexes to different implementations of the method.
+func (merged *ZktoroStakingTransactor) SetSlashDelegatorsPercent(opts *bind.TransactOpts, percent *big.Int) (retVal *types.Transaction, err error) {
+	if !merged.unsafe {
+		merged.mu.RLock()
+		defer merged.mu.RUnlock()
+	}
+
+	if merged.currTag == "0.1.2" {
+		val, methodErr := merged.typ1.SetSlashDelegatorsPercent(opts, percent)
+
+		if methodErr != nil {
+			err = methodErr
+			return
+		}
+
+		retVal = val
+
+		return
+	}
+
+	err = import_fmt.Errorf("ZktoroStakingTransactor.SetSlashDelegatorsPercent not implemented (tag=%s)", merged.currTag)
+	return
+}
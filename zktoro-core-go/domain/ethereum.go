@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"math/big"
 	"time"
 
@@ -55,6 +57,16 @@ func (b *Block) GetTimestamp() (*time.Time, error) {
 	return &result, nil
 }
 
+// ResponseHash computes a hash by using the block hash and the tx hashes so that the responses
+// of different APIs can be compared.
+func (b *Block) ResponseHash() string {
+	hashConcat := b.Hash
+	for _, tx := range b.Transactions {
+		hashConcat += tx.Hash
+	}
+	return hashOf(hashConcat)
+}
+
 // Transaction is the intersection between parity and go-ethereum transactions
 type Transaction struct {
 	BlockHash            string  `json:"blockHash"`
@@ -203,6 +215,23 @@ type Trace struct {
 	Error               *string      `json:"error"`
 }
 
+// TracesResponseHash computes a hash by using the tx hashes of the traces so that the responses
+// of different APIs can be compared.
+func TracesResponseHash(traces []Trace) string {
+	var hashConcat string
+	for _, trace := range traces {
+		if trace.TransactionHash != nil {
+			hashConcat += *trace.TransactionHash
+		}
+	}
+	return hashOf(hashConcat)
+}
+
+func hashOf(str string) string {
+	hash := sha256.Sum256([]byte(str))
+	return hex.EncodeToString(hash[:])
+}
+
 func (t Trace) ToProto() *protocol.TransactionEvent_Trace {
 	traceAddress := make([]int64, len(t.TraceAddress))
 	for i, address := range t.TraceAddress {
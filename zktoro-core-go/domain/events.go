@@ -18,6 +18,7 @@ type EventType string
 
 const (
 	EventTypeBlock EventType = "block"
+	EventTypeReorg EventType = "reorg"
 )
 
 const TimeTrackingTimestampFormat = time.RFC3339Nano
@@ -70,6 +71,39 @@ type BlockEvent struct {
 	Logs       []LogEntry
 	Traces     []Trace
 	Timestamps *TrackingTimestamps
+	Reorg      *Reorg
+}
+
+// Reorg contains the blocks which were replaced by a reorg. The block of the reorg event
+// is the canonical block at the height where the reorg was detected.
+type Reorg struct {
+	CommonAncestor uint64
+	Orphaned       []*OrphanedBlock
+}
+
+// OrphanedBlock is a previously processed block which is no longer in the canonical chain.
+type OrphanedBlock struct {
+	Number        uint64
+	Hash          string
+	CanonicalHash string
+}
+
+// ToRetractions converts the orphaned blocks to retractions.
+func (r *Reorg) ToRetractions() []*protocol.BlockRetraction {
+	var retractions []*protocol.BlockRetraction
+	for _, orphaned := range r.Orphaned {
+		retractions = append(retractions, &protocol.BlockRetraction{
+			BlockNumber:        orphaned.Number,
+			BlockHash:          orphaned.Hash,
+			CanonicalBlockHash: orphaned.CanonicalHash,
+		})
+	}
+	return retractions
+}
+
+// IsReorg tells if this is a reorg event.
+func (t *BlockEvent) IsReorg() bool {
+	return t.EventType == EventTypeReorg
 }
 
 func str(val *string) string {
@@ -93,6 +127,9 @@ func strPtr(val string) *string {
 
 func (t *BlockEvent) ToMessage() (*protocol.BlockEvent, error) {
 	evtType := protocol.BlockEvent_BLOCK
+	if t.IsReorg() {
+		evtType = protocol.BlockEvent_REORG
+	}
 
 	txs := make([]string, 0, len(t.Block.Transactions))
 	for _, tx := range t.Block.Transactions {
@@ -370,7 +370,7 @@ func (e *streamEthClient) Name() string {
 
 // Health implements the health.Reporter interface.
 func (e *streamEthClient) Health() health.Reports {
-	return health.Reports{
+	reports := health.Reports{
 		e.lastBlockByNumberReq.GetReport("request.block-by-number.time"),
 		e.lastBlockByNumberErr.GetReport("request.block-by-number.error"),
 		e.lastGetTransactionReceiptReq.GetReport("request.get-transaction-receipt.time"),
@@ -378,6 +378,10 @@ func (e *streamEthClient) Health() health.Reports {
 		e.lastTraceBlockReq.GetReport("request.trace-block.time"),
 		e.lastTraceBlockErr.GetReport("request.trace-block.error"),
 	}
+	if reporter, ok := e.rpcClient.(*failoverClient); ok {
+		reports = append(reports, reporter.Health()...)
+	}
+	return reports
 }
 
 type rpcClient struct {
@@ -432,6 +436,21 @@ func NewStreamEthClient(ctx context.Context, apiName, apiURL string) (*streamEth
 	}, nil
 }
 
+// NewFailoverStreamEthClient creates a new ethereum client which spreads the requests over multiple endpoints.
+func NewFailoverStreamEthClient(ctx context.Context, apiName string, endpoints []Endpoint, opts FailoverOptions) (*streamEthClient, error) {
+	fClient, err := NewFailoverRPCClient(ctx, endpoints, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamEthClient{
+		apiName:       apiName,
+		rpcClient:     fClient,
+		retryInterval: defaultRetryInterval,
+		isWebsocket:   fClient.isWebsocket(),
+	}, nil
+}
+
 func isWebsocket(apiURL string) bool {
 	u, err := url.Parse(apiURL)
 	if err != nil {
@@ -0,0 +1,458 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"zktoro/zktoro-core-go/clients/health"
+	"zktoro/zktoro-core-go/domain"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// endpointStatsDecay is the weight of the latest request in the latency and error rate averages.
+	endpointStatsDecay = 0.2
+	// unhealthyErrorRate is the error rate which makes an endpoint the last resort.
+	unhealthyErrorRate = 0.5
+	// unhealthyRetryInterval is how often an unhealthy endpoint is tried again before the others.
+	unhealthyRetryInterval = 30 * time.Second
+	// failedRequestPenalty is added to the latency of the failed requests.
+	failedRequestPenalty = 5 * time.Second
+)
+
+// ErrQuorumNotReached is returned when not enough endpoints return the same response.
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+// Endpoint is a JSON-RPC API which the requests can be sent to.
+type Endpoint struct {
+	URL     string
+	Weight  int
+	Headers map[string]string
+}
+
+// FailoverOptions configure how the requests are spread over the endpoints.
+type FailoverOptions struct {
+	// HedgeDelay is how long to wait for an endpoint before sending the same request
+	// to the next one. Zero disables hedging.
+	HedgeDelay time.Duration
+	// Quorum is the number of endpoints which should return the same block or traces.
+	// Values below 2 disable the quorum reads.
+	Quorum int
+}
+
+// endpointClient tracks the latency and the error rate of an endpoint.
+type endpointClient struct {
+	name        string
+	weight      float64
+	client      RPCClient
+	isWebsocket bool
+
+	latency     time.Duration
+	errorRate   float64
+	lastAttempt time.Time
+	lastErr     health.ErrorTracker
+	mu          sync.RWMutex
+}
+
+func (ep *endpointClient) call(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	var raw json.RawMessage
+	start := time.Now()
+	err := ep.client.CallContext(ctx, &raw, method, args...)
+	// do not blame the endpoint for the requests which were cancelled because of another endpoint
+	if err == nil || ctx.Err() == nil {
+		ep.observe(time.Since(start), err)
+	}
+	return raw, err
+}
+
+func (ep *endpointClient) observe(latency time.Duration, err error) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.lastAttempt = time.Now()
+	var failure float64
+	if err != nil {
+		failure = 1
+	}
+	ep.errorRate = ep.errorRate*(1-endpointStatsDecay) + failure*endpointStatsDecay
+	ep.lastErr.Set(err)
+	if err != nil {
+		latency += failedRequestPenalty
+	}
+	if ep.latency == 0 {
+		ep.latency = latency
+		return
+	}
+	ep.latency = time.Duration(float64(ep.latency)*(1-endpointStatsDecay) + float64(latency)*endpointStatsDecay)
+}
+
+// rank returns whether the endpoint should be preferred and how fast it is. The lower scores are better.
+func (ep *endpointClient) rank() (healthy bool, score float64) {
+	ep.mu.RLock()
+	defer ep.mu.RUnlock()
+	healthy = ep.errorRate < unhealthyErrorRate || time.Since(ep.lastAttempt) > unhealthyRetryInterval
+	return healthy, float64(ep.latency) * (1 + ep.errorRate) / ep.weight
+}
+
+func (ep *endpointClient) isHealthy() bool {
+	ep.mu.RLock()
+	defer ep.mu.RUnlock()
+	return ep.errorRate < unhealthyErrorRate
+}
+
+func (ep *endpointClient) getReport(name string) *health.Report {
+	ep.mu.RLock()
+	defer ep.mu.RUnlock()
+	report := ep.lastErr.GetReport(name)
+	if ep.errorRate < unhealthyErrorRate {
+		report.Status = health.StatusOK
+	}
+	report.Details = fmt.Sprintf("endpoint=%s latency=%s errorRate=%.2f %s", ep.name, ep.latency, ep.errorRate, report.Details)
+	return report
+}
+
+// failoverClient is an RPC client which sends the requests to the fastest healthy endpoint and
+// fails over to the next ones. The requests can be hedged and the blocks and the traces can be
+// read from multiple endpoints to make sure that they agree.
+type failoverClient struct {
+	endpoints []*endpointClient
+	opts      FailoverOptions
+
+	lastDisagreement health.ErrorTracker
+}
+
+func newFailoverClient(endpoints []*endpointClient, opts FailoverOptions) (*failoverClient, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no json-rpc endpoints")
+	}
+	if opts.Quorum > len(endpoints) {
+		return nil, fmt.Errorf("quorum %d is larger than the endpoint count %d", opts.Quorum, len(endpoints))
+	}
+	for _, ep := range endpoints {
+		if ep.weight <= 0 {
+			ep.weight = 1
+		}
+	}
+	return &failoverClient{endpoints: endpoints, opts: opts}, nil
+}
+
+// NewFailoverRPCClient dials all of the endpoints and returns an RPC client which spreads the requests over them.
+func NewFailoverRPCClient(ctx context.Context, endpoints []Endpoint, opts FailoverOptions) (*failoverClient, error) {
+	var epClients []*endpointClient
+	for _, endpoint := range endpoints {
+		rClient, err := NewRpcClient(ctx, endpoint.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial %s: %v", endpointName(endpoint.URL), err)
+		}
+		rClient.SetHeader("Content-Type", "application/json")
+		for k, v := range endpoint.Headers {
+			rClient.SetHeader(k, v)
+		}
+		epClients = append(epClients, &endpointClient{
+			name:        endpointName(endpoint.URL),
+			weight:      float64(endpoint.Weight),
+			client:      &rpcClient{Client: rClient},
+			isWebsocket: isWebsocket(endpoint.URL),
+		})
+	}
+	return newFailoverClient(epClients, opts)
+}
+
+// endpointName returns the host of the URL so that the keys in the paths do not end up in the logs.
+func endpointName(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return "invalid-url"
+	}
+	return u.Host
+}
+
+// ranked returns the endpoints from the best to the worst. The healthy ones come first and
+// are sorted by their latency divided by their weight. The config order breaks the ties.
+func (fc *failoverClient) ranked() []*endpointClient {
+	type rankedEndpoint struct {
+		ep      *endpointClient
+		healthy bool
+		score   float64
+	}
+	ranked := make([]rankedEndpoint, len(fc.endpoints))
+	for i, ep := range fc.endpoints {
+		healthy, score := ep.rank()
+		ranked[i] = rankedEndpoint{ep: ep, healthy: healthy, score: score}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].healthy != ranked[j].healthy {
+			return ranked[i].healthy
+		}
+		return ranked[i].score < ranked[j].score
+	})
+	endpoints := make([]*endpointClient, len(ranked))
+	for i, r := range ranked {
+		endpoints[i] = r.ep
+	}
+	return endpoints
+}
+
+func (fc *failoverClient) isWebsocket() bool {
+	for _, ep := range fc.endpoints {
+		if ep.isWebsocket {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes all of the endpoint clients.
+func (fc *failoverClient) Close() {
+	for _, ep := range fc.endpoints {
+		ep.client.Close()
+	}
+}
+
+// CallContext implements RPCClient.
+func (fc *failoverClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var (
+		raw json.RawMessage
+		err error
+	)
+	if fc.opts.Quorum > 1 && (method == blocksByNumber || method == traceBlock) {
+		raw, err = fc.quorumCall(ctx, method, args...)
+	} else {
+		raw, err = fc.hedgedCall(ctx, method, args...)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+type endpointResponse struct {
+	ep  *endpointClient
+	raw json.RawMessage
+	err error
+}
+
+func isEmptyResponse(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+// hedgedCall sends the request to the best endpoint and moves on to the next one when it fails,
+// responds with nothing or does not respond before the hedge delay.
+func (fc *failoverClient) hedgedCall(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	endpoints := fc.ranked()
+	respCh := make(chan *endpointResponse, len(endpoints))
+	var next, pending int
+	send := func() {
+		ep := endpoints[next]
+		next++
+		pending++
+		go func() {
+			raw, err := ep.call(ctx, method, args...)
+			respCh <- &endpointResponse{ep: ep, raw: raw, err: err}
+		}()
+	}
+
+	send()
+	var (
+		emptyResp *endpointResponse
+		lastErr   error
+	)
+	for {
+		var (
+			timer   *time.Timer
+			hedgeCh <-chan time.Time
+			resp    *endpointResponse
+		)
+		if fc.opts.HedgeDelay > 0 && next < len(endpoints) {
+			timer = time.NewTimer(fc.opts.HedgeDelay)
+			hedgeCh = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-hedgeCh:
+		case resp = <-respCh:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if resp == nil {
+			log.WithFields(log.Fields{
+				"method":   method,
+				"endpoint": endpoints[next].name,
+			}).Debug("hedging json-rpc request")
+			send()
+			continue
+		}
+
+		pending--
+		switch {
+		case resp.err == nil && !isEmptyResponse(resp.raw):
+			return resp.raw, nil
+		case resp.err == nil:
+			// the endpoint can be behind the others
+			emptyResp = resp
+		default:
+			lastErr = resp.err
+			log.WithError(resp.err).WithFields(log.Fields{
+				"method":   method,
+				"endpoint": resp.ep.name,
+			}).Debug("json-rpc request failed")
+		}
+		switch {
+		case next < len(endpoints):
+			send()
+		case pending > 0:
+		case emptyResp != nil:
+			return emptyResp.raw, nil
+		default:
+			return nil, lastErr
+		}
+	}
+}
+
+// quorumCall sends the request to all of the endpoints and returns the response which enough
+// endpoints agree on. The responses are compared by their hashes like the inspections do.
+func (fc *failoverClient) quorumCall(ctx context.Context, method string, args ...interface{}) (json.RawMessage, error) {
+	responses := make([]*endpointResponse, len(fc.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range fc.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpointClient) {
+			defer wg.Done()
+			raw, err := ep.call(ctx, method, args...)
+			responses[i] = &endpointResponse{ep: ep, raw: raw, err: err}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var (
+		votes     = make(map[string][]*endpointResponse)
+		bestHash  string
+		emptyResp *endpointResponse
+		lastErr   error
+	)
+	for _, resp := range responses {
+		if resp.err != nil {
+			lastErr = resp.err
+			continue
+		}
+		if isEmptyResponse(resp.raw) {
+			emptyResp = resp
+			continue
+		}
+		hash, err := responseHash(method, resp.raw)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		votes[hash] = append(votes[hash], resp)
+		if len(votes[hash]) > len(votes[bestHash]) {
+			bestHash = hash
+		}
+	}
+
+	if len(votes) > 1 {
+		fields := log.Fields{"method": method, "args": args}
+		for hash, resps := range votes {
+			var names []string
+			for _, resp := range resps {
+				names = append(names, resp.ep.name)
+			}
+			fields[hash] = names
+		}
+		log.WithFields(fields).Warn("json-rpc endpoints disagree")
+		fc.lastDisagreement.Set(fmt.Errorf("endpoints disagree on %s(%v)", method, args))
+	}
+
+	best := votes[bestHash]
+	if len(best) >= fc.opts.Quorum {
+		if len(votes) == 1 {
+			fc.lastDisagreement.Set(nil)
+		}
+		return best[0].raw, nil
+	}
+	// no endpoint has the block yet
+	if len(votes) == 0 && emptyResp != nil {
+		return emptyResp.raw, nil
+	}
+	if len(votes) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w: %d of %d endpoints agree on %s(%v)", ErrQuorumNotReached, len(best), fc.opts.Quorum, method, args)
+}
+
+func responseHash(method string, raw json.RawMessage) (string, error) {
+	switch method {
+	case blocksByNumber:
+		var block domain.Block
+		if err := json.Unmarshal(raw, &block); err != nil {
+			return "", err
+		}
+		return block.ResponseHash(), nil
+	case traceBlock:
+		var traces []domain.Trace
+		if err := json.Unmarshal(raw, &traces); err != nil {
+			return "", err
+		}
+		return domain.TracesResponseHash(traces), nil
+	default:
+		return "", fmt.Errorf("cannot compare the responses of %s", method)
+	}
+}
+
+// Subscribe implements RPCClient by subscribing to the best websocket endpoint.
+func (fc *failoverClient) Subscribe(ctx context.Context, channel interface{}, args ...interface{}) (domain.ClientSubscription, error) {
+	var lastErr error
+	for _, ep := range fc.ranked() {
+		if !ep.isWebsocket {
+			continue
+		}
+		sub, err := ep.client.Subscribe(ctx, channel, args...)
+		if err == nil {
+			return sub, nil
+		}
+		lastErr = err
+		log.WithError(err).WithField("endpoint", ep.name).Warn("failed to subscribe")
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no websocket endpoints")
+	}
+	return nil, lastErr
+}
+
+// Health implements the health.Reporter interface.
+func (fc *failoverClient) Health() health.Reports {
+	var reports health.Reports
+	var healthyCount int
+	for i, ep := range fc.endpoints {
+		reports = append(reports, ep.getReport(fmt.Sprintf("endpoint.%d", i)))
+		if ep.isHealthy() {
+			healthyCount++
+		}
+	}
+	endpointsReport := &health.Report{
+		Name:    "endpoints.healthy",
+		Status:  health.StatusOK,
+		Details: fmt.Sprintf("%d/%d", healthyCount, len(fc.endpoints)),
+	}
+	if healthyCount == 0 {
+		endpointsReport.Status = health.StatusFailing
+	}
+	reports = append(reports, endpointsReport)
+	if fc.opts.Quorum > 1 {
+		reports = append(reports, fc.lastDisagreement.GetReport("quorum.disagreement"))
+	}
+	return reports
+}
@@ -0,0 +1,132 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"zktoro/zktoro-core-go/clients/health"
+	"zktoro/zktoro-core-go/domain"
+	mocks "zktoro/zktoro-core-go/ethereum/mocks"
+)
+
+func initFailoverClient(t *testing.T, count int, opts FailoverOptions) (*failoverClient, []*mocks.MockRPCClient) {
+	ctrl := gomock.NewController(t)
+	var (
+		endpoints []*endpointClient
+		clients   []*mocks.MockRPCClient
+	)
+	for i := 0; i < count; i++ {
+		client := mocks.NewMockRPCClient(ctrl)
+		clients = append(clients, client)
+		endpoints = append(endpoints, &endpointClient{name: "endpoint", client: client})
+	}
+	fClient, err := newFailoverClient(endpoints, opts)
+	require.NoError(t, err)
+	return fClient, clients
+}
+
+func respondWith(v interface{}) func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		b, _ := json.Marshal(v)
+		return json.Unmarshal(b, result)
+	}
+}
+
+func TestFailoverClient_Failover(t *testing.T) {
+	r := require.New(t)
+
+	fClient, clients := initFailoverClient(t, 2, FailoverOptions{})
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).Return(testErr)
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).
+		DoAndReturn(respondWith(domain.Block{Hash: testBlockHash}))
+
+	var block domain.Block
+	r.NoError(fClient.CallContext(context.Background(), &block, blocksByHash, testBlockHash))
+	r.Equal(testBlockHash, block.Hash)
+
+	// the failed endpoint should not be preferred anymore
+	r.Same(fClient.endpoints[1], fClient.ranked()[0])
+
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).Return(testErr)
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).Return(testErr)
+	r.ErrorIs(fClient.CallContext(context.Background(), &block, blocksByHash, testBlockHash), testErr)
+}
+
+func TestFailoverClient_EmptyResponse(t *testing.T) {
+	r := require.New(t)
+
+	fClient, clients := initFailoverClient(t, 2, FailoverOptions{})
+	// the first endpoint does not have the block yet
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(nil))
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).
+		DoAndReturn(respondWith(domain.Block{Hash: testBlockHash}))
+
+	var block domain.Block
+	r.NoError(fClient.CallContext(context.Background(), &block, blocksByNumber, "0x1", true))
+	r.Equal(testBlockHash, block.Hash)
+}
+
+func TestFailoverClient_Hedge(t *testing.T) {
+	r := require.New(t)
+
+	fClient, clients := initFailoverClient(t, 2, FailoverOptions{HedgeDelay: 10 * time.Millisecond})
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).DoAndReturn(
+		func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).
+		DoAndReturn(respondWith(domain.Block{Hash: testBlockHash}))
+
+	var block domain.Block
+	r.NoError(fClient.CallContext(context.Background(), &block, blocksByHash, testBlockHash))
+	r.Equal(testBlockHash, block.Hash)
+
+	// the slow endpoint is not blamed for the cancelled request
+	r.Zero(fClient.endpoints[0].errorRate)
+}
+
+func TestFailoverClient_Quorum(t *testing.T) {
+	r := require.New(t)
+
+	fClient, clients := initFailoverClient(t, 3, FailoverOptions{Quorum: 2})
+	canonical := domain.Block{Hash: testBlockHash, Transactions: []domain.Transaction{{Hash: "0x1"}}}
+	other := domain.Block{Hash: testBlockHash, Transactions: []domain.Transaction{{Hash: "0x2"}}}
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(other))
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(canonical))
+	clients[2].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(canonical))
+
+	var block domain.Block
+	r.NoError(fClient.CallContext(context.Background(), &block, blocksByNumber, "0x1", true))
+	r.Equal(canonical.Transactions[0].Hash, block.Transactions[0].Hash)
+	r.Equal(health.StatusFailing, fClient.lastDisagreement.GetReport("").Status)
+
+	clients[0].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(other))
+	clients[1].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).DoAndReturn(respondWith(canonical))
+	clients[2].EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByNumber, "0x1", true).Return(testErr)
+	r.ErrorIs(fClient.CallContext(context.Background(), &block, blocksByNumber, "0x1", true), ErrQuorumNotReached)
+
+	// other methods are not sent to all of the endpoints
+	var calls int
+	for _, client := range clients {
+		client.EXPECT().CallContext(gomock.Any(), gomock.Any(), blocksByHash, testBlockHash).DoAndReturn(
+			func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+				calls++
+				return respondWith(domain.Block{Hash: testBlockHash})(ctx, result, method, args...)
+			},
+		).AnyTimes()
+	}
+	r.NoError(fClient.CallContext(context.Background(), &block, blocksByHash, testBlockHash))
+	r.Equal(1, calls)
+}
+
+func TestFailoverClient_QuorumTooLarge(t *testing.T) {
+	_, err := newFailoverClient([]*endpointClient{{name: "endpoint"}}, FailoverOptions{Quorum: 2})
+	require.Error(t, err)
+}
@@ -20,6 +20,9 @@ import (
 
 var ErrEndBlockReached = errors.New("end block reached")
 
+// DefaultReorgWindow is the default number of recent blocks which are checked for reorgs.
+const DefaultReorgWindow = 64
+
 type bfHandler struct {
 	Handler func(evt *domain.BlockEvent) error
 	ErrCh   chan<- error
@@ -40,6 +43,12 @@ type blockFeed struct {
 	maxBlockAge      *time.Duration
 	subscriptionMode bool
 
+	// recent block hashes by number to detect reorgs
+	reorgWindow  int
+	recentBlocks map[uint64]string
+	// blocks up to this number are fed again after a reorg
+	refeedUntil *big.Int
+
 	lastBlock health.MessageTracker
 
 	handlers   []bfHandler
@@ -57,6 +66,9 @@ type BlockFeedConfig struct {
 	RateLimit           *time.Ticker
 	Tracing             bool
 	SkipBlocksOlderThan *time.Duration
+	// ReorgWindow is the number of recent blocks to check reorgs against. Zero uses the
+	// default window and a negative value disables the reorg detection.
+	ReorgWindow int
 }
 
 func (bf *blockFeed) initialize() error {
@@ -255,7 +267,7 @@ func (bf *blockFeed) forEachBlock() error {
 			logger.Info("end block reached - exiting")
 			return ErrEndBlockReached
 		}
-		if bf.cache.Exists(blockNumToAnalyze.String()) {
+		if bf.cache.Exists(blockNumToAnalyze.String()) && !bf.isRefeeding(blockNumToAnalyze) {
 			logger.Info("already analyzed block - skipping")
 			currentBlockNum.Add(currentBlockNum, increment)
 			continue
@@ -296,6 +308,25 @@ func (bf *blockFeed) forEachBlock() error {
 			continue
 		}
 
+		reorg, err := bf.detectReorg(block, blockNumToAnalyze)
+		if err != nil {
+			logger.WithError(err).Error("failed to check reorg")
+			continue
+		}
+		if reorg != nil {
+			logger.WithFields(log.Fields{
+				"commonAncestor": reorg.CommonAncestor,
+				"orphanedBlocks": len(reorg.Orphaned),
+			}).Warn("detected reorg - feeding the canonical blocks again")
+			if err := bf.handleReorg(block, reorg); err != nil {
+				return err
+			}
+			// continue from the first orphaned block
+			currentBlockNum = new(big.Int).SetUint64(reorg.CommonAncestor + 1)
+			currentBlockNum.Add(currentBlockNum, big.NewInt(int64(bf.offset)))
+			continue
+		}
+
 		bf.lastBlock.Set(blockNumToAnalyze.String())
 
 		var traces []domain.Trace
@@ -345,11 +376,107 @@ func (bf *blockFeed) forEachBlock() error {
 			}
 		}
 		bf.cache.Add(blockNumToAnalyze.String())
+		bf.addRecentBlock(blockNumToAnalyze.Uint64(), block.Hash)
 
 		currentBlockNum.Add(currentBlockNum, increment)
 	}
 }
 
+func (bf *blockFeed) isRefeeding(blockNum *big.Int) bool {
+	return bf.refeedUntil != nil && blockNum.Cmp(bf.refeedUntil) <= 0
+}
+
+func (bf *blockFeed) addRecentBlock(blockNum uint64, hash string) {
+	if bf.reorgWindow <= 0 {
+		return
+	}
+	if bf.recentBlocks == nil {
+		bf.recentBlocks = make(map[uint64]string)
+	}
+	bf.recentBlocks[blockNum] = hash
+	if blockNum >= uint64(bf.reorgWindow) {
+		delete(bf.recentBlocks, blockNum-uint64(bf.reorgWindow))
+	}
+}
+
+// detectReorg checks if the parent of the block is the previously processed block and,
+// if not, walks back the recent blocks to find the ones which are no longer canonical.
+func (bf *blockFeed) detectReorg(block *domain.Block, blockNum *big.Int) (*domain.Reorg, error) {
+	if bf.reorgWindow <= 0 || blockNum.Sign() == 0 {
+		return nil, nil
+	}
+	parentNum := blockNum.Uint64() - 1
+	parentHash, ok := bf.recentBlocks[parentNum]
+	if !ok || parentHash == block.ParentHash {
+		return nil, nil
+	}
+
+	reorg := &domain.Reorg{}
+	for num := parentNum; ; num-- {
+		knownHash, ok := bf.recentBlocks[num]
+		if !ok {
+			// the reorg is deeper than the window: the blocks before are assumed canonical
+			reorg.CommonAncestor = num
+			break
+		}
+		canonical, err := bf.client.BlockByNumber(bf.ctx, new(big.Int).SetUint64(num))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get canonical block %d: %v", num, err)
+		}
+		if canonical.Hash == knownHash {
+			reorg.CommonAncestor = num
+			break
+		}
+		reorg.Orphaned = append([]*domain.OrphanedBlock{{
+			Number:        num,
+			Hash:          knownHash,
+			CanonicalHash: canonical.Hash,
+		}}, reorg.Orphaned...)
+		if num == 0 {
+			break
+		}
+	}
+	if len(reorg.Orphaned) == 0 {
+		// the parent is canonical but the block is not, so the node is not consistent yet
+		return nil, fmt.Errorf("block %s is not a child of the canonical block %d", block.Hash, parentNum)
+	}
+	return reorg, nil
+}
+
+// handleReorg sends the reorg event to the handlers and prepares feeding the canonical blocks.
+func (bf *blockFeed) handleReorg(block *domain.Block, reorg *domain.Reorg) error {
+	blockTs, err := block.GetTimestamp()
+	if err != nil {
+		return err
+	}
+	evt := &domain.BlockEvent{
+		EventType: domain.EventTypeReorg,
+		Block:     block,
+		ChainID:   bf.chainID,
+		Reorg:     reorg,
+		Timestamps: &domain.TrackingTimestamps{
+			Block: *blockTs,
+			Feed:  time.Now().UTC(),
+		},
+	}
+	bf.handlersMu.RLock()
+	handlers := bf.handlers
+	bf.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		if err := handler.Handler(evt); err != nil {
+			return err
+		}
+	}
+
+	for _, orphaned := range reorg.Orphaned {
+		delete(bf.recentBlocks, orphaned.Number)
+	}
+	if len(reorg.Orphaned) > 0 {
+		bf.refeedUntil = new(big.Int).SetUint64(reorg.Orphaned[len(reorg.Orphaned)-1].Number)
+	}
+	return nil
+}
+
 func blockIsTooOld(block *domain.Block, maxAge *time.Duration) (bool, *time.Duration) {
 	if maxAge == nil {
 		return false, nil
@@ -393,6 +520,10 @@ func NewBlockFeed(ctx context.Context, client ethereum.Client, traceClient ether
 		rateLimit:        cfg.RateLimit,
 		maxBlockAge:      cfg.SkipBlocksOlderThan,
 		subscriptionMode: client.IsWebsocket(),
+		reorgWindow:      cfg.ReorgWindow,
+	}
+	if bf.reorgWindow == 0 {
+		bf.reorgWindow = DefaultReorgWindow
 	}
 	return bf, nil
 }
@@ -11,10 +11,12 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"zktoro/zktoro-core-go/clients/health"
 	"zktoro/zktoro-core-go/domain"
 	mocks "zktoro/zktoro-core-go/ethereum/mocks"
+	"zktoro/zktoro-core-go/protocol"
 	"zktoro/zktoro-core-go/utils"
 )
 
@@ -152,6 +154,56 @@ func TestBlockFeed_ForEachBlock(t *testing.T) {
 	assertEvts(t, evts, blockEvent(block1), blockEvent(block2), blockEvent(block3))
 }
 
+func TestBlockFeed_ForEachBlock_Reorg(t *testing.T) {
+	r := require.New(t)
+
+	bf, client, traceClient, ctx, _ := getTestBlockFeed(t)
+	bf.reorgWindow = 10
+	bf.chainID = big.NewInt(1)
+
+	block1 := blockWithParent(startHash, 1)
+	block2 := blockWithParent(block1.Hash, 2)
+	block3 := blockWithParent(block2.Hash, 3)
+	// block 3 is replaced and the next block builds on the new one
+	newBlock3 := blockWithParent(block2.Hash+"new", 3)
+	newBlock3.ParentHash = block2.Hash
+	newBlock4 := blockWithParent(newBlock3.Hash, 4)
+
+	client.EXPECT().BlockByNumber(ctx, big.NewInt(1)).Return(block1, nil).Times(1)
+	client.EXPECT().BlockByNumber(ctx, big.NewInt(2)).Return(block2, nil).Times(2)
+	client.EXPECT().BlockByNumber(ctx, big.NewInt(3)).Return(block3, nil).Times(1)
+	client.EXPECT().BlockByNumber(ctx, big.NewInt(3)).Return(newBlock3, nil).Times(2)
+	client.EXPECT().BlockByNumber(ctx, big.NewInt(4)).Return(newBlock4, nil).Times(2)
+	client.EXPECT().GetLogs(ctx, gomock.Any()).Return(nil, nil).AnyTimes()
+	traceClient.EXPECT().TraceBlock(ctx, gomock.Any()).Return(nil, nil).AnyTimes()
+
+	var evts []*domain.BlockEvent
+	bf.Subscribe(func(evt *domain.BlockEvent) error {
+		evts = append(evts, evt)
+		if len(evts) == 6 {
+			return testErr
+		}
+		return nil
+	})
+	r.ErrorIs(bf.forEachBlock(), testErr)
+	r.Len(evts, 6)
+
+	assertEvts(t, []*domain.BlockEvent{evts[0], evts[1], evts[2]}, blockEvent(block1), blockEvent(block2), blockEvent(block3))
+
+	reorgEvt := evts[3]
+	r.True(reorgEvt.IsReorg())
+	r.Equal(newBlock4, reorgEvt.Block)
+	r.Equal(uint64(2), reorgEvt.Reorg.CommonAncestor)
+	r.Equal([]*domain.OrphanedBlock{{Number: 3, Hash: block3.Hash, CanonicalHash: newBlock3.Hash}}, reorgEvt.Reorg.Orphaned)
+	msg, err := reorgEvt.ToMessage()
+	r.NoError(err)
+	r.Equal(protocol.BlockEvent_REORG, msg.Type)
+
+	// the canonical blocks are fed again
+	r.False(evts[4].IsReorg())
+	assertEvts(t, []*domain.BlockEvent{evts[4], evts[5]}, blockEvent(newBlock3), blockEvent(newBlock4))
+}
+
 func TestBlockFeed_ForEachBlock_SubscriptionMode(t *testing.T) {
 	bf, client, traceClient, ctx, cancel := getTestBlockFeed(t)
 	bf.subscriptionMode = true
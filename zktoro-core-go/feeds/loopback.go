@@ -0,0 +1,278 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/sirupsen/logrus"
+	"zktoro/zktoro-core-go/clients/health"
+	"zktoro/zktoro-core-go/domain"
+	"zktoro/zktoro-core-go/protocol"
+)
+
+const defaultLoopbackBufferSize = 1000
+
+// LoopbackFeed is an alert feed which pushes the alerts of the bots on this node directly to the
+// subscribed combiner bots. The subscriptions to the bots which are not on this node are handled
+// by the remote feed.
+//
+// The sharded bots are handled by the remote feed even if they run on this node, since the other
+// shards of the bot may run on the other nodes.
+type LoopbackFeed struct {
+	ctx     context.Context
+	remote  AlertFeed
+	alertCh chan *protocol.AlertEvent_Alert
+	started bool
+
+	lastAlert health.TimeTracker
+
+	localBots     map[string]bool
+	subscriptions []*domain.CombinerBotSubscription
+	mu            sync.RWMutex
+
+	handlers   []func(evt *domain.AlertEvent) error
+	handlersMu sync.RWMutex
+}
+
+// NewLoopbackFeed creates a new loopback feed which falls back to the remote feed.
+func NewLoopbackFeed(ctx context.Context, remote AlertFeed) *LoopbackFeed {
+	return &LoopbackFeed{
+		ctx:       ctx,
+		remote:    remote,
+		alertCh:   make(chan *protocol.AlertEvent_Alert, defaultLoopbackBufferSize),
+		localBots: make(map[string]bool),
+	}
+}
+
+// Start starts the remote feed and the delivery of the local alerts.
+func (lf *LoopbackFeed) Start() {
+	lf.remote.Start()
+	if !lf.started {
+		lf.started = true
+		go lf.loop()
+	}
+}
+
+// AddSubscription adds the subscription to the loopback feed if the subscribed bot is on this
+// node and to the remote feed otherwise.
+func (lf *LoopbackFeed) AddSubscription(subscription *domain.CombinerBotSubscription) error {
+	if subscription == nil || subscription.Subscription == nil || subscription.Subscriber == nil {
+		return fmt.Errorf("nil subscription data")
+	}
+
+	// subscriptions should be bot <-> bot
+	if subscription.Subscription.BotId == "" {
+		return fmt.Errorf("subscription must have valid bot id")
+	}
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	for _, s := range lf.subscriptions {
+		if s.Equal(subscription) {
+			return fmt.Errorf("incoming subscription already exists")
+		}
+	}
+
+	if !lf.isLocal(subscription) {
+		if err := lf.remote.AddSubscription(subscription); err != nil {
+			return err
+		}
+	}
+	lf.subscriptions = append(lf.subscriptions, subscription)
+	return nil
+}
+
+// RemoveSubscription removes the subscription from the loopback feed and the remote feed.
+func (lf *LoopbackFeed) RemoveSubscription(subscription *domain.CombinerBotSubscription) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	for i, s := range lf.subscriptions {
+		if s.Equal(subscription) {
+			if !lf.isLocal(s) {
+				lf.remote.RemoveSubscription(s)
+			}
+			lf.subscriptions = append(lf.subscriptions[:i], lf.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Subscriptions returns the local and the remote subscriptions.
+func (lf *LoopbackFeed) Subscriptions() []*domain.CombinerBotSubscription {
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+
+	return lf.subscriptions
+}
+
+// RegisterHandler registers the handler to the loopback feed and the remote feed. The returned
+// channel receives the errors of the remote feed.
+func (lf *LoopbackFeed) RegisterHandler(alertHandler func(evt *domain.AlertEvent) error) <-chan error {
+	lf.handlersMu.Lock()
+	lf.handlers = append(lf.handlers, alertHandler)
+	lf.handlersMu.Unlock()
+
+	return lf.remote.RegisterHandler(alertHandler)
+}
+
+// LocalBot is a bot which runs on this node.
+type LocalBot struct {
+	ID string
+	// Shards is the total number of the shards of the bot and zero if the bot is not sharded.
+	Shards uint
+}
+
+// SetLocalBots updates the bots which run on this node and moves the subscriptions to these bots
+// between the loopback feed and the remote feed.
+func (lf *LoopbackFeed) SetLocalBots(bots []LocalBot) {
+	localBots := make(map[string]bool)
+	for _, bot := range bots {
+		// only some of the shards may run on this node
+		if bot.Shards > 1 {
+			continue
+		}
+		localBots[strings.ToLower(bot.ID)] = true
+	}
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	for _, subscription := range lf.subscriptions {
+		botID := strings.ToLower(subscription.Subscription.BotId)
+		wasLocal := lf.localBots[botID]
+		isLocal := localBots[botID]
+		switch {
+		case wasLocal && !isLocal:
+			if err := lf.remote.AddSubscription(subscription); err != nil {
+				log.WithFields(log.Fields{
+					"subscriberBot": subscription.Subscriber.BotID,
+					"subscribesTo":  subscription.Subscription.BotId,
+				}).WithError(err).Warn("failed to move subscription to remote feed")
+			}
+		case !wasLocal && isLocal:
+			lf.remote.RemoveSubscription(subscription)
+		}
+	}
+	lf.localBots = localBots
+}
+
+// Publish queues an alert of a bot on this node for delivery to the subscribers. The alert is
+// dropped if the buffer is full so that the caller is never blocked.
+func (lf *LoopbackFeed) Publish(alert *protocol.AlertEvent_Alert) {
+	if alert == nil || alert.Source == nil || alert.Source.Bot == nil {
+		return
+	}
+	select {
+	case lf.alertCh <- alert:
+	default:
+		log.WithField("alertHash", alert.Hash).Warn("loopback alert buffer is full - skipping")
+	}
+}
+
+func (lf *LoopbackFeed) isLocal(subscription *domain.CombinerBotSubscription) bool {
+	return lf.localBots[strings.ToLower(subscription.Subscription.BotId)]
+}
+
+func (lf *LoopbackFeed) loop() {
+	for {
+		select {
+		case <-lf.ctx.Done():
+			return
+		case alert := <-lf.alertCh:
+			lf.handleAlert(alert)
+		}
+	}
+}
+
+func (lf *LoopbackFeed) handleAlert(alert *protocol.AlertEvent_Alert) {
+	createdAt, err := time.Parse(time.RFC3339, alert.CreatedAt)
+	if err != nil {
+		log.WithField("alertHash", alert.Hash).WithError(err).Warn("failed to process loopback alert")
+		return
+	}
+
+	lf.handlersMu.RLock()
+	handlers := lf.handlers
+	lf.handlersMu.RUnlock()
+
+	for _, subscription := range lf.matchingSubscriptions(alert) {
+		evt := &domain.AlertEvent{
+			Event: &protocol.AlertEvent{
+				Alert: alert,
+				Timestamps: &protocol.TrackingTimestamps{
+					SourceAlert: hexutil.EncodeUint64(uint64(createdAt.Unix())),
+				},
+			},
+			Timestamps: &domain.TrackingTimestamps{
+				Feed:        time.Now().UTC(),
+				SourceAlert: createdAt,
+			},
+			Subscriber: subscription.Subscriber,
+		}
+
+		for _, handler := range handlers {
+			if err := handler(evt); err != nil {
+				log.WithField("alertHash", alert.Hash).WithError(err).Warn("error executing alert handler")
+			}
+		}
+		lf.lastAlert.Set()
+	}
+}
+
+// matchingSubscriptions returns the local subscriptions which match the bot, the alert ID and
+// the chain of the alert.
+func (lf *LoopbackFeed) matchingSubscriptions(alert *protocol.AlertEvent_Alert) []*domain.CombinerBotSubscription {
+	botID := strings.ToLower(alert.Source.Bot.Id)
+
+	lf.mu.RLock()
+	defer lf.mu.RUnlock()
+
+	if !lf.localBots[botID] {
+		return nil
+	}
+
+	var matches []*domain.CombinerBotSubscription
+	for _, subscription := range lf.subscriptions {
+		if strings.ToLower(subscription.Subscription.BotId) != botID {
+			continue
+		}
+		if subscriptionMatchesAlert(subscription.Subscription, alert) {
+			matches = append(matches, subscription)
+		}
+	}
+	return matches
+}
+
+func subscriptionMatchesAlert(subscription *protocol.CombinerBotSubscription, alert *protocol.AlertEvent_Alert) bool {
+	if subscription.ChainId != 0 && subscription.ChainId != alert.ChainId {
+		return false
+	}
+	if len(subscription.AlertId) == 0 && len(subscription.AlertIds) == 0 {
+		return true
+	}
+	if subscription.AlertId == alert.AlertId {
+		return true
+	}
+	for _, alertID := range subscription.AlertIds {
+		if alertID == alert.AlertId {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of this implementation.
+func (lf *LoopbackFeed) Name() string {
+	return "loopback-alert-feed"
+}
+
+// Health implements the health.Reporter interface.
+func (lf *LoopbackFeed) Health() health.Reports {
+	return append(lf.remote.Health(), lf.lastAlert.GetReport("loopback.event.alert.time"))
+}
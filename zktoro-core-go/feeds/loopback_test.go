@@ -0,0 +1,188 @@
+package feeds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"zktoro/zktoro-core-go/clients/health"
+	"zktoro/zktoro-core-go/domain"
+	"zktoro/zktoro-core-go/protocol"
+)
+
+type remoteFeedStub struct {
+	subscriptions []*domain.CombinerBotSubscription
+}
+
+func (r *remoteFeedStub) Start() {}
+
+func (r *remoteFeedStub) AddSubscription(subscription *domain.CombinerBotSubscription) error {
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+func (r *remoteFeedStub) RemoveSubscription(subscription *domain.CombinerBotSubscription) {
+	for i, s := range r.subscriptions {
+		if s.Equal(subscription) {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *remoteFeedStub) Subscriptions() []*domain.CombinerBotSubscription {
+	return r.subscriptions
+}
+
+func (r *remoteFeedStub) RegisterHandler(alertHandler func(evt *domain.AlertEvent) error) <-chan error {
+	return make(chan error)
+}
+
+func (r *remoteFeedStub) Name() string {
+	return "remote"
+}
+
+func (r *remoteFeedStub) Health() health.Reports {
+	return nil
+}
+
+func testSubscription(subscriber, botID string, alertIDs []string, chainID uint64) *domain.CombinerBotSubscription {
+	return &domain.CombinerBotSubscription{
+		Subscription: &protocol.CombinerBotSubscription{
+			BotId:    botID,
+			AlertIds: alertIDs,
+			ChainId:  chainID,
+		},
+		Subscriber: &domain.Subscriber{BotID: subscriber},
+	}
+}
+
+func testLoopbackAlert(hash, botID, alertID string, chainID uint64) *protocol.AlertEvent_Alert {
+	return &protocol.AlertEvent_Alert{
+		Hash:      hash,
+		AlertId:   alertID,
+		ChainId:   chainID,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Source: &protocol.AlertEvent_Alert_Source{
+			Bot: &protocol.AlertEvent_Alert_Bot{Id: botID},
+		},
+	}
+}
+
+func TestLoopbackFeed_RoutesSubscriptions(t *testing.T) {
+	r := require.New(t)
+
+	remote := &remoteFeedStub{}
+	lf := NewLoopbackFeed(context.Background(), remote)
+	lf.SetLocalBots([]LocalBot{{ID: "0xLocal"}})
+
+	localSub := testSubscription("0xcombiner", "0xlocal", nil, 0)
+	remoteSub := testSubscription("0xcombiner", "0xremote", nil, 0)
+	r.NoError(lf.AddSubscription(localSub))
+	r.NoError(lf.AddSubscription(remoteSub))
+	r.Error(lf.AddSubscription(localSub))
+
+	r.Len(lf.Subscriptions(), 2)
+	r.Equal([]*domain.CombinerBotSubscription{remoteSub}, remote.Subscriptions())
+
+	// the remote feed takes over when the bot stops running on this node
+	lf.SetLocalBots([]LocalBot{{ID: "0xremote"}})
+	r.ElementsMatch([]*domain.CombinerBotSubscription{localSub}, remote.Subscriptions())
+
+	lf.RemoveSubscription(localSub)
+	r.Empty(remote.Subscriptions())
+	r.Equal([]*domain.CombinerBotSubscription{remoteSub}, lf.Subscriptions())
+}
+
+func TestLoopbackFeed_ShardedBots(t *testing.T) {
+	r := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote := &remoteFeedStub{}
+	lf := NewLoopbackFeed(ctx, remote)
+	lf.SetLocalBots([]LocalBot{{ID: "0xlocal"}, {ID: "0xsharded", Shards: 2}})
+
+	localSub := testSubscription("0xcombiner", "0xlocal", nil, 0)
+	shardedSub := testSubscription("0xcombiner", "0xsharded", nil, 0)
+	r.NoError(lf.AddSubscription(localSub))
+	r.NoError(lf.AddSubscription(shardedSub))
+
+	// the other shards may run on the other nodes so the remote feed delivers all alerts of the bot
+	r.Equal([]*domain.CombinerBotSubscription{shardedSub}, remote.Subscriptions())
+
+	events := make(chan *domain.AlertEvent, 10)
+	lf.RegisterHandler(func(evt *domain.AlertEvent) error {
+		events <- evt
+		return nil
+	})
+	lf.Start()
+
+	lf.Publish(testLoopbackAlert("0x1", "0xsharded", "ALERT-1", 1))
+	lf.Publish(testLoopbackAlert("0x2", "0xlocal", "ALERT-1", 1))
+
+	select {
+	case evt := <-events:
+		r.Equal("0x2", evt.Event.Alert.Hash)
+	case <-time.After(time.Second * 5):
+		r.FailNow("timed out waiting for the alert")
+	}
+	select {
+	case evt := <-events:
+		r.FailNow("unexpected alert", evt.Event.Alert.Hash)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	// the subscription moves to the remote feed when the local bot becomes sharded
+	lf.SetLocalBots([]LocalBot{{ID: "0xlocal", Shards: 2}, {ID: "0xsharded", Shards: 2}})
+	r.ElementsMatch([]*domain.CombinerBotSubscription{localSub, shardedSub}, remote.Subscriptions())
+
+	// and back to the loopback feed when it is not sharded anymore
+	lf.SetLocalBots([]LocalBot{{ID: "0xlocal", Shards: 1}, {ID: "0xsharded", Shards: 2}})
+	r.Equal([]*domain.CombinerBotSubscription{shardedSub}, remote.Subscriptions())
+}
+
+func TestLoopbackFeed_DeliversLocalAlerts(t *testing.T) {
+	r := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lf := NewLoopbackFeed(ctx, &remoteFeedStub{})
+	lf.SetLocalBots([]LocalBot{{ID: "0xlocal"}, {ID: "0xother"}})
+	r.NoError(lf.AddSubscription(testSubscription("0xcombiner1", "0xlocal", []string{"ALERT-1"}, 0)))
+	r.NoError(lf.AddSubscription(testSubscription("0xcombiner2", "0xlocal", nil, 137)))
+	r.NoError(lf.AddSubscription(testSubscription("0xcombiner3", "0xremote", nil, 0)))
+
+	events := make(chan *domain.AlertEvent, 10)
+	lf.RegisterHandler(func(evt *domain.AlertEvent) error {
+		events <- evt
+		return nil
+	})
+	lf.Start()
+
+	lf.Publish(testLoopbackAlert("0x1", "0xlocal", "ALERT-1", 1))
+	lf.Publish(testLoopbackAlert("0x2", "0xlocal", "ALERT-2", 137))
+	lf.Publish(testLoopbackAlert("0x3", "0xother", "ALERT-1", 1))
+	lf.Publish(testLoopbackAlert("0x4", "0xremote", "ALERT-1", 1))
+
+	var received []string
+	for len(received) < 2 {
+		select {
+		case evt := <-events:
+			r.NotNil(evt.Event.Timestamps)
+			received = append(received, evt.Subscriber.BotID+":"+evt.Event.Alert.Hash)
+		case <-time.After(time.Second * 5):
+			r.FailNow("timed out waiting for the alerts", received)
+		}
+	}
+	r.Equal([]string{"0xcombiner1:0x1", "0xcombiner2:0x2"}, received)
+
+	select {
+	case evt := <-events:
+		r.FailNow("unexpected alert", evt.Event.Alert.Hash)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
@@ -31,6 +31,10 @@ func (tf *transactionFeed) streamTransactions() error {
 		if !ok {
 			return nil
 		}
+		// the canonical blocks are fed again after the reorg events
+		if blockEvt.IsReorg() {
+			continue
+		}
 
 		logger := log.WithFields(log.Fields{
 			"blockHex":     blockEvt.Block.Number,
@@ -52,7 +56,8 @@ func (tf *transactionFeed) streamTransactions() error {
 			case <-tf.ctx.Done():
 				return tf.ctx.Err()
 			default:
-				if !tf.cache.ExistsAndAdd(tx.Hash) {
+				// a tx is evaluated again if it is included in another block after a reorg
+				if !tf.cache.ExistsAndAdd(blockEvt.Block.Hash + tx.Hash) {
 					log.Debugf("tx-iterator: block(%s), txs <- %s", blockEvt.Block.Number, tx.Hash)
 					tf.txCh <- &domain.TransactionEvent{
 						BlockEvt:    blockEvt,
@@ -2,8 +2,6 @@ package inspect
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 
@@ -24,31 +22,16 @@ func GetBlockResponseHash(ctx context.Context, rpcClient *rpc.Client, blockNumbe
 	if err := getRpcResponse(ctx, rpcClient, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(blockNumber), true); err != nil {
 		return "", err
 	}
-	hashConcat := block.Hash
-	for _, tx := range block.Transactions {
-		hashConcat += tx.Hash
-	}
-	return hashOf(hashConcat), nil
+	return block.ResponseHash(), nil
 }
 
 // GetTraceResponseHash computes a hash by using some data from the API response.
 func GetTraceResponseHash(ctx context.Context, rpcClient *rpc.Client, blockNumber uint64) (string, error) {
-	var traces []*domain.Trace
+	var traces []domain.Trace
 	if err := getRpcResponse(ctx, rpcClient, &traces, "trace_block", hexutil.EncodeUint64(blockNumber)); err != nil {
 		return "", err
 	}
-	var hashConcat string
-	for _, trace := range traces {
-		if trace.TransactionHash != nil {
-			hashConcat += *trace.TransactionHash
-		}
-	}
-	return hashOf(hashConcat), nil
-}
-
-func hashOf(str string) string {
-	hash := sha256.Sum256([]byte(str))
-	return hex.EncodeToString(hash[:])
+	return domain.TracesResponseHash(traces), nil
 }
 
 func getRpcResponse(ctx context.Context, rpcClient *rpc.Client, respData interface{}, method string, args ...interface{}) error {
@@ -19,12 +19,24 @@ type AgentManifest struct {
 	Documentation   *string                       `json:"documentation"`
 	ChainIDs        []int64                       `json:"chainIds"`
 	ChainSettings   map[string]AgentChainSettings `json:"chainSettings"`
+	Resources       *AgentResources               `json:"resources,omitempty"`
+}
+
+// AgentResources are the container resources a bot needs. The nodes cap these by their own limits.
+type AgentResources struct {
+	CPUs      float64 `json:"cpus,omitempty"`
+	MemoryMiB int     `json:"memoryMib,omitempty"`
+	Pids      int64   `json:"pids,omitempty"`
+	DiskMiB   int     `json:"diskMib,omitempty"`
 }
 
 // AgentChainSettings is the per-chain configuration of a bot.
 type AgentChainSettings struct {
 	Shards uint `json:"shards"`
 	Target uint `json:"target"`
+	// ShardStrategy decides which shard processes a tx: "block" (default), "txHash",
+	// "toAddress" or "fromAddress".
+	ShardStrategy string `json:"shardStrategy,omitempty"`
 }
 
 // SignedAgentManifest is the contents of an agent manifest
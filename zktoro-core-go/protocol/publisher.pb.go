@@ -35,6 +35,7 @@ type NotifyRequest struct {
 	Timestamps        *TrackingTimestamps    `protobuf:"bytes,7,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
 	EvalAlertRequest  *EvaluateAlertRequest  `protobuf:"bytes,8,opt,name=evalAlertRequest,proto3" json:"evalAlertRequest,omitempty"`
 	EvalAlertResponse *EvaluateAlertResponse `protobuf:"bytes,9,opt,name=evalAlertResponse,proto3" json:"evalAlertResponse,omitempty"`
+	Retractions       []*BlockRetraction     `protobuf:"bytes,10,rep,name=retractions,proto3" json:"retractions,omitempty"`
 }
 
 func (x *NotifyRequest) Reset() {
@@ -132,6 +133,77 @@ func (x *NotifyRequest) GetEvalAlertResponse() *EvaluateAlertResponse {
 	return nil
 }
 
+func (x *NotifyRequest) GetRetractions() []*BlockRetraction {
+	if x != nil {
+		return x.Retractions
+	}
+	return nil
+}
+
+// BlockRetraction invalidates the alerts of a block which was orphaned by a reorg.
+type BlockRetraction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockNumber        uint64 `protobuf:"varint,1,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	BlockHash          string `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	CanonicalBlockHash string `protobuf:"bytes,3,opt,name=canonicalBlockHash,proto3" json:"canonicalBlockHash,omitempty"`
+}
+
+func (x *BlockRetraction) Reset() {
+	*x = BlockRetraction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockRetraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockRetraction) ProtoMessage() {}
+
+func (x *BlockRetraction) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockRetraction.ProtoReflect.Descriptor instead.
+func (*BlockRetraction) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlockRetraction) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *BlockRetraction) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *BlockRetraction) GetCanonicalBlockHash() string {
+	if x != nil {
+		return x.CanonicalBlockHash
+	}
+	return ""
+}
+
 type NotifyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -141,7 +213,7 @@ type NotifyResponse struct {
 func (x *NotifyResponse) Reset() {
 	*x = NotifyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_publisher_proto_msgTypes[1]
+		mi := &file_publisher_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -154,7 +226,7 @@ func (x *NotifyResponse) String() string {
 func (*NotifyResponse) ProtoMessage() {}
 
 func (x *NotifyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_publisher_proto_msgTypes[1]
+	mi := &file_publisher_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,7 +239,7 @@ func (x *NotifyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NotifyResponse.ProtoReflect.Descriptor instead.
 func (*NotifyResponse) Descriptor() ([]byte, []int) {
-	return file_publisher_proto_rawDescGZIP(), []int{1}
+	return file_publisher_proto_rawDescGZIP(), []int{2}
 }
 
 var File_publisher_proto protoreflect.FileDescriptor
@@ -175,7 +247,7 @@ var File_publisher_proto protoreflect.FileDescriptor
 var file_publisher_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x12, 0x0d, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61,
-	0x1a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa5, 0x05,
+	0x1a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe7, 0x05,
 	0x0a, 0x0d, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x3c, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66,
@@ -218,15 +290,27 @@ var file_publisher_proto_rawDesc = []byte{
 	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61, 0x2e, 0x45, 0x76, 0x61,
 	0x6c, 0x75, 0x61, 0x74, 0x65, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
 	0x73, 0x65, 0x52, 0x11, 0x65, 0x76, 0x61, 0x6c, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x10, 0x0a, 0x0e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x58, 0x0a, 0x0d, 0x50, 0x75, 0x62, 0x6c, 0x69,
-	0x73, 0x68, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x47, 0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69,
-	0x66, 0x79, 0x12, 0x1c, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72,
-	0x74, 0x61, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61,
-	0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x42, 0x0d, 0x5a, 0x0b, 0x2e, 0x2f, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x50, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x65, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0f, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x52, 0x65, 0x74, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1c, 0x0a,
+	0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2e, 0x0a, 0x12, 0x63,
+	0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63,
+	0x61, 0x6c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x22, 0x10, 0x0a, 0x0e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x58, 0x0a,
+	0x0d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x47,
+	0x0a, 0x06, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x1c, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x2e, 0x66, 0x6f, 0x72, 0x74, 0x61, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x0d, 0x5a, 0x0b, 0x2e, 0x2f, 0x3b, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x50, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -241,37 +325,39 @@ func file_publisher_proto_rawDescGZIP() []byte {
 	return file_publisher_proto_rawDescData
 }
 
-var file_publisher_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_publisher_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_publisher_proto_goTypes = []interface{}{
 	(*NotifyRequest)(nil),         // 0: network.zktoro.NotifyRequest
-	(*NotifyResponse)(nil),        // 1: network.zktoro.NotifyResponse
-	(*SignedAlert)(nil),           // 2: network.zktoro.SignedAlert
-	(*EvaluateTxRequest)(nil),     // 3: network.zktoro.EvaluateTxRequest
-	(*EvaluateTxResponse)(nil),    // 4: network.zktoro.EvaluateTxResponse
-	(*EvaluateBlockRequest)(nil),  // 5: network.zktoro.EvaluateBlockRequest
-	(*EvaluateBlockResponse)(nil), // 6: network.zktoro.EvaluateBlockResponse
-	(*AgentInfo)(nil),             // 7: network.zktoro.AgentInfo
-	(*TrackingTimestamps)(nil),    // 8: network.zktoro.TrackingTimestamps
-	(*EvaluateAlertRequest)(nil),  // 9: network.zktoro.EvaluateAlertRequest
-	(*EvaluateAlertResponse)(nil), // 10: network.zktoro.EvaluateAlertResponse
+	(*BlockRetraction)(nil),       // 1: network.zktoro.BlockRetraction
+	(*NotifyResponse)(nil),        // 2: network.zktoro.NotifyResponse
+	(*SignedAlert)(nil),           // 3: network.zktoro.SignedAlert
+	(*EvaluateTxRequest)(nil),     // 4: network.zktoro.EvaluateTxRequest
+	(*EvaluateTxResponse)(nil),    // 5: network.zktoro.EvaluateTxResponse
+	(*EvaluateBlockRequest)(nil),  // 6: network.zktoro.EvaluateBlockRequest
+	(*EvaluateBlockResponse)(nil), // 7: network.zktoro.EvaluateBlockResponse
+	(*AgentInfo)(nil),             // 8: network.zktoro.AgentInfo
+	(*TrackingTimestamps)(nil),    // 9: network.zktoro.TrackingTimestamps
+	(*EvaluateAlertRequest)(nil),  // 10: network.zktoro.EvaluateAlertRequest
+	(*EvaluateAlertResponse)(nil), // 11: network.zktoro.EvaluateAlertResponse
 }
 var file_publisher_proto_depIdxs = []int32{
-	2,  // 0: network.zktoro.NotifyRequest.signedAlert:type_name -> network.zktoro.SignedAlert
-	3,  // 1: network.zktoro.NotifyRequest.evalTxRequest:type_name -> network.zktoro.EvaluateTxRequest
-	4,  // 2: network.zktoro.NotifyRequest.evalTxResponse:type_name -> network.zktoro.EvaluateTxResponse
-	5,  // 3: network.zktoro.NotifyRequest.evalBlockRequest:type_name -> network.zktoro.EvaluateBlockRequest
-	6,  // 4: network.zktoro.NotifyRequest.evalBlockResponse:type_name -> network.zktoro.EvaluateBlockResponse
-	7,  // 5: network.zktoro.NotifyRequest.agentInfo:type_name -> network.zktoro.AgentInfo
-	8,  // 6: network.zktoro.NotifyRequest.timestamps:type_name -> network.zktoro.TrackingTimestamps
-	9,  // 7: network.zktoro.NotifyRequest.evalAlertRequest:type_name -> network.zktoro.EvaluateAlertRequest
-	10, // 8: network.zktoro.NotifyRequest.evalAlertResponse:type_name -> network.zktoro.EvaluateAlertResponse
-	0,  // 9: network.zktoro.PublisherNode.Notify:input_type -> network.zktoro.NotifyRequest
-	1,  // 10: network.zktoro.PublisherNode.Notify:output_type -> network.zktoro.NotifyResponse
-	10, // [10:11] is the sub-list for method output_type
-	9,  // [9:10] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	3,  // 0: network.zktoro.NotifyRequest.signedAlert:type_name -> network.zktoro.SignedAlert
+	4,  // 1: network.zktoro.NotifyRequest.evalTxRequest:type_name -> network.zktoro.EvaluateTxRequest
+	5,  // 2: network.zktoro.NotifyRequest.evalTxResponse:type_name -> network.zktoro.EvaluateTxResponse
+	6,  // 3: network.zktoro.NotifyRequest.evalBlockRequest:type_name -> network.zktoro.EvaluateBlockRequest
+	7,  // 4: network.zktoro.NotifyRequest.evalBlockResponse:type_name -> network.zktoro.EvaluateBlockResponse
+	8,  // 5: network.zktoro.NotifyRequest.agentInfo:type_name -> network.zktoro.AgentInfo
+	9,  // 6: network.zktoro.NotifyRequest.timestamps:type_name -> network.zktoro.TrackingTimestamps
+	10, // 7: network.zktoro.NotifyRequest.evalAlertRequest:type_name -> network.zktoro.EvaluateAlertRequest
+	11, // 8: network.zktoro.NotifyRequest.evalAlertResponse:type_name -> network.zktoro.EvaluateAlertResponse
+	1,  // 9: network.zktoro.NotifyRequest.retractions:type_name -> network.zktoro.BlockRetraction
+	0,  // 10: network.zktoro.PublisherNode.Notify:input_type -> network.zktoro.NotifyRequest
+	2,  // 11: network.zktoro.PublisherNode.Notify:output_type -> network.zktoro.NotifyResponse
+	11, // [11:12] is the sub-list for method output_type
+	10, // [10:11] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_publisher_proto_init() }
@@ -294,6 +380,18 @@ func file_publisher_proto_init() {
 			}
 		}
 		file_publisher_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockRetraction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NotifyResponse); i {
 			case 0:
 				return &v.state
@@ -312,7 +410,7 @@ func file_publisher_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_publisher_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -9,11 +9,28 @@ import (
 // ToWebhookAlertBatch transforms an alert batch to a webhook alert batch.
 func ToWebhookAlertBatch(batch *protocol.AlertBatch) *models.AlertBatch {
 	return &models.AlertBatch{
-		Alerts:  ToWebhookAlertList(batch),
-		Metrics: ToWebhookBotMetricsList(batch),
+		Alerts:      ToWebhookAlertList(batch),
+		Metrics:     ToWebhookBotMetricsList(batch),
+		Retractions: ToWebhookBlockRetractionList(batch),
 	}
 }
 
+// ToWebhookBlockRetractionList transforms the retractions of an alert batch.
+func ToWebhookBlockRetractionList(batch *protocol.AlertBatch) models.BlockRetractionList {
+	var retractions models.BlockRetractionList
+	for _, retraction := range batch.Retractions {
+		retractions = append(
+			retractions, &models.BlockRetraction{
+				ChainID:            batch.ChainId,
+				BlockNumber:        retraction.BlockNumber,
+				BlockHash:          retraction.BlockHash,
+				CanonicalBlockHash: retraction.CanonicalBlockHash,
+			},
+		)
+	}
+	return retractions
+}
+
 // ToWebhookBotMetricsList transforms an alert batch to a bot metrics list.
 func ToWebhookBotMetricsList(batch *protocol.AlertBatch) models.BotMetricsList {
 	var metricsList models.BotMetricsList
@@ -145,3 +145,20 @@ func TestBatchToAlertList(t *testing.T) {
 	r.Equal(blockTimestamp, txAlertBlock.Timestamp)
 	r.Equal(transactionHash, txAlert.Source.TransactionHash)
 }
+
+func TestBatchToBlockRetractionList(t *testing.T) {
+	r := require.New(t)
+
+	batch := &protocol.AlertBatch{
+		ChainId: 1234,
+		Retractions: []*protocol.BlockRetraction{
+			{BlockNumber: 1337, BlockHash: "orphaned-hash", CanonicalBlockHash: "canonical-hash"},
+		},
+	}
+	retractions := transform.ToWebhookAlertBatch(batch).Retractions
+	r.Len(retractions, 1)
+	r.Equal(uint64(1234), retractions[0].ChainID)
+	r.Equal(uint64(1337), retractions[0].BlockNumber)
+	r.Equal("orphaned-hash", retractions[0].BlockHash)
+	r.Equal("canonical-hash", retractions[0].CanonicalBlockHash)
+}
@@ -59,38 +59,45 @@ func (c *client) GetAssignmentList(blockNumber, assignedChainID *big.Int, scanne
 			agentCalls = make([]*multicall.Call, 0)
 			for i := int64(0); i < agentCount.Int64(); i++ {
 				agentCalls = append(
-					agentCalls, dispatchMulti.NewCall(new(agentRefAtOutput), "agentRefAt", scannerID, big.NewInt(i)),
+					agentCalls,
+					dispatchMulti.NewCall(new(agentRefAtOutput), "agentRefAt", scannerID, big.NewInt(i)).AllowFailure(),
 				)
 			}
 
-			_, err := c.multiCaller.CallChunked(opts, 100, agentCalls...)
-			if err != nil {
-				return err
-			}
-
-			return nil
+			return c.batchCall(opts, agentCalls...)
 		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("agentRefAt calls failed: %v", err)
 	}
 
+	// skip the bots which could not be read
+	var readAgentCalls []*multicall.Call
+	for _, agentCall := range agentCalls {
+		if agentCall.Failed {
+			c.logFailedCall(agentCall)
+			continue
+		}
+		readAgentCalls = append(readAgentCalls, agentCall)
+	}
+	agentCalls = readAgentCalls
+
 	// 3rd eth_call: for each assigned bot, get the assigned scanner counts
 	var numScannersCalls []*multicall.Call
 	err = withBackoff(
 		c.ctx, func(ctx context.Context) error {
 			numScannersCalls = make([]*multicall.Call, 0)
-			for i := 0; i < int(agentCount.Int64()); i++ {
+			for _, agentCall := range agentCalls {
 				numScannersCall := dispatchMulti.NewCall(
-					new(numOutput), "numScannersFor", agentCalls[i].Outputs.(*agentRefAtOutput).AgentId,
-				)
+					new(numOutput), "numScannersFor", agentCall.Outputs.(*agentRefAtOutput).AgentId,
+				).AllowFailure()
 				numScannersCalls = append(numScannersCalls, numScannersCall)
 			}
-			_, err := c.multiCaller.Call(opts, numScannersCalls...)
-			if err != nil {
+			if err := c.batchCall(opts, numScannersCalls...); err != nil {
 				return err
 			}
-			return nil
+			// the assignment indexes depend on the scanner counts so all of them are needed
+			return checkFailedCalls(numScannersCalls)
 		},
 	)
 	if err != nil {
@@ -108,18 +115,17 @@ func (c *client) GetAssignmentList(blockNumber, assignedChainID *big.Int, scanne
 				for i := int64(0); i < scannerCount; i++ {
 					scannerCall := dispatchMulti.NewCall(
 						new(scannerRefAtOutput), "scannerRefAt", agent.AgentId, big.NewInt(i),
-					)
+					).AllowFailure()
 					scannerCalls = append(scannerCalls, scannerCall)
 				}
 			}
 
-			// the amount of scanners can scale up unexpectedly sometimes so this
+			// the amount of scanners can scale up unexpectedly sometimes so the
 			// chunking is a protection against that
-			_, err := c.multiCaller.CallChunked(opts, 100, scannerCalls...)
-			if err != nil {
+			if err := c.batchCall(opts, scannerCalls...); err != nil {
 				return err
 			}
-			return nil
+			return checkFailedCalls(scannerCalls)
 		},
 	)
 	if err != nil {
@@ -135,7 +141,7 @@ func (c *client) GetAssignmentList(blockNumber, assignedChainID *big.Int, scanne
 		assignments     []*Assignment
 		scannersChecked int
 	)
-	for i := 0; i < int(agentCount.Int64()); i++ {
+	for i := range agentCalls {
 		agent := agentCalls[i].Outputs.(*agentRefAtOutput)
 		scannerCount := int(numScannersCalls[i].Outputs.(*numOutput).Num.Int64())
 
@@ -10,7 +10,9 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 	"zktoro/go-multicall"
+	"zktoro/zktoro-core-go/contracts/generated/contract_agent_registry_0_1_6"
 	"zktoro/zktoro-core-go/contracts/generated/contract_dispatch_0_1_5"
 	"zktoro/zktoro-core-go/contracts/merged/contract_rewards_distributor"
 
@@ -26,9 +28,11 @@ import (
 	"zktoro/zktoro-core-go/utils/ethutils"
 
 	"zktoro/zktoro-core-go/domain/registry"
+	"zktoro/zktoro-core-go/security"
 	"zktoro/zktoro-core-go/security/eip712"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
@@ -180,6 +184,8 @@ type Contracts struct {
 	AgentRegFil *contract_agent_registry.AgentRegistryFilterer
 	AgentRegTx  *contract_agent_registry.AgentRegistryTransactor
 
+	AgentRegMulti *multicall.Contract
+
 	ScannerReg    *contract_scanner_registry.ScannerRegistryCaller
 	ScannerRegFil *contract_scanner_registry.ScannerRegistryFilterer
 
@@ -192,11 +198,13 @@ type Contracts struct {
 
 	ZktoroStaking    *contract_zktoro_staking.ZktoroStakingCaller
 	ZktoroStakingFil *contract_zktoro_staking.ZktoroStakingFilterer
+	ZktoroStakingTx  *contract_zktoro_staking.ZktoroStakingTransactor
 
 	// delegated staking contracts
 
 	ScannerPoolReg    *contract_scanner_pool_registry.ScannerPoolRegistryCaller
 	ScannerPoolRegFil *contract_scanner_pool_registry.ScannerPoolRegistryFilterer
+	ScannerPoolRegTx  *contract_scanner_pool_registry.ScannerPoolRegistryTransactor
 
 	StakeAllocator    *contract_stake_allocator.StakeAllocatorCaller
 	StakeAllocatorFil *contract_stake_allocator.StakeAllocatorFilterer
@@ -213,9 +221,15 @@ type client struct {
 	multiCaller *multicall.Caller
 	chainID     *big.Int
 
+	// multicall support is checked once, on the first batch of calls
+	multicallMu        sync.Mutex
+	multicallChecked   bool
+	multicallSupported bool
+
 	// call PegLatestBlock to peg the context to the latest block
 	opts       *bind.CallOpts
 	privateKey *ecdsa.PrivateKey
+	signer     security.Signer
 
 	contracts Contracts
 
@@ -237,11 +251,17 @@ type ClientConfig struct {
 	// PrivateKey is used for sending transactions
 	PrivateKey *ecdsa.PrivateKey
 
+	// Signer is used for signing the scanner registrations. The private key is used if it is not set.
+	Signer security.Signer
+
 	// NoRefresh tells if the contracts should not be refreshed and multiplexed to different versions.
 	NoRefresh bool
 
 	// MulticallAddress is the contract address used for the multicalls
 	MulticallAddress string
+
+	// MulticallChunkSize is the max number of calls batched in a single multicall.
+	MulticallChunkSize int
 }
 
 var defaultConfig = ClientConfig{
@@ -322,6 +342,7 @@ func NewClientWithoutENS(ctx context.Context, cfg ClientConfig) (*client, error)
 		chainID: big.NewInt(0).SetUint64(defaultScannerRegistryChainID),
 
 		privateKey: cfg.PrivateKey,
+		signer:     cfg.Signer,
 
 		versionManager: &VersionManager{},
 	}
@@ -349,6 +370,10 @@ func NewClientWithoutENS(ctx context.Context, cfg ClientConfig) (*client, error)
 		return nil, err
 	}
 	cl.versionManager.SetUpdateRule("AgentRegistry", cl.contracts.AgentReg, cl.contracts.AgentReg, cl.contracts.AgentRegFil, cl.contracts.AgentRegTx)
+	cl.contracts.AgentRegMulti, err = multicall.NewContract(contract_agent_registry_0_1_6.AgentRegistryMetaData.ABI, regContracts.AgentRegistry.Hex())
+	if err != nil {
+		return nil, err
+	}
 
 	cl.contracts.ScannerReg, err = contract_scanner_registry.NewScannerRegistryCaller(regContracts.ScannerRegistry, ec)
 	if err != nil {
@@ -368,7 +393,11 @@ func NewClientWithoutENS(ctx context.Context, cfg ClientConfig) (*client, error)
 	if err != nil {
 		return nil, err
 	}
-	cl.versionManager.SetUpdateRule("ScannerPoolRegistry", cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolRegFil)
+	cl.contracts.ScannerPoolRegTx, err = contract_scanner_pool_registry.NewScannerPoolRegistryTransactor(regContracts.ScannerPoolRegistry, ec)
+	if err != nil {
+		return nil, err
+	}
+	cl.versionManager.SetUpdateRule("ScannerPoolRegistry", cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolRegFil, cl.contracts.ScannerPoolRegTx)
 
 	cl.contracts.Dispatch, err = contract_dispatch.NewDispatchCaller(regContracts.Dispatch, ec)
 	if err != nil {
@@ -402,7 +431,11 @@ func NewClientWithoutENS(ctx context.Context, cfg ClientConfig) (*client, error)
 	if err != nil {
 		return nil, err
 	}
-	cl.versionManager.SetUpdateRule("ZktoroStaking", cl.contracts.ZktoroStaking, cl.contracts.ZktoroStaking, cl.contracts.ZktoroStakingFil)
+	cl.contracts.ZktoroStakingTx, err = contract_zktoro_staking.NewZktoroStakingTransactor(regContracts.ZktoroStaking, ec)
+	if err != nil {
+		return nil, err
+	}
+	cl.versionManager.SetUpdateRule("ZktoroStaking", cl.contracts.ZktoroStaking, cl.contracts.ZktoroStaking, cl.contracts.ZktoroStakingFil, cl.contracts.ZktoroStakingTx)
 
 	cl.contracts.StakeAllocator, err = contract_stake_allocator.NewStakeAllocatorCaller(regContracts.StakeAllocator, ec)
 	if err != nil {
@@ -460,6 +493,7 @@ func NewClientWithENSStore(ctx context.Context, cfg ClientConfig, ensStore ens.E
 		chainID: big.NewInt(0).SetUint64(defaultScannerRegistryChainID),
 
 		privateKey: cfg.PrivateKey,
+		signer:     cfg.Signer,
 
 		versionManager: &VersionManager{},
 	}
@@ -487,6 +521,10 @@ func NewClientWithENSStore(ctx context.Context, cfg ClientConfig, ensStore ens.E
 		return nil, err
 	}
 	cl.versionManager.SetUpdateRule("AgentRegistry", cl.contracts.AgentReg, cl.contracts.AgentReg, cl.contracts.AgentRegFil, cl.contracts.AgentRegTx)
+	cl.contracts.AgentRegMulti, err = multicall.NewContract(contract_agent_registry_0_1_6.AgentRegistryMetaData.ABI, regContracts.AgentRegistry.Hex())
+	if err != nil {
+		return nil, err
+	}
 
 	cl.contracts.ScannerReg, err = contract_scanner_registry.NewScannerRegistryCaller(regContracts.ScannerRegistry, ec)
 	if err != nil {
@@ -506,7 +544,11 @@ func NewClientWithENSStore(ctx context.Context, cfg ClientConfig, ensStore ens.E
 	if err != nil {
 		return nil, err
 	}
-	cl.versionManager.SetUpdateRule("ScannerPoolRegistry", cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolRegFil)
+	cl.contracts.ScannerPoolRegTx, err = contract_scanner_pool_registry.NewScannerPoolRegistryTransactor(regContracts.ScannerPoolRegistry, ec)
+	if err != nil {
+		return nil, err
+	}
+	cl.versionManager.SetUpdateRule("ScannerPoolRegistry", cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolReg, cl.contracts.ScannerPoolRegFil, cl.contracts.ScannerPoolRegTx)
 
 	cl.contracts.Dispatch, err = contract_dispatch.NewDispatchCaller(regContracts.Dispatch, ec)
 	if err != nil {
@@ -540,7 +582,11 @@ func NewClientWithENSStore(ctx context.Context, cfg ClientConfig, ensStore ens.E
 	if err != nil {
 		return nil, err
 	}
-	cl.versionManager.SetUpdateRule("ZktoroStaking", cl.contracts.ZktoroStaking, cl.contracts.ZktoroStaking, cl.contracts.ZktoroStakingFil)
+	cl.contracts.ZktoroStakingTx, err = contract_zktoro_staking.NewZktoroStakingTransactor(regContracts.ZktoroStaking, ec)
+	if err != nil {
+		return nil, err
+	}
+	cl.versionManager.SetUpdateRule("ZktoroStaking", cl.contracts.ZktoroStaking, cl.contracts.ZktoroStaking, cl.contracts.ZktoroStakingFil, cl.contracts.ZktoroStakingTx)
 
 	cl.contracts.StakeAllocator, err = contract_stake_allocator.NewStakeAllocatorCaller(regContracts.StakeAllocator, ec)
 	if err != nil {
@@ -815,16 +861,39 @@ func (c *client) ForEachChainAgent(chainID int64, handler func(a *Agent) error)
 		return err
 	}
 
+	idCalls := make([]*multicall.Call, 0, length.Int64())
 	for i := int64(0); i < length.Int64(); i++ {
-		idx := big.NewInt(i)
-		agtID, err := c.contracts.AgentReg.GetAgentByChainAndIndex(opts, cID, idx)
-		if err != nil {
-			return err
+		idCalls = append(
+			idCalls,
+			contracts.AgentRegMulti.NewCall(new(agentIDOutput), "getAgentByChainAndIndex", cID, big.NewInt(i)).AllowFailure(),
+		)
+	}
+	if err := c.batchCall(opts, idCalls...); err != nil {
+		return err
+	}
+
+	var stateCalls []*multicall.Call
+	for _, idCall := range idCalls {
+		if idCall.Failed {
+			c.logFailedCall(idCall)
+			continue
 		}
-		agt, err := contracts.AgentReg.GetAgentState(opts, agtID)
-		if err != nil {
-			return err
+		stateCalls = append(
+			stateCalls,
+			contracts.AgentRegMulti.NewCall(new(agentStateOutput), "getAgentState", idCall.Outputs.(*agentIDOutput).AgentId).AllowFailure(),
+		)
+	}
+	if err := c.batchCall(opts, stateCalls...); err != nil {
+		return err
+	}
+
+	for _, stateCall := range stateCalls {
+		if stateCall.Failed {
+			c.logFailedCall(stateCall)
+			continue
 		}
+		agtID := stateCall.Inputs[0].(*big.Int)
+		agt := stateCall.Outputs.(*agentStateOutput)
 		if err := handler(&Agent{
 			AgentID:  utils.AgentBigIntToHex(agtID),
 			ChainIDs: utils.IntArray(agt.ChainIds),
@@ -1058,12 +1127,22 @@ func (c *client) ForEachAssignedAgent(scannerID string, handler func(a *Agent) e
 	if err != nil {
 		return err
 	}
+	calls := make([]*multicall.Call, 0, length.Int64())
 	for i := int64(0); i < length.Int64(); i++ {
-		idx := big.NewInt(i)
-		agt, err := contracts.Dispatch.AgentRefAt(opts, sID, idx)
-		if err != nil {
-			return err
+		calls = append(
+			calls, contracts.DispatchMulti.NewCall(new(agentRefAtOutput), "agentRefAt", sID, big.NewInt(i)).AllowFailure(),
+		)
+	}
+	if err := c.batchCall(opts, calls...); err != nil {
+		return err
+	}
+
+	for _, call := range calls {
+		if call.Failed {
+			c.logFailedCall(call)
+			continue
 		}
+		agt := call.Outputs.(*agentRefAtOutput)
 		if err := handler(&Agent{
 			AgentID:  utils.AgentBigIntToHex(agt.AgentId),
 			ChainIDs: utils.IntArray(agt.ChainIds),
@@ -1185,7 +1264,11 @@ func (c *client) GetAgent(agentID string) (*Agent, error) {
 }
 
 func (c *client) GenerateScannerRegistrationSignature(reg *eip712.ScannerNodeRegistration) (*ScannerRegistrationInfo, error) {
-	_, sig, err := eip712.SignScannerRegistration(c.privateKey, c.contracts.Addresses.ScannerPoolRegistry, c.chainID, reg)
+	signer := c.signer
+	if signer == nil {
+		signer = security.NewKeySigner(&keystore.Key{PrivateKey: c.privateKey})
+	}
+	_, sig, err := eip712.SignScannerRegistrationWithSigner(signer, c.contracts.Addresses.ScannerPoolRegistry, c.chainID, reg)
 	fmt.Println("ScannerPoolRegistry", c.contracts.Addresses.ScannerPoolRegistry)
 	fmt.Println("ChainID", c.chainID)
 	if err != nil {
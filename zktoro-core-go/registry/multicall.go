@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"zktoro/go-multicall"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultMulticallChunkSize = 100
+
+// batchCall makes the calls through the multicall contract, in chunks. If the multicall
+// contract is not deployed on the chain, it falls back to making the calls one by one.
+func (c *client) batchCall(opts *bind.CallOpts, calls ...*multicall.Call) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	supported, err := c.isMulticallSupported(opts)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return c.callSequentially(opts, calls...)
+	}
+	_, err = c.multiCaller.CallChunked(opts, c.multicallChunkSize(), calls...)
+	return err
+}
+
+func (c *client) multicallChunkSize() int {
+	if c.cfg.MulticallChunkSize > 0 {
+		return c.cfg.MulticallChunkSize
+	}
+	return defaultMulticallChunkSize
+}
+
+// isMulticallSupported checks the multicall contract with an empty call and remembers the result.
+func (c *client) isMulticallSupported(opts *bind.CallOpts) (bool, error) {
+	c.multicallMu.Lock()
+	defer c.multicallMu.Unlock()
+
+	if c.multicallChecked {
+		return c.multicallSupported, nil
+	}
+
+	_, err := c.multiCaller.Call(opts)
+	switch {
+	case err == nil:
+		c.multicallSupported = true
+
+	case errors.Is(err, bind.ErrNoCode):
+		multicallAddress := c.cfg.MulticallAddress
+		if len(multicallAddress) == 0 {
+			multicallAddress = multicall.DefaultAddress
+		}
+		log.WithFields(log.Fields{
+			"name":             c.cfg.Name,
+			"multicallAddress": multicallAddress,
+		}).Warn("no multicall contract found - falling back to sequential calls")
+		c.multicallSupported = false
+
+	default:
+		return false, fmt.Errorf("failed to check the multicall contract: %v", err)
+	}
+
+	c.multicallChecked = true
+	return c.multicallSupported, nil
+}
+
+// callSequentially makes the calls one by one while respecting the allowed failures. Only the reverted
+// calls are allowed to fail and the other errors are returned so the callers can retry.
+func (c *client) callSequentially(opts *bind.CallOpts, calls ...*multicall.Call) error {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = c.ctx
+	}
+	for i, call := range calls {
+		b, err := call.Pack()
+		if err != nil {
+			return fmt.Errorf("failed to pack call inputs at index [%d]: %v", i, err)
+		}
+		to := call.Contract.Address
+		returnData, err := c.ec.CallContract(ctx, ethereum.CallMsg{
+			From: opts.From,
+			To:   &to,
+			Data: b,
+		}, opts.BlockNumber)
+		call.Failed = err != nil
+		if call.Failed && call.CanFail && isExecutionReverted(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("call at index [%d] failed: %v", i, err)
+		}
+		if err := call.Unpack(returnData); err != nil {
+			return fmt.Errorf("failed to unpack call outputs at index [%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// executionRevertedCode is the JSON-RPC error code of the reverted calls.
+const executionRevertedCode = 3
+
+// isExecutionReverted tells if the JSON-RPC error is an execution revert.
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	return rpcErr.ErrorCode() == executionRevertedCode || strings.Contains(rpcErr.Error(), "revert")
+}
+
+// checkFailedCalls returns an error for the first failed call.
+func checkFailedCalls(calls []*multicall.Call) error {
+	for i, call := range calls {
+		if call.Failed {
+			return fmt.Errorf("'%s' call at index [%d] failed with inputs %v", call.Method, i, call.Inputs)
+		}
+	}
+	return nil
+}
+
+// logFailedCall logs a failed call which is skipped.
+func (c *client) logFailedCall(call *multicall.Call) {
+	log.WithFields(log.Fields{
+		"name":   c.cfg.Name,
+		"method": call.Method,
+		"inputs": fmt.Sprint(call.Inputs),
+	}).Warn("registry call failed - skipping")
+}
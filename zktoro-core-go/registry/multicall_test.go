@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"zktoro/go-multicall"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+const testNumABI = `[{"name":"num","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"num","type":"uint256"}]}]`
+
+const testNumResult = `"0x000000000000000000000000000000000000000000000000000000000000002a"`
+
+// testCallReplies replies to the eth_call requests, in order, with the given JSON-RPC errors or results.
+func testCallReplies(t *testing.T, replies ...string) *httptest.Server {
+	var i int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reply := replies[i]
+		i++
+		if reply == "" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,` + reply + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testSequentialClient(t *testing.T, url string) *client {
+	ec, err := ethclient.Dial(url)
+	require.NoError(t, err)
+	return &client{ctx: context.Background(), ec: ec}
+}
+
+func testNumCalls(t *testing.T, n int) []*multicall.Call {
+	contract, err := multicall.NewContract(testNumABI, "0x64d5192F03bD98dB1De2AA8B4abAC5419eaC32CE")
+	require.NoError(t, err)
+	var calls []*multicall.Call
+	for i := 0; i < n; i++ {
+		calls = append(calls, contract.NewCall(new(numOutput), "num").AllowFailure())
+	}
+	return calls
+}
+
+func TestCallSequentially_Reverted(t *testing.T) {
+	r := require.New(t)
+
+	srv := testCallReplies(t,
+		`"error":{"code":3,"message":"execution reverted","data":"0x"}`,
+		`"error":{"code":-32000,"message":"execution reverted"}`,
+		`"result":`+testNumResult,
+	)
+	c := testSequentialClient(t, srv.URL)
+
+	calls := testNumCalls(t, 3)
+	r.NoError(c.callSequentially(&bind.CallOpts{}, calls...))
+	r.True(calls[0].Failed)
+	r.True(calls[1].Failed)
+	r.False(calls[2].Failed)
+	r.Equal(big.NewInt(42), calls[2].Outputs.(*numOutput).Num)
+}
+
+func TestCallSequentially_TransportError(t *testing.T) {
+	for _, reply := range []string{
+		`"error":{"code":-32000,"message":"header not found"}`,
+		"", // bad gateway
+	} {
+		r := require.New(t)
+
+		srv := testCallReplies(t, `"result":`+testNumResult, reply)
+		c := testSequentialClient(t, srv.URL)
+
+		calls := testNumCalls(t, 2)
+		r.Error(c.callSequentially(&bind.CallOpts{}, calls...))
+		r.False(calls[0].Failed)
+	}
+}
@@ -91,4 +91,19 @@ type agentRefAtOutput struct {
 	AgentId      *big.Int
 	AgentVersion *big.Int
 	Metadata     string
+	ChainIds     []*big.Int
+	Enabled      bool
+}
+
+type agentIDOutput struct {
+	AgentId *big.Int
+}
+
+type agentStateOutput struct {
+	Registered   bool
+	Owner        common.Address
+	AgentVersion *big.Int
+	Metadata     string
+	ChainIds     []*big.Int
+	Enabled      bool
 }
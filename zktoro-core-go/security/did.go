@@ -0,0 +1,266 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+// Supported DID methods
+const (
+	DIDMethodKey = "key"
+	DIDMethodWeb = "web"
+)
+
+const (
+	didKeyFileVersion = 1
+
+	didContextURI          = "https://www.w3.org/ns/did/v1"
+	ed25519Suite2020URI    = "https://w3id.org/security/suites/ed25519-2020/v1"
+	ed25519VerificationKey = "Ed25519VerificationKey2020"
+)
+
+// Errors
+var (
+	ErrLegacyDIDKey     = errors.New("the DID key is not encrypted")
+	ErrUnknownDIDMethod = errors.New("unknown DID method")
+)
+
+// DIDKey is the Ed25519 key of the node DID.
+type DIDKey struct {
+	DID          string
+	Method       string
+	KeyID        string
+	PrivateKey   ed25519.PrivateKey
+	CreatedAt    time.Time
+	PreviousKeys []*DIDPreviousKey
+}
+
+// DIDPreviousKey is a key which was rotated out.
+type DIDPreviousKey struct {
+	DID       string    `json:"did"`
+	KeyID     string    `json:"keyId"`
+	PublicKey string    `json:"publicKey"`
+	RotatedAt time.Time `json:"rotatedAt"`
+}
+
+// PublicKey returns the public key of the DID key.
+func (k *DIDKey) PublicKey() ed25519.PublicKey {
+	return k.PrivateKey.Public().(ed25519.PublicKey)
+}
+
+// NewDIDKey creates a DID key from the private key. The domain is required for did:web and
+// can contain a port and a path (e.g. example.com:8443/nodes/node1).
+func NewDIDKey(method, domain string, privateKey ed25519.PrivateKey) (*DIDKey, error) {
+	key := &DIDKey{
+		Method:     method,
+		PrivateKey: privateKey,
+		CreatedAt:  time.Now().UTC(),
+	}
+	switch method {
+	case DIDMethodKey:
+		key.DID, key.KeyID = fingerprint.CreateDIDKey(key.PublicKey())
+	case DIDMethodWeb:
+		did, err := DIDWebFromDomain(domain)
+		if err != nil {
+			return nil, err
+		}
+		key.DID = did
+		key.KeyID = fmt.Sprintf("%s#key-1", did)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDIDMethod, method)
+	}
+	return key, nil
+}
+
+// GenerateDIDKey generates a new DID key.
+func GenerateDIDKey(method, domain string) (*DIDKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewDIDKey(method, domain, privateKey)
+}
+
+// DIDWebFromDomain converts a domain and an optional path to a did:web DID.
+func DIDWebFromDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.TrimPrefix(domain, "https://"), "/")
+	if domain == "" {
+		return "", errors.New("did:web requires a domain")
+	}
+	parts := strings.Split(domain, "/")
+	u, err := url.Parse("https://" + parts[0])
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid did:web domain: %s", domain)
+	}
+	// the port colon must be percent-encoded and the path separators become colons
+	parts[0] = strings.ReplaceAll(parts[0], ":", "%3A")
+	return "did:web:" + strings.Join(parts, ":"), nil
+}
+
+// Rotate replaces the key with a new key. A did:web DID stays the same and gets a new
+// verification method while a did:key DID changes with the key.
+func (k *DIDKey) Rotate(privateKey ed25519.PrivateKey) (*DIDKey, error) {
+	rotated := &DIDKey{
+		DID:        k.DID,
+		Method:     k.Method,
+		PrivateKey: privateKey,
+		CreatedAt:  time.Now().UTC(),
+		PreviousKeys: append(append([]*DIDPreviousKey{}, k.PreviousKeys...), &DIDPreviousKey{
+			DID:       k.DID,
+			KeyID:     k.KeyID,
+			PublicKey: hex.EncodeToString(k.PublicKey()),
+			RotatedAt: time.Now().UTC(),
+		}),
+	}
+	switch k.Method {
+	case DIDMethodKey:
+		rotated.DID, rotated.KeyID = fingerprint.CreateDIDKey(rotated.PublicKey())
+	case DIDMethodWeb:
+		rotated.KeyID = fmt.Sprintf("%s#key-%d", k.DID, len(rotated.PreviousKeys)+1)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDIDMethod, k.Method)
+	}
+	return rotated, nil
+}
+
+// DIDDocument is the W3C DID document of the node DID.
+type DIDDocument struct {
+	Context            []string                 `json:"@context"`
+	ID                 string                   `json:"id"`
+	VerificationMethod []*DIDVerificationMethod `json:"verificationMethod"`
+	Authentication     []string                 `json:"authentication"`
+	AssertionMethod    []string                 `json:"assertionMethod"`
+}
+
+// DIDVerificationMethod is a verification method in the DID document.
+type DIDVerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+// Document creates the DID document.
+func (k *DIDKey) Document() *DIDDocument {
+	return &DIDDocument{
+		Context: []string{didContextURI, ed25519Suite2020URI},
+		ID:      k.DID,
+		VerificationMethod: []*DIDVerificationMethod{
+			{
+				ID:                 k.KeyID,
+				Type:               ed25519VerificationKey,
+				Controller:         k.DID,
+				PublicKeyMultibase: fingerprint.KeyFingerprint(fingerprint.ED25519PubKeyMultiCodec, k.PublicKey()),
+			},
+		},
+		Authentication:  []string{k.KeyID},
+		AssertionMethod: []string{k.KeyID},
+	}
+}
+
+type didKeyFile struct {
+	Version      int                 `json:"version"`
+	DID          string              `json:"did"`
+	Method       string              `json:"method"`
+	KeyID        string              `json:"keyId"`
+	PublicKey    string              `json:"publicKey"`
+	Crypto       keystore.CryptoJSON `json:"crypto"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	PreviousKeys []*DIDPreviousKey   `json:"previousKeys,omitempty"`
+}
+
+// EncryptDIDKey encrypts the DID key with the passphrase in the same way as the scanner keystore.
+func EncryptDIDKey(key *DIDKey, passphrase string) ([]byte, error) {
+	cryptoJSON, err := keystore.EncryptDataV3(key.PrivateKey.Seed(), []byte(passphrase), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt the DID key: %v", err)
+	}
+	return json.MarshalIndent(&didKeyFile{
+		Version:      didKeyFileVersion,
+		DID:          key.DID,
+		Method:       key.Method,
+		KeyID:        key.KeyID,
+		PublicKey:    hex.EncodeToString(key.PublicKey()),
+		Crypto:       cryptoJSON,
+		CreatedAt:    key.CreatedAt,
+		PreviousKeys: key.PreviousKeys,
+	}, "", "  ")
+}
+
+// DecryptDIDKey decrypts the DID key with the passphrase.
+func DecryptDIDKey(b []byte, passphrase string) (*DIDKey, error) {
+	if len(b) == ed25519.PrivateKeySize {
+		return nil, ErrLegacyDIDKey
+	}
+	var keyFile didKeyFile
+	if err := json.Unmarshal(b, &keyFile); err != nil {
+		return nil, fmt.Errorf("failed to decode the DID key file: %v", err)
+	}
+	if keyFile.Version != didKeyFileVersion {
+		return nil, fmt.Errorf("unsupported DID key file version: %d", keyFile.Version)
+	}
+	seed, err := keystore.DecryptDataV3(keyFile.Crypto, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the DID key: %v", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid DID key seed length: %d", len(seed))
+	}
+	key := &DIDKey{
+		DID:          keyFile.DID,
+		Method:       keyFile.Method,
+		KeyID:        keyFile.KeyID,
+		PrivateKey:   ed25519.NewKeyFromSeed(seed),
+		CreatedAt:    keyFile.CreatedAt,
+		PreviousKeys: keyFile.PreviousKeys,
+	}
+	if hex.EncodeToString(key.PublicKey()) != keyFile.PublicKey {
+		return nil, errors.New("decrypted DID key does not match the public key")
+	}
+	return key, nil
+}
+
+// LoadDIDKeyWithPassphrase loads and decrypts the DID key file.
+func LoadDIDKeyWithPassphrase(keyPath, passphrase string) (*DIDKey, error) {
+	b, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptDIDKey(b, passphrase)
+}
+
+// LoadLegacyDIDKey loads the unencrypted raw Ed25519 private key.
+func LoadLegacyDIDKey(keyPath string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != ed25519.PrivateKeySize {
+		return nil, errors.New("not a legacy DID key")
+	}
+	return b, nil
+}
+
+// StoreDIDKey encrypts and writes the DID key file.
+func StoreDIDKey(keyPath string, key *DIDKey, passphrase string) error {
+	b, err := EncryptDIDKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+	tmpPath := keyPath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, keyPath)
+}
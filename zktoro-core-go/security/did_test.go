@@ -0,0 +1,99 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDIDPassphrase = "Passphrase123"
+
+func TestDIDWebFromDomain(t *testing.T) {
+	r := require.New(t)
+
+	did, err := DIDWebFromDomain("example.com")
+	r.NoError(err)
+	r.Equal("did:web:example.com", did)
+
+	did, err = DIDWebFromDomain("https://example.com:8443/nodes/node1/")
+	r.NoError(err)
+	r.Equal("did:web:example.com%3A8443:nodes:node1", did)
+
+	_, err = DIDWebFromDomain("")
+	r.Error(err)
+}
+
+func TestDIDKey_StoreAndLoad(t *testing.T) {
+	r := require.New(t)
+
+	keyPath := path.Join(t.TempDir(), ".did")
+	key, err := GenerateDIDKey(DIDMethodKey, "")
+	r.NoError(err)
+	r.True(strings.HasPrefix(key.DID, "did:key:z"))
+	r.True(strings.HasPrefix(key.KeyID, key.DID+"#"))
+
+	r.NoError(StoreDIDKey(keyPath, key, testDIDPassphrase))
+	info, err := os.Stat(keyPath)
+	r.NoError(err)
+	r.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadDIDKeyWithPassphrase(keyPath, testDIDPassphrase)
+	r.NoError(err)
+	r.Equal(key.DID, loaded.DID)
+	r.Equal(key.KeyID, loaded.KeyID)
+	r.Equal(key.PrivateKey, loaded.PrivateKey)
+
+	_, err = LoadDIDKeyWithPassphrase(keyPath, "wrong")
+	r.Error(err)
+}
+
+func TestDIDKey_Legacy(t *testing.T) {
+	r := require.New(t)
+
+	keyPath := path.Join(t.TempDir(), ".did")
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	r.NoError(os.WriteFile(keyPath, privateKey, 0600))
+
+	_, err = LoadDIDKeyWithPassphrase(keyPath, testDIDPassphrase)
+	r.ErrorIs(err, ErrLegacyDIDKey)
+
+	legacyKey, err := LoadLegacyDIDKey(keyPath)
+	r.NoError(err)
+	r.Equal(privateKey, legacyKey)
+}
+
+func TestDIDKey_Rotate(t *testing.T) {
+	r := require.New(t)
+
+	webKey, err := GenerateDIDKey(DIDMethodWeb, "example.com")
+	r.NoError(err)
+	r.Equal("did:web:example.com#key-1", webKey.KeyID)
+
+	_, newPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	rotated, err := webKey.Rotate(newPrivateKey)
+	r.NoError(err)
+	r.Equal(webKey.DID, rotated.DID)
+	r.Equal("did:web:example.com#key-2", rotated.KeyID)
+	r.Len(rotated.PreviousKeys, 1)
+	r.Equal(webKey.KeyID, rotated.PreviousKeys[0].KeyID)
+
+	doc := rotated.Document()
+	r.Equal(rotated.DID, doc.ID)
+	r.Len(doc.VerificationMethod, 1)
+	r.Equal(rotated.KeyID, doc.VerificationMethod[0].ID)
+	r.Equal([]string{rotated.KeyID}, doc.AssertionMethod)
+
+	keyKey, err := GenerateDIDKey(DIDMethodKey, "")
+	r.NoError(err)
+	rotated, err = keyKey.Rotate(newPrivateKey)
+	r.NoError(err)
+	r.NotEqual(keyKey.DID, rotated.DID)
+	r.Equal(keyKey.DID, rotated.PreviousKeys[0].DID)
+}
@@ -21,13 +21,54 @@ func MessageHash(data []byte) []byte {
 
 type ScannerNodeRegistration contract_scanner_pool_registry.ScannerPoolRegistryCoreScannerNodeRegistration
 
+// TypedDataSigner signs the EIP-712 typed data.
+type TypedDataSigner interface {
+	SignTypedData(data *apitypes.TypedData) ([]byte, error)
+}
+
 // SignScannerRegistration encodes registration data using EIP712
 // typed structured data encoding rules and signs.
 func SignScannerRegistration(
 	scannerKey *ecdsa.PrivateKey, verifyingContract common.Address, verifyingContractChainID *big.Int,
 	reg *ScannerNodeRegistration,
 ) ([]byte, []byte, error) {
-	data := &apitypes.TypedData{
+	data := ScannerRegistrationTypedData(verifyingContract, verifyingContractChainID, reg)
+	encoded, hash, err := hashTypedData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := crypto.Sign(hash, scannerKey)
+	if err != nil {
+		return encoded, nil, err
+	}
+
+	return encoded, sig, nil
+}
+
+// SignScannerRegistrationWithSigner signs the registration data like SignScannerRegistration
+// by using a signer which may keep the key outside of the node.
+func SignScannerRegistrationWithSigner(
+	signer TypedDataSigner, verifyingContract common.Address, verifyingContractChainID *big.Int,
+	reg *ScannerNodeRegistration,
+) ([]byte, []byte, error) {
+	data := ScannerRegistrationTypedData(verifyingContract, verifyingContractChainID, reg)
+	encoded, _, err := hashTypedData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := signer.SignTypedData(data)
+	if err != nil {
+		return encoded, nil, err
+	}
+
+	return encoded, sig, nil
+}
+
+// ScannerRegistrationTypedData makes the EIP712 typed data of the registration.
+func ScannerRegistrationTypedData(
+	verifyingContract common.Address, verifyingContractChainID *big.Int, reg *ScannerNodeRegistration,
+) *apitypes.TypedData {
+	return &apitypes.TypedData{
 		Types: apitypes.Types{
 			"EIP712Domain": {
 				{
@@ -85,17 +126,6 @@ func SignScannerRegistration(
 			"timestamp":     (*hexutil.Big)(reg.Timestamp).String(),
 		},
 	}
-
-	encoded, hash, err := hashTypedData(data)
-	if err != nil {
-		return nil, nil, err
-	}
-	sig, err := crypto.Sign(hash, scannerKey)
-	if err != nil {
-		return encoded, nil, err
-	}
-
-	return encoded, sig, nil
 }
 
 func hashTypedData(data *apitypes.TypedData) ([]byte, []byte, error) {
@@ -52,9 +52,18 @@ func (e ethSigningMethod) Verify(signingString, signature string, key interface{
 }
 
 func (e ethSigningMethod) Sign(signingString string, key interface{}) (string, error) {
-	pk := key.(*ecdsa.PrivateKey)
-	hash := crypto.Keccak256([]byte(signingString))
-	sig, err := crypto.Sign(hash, pk)
+	var (
+		sig []byte
+		err error
+	)
+	switch key := key.(type) {
+	case Signer:
+		sig, err = key.SignData([]byte(signingString))
+	case *ecdsa.PrivateKey:
+		sig, err = crypto.Sign(crypto.Keccak256([]byte(signingString)), key)
+	default:
+		return "", jwt.ErrInvalidKeyType
+	}
 	if err != nil {
 		return "", err
 	}
@@ -107,11 +116,16 @@ func VerifyScannerJWT(tokenString string) (*ScannerToken, error) {
 }
 
 func CreateScannerJWT(key *keystore.Key, claims map[string]interface{}) (string, error) {
+	return CreateScannerJWTWithSigner(NewKeySigner(key), claims)
+}
+
+// CreateScannerJWTWithSigner creates a scanner JWT by using the signer.
+func CreateScannerJWTWithSigner(signer Signer, claims map[string]interface{}) (string, error) {
 	u := uuid.Must(uuid.NewUUID())
 	now := time.Now().UTC()
 	mapClaims := map[string]interface{}{
 		"jti": u.String(),
-		"sub": key.Address.Hex(),
+		"sub": signer.Address().Hex(),
 		"iat": now.Unix(),
 		"nbf": now.Add(-30 * time.Second).Unix(),
 		"exp": now.Add(30 * time.Second).Unix(),
@@ -120,7 +134,7 @@ func CreateScannerJWT(key *keystore.Key, claims map[string]interface{}) (string,
 		mapClaims[k] = v
 	}
 	token := jwt.NewWithClaims(&ethSigningMethod{}, jwt.MapClaims(mapClaims))
-	str, err := token.SignedString(key.PrivateKey)
+	str, err := token.SignedString(signer)
 	if err != nil {
 		return "", err
 	}
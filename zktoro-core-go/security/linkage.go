@@ -0,0 +1,210 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+const (
+	// DIDLinkageType is the type of the scanner-DID linkage credential.
+	DIDLinkageType = "ScannerDIDLinkage"
+	// DIDLinkageClaim is the scanner JWT claim which contains the encoded linkage.
+	DIDLinkageClaim = "didLinkage"
+)
+
+// Errors
+var (
+	ErrDIDLinkageMismatch = errors.New("linkage does not belong to the scanner")
+)
+
+// DIDLinkage binds the scanner key and the node DID key to each other. The scanner key
+// attests the DID and the DID key attests the scanner address by signing the same payload.
+type DIDLinkage struct {
+	Type             string `json:"type"`
+	Scanner          string `json:"scanner"`
+	DID              string `json:"did"`
+	KeyID            string `json:"keyId"`
+	PublicKey        string `json:"publicKey"`
+	IssuedAt         string `json:"issuedAt"`
+	ScannerSignature string `json:"scannerSignature"`
+	DIDSignature     string `json:"didSignature"`
+}
+
+func (l *DIDLinkage) signingPayload() []byte {
+	return []byte(strings.Join([]string{l.Type, l.Scanner, l.DID, l.KeyID, l.PublicKey, l.IssuedAt}, "\n"))
+}
+
+// CreateDIDLinkage creates a linkage signed by both of the keys.
+func CreateDIDLinkage(scannerKey *keystore.Key, didKey *DIDKey) (*DIDLinkage, error) {
+	linkage := &DIDLinkage{
+		Type:      DIDLinkageType,
+		Scanner:   scannerKey.Address.Hex(),
+		DID:       didKey.DID,
+		KeyID:     didKey.KeyID,
+		PublicKey: hex.EncodeToString(didKey.PublicKey()),
+		IssuedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+	payload := linkage.signingPayload()
+	scannerSig, err := SignBytes(scannerKey, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign linkage with scanner key: %v", err)
+	}
+	linkage.ScannerSignature = scannerSig.Signature
+	linkage.DIDSignature = base64.RawURLEncoding.EncodeToString(ed25519.Sign(didKey.PrivateKey, payload))
+	return linkage, nil
+}
+
+// VerifyDIDLinkage verifies both of the signatures and the consistency of the DID key.
+func VerifyDIDLinkage(linkage *DIDLinkage) error {
+	if linkage.Type != DIDLinkageType {
+		return fmt.Errorf("invalid linkage type: %s", linkage.Type)
+	}
+	if !common.IsHexAddress(linkage.Scanner) {
+		return fmt.Errorf("invalid scanner address: %s", linkage.Scanner)
+	}
+	if !strings.HasPrefix(linkage.KeyID, linkage.DID+"#") {
+		return fmt.Errorf("verification method %s does not belong to %s", linkage.KeyID, linkage.DID)
+	}
+	if _, err := time.Parse(time.RFC3339, linkage.IssuedAt); err != nil {
+		return fmt.Errorf("invalid linkage issue time: %v", err)
+	}
+	publicKey, err := hex.DecodeString(linkage.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid DID public key")
+	}
+	// a did:key can only have the key which it encodes
+	if strings.HasPrefix(linkage.DID, "did:key:") {
+		didPublicKey, err := fingerprint.PubKeyFromDIDKey(linkage.DID)
+		if err != nil {
+			return fmt.Errorf("invalid did:key: %v", err)
+		}
+		if !ed25519.PublicKey(publicKey).Equal(ed25519.PublicKey(didPublicKey)) {
+			return errors.New("public key does not match the did:key")
+		}
+	}
+
+	payload := linkage.signingPayload()
+	if err := VerifySignature(payload, common.HexToAddress(linkage.Scanner).Hex(), linkage.ScannerSignature); err != nil {
+		return fmt.Errorf("invalid scanner signature: %w", err)
+	}
+	didSig, err := base64.RawURLEncoding.DecodeString(linkage.DIDSignature)
+	if err != nil {
+		return fmt.Errorf("invalid DID signature encoding: %v", err)
+	}
+	if !ed25519.Verify(publicKey, payload, didSig) {
+		return fmt.Errorf("invalid DID signature: %w", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// VerifyDIDLinkageForScanner verifies the linkage and makes sure that it belongs to the scanner.
+func VerifyDIDLinkageForScanner(linkage *DIDLinkage, scannerAddress string) error {
+	if !strings.EqualFold(linkage.Scanner, scannerAddress) {
+		return ErrDIDLinkageMismatch
+	}
+	return VerifyDIDLinkage(linkage)
+}
+
+// EncodeDIDLinkage encodes the linkage to be embedded into tokens and batches.
+func EncodeDIDLinkage(linkage *DIDLinkage) (string, error) {
+	b, err := json.Marshal(linkage)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeDIDLinkage decodes an embedded linkage.
+func DecodeDIDLinkage(encoded string) (*DIDLinkage, error) {
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid linkage encoding: %v", err)
+	}
+	var linkage DIDLinkage
+	if err := json.Unmarshal(b, &linkage); err != nil {
+		return nil, fmt.Errorf("invalid linkage: %v", err)
+	}
+	return &linkage, nil
+}
+
+// VerifyScannerJWTDIDLinkage verifies the linkage claim of a verified scanner token. It returns
+// nil if the token does not have a linkage claim.
+func VerifyScannerJWTDIDLinkage(token *ScannerToken) (*DIDLinkage, error) {
+	claims, ok := token.Token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid claims")
+	}
+	claim, ok := claims[DIDLinkageClaim]
+	if !ok {
+		return nil, nil
+	}
+	encoded, ok := claim.(string)
+	if !ok {
+		return nil, errors.New("invalid linkage claim")
+	}
+	linkage, err := DecodeDIDLinkage(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyDIDLinkageForScanner(linkage, token.Scanner); err != nil {
+		return nil, err
+	}
+	return linkage, nil
+}
+
+// WithDIDLinkage adds the linkage claim to the scanner JWT claims if the linkage is available.
+func WithDIDLinkage(claims map[string]interface{}, encodedLinkage string) map[string]interface{} {
+	if encodedLinkage == "" {
+		return claims
+	}
+	if claims == nil {
+		claims = make(map[string]interface{})
+	}
+	claims[DIDLinkageClaim] = encodedLinkage
+	return claims
+}
+
+// NewScannerJWTCreator creates a scanner JWT creator which embeds the linkage claim if it is available.
+func NewScannerJWTCreator(encodedLinkage string) func(signer Signer, claims map[string]interface{}) (string, error) {
+	return func(signer Signer, claims map[string]interface{}) (string, error) {
+		return CreateScannerJWTWithSigner(signer, WithDIDLinkage(claims, encodedLinkage))
+	}
+}
+
+// LoadDIDLinkage reads the linkage file.
+func LoadDIDLinkage(linkagePath string) (*DIDLinkage, error) {
+	b, err := os.ReadFile(linkagePath)
+	if err != nil {
+		return nil, err
+	}
+	var linkage DIDLinkage
+	if err := json.Unmarshal(b, &linkage); err != nil {
+		return nil, fmt.Errorf("failed to decode the linkage file: %v", err)
+	}
+	return &linkage, nil
+}
+
+// StoreDIDLinkage writes the linkage file.
+func StoreDIDLinkage(linkagePath string, linkage *DIDLinkage) error {
+	b, err := json.MarshalIndent(linkage, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := linkagePath + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, linkagePath)
+}
@@ -0,0 +1,115 @@
+package security
+
+import (
+	"path"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScannerKey(r *require.Assertions) *keystore.Key {
+	privateKey, err := crypto.GenerateKey()
+	r.NoError(err)
+	return &keystore.Key{
+		PrivateKey: privateKey,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
+
+func TestDIDLinkage(t *testing.T) {
+	r := require.New(t)
+
+	scannerKey := newTestScannerKey(r)
+	didKey, err := GenerateDIDKey(DIDMethodKey, "")
+	r.NoError(err)
+
+	linkage, err := CreateDIDLinkage(scannerKey, didKey)
+	r.NoError(err)
+	r.NoError(VerifyDIDLinkageForScanner(linkage, scannerKey.Address.Hex()))
+	r.ErrorIs(VerifyDIDLinkageForScanner(linkage, newTestScannerKey(r).Address.Hex()), ErrDIDLinkageMismatch)
+
+	linkagePath := path.Join(t.TempDir(), "did-linkage.json")
+	r.NoError(StoreDIDLinkage(linkagePath, linkage))
+	loaded, err := LoadDIDLinkage(linkagePath)
+	r.NoError(err)
+	r.Equal(linkage, loaded)
+
+	encoded, err := EncodeDIDLinkage(linkage)
+	r.NoError(err)
+	decoded, err := DecodeDIDLinkage(encoded)
+	r.NoError(err)
+	r.Equal(linkage, decoded)
+}
+
+func TestDIDLinkage_Tampered(t *testing.T) {
+	r := require.New(t)
+
+	scannerKey := newTestScannerKey(r)
+	didKey, err := GenerateDIDKey(DIDMethodWeb, "example.com")
+	r.NoError(err)
+	otherDIDKey, err := GenerateDIDKey(DIDMethodKey, "")
+	r.NoError(err)
+
+	tamper := func(f func(l *DIDLinkage)) *DIDLinkage {
+		linkage, err := CreateDIDLinkage(scannerKey, didKey)
+		r.NoError(err)
+		f(linkage)
+		return linkage
+	}
+
+	r.Error(VerifyDIDLinkage(tamper(func(l *DIDLinkage) {
+		l.Scanner = newTestScannerKey(r).Address.Hex()
+	})))
+	r.Error(VerifyDIDLinkage(tamper(func(l *DIDLinkage) {
+		l.DID = "did:web:evil.com"
+		l.KeyID = "did:web:evil.com#key-1"
+	})))
+	r.Error(VerifyDIDLinkage(tamper(func(l *DIDLinkage) {
+		l.KeyID = "did:web:evil.com#key-1"
+	})))
+	r.Error(VerifyDIDLinkage(tamper(func(l *DIDLinkage) {
+		// a did:key which does not encode the public key
+		l.DID = otherDIDKey.DID
+		l.KeyID = otherDIDKey.KeyID
+	})))
+}
+
+func TestVerifyScannerJWTDIDLinkage(t *testing.T) {
+	r := require.New(t)
+
+	scannerKey := newTestScannerKey(r)
+	didKey, err := GenerateDIDKey(DIDMethodKey, "")
+	r.NoError(err)
+	linkage, err := CreateDIDLinkage(scannerKey, didKey)
+	r.NoError(err)
+	encoded, err := EncodeDIDLinkage(linkage)
+	r.NoError(err)
+
+	token, err := CreateScannerJWT(scannerKey, WithDIDLinkage(map[string]interface{}{"batch": "ref"}, encoded))
+	r.NoError(err)
+	scannerToken, err := VerifyScannerJWT(token)
+	r.NoError(err)
+	verified, err := VerifyScannerJWTDIDLinkage(scannerToken)
+	r.NoError(err)
+	r.Equal(didKey.DID, verified.DID)
+
+	// another scanner cannot use the linkage
+	otherKey := newTestScannerKey(r)
+	token, err = CreateScannerJWT(otherKey, WithDIDLinkage(nil, encoded))
+	r.NoError(err)
+	scannerToken, err = VerifyScannerJWT(token)
+	r.NoError(err)
+	_, err = VerifyScannerJWTDIDLinkage(scannerToken)
+	r.ErrorIs(err, ErrDIDLinkageMismatch)
+
+	// no claim
+	token, err = CreateScannerJWT(scannerKey, nil)
+	r.NoError(err)
+	scannerToken, err = VerifyScannerJWT(token)
+	r.NoError(err)
+	verified, err = VerifyScannerJWTDIDLinkage(scannerToken)
+	r.NoError(err)
+	r.Nil(verified)
+}
@@ -0,0 +1,215 @@
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const defaultRemoteSignerTimeout = 10 * time.Second
+
+// RemoteSignerConfig configures a remote signer.
+type RemoteSignerConfig struct {
+	URL string
+	// Address is the scanner address. It is the first account of the signer if it is empty.
+	Address string
+	Headers map[string]string
+	Timeout time.Duration
+}
+
+// remoteSigner uses a Web3Signer compatible signing service. The data is signed with the eth1 sign
+// endpoint which signs the keccak256 hash of the data without a prefix. The typed data is signed
+// with the eth_signTypedData_v4 JSON-RPC method.
+type remoteSigner struct {
+	cfg        RemoteSignerConfig
+	address    common.Address
+	httpClient *http.Client
+	requestID  uint64
+}
+
+// NewRemoteSigner creates a remote signer and finds the scanner address if it is not configured.
+func NewRemoteSigner(cfg RemoteSignerConfig) (Signer, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("remote signer url is empty")
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultRemoteSignerTimeout
+	}
+	s := &remoteSigner{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+	}
+
+	if cfg.Address != "" {
+		if !common.IsHexAddress(cfg.Address) {
+			return nil, fmt.Errorf("invalid remote signer address: %s", cfg.Address)
+		}
+		s.address = common.HexToAddress(cfg.Address)
+		return s, nil
+	}
+
+	var accounts []common.Address
+	if err := s.call("eth_accounts", nil, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to get the remote signer accounts: %v", err)
+	}
+	if len(accounts) == 0 {
+		return nil, errors.New("remote signer has no accounts")
+	}
+	s.address = accounts[0]
+	return s, nil
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) SignData(data []byte) ([]byte, error) {
+	reqBody, err := json.Marshal(map[string]string{
+		"data": hexutil.Encode(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/api/v1/eth1/sign/%s", s.cfg.URL, s.address.Hex())
+	respBody, err := s.post(url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	// the signature can be plain text or a json string
+	sig, err := decodeRemoteSignature(strings.Trim(strings.TrimSpace(string(respBody)), `"`))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifySigner(crypto.Keccak256(data), sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func (s *remoteSigner) SignTypedData(data *apitypes.TypedData) ([]byte, error) {
+	var sigHex string
+	if err := s.call("eth_signTypedData_v4", []interface{}{s.address.Hex(), data}, &sigHex); err != nil {
+		return nil, err
+	}
+	sig, err := decodeRemoteSignature(sigHex)
+	if err != nil {
+		return nil, err
+	}
+	hash, _, err := apitypes.TypedDataAndHash(*data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifySigner(hash, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// verifySigner makes sure that the hash is signed with the scanner key and not with another key
+// of the signing service.
+func (s *remoteSigner) verifySigner(hash, sig []byte) error {
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover the remote signer address: %v", err)
+	}
+	if signerAddr := crypto.PubkeyToAddress(*pubKey); signerAddr != s.address {
+		return fmt.Errorf("remote signature is from %s instead of %s", signerAddr.Hex(), s.address.Hex())
+	}
+	return nil
+}
+
+type jsonRpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *remoteSigner) call(method string, params []interface{}, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	reqBody, err := json.Marshal(&jsonRpcRequest{
+		JsonRpc: "2.0",
+		ID:      atomic.AddUint64(&s.requestID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	respBody, err := s.post(s.cfg.URL, reqBody)
+	if err != nil {
+		return err
+	}
+	var resp jsonRpcResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to decode the %s response: %v", method, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s failed with code %d: %s", method, resp.Error.Code, resp.Error.Message)
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+func (s *remoteSigner) post(url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the remote signer response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer responded with '%d': %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// decodeRemoteSignature decodes the signature and converts V from 27/28 to 0/1.
+func decodeRemoteSignature(sigHex string) ([]byte, error) {
+	sig, err := hexutil.Decode(sigHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature: %v", err)
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("invalid remote signature length: %d", len(sig))
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] != 0 && sig[64] != 1 {
+		return nil, errors.New("invalid remote signature (V is not 0 or 1)")
+	}
+	return sig, nil
+}
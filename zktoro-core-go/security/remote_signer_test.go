@@ -0,0 +1,150 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRemoteSigner starts a stand-in for a Web3Signer compatible signing service.
+func newTestRemoteSigner(t *testing.T, key *keystore.Key) *httptest.Server {
+	localSigner := NewKeySigner(key)
+	// the signatures are returned with V as 27 or 28
+	encodeSig := func(sig []byte) string {
+		sig[64] += 27
+		return hexutil.Encode(sig)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+
+		if r.URL.Path == fmt.Sprintf("/api/v1/eth1/sign/%s", key.Address.Hex()) {
+			var req struct {
+				Data string `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(body, &req))
+			data, err := hexutil.Decode(req.Data)
+			require.NoError(t, err)
+			sig, err := localSigner.SignData(data)
+			require.NoError(t, err)
+			fmt.Fprint(w, encodeSig(sig))
+			return
+		}
+
+		var req jsonRpcRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		var result interface{}
+		switch req.Method {
+		case "eth_accounts":
+			result = []string{key.Address.Hex()}
+		case "eth_signTypedData_v4":
+			b, _ := json.Marshal(req.Params[1])
+			var typedData apitypes.TypedData
+			require.NoError(t, json.Unmarshal(b, &typedData))
+			sig, err := localSigner.SignTypedData(&typedData)
+			require.NoError(t, err)
+			result = encodeSig(sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRemoteSigner(t *testing.T) {
+	r := require.New(t)
+
+	privateKey, err := crypto.GenerateKey()
+	r.NoError(err)
+	key := &keystore.Key{PrivateKey: privateKey, Address: crypto.PubkeyToAddress(privateKey.PublicKey)}
+	server := newTestRemoteSigner(t, key)
+
+	signer, err := NewRemoteSigner(RemoteSignerConfig{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer test-token"},
+	})
+	r.NoError(err)
+	r.Equal(key.Address, signer.Address())
+
+	// the signatures are the same as the local signatures
+	signature, err := SignBytesWithSigner(signer, []byte("test"))
+	r.NoError(err)
+	localSignature, err := SignBytes(key, []byte("test"))
+	r.NoError(err)
+	r.Equal(localSignature, signature)
+	r.NoError(VerifySignature([]byte("test"), key.Address.Hex(), signature.Signature))
+
+	token, err := CreateScannerJWTWithSigner(signer, nil)
+	r.NoError(err)
+	scannerToken, err := VerifyScannerJWT(token)
+	r.NoError(err)
+	r.Equal(key.Address.Hex(), scannerToken.Scanner)
+
+	typedData := &apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Test":         {{Name: "value", Type: "string"}},
+		},
+		Domain:      apitypes.TypedDataDomain{Name: "test"},
+		PrimaryType: "Test",
+		Message:     apitypes.TypedDataMessage{"value": "test"},
+	}
+	sig, err := signer.SignTypedData(typedData)
+	r.NoError(err)
+	localSig, err := NewKeySigner(key).SignTypedData(typedData)
+	r.NoError(err)
+	r.Equal(localSig, sig)
+
+	// unauthorized requests fail
+	_, err = NewRemoteSigner(RemoteSignerConfig{URL: server.URL})
+	r.Error(err)
+}
+
+func TestRemoteSigner_WrongKey(t *testing.T) {
+	r := require.New(t)
+
+	privateKey, err := crypto.GenerateKey()
+	r.NoError(err)
+	key := &keystore.Key{PrivateKey: privateKey, Address: crypto.PubkeyToAddress(privateKey.PublicKey)}
+	otherKey, err := crypto.GenerateKey()
+	r.NoError(err)
+
+	// the service signs with another key than the one at the requested address
+	server := newTestRemoteSigner(t, &keystore.Key{PrivateKey: otherKey, Address: key.Address})
+	signer, err := NewRemoteSigner(RemoteSignerConfig{
+		URL:     server.URL,
+		Address: key.Address.Hex(),
+		Headers: map[string]string{"Authorization": "Bearer test-token"},
+	})
+	r.NoError(err)
+
+	_, err = signer.SignData([]byte("test"))
+	r.Error(err)
+
+	_, err = signer.SignTypedData(&apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Test":         {{Name: "value", Type: "string"}},
+		},
+		Domain:      apitypes.TypedDataDomain{Name: "test"},
+		PrimaryType: "Test",
+		Message:     apitypes.TypedDataMessage{"value": "test"},
+	})
+	r.Error(err)
+}
@@ -0,0 +1,45 @@
+package security
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer signs with the scanner key. The key can be in the local keystore or in an external
+// signing service.
+type Signer interface {
+	// Address returns the address of the scanner key.
+	Address() common.Address
+	// SignData signs the keccak256 hash of the data. The signature is in the [R || S || V] format
+	// where V is 0 or 1.
+	SignData(data []byte) ([]byte, error)
+	// SignTypedData signs the EIP-712 typed data in the same format.
+	SignTypedData(data *apitypes.TypedData) ([]byte, error)
+}
+
+type keySigner struct {
+	key *keystore.Key
+}
+
+// NewKeySigner creates a signer which uses the decrypted keystore key.
+func NewKeySigner(key *keystore.Key) Signer {
+	return &keySigner{key: key}
+}
+
+func (s *keySigner) Address() common.Address {
+	return s.key.Address
+}
+
+func (s *keySigner) SignData(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), s.key.PrivateKey)
+}
+
+func (s *keySigner) SignTypedData(data *apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(*data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash, s.key.PrivateKey)
+}
@@ -86,8 +86,13 @@ func alertHash(alert *protocol.Alert) common.Hash {
 
 // SignAlert signs the alert using the alertID and deterministicly formatted Metadata
 func SignAlert(key *keystore.Key, alert *protocol.Alert) (*protocol.SignedAlert, error) {
+	return SignAlertWithSigner(NewKeySigner(key), alert)
+}
+
+// SignAlertWithSigner signs the alert like SignAlert by using the signer.
+func SignAlertWithSigner(signer Signer, alert *protocol.Alert) (*protocol.SignedAlert, error) {
 	hash := alertHash(alert)
-	signature, err := SignBytes(key, hash.Bytes())
+	signature, err := SignBytesWithSigner(signer, hash.Bytes())
 	if err != nil {
 		return nil, err
 	}
@@ -107,9 +112,12 @@ func VerifyAlertSignature(sa *protocol.SignedAlert) error {
 }
 
 func SignBytes(key *keystore.Key, b []byte) (*protocol.Signature, error) {
-	hash := crypto.Keccak256(b)
-	sig, err := crypto.Sign(hash, key.PrivateKey)
+	return SignBytesWithSigner(NewKeySigner(key), b)
+}
 
+// SignBytesWithSigner signs the keccak256 hash of the bytes by using the signer.
+func SignBytesWithSigner(signer Signer, b []byte) (*protocol.Signature, error) {
+	sig, err := signer.SignData(b)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +125,7 @@ func SignBytes(key *keystore.Key, b []byte) (*protocol.Signature, error) {
 	return &protocol.Signature{
 		Signature: fmt.Sprintf("0x%s", hex.EncodeToString(sig)),
 		Algorithm: "ECDSA",
-		Signer:    key.Address.Hex(),
+		Signer:    signer.Address().Hex(),
 	}, nil
 }
 
@@ -152,12 +160,12 @@ func VerifySignature(message []byte, signerAddress string, sigHex string) error
 	return nil
 }
 
-func signPayload(key *keystore.Key, payloadType protocol.SignedPayload_PayloadType, msg proto.Message) (*protocol.SignedPayload, error) {
+func signPayload(signer Signer, payloadType protocol.SignedPayload_PayloadType, msg proto.Message) (*protocol.SignedPayload, error) {
 	encoded, err := encoding.EncodeGzippedProto(msg)
 	if err != nil {
 		return nil, err
 	}
-	signature, err := SignString(key, encoded)
+	signature, err := SignBytesWithSigner(signer, []byte(encoded))
 	if err != nil {
 		return nil, err
 	}
@@ -171,17 +179,27 @@ func signPayload(key *keystore.Key, payloadType protocol.SignedPayload_PayloadTy
 
 // SignBatch will sign an alert batch and return a SignedAlertBatch
 func SignBatch(key *keystore.Key, payload *protocol.AlertBatch) (*protocol.SignedPayload, error) {
-	return signPayload(key, protocol.SignedPayload_BATCH, payload)
+	return SignBatchWithSigner(NewKeySigner(key), payload)
+}
+
+// SignBatchWithSigner will sign an alert batch by using the signer
+func SignBatchWithSigner(signer Signer, payload *protocol.AlertBatch) (*protocol.SignedPayload, error) {
+	return signPayload(signer, protocol.SignedPayload_BATCH, payload)
 }
 
 // SignBatchSummary will sign an alert batch summary
 func SignBatchSummary(key *keystore.Key, payload *protocol.BatchSummary) (*protocol.SignedPayload, error) {
-	return signPayload(key, protocol.SignedPayload_BATCH_SUMMARY, payload)
+	return SignBatchSummaryWithSigner(NewKeySigner(key), payload)
+}
+
+// SignBatchSummaryWithSigner will sign an alert batch summary by using the signer
+func SignBatchSummaryWithSigner(signer Signer, payload *protocol.BatchSummary) (*protocol.SignedPayload, error) {
+	return signPayload(signer, protocol.SignedPayload_BATCH_SUMMARY, payload)
 }
 
 // SignBatchReceipt will sign a batch receipt
 func SignBatchReceipt(key *keystore.Key, payload *protocol.BatchReceipt) (*protocol.SignedPayload, error) {
-	return signPayload(key, protocol.SignedPayload_BATCH_RECEIPT, payload)
+	return signPayload(NewKeySigner(key), protocol.SignedPayload_BATCH_RECEIPT, payload)
 }
 
 // VerifySignedPayload will return an error if the signature fails to validate
@@ -0,0 +1,288 @@
+package devchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"zktoro/zktoro-core-go/contracts/generated/contract_agent_registry_0_1_6"
+	"zktoro/zktoro-core-go/contracts/generated/contract_dispatch_0_1_5"
+	"zktoro/zktoro-core-go/contracts/generated/contract_rewards_distributor_0_1_0"
+	"zktoro/zktoro-core-go/contracts/generated/contract_scanner_node_version_0_1_1"
+	"zktoro/zktoro-core-go/contracts/generated/contract_scanner_pool_registry_0_1_0"
+	"zktoro/zktoro-core-go/contracts/generated/contract_scanner_registry_0_1_4"
+	"zktoro/zktoro-core-go/contracts/generated/contract_stake_allocator_0_1_0"
+	"zktoro/zktoro-core-go/contracts/generated/contract_zktoro_0_2_0"
+	"zktoro/zktoro-core-go/contracts/generated/contract_zktoro_staking_0_1_2"
+	"zktoro/zktoro-core-go/domain/registry"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultWithdrawalDelay   = 1
+	defaultRegistrationDelay = 60 * 60 * 24 * 365
+	defaultDelegationDelay   = 1
+	defaultFeeBps            = 0
+)
+
+// accessManagerCode deploys a contract which returns true for every call except when the first
+// argument is 0xffffffff, so that it passes the ERC-165 interface checks. It stands in for the access
+// manager and the stake subject gateway so that every role check passes on the dev chain. The
+// maxStakeFor and maxManagedStakeFor calls return 2^100 so that the stake deposits are not capped.
+var accessManagerCode = hexutil.MustDecode("0x603d600c600039603d6000f360003560e01c80631da1064014602f578063cdf50e1714602f5760043563ffffffff60e01b141560005260206000f35b600160641b60005260206000f3")
+
+// proxyCode returns the creation code of a minimal proxy which delegates to the implementation.
+// The constructor delegates the initialization call to the implementation first, like the ERC-1967
+// proxies of the real deployments, because the implementations can only be initialized while the
+// proxy is being constructed. The runtime code is the EIP-1167 minimal proxy.
+func proxyCode(implementation common.Address, initData []byte) []byte {
+	// copy the init data which follows the runtime code, delegatecall, bubble up the revert or
+	// return the runtime code
+	code := hexutil.MustDecode("0x606f380380606f6000396000600082600073")
+	code = append(code, implementation.Bytes()...)
+	code = append(code, hexutil.MustDecode("0x5af46035573d600060003e3d6000fd5b602d6042600039602d6000f3")...)
+	// runtime code
+	code = append(code, hexutil.MustDecode("0x363d3d373d3d3d363d73")...)
+	code = append(code, implementation.Bytes()...)
+	code = append(code, hexutil.MustDecode("0x5af43d82803e903d91602b57fd5bf3")...)
+	return append(code, initData...)
+}
+
+// Contracts contains the bindings of the deployed contracts.
+type Contracts struct {
+	Addresses     registry.RegistryContracts
+	AccessManager common.Address
+
+	Zktoro              *contract_zktoro_0_2_0.Zktoro
+	ZktoroStaking       *contract_zktoro_staking_0_1_2.ZktoroStaking
+	StakeAllocator      *contract_stake_allocator_0_1_0.StakeAllocator
+	RewardsDistributor  *contract_rewards_distributor_0_1_0.RewardsDistributor
+	AgentRegistry       *contract_agent_registry_0_1_6.AgentRegistry
+	ScannerRegistry     *contract_scanner_registry_0_1_4.ScannerRegistry
+	ScannerPoolRegistry *contract_scanner_pool_registry_0_1_0.ScannerPoolRegistry
+	Dispatch            *contract_dispatch_0_1_5.Dispatch
+	ScannerNodeVersion  *contract_scanner_node_version_0_1_1.ScannerNodeVersion
+}
+
+// deployer deploys the contracts and stops at the first error.
+type deployer struct {
+	chain *Chain
+	err   error
+}
+
+func (d *deployer) deployCode(name string, code []byte) common.Address {
+	if d.err != nil {
+		return common.Address{}
+	}
+	address, tx, _, err := bind.DeployContract(d.chain.TransactOpts(), abi.ABI{}, code, d.chain.backend)
+	d.wait(name, tx, err)
+	return address
+}
+
+// deployProxy deploys the implementation and the proxy which is initialized with the arguments. It
+// returns the address of the proxy.
+func (d *deployer) deployProxy(
+	name string, metaData *bind.MetaData,
+	deployImpl func(*bind.TransactOpts, bind.ContractBackend) (common.Address, *types.Transaction, error),
+	initArgs ...interface{},
+) common.Address {
+	if d.err != nil {
+		return common.Address{}
+	}
+	impl, tx, err := deployImpl(d.chain.TransactOpts(), d.chain.backend)
+	d.wait(name, tx, err)
+	if d.err != nil {
+		return common.Address{}
+	}
+	parsed, err := metaData.GetAbi()
+	if err != nil {
+		d.err = fmt.Errorf("%s abi is invalid: %v", name, err)
+		return common.Address{}
+	}
+	initData, err := parsed.Pack("initialize", initArgs...)
+	if err != nil {
+		d.err = fmt.Errorf("failed to pack the %s initialize call: %v", name, err)
+		return common.Address{}
+	}
+	return d.deployCode(name+" proxy", proxyCode(impl, initData))
+}
+
+// send waits for the transaction sent by the function.
+func (d *deployer) send(name string, sendTx func(*bind.TransactOpts) (*types.Transaction, error)) {
+	if d.err != nil {
+		return
+	}
+	tx, err := sendTx(d.chain.TransactOpts())
+	d.wait(name, tx, err)
+}
+
+func (d *deployer) wait(name string, tx *types.Transaction, err error) {
+	if err != nil {
+		d.err = fmt.Errorf("%s failed: %v", name, withRevertData(err))
+		return
+	}
+	if _, err := d.chain.waitTx(tx); err != nil {
+		d.err = fmt.Errorf("%s failed: %v", name, err)
+	}
+}
+
+func (d *deployer) bind(bindFn func() error) {
+	if d.err != nil {
+		return
+	}
+	if err := bindFn(); err != nil {
+		d.err = fmt.Errorf("failed to bind the contracts: %v", err)
+	}
+}
+
+// deployContracts deploys and wires the registry contracts.
+func (chain *Chain) deployContracts() (*Contracts, error) {
+	var (
+		d         = &deployer{chain: chain}
+		contracts = &Contracts{}
+		addrs     = &contracts.Addresses
+		owner     = chain.Owner()
+		backend   = chain.backend
+		noForward common.Address
+	)
+
+	contracts.AccessManager = d.deployCode("access manager", accessManagerCode)
+	manager := contracts.AccessManager
+	// the stake thresholds are not activated so the gateway only needs to allow the stake
+	subjectGateway := contracts.AccessManager
+
+	addrs.Zktoro = d.deployProxy(
+		"zktoro token", contract_zktoro_0_2_0.ZktoroMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_zktoro_0_2_0.DeployZktoro(opts, backend)
+			return addr, tx, err
+		}, owner,
+	)
+	addrs.Rewards = d.deployProxy(
+		"rewards distributor", contract_rewards_distributor_0_1_0.RewardsDistributorMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_rewards_distributor_0_1_0.DeployRewardsDistributor(opts, backend, noForward, addrs.Zktoro, subjectGateway)
+			return addr, tx, err
+		}, manager, big.NewInt(defaultDelegationDelay), big.NewInt(defaultFeeBps),
+	)
+	addrs.StakeAllocator = d.deployProxy(
+		"stake allocator", contract_stake_allocator_0_1_0.StakeAllocatorMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_stake_allocator_0_1_0.DeployStakeAllocator(opts, backend, noForward, subjectGateway, addrs.Rewards)
+			return addr, tx, err
+		}, manager,
+	)
+	addrs.ZktoroStaking = d.deployProxy(
+		"staking", contract_zktoro_staking_0_1_2.ZktoroStakingMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_zktoro_staking_0_1_2.DeployZktoroStaking(opts, backend, noForward)
+			return addr, tx, err
+		}, manager, addrs.Zktoro, uint64(defaultWithdrawalDelay), owner,
+	)
+	addrs.AgentRegistry = d.deployProxy(
+		"agent registry", contract_agent_registry_0_1_6.AgentRegistryMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_agent_registry_0_1_6.DeployAgentRegistry(opts, backend, noForward)
+			return addr, tx, err
+		}, manager, "Zktoro Agents", "ZKAGENT",
+	)
+	addrs.ScannerRegistry = d.deployProxy(
+		"scanner registry", contract_scanner_registry_0_1_4.ScannerRegistryMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_scanner_registry_0_1_4.DeployScannerRegistry(opts, backend, noForward)
+			return addr, tx, err
+		}, manager, "Zktoro Scanners", "ZKSCANNER",
+	)
+	addrs.ScannerPoolRegistry = d.deployProxy(
+		"scanner pool registry", contract_scanner_pool_registry_0_1_0.ScannerPoolRegistryMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_scanner_pool_registry_0_1_0.DeployScannerPoolRegistry(opts, backend, noForward, addrs.StakeAllocator)
+			return addr, tx, err
+		}, manager, "Zktoro Scanner Pools", "ZKSCANNERPOOL", subjectGateway, big.NewInt(defaultRegistrationDelay),
+	)
+	addrs.Dispatch = d.deployProxy(
+		"dispatch", contract_dispatch_0_1_5.DispatchMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_dispatch_0_1_5.DeployDispatch(opts, backend, noForward)
+			return addr, tx, err
+		}, manager, addrs.AgentRegistry, addrs.ScannerRegistry, addrs.ScannerPoolRegistry,
+	)
+	addrs.ScannerNodeVersion = d.deployProxy(
+		"scanner node version", contract_scanner_node_version_0_1_1.ScannerNodeVersionMetaData,
+		func(opts *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, error) {
+			addr, tx, _, err := contract_scanner_node_version_0_1_1.DeployScannerNodeVersion(opts, backend, noForward)
+			return addr, tx, err
+		}, manager,
+	)
+
+	d.bind(func() (err error) {
+		if contracts.Zktoro, err = contract_zktoro_0_2_0.NewZktoro(addrs.Zktoro, backend); err != nil {
+			return
+		}
+		if contracts.RewardsDistributor, err = contract_rewards_distributor_0_1_0.NewRewardsDistributor(addrs.Rewards, backend); err != nil {
+			return
+		}
+		if contracts.StakeAllocator, err = contract_stake_allocator_0_1_0.NewStakeAllocator(addrs.StakeAllocator, backend); err != nil {
+			return
+		}
+		if contracts.ZktoroStaking, err = contract_zktoro_staking_0_1_2.NewZktoroStaking(addrs.ZktoroStaking, backend); err != nil {
+			return
+		}
+		if contracts.AgentRegistry, err = contract_agent_registry_0_1_6.NewAgentRegistry(addrs.AgentRegistry, backend); err != nil {
+			return
+		}
+		if contracts.ScannerRegistry, err = contract_scanner_registry_0_1_4.NewScannerRegistry(addrs.ScannerRegistry, backend); err != nil {
+			return
+		}
+		if contracts.ScannerPoolRegistry, err = contract_scanner_pool_registry_0_1_0.NewScannerPoolRegistry(addrs.ScannerPoolRegistry, backend); err != nil {
+			return
+		}
+		if contracts.Dispatch, err = contract_dispatch_0_1_5.NewDispatch(addrs.Dispatch, backend); err != nil {
+			return
+		}
+		contracts.ScannerNodeVersion, err = contract_scanner_node_version_0_1_1.NewScannerNodeVersion(addrs.ScannerNodeVersion, backend)
+		return
+	})
+
+	d.send("staking helpers", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ZktoroStaking.ConfigureStakeHelpers(opts, subjectGateway, addrs.StakeAllocator)
+	})
+	d.send("token minter role", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		minterRole, err := contracts.Zktoro.MINTERROLE(&bind.CallOpts{})
+		if err != nil {
+			return nil, err
+		}
+		return contracts.Zktoro.GrantRole(opts, minterRole, owner)
+	})
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	return contracts, nil
+}
+
+// waitTx mines the transaction and makes sure that it succeeded.
+func (chain *Chain) waitTx(tx *types.Transaction) (*types.Receipt, error) {
+	chain.backend.Commit()
+	receipt, err := chain.backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// withRevertData adds the revert data to the error so that the custom errors can be decoded.
+func withRevertData(err error) error {
+	if data := revertData(err); len(data) > 0 {
+		return fmt.Errorf("%v: %s", err, hexutil.Encode(data))
+	}
+	return err
+}
@@ -0,0 +1,246 @@
+// Package devchain runs the registry contracts on an in-process simulated chain so that the
+// registry dependent code can be tested end-to-end and run against local devnets.
+package devchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"zktoro/zktoro-core-go/domain/registry"
+	"zktoro/zktoro-core-go/ens"
+	registryclient "zktoro/zktoro-core-go/registry"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	log "github.com/sirupsen/logrus"
+)
+
+// ChainID is the chain ID of the simulated chain.
+const ChainID = 1337
+
+const (
+	defaultListenAddr = "127.0.0.1:0"
+	defaultGasLimit   = 30_000_000
+	// the reward epochs start four days after the unix epoch so the chain starts a week later
+	genesisTimeOffset = 7 * 24 * time.Hour
+)
+
+var defaultBalance = new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e18))
+
+// Config configures the dev chain.
+type Config struct {
+	// ListenAddr is the address of the JSON-RPC endpoint. A random local port is used if it is empty.
+	ListenAddr string
+	// OwnerKey is the key of the account which deploys the contracts and seeds the fixtures.
+	// A new key is generated if it is nil.
+	OwnerKey *ecdsa.PrivateKey
+	// Accounts are funded in the genesis block in addition to the owner.
+	Accounts []common.Address
+}
+
+// Chain is a simulated chain with the registry contracts.
+type Chain struct {
+	backend   *backends.SimulatedBackend
+	ownerKey  *ecdsa.PrivateKey
+	contracts *Contracts
+
+	listener net.Listener
+	server   *http.Server
+	rpc      *rpc.Server
+
+	// mu serializes the transactions which are mined right away
+	mu sync.Mutex
+}
+
+// New creates the simulated chain, deploys the contracts and starts serving the JSON-RPC endpoint.
+func New(cfg Config) (*Chain, error) {
+	if cfg.OwnerKey == nil {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		cfg.OwnerKey = key
+	}
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = defaultListenAddr
+	}
+
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(cfg.OwnerKey.PublicKey): {Balance: defaultBalance},
+	}
+	for _, account := range cfg.Accounts {
+		alloc[account] = core.GenesisAccount{Balance: defaultBalance}
+	}
+
+	chain := &Chain{
+		backend:  backends.NewSimulatedBackend(alloc, defaultGasLimit),
+		ownerKey: cfg.OwnerKey,
+	}
+	if err := chain.backend.AdjustTime(genesisTimeOffset); err != nil {
+		chain.backend.Close()
+		return nil, err
+	}
+	chain.backend.Commit()
+
+	contracts, err := chain.deployContracts()
+	if err != nil {
+		chain.backend.Close()
+		return nil, fmt.Errorf("failed to deploy the contracts: %v", err)
+	}
+	chain.contracts = contracts
+
+	if err := chain.serve(cfg.ListenAddr); err != nil {
+		chain.backend.Close()
+		return nil, err
+	}
+	return chain, nil
+}
+
+func (chain *Chain) serve(listenAddr string) error {
+	chain.rpc = rpc.NewServer()
+	if err := chain.rpc.RegisterName("eth", &ethAPI{chain: chain}); err != nil {
+		return err
+	}
+	if err := chain.rpc.RegisterName("net", &netAPI{}); err != nil {
+		return err
+	}
+	if err := chain.rpc.RegisterName("web3", &web3API{}); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", listenAddr, err)
+	}
+	chain.listener = listener
+
+	wsHandler := chain.rpc.WebsocketHandler([]string{"*"})
+	chain.server = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				wsHandler.ServeHTTP(w, r)
+				return
+			}
+			chain.rpc.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := chain.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("dev chain json-rpc server stopped")
+		}
+	}()
+	return nil
+}
+
+// URL returns the HTTP URL of the JSON-RPC endpoint.
+func (chain *Chain) URL() string {
+	return fmt.Sprintf("http://%s", chain.listener.Addr().String())
+}
+
+// WebsocketURL returns the websocket URL of the JSON-RPC endpoint.
+func (chain *Chain) WebsocketURL() string {
+	return fmt.Sprintf("ws://%s", chain.listener.Addr().String())
+}
+
+// Backend returns the simulated backend.
+func (chain *Chain) Backend() *backends.SimulatedBackend {
+	return chain.backend
+}
+
+// Contracts returns the deployed contracts.
+func (chain *Chain) Contracts() *Contracts {
+	return chain.contracts
+}
+
+// Addresses returns the addresses of the deployed contracts.
+func (chain *Chain) Addresses() registry.RegistryContracts {
+	return chain.contracts.Addresses
+}
+
+// Owner returns the address of the account which deployed the contracts.
+func (chain *Chain) Owner() common.Address {
+	return crypto.PubkeyToAddress(chain.ownerKey.PublicKey)
+}
+
+// OwnerKey returns the private key of the owner account.
+func (chain *Chain) OwnerKey() *ecdsa.PrivateKey {
+	return chain.ownerKey
+}
+
+// TransactOpts returns new transaction options for the owner account.
+func (chain *Chain) TransactOpts() *bind.TransactOpts {
+	opts, _ := bind.NewKeyedTransactorWithChainID(chain.ownerKey, big.NewInt(ChainID))
+	return opts
+}
+
+// ENSOverrides returns the contract names and addresses in the ens-override.json format.
+func (chain *Chain) ENSOverrides() map[string]string {
+	addrs := chain.contracts.Addresses
+	return map[string]string{
+		ens.DispatchContract:            addrs.Dispatch.Hex(),
+		ens.AgentRegistryContract:       addrs.AgentRegistry.Hex(),
+		ens.ScannerRegistryContract:     addrs.ScannerRegistry.Hex(),
+		ens.ScannerPoolRegistryContract: addrs.ScannerPoolRegistry.Hex(),
+		ens.ScannerNodeVersionContract:  addrs.ScannerNodeVersion.Hex(),
+		ens.StakingContract:             addrs.ZktoroStaking.Hex(),
+		ens.ZktoroContract:              addrs.Zktoro.Hex(),
+		ens.MigrationContract:           addrs.Migration.Hex(),
+		ens.RewardsContract:             addrs.Rewards.Hex(),
+		ens.StakeAllocatorContract:      addrs.StakeAllocator.Hex(),
+	}
+}
+
+// Resolve implements the ens.Resolver interface.
+func (chain *Chain) Resolve(input string) (common.Address, error) {
+	addr, ok := chain.ENSOverrides()[input]
+	if !ok {
+		return common.Address{}, fmt.Errorf("unknown contract name: %s", input)
+	}
+	return common.HexToAddress(addr), nil
+}
+
+// Client creates a registry client which uses the JSON-RPC endpoint of the chain.
+func (chain *Chain) Client(ctx context.Context) (registryclient.Client, error) {
+	client, err := registryclient.NewClientWithENSStore(ctx, registryclient.ClientConfig{
+		JsonRpcUrl: chain.URL(),
+		Name:       "devchain-registry-client",
+		PrivateKey: chain.ownerKey,
+	}, ens.NewENStoreWithResolver(chain))
+	if err != nil {
+		return nil, err
+	}
+	client.SetRegistryChainID(ChainID)
+	return client, nil
+}
+
+// Commit mines a block with the pending transactions. It can be called periodically to mine empty
+// blocks when the transactions need confirmations.
+func (chain *Chain) Commit() {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	chain.backend.Commit()
+}
+
+// Close stops the JSON-RPC endpoint and the simulated chain.
+func (chain *Chain) Close() error {
+	if chain.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		chain.server.Shutdown(ctx)
+		chain.rpc.Stop()
+	}
+	return chain.backend.Close()
+}
@@ -0,0 +1,180 @@
+package devchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"zktoro/zktoro-core-go/ens"
+	registryclient "zktoro/zktoro-core-go/registry"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBotID   = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	testScanner = "0x1111111111111111111111111111111111111111"
+)
+
+func TestSeedFixture(t *testing.T) {
+	r := require.New(t)
+
+	chain, err := New(Config{})
+	r.NoError(err)
+	defer chain.Close()
+
+	fixture, err := LoadFixture("testdata/fixture.yml")
+	r.NoError(err)
+	r.NoError(chain.Seed(fixture))
+
+	client, err := chain.Client(context.Background())
+	r.NoError(err)
+
+	version, err := client.GetScannerNodeVersion()
+	r.NoError(err)
+	r.Equal("QmScannerNodeVersion", version)
+
+	scanner, err := client.GetPoolScanner(testScanner)
+	r.NoError(err)
+	r.NotNil(scanner)
+	r.Equal("1", scanner.PoolID)
+	r.Equal(int64(1), scanner.ChainID)
+	r.Equal("scanner-metadata", scanner.Manifest)
+	r.Equal(chain.Owner().Hex(), scanner.Owner)
+
+	owner, err := client.GetScannerPoolOwner(big.NewInt(1))
+	r.NoError(err)
+	r.Equal(chain.Owner().Hex(), owner)
+
+	bot, err := client.GetAgent(testBotID)
+	r.NoError(err)
+	r.NotNil(bot)
+	r.Equal("QmBotManifest", bot.Manifest)
+	r.Equal([]int64{1}, bot.ChainIDs)
+
+	assigned, err := client.IsAssigned(testScanner, testBotID)
+	r.NoError(err)
+	r.True(assigned)
+
+	assignments, err := client.GetAssignmentList(nil, big.NewInt(1), testScanner)
+	r.NoError(err)
+	r.Len(assignments, 1)
+	r.Equal(testBotID, assignments[0].AgentID)
+	r.Equal("QmBotManifest", assignments[0].AgentManifest)
+}
+
+func TestStakeDeposit(t *testing.T) {
+	r := require.New(t)
+
+	chain, err := New(Config{})
+	r.NoError(err)
+	defer chain.Close()
+
+	amount := new(big.Int).Mul(big.NewInt(500), tokenUnit)
+	r.NoError(chain.Mint(chain.Owner(), amount))
+	poolID, err := chain.RegisterPool(chain.Owner(), 1)
+	r.NoError(err)
+
+	contracts := chain.Contracts()
+	_, err = chain.transact("approve", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.Zktoro.Approve(opts, contracts.Addresses.ZktoroStaking, amount)
+	})
+	r.NoError(err)
+	_, err = chain.transact("deposit", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contracts.ZktoroStaking.Deposit(opts, registryclient.SubjectTypeScannerPool, poolID, amount)
+	})
+	r.NoError(err)
+
+	client, err := chain.Client(context.Background())
+	r.NoError(err)
+	stake, err := client.GetActivePoolStake(nil, poolID)
+	r.NoError(err)
+	r.Equal(amount, stake)
+}
+
+func TestBatchedRegistryReads(t *testing.T) {
+	r := require.New(t)
+
+	chain, err := New(Config{})
+	r.NoError(err)
+	defer chain.Close()
+
+	fixture, err := LoadFixture("testdata/fixture.yml")
+	r.NoError(err)
+	r.NoError(chain.Seed(fixture))
+
+	bots := []string{
+		testBotID,
+		"0x0000000000000000000000000000000000000000000000000000000000000002",
+		"0x0000000000000000000000000000000000000000000000000000000000000003",
+	}
+	// the manifest references are unique in the registry
+	manifests := map[string]string{testBotID: "QmBotManifest"}
+	for _, botID := range bots[1:] {
+		manifests[botID] = "QmBotManifest" + botID[len(botID)-1:]
+		r.NoError(chain.RegisterBot(botID, chain.Owner(), manifests[botID], []int64{1}))
+		r.NoError(chain.Assign(botID, testScanner))
+	}
+	// not assigned and on another chain
+	otherBotID := "0x0000000000000000000000000000000000000000000000000000000000000004"
+	r.NoError(chain.RegisterBot(otherBotID, chain.Owner(), "QmOtherBotManifest", []int64{137}))
+
+	for name, cfg := range map[string]registryclient.ClientConfig{
+		"multicall": {},
+		"chunked":   {MulticallChunkSize: 1},
+		// there is no contract at this address so the client makes sequential calls
+		"sequential": {MulticallAddress: "0x000000000000000000000000000000000000dEaD"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+
+			cfg.JsonRpcUrl = chain.URL()
+			cfg.Name = "devchain-test-" + name
+			client, err := registryclient.NewClientWithENSStore(context.Background(), cfg, ens.NewENStoreWithResolver(chain))
+			r.NoError(err)
+			client.SetRegistryChainID(ChainID)
+
+			var assigned []string
+			r.NoError(client.ForEachAssignedAgent(testScanner, func(a *registryclient.Agent) error {
+				r.Equal(manifests[a.AgentID], a.Manifest)
+				r.Equal([]int64{1}, a.ChainIDs)
+				r.True(a.Enabled)
+				r.Equal(chain.Owner().Hex(), a.Owner)
+				assigned = append(assigned, a.AgentID)
+				return nil
+			}))
+			r.ElementsMatch(bots, assigned)
+
+			var chainBots []string
+			r.NoError(client.ForEachChainAgent(1, func(a *registryclient.Agent) error {
+				r.Equal([]int64{1}, a.ChainIDs)
+				chainBots = append(chainBots, a.AgentID)
+				return nil
+			}))
+			r.ElementsMatch(bots, chainBots)
+
+			var otherChainBots []*registryclient.Agent
+			r.NoError(client.ForEachChainAgent(137, func(a *registryclient.Agent) error {
+				otherChainBots = append(otherChainBots, a)
+				return nil
+			}))
+			r.Len(otherChainBots, 1)
+			r.Equal(otherBotID, otherChainBots[0].AgentID)
+			r.Equal("QmOtherBotManifest", otherChainBots[0].Manifest)
+
+			// the simulated chain can only serve the calls pegged to the latest block
+			header, err := chain.Backend().HeaderByNumber(context.Background(), nil)
+			r.NoError(err)
+			client.PegBlock(header.Number)
+			assignments, err := client.GetAssignmentList(nil, big.NewInt(1), testScanner)
+			r.NoError(err)
+			r.Len(assignments, len(bots))
+			for _, assignment := range assignments {
+				r.Equal(1, assignment.AssignedScanners)
+				r.Equal(0, assignment.ScannerIndex)
+			}
+		})
+	}
+}
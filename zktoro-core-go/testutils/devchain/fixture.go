@@ -0,0 +1,215 @@
+package devchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"zktoro/zktoro-core-go/contracts/generated/contract_scanner_pool_registry_0_1_0"
+	"zktoro/zktoro-core-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gopkg.in/yaml.v3"
+)
+
+var tokenUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// Fixture describes the registry state which is seeded on the dev chain.
+type Fixture struct {
+	ScannerNodeVersion string          `yaml:"scannerNodeVersion"`
+	Tokens             []FixtureTokens `yaml:"tokens"`
+	Pools              []FixturePool   `yaml:"pools"`
+	Bots               []FixtureBot    `yaml:"bots"`
+	// Assignments link the bots to the scanners.
+	Assignments []FixtureAssignment `yaml:"assignments"`
+}
+
+// FixtureTokens mints tokens to an account so that it can stake.
+type FixtureTokens struct {
+	Address string `yaml:"address"`
+	// Amount is in whole tokens.
+	Amount int64 `yaml:"amount"`
+}
+
+// FixturePool is a scanner pool with its scanners. The pools are registered in order so the first
+// pool gets the ID 1.
+type FixturePool struct {
+	Owner    string           `yaml:"owner"`
+	ChainID  int64            `yaml:"chainId"`
+	Scanners []FixtureScanner `yaml:"scanners"`
+}
+
+// FixtureScanner is a scanner node in a pool.
+type FixtureScanner struct {
+	Address  string `yaml:"address"`
+	Metadata string `yaml:"metadata"`
+	Disabled bool   `yaml:"disabled"`
+}
+
+// FixtureBot is a bot registered to the agent registry.
+type FixtureBot struct {
+	ID       string  `yaml:"id"`
+	Owner    string  `yaml:"owner"`
+	Manifest string  `yaml:"manifest"`
+	ChainIDs []int64 `yaml:"chainIds"`
+}
+
+// FixtureAssignment assigns a bot to a scanner.
+type FixtureAssignment struct {
+	Bot     string `yaml:"bot"`
+	Scanner string `yaml:"scanner"`
+}
+
+// LoadFixture reads the fixture from the YAML file.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the fixture: %v", err)
+	}
+	var fixture Fixture
+	if err := yaml.Unmarshal(b, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse the fixture: %v", err)
+	}
+	return &fixture, nil
+}
+
+// Seed registers the pools, scanners and bots in the fixture and assigns the bots.
+func (chain *Chain) Seed(fixture *Fixture) error {
+	if len(fixture.ScannerNodeVersion) > 0 {
+		if err := chain.SetScannerNodeVersion(fixture.ScannerNodeVersion); err != nil {
+			return err
+		}
+	}
+	for _, tokens := range fixture.Tokens {
+		if !common.IsHexAddress(tokens.Address) {
+			return fmt.Errorf("invalid address to mint tokens to: %s", tokens.Address)
+		}
+		amount := new(big.Int).Mul(big.NewInt(tokens.Amount), tokenUnit)
+		if err := chain.Mint(common.HexToAddress(tokens.Address), amount); err != nil {
+			return err
+		}
+	}
+	for i, pool := range fixture.Pools {
+		owner := chain.Owner()
+		if len(pool.Owner) > 0 {
+			if !common.IsHexAddress(pool.Owner) {
+				return fmt.Errorf("pool %d has invalid owner address: %s", i, pool.Owner)
+			}
+			owner = common.HexToAddress(pool.Owner)
+		}
+		poolID, err := chain.RegisterPool(owner, pool.ChainID)
+		if err != nil {
+			return err
+		}
+		for _, scanner := range pool.Scanners {
+			if !common.IsHexAddress(scanner.Address) {
+				return fmt.Errorf("pool %s has invalid scanner address: %s", poolID, scanner.Address)
+			}
+			if err := chain.RegisterScanner(
+				poolID, common.HexToAddress(scanner.Address), pool.ChainID, scanner.Metadata, scanner.Disabled,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	for _, bot := range fixture.Bots {
+		owner := chain.Owner()
+		if len(bot.Owner) > 0 {
+			owner = common.HexToAddress(bot.Owner)
+		}
+		if err := chain.RegisterBot(bot.ID, owner, bot.Manifest, bot.ChainIDs); err != nil {
+			return err
+		}
+	}
+	for _, assignment := range fixture.Assignments {
+		if err := chain.Assign(assignment.Bot, assignment.Scanner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetScannerNodeVersion sets the scanner node release reference.
+func (chain *Chain) SetScannerNodeVersion(version string) error {
+	_, err := chain.transact("set scanner node version", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.ScannerNodeVersion.SetScannerNodeVersion(opts, version)
+	})
+	return err
+}
+
+// Mint mints the amount of tokens (in the smallest unit) to the account.
+func (chain *Chain) Mint(to common.Address, amount *big.Int) error {
+	_, err := chain.transact("mint tokens", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.Zktoro.Mint(opts, to, amount)
+	})
+	return err
+}
+
+// RegisterPool registers a scanner pool for the owner and returns the pool ID.
+func (chain *Chain) RegisterPool(owner common.Address, chainID int64) (*big.Int, error) {
+	receipt, err := chain.transact("register pool", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.ScannerPoolRegistry.RegisterMigratedScannerPool(opts, owner, big.NewInt(chainID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range receipt.Logs {
+		if registered, err := chain.contracts.ScannerPoolRegistry.ParseScannerPoolRegistered(*log); err == nil {
+			return registered.ScannerPoolId, nil
+		}
+	}
+	return nil, errors.New("pool registration event not found")
+}
+
+// RegisterScanner registers a scanner to the pool. The scanner does not need to sign the registration.
+func (chain *Chain) RegisterScanner(poolID *big.Int, scanner common.Address, chainID int64, metadata string, disabled bool) error {
+	_, err := chain.transact("register scanner", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.ScannerPoolRegistry.RegisterMigratedScannerNode(opts, contract_scanner_pool_registry_0_1_0.ScannerPoolRegistryCoreScannerNodeRegistration{
+			Scanner:       scanner,
+			ScannerPoolId: poolID,
+			ChainId:       big.NewInt(chainID),
+			Metadata:      metadata,
+			Timestamp:     big.NewInt(0),
+		}, disabled)
+	})
+	return err
+}
+
+// RegisterBot registers a bot with the manifest reference.
+func (chain *Chain) RegisterBot(botID string, owner common.Address, manifest string, chainIDs []int64) error {
+	var chains []*big.Int
+	for _, chainID := range chainIDs {
+		chains = append(chains, big.NewInt(chainID))
+	}
+	_, err := chain.transact("register bot", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.AgentRegistry.CreateAgent(opts, utils.AgentHexToBigInt(botID), owner, manifest, chains)
+	})
+	return err
+}
+
+// Assign assigns the bot to the scanner.
+func (chain *Chain) Assign(botID, scanner string) error {
+	_, err := chain.transact("assign bot", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return chain.contracts.Dispatch.Link(opts, utils.AgentHexToBigInt(botID), utils.ScannerIDHexToBigInt(scanner))
+	})
+	return err
+}
+
+// transact sends the transaction from the owner account, mines it and checks the receipt.
+func (chain *Chain) transact(name string, sendTx func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Receipt, error) {
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+
+	tx, err := sendTx(chain.TransactOpts())
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %v", name, withRevertData(err))
+	}
+	receipt, err := chain.waitTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %v", name, err)
+	}
+	return receipt, nil
+}
@@ -0,0 +1,395 @@
+package devchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	multicall "zktoro/go-multicall"
+	"zktoro/go-multicall/contracts/contract_multicall"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var multicallAddress = common.HexToAddress(multicall.DefaultAddress)
+
+// ethAPI serves the subset of the eth namespace which is used by the node and the registry
+// clients. Every transaction is mined in its own block as soon as it is received.
+type ethAPI struct {
+	chain *Chain
+}
+
+type netAPI struct{}
+
+// Version returns the network ID.
+func (api *netAPI) Version() string {
+	return fmt.Sprint(ChainID)
+}
+
+type web3API struct{}
+
+// ClientVersion returns the name of the node.
+func (api *web3API) ClientVersion() string {
+	return "zktoro-devchain"
+}
+
+// callArgs are the arguments of eth_call and eth_estimateGas.
+type callArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+}
+
+func (args *callArgs) toCallMsg() ethereum.CallMsg {
+	var msg ethereum.CallMsg
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	msg.To = args.To
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	msg.GasPrice = (*big.Int)(args.GasPrice)
+	msg.GasFeeCap = (*big.Int)(args.MaxFeePerGas)
+	msg.GasTipCap = (*big.Int)(args.MaxPriorityFeePerGas)
+	msg.Value = (*big.Int)(args.Value)
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	return msg
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(ChainID))
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.backend.Blockchain().CurrentBlock().Number.Uint64())
+}
+
+func (api *ethAPI) Syncing() bool {
+	return false
+}
+
+func (api *ethAPI) Accounts() []common.Address {
+	return []common.Address{}
+}
+
+func (api *ethAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.chain.backend.SuggestGasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := api.chain.backend.SuggestGasTipCap(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
+func (api *ethAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	block, err := api.chain.backend.BlockByNumber(ctx, blockNumberArg(number))
+	if err != nil {
+		return nil, nil
+	}
+	return marshalBlock(block, fullTx), nil
+}
+
+func (api *ethAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block, err := api.chain.backend.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, nil
+	}
+	return marshalBlock(block, fullTx), nil
+}
+
+func (api *ethAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	balance, err := api.chain.backend.BalanceAt(ctx, address, nil)
+	return (*hexutil.Big)(balance), err
+}
+
+func (api *ethAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if isPending(blockNrOrHash) {
+		return api.chain.backend.PendingCodeAt(ctx, address)
+	}
+	return api.chain.backend.CodeAt(ctx, address, nil)
+}
+
+func (api *ethAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if isPending(blockNrOrHash) {
+		nonce, err := api.chain.backend.PendingNonceAt(ctx, address)
+		return hexutil.Uint64(nonce), err
+	}
+	nonce, err := api.chain.backend.NonceAt(ctx, address, nil)
+	return hexutil.Uint64(nonce), err
+}
+
+func (api *ethAPI) Call(ctx context.Context, args callArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	msg := args.toCallMsg()
+	if msg.To != nil && *msg.To == multicallAddress {
+		code, err := api.chain.backend.CodeAt(ctx, multicallAddress, nil)
+		if err == nil && len(code) == 0 {
+			return api.aggregate3(ctx, msg, blockNrOrHash)
+		}
+	}
+	return api.call(ctx, msg, blockNrOrHash)
+}
+
+func (api *ethAPI) call(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash) ([]byte, error) {
+	if isPending(blockNrOrHash) {
+		return api.chain.backend.PendingCallContract(ctx, msg)
+	}
+	blockNumber, err := api.blockNumberOrHashArg(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.chain.backend.CallContract(ctx, msg, blockNumber)
+}
+
+// aggregate3 executes the Multicall3 aggregate3 calls because the Multicall3 contract is not
+// deployed on the simulated chain.
+func (api *ethAPI) aggregate3(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash) ([]byte, error) {
+	multicallABI, err := contract_multicall.MulticallMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method := multicallABI.Methods["aggregate3"]
+	if len(msg.Data) < 4 || !bytes.Equal(msg.Data[:4], method.ID) {
+		return nil, errors.New("only aggregate3 is supported by the dev chain multicall")
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the multicall inputs: %v", err)
+	}
+	calls := *abi.ConvertType(args[0], new([]contract_multicall.Multicall3Call3)).(*[]contract_multicall.Multicall3Call3)
+
+	results := make([]contract_multicall.Multicall3Result, len(calls))
+	for i, call := range calls {
+		target := call.Target
+		returnData, err := api.call(ctx, ethereum.CallMsg{
+			From: multicallAddress,
+			To:   &target,
+			Data: call.CallData,
+		}, blockNrOrHash)
+		if err != nil {
+			if !call.AllowFailure {
+				return nil, fmt.Errorf("Multicall3: call failed: %v", err)
+			}
+			results[i] = contract_multicall.Multicall3Result{ReturnData: revertData(err)}
+			continue
+		}
+		results[i] = contract_multicall.Multicall3Result{Success: true, ReturnData: returnData}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func (api *ethAPI) EstimateGas(ctx context.Context, args callArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	gas, err := api.chain.backend.EstimateGas(ctx, args.toCallMsg())
+	return hexutil.Uint64(gas), err
+}
+
+func (api *ethAPI) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+
+	api.chain.mu.Lock()
+	defer api.chain.mu.Unlock()
+	if err := api.chain.backend.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	api.chain.backend.Commit()
+	return tx.Hash(), nil
+}
+
+func (api *ethAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, pending, err := api.chain.backend.TransactionByHash(ctx, hash)
+	if err != nil || pending {
+		return nil, nil
+	}
+	receipt, err := api.chain.backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, nil
+	}
+	block, err := api.chain.backend.BlockByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, nil
+	}
+	return marshalTx(tx, block, receipt.TransactionIndex), nil
+}
+
+func (api *ethAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := api.chain.backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+func (api *ethAPI) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([]types.Log, error) {
+	logs, err := api.chain.backend.FilterLogs(ctx, ethereum.FilterQuery(crit))
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	return logs, nil
+}
+
+// NewHeads sends the headers of the new blocks to the websocket subscribers.
+func (api *ethAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+
+	headers := make(chan *types.Header)
+	headerSub, err := api.chain.backend.SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		defer headerSub.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				notifier.Notify(sub.ID, marshalHeader(header))
+			case <-sub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func (api *ethAPI) blockNumberOrHashArg(ctx context.Context, blockNrOrHash *rpc.BlockNumberOrHash) (*big.Int, error) {
+	if blockNrOrHash == nil {
+		return nil, nil
+	}
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header, err := api.chain.backend.HeaderByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		return header.Number, nil
+	}
+	number, _ := blockNrOrHash.Number()
+	return blockNumberArg(number), nil
+}
+
+// blockNumberArg converts the special block numbers to nil which means the latest block.
+func blockNumberArg(number rpc.BlockNumber) *big.Int {
+	if number < 0 {
+		return nil
+	}
+	return big.NewInt(number.Int64())
+}
+
+func isPending(blockNrOrHash *rpc.BlockNumberOrHash) bool {
+	if blockNrOrHash == nil {
+		return false
+	}
+	number, ok := blockNrOrHash.Number()
+	return ok && number == rpc.PendingBlockNumber
+}
+
+// revertData extracts the revert data from the call error.
+func revertData(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	data, _ := dataErr.ErrorData().(string)
+	b, _ := hexutil.Decode(data)
+	return b
+}
+
+func marshalHeader(header *types.Header) map[string]interface{} {
+	result := map[string]interface{}{
+		"number":           (*hexutil.Big)(header.Number),
+		"hash":             header.Hash(),
+		"parentHash":       header.ParentHash,
+		"nonce":            header.Nonce,
+		"mixHash":          header.MixDigest,
+		"sha3Uncles":       header.UncleHash,
+		"logsBloom":        header.Bloom,
+		"stateRoot":        header.Root,
+		"miner":            header.Coinbase,
+		"difficulty":       (*hexutil.Big)(header.Difficulty),
+		"extraData":        hexutil.Bytes(header.Extra),
+		"gasLimit":         hexutil.Uint64(header.GasLimit),
+		"gasUsed":          hexutil.Uint64(header.GasUsed),
+		"timestamp":        hexutil.Uint64(header.Time),
+		"transactionsRoot": header.TxHash,
+		"receiptsRoot":     header.ReceiptHash,
+	}
+	if header.BaseFee != nil {
+		result["baseFeePerGas"] = (*hexutil.Big)(header.BaseFee)
+	}
+	return result
+}
+
+func marshalBlock(block *types.Block, fullTx bool) map[string]interface{} {
+	result := marshalHeader(block.Header())
+	result["size"] = hexutil.Uint64(block.Size())
+	result["totalDifficulty"] = (*hexutil.Big)(block.Difficulty())
+	result["uncles"] = []common.Hash{}
+
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if fullTx {
+			txs[i] = marshalTx(tx, block, uint(i))
+		} else {
+			txs[i] = tx.Hash()
+		}
+	}
+	result["transactions"] = txs
+	return result
+}
+
+func marshalTx(tx *types.Transaction, block *types.Block, index uint) map[string]interface{} {
+	from, _ := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	v, r, s := tx.RawSignatureValues()
+	result := map[string]interface{}{
+		"blockHash":        block.Hash(),
+		"blockNumber":      (*hexutil.Big)(block.Number()),
+		"from":             from,
+		"gas":              hexutil.Uint64(tx.Gas()),
+		"gasPrice":         (*hexutil.Big)(tx.GasPrice()),
+		"hash":             tx.Hash(),
+		"input":            hexutil.Bytes(tx.Data()),
+		"nonce":            hexutil.Uint64(tx.Nonce()),
+		"to":               tx.To(),
+		"transactionIndex": hexutil.Uint64(index),
+		"value":            (*hexutil.Big)(tx.Value()),
+		"type":             hexutil.Uint64(tx.Type()),
+		"chainId":          (*hexutil.Big)(tx.ChainId()),
+		"v":                (*hexutil.Big)(v),
+		"r":                (*hexutil.Big)(r),
+		"s":                (*hexutil.Big)(s),
+	}
+	if tx.Type() != types.LegacyTxType {
+		result["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		result["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+	}
+	return result
+}